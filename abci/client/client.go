@@ -52,11 +52,12 @@ type Client interface {
 //----------------------------------------
 
 // NewClient returns a new ABCI client of the specified transport type.
-// It returns an error if the transport is not "socket" or "grpc"
-func NewClient(addr, transport string, mustConnect bool) (client Client, err error) {
+// It returns an error if the transport is not "socket" or "grpc".
+// The options only apply to the socket transport.
+func NewClient(addr, transport string, mustConnect bool, options ...SocketClientOption) (client Client, err error) {
 	switch transport {
 	case "socket":
-		client = NewSocketClient(addr, mustConnect)
+		client = NewSocketClient(addr, mustConnect, options...)
 	case "grpc":
 		client = NewGRPCClient(addr, mustConnect)
 	default:
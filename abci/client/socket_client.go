@@ -17,9 +17,29 @@ import (
 const reqQueueSize = 256 // TODO make configurable
 // const maxResponseSize = 1048576 // 1MB TODO make configurable
 const flushThrottleMS = 20 // Don't wait longer than...
+const negotiateTimeout = 10 * time.Second
 
 var _ Client = (*socketClient)(nil)
 
+// SocketClientOption sets an optional parameter on the socket client.
+type SocketClientOption func(*socketClient)
+
+// SocketClientMaxMsgSize sets the maximum size of the messages exchanged
+// with the application, in bytes. The application may negotiate a smaller
+// one, and applications which don't support the negotiation use
+// types.DefaultMaxMsgSize.
+func SocketClientMaxMsgSize(size int) SocketClientOption {
+	return func(cli *socketClient) { cli.maxMsgSize = size }
+}
+
+// SocketClientCredits sets the maximum number of requests sent to the
+// application before receiving their responses, 0 for unlimited. The
+// application may negotiate fewer, and there is no limit with applications
+// which don't support the negotiation.
+func SocketClientCredits(credits int) SocketClientOption {
+	return func(cli *socketClient) { cli.maxCredits = credits }
+}
+
 // This is goroutine-safe, but users should beware that
 // the application in general is not meant to be interfaced
 // with concurrent callers.
@@ -29,6 +49,13 @@ type socketClient struct {
 	reqQueue    chan *ReqRes
 	flushTimer  *cmn.ThrottleTimer
 	mustConnect bool
+	maxMsgSize  int
+	maxCredits  int
+
+	// Negotiated when connecting. credits holds a value for each request in
+	// flight, it is nil if their number is unlimited.
+	params  types.SocketParams
+	credits chan struct{}
 
 	mtx     sync.Mutex
 	addr    string
@@ -39,17 +66,22 @@ type socketClient struct {
 
 }
 
-func NewSocketClient(addr string, mustConnect bool) *socketClient {
+func NewSocketClient(addr string, mustConnect bool, options ...SocketClientOption) *socketClient {
 	cli := &socketClient{
 		reqQueue:    make(chan *ReqRes, reqQueueSize),
 		flushTimer:  cmn.NewThrottleTimer("socketClient", flushThrottleMS),
 		mustConnect: mustConnect,
+		maxMsgSize:  types.DefaultMaxMsgSize,
+		maxCredits:  reqQueueSize,
 
 		addr:    addr,
 		reqSent: list.New(),
 		resCb:   nil,
 	}
 	cli.BaseService = *cmn.NewBaseService(nil, "socketClient", cli)
+	for _, option := range options {
+		option(cli)
+	}
 	return cli
 }
 
@@ -71,15 +103,75 @@ RETRY_LOOP:
 			time.Sleep(time.Second * dialRetryIntervalSeconds)
 			continue RETRY_LOOP
 		}
+
+		r := bufio.NewReader(conn) // Buffer reads
+		if err = cli.negotiate(conn, r); err != nil {
+			conn.Close() // nolint: errcheck
+			if cli.mustConnect {
+				return err
+			}
+			cli.Logger.Error(fmt.Sprintf("abci.socketClient failed to negotiate with %v.  Retrying...", cli.addr), "err", err)
+			time.Sleep(time.Second * dialRetryIntervalSeconds)
+			continue RETRY_LOOP
+		}
 		cli.conn = conn
 
 		go cli.sendRequestsRoutine(conn)
-		go cli.recvResponseRoutine(conn)
+		go cli.recvResponseRoutine(r)
 
 		return nil
 	}
 }
 
+// negotiate agrees on the connection parameters with the application, see
+// types.SocketParams.
+func (cli *socketClient) negotiate(conn net.Conn, r *bufio.Reader) error {
+	if err := conn.SetDeadline(time.Now().Add(negotiateTimeout)); err != nil {
+		return err
+	}
+	proposed := types.SocketParams{MaxMsgSize: cli.maxMsgSize, Credits: cli.maxCredits}
+
+	// The server only flushes its responses on Flush requests.
+	w := bufio.NewWriter(conn)
+	if err := types.WriteMessage(types.ToRequestEcho(proposed.RequestMessage()), w); err != nil {
+		return err
+	}
+	if err := types.WriteMessage(types.ToRequestFlush(), w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	var echo, flush types.Response
+	if err := types.ReadMessageMaxSize(r, &echo, cli.maxMsgSize); err != nil {
+		return err
+	}
+	if err := types.ReadMessageMaxSize(r, &flush, cli.maxMsgSize); err != nil {
+		return err
+	}
+	if echo.GetEcho() == nil || flush.GetFlush() == nil {
+		return fmt.Errorf("Unexpected responses %v and %v to the negotiation",
+			reflect.TypeOf(echo.Value), reflect.TypeOf(flush.Value))
+	}
+
+	params, ok := types.ParseSocketParamsResponse(echo.GetEcho().Message)
+	if !ok {
+		// The application doesn't support the negotiation and echoed the
+		// request.
+		params = types.DefaultSocketParams()
+		cli.Logger.Info("Application doesn't negotiate the connection parameters, using the defaults")
+	} else {
+		cli.Logger.Info("Negotiated the connection parameters",
+			"maxMsgSize", params.MaxMsgSize, "credits", params.Credits)
+	}
+	cli.params = params
+	cli.credits = nil
+	if params.Credits > 0 {
+		cli.credits = make(chan struct{}, params.Credits)
+	}
+	return conn.SetDeadline(time.Time{})
+}
+
 func (cli *socketClient) OnStop() {
 	cli.BaseService.OnStop()
 
@@ -138,6 +230,9 @@ func (cli *socketClient) sendRequestsRoutine(conn net.Conn) {
 		case <-cli.Quit():
 			return
 		case reqres := <-cli.reqQueue:
+			if !cli.acquireCredit(reqres.Request, w) {
+				return
+			}
 			cli.willSendReq(reqres)
 			err := types.WriteMessage(reqres.Request, w)
 			if err != nil {
@@ -156,12 +251,41 @@ func (cli *socketClient) sendRequestsRoutine(conn net.Conn) {
 	}
 }
 
-func (cli *socketClient) recvResponseRoutine(conn net.Conn) {
+// acquireCredit blocks until req can be sent. When out of credits, it first
+// flushes the requests in flight so that the application answers them,
+// releasing their credits. It returns false if the client stopped.
+func (cli *socketClient) acquireCredit(req *types.Request, w *bufio.Writer) bool {
+	if _, ok := req.Value.(*types.Request_Flush); ok || cli.credits == nil {
+		return true
+	}
+	select {
+	case cli.credits <- struct{}{}:
+		return true
+	default:
+	}
+
+	flush := NewReqRes(types.ToRequestFlush())
+	cli.willSendReq(flush)
+	if err := types.WriteMessage(flush.Request, w); err != nil {
+		cli.StopForError(fmt.Errorf("Error writing msg: %v", err))
+		return false
+	}
+	if err := w.Flush(); err != nil {
+		cli.StopForError(fmt.Errorf("Error flushing writer: %v", err))
+		return false
+	}
+	select {
+	case cli.credits <- struct{}{}:
+		return true
+	case <-cli.Quit():
+		return false
+	}
+}
 
-	r := bufio.NewReader(conn) // Buffer reads
+func (cli *socketClient) recvResponseRoutine(r *bufio.Reader) {
 	for {
 		var res = &types.Response{}
-		err := types.ReadMessage(r, res)
+		err := types.ReadMessageMaxSize(r, res, cli.params.MaxMsgSize)
 		if err != nil {
 			cli.StopForError(err)
 			return
@@ -207,6 +331,11 @@ func (cli *socketClient) didRecvResponse(res *types.Response) error {
 	reqres.Done()            // Release waiters
 	cli.reqSent.Remove(next) // Pop first item from linked list
 
+	// Release the credit of the request
+	if _, ok := res.Value.(*types.Response_Flush); !ok && cli.credits != nil {
+		<-cli.credits
+	}
+
 	// Notify reqRes listener if set
 	if cb := reqres.GetCallback(); cb != nil {
 		cb(res)
@@ -342,6 +471,14 @@ func (cli *socketClient) EndBlockSync(req types.RequestEndBlock) (*types.Respons
 func (cli *socketClient) queueRequest(req *types.Request) *ReqRes {
 	reqres := NewReqRes(req)
 
+	// The application would close the connection on reading it.
+	if size := req.Size(); size > cli.params.MaxMsgSize {
+		cli.StopForError(fmt.Errorf("Request %v of %d bytes exceeds the max message size of %d bytes",
+			reflect.TypeOf(req.Value), size, cli.params.MaxMsgSize))
+		reqres.Done()
+		return reqres
+	}
+
 	// TODO: set cli.err if reqQueue times out
 	cli.reqQueue <- reqres
 
@@ -93,18 +93,69 @@ func TestHangingSyncCalls(t *testing.T) {
 	}
 }
 
+func TestSocketClientMaxMsgSize(t *testing.T) {
+	s, c := setupClientServerWithOptions(t, types.NewBaseApplication(),
+		[]server.SocketServerOption{server.SocketServerMaxMsgSize(1000)},
+		abcicli.SocketClientMaxMsgSize(2000))
+	defer s.Stop()
+	defer c.Stop()
+
+	_, err := c.DeliverTxSync(make([]byte, 500))
+	require.NoError(t, err)
+
+	// The server negotiated a smaller size than the client wanted.
+	_, err = c.DeliverTxSync(make([]byte, 1500))
+	assert.Error(t, err)
+	assert.False(t, c.IsRunning())
+}
+
+func TestSocketClientCredits(t *testing.T) {
+	app := slowApp{}
+
+	s, c := setupClientServerWithOptions(t, app, nil, abcicli.SocketClientCredits(1))
+	defer s.Stop()
+	defer c.Stop()
+
+	resp := make(chan error, 1)
+	go func() {
+		// Without credits left, the client flushes the requests in flight
+		// and waits for their responses.
+		var reqs []*abcicli.ReqRes
+		for i := 0; i < 3; i++ {
+			reqs = append(reqs, c.BeginBlockAsync(types.RequestBeginBlock{}))
+		}
+		c.FlushSync()
+		for _, reqres := range reqs {
+			require.NotNil(t, reqres.Response.GetBeginBlock())
+		}
+		resp <- c.Error()
+	}()
+
+	select {
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "No response arrived")
+	case err := <-resp:
+		assert.NoError(t, err)
+	}
+}
+
 func setupClientServer(t *testing.T, app types.Application) (
+	cmn.Service, abcicli.Client) {
+	return setupClientServerWithOptions(t, app, nil)
+}
+
+func setupClientServerWithOptions(t *testing.T, app types.Application,
+	serverOptions []server.SocketServerOption, clientOptions ...abcicli.SocketClientOption) (
 	cmn.Service, abcicli.Client) {
 	// some port between 20k and 30k
 	port := 20000 + cmn.RandInt32()%10000
 	addr := fmt.Sprintf("localhost:%d", port)
 
-	s, err := server.NewServer(addr, "socket", app)
-	require.NoError(t, err)
-	err = s.Start()
+	s := server.NewSocketServer(addr, app, serverOptions...)
+	err := s.Start()
 	require.NoError(t, err)
 
-	c := abcicli.NewSocketClient(addr, true)
+	c := abcicli.NewSocketClient(addr, true, clientOptions...)
 	err = c.Start()
 	require.NoError(t, err)
 
@@ -19,6 +19,7 @@ import (
 	"github.com/tendermint/tendermint/abci/example/counter"
 	"github.com/tendermint/tendermint/abci/example/kvstore"
 	"github.com/tendermint/tendermint/abci/server"
+	"github.com/tendermint/tendermint/abci/tests/conformance"
 	servertest "github.com/tendermint/tendermint/abci/tests/server"
 	"github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/abci/version"
@@ -49,6 +50,13 @@ var (
 
 	// kvstore
 	flagPersist string
+
+	// conformance
+	flagBlocks     int
+	flagMaxTxBytes int
+
+	// script
+	flagJSON bool
 )
 
 var RootCmd = &cobra.Command{
@@ -131,9 +139,17 @@ func addKVStoreFlags() {
 	kvstoreCmd.PersistentFlags().StringVarP(&flagPersist, "persist", "", "", "directory to use for a database")
 }
 
+func addConformanceFlags() {
+	defaults := conformance.DefaultConfig()
+	conformanceCmd.PersistentFlags().IntVarP(&flagBlocks, "blocks", "", defaults.Blocks, "number of blocks to execute")
+	conformanceCmd.PersistentFlags().IntVarP(&flagMaxTxBytes, "max_tx_bytes", "", defaults.MaxTxBytes, "size of the huge transactions")
+}
+
 func addCommands() {
 	RootCmd.AddCommand(batchCmd)
 	RootCmd.AddCommand(consoleCmd)
+	addScriptFlags()
+	RootCmd.AddCommand(scriptCmd)
 	RootCmd.AddCommand(echoCmd)
 	RootCmd.AddCommand(infoCmd)
 	RootCmd.AddCommand(setOptionCmd)
@@ -142,6 +158,8 @@ func addCommands() {
 	RootCmd.AddCommand(commitCmd)
 	RootCmd.AddCommand(versionCmd)
 	RootCmd.AddCommand(testCmd)
+	addConformanceFlags()
+	RootCmd.AddCommand(conformanceCmd)
 	addQueryFlags()
 	RootCmd.AddCommand(queryCmd)
 
@@ -303,6 +321,21 @@ var testCmd = &cobra.Command{
 	},
 }
 
+var conformanceCmd = &cobra.Command{
+	Use:   "conformance",
+	Short: "check an application conforms to the ABCI specification",
+	Long: `check an application conforms to the ABCI specification
+
+This command runs every ABCI method against the application, with edge cases
+like huge transactions and validator changes, and checks its responses. The
+application must be at genesis, as the chain is initialized and blocks are
+executed from height 1.`,
+	Args: cobra.ExactArgs(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmdConformance(cmd, args)
+	},
+}
+
 // Generates new Args array based off of previous call args to maintain flag persistence
 func persistentArgs(line []byte) []string {
 
@@ -354,6 +387,25 @@ func cmdTest(cmd *cobra.Command, args []string) error {
 		})
 }
 
+func cmdConformance(cmd *cobra.Command, args []string) error {
+	config := conformance.DefaultConfig()
+	config.Blocks = flagBlocks
+	config.MaxTxBytes = flagMaxTxBytes
+
+	results := conformance.Run(client, config)
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Printf("Failed check: %s - %v\n", r.Name, r.Err)
+		} else {
+			fmt.Printf("Passed check: %s\n", r.Name)
+		}
+	}
+	if failed := conformance.Failed(results); len(failed) > 0 {
+		return fmt.Errorf("%d of %d checks failed", len(failed), len(results))
+	}
+	return nil
+}
+
 func cmdBatch(cmd *cobra.Command, args []string) error {
 	bufReader := bufio.NewReader(os.Stdin)
 	for {
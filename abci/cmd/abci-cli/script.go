@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/abci/types"
+)
+
+var (
+	scriptMarshaler = jsonpb.Marshaler{
+		EnumsAsInts: true,
+		OrigName:    true,
+	}
+	scriptUnmarshaler = jsonpb.Unmarshaler{}
+)
+
+var scriptCmd = &cobra.Command{
+	Use:   "script [file]",
+	Short: "run a script of abci commands and assertions against an application",
+	Long: `run a script of abci commands and assertions against an application
+
+The script is read from the file, or stdin if none is given. Each line is a
+command, an assertion on the response to the previous command, or a comment
+starting with #:
+
+    # deliver a tx, then check it is stored
+    deliver_tx "abc"
+    expect code 0
+    commit
+    query "abc"
+    expect value "abc"
+    expect log "exists"
+
+The commands are those of the console, and begin_block, end_block and
+init_chain, which take their request in JSON (proto3 JSON mapping, i.e.
+bytes in base64 and 64 bits integers as strings):
+
+    init_chain {"chain_id": "test", "validators": []}
+    begin_block {"header": {"height": "1"}}
+    end_block {"height": "1"}
+
+The request of query can also be given in JSON, to set its path, height or
+proof. An assertion compares a field of the response (its name in JSON) with a
+value: an integer, true or false, a quoted string or a 0x-prefixed hex string
+for bytes. The script stops at the first failed assertion, with an error.
+`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmdScript(cmd, args)
+	},
+}
+
+func addScriptFlags() {
+	scriptCmd.PersistentFlags().BoolVarP(&flagJSON, "json", "", false, "print the responses in JSON, one per line")
+}
+
+func cmdScript(cmd *cobra.Command, args []string) error {
+	input := os.Stdin
+	if len(args) == 1 {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close() // nolint: errcheck
+		input = file
+	}
+	return runScript(input, os.Stdout)
+}
+
+// runScript runs the commands read from r, printing the responses to w.
+func runScript(r io.Reader, w io.Writer) error {
+	var (
+		scanner = bufio.NewScanner(r)
+		lineNum int
+		last    proto.Message // the response to the previous command
+	)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		command, arg := splitCommand(line)
+
+		if command == "expect" {
+			if last == nil {
+				return fmt.Errorf("line %d: no response to check", lineNum)
+			}
+			field, value := splitCommand(arg)
+			if err := checkField(last, field, value); err != nil {
+				return fmt.Errorf("line %d: %v", lineNum, err)
+			}
+			if !flagJSON {
+				fmt.Fprintf(w, "-> %s: OK\n", line)
+			}
+			continue
+		}
+
+		res, err := runScriptCommand(command, arg)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		last = res
+		if err := printScriptResponse(w, lineNum, line, command, res); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// splitCommand splits line into its first word and the rest.
+func splitCommand(line string) (string, string) {
+	parts := strings.SplitN(line, " ", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], strings.TrimSpace(parts[1])
+}
+
+// runScriptCommand sends the request of command to the application and
+// returns its response.
+func runScriptCommand(command, arg string) (proto.Message, error) {
+	switch command {
+	case "echo":
+		return client.EchoSync(unquote(arg))
+	case "info":
+		return client.InfoSync(types.RequestInfo{Version: arg})
+	case "set_option":
+		key, value := splitCommand(arg)
+		return client.SetOptionSync(types.RequestSetOption{Key: key, Value: value})
+	case "check_tx":
+		tx, err := stringOrHexToBytes(arg)
+		if err != nil {
+			return nil, err
+		}
+		return client.CheckTxSync(tx)
+	case "deliver_tx":
+		tx, err := stringOrHexToBytes(arg)
+		if err != nil {
+			return nil, err
+		}
+		return client.DeliverTxSync(tx)
+	case "commit":
+		return client.CommitSync()
+	case "query":
+		var req types.RequestQuery
+		if strings.HasPrefix(arg, "{") {
+			if err := scriptUnmarshaler.Unmarshal(strings.NewReader(arg), &req); err != nil {
+				return nil, err
+			}
+		} else {
+			data, err := stringOrHexToBytes(arg)
+			if err != nil {
+				return nil, err
+			}
+			req = types.RequestQuery{Data: data, Path: flagPath}
+		}
+		return client.QuerySync(req)
+	case "init_chain":
+		var req types.RequestInitChain
+		if err := unmarshalScriptRequest(arg, &req); err != nil {
+			return nil, err
+		}
+		return client.InitChainSync(req)
+	case "begin_block":
+		var req types.RequestBeginBlock
+		if err := unmarshalScriptRequest(arg, &req); err != nil {
+			return nil, err
+		}
+		return client.BeginBlockSync(req)
+	case "end_block":
+		var req types.RequestEndBlock
+		if err := unmarshalScriptRequest(arg, &req); err != nil {
+			return nil, err
+		}
+		return client.EndBlockSync(req)
+	default:
+		return nil, fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// unmarshalScriptRequest unmarshals the JSON request arg into req, leaving it
+// empty if arg is.
+func unmarshalScriptRequest(arg string, req proto.Message) error {
+	if arg == "" {
+		return nil
+	}
+	return scriptUnmarshaler.Unmarshal(strings.NewReader(arg), req)
+}
+
+func printScriptResponse(w io.Writer, lineNum int, line, command string, res proto.Message) error {
+	bz, err := scriptMarshaler.MarshalToString(res)
+	if err != nil {
+		return err
+	}
+	if flagJSON {
+		_, err = fmt.Fprintf(w, "{\"line\":%d,\"command\":%q,\"response\":%s}\n", lineNum, command, bz)
+		return err
+	}
+	_, err = fmt.Fprintf(w, "> %s\n-> %s\n", line, bz)
+	return err
+}
+
+// checkField returns an error if the field of res named name in JSON doesn't
+// have value.
+func checkField(res proto.Message, name, value string) error {
+	v := reflect.ValueOf(res).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		tag := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		if tag != name {
+			continue
+		}
+		field := v.Field(i)
+		expected, err := parseFieldValue(field.Type(), value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %v", name, err)
+		}
+		if !reflect.DeepEqual(normalizeBytes(field.Interface()), normalizeBytes(expected)) {
+			return fmt.Errorf("expected %s to be %s, got %v", name, value, formatField(field.Interface()))
+		}
+		return nil
+	}
+	return fmt.Errorf("no field %s in %v", name, reflect.TypeOf(res).Elem().Name())
+}
+
+// parseFieldValue parses value as a value of type typ.
+func parseFieldValue(typ reflect.Type, value string) (interface{}, error) {
+	switch typ.Kind() {
+	case reflect.Slice:
+		if typ.Elem().Kind() != reflect.Uint8 {
+			return nil, fmt.Errorf("can't compare %v", typ)
+		}
+		return stringOrHexToBytes(value)
+	case reflect.String:
+		return unquote(value), nil
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		return reflect.ValueOf(i).Convert(typ).Interface(), err
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, 64)
+		return reflect.ValueOf(u).Convert(typ).Interface(), err
+	default:
+		return nil, fmt.Errorf("can't compare %v", typ)
+	}
+}
+
+// unquote removes the quotes around s, if any, and unescapes it as a Go
+// string literal, e.g. "{\"size\":2}".
+func unquote(s string) string {
+	if len(s) >= 2 && strings.HasPrefix(s, "\"") && strings.HasSuffix(s, "\"") {
+		if u, err := strconv.Unquote(s); err == nil {
+			return u
+		}
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// normalizeBytes returns nil for empty bytes, so that they equal "".
+func normalizeBytes(v interface{}) interface{} {
+	if bz, ok := v.([]byte); ok && len(bz) == 0 {
+		return []byte(nil)
+	}
+	return v
+}
+
+func formatField(v interface{}) string {
+	switch v := v.(type) {
+	case []byte:
+		return fmt.Sprintf("0x%X", v)
+	case string:
+		return strconv.Quote(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -13,6 +13,7 @@ type CounterApplication struct {
 
 	hashCount int
 	txCount   int
+	appHash   []byte // of the last commit
 	serial    bool
 }
 
@@ -21,7 +22,11 @@ func NewCounterApplication(serial bool) *CounterApplication {
 }
 
 func (app *CounterApplication) Info(req types.RequestInfo) types.ResponseInfo {
-	return types.ResponseInfo{Data: fmt.Sprintf("{\"hashes\":%v,\"txs\":%v}", app.hashCount, app.txCount)}
+	return types.ResponseInfo{
+		Data:             fmt.Sprintf("{\"hashes\":%v,\"txs\":%v}", app.hashCount, app.txCount),
+		LastBlockHeight:  int64(app.hashCount),
+		LastBlockAppHash: app.appHash,
+	}
 }
 
 func (app *CounterApplication) SetOption(req types.RequestSetOption) types.ResponseSetOption {
@@ -86,9 +91,9 @@ func (app *CounterApplication) Commit() (resp types.ResponseCommit) {
 	if app.txCount == 0 {
 		return types.ResponseCommit{}
 	}
-	hash := make([]byte, 8)
-	binary.BigEndian.PutUint64(hash, uint64(app.txCount))
-	return types.ResponseCommit{Data: hash}
+	app.appHash = make([]byte, 8)
+	binary.BigEndian.PutUint64(app.appHash, uint64(app.txCount))
+	return types.ResponseCommit{Data: app.appHash}
 }
 
 func (app *CounterApplication) Query(reqQuery types.RequestQuery) types.ResponseQuery {
@@ -50,10 +50,7 @@ func (app *PersistentKVStoreApplication) SetLogger(l log.Logger) {
 }
 
 func (app *PersistentKVStoreApplication) Info(req types.RequestInfo) types.ResponseInfo {
-	res := app.app.Info(req)
-	res.LastBlockHeight = app.app.state.Height
-	res.LastBlockAppHash = app.app.state.AppHash
-	return res
+	return app.app.Info(req)
 }
 
 func (app *PersistentKVStoreApplication) SetOption(req types.RequestSetOption) types.ResponseSetOption {
@@ -13,12 +13,24 @@ import (
 
 // var maxNumberConnections = 2
 
+const responsesBufferSize = 1000
+
+// SocketServerOption sets an optional parameter on the SocketServer.
+type SocketServerOption func(*SocketServer)
+
+// SocketServerMaxMsgSize sets the maximum size of the messages the server
+// accepts, in bytes. A client may negotiate a smaller one.
+func SocketServerMaxMsgSize(size int) SocketServerOption {
+	return func(s *SocketServer) { s.maxMsgSize = size }
+}
+
 type SocketServer struct {
 	cmn.BaseService
 
-	proto    string
-	addr     string
-	listener net.Listener
+	proto      string
+	addr       string
+	listener   net.Listener
+	maxMsgSize int
 
 	connsMtx   sync.Mutex
 	conns      map[int]net.Conn
@@ -28,16 +40,20 @@ type SocketServer struct {
 	app    types.Application
 }
 
-func NewSocketServer(protoAddr string, app types.Application) cmn.Service {
+func NewSocketServer(protoAddr string, app types.Application, options ...SocketServerOption) cmn.Service {
 	proto, addr := cmn.ProtocolAndAddress(protoAddr)
 	s := &SocketServer{
-		proto:    proto,
-		addr:     addr,
-		listener: nil,
-		app:      app,
-		conns:    make(map[int]net.Conn),
+		proto:      proto,
+		addr:       addr,
+		listener:   nil,
+		maxMsgSize: types.DefaultMaxMsgSize,
+		app:        app,
+		conns:      make(map[int]net.Conn),
 	}
 	s.BaseService = *cmn.NewBaseService(nil, "ABCIServer", s)
+	for _, option := range options {
+		option(s)
+	}
 	return s
 }
 
@@ -112,8 +128,8 @@ func (s *SocketServer) acceptConnectionsRoutine() {
 
 		connID := s.addConn(conn)
 
-		closeConn := make(chan error, 2)              // Push to signal connection closed
-		responses := make(chan *types.Response, 1000) // A channel to buffer responses
+		closeConn := make(chan error, 2)                             // Push to signal connection closed
+		responses := make(chan *types.Response, responsesBufferSize) // A channel to buffer responses
 
 		// Read requests from conn and deal with them
 		go s.handleRequests(closeConn, conn, responses)
@@ -146,10 +162,11 @@ func (s *SocketServer) waitForClose(closeConn chan error, connID int) {
 func (s *SocketServer) handleRequests(closeConn chan error, conn net.Conn, responses chan<- *types.Response) {
 	var count int
 	var bufReader = bufio.NewReader(conn)
+	var maxMsgSize = s.maxMsgSize
 	for {
 
 		var req = &types.Request{}
-		err := types.ReadMessage(bufReader, req)
+		err := types.ReadMessageMaxSize(bufReader, req, maxMsgSize)
 		if err != nil {
 			if err == io.EOF {
 				closeConn <- err
@@ -158,6 +175,13 @@ func (s *SocketServer) handleRequests(closeConn chan error, conn net.Conn, respo
 			}
 			return
 		}
+		if params, ok := s.negotiate(req); ok {
+			s.Logger.Info("Negotiated the connection parameters",
+				"maxMsgSize", params.MaxMsgSize, "credits", params.Credits)
+			maxMsgSize = params.MaxMsgSize
+			responses <- types.ToResponseEcho(params.ResponseMessage())
+			continue
+		}
 		s.appMtx.Lock()
 		count++
 		s.handleRequest(req, responses)
@@ -165,6 +189,23 @@ func (s *SocketServer) handleRequests(closeConn chan error, conn net.Conn, respo
 	}
 }
 
+// negotiate returns the connection parameters if req is a negotiation
+// request. The client may not have more requests in flight than fit in the
+// responses buffer, so that a slow application blocks the client instead of
+// the responses piling up.
+func (s *SocketServer) negotiate(req *types.Request) (types.SocketParams, bool) {
+	echo, ok := req.Value.(*types.Request_Echo)
+	if !ok {
+		return types.SocketParams{}, false
+	}
+	proposed, ok := types.ParseSocketParamsRequest(echo.Echo.Message)
+	if !ok {
+		return types.SocketParams{}, false
+	}
+	params := types.SocketParams{MaxMsgSize: s.maxMsgSize, Credits: responsesBufferSize}
+	return params.Negotiate(proposed), true
+}
+
 func (s *SocketServer) handleRequest(req *types.Request, responses chan<- *types.Response) {
 	switch r := req.Value.(type) {
 	case *types.Request_Echo:
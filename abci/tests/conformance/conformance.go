@@ -0,0 +1,391 @@
+/*
+Package conformance checks that an ABCI application answers every ABCI method
+as Tendermint expects, including edge cases like huge transactions, a
+changing set of signing validators at every block and custom consensus
+params.
+
+The checks only rely on the ABCI specification, not on the logic of the
+application, so they can be run against any application with
+`abci-cli conformance`. The application must be at genesis: the checks
+initialize the chain and execute blocks from height 1.
+*/
+package conformance
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types"
+)
+
+const chainID = "abci-conformance"
+
+// Config sets the requests sent by the checks.
+type Config struct {
+	// Number of blocks to execute.
+	Blocks int
+
+	// Number of random transactions in each block, in addition to the edge
+	// cases (empty and huge transactions).
+	TxsPerBlock int
+
+	// Size of the huge transactions, in bytes.
+	MaxTxBytes int
+
+	// Number of validators at genesis.
+	Validators int
+}
+
+// DefaultConfig returns a default configuration for the checks.
+func DefaultConfig() Config {
+	return Config{
+		Blocks:      5,
+		TxsPerBlock: 5,
+		MaxTxBytes:  1024 * 1024, // 1MB
+		Validators:  4,
+	}
+}
+
+// Result is the result of a check, Err being nil if it passed.
+type Result struct {
+	Name string
+	Err  error
+}
+
+// Failed returns the results of the failed checks.
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// Run runs the checks against the application behind client, in order, and
+// returns their results. The application must be at genesis.
+func Run(client abcicli.Client, config Config) []Result {
+	c := &checker{
+		client: client,
+		config: config,
+	}
+	checks := []struct {
+		name string
+		run  func() error
+	}{
+		{"Echo", c.checkEcho},
+		{"Info", c.checkInfo},
+		{"SetOption", c.checkSetOption},
+		{"InitChain", c.checkInitChain},
+		{"CheckTx", c.checkCheckTx},
+		{"Blocks", c.checkBlocks},
+		{"Query", c.checkQuery},
+	}
+	results := make([]Result, len(checks))
+	for i, check := range checks {
+		results[i] = Result{Name: check.name, Err: check.run()}
+	}
+	return results
+}
+
+// checker holds the state of the chain across the checks.
+type checker struct {
+	client abcicli.Client
+	config Config
+
+	params     types.ConsensusParams
+	validators []abci.ValidatorUpdate
+	height     int64
+	totalTxs   int64
+	appHash    []byte
+}
+
+func (c *checker) checkEcho() error {
+	for _, msg := range []string{"", "hello", cmn.RandStr(1024)} {
+		res, err := c.client.EchoSync(msg)
+		if err != nil {
+			return err
+		}
+		if res.Message != msg {
+			return fmt.Errorf("echoed %q instead of %q", res.Message, msg)
+		}
+	}
+	return nil
+}
+
+func (c *checker) checkInfo() error {
+	res, err := c.client.InfoSync(abci.RequestInfo{Version: "conformance"})
+	if err != nil {
+		return err
+	}
+	if res.LastBlockHeight != 0 || len(res.LastBlockAppHash) != 0 {
+		return fmt.Errorf("the application is not at genesis: height %d, app hash %X",
+			res.LastBlockHeight, res.LastBlockAppHash)
+	}
+	return nil
+}
+
+func (c *checker) checkSetOption() error {
+	// The application may reject the option, but must answer.
+	_, err := c.client.SetOptionSync(abci.RequestSetOption{Key: "abci-conformance", Value: "on"})
+	return err
+}
+
+func (c *checker) checkInitChain() error {
+	c.validators = make([]abci.ValidatorUpdate, c.config.Validators)
+	for i := range c.validators {
+		c.validators[i] = abci.Ed25519ValidatorUpdate(cmn.RandBytes(32), int64(1+cmn.RandIntn(1000)))
+	}
+	// Not the default params, which an application may assume.
+	maxBytes := 2 * int64(c.config.MaxTxBytes) * int64(c.config.TxsPerBlock+3)
+	if maxBytes > types.MaxBlockSizeBytes {
+		maxBytes = types.MaxBlockSizeBytes
+	}
+	c.params = types.DefaultConsensusParams().Update(&abci.ConsensusParams{
+		BlockSize: &abci.BlockSizeParams{MaxBytes: maxBytes, MaxGas: 1000000},
+		Evidence:  &abci.EvidenceParams{MaxAge: 1000},
+	})
+	res, err := c.client.InitChainSync(abci.RequestInitChain{
+		Time:            time.Now(),
+		ChainId:         chainID,
+		ConsensusParams: types.TM2PB.ConsensusParams(&c.params),
+		Validators:      c.validators,
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.Validators) > 0 {
+		if err := validateValidatorUpdates(res.Validators); err != nil {
+			return errors.Wrap(err, "invalid validators")
+		}
+		c.validators = res.Validators
+	}
+	return c.updateParams(res.ConsensusParams)
+}
+
+func (c *checker) checkCheckTx() error {
+	for _, tx := range c.edgeCaseTxs() {
+		res, err := c.client.CheckTxSync(tx)
+		if err != nil {
+			return err
+		}
+		if res.GasWanted < 0 || res.GasUsed < 0 {
+			return fmt.Errorf("negative gas in CheckTx response %v", res)
+		}
+	}
+	return nil
+}
+
+func (c *checker) checkBlocks() error {
+	for i := 0; i < c.config.Blocks; i++ {
+		if err := c.executeBlock(); err != nil {
+			return errors.Wrapf(err, "height %d", c.height+1)
+		}
+	}
+	return nil
+}
+
+func (c *checker) checkQuery() error {
+	res, err := c.client.QuerySync(abci.RequestQuery{Data: []byte("abci-conformance")})
+	if err != nil {
+		return err
+	}
+	if res.Height > c.height {
+		return fmt.Errorf("query at height %d, greater than the last committed height %d", res.Height, c.height)
+	}
+	return nil
+}
+
+//----------------------------------------
+
+// executeBlock executes the next block, the validators signing it changing
+// at every block, and with evidence of misbehavior at every other one.
+func (c *checker) executeBlock() error {
+	height := c.height + 1
+	txs := append(c.edgeCaseTxs(), c.randomTxs()...)
+
+	votes := make([]abci.VoteInfo, len(c.validators))
+	for i, v := range c.validators {
+		pubKey, err := types.PB2TM.PubKey(v.PubKey)
+		if err != nil {
+			return err
+		}
+		votes[i] = abci.VoteInfo{
+			Validator:       abci.Validator{Address: pubKey.Address(), Power: v.Power},
+			SignedLastBlock: height > 1 && (int64(i)+height)%3 != 0,
+		}
+	}
+	var (
+		proposer []byte
+		evidence []abci.Evidence
+	)
+	if len(votes) > 0 {
+		proposer = votes[int(height)%len(votes)].Validator.Address
+	}
+	if height%2 == 0 && len(votes) > 0 {
+		evidence = append(evidence, abci.Evidence{
+			Type:             types.ABCIEvidenceTypeDuplicateVote,
+			Validator:        votes[0].Validator,
+			Height:           height - 1,
+			Time:             time.Now(),
+			TotalVotingPower: totalPower(c.validators),
+		})
+	}
+
+	_, err := c.client.BeginBlockSync(abci.RequestBeginBlock{
+		Hash: cmn.RandBytes(32),
+		Header: abci.Header{
+			ChainID:         chainID,
+			Height:          height,
+			Time:            time.Now(),
+			NumTxs:          int64(len(txs)),
+			TotalTxs:        c.totalTxs + int64(len(txs)),
+			AppHash:         c.appHash,
+			ProposerAddress: proposer,
+		},
+		LastCommitInfo:      abci.LastCommitInfo{Votes: votes},
+		ByzantineValidators: evidence,
+	})
+	if err != nil {
+		return errors.Wrap(err, "BeginBlock")
+	}
+
+	for _, tx := range txs {
+		res, err := c.client.DeliverTxSync(tx)
+		if err != nil {
+			return errors.Wrap(err, "DeliverTx")
+		}
+		if res.GasWanted < 0 || res.GasUsed < 0 {
+			return fmt.Errorf("negative gas in DeliverTx response %v", res)
+		}
+		for _, event := range res.Events {
+			for _, attr := range event.Attributes {
+				if len(attr.Key) == 0 {
+					return fmt.Errorf("empty attribute key in DeliverTx response %v", res)
+				}
+			}
+		}
+	}
+
+	res, err := c.client.EndBlockSync(abci.RequestEndBlock{Height: height})
+	if err != nil {
+		return errors.Wrap(err, "EndBlock")
+	}
+	if err := validateValidatorUpdates(res.ValidatorUpdates); err != nil {
+		return errors.Wrap(err, "invalid validator updates")
+	}
+	if err := c.updateParams(res.ConsensusParamUpdates); err != nil {
+		return err
+	}
+	c.applyValidatorUpdates(res.ValidatorUpdates)
+
+	resCommit, err := c.client.CommitSync()
+	if err != nil {
+		return errors.Wrap(err, "Commit")
+	}
+	c.height = height
+	c.totalTxs += int64(len(txs))
+	c.appHash = resCommit.Data
+
+	// The handshake relies on Info to know the last block the application
+	// committed.
+	resInfo, err := c.client.InfoSync(abci.RequestInfo{})
+	if err != nil {
+		return errors.Wrap(err, "Info")
+	}
+	if resInfo.LastBlockHeight != c.height {
+		return fmt.Errorf("Info returned the height %d after committing %d", resInfo.LastBlockHeight, c.height)
+	}
+	if !bytes.Equal(resInfo.LastBlockAppHash, c.appHash) {
+		return fmt.Errorf("Info returned the app hash %X after committing %X", resInfo.LastBlockAppHash, c.appHash)
+	}
+	return nil
+}
+
+// edgeCaseTxs returns an empty, a one byte and a huge transaction.
+func (c *checker) edgeCaseTxs() [][]byte {
+	return [][]byte{{}, {0x00}, cmn.RandBytes(c.config.MaxTxBytes)}
+}
+
+func (c *checker) randomTxs() [][]byte {
+	txs := make([][]byte, c.config.TxsPerBlock)
+	for i := range txs {
+		txs[i] = cmn.RandBytes(1 + cmn.RandIntn(256))
+	}
+	return txs
+}
+
+// updateParams checks the consensus params resulting from the updates are
+// valid, and applies them.
+func (c *checker) updateParams(updates *abci.ConsensusParams) error {
+	params := c.params.Update(updates)
+	if err := params.Validate(); err != nil {
+		return errors.Wrap(err, "invalid consensus params")
+	}
+	c.params = params
+	return nil
+}
+
+// applyValidatorUpdates applies valid updates to the validators.
+func (c *checker) applyValidatorUpdates(updates []abci.ValidatorUpdate) {
+	for _, u := range updates {
+		i := 0
+		for ; i < len(c.validators); i++ {
+			if c.validators[i].PubKey.Equal(u.PubKey) {
+				break
+			}
+		}
+		switch {
+		case i < len(c.validators) && u.Power == 0:
+			c.validators = append(c.validators[:i], c.validators[i+1:]...)
+		case i < len(c.validators):
+			c.validators[i] = u
+		case u.Power > 0:
+			c.validators = append(c.validators, u)
+		}
+	}
+}
+
+// validateValidatorUpdates returns an error if the updates have an unknown
+// public key type, a negative power, a total power overflowing the maximum
+// or duplicates.
+func validateValidatorUpdates(updates []abci.ValidatorUpdate) error {
+	vals, err := types.PB2TM.ValidatorUpdates(updates)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(vals))
+	for _, val := range vals {
+		if val.VotingPower < 0 {
+			return fmt.Errorf("negative power %d for %v", val.VotingPower, val.PubKey)
+		}
+		if seen[string(val.Address)] {
+			return fmt.Errorf("duplicate update for %v", val.PubKey)
+		}
+		seen[string(val.Address)] = true
+	}
+	if total := totalPower(updates); total > types.MaxTotalVotingPower || total < 0 {
+		return fmt.Errorf("total power of the updates %d exceeds the maximum %d", total, types.MaxTotalVotingPower)
+	}
+	return nil
+}
+
+// totalPower returns the total power of the validators, negative if it
+// overflows.
+func totalPower(validators []abci.ValidatorUpdate) int64 {
+	var total int64
+	for _, v := range validators {
+		total += v.Power
+		if total < 0 {
+			return -1
+		}
+	}
+	return total
+}
@@ -0,0 +1,72 @@
+package conformance
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/example/counter"
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.MaxTxBytes = 1024
+	return config
+}
+
+func runChecks(t *testing.T, app abci.Application) []Result {
+	client := abcicli.NewLocalClient(nil, app)
+	require.NoError(t, client.Start())
+	defer client.Stop()
+	return Run(client, testConfig())
+}
+
+func TestExampleApps(t *testing.T) {
+	dir, err := ioutil.TempDir("", "abci_conformance_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	apps := map[string]abci.Application{
+		"counter":            counter.NewCounterApplication(false),
+		"kvstore":            kvstore.NewKVStoreApplication(),
+		"persistent_kvstore": kvstore.NewPersistentKVStoreApplication(dir),
+	}
+	for name, app := range apps {
+		results := runChecks(t, app)
+		assert.Len(t, results, 7)
+		assert.Empty(t, Failed(results), name)
+	}
+}
+
+// forgetfulApp doesn't report the blocks it committed in Info.
+type forgetfulApp struct {
+	abci.BaseApplication
+}
+
+// eventsApp returns an empty attribute key.
+type eventsApp struct {
+	abci.BaseApplication
+}
+
+func (eventsApp) DeliverTx(tx []byte) abci.ResponseDeliverTx {
+	return abci.ResponseDeliverTx{Events: []abci.Event{{Type: "tx", Attributes: []cmn.KVPair{{Key: nil, Value: tx}}}}}
+}
+
+func TestNonConformingApps(t *testing.T) {
+	failed := Failed(runChecks(t, forgetfulApp{}))
+	require.Len(t, failed, 1)
+	assert.Equal(t, "Blocks", failed[0].Name)
+	assert.Contains(t, failed[0].Err.Error(), "Info returned the height 0 after committing 1")
+
+	failed = Failed(runChecks(t, eventsApp{}))
+	require.Len(t, failed, 1)
+	assert.Equal(t, "Blocks", failed[0].Name)
+	assert.Contains(t, failed[0].Err.Error(), "empty attribute key")
+}
@@ -38,5 +38,11 @@ function testExample() {
 testExample 1 tests/test_cli/ex1.abci abci-cli kvstore
 testExample 2 tests/test_cli/ex2.abci abci-cli counter
 
+echo "Script: abci-cli kvstore"
+abci-cli kvstore &> /dev/null &
+sleep 2
+abci-cli --log_level=error script tests/test_cli/ex3.abci > /dev/null
+killall abci-cli
+
 echo ""
 echo "PASS"
@@ -0,0 +1,56 @@
+package types
+
+import (
+	"bytes"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// EventKey returns the composite key of the attribute of an event of the type,
+// matched by the queries of the events and txs: type.key, or the key for an
+// event without type.
+func EventKey(eventType string, key []byte) string {
+	if eventType == "" {
+		return string(key)
+	}
+	return eventType + "." + string(key)
+}
+
+// EventsFromTags converts the tags of the previous versions of ABCI, still
+// returned by the applications not upgraded yet, to events, matched by the
+// same queries: the tags are grouped by the prefix of their keys up to the
+// first dot, into events of this type, in the order of their first tag. E.g.
+// the tags transfer.sender and transfer.recipient are the attributes sender
+// and recipient of a transfer event. The tags without a dot are the attributes
+// of an event without type.
+func EventsFromTags(tags []cmn.KVPair) []Event {
+	var events []Event
+	index := make(map[string]int) // by type
+	for _, tag := range tags {
+		eventType, key := "", tag.Key
+		if i := bytes.IndexByte(tag.Key, '.'); i >= 0 {
+			eventType, key = string(tag.Key[:i]), tag.Key[i+1:]
+		}
+		i, ok := index[eventType]
+		if !ok {
+			i = len(events)
+			index[eventType] = i
+			events = append(events, Event{Type: eventType})
+		}
+		events[i].Attributes = append(events[i].Attributes, cmn.KVPair{Key: key, Value: tag.Value})
+	}
+	return events
+}
+
+// TagsFromEvents flattens the events to the tags of the previous versions of
+// ABCI, keyed by the composite keys of their attributes (see EventKey), for
+// the clients expecting tags. It is the inverse of EventsFromTags.
+func TagsFromEvents(events []Event) []cmn.KVPair {
+	var tags []cmn.KVPair
+	for _, event := range events {
+		for _, attr := range event.Attributes {
+			tags = append(tags, cmn.KVPair{Key: []byte(EventKey(event.Type, attr.Key)), Value: attr.Value})
+		}
+	}
+	return tags
+}
@@ -0,0 +1,38 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+func TestEventKey(t *testing.T) {
+	assert.Equal(t, "transfer.sender", EventKey("transfer", []byte("sender")))
+	assert.Equal(t, "fee", EventKey("", []byte("fee")))
+}
+
+func TestEventsFromTags(t *testing.T) {
+	assert.Nil(t, EventsFromTags(nil))
+
+	tags := []cmn.KVPair{
+		{Key: []byte("transfer.sender"), Value: []byte("alice")},
+		{Key: []byte("fee"), Value: []byte("1")},
+		{Key: []byte("transfer.recipient"), Value: []byte("bob")},
+		{Key: []byte("message.action.name"), Value: []byte("send")},
+	}
+	events := EventsFromTags(tags)
+	assert.Equal(t, []Event{
+		{Type: "transfer", Attributes: []cmn.KVPair{
+			{Key: []byte("sender"), Value: []byte("alice")},
+			{Key: []byte("recipient"), Value: []byte("bob")},
+		}},
+		{Type: "", Attributes: []cmn.KVPair{{Key: []byte("fee"), Value: []byte("1")}}},
+		{Type: "message", Attributes: []cmn.KVPair{{Key: []byte("action.name"), Value: []byte("send")}}},
+	}, events)
+
+	// the composite keys of the events are the keys of the tags
+	assert.Equal(t, []cmn.KVPair{tags[0], tags[2], tags[1], tags[3]}, TagsFromEvents(events))
+	assert.Nil(t, TagsFromEvents(nil))
+}
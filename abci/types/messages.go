@@ -3,13 +3,19 @@ package types
 import (
 	"bufio"
 	"encoding/binary"
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/gogo/protobuf/proto"
 )
 
 const (
 	maxMsgSize = 104857600 // 100MB
+
+	// DefaultMaxMsgSize is the maximum size of a message on the socket
+	// protocol, unless a larger one is negotiated.
+	DefaultMaxMsgSize = maxMsgSize
 )
 
 // WriteMessage writes a varint length-delimited protobuf message.
@@ -26,6 +32,12 @@ func ReadMessage(r io.Reader, msg proto.Message) error {
 	return readProtoMsg(r, msg, maxMsgSize)
 }
 
+// ReadMessageMaxSize reads a varint length-delimited protobuf message of at
+// most maxSize bytes.
+func ReadMessageMaxSize(r io.Reader, msg proto.Message, maxSize int) error {
+	return readProtoMsg(r, msg, maxSize)
+}
+
 func readProtoMsg(r io.Reader, msg proto.Message, maxSize int) error {
 	// binary.ReadVarint takes an io.ByteReader, eg. a bufio.Reader
 	reader, ok := r.(*bufio.Reader)
@@ -47,6 +59,86 @@ func readProtoMsg(r io.Reader, msg proto.Message, maxSize int) error {
 	return proto.Unmarshal(buf, msg)
 }
 
+//-----------------------------------------------------------------------
+// Socket protocol negotiation.
+//
+// Right after connecting, the socket client sends an Echo request with the
+// parameters it wants (SocketParams.RequestMessage). A server supporting the
+// negotiation replies with the negotiated parameters
+// (SocketParams.ResponseMessage), while an older one echoes the message back
+// unchanged, in which case the defaults are used.
+
+const (
+	socketParamsRequestPrefix  = "abci-negotiate"
+	socketParamsResponsePrefix = "abci-negotiated"
+)
+
+// SocketParams are the parameters of a socket protocol connection.
+type SocketParams struct {
+	// Maximum size of a message, in bytes.
+	MaxMsgSize int
+
+	// Maximum number of requests, other than Flush, the client can send
+	// before receiving their responses. 0 means unlimited.
+	Credits int
+}
+
+// DefaultSocketParams returns the parameters used when none are negotiated.
+func DefaultSocketParams() SocketParams {
+	return SocketParams{MaxMsgSize: DefaultMaxMsgSize}
+}
+
+// Negotiate returns the parameters acceptable to both p and other: the
+// smallest message size and number of credits, 0 credits being unlimited.
+func (p SocketParams) Negotiate(other SocketParams) SocketParams {
+	if other.MaxMsgSize < p.MaxMsgSize {
+		p.MaxMsgSize = other.MaxMsgSize
+	}
+	if p.Credits == 0 || (other.Credits > 0 && other.Credits < p.Credits) {
+		p.Credits = other.Credits
+	}
+	return p
+}
+
+// RequestMessage returns the Echo message a client sends to propose p.
+func (p SocketParams) RequestMessage() string {
+	return p.message(socketParamsRequestPrefix)
+}
+
+// ResponseMessage returns the Echo message a server replies with to accept p.
+func (p SocketParams) ResponseMessage() string {
+	return p.message(socketParamsResponsePrefix)
+}
+
+func (p SocketParams) message(prefix string) string {
+	return fmt.Sprintf("%s max_msg_size=%d credits=%d", prefix, p.MaxMsgSize, p.Credits)
+}
+
+// ParseSocketParamsRequest parses the parameters proposed by a client. It
+// returns false if msg isn't a negotiation request.
+func ParseSocketParamsRequest(msg string) (SocketParams, bool) {
+	return parseSocketParams(socketParamsRequestPrefix, msg)
+}
+
+// ParseSocketParamsResponse parses the parameters accepted by a server. It
+// returns false if msg isn't a negotiation response, e.g. if the server
+// doesn't support the negotiation.
+func ParseSocketParamsResponse(msg string) (SocketParams, bool) {
+	return parseSocketParams(socketParamsResponsePrefix, msg)
+}
+
+func parseSocketParams(prefix, msg string) (SocketParams, bool) {
+	var p SocketParams
+	if !strings.HasPrefix(msg, prefix+" ") {
+		return p, false
+	}
+	n, err := fmt.Sscanf(msg[len(prefix):], " max_msg_size=%d credits=%d", &p.MaxMsgSize, &p.Credits)
+	if err != nil || n != 2 || p.MaxMsgSize <= 0 || p.Credits < 0 {
+		return p, false
+	}
+	return p, true
+}
+
 //-----------------------------------------------------------------------
 // NOTE: we copied wire.EncodeByteSlice from go-wire rather than keep
 // go-wire as a dep
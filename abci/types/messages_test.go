@@ -21,8 +21,8 @@ func TestMarshalJSON(t *testing.T) {
 		Code:      1,
 		Data:      []byte("hello"),
 		GasWanted: 43,
-		Tags: []cmn.KVPair{
-			{Key: []byte("pho"), Value: []byte("bo")},
+		Events: []Event{
+			{Type: "testEvent", Attributes: []cmn.KVPair{{Key: []byte("pho"), Value: []byte("bo")}}},
 		},
 	}
 	b, err = json.Marshal(&r1)
@@ -82,8 +82,8 @@ func TestWriteReadMessage2(t *testing.T) {
 			Data:      []byte(phrase),
 			Log:       phrase,
 			GasWanted: 10,
-			Tags: []cmn.KVPair{
-				{Key: []byte("abc"), Value: []byte("def")},
+			Events: []Event{
+				{Type: "testEvent", Attributes: []cmn.KVPair{{Key: []byte("abc"), Value: []byte("def")}}},
 			},
 		},
 		// TODO: add the rest
@@ -101,3 +101,29 @@ func TestWriteReadMessage2(t *testing.T) {
 		assert.Equal(t, c, msg)
 	}
 }
+
+func TestSocketParams(t *testing.T) {
+	client := SocketParams{MaxMsgSize: 2000, Credits: 0}
+	server := SocketParams{MaxMsgSize: 1000, Credits: 10}
+
+	p, ok := ParseSocketParamsRequest(client.RequestMessage())
+	assert.True(t, ok)
+	assert.Equal(t, client, p)
+
+	negotiated := server.Negotiate(p)
+	assert.Equal(t, SocketParams{MaxMsgSize: 1000, Credits: 10}, negotiated)
+	assert.Equal(t, SocketParams{MaxMsgSize: 500, Credits: 5},
+		server.Negotiate(SocketParams{MaxMsgSize: 500, Credits: 5}))
+	assert.Equal(t, SocketParams{MaxMsgSize: 1000, Credits: 0},
+		SocketParams{MaxMsgSize: 1000}.Negotiate(client), "unlimited credits on both sides")
+
+	p, ok = ParseSocketParamsResponse(negotiated.ResponseMessage())
+	assert.True(t, ok)
+	assert.Equal(t, negotiated, p)
+
+	// A server which doesn't support the negotiation echoes the request.
+	_, ok = ParseSocketParamsResponse(client.RequestMessage())
+	assert.False(t, ok)
+	_, ok = ParseSocketParamsRequest("Hello")
+	assert.False(t, ok)
+}
@@ -1990,6 +1990,7 @@ func (m *ResponseQuery) GetCodespace() string {
 
 type ResponseBeginBlock struct {
 	Tags                 []common.KVPair `protobuf:"bytes,1,rep,name=tags" json:"tags,omitempty"`
+	Events               []Event         `protobuf:"bytes,2,rep,name=events" json:"events,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -2035,6 +2036,13 @@ func (m *ResponseBeginBlock) GetTags() []common.KVPair {
 	return nil
 }
 
+func (m *ResponseBeginBlock) GetEvents() []Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
 type ResponseCheckTx struct {
 	Code                 uint32          `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
 	Data                 []byte          `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
@@ -2044,6 +2052,9 @@ type ResponseCheckTx struct {
 	GasUsed              int64           `protobuf:"varint,6,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
 	Tags                 []common.KVPair `protobuf:"bytes,7,rep,name=tags" json:"tags,omitempty"`
 	Codespace            string          `protobuf:"bytes,8,opt,name=codespace,proto3" json:"codespace,omitempty"`
+	ReplacementKey       []byte          `protobuf:"bytes,9,opt,name=replacement_key,json=replacementKey,proto3" json:"replacement_key,omitempty"`
+	Priority             int64           `protobuf:"varint,10,opt,name=priority,proto3" json:"priority,omitempty"`
+	Events               []Event         `protobuf:"bytes,11,rep,name=events" json:"events,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -2138,6 +2149,27 @@ func (m *ResponseCheckTx) GetCodespace() string {
 	return ""
 }
 
+func (m *ResponseCheckTx) GetReplacementKey() []byte {
+	if m != nil {
+		return m.ReplacementKey
+	}
+	return nil
+}
+
+func (m *ResponseCheckTx) GetPriority() int64 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *ResponseCheckTx) GetEvents() []Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
 type ResponseDeliverTx struct {
 	Code                 uint32          `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
 	Data                 []byte          `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
@@ -2147,6 +2179,7 @@ type ResponseDeliverTx struct {
 	GasUsed              int64           `protobuf:"varint,6,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
 	Tags                 []common.KVPair `protobuf:"bytes,7,rep,name=tags" json:"tags,omitempty"`
 	Codespace            string          `protobuf:"bytes,8,opt,name=codespace,proto3" json:"codespace,omitempty"`
+	Events               []Event         `protobuf:"bytes,9,rep,name=events" json:"events,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -2241,10 +2274,19 @@ func (m *ResponseDeliverTx) GetCodespace() string {
 	return ""
 }
 
+func (m *ResponseDeliverTx) GetEvents() []Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
 type ResponseEndBlock struct {
 	ValidatorUpdates      []ValidatorUpdate `protobuf:"bytes,1,rep,name=validator_updates,json=validatorUpdates" json:"validator_updates"`
 	ConsensusParamUpdates *ConsensusParams  `protobuf:"bytes,2,opt,name=consensus_param_updates,json=consensusParamUpdates" json:"consensus_param_updates,omitempty"`
 	Tags                  []common.KVPair   `protobuf:"bytes,3,rep,name=tags" json:"tags,omitempty"`
+	AppVersion            uint64            `protobuf:"varint,4,opt,name=app_version,json=appVersion,proto3" json:"app_version,omitempty"`
+	Events                []Event           `protobuf:"bytes,5,rep,name=events" json:"events,omitempty"`
 	XXX_NoUnkeyedLiteral  struct{}          `json:"-"`
 	XXX_unrecognized      []byte            `json:"-"`
 	XXX_sizecache         int32             `json:"-"`
@@ -2304,6 +2346,20 @@ func (m *ResponseEndBlock) GetTags() []common.KVPair {
 	return nil
 }
 
+func (m *ResponseEndBlock) GetAppVersion() uint64 {
+	if m != nil {
+		return m.AppVersion
+	}
+	return 0
+}
+
+func (m *ResponseEndBlock) GetEvents() []Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
 type ResponseCommit struct {
 	// reserve 1
 	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
@@ -2627,6 +2683,61 @@ func (m *LastCommitInfo) GetVotes() []VoteInfo {
 	return nil
 }
 
+type Event struct {
+	Type                 string          `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Attributes           []common.KVPair `protobuf:"bytes,2,rep,name=attributes" json:"attributes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_types_5b877df1938afe10, []int{30}
+}
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(dst, src)
+}
+func (m *Event) XXX_Size() int {
+	return m.Size()
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Event) GetAttributes() []common.KVPair {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
 type Header struct {
 	// basic block info
 	Version  Version   `protobuf:"bytes,1,opt,name=version" json:"version"`
@@ -2658,7 +2769,7 @@ func (m *Header) Reset()         { *m = Header{} }
 func (m *Header) String() string { return proto.CompactTextString(m) }
 func (*Header) ProtoMessage()    {}
 func (*Header) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_5b877df1938afe10, []int{30}
+	return fileDescriptor_types_5b877df1938afe10, []int{31}
 }
 func (m *Header) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2811,7 +2922,7 @@ func (m *Version) Reset()         { *m = Version{} }
 func (m *Version) String() string { return proto.CompactTextString(m) }
 func (*Version) ProtoMessage()    {}
 func (*Version) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_5b877df1938afe10, []int{31}
+	return fileDescriptor_types_5b877df1938afe10, []int{32}
 }
 func (m *Version) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2866,7 +2977,7 @@ func (m *BlockID) Reset()         { *m = BlockID{} }
 func (m *BlockID) String() string { return proto.CompactTextString(m) }
 func (*BlockID) ProtoMessage()    {}
 func (*BlockID) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_5b877df1938afe10, []int{32}
+	return fileDescriptor_types_5b877df1938afe10, []int{33}
 }
 func (m *BlockID) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2921,7 +3032,7 @@ func (m *PartSetHeader) Reset()         { *m = PartSetHeader{} }
 func (m *PartSetHeader) String() string { return proto.CompactTextString(m) }
 func (*PartSetHeader) ProtoMessage()    {}
 func (*PartSetHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_5b877df1938afe10, []int{33}
+	return fileDescriptor_types_5b877df1938afe10, []int{34}
 }
 func (m *PartSetHeader) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2978,7 +3089,7 @@ func (m *Validator) Reset()         { *m = Validator{} }
 func (m *Validator) String() string { return proto.CompactTextString(m) }
 func (*Validator) ProtoMessage()    {}
 func (*Validator) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_5b877df1938afe10, []int{34}
+	return fileDescriptor_types_5b877df1938afe10, []int{35}
 }
 func (m *Validator) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3034,7 +3145,7 @@ func (m *ValidatorUpdate) Reset()         { *m = ValidatorUpdate{} }
 func (m *ValidatorUpdate) String() string { return proto.CompactTextString(m) }
 func (*ValidatorUpdate) ProtoMessage()    {}
 func (*ValidatorUpdate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_5b877df1938afe10, []int{35}
+	return fileDescriptor_types_5b877df1938afe10, []int{36}
 }
 func (m *ValidatorUpdate) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3090,7 +3201,7 @@ func (m *VoteInfo) Reset()         { *m = VoteInfo{} }
 func (m *VoteInfo) String() string { return proto.CompactTextString(m) }
 func (*VoteInfo) ProtoMessage()    {}
 func (*VoteInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_5b877df1938afe10, []int{36}
+	return fileDescriptor_types_5b877df1938afe10, []int{37}
 }
 func (m *VoteInfo) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3145,7 +3256,7 @@ func (m *PubKey) Reset()         { *m = PubKey{} }
 func (m *PubKey) String() string { return proto.CompactTextString(m) }
 func (*PubKey) ProtoMessage()    {}
 func (*PubKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_5b877df1938afe10, []int{37}
+	return fileDescriptor_types_5b877df1938afe10, []int{38}
 }
 func (m *PubKey) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3203,7 +3314,7 @@ func (m *Evidence) Reset()         { *m = Evidence{} }
 func (m *Evidence) String() string { return proto.CompactTextString(m) }
 func (*Evidence) ProtoMessage()    {}
 func (*Evidence) Descriptor() ([]byte, []int) {
-	return fileDescriptor_types_5b877df1938afe10, []int{38}
+	return fileDescriptor_types_5b877df1938afe10, []int{39}
 }
 func (m *Evidence) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3328,6 +3439,8 @@ func init() {
 	golang_proto.RegisterType((*ValidatorParams)(nil), "types.ValidatorParams")
 	proto.RegisterType((*LastCommitInfo)(nil), "types.LastCommitInfo")
 	golang_proto.RegisterType((*LastCommitInfo)(nil), "types.LastCommitInfo")
+	proto.RegisterType((*Event)(nil), "types.Event")
+	golang_proto.RegisterType((*Event)(nil), "types.Event")
 	proto.RegisterType((*Header)(nil), "types.Header")
 	golang_proto.RegisterType((*Header)(nil), "types.Header")
 	proto.RegisterType((*Version)(nil), "types.Version")
@@ -4568,6 +4681,14 @@ func (this *ResponseBeginBlock) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.Events) != len(that1.Events) {
+		return false
+	}
+	for i := range this.Events {
+		if !this.Events[i].Equal(&that1.Events[i]) {
+			return false
+		}
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -4621,6 +4742,20 @@ func (this *ResponseCheckTx) Equal(that interface{}) bool {
 	if this.Codespace != that1.Codespace {
 		return false
 	}
+	if !bytes.Equal(this.ReplacementKey, that1.ReplacementKey) {
+		return false
+	}
+	if this.Priority != that1.Priority {
+		return false
+	}
+	if len(this.Events) != len(that1.Events) {
+		return false
+	}
+	for i := range this.Events {
+		if !this.Events[i].Equal(&that1.Events[i]) {
+			return false
+		}
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -4674,6 +4809,14 @@ func (this *ResponseDeliverTx) Equal(that interface{}) bool {
 	if this.Codespace != that1.Codespace {
 		return false
 	}
+	if len(this.Events) != len(that1.Events) {
+		return false
+	}
+	for i := range this.Events {
+		if !this.Events[i].Equal(&that1.Events[i]) {
+			return false
+		}
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -4717,6 +4860,17 @@ func (this *ResponseEndBlock) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if this.AppVersion != that1.AppVersion {
+		return false
+	}
+	if len(this.Events) != len(that1.Events) {
+		return false
+	}
+	for i := range this.Events {
+		if !this.Events[i].Equal(&that1.Events[i]) {
+			return false
+		}
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -4906,6 +5060,41 @@ func (this *LastCommitInfo) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *Event) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Event)
+	if !ok {
+		that2, ok := that.(Event)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Type != that1.Type {
+		return false
+	}
+	if len(this.Attributes) != len(that1.Attributes) {
+		return false
+	}
+	for i := range this.Attributes {
+		if !this.Attributes[i].Equal(&that1.Attributes[i]) {
+			return false
+		}
+	}
+	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
+		return false
+	}
+	return true
+}
 func (this *Header) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -6703,6 +6892,18 @@ func (m *ResponseBeginBlock) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.Events) > 0 {
+		for _, msg := range m.Events {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -6775,6 +6976,29 @@ func (m *ResponseCheckTx) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintTypes(dAtA, i, uint64(len(m.Codespace)))
 		i += copy(dAtA[i:], m.Codespace)
 	}
+	if len(m.ReplacementKey) > 0 {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.ReplacementKey)))
+		i += copy(dAtA[i:], m.ReplacementKey)
+	}
+	if m.Priority != 0 {
+		dAtA[i] = 0x50
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.Priority))
+	}
+	if len(m.Events) > 0 {
+		for _, msg := range m.Events {
+			dAtA[i] = 0x5a
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -6847,6 +7071,18 @@ func (m *ResponseDeliverTx) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintTypes(dAtA, i, uint64(len(m.Codespace)))
 		i += copy(dAtA[i:], m.Codespace)
 	}
+	if len(m.Events) > 0 {
+		for _, msg := range m.Events {
+			dAtA[i] = 0x4a
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -6902,6 +7138,23 @@ func (m *ResponseEndBlock) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if m.AppVersion != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(m.AppVersion))
+	}
+	if len(m.Events) > 0 {
+		for _, msg := range m.Events {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		i += copy(dAtA[i:], m.XXX_unrecognized)
 	}
@@ -7117,6 +7370,45 @@ func (m *LastCommitInfo) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *Event) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Event) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Type) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.Type)))
+		i += copy(dAtA[i:], m.Type)
+	}
+	if len(m.Attributes) > 0 {
+		for _, msg := range m.Attributes {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintTypes(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		i += copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return i, nil
+}
+
 func (m *Header) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -7994,8 +8286,16 @@ func NewPopulatedResponseBeginBlock(r randyTypes, easy bool) *ResponseBeginBlock
 			this.Tags[i] = *v19
 		}
 	}
+	if r.Intn(10) != 0 {
+		v20 := r.Intn(5)
+		this.Events = make([]Event, v20)
+		for i := 0; i < v20; i++ {
+			v21 := NewPopulatedEvent(r, easy)
+			this.Events[i] = *v21
+		}
+	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedTypes(r, 2)
+		this.XXX_unrecognized = randUnrecognizedTypes(r, 3)
 	}
 	return this
 }
@@ -8003,9 +8303,9 @@ func NewPopulatedResponseBeginBlock(r randyTypes, easy bool) *ResponseBeginBlock
 func NewPopulatedResponseCheckTx(r randyTypes, easy bool) *ResponseCheckTx {
 	this := &ResponseCheckTx{}
 	this.Code = uint32(r.Uint32())
-	v20 := r.Intn(100)
-	this.Data = make([]byte, v20)
-	for i := 0; i < v20; i++ {
+	v22 := r.Intn(100)
+	this.Data = make([]byte, v22)
+	for i := 0; i < v22; i++ {
 		this.Data[i] = byte(r.Intn(256))
 	}
 	this.Log = string(randStringTypes(r))
@@ -8019,16 +8319,33 @@ func NewPopulatedResponseCheckTx(r randyTypes, easy bool) *ResponseCheckTx {
 		this.GasUsed *= -1
 	}
 	if r.Intn(10) != 0 {
-		v21 := r.Intn(5)
-		this.Tags = make([]common.KVPair, v21)
-		for i := 0; i < v21; i++ {
-			v22 := common.NewPopulatedKVPair(r, easy)
-			this.Tags[i] = *v22
+		v23 := r.Intn(5)
+		this.Tags = make([]common.KVPair, v23)
+		for i := 0; i < v23; i++ {
+			v24 := common.NewPopulatedKVPair(r, easy)
+			this.Tags[i] = *v24
 		}
 	}
 	this.Codespace = string(randStringTypes(r))
+	v25 := r.Intn(100)
+	this.ReplacementKey = make([]byte, v25)
+	for i := 0; i < v25; i++ {
+		this.ReplacementKey[i] = byte(r.Intn(256))
+	}
+	this.Priority = int64(r.Int63())
+	if r.Intn(2) == 0 {
+		this.Priority *= -1
+	}
+	if r.Intn(10) != 0 {
+		v20 := r.Intn(5)
+		this.Events = make([]Event, v20)
+		for i := 0; i < v20; i++ {
+			v21 := NewPopulatedEvent(r, easy)
+			this.Events[i] = *v21
+		}
+	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedTypes(r, 9)
+		this.XXX_unrecognized = randUnrecognizedTypes(r, 12)
 	}
 	return this
 }
@@ -8036,9 +8353,9 @@ func NewPopulatedResponseCheckTx(r randyTypes, easy bool) *ResponseCheckTx {
 func NewPopulatedResponseDeliverTx(r randyTypes, easy bool) *ResponseDeliverTx {
 	this := &ResponseDeliverTx{}
 	this.Code = uint32(r.Uint32())
-	v23 := r.Intn(100)
-	this.Data = make([]byte, v23)
-	for i := 0; i < v23; i++ {
+	v26 := r.Intn(100)
+	this.Data = make([]byte, v26)
+	for i := 0; i < v26; i++ {
 		this.Data[i] = byte(r.Intn(256))
 	}
 	this.Log = string(randStringTypes(r))
@@ -8052,16 +8369,24 @@ func NewPopulatedResponseDeliverTx(r randyTypes, easy bool) *ResponseDeliverTx {
 		this.GasUsed *= -1
 	}
 	if r.Intn(10) != 0 {
-		v24 := r.Intn(5)
-		this.Tags = make([]common.KVPair, v24)
-		for i := 0; i < v24; i++ {
-			v25 := common.NewPopulatedKVPair(r, easy)
-			this.Tags[i] = *v25
+		v27 := r.Intn(5)
+		this.Tags = make([]common.KVPair, v27)
+		for i := 0; i < v27; i++ {
+			v28 := common.NewPopulatedKVPair(r, easy)
+			this.Tags[i] = *v28
 		}
 	}
 	this.Codespace = string(randStringTypes(r))
+	if r.Intn(10) != 0 {
+		v20 := r.Intn(5)
+		this.Events = make([]Event, v20)
+		for i := 0; i < v20; i++ {
+			v21 := NewPopulatedEvent(r, easy)
+			this.Events[i] = *v21
+		}
+	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedTypes(r, 9)
+		this.XXX_unrecognized = randUnrecognizedTypes(r, 10)
 	}
 	return this
 }
@@ -8069,35 +8394,44 @@ func NewPopulatedResponseDeliverTx(r randyTypes, easy bool) *ResponseDeliverTx {
 func NewPopulatedResponseEndBlock(r randyTypes, easy bool) *ResponseEndBlock {
 	this := &ResponseEndBlock{}
 	if r.Intn(10) != 0 {
-		v26 := r.Intn(5)
-		this.ValidatorUpdates = make([]ValidatorUpdate, v26)
-		for i := 0; i < v26; i++ {
-			v27 := NewPopulatedValidatorUpdate(r, easy)
-			this.ValidatorUpdates[i] = *v27
+		v29 := r.Intn(5)
+		this.ValidatorUpdates = make([]ValidatorUpdate, v29)
+		for i := 0; i < v29; i++ {
+			v30 := NewPopulatedValidatorUpdate(r, easy)
+			this.ValidatorUpdates[i] = *v30
 		}
 	}
 	if r.Intn(10) != 0 {
 		this.ConsensusParamUpdates = NewPopulatedConsensusParams(r, easy)
 	}
 	if r.Intn(10) != 0 {
-		v28 := r.Intn(5)
-		this.Tags = make([]common.KVPair, v28)
-		for i := 0; i < v28; i++ {
-			v29 := common.NewPopulatedKVPair(r, easy)
-			this.Tags[i] = *v29
+		v31 := r.Intn(5)
+		this.Tags = make([]common.KVPair, v31)
+		for i := 0; i < v31; i++ {
+			v32 := common.NewPopulatedKVPair(r, easy)
+			this.Tags[i] = *v32
+		}
+	}
+	this.AppVersion = uint64(uint64(r.Uint32()))
+	if r.Intn(10) != 0 {
+		v20 := r.Intn(5)
+		this.Events = make([]Event, v20)
+		for i := 0; i < v20; i++ {
+			v21 := NewPopulatedEvent(r, easy)
+			this.Events[i] = *v21
 		}
 	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedTypes(r, 4)
+		this.XXX_unrecognized = randUnrecognizedTypes(r, 6)
 	}
 	return this
 }
 
 func NewPopulatedResponseCommit(r randyTypes, easy bool) *ResponseCommit {
 	this := &ResponseCommit{}
-	v30 := r.Intn(100)
-	this.Data = make([]byte, v30)
-	for i := 0; i < v30; i++ {
+	v33 := r.Intn(100)
+	this.Data = make([]byte, v33)
+	for i := 0; i < v33; i++ {
 		this.Data[i] = byte(r.Intn(256))
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -8153,9 +8487,9 @@ func NewPopulatedEvidenceParams(r randyTypes, easy bool) *EvidenceParams {
 
 func NewPopulatedValidatorParams(r randyTypes, easy bool) *ValidatorParams {
 	this := &ValidatorParams{}
-	v31 := r.Intn(10)
-	this.PubKeyTypes = make([]string, v31)
-	for i := 0; i < v31; i++ {
+	v34 := r.Intn(10)
+	this.PubKeyTypes = make([]string, v34)
+	for i := 0; i < v34; i++ {
 		this.PubKeyTypes[i] = string(randStringTypes(r))
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -8171,11 +8505,11 @@ func NewPopulatedLastCommitInfo(r randyTypes, easy bool) *LastCommitInfo {
 		this.Round *= -1
 	}
 	if r.Intn(10) != 0 {
-		v32 := r.Intn(5)
-		this.Votes = make([]VoteInfo, v32)
-		for i := 0; i < v32; i++ {
-			v33 := NewPopulatedVoteInfo(r, easy)
-			this.Votes[i] = *v33
+		v35 := r.Intn(5)
+		this.Votes = make([]VoteInfo, v35)
+		for i := 0; i < v35; i++ {
+			v36 := NewPopulatedVoteInfo(r, easy)
+			this.Votes[i] = *v36
 		}
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -8184,17 +8518,34 @@ func NewPopulatedLastCommitInfo(r randyTypes, easy bool) *LastCommitInfo {
 	return this
 }
 
-func NewPopulatedHeader(r randyTypes, easy bool) *Header {
-	this := &Header{}
-	v34 := NewPopulatedVersion(r, easy)
-	this.Version = *v34
-	this.ChainID = string(randStringTypes(r))
-	this.Height = int64(r.Int63())
-	if r.Intn(2) == 0 {
-		this.Height *= -1
+func NewPopulatedEvent(r randyTypes, easy bool) *Event {
+	this := &Event{}
+	this.Type = string(randStringTypes(r))
+	if r.Intn(10) != 0 {
+		v37 := r.Intn(5)
+		this.Attributes = make([]common.KVPair, v37)
+		for i := 0; i < v37; i++ {
+			v38 := common.NewPopulatedKVPair(r, easy)
+			this.Attributes[i] = *v38
+		}
 	}
-	v35 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
-	this.Time = *v35
+	if !easy && r.Intn(10) != 0 {
+		this.XXX_unrecognized = randUnrecognizedTypes(r, 3)
+	}
+	return this
+}
+
+func NewPopulatedHeader(r randyTypes, easy bool) *Header {
+	this := &Header{}
+	v39 := NewPopulatedVersion(r, easy)
+	this.Version = *v39
+	this.ChainID = string(randStringTypes(r))
+	this.Height = int64(r.Int63())
+	if r.Intn(2) == 0 {
+		this.Height *= -1
+	}
+	v40 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
+	this.Time = *v40
 	this.NumTxs = int64(r.Int63())
 	if r.Intn(2) == 0 {
 		this.NumTxs *= -1
@@ -8203,51 +8554,51 @@ func NewPopulatedHeader(r randyTypes, easy bool) *Header {
 	if r.Intn(2) == 0 {
 		this.TotalTxs *= -1
 	}
-	v36 := NewPopulatedBlockID(r, easy)
-	this.LastBlockId = *v36
-	v37 := r.Intn(100)
-	this.LastCommitHash = make([]byte, v37)
-	for i := 0; i < v37; i++ {
+	v41 := NewPopulatedBlockID(r, easy)
+	this.LastBlockId = *v41
+	v42 := r.Intn(100)
+	this.LastCommitHash = make([]byte, v42)
+	for i := 0; i < v42; i++ {
 		this.LastCommitHash[i] = byte(r.Intn(256))
 	}
-	v38 := r.Intn(100)
-	this.DataHash = make([]byte, v38)
-	for i := 0; i < v38; i++ {
+	v43 := r.Intn(100)
+	this.DataHash = make([]byte, v43)
+	for i := 0; i < v43; i++ {
 		this.DataHash[i] = byte(r.Intn(256))
 	}
-	v39 := r.Intn(100)
-	this.ValidatorsHash = make([]byte, v39)
-	for i := 0; i < v39; i++ {
+	v44 := r.Intn(100)
+	this.ValidatorsHash = make([]byte, v44)
+	for i := 0; i < v44; i++ {
 		this.ValidatorsHash[i] = byte(r.Intn(256))
 	}
-	v40 := r.Intn(100)
-	this.NextValidatorsHash = make([]byte, v40)
-	for i := 0; i < v40; i++ {
+	v45 := r.Intn(100)
+	this.NextValidatorsHash = make([]byte, v45)
+	for i := 0; i < v45; i++ {
 		this.NextValidatorsHash[i] = byte(r.Intn(256))
 	}
-	v41 := r.Intn(100)
-	this.ConsensusHash = make([]byte, v41)
-	for i := 0; i < v41; i++ {
+	v46 := r.Intn(100)
+	this.ConsensusHash = make([]byte, v46)
+	for i := 0; i < v46; i++ {
 		this.ConsensusHash[i] = byte(r.Intn(256))
 	}
-	v42 := r.Intn(100)
-	this.AppHash = make([]byte, v42)
-	for i := 0; i < v42; i++ {
+	v47 := r.Intn(100)
+	this.AppHash = make([]byte, v47)
+	for i := 0; i < v47; i++ {
 		this.AppHash[i] = byte(r.Intn(256))
 	}
-	v43 := r.Intn(100)
-	this.LastResultsHash = make([]byte, v43)
-	for i := 0; i < v43; i++ {
+	v48 := r.Intn(100)
+	this.LastResultsHash = make([]byte, v48)
+	for i := 0; i < v48; i++ {
 		this.LastResultsHash[i] = byte(r.Intn(256))
 	}
-	v44 := r.Intn(100)
-	this.EvidenceHash = make([]byte, v44)
-	for i := 0; i < v44; i++ {
+	v49 := r.Intn(100)
+	this.EvidenceHash = make([]byte, v49)
+	for i := 0; i < v49; i++ {
 		this.EvidenceHash[i] = byte(r.Intn(256))
 	}
-	v45 := r.Intn(100)
-	this.ProposerAddress = make([]byte, v45)
-	for i := 0; i < v45; i++ {
+	v50 := r.Intn(100)
+	this.ProposerAddress = make([]byte, v50)
+	for i := 0; i < v50; i++ {
 		this.ProposerAddress[i] = byte(r.Intn(256))
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -8268,13 +8619,13 @@ func NewPopulatedVersion(r randyTypes, easy bool) *Version {
 
 func NewPopulatedBlockID(r randyTypes, easy bool) *BlockID {
 	this := &BlockID{}
-	v46 := r.Intn(100)
-	this.Hash = make([]byte, v46)
-	for i := 0; i < v46; i++ {
+	v51 := r.Intn(100)
+	this.Hash = make([]byte, v51)
+	for i := 0; i < v51; i++ {
 		this.Hash[i] = byte(r.Intn(256))
 	}
-	v47 := NewPopulatedPartSetHeader(r, easy)
-	this.PartsHeader = *v47
+	v52 := NewPopulatedPartSetHeader(r, easy)
+	this.PartsHeader = *v52
 	if !easy && r.Intn(10) != 0 {
 		this.XXX_unrecognized = randUnrecognizedTypes(r, 3)
 	}
@@ -8287,9 +8638,9 @@ func NewPopulatedPartSetHeader(r randyTypes, easy bool) *PartSetHeader {
 	if r.Intn(2) == 0 {
 		this.Total *= -1
 	}
-	v48 := r.Intn(100)
-	this.Hash = make([]byte, v48)
-	for i := 0; i < v48; i++ {
+	v53 := r.Intn(100)
+	this.Hash = make([]byte, v53)
+	for i := 0; i < v53; i++ {
 		this.Hash[i] = byte(r.Intn(256))
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -8300,9 +8651,9 @@ func NewPopulatedPartSetHeader(r randyTypes, easy bool) *PartSetHeader {
 
 func NewPopulatedValidator(r randyTypes, easy bool) *Validator {
 	this := &Validator{}
-	v49 := r.Intn(100)
-	this.Address = make([]byte, v49)
-	for i := 0; i < v49; i++ {
+	v54 := r.Intn(100)
+	this.Address = make([]byte, v54)
+	for i := 0; i < v54; i++ {
 		this.Address[i] = byte(r.Intn(256))
 	}
 	this.Power = int64(r.Int63())
@@ -8317,8 +8668,8 @@ func NewPopulatedValidator(r randyTypes, easy bool) *Validator {
 
 func NewPopulatedValidatorUpdate(r randyTypes, easy bool) *ValidatorUpdate {
 	this := &ValidatorUpdate{}
-	v50 := NewPopulatedPubKey(r, easy)
-	this.PubKey = *v50
+	v55 := NewPopulatedPubKey(r, easy)
+	this.PubKey = *v55
 	this.Power = int64(r.Int63())
 	if r.Intn(2) == 0 {
 		this.Power *= -1
@@ -8331,8 +8682,8 @@ func NewPopulatedValidatorUpdate(r randyTypes, easy bool) *ValidatorUpdate {
 
 func NewPopulatedVoteInfo(r randyTypes, easy bool) *VoteInfo {
 	this := &VoteInfo{}
-	v51 := NewPopulatedValidator(r, easy)
-	this.Validator = *v51
+	v56 := NewPopulatedValidator(r, easy)
+	this.Validator = *v56
 	this.SignedLastBlock = bool(bool(r.Intn(2) == 0))
 	if !easy && r.Intn(10) != 0 {
 		this.XXX_unrecognized = randUnrecognizedTypes(r, 3)
@@ -8343,9 +8694,9 @@ func NewPopulatedVoteInfo(r randyTypes, easy bool) *VoteInfo {
 func NewPopulatedPubKey(r randyTypes, easy bool) *PubKey {
 	this := &PubKey{}
 	this.Type = string(randStringTypes(r))
-	v52 := r.Intn(100)
-	this.Data = make([]byte, v52)
-	for i := 0; i < v52; i++ {
+	v57 := r.Intn(100)
+	this.Data = make([]byte, v57)
+	for i := 0; i < v57; i++ {
 		this.Data[i] = byte(r.Intn(256))
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -8357,14 +8708,14 @@ func NewPopulatedPubKey(r randyTypes, easy bool) *PubKey {
 func NewPopulatedEvidence(r randyTypes, easy bool) *Evidence {
 	this := &Evidence{}
 	this.Type = string(randStringTypes(r))
-	v53 := NewPopulatedValidator(r, easy)
-	this.Validator = *v53
+	v58 := NewPopulatedValidator(r, easy)
+	this.Validator = *v58
 	this.Height = int64(r.Int63())
 	if r.Intn(2) == 0 {
 		this.Height *= -1
 	}
-	v54 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
-	this.Time = *v54
+	v59 := github_com_gogo_protobuf_types.NewPopulatedStdTime(r, easy)
+	this.Time = *v59
 	this.TotalVotingPower = int64(r.Int63())
 	if r.Intn(2) == 0 {
 		this.TotalVotingPower *= -1
@@ -8394,9 +8745,9 @@ func randUTF8RuneTypes(r randyTypes) rune {
 	return rune(ru + 61)
 }
 func randStringTypes(r randyTypes) string {
-	v55 := r.Intn(100)
-	tmps := make([]rune, v55)
-	for i := 0; i < v55; i++ {
+	v56 := r.Intn(100)
+	tmps := make([]rune, v56)
+	for i := 0; i < v56; i++ {
 		tmps[i] = randUTF8RuneTypes(r)
 	}
 	return string(tmps)
@@ -8418,11 +8769,11 @@ func randFieldTypes(dAtA []byte, r randyTypes, fieldNumber int, wire int) []byte
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateTypes(dAtA, uint64(key))
-		v56 := r.Int63()
+		v57 := r.Int63()
 		if r.Intn(2) == 0 {
-			v56 *= -1
+			v57 *= -1
 		}
-		dAtA = encodeVarintPopulateTypes(dAtA, uint64(v56))
+		dAtA = encodeVarintPopulateTypes(dAtA, uint64(v57))
 	case 1:
 		dAtA = encodeVarintPopulateTypes(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -9010,6 +9361,12 @@ func (m *ResponseBeginBlock) Size() (n int) {
 			n += 1 + l + sovTypes(uint64(l))
 		}
 	}
+	if len(m.Events) > 0 {
+		for _, e := range m.Events {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9050,6 +9407,19 @@ func (m *ResponseCheckTx) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	l = len(m.ReplacementKey)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if m.Priority != 0 {
+		n += 1 + sovTypes(uint64(m.Priority))
+	}
+	if len(m.Events) > 0 {
+		for _, e := range m.Events {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9090,6 +9460,12 @@ func (m *ResponseDeliverTx) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	if len(m.Events) > 0 {
+		for _, e := range m.Events {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9115,6 +9491,15 @@ func (m *ResponseEndBlock) Size() (n int) {
 			n += 1 + l + sovTypes(uint64(l))
 		}
 	}
+	if m.AppVersion != 0 {
+		n += 1 + sovTypes(uint64(m.AppVersion))
+	}
+	if len(m.Events) > 0 {
+		for _, e := range m.Events {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9215,6 +9600,25 @@ func (m *LastCommitInfo) Size() (n int) {
 	return n
 }
 
+func (m *Event) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	if len(m.Attributes) > 0 {
+		for _, e := range m.Attributes {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *Header) Size() (n int) {
 	var l int
 	_ = l
@@ -12413,6 +12817,37 @@ func (m *ResponseBeginBlock) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Events", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Events = append(m.Events, Event{})
+			if err := m.Events[len(m.Events)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -12670,6 +13105,87 @@ func (m *ResponseCheckTx) Unmarshal(dAtA []byte) error {
 			}
 			m.Codespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplacementKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ReplacementKey = append(m.ReplacementKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.ReplacementKey == nil {
+				m.ReplacementKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Priority", wireType)
+			}
+			m.Priority = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Priority |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Events", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Events = append(m.Events, Event{})
+			if err := m.Events[len(m.Events)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -12927,6 +13443,37 @@ func (m *ResponseDeliverTx) Unmarshal(dAtA []byte) error {
 			}
 			m.Codespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Events", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Events = append(m.Events, Event{})
+			if err := m.Events[len(m.Events)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -13073,6 +13620,56 @@ func (m *ResponseEndBlock) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppVersion", wireType)
+			}
+			m.AppVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AppVersion |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Events", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Events = append(m.Events, Event{})
+			if err := m.Events[len(m.Events)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -13667,6 +14264,117 @@ func (m *LastCommitInfo) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *Event) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Event: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Event: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attributes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attributes = append(m.Attributes, common.KVPair{})
+			if err := m.Attributes[len(m.Attributes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *Header) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -15177,144 +15885,149 @@ func init() {
 }
 
 var fileDescriptor_types_5b877df1938afe10 = []byte{
-	// 2214 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x58, 0xcb, 0x73, 0x1b, 0xc7,
-	0xd1, 0xe7, 0x82, 0x20, 0x81, 0x6d, 0x10, 0x0f, 0x8d, 0x28, 0x09, 0xc2, 0xe7, 0x8f, 0x54, 0xad,
-	0x12, 0x5b, 0x8c, 0x65, 0xd0, 0xa6, 0xa3, 0x14, 0x65, 0x39, 0xa9, 0x22, 0x24, 0xc5, 0x64, 0xd9,
-	0x49, 0x98, 0x95, 0xc4, 0x5c, 0x52, 0xb5, 0x35, 0xc0, 0x8e, 0x80, 0x2d, 0x02, 0xbb, 0xeb, 0xdd,
-	0x01, 0x0d, 0xea, 0x98, 0xb3, 0x0f, 0x3e, 0xe4, 0x8f, 0xc8, 0x35, 0x37, 0x1f, 0x73, 0x4a, 0xf9,
-	0x98, 0x43, 0xce, 0x4a, 0xc2, 0x54, 0x0e, 0xc9, 0x35, 0x95, 0xaa, 0x1c, 0x53, 0xd3, 0x33, 0xb3,
-	0x2f, 0x2e, 0x14, 0xcb, 0xc9, 0x29, 0x17, 0x60, 0xa6, 0x1f, 0xf3, 0xe8, 0xed, 0xee, 0x5f, 0xf7,
-	0xc0, 0x75, 0x3a, 0x1c, 0x79, 0xbb, 0xfc, 0x3c, 0x64, 0xb1, 0xfc, 0xed, 0x87, 0x51, 0xc0, 0x03,
-	0xb2, 0x86, 0x93, 0xde, 0x3b, 0x63, 0x8f, 0x4f, 0xe6, 0xc3, 0xfe, 0x28, 0x98, 0xed, 0x8e, 0x83,
-	0x71, 0xb0, 0x8b, 0xdc, 0xe1, 0xfc, 0x39, 0xce, 0x70, 0x82, 0x23, 0xa9, 0xd5, 0xdb, 0x1e, 0x07,
-	0xc1, 0x78, 0xca, 0x52, 0x29, 0xee, 0xcd, 0x58, 0xcc, 0xe9, 0x2c, 0x54, 0x02, 0xfb, 0x99, 0xf5,
-	0x38, 0xf3, 0x5d, 0x16, 0xcd, 0x3c, 0x9f, 0x67, 0x87, 0x53, 0x6f, 0x18, 0xef, 0x8e, 0x82, 0xd9,
-	0x2c, 0xf0, 0xb3, 0x07, 0xea, 0x3d, 0xf8, 0xb7, 0x9a, 0xa3, 0xe8, 0x3c, 0xe4, 0xc1, 0xee, 0x8c,
-	0x45, 0xa7, 0x53, 0xa6, 0xfe, 0xa4, 0xb2, 0xf5, 0xdb, 0x2a, 0xd4, 0x6c, 0xf6, 0xe9, 0x9c, 0xc5,
-	0x9c, 0xdc, 0x81, 0x2a, 0x1b, 0x4d, 0x82, 0x6e, 0xe5, 0x96, 0x71, 0xa7, 0xb1, 0x47, 0xfa, 0x72,
-	0x13, 0xc5, 0x7d, 0x3c, 0x9a, 0x04, 0x87, 0x2b, 0x36, 0x4a, 0x90, 0xb7, 0x61, 0xed, 0xf9, 0x74,
-	0x1e, 0x4f, 0xba, 0xab, 0x28, 0x7a, 0x35, 0x2f, 0xfa, 0x43, 0xc1, 0x3a, 0x5c, 0xb1, 0xa5, 0x8c,
-	0x58, 0xd6, 0xf3, 0x9f, 0x07, 0xdd, 0x6a, 0xd9, 0xb2, 0x47, 0xfe, 0x73, 0x5c, 0x56, 0x48, 0x90,
-	0x7d, 0x80, 0x98, 0x71, 0x27, 0x08, 0xb9, 0x17, 0xf8, 0xdd, 0x35, 0x94, 0xbf, 0x91, 0x97, 0x7f,
-	0xc2, 0xf8, 0x4f, 0x90, 0x7d, 0xb8, 0x62, 0x9b, 0xb1, 0x9e, 0x08, 0x4d, 0xcf, 0xf7, 0xb8, 0x33,
-	0x9a, 0x50, 0xcf, 0xef, 0xae, 0x97, 0x69, 0x1e, 0xf9, 0x1e, 0x7f, 0x28, 0xd8, 0x42, 0xd3, 0xd3,
-	0x13, 0x71, 0x95, 0x4f, 0xe7, 0x2c, 0x3a, 0xef, 0xd6, 0xca, 0xae, 0xf2, 0x53, 0xc1, 0x12, 0x57,
-	0x41, 0x19, 0xf2, 0x00, 0x1a, 0x43, 0x36, 0xf6, 0x7c, 0x67, 0x38, 0x0d, 0x46, 0xa7, 0xdd, 0x3a,
-	0xaa, 0x74, 0xf3, 0x2a, 0x03, 0x21, 0x30, 0x10, 0xfc, 0xc3, 0x15, 0x1b, 0x86, 0xc9, 0x8c, 0xec,
-	0x41, 0x7d, 0x34, 0x61, 0xa3, 0x53, 0x87, 0x2f, 0xba, 0x26, 0x6a, 0x5e, 0xcb, 0x6b, 0x3e, 0x14,
-	0xdc, 0xa7, 0x8b, 0xc3, 0x15, 0xbb, 0x36, 0x92, 0x43, 0x72, 0x0f, 0x4c, 0xe6, 0xbb, 0x6a, 0xbb,
-	0x06, 0x2a, 0x5d, 0x2f, 0x7c, 0x17, 0xdf, 0xd5, 0x9b, 0xd5, 0x99, 0x1a, 0x93, 0x3e, 0xac, 0x0b,
-	0x47, 0xf1, 0x78, 0x77, 0x03, 0x75, 0x36, 0x0b, 0x1b, 0x21, 0xef, 0x70, 0xc5, 0x56, 0x52, 0xc2,
-	0x7c, 0x2e, 0x9b, 0x7a, 0x67, 0x2c, 0x12, 0x87, 0xbb, 0x5a, 0x66, 0xbe, 0x47, 0x92, 0x8f, 0xc7,
-	0x33, 0x5d, 0x3d, 0x19, 0xd4, 0x60, 0xed, 0x8c, 0x4e, 0xe7, 0xcc, 0x7a, 0x0b, 0x1a, 0x19, 0x4f,
-	0x21, 0x5d, 0xa8, 0xcd, 0x58, 0x1c, 0xd3, 0x31, 0xeb, 0x1a, 0xb7, 0x8c, 0x3b, 0xa6, 0xad, 0xa7,
-	0x56, 0x0b, 0x36, 0xb2, 0x7e, 0x62, 0xcd, 0x12, 0x45, 0xe1, 0x0b, 0x42, 0xf1, 0x8c, 0x45, 0xb1,
-	0x70, 0x00, 0xa5, 0xa8, 0xa6, 0xe4, 0x36, 0x34, 0xd1, 0x0e, 0x8e, 0xe6, 0x0b, 0x3f, 0xad, 0xda,
-	0x1b, 0x48, 0x3c, 0x51, 0x42, 0xdb, 0xd0, 0x08, 0xf7, 0xc2, 0x44, 0x64, 0x15, 0x45, 0x20, 0xdc,
-	0x0b, 0x95, 0x80, 0xf5, 0x01, 0x74, 0x8a, 0xae, 0x44, 0x3a, 0xb0, 0x7a, 0xca, 0xce, 0xd5, 0x7e,
-	0x62, 0x48, 0x36, 0xd5, 0xb5, 0x70, 0x0f, 0xd3, 0x56, 0x77, 0xfc, 0xa2, 0x92, 0x28, 0x27, 0xde,
-	0x44, 0xf6, 0xa1, 0x2a, 0x62, 0x19, 0xb5, 0x1b, 0x7b, 0xbd, 0xbe, 0x0c, 0xf4, 0xbe, 0x0e, 0xf4,
-	0xfe, 0x53, 0x1d, 0xe8, 0x83, 0xfa, 0x57, 0x2f, 0xb7, 0x57, 0xbe, 0xf8, 0xc3, 0xb6, 0x61, 0xa3,
-	0x06, 0xb9, 0x29, 0x1c, 0x82, 0x7a, 0xbe, 0xe3, 0xb9, 0x6a, 0x9f, 0x1a, 0xce, 0x8f, 0x5c, 0x72,
-	0x00, 0x9d, 0x51, 0xe0, 0xc7, 0xcc, 0x8f, 0xe7, 0xb1, 0x13, 0xd2, 0x88, 0xce, 0x62, 0x15, 0x6b,
-	0xfa, 0xf3, 0x3f, 0xd4, 0xec, 0x63, 0xe4, 0xda, 0xed, 0x51, 0x9e, 0x40, 0x3e, 0x04, 0x38, 0xa3,
-	0x53, 0xcf, 0xa5, 0x3c, 0x88, 0xe2, 0x6e, 0xf5, 0xd6, 0x6a, 0x46, 0xf9, 0x44, 0x33, 0x9e, 0x85,
-	0x2e, 0xe5, 0x6c, 0x50, 0x15, 0x27, 0xb3, 0x33, 0xf2, 0xe4, 0x4d, 0x68, 0xd3, 0x30, 0x74, 0x62,
-	0x4e, 0x39, 0x73, 0x86, 0xe7, 0x9c, 0xc5, 0x18, 0x8f, 0x1b, 0x76, 0x93, 0x86, 0xe1, 0x13, 0x41,
-	0x1d, 0x08, 0xa2, 0xe5, 0x26, 0x5f, 0x13, 0x43, 0x85, 0x10, 0xa8, 0xba, 0x94, 0x53, 0xb4, 0xc6,
-	0x86, 0x8d, 0x63, 0x41, 0x0b, 0x29, 0x9f, 0xa8, 0x3b, 0xe2, 0x98, 0x5c, 0x87, 0xf5, 0x09, 0xf3,
-	0xc6, 0x13, 0x8e, 0xd7, 0x5a, 0xb5, 0xd5, 0x4c, 0x18, 0x3e, 0x8c, 0x82, 0x33, 0x86, 0xd9, 0xa2,
-	0x6e, 0xcb, 0x89, 0xf5, 0x17, 0x03, 0xae, 0x5c, 0x0a, 0x2f, 0xb1, 0xee, 0x84, 0xc6, 0x13, 0xbd,
-	0x97, 0x18, 0x93, 0xb7, 0xc5, 0xba, 0xd4, 0x65, 0x91, 0xca, 0x62, 0x4d, 0x75, 0xe3, 0x43, 0x24,
-	0xaa, 0x8b, 0x2a, 0x11, 0xf2, 0x18, 0x3a, 0x53, 0x1a, 0x73, 0x47, 0x46, 0x81, 0x83, 0x59, 0x6a,
-	0x35, 0x17, 0x99, 0x9f, 0x50, 0x1d, 0x2d, 0xc2, 0x39, 0x95, 0x7a, 0x6b, 0x9a, 0xa3, 0x92, 0x43,
-	0xd8, 0x1c, 0x9e, 0xbf, 0xa0, 0x3e, 0xf7, 0x7c, 0xe6, 0x5c, 0xb2, 0x79, 0x5b, 0x2d, 0xf5, 0xf8,
-	0xcc, 0x73, 0x99, 0x3f, 0xd2, 0xc6, 0xbe, 0x9a, 0xa8, 0x24, 0x1f, 0x23, 0xb6, 0x6e, 0x41, 0x2b,
-	0x9f, 0x0b, 0x48, 0x0b, 0x2a, 0x7c, 0xa1, 0x6e, 0x58, 0xe1, 0x0b, 0xcb, 0x4a, 0x3c, 0x30, 0x09,
-	0xc8, 0x4b, 0x32, 0x3b, 0xd0, 0x2e, 0x24, 0x87, 0x8c, 0xb9, 0x8d, 0xac, 0xb9, 0xad, 0x36, 0x34,
-	0x73, 0x39, 0xc1, 0xfa, 0x7c, 0x0d, 0xea, 0x36, 0x8b, 0x43, 0xe1, 0x4c, 0x64, 0x1f, 0x4c, 0xb6,
-	0x18, 0x31, 0x99, 0x8e, 0x8d, 0x42, 0xb2, 0x93, 0x32, 0x8f, 0x35, 0x5f, 0xa4, 0x85, 0x44, 0x98,
-	0xec, 0xe4, 0xa0, 0xe4, 0x6a, 0x51, 0x29, 0x8b, 0x25, 0x77, 0xf3, 0x58, 0xb2, 0x59, 0x90, 0x2d,
-	0x80, 0xc9, 0x4e, 0x0e, 0x4c, 0x8a, 0x0b, 0xe7, 0xd0, 0xe4, 0x7e, 0x09, 0x9a, 0x14, 0x8f, 0xbf,
-	0x04, 0x4e, 0xee, 0x97, 0xc0, 0x49, 0xf7, 0xd2, 0x5e, 0xa5, 0x78, 0x72, 0x37, 0x8f, 0x27, 0xc5,
-	0xeb, 0x14, 0x00, 0xe5, 0xc3, 0x32, 0x40, 0xb9, 0x59, 0xd0, 0x59, 0x8a, 0x28, 0xef, 0x5f, 0x42,
-	0x94, 0xeb, 0x05, 0xd5, 0x12, 0x48, 0xb9, 0x9f, 0xcb, 0xf5, 0x50, 0x7a, 0xb7, 0xf2, 0x64, 0x4f,
-	0xbe, 0x77, 0x19, 0x8d, 0x6e, 0x14, 0x3f, 0x6d, 0x19, 0x1c, 0xed, 0x16, 0xe0, 0xe8, 0x5a, 0xf1,
-	0x94, 0x05, 0x3c, 0x4a, 0x51, 0x65, 0x47, 0xc4, 0x7d, 0xc1, 0xd3, 0x44, 0x8e, 0x60, 0x51, 0x14,
-	0x44, 0x2a, 0x61, 0xcb, 0x89, 0x75, 0x47, 0x64, 0xa2, 0xd4, 0xbf, 0x5e, 0x81, 0x40, 0xe8, 0xf4,
-	0x19, 0xef, 0xb2, 0xbe, 0x34, 0x52, 0x5d, 0x8c, 0xe8, 0x6c, 0x16, 0x33, 0x55, 0x16, 0xcb, 0x00,
-	0x53, 0x25, 0x0f, 0x4c, 0xdb, 0xd0, 0x10, 0xb9, 0xb2, 0x80, 0x39, 0x34, 0xd4, 0x98, 0x43, 0xbe,
-	0x03, 0x57, 0x30, 0xcf, 0x48, 0xf8, 0x52, 0x81, 0x58, 0xc5, 0x40, 0x6c, 0x0b, 0x86, 0xb4, 0x98,
-	0x4c, 0x80, 0xef, 0xc0, 0xd5, 0x8c, 0xac, 0x58, 0x17, 0x73, 0x9c, 0x4c, 0xbe, 0x9d, 0x44, 0xfa,
-	0x20, 0x0c, 0x0f, 0x69, 0x3c, 0xb1, 0x7e, 0x94, 0x1a, 0x28, 0xc5, 0x33, 0x02, 0xd5, 0x51, 0xe0,
-	0xca, 0x7b, 0x37, 0x6d, 0x1c, 0x0b, 0x8c, 0x9b, 0x06, 0x63, 0x3c, 0x9c, 0x69, 0x8b, 0xa1, 0x90,
-	0x4a, 0x42, 0xc9, 0x94, 0x31, 0x63, 0xfd, 0xd2, 0x48, 0xd7, 0x4b, 0x21, 0xae, 0x0c, 0x8d, 0x8c,
-	0xff, 0x04, 0x8d, 0x2a, 0xaf, 0x87, 0x46, 0xd6, 0x85, 0x91, 0x7e, 0xb2, 0x04, 0x67, 0xbe, 0xd9,
-	0x15, 0x85, 0xf7, 0x78, 0xbe, 0xcb, 0x16, 0x68, 0xd2, 0x55, 0x5b, 0x4e, 0x74, 0x09, 0xb0, 0x8e,
-	0x66, 0xce, 0x97, 0x00, 0x35, 0xa4, 0xc9, 0x09, 0xb9, 0x8d, 0xf8, 0x14, 0x3c, 0x57, 0xa1, 0xda,
-	0xec, 0xab, 0x6a, 0xfa, 0x58, 0x10, 0x6d, 0xc9, 0xcb, 0x64, 0x5b, 0x33, 0x07, 0x6e, 0x6f, 0x80,
-	0x29, 0x0e, 0x1a, 0x87, 0x74, 0xc4, 0x30, 0xf2, 0x4c, 0x3b, 0x25, 0x58, 0xc7, 0x40, 0x2e, 0x47,
-	0x3c, 0xf9, 0x00, 0xaa, 0x9c, 0x8e, 0x85, 0xbd, 0x85, 0xc9, 0x5a, 0x7d, 0xd9, 0x00, 0xf4, 0x3f,
-	0x3e, 0x39, 0xa6, 0x5e, 0x34, 0xb8, 0x2e, 0x4c, 0xf5, 0xb7, 0x97, 0xdb, 0x2d, 0x21, 0x73, 0x37,
-	0x98, 0x79, 0x9c, 0xcd, 0x42, 0x7e, 0x6e, 0xa3, 0x8e, 0xf5, 0x77, 0x43, 0x20, 0x41, 0x2e, 0x13,
-	0x94, 0x1a, 0x4e, 0xbb, 0x7b, 0x25, 0x03, 0xda, 0x5f, 0xcf, 0x98, 0xff, 0x0f, 0x30, 0xa6, 0xb1,
-	0xf3, 0x19, 0xf5, 0x39, 0x73, 0x95, 0x45, 0xcd, 0x31, 0x8d, 0x7f, 0x86, 0x04, 0x51, 0xe1, 0x08,
-	0xf6, 0x3c, 0x66, 0x2e, 0x9a, 0x76, 0xd5, 0xae, 0x8d, 0x69, 0xfc, 0x2c, 0x66, 0x6e, 0x72, 0xaf,
-	0xda, 0xeb, 0xdf, 0x2b, 0x6f, 0xc7, 0x7a, 0xd1, 0x8e, 0xff, 0xc8, 0xf8, 0x70, 0x0a, 0x92, 0xff,
-	0xfb, 0xf7, 0xfe, 0xab, 0x21, 0x6a, 0x83, 0x7c, 0x1a, 0x26, 0x47, 0x70, 0x25, 0x89, 0x23, 0x67,
-	0x8e, 0xf1, 0xa5, 0x7d, 0xe9, 0xd5, 0xe1, 0xd7, 0x39, 0xcb, 0x93, 0x63, 0xf2, 0x63, 0xb8, 0x51,
-	0xc8, 0x02, 0xc9, 0x82, 0x95, 0x57, 0x26, 0x83, 0x6b, 0xf9, 0x64, 0xa0, 0xd7, 0xd3, 0x96, 0x58,
-	0xfd, 0x06, 0x9e, 0xfd, 0x2d, 0x51, 0x28, 0x65, 0xc1, 0xa3, 0xec, 0x5b, 0x5a, 0xbf, 0x36, 0xa0,
-	0x5d, 0x38, 0x0c, 0xb9, 0x07, 0x20, 0x53, 0x6b, 0xec, 0xbd, 0x60, 0x85, 0x2c, 0x86, 0x26, 0x7b,
-	0xe2, 0xbd, 0x60, 0xea, 0xe0, 0xe6, 0x50, 0x13, 0xc8, 0x7b, 0x50, 0x67, 0xaa, 0x80, 0x53, 0xb7,
-	0xbd, 0x56, 0xa8, 0xeb, 0x94, 0x4e, 0x22, 0x46, 0xbe, 0x0b, 0x66, 0x62, 0xc3, 0x42, 0xf1, 0x9e,
-	0x98, 0x5c, 0x6f, 0x94, 0x08, 0x5a, 0x1f, 0x41, 0xbb, 0x70, 0x0c, 0xf2, 0x7f, 0x60, 0xce, 0xe8,
-	0x42, 0x55, 0xe1, 0xb2, 0x7e, 0xab, 0xcf, 0xe8, 0x02, 0x0b, 0x70, 0x72, 0x03, 0x6a, 0x82, 0x39,
-	0xa6, 0xf2, 0x2b, 0xac, 0xda, 0xeb, 0x33, 0xba, 0xf8, 0x88, 0xc6, 0xd6, 0x0e, 0xb4, 0xf2, 0x47,
-	0xd3, 0xa2, 0x1a, 0x11, 0xa5, 0xe8, 0xc1, 0x98, 0x59, 0xf7, 0xa0, 0x5d, 0x38, 0x11, 0xb1, 0xa0,
-	0x19, 0xce, 0x87, 0xce, 0x29, 0x3b, 0x77, 0xf0, 0xc8, 0xe8, 0x33, 0xa6, 0xdd, 0x08, 0xe7, 0xc3,
-	0x8f, 0xd9, 0xf9, 0x53, 0x41, 0xb2, 0x9e, 0x40, 0x2b, 0x5f, 0x1f, 0x8b, 0x9c, 0x19, 0x05, 0x73,
-	0xdf, 0xc5, 0xf5, 0xd7, 0x6c, 0x39, 0x11, 0x2d, 0xf6, 0x59, 0x20, 0xdd, 0x24, 0x5b, 0x10, 0x9f,
-	0x04, 0x9c, 0x65, 0xaa, 0x6a, 0x29, 0x63, 0xfd, 0x62, 0x0d, 0xd6, 0x65, 0xb1, 0x4e, 0xfa, 0xf9,
-	0x56, 0x50, 0xf8, 0x88, 0xd2, 0x94, 0x54, 0xa5, 0x98, 0xe0, 0xf0, 0x9b, 0xc5, 0x7e, 0x6a, 0xd0,
-	0xb8, 0x78, 0xb9, 0x5d, 0x43, 0x0c, 0x3b, 0x7a, 0x94, 0x36, 0x57, 0xcb, 0x7a, 0x0f, 0xdd, 0xc9,
-	0x55, 0x5f, 0xbb, 0x93, 0xbb, 0x01, 0x35, 0x7f, 0x3e, 0x73, 0xf8, 0x22, 0x56, 0xb9, 0x60, 0xdd,
-	0x9f, 0xcf, 0x9e, 0x2e, 0xf0, 0xd3, 0xf1, 0x80, 0xd3, 0x29, 0xb2, 0x64, 0x26, 0xa8, 0x23, 0x41,
-	0x30, 0xf7, 0xa1, 0x99, 0x81, 0x7a, 0xcf, 0x55, 0x25, 0x63, 0x2b, 0xeb, 0x8d, 0x47, 0x8f, 0xd4,
-	0x2d, 0x1b, 0x09, 0xf4, 0x1f, 0xb9, 0xe4, 0x4e, 0xbe, 0x71, 0xc1, 0x0a, 0xa1, 0x8e, 0x8e, 0x9f,
-	0xe9, 0x4d, 0x44, 0x7d, 0x20, 0x0e, 0x20, 0x42, 0x41, 0x8a, 0x98, 0x28, 0x52, 0x17, 0x04, 0x64,
-	0xbe, 0x05, 0xed, 0x14, 0x64, 0xa5, 0x08, 0xc8, 0x55, 0x52, 0x32, 0x0a, 0xbe, 0x0b, 0x9b, 0x3e,
-	0x5b, 0x70, 0xa7, 0x28, 0xdd, 0x40, 0x69, 0x22, 0x78, 0x27, 0x79, 0x8d, 0x6f, 0x43, 0x2b, 0x4d,
-	0x16, 0x28, 0xbb, 0x21, 0xdb, 0xc7, 0x84, 0x8a, 0x62, 0x37, 0xa1, 0x9e, 0x94, 0x38, 0x4d, 0x14,
-	0xa8, 0x51, 0x59, 0xd9, 0x24, 0x45, 0x53, 0xc4, 0xe2, 0xf9, 0x94, 0xab, 0x45, 0x5a, 0x28, 0x83,
-	0x45, 0x93, 0x2d, 0xe9, 0x28, 0x7b, 0x1b, 0x9a, 0x3a, 0xec, 0xa4, 0x5c, 0x1b, 0xe5, 0x36, 0x34,
-	0x11, 0x85, 0x76, 0xa0, 0x13, 0x46, 0x41, 0x18, 0xc4, 0x2c, 0x72, 0xa8, 0xeb, 0x46, 0x2c, 0x8e,
-	0xbb, 0x1d, 0xb9, 0x9e, 0xa6, 0x1f, 0x48, 0xb2, 0xf5, 0x1e, 0xd4, 0x74, 0xed, 0xb6, 0x09, 0x6b,
-	0x68, 0x75, 0x74, 0xc1, 0xaa, 0x2d, 0x27, 0x02, 0x25, 0x0e, 0xc2, 0x50, 0xbd, 0x40, 0x88, 0xa1,
-	0xf5, 0x73, 0xa8, 0xa9, 0x0f, 0x56, 0xda, 0x97, 0x7e, 0x1f, 0x36, 0x42, 0x1a, 0x89, 0x6b, 0x64,
-	0xbb, 0x53, 0xdd, 0x1d, 0x1c, 0xd3, 0x88, 0x3f, 0x61, 0x3c, 0xd7, 0xa4, 0x36, 0x50, 0x5e, 0x92,
-	0xac, 0xfb, 0xd0, 0xcc, 0xc9, 0x88, 0x63, 0xa1, 0x1f, 0xe9, 0x48, 0xc3, 0x49, 0xb2, 0x73, 0x25,
-	0xdd, 0xd9, 0x7a, 0x00, 0x66, 0xf2, 0x6d, 0x44, 0x11, 0xab, 0xaf, 0x6e, 0x28, 0x73, 0xcb, 0x29,
-	0x36, 0xde, 0xc1, 0x67, 0x2c, 0x52, 0x31, 0x21, 0x27, 0xd6, 0xb3, 0x4c, 0x66, 0x90, 0x79, 0x9b,
-	0xdc, 0x85, 0x9a, 0xca, 0x0c, 0x2a, 0x2a, 0x75, 0x8b, 0x7d, 0x8c, 0xa9, 0x41, 0xb7, 0xd8, 0x32,
-	0x51, 0xa4, 0xcb, 0x56, 0xb2, 0xcb, 0x4e, 0xa1, 0xae, 0xa3, 0x3f, 0x9f, 0x26, 0xe5, 0x8a, 0x9d,
-	0x62, 0x9a, 0x54, 0x8b, 0xa6, 0x82, 0xc2, 0x3b, 0x62, 0x6f, 0xec, 0x33, 0xd7, 0x49, 0x43, 0x08,
-	0xf7, 0xa8, 0xdb, 0x6d, 0xc9, 0xf8, 0x44, 0xc7, 0x8b, 0xf5, 0x2e, 0xac, 0xcb, 0xb3, 0x09, 0xfb,
-	0x88, 0x95, 0x75, 0x5d, 0x2f, 0xc6, 0xa5, 0xc0, 0xf1, 0x7b, 0x03, 0xea, 0x3a, 0x79, 0x96, 0x2a,
-	0xe5, 0x0e, 0x5d, 0xf9, 0xba, 0x87, 0xfe, 0xef, 0x27, 0x9e, 0xbb, 0x40, 0x64, 0x7e, 0x39, 0x0b,
-	0xb8, 0xe7, 0x8f, 0x1d, 0x69, 0x6b, 0x99, 0x83, 0x3a, 0xc8, 0x39, 0x41, 0xc6, 0xb1, 0xa0, 0xef,
-	0x7d, 0xbe, 0x06, 0xed, 0x83, 0xc1, 0xc3, 0xa3, 0x83, 0x30, 0x9c, 0x7a, 0x23, 0x8a, 0xbd, 0xc2,
-	0x2e, 0x54, 0xb1, 0x5d, 0x2a, 0x79, 0xee, 0xed, 0x95, 0xf5, 0xed, 0x64, 0x0f, 0xd6, 0xb0, 0x6b,
-	0x22, 0x65, 0xaf, 0xbe, 0xbd, 0xd2, 0xf6, 0x5d, 0x6c, 0x22, 0xfb, 0xaa, 0xcb, 0x8f, 0xbf, 0xbd,
-	0xb2, 0x1e, 0x9e, 0xfc, 0x00, 0xcc, 0xb4, 0x9d, 0x59, 0xf6, 0x04, 0xdc, 0x5b, 0xda, 0xcd, 0x0b,
-	0xfd, 0xb4, 0xf4, 0x5b, 0xf6, 0x92, 0xd9, 0x5b, 0xda, 0xf6, 0x92, 0x7d, 0xa8, 0xe9, 0x82, 0xb9,
-	0xfc, 0x91, 0xb6, 0xb7, 0xa4, 0xd3, 0x16, 0xe6, 0x91, 0x1d, 0x4a, 0xd9, 0x4b, 0x72, 0xaf, 0xf4,
-	0x39, 0x80, 0xdc, 0x83, 0x75, 0x55, 0xc5, 0x94, 0x3e, 0xd4, 0xf6, 0xca, 0xfb, 0x65, 0x71, 0xc9,
-	0xb4, 0x47, 0x5b, 0xf6, 0xda, 0xdd, 0x5b, 0xfa, 0x6e, 0x41, 0x0e, 0x00, 0x32, 0x8d, 0xc6, 0xd2,
-	0x67, 0xec, 0xde, 0xf2, 0xf7, 0x08, 0xf2, 0x00, 0xea, 0xe9, 0x1b, 0x53, 0xf9, 0xc3, 0x74, 0x6f,
-	0xd9, 0x13, 0xc1, 0xe0, 0x8d, 0x7f, 0xfe, 0x69, 0xcb, 0xf8, 0xd5, 0xc5, 0x96, 0xf1, 0xe5, 0xc5,
-	0x96, 0xf1, 0xd5, 0xc5, 0x96, 0xf1, 0xbb, 0x8b, 0x2d, 0xe3, 0x8f, 0x17, 0x5b, 0xc6, 0x6f, 0xfe,
-	0xbc, 0x65, 0x0c, 0xd7, 0xd1, 0xfd, 0xdf, 0xff, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff, 0xfc, 0x8d,
-	0xcb, 0x04, 0x88, 0x19, 0x00, 0x00,
+	// 2303 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdd, 0x59, 0x4b, 0x73, 0x1b, 0xc7,
+	0x11, 0x36, 0x40, 0xbc, 0x76, 0x40, 0x3c, 0x34, 0xa4, 0x28, 0x18, 0x51, 0x44, 0xd7, 0xda, 0xb1,
+	0xc5, 0x44, 0x06, 0x6d, 0x3a, 0x4e, 0x49, 0x96, 0x93, 0x2a, 0x42, 0x52, 0x2c, 0x96, 0x15, 0x87,
+	0x59, 0x49, 0xcc, 0x25, 0x55, 0xa8, 0x05, 0x30, 0x02, 0xb7, 0x04, 0xec, 0x6e, 0x76, 0x17, 0x34,
+	0xe9, 0x63, 0xce, 0x3e, 0xe8, 0x90, 0x1f, 0x91, 0x6b, 0x0e, 0xa9, 0xf2, 0x31, 0xa7, 0x94, 0x2b,
+	0xa7, 0x1c, 0x72, 0x56, 0x62, 0xa5, 0x72, 0xc9, 0x2f, 0xf0, 0x31, 0xdd, 0x3d, 0x33, 0xfb, 0xe2,
+	0x42, 0xb1, 0x99, 0x9c, 0x72, 0xa0, 0x34, 0xd3, 0x8f, 0xd9, 0x99, 0x9e, 0xee, 0xfe, 0xba, 0x07,
+	0x6c, 0xcb, 0x1e, 0x4f, 0x9c, 0xdd, 0xe8, 0xcc, 0x17, 0xa1, 0xfc, 0x77, 0xe0, 0x07, 0x5e, 0xe4,
+	0xf1, 0x2a, 0x4d, 0xfa, 0x6f, 0xcf, 0x9c, 0xe8, 0x78, 0x39, 0x1e, 0x4c, 0xbc, 0xc5, 0xee, 0xcc,
+	0x9b, 0x79, 0xbb, 0xc4, 0x1d, 0x2f, 0x9f, 0xd0, 0x8c, 0x26, 0x34, 0x92, 0x5a, 0xfd, 0xed, 0x99,
+	0xe7, 0xcd, 0xe6, 0x22, 0x91, 0x8a, 0x9c, 0x85, 0x08, 0x23, 0x7b, 0xe1, 0x2b, 0x81, 0x9b, 0xa9,
+	0xf5, 0x22, 0xe1, 0x4e, 0x45, 0xb0, 0x70, 0xdc, 0x28, 0x3d, 0x9c, 0x3b, 0xe3, 0x70, 0x17, 0xd8,
+	0x0b, 0xcf, 0x4d, 0x6f, 0xa8, 0x7f, 0xfb, 0x3f, 0x6a, 0x4e, 0x82, 0x33, 0x1f, 0xb6, 0xb3, 0x10,
+	0xc1, 0x53, 0xd8, 0x82, 0xfc, 0x4f, 0x2a, 0x9b, 0x7f, 0xaa, 0xb0, 0xba, 0x25, 0x7e, 0xbd, 0x84,
+	0xbd, 0xf0, 0xeb, 0xac, 0x22, 0x26, 0xc7, 0x5e, 0xaf, 0xfc, 0x5a, 0xe9, 0x7a, 0x73, 0x8f, 0x0f,
+	0xe4, 0x47, 0x14, 0xf7, 0x1e, 0x70, 0xee, 0xbf, 0x62, 0x91, 0x04, 0xff, 0x01, 0xab, 0x3e, 0x99,
+	0x2f, 0xc3, 0xe3, 0xde, 0x1a, 0x89, 0x6e, 0x64, 0x45, 0x7f, 0x8a, 0x2c, 0x90, 0x95, 0x32, 0xb8,
+	0xac, 0xe3, 0x3e, 0xf1, 0x7a, 0x95, 0xa2, 0x65, 0x0f, 0x80, 0x83, 0xcb, 0xa2, 0x04, 0xbf, 0xc9,
+	0x58, 0x28, 0xa2, 0x91, 0xe7, 0x47, 0x8e, 0xe7, 0xf6, 0xaa, 0x24, 0x7f, 0x25, 0x2b, 0xff, 0x50,
+	0x44, 0x3f, 0x27, 0x36, 0x28, 0x19, 0xa1, 0x9e, 0xa0, 0xa6, 0xe3, 0x3a, 0xd1, 0x68, 0x72, 0x6c,
+	0x3b, 0x6e, 0xaf, 0x56, 0xa4, 0x79, 0x00, 0xfc, 0x3b, 0xc8, 0x46, 0x4d, 0x47, 0x4f, 0xf0, 0x28,
+	0xc0, 0x0e, 0xce, 0x7a, 0xf5, 0xa2, 0xa3, 0xfc, 0x02, 0x59, 0x78, 0x14, 0x92, 0xe1, 0xb7, 0x59,
+	0x73, 0x2c, 0x66, 0x8e, 0x3b, 0x1a, 0xcf, 0xbd, 0xc9, 0xd3, 0x5e, 0x83, 0x54, 0x7a, 0x59, 0x95,
+	0x21, 0x0a, 0x0c, 0x91, 0x0f, 0x7a, 0x6c, 0x1c, 0xcf, 0xf8, 0x1e, 0x6b, 0x4c, 0x8e, 0xc5, 0xe4,
+	0xe9, 0x28, 0x3a, 0xed, 0x19, 0xa4, 0x79, 0x39, 0xab, 0x79, 0x07, 0xb9, 0x8f, 0x4e, 0x41, 0xad,
+	0x3e, 0x91, 0x43, 0xfe, 0x3e, 0x33, 0xe0, 0x1a, 0xd5, 0xe7, 0x9a, 0xa4, 0xb4, 0x95, 0xbb, 0x17,
+	0x77, 0xaa, 0x3f, 0xd6, 0x10, 0x6a, 0xcc, 0x07, 0xac, 0x86, 0x8e, 0xe2, 0x44, 0xbd, 0x75, 0xd2,
+	0xd9, 0xcc, 0x7d, 0x88, 0x78, 0xa0, 0xa1, 0xa4, 0xd0, 0x7c, 0x53, 0x31, 0x77, 0x4e, 0x44, 0x80,
+	0x9b, 0xdb, 0x28, 0x32, 0xdf, 0x5d, 0xc9, 0xa7, 0xed, 0x19, 0x53, 0x3d, 0x19, 0xd6, 0x59, 0xf5,
+	0xc4, 0x9e, 0x2f, 0x85, 0xf9, 0x16, 0x6b, 0xa6, 0x3c, 0x85, 0xf7, 0x58, 0x1d, 0xfc, 0x3b, 0xb4,
+	0x67, 0xa2, 0x57, 0x82, 0xe5, 0x0c, 0x4b, 0x4f, 0xcd, 0x36, 0x5b, 0x4f, 0xfb, 0x89, 0xb9, 0x88,
+	0x15, 0xd1, 0x17, 0x50, 0x11, 0x56, 0x0e, 0xd1, 0x01, 0x94, 0xa2, 0x9a, 0xf2, 0xd7, 0x59, 0x8b,
+	0xec, 0x30, 0xd2, 0x7c, 0xf4, 0xd3, 0x8a, 0xb5, 0x4e, 0xc4, 0x23, 0x25, 0xb4, 0xcd, 0x9a, 0xfe,
+	0x9e, 0x1f, 0x8b, 0xac, 0x91, 0x08, 0x03, 0x92, 0x12, 0x30, 0x3f, 0x60, 0xdd, 0xbc, 0x2b, 0xf1,
+	0x2e, 0x5b, 0x7b, 0x2a, 0xce, 0xd4, 0xf7, 0x70, 0xc8, 0x37, 0xd5, 0xb1, 0xe8, 0x1b, 0x86, 0xa5,
+	0xce, 0xf8, 0xac, 0x1c, 0x2b, 0xc7, 0xde, 0x04, 0xb6, 0xab, 0x60, 0x2c, 0x93, 0x76, 0x73, 0xaf,
+	0x3f, 0x90, 0x81, 0x3e, 0xd0, 0x81, 0x3e, 0x78, 0xa4, 0x03, 0x7d, 0xd8, 0xf8, 0xf2, 0xf9, 0xf6,
+	0x2b, 0xcf, 0xfe, 0xb6, 0x5d, 0xb2, 0x48, 0x83, 0xbf, 0x8a, 0x0e, 0x01, 0x4b, 0x8c, 0x9c, 0xa9,
+	0xfa, 0x4e, 0x9d, 0xe6, 0x07, 0x53, 0xbe, 0xcf, 0xba, 0x13, 0xcf, 0x0d, 0x85, 0x1b, 0x2e, 0xc3,
+	0x91, 0x6f, 0x07, 0xf6, 0x22, 0x54, 0xb1, 0xa6, 0xaf, 0xff, 0x8e, 0x66, 0x1f, 0x12, 0xd7, 0xea,
+	0x4c, 0xb2, 0x04, 0xfe, 0x21, 0x63, 0xb0, 0x6b, 0x67, 0x6a, 0x47, 0x5e, 0x10, 0x42, 0xf0, 0xad,
+	0xa5, 0x94, 0x8f, 0x34, 0xe3, 0xb1, 0x0f, 0xff, 0x89, 0x61, 0x05, 0x77, 0x66, 0xa5, 0xe4, 0xf9,
+	0x9b, 0xac, 0x63, 0xfb, 0xfe, 0x08, 0x36, 0x1e, 0x89, 0xd1, 0xf8, 0x2c, 0x12, 0x21, 0xc5, 0xe3,
+	0xba, 0xd5, 0x02, 0xf2, 0x43, 0xa4, 0x0e, 0x91, 0x68, 0x4e, 0xe3, 0xdb, 0xa4, 0x50, 0xe1, 0x9c,
+	0x55, 0x60, 0x05, 0x9b, 0xac, 0xb1, 0x6e, 0xd1, 0x18, 0x69, 0xbe, 0x1d, 0x1d, 0xab, 0x33, 0xd2,
+	0x98, 0x6f, 0xb1, 0xda, 0xb1, 0x70, 0x66, 0xc7, 0x11, 0x1d, 0x6b, 0xcd, 0x52, 0x33, 0x34, 0x3c,
+	0x58, 0xee, 0x44, 0x50, 0xb6, 0x68, 0x58, 0x72, 0x62, 0xfe, 0xb3, 0xc4, 0x2e, 0x9d, 0x0b, 0x2f,
+	0x5c, 0xf7, 0xd8, 0x86, 0x24, 0xa4, 0xbe, 0x85, 0x63, 0x08, 0x67, 0x58, 0xc9, 0x86, 0xc4, 0xa7,
+	0xb2, 0x58, 0x4b, 0x9d, 0xf8, 0x3e, 0x11, 0xd5, 0x41, 0x95, 0x08, 0xbf, 0xc7, 0xba, 0x73, 0x3b,
+	0x84, 0xac, 0x41, 0x51, 0x30, 0xa2, 0x2c, 0xb5, 0x96, 0x89, 0xcc, 0x07, 0xb6, 0x8e, 0x16, 0x74,
+	0x4e, 0xa5, 0xde, 0x9e, 0x67, 0xa8, 0xfc, 0x3e, 0xdb, 0x1c, 0x9f, 0x7d, 0x66, 0xbb, 0x91, 0xe3,
+	0x8a, 0xd1, 0x39, 0x9b, 0x77, 0xd4, 0x52, 0xf7, 0x4e, 0x9c, 0xa9, 0x70, 0x27, 0xda, 0xd8, 0x1b,
+	0xb1, 0x4a, 0x7c, 0x19, 0xa1, 0xf9, 0x1a, 0x6b, 0x67, 0x73, 0x01, 0x6f, 0xb3, 0x32, 0x44, 0xa4,
+	0x3c, 0x21, 0x8c, 0x4c, 0x33, 0xf6, 0xc0, 0x38, 0x20, 0xcf, 0xc9, 0xec, 0xb0, 0x4e, 0x2e, 0x39,
+	0xa4, 0xcc, 0x5d, 0x4a, 0x9b, 0xdb, 0xec, 0xb0, 0x56, 0x26, 0x27, 0x98, 0x9f, 0x57, 0x59, 0xc3,
+	0x12, 0xa1, 0x8f, 0xce, 0x04, 0xae, 0x6d, 0x88, 0xd3, 0x89, 0x90, 0xe9, 0xb8, 0x94, 0x4b, 0x76,
+	0x52, 0xe6, 0x9e, 0xe6, 0x63, 0x5a, 0x88, 0x85, 0xf9, 0x4e, 0x06, 0x4a, 0x36, 0xf2, 0x4a, 0x69,
+	0x2c, 0xb9, 0x91, 0xc5, 0x92, 0xcd, 0x9c, 0x6c, 0x0e, 0x4c, 0x76, 0x32, 0x60, 0x92, 0x5f, 0x38,
+	0x83, 0x26, 0xb7, 0x0a, 0xd0, 0x24, 0xbf, 0xfd, 0x15, 0x70, 0x72, 0xab, 0x00, 0x4e, 0x7a, 0xe7,
+	0xbe, 0x55, 0x88, 0x27, 0x37, 0xb2, 0x78, 0x92, 0x3f, 0x4e, 0x0e, 0x50, 0x3e, 0x2c, 0x02, 0x94,
+	0x57, 0x73, 0x3a, 0x2b, 0x11, 0xe5, 0xbd, 0x73, 0x88, 0xb2, 0x95, 0x53, 0x2d, 0x80, 0x94, 0x5b,
+	0x99, 0x5c, 0xcf, 0x0a, 0xcf, 0x56, 0x9c, 0xec, 0xf9, 0x8f, 0xce, 0xa3, 0xd1, 0x95, 0xfc, 0xd5,
+	0x16, 0xc1, 0xd1, 0x6e, 0x0e, 0x8e, 0x2e, 0xe7, 0x77, 0x99, 0xc3, 0xa3, 0x04, 0x55, 0x76, 0x30,
+	0xee, 0x73, 0x9e, 0x86, 0x39, 0x42, 0x04, 0x81, 0x17, 0xa8, 0x84, 0x2d, 0x27, 0xe6, 0x75, 0xcc,
+	0x44, 0x89, 0x7f, 0xbd, 0x04, 0x81, 0xc8, 0xe9, 0x53, 0xde, 0x65, 0x7e, 0x51, 0x4a, 0x74, 0x29,
+	0xa2, 0xd3, 0x59, 0xcc, 0x50, 0x59, 0x2c, 0x05, 0x4c, 0xe5, 0x2c, 0x30, 0x01, 0xe6, 0x60, 0xae,
+	0xcc, 0x61, 0x0e, 0x90, 0x34, 0x28, 0x7d, 0x9f, 0x5d, 0xa2, 0x3c, 0x23, 0xe1, 0x4b, 0x05, 0x62,
+	0x85, 0x02, 0xb1, 0x83, 0x0c, 0x69, 0x31, 0x99, 0x00, 0xdf, 0x66, 0x1b, 0x29, 0x59, 0x5c, 0x97,
+	0x72, 0x9c, 0x4c, 0xbe, 0xdd, 0x58, 0x7a, 0xdf, 0xf7, 0xef, 0x03, 0xdd, 0xfc, 0x59, 0x62, 0xa0,
+	0x04, 0xcf, 0x60, 0xfb, 0x13, 0x6f, 0x2a, 0xcf, 0xdd, 0xb2, 0x68, 0x8c, 0x18, 0x37, 0xf7, 0x66,
+	0xb4, 0x39, 0xc0, 0x38, 0x18, 0xa2, 0x54, 0x1c, 0x4a, 0x86, 0x8c, 0x19, 0xf3, 0xb7, 0xa5, 0x64,
+	0xbd, 0x04, 0xe2, 0x8a, 0xd0, 0xa8, 0xf4, 0xdf, 0xa0, 0x51, 0xf9, 0xdb, 0xa1, 0x91, 0xf9, 0xa2,
+	0x94, 0x5c, 0x59, 0x8c, 0x33, 0x17, 0x3b, 0x22, 0x7a, 0x8f, 0x03, 0x95, 0xf1, 0x29, 0x99, 0x74,
+	0xcd, 0x92, 0x13, 0x5d, 0x02, 0xd4, 0xc8, 0xcc, 0xd9, 0x12, 0xa0, 0x4e, 0x34, 0x39, 0x81, 0x22,
+	0x04, 0x21, 0xc9, 0x7b, 0xa2, 0x42, 0xb5, 0x35, 0x50, 0xd5, 0xf4, 0x21, 0x12, 0x2d, 0xc9, 0x4b,
+	0x65, 0x5b, 0x23, 0x03, 0x6e, 0x57, 0x99, 0x81, 0x1b, 0x0d, 0x7d, 0x7b, 0x22, 0x28, 0xf2, 0x0c,
+	0x2b, 0x21, 0x98, 0xcf, 0x4a, 0x8c, 0x9f, 0x0f, 0x79, 0xfe, 0x01, 0xd4, 0x17, 0xf6, 0x0c, 0x0d,
+	0x8e, 0x36, 0x6b, 0x0f, 0x64, 0x07, 0x30, 0xf8, 0xf8, 0xe8, 0xd0, 0x76, 0x82, 0xe1, 0x16, 0xda,
+	0xea, 0x5f, 0xcf, 0xb7, 0xdb, 0x28, 0x73, 0xc3, 0x83, 0xe0, 0x11, 0x0b, 0x3f, 0x3a, 0xb3, 0x48,
+	0x87, 0xff, 0x84, 0xd5, 0xc4, 0x89, 0x70, 0x23, 0x6d, 0xf1, 0xf5, 0x18, 0x8b, 0x80, 0x38, 0xec,
+	0x29, 0xdd, 0xae, 0x94, 0x49, 0x69, 0x2b, 0x2d, 0xf3, 0xeb, 0x32, 0x42, 0x49, 0x26, 0x95, 0x14,
+	0x5a, 0x5e, 0xc7, 0x4b, 0x39, 0x85, 0xfa, 0xdf, 0xec, 0x36, 0xbe, 0xcb, 0xd8, 0xcc, 0x0e, 0x47,
+	0x9f, 0x02, 0x14, 0x8a, 0xa9, 0xba, 0x12, 0x03, 0x28, 0xbf, 0x24, 0x02, 0x96, 0x48, 0xc8, 0x5e,
+	0x86, 0xc0, 0xac, 0x11, 0xb3, 0x0e, 0xf3, 0xc7, 0x30, 0x8d, 0xed, 0x52, 0xbf, 0x80, 0x5d, 0x32,
+	0x17, 0xd1, 0xc8, 0x5d, 0x04, 0x7f, 0x8b, 0x75, 0x02, 0xe1, 0xcf, 0x61, 0xb8, 0x00, 0x2b, 0x8c,
+	0xd0, 0x2f, 0x0c, 0x3a, 0x58, 0x3b, 0x45, 0xfe, 0x18, 0x5c, 0xa4, 0xcf, 0x1a, 0x7e, 0xe0, 0x78,
+	0x81, 0x13, 0x9d, 0xd1, 0x75, 0xae, 0x59, 0xf1, 0x3c, 0x65, 0xfa, 0xe6, 0x85, 0x4c, 0xff, 0x87,
+	0x72, 0x12, 0x89, 0x09, 0xd4, 0xff, 0xff, 0x1b, 0x3f, 0xb1, 0x9b, 0x71, 0x21, 0xbb, 0xfd, 0x99,
+	0x6a, 0xf4, 0x2c, 0x18, 0xf1, 0x03, 0x76, 0x29, 0xce, 0x26, 0xa3, 0x25, 0x65, 0x19, 0x1d, 0x50,
+	0x2f, 0x4f, 0x42, 0xdd, 0x93, 0x2c, 0x39, 0xe4, 0x9f, 0xb0, 0x2b, 0xb9, 0x5c, 0x18, 0x2f, 0x58,
+	0x7e, 0x69, 0x4a, 0xbc, 0x9c, 0x4d, 0x89, 0x7a, 0x3d, 0x6d, 0xc9, 0xb5, 0x0b, 0x58, 0x32, 0x07,
+	0x3c, 0x95, 0x73, 0xc0, 0x93, 0x18, 0xb3, 0x7a, 0x21, 0x63, 0xbe, 0x81, 0xf5, 0x68, 0x1a, 0xa3,
+	0x8b, 0x9c, 0xcd, 0xfc, 0x7d, 0x89, 0x75, 0x72, 0xa7, 0x85, 0xc6, 0x95, 0x49, 0x04, 0x0b, 0x9d,
+	0xcf, 0x44, 0x0e, 0x2c, 0xe8, 0x4e, 0x1e, 0x02, 0x5d, 0x59, 0xc6, 0x18, 0x6b, 0x02, 0x7f, 0x97,
+	0x35, 0x84, 0xaa, 0x93, 0x95, 0x39, 0x2f, 0xe7, 0xca, 0x67, 0xa5, 0x13, 0x8b, 0xf1, 0x1f, 0x32,
+	0x23, 0xbe, 0xa4, 0x5c, 0x8f, 0x14, 0xdf, 0xa9, 0xfe, 0x50, 0x2c, 0x68, 0x7e, 0xc4, 0x3a, 0xb9,
+	0x6d, 0xf0, 0xef, 0x30, 0x63, 0x61, 0x9f, 0xaa, 0x66, 0x47, 0x96, 0xc9, 0x0d, 0x20, 0x50, 0x9f,
+	0xc3, 0xaf, 0x40, 0x35, 0x01, 0x4c, 0xf0, 0x7f, 0xda, 0x17, 0xe4, 0x74, 0x98, 0x7e, 0x64, 0x87,
+	0x50, 0xa1, 0xb4, 0xb3, 0x5b, 0xd3, 0xa2, 0xba, 0xf0, 0x90, 0xa2, 0xfb, 0x50, 0x77, 0xbc, 0xcf,
+	0x3a, 0xb9, 0x1d, 0x71, 0x93, 0xb5, 0xfc, 0xe5, 0x18, 0x53, 0xcc, 0x88, 0xb6, 0x4c, 0x4e, 0x69,
+	0x58, 0x4d, 0x20, 0x42, 0x82, 0x79, 0x84, 0x24, 0xf3, 0x21, 0x6b, 0x67, 0xdb, 0x10, 0x84, 0xa6,
+	0xc0, 0x5b, 0xba, 0x53, 0x5a, 0xbf, 0x6a, 0xc9, 0x09, 0xbe, 0x64, 0x9c, 0x78, 0xd2, 0x0f, 0xd3,
+	0x7d, 0xc7, 0x11, 0xd0, 0x52, 0xcd, 0x8b, 0x94, 0x31, 0x1d, 0x56, 0x25, 0x27, 0xc0, 0x0b, 0x45,
+	0x39, 0x5d, 0xea, 0xe0, 0x98, 0x3f, 0x60, 0xcc, 0x8e, 0xa2, 0xc0, 0x19, 0x2f, 0x93, 0xe5, 0xf2,
+	0x9e, 0x79, 0x55, 0x39, 0xcf, 0x66, 0x22, 0x99, 0x72, 0xa0, 0x94, 0xbe, 0xf9, 0x9b, 0x2a, 0xab,
+	0xc9, 0xf6, 0x8b, 0x0f, 0xb2, 0xcd, 0x3d, 0xae, 0xaa, 0x36, 0x29, 0xa9, 0x6a, 0x8f, 0x71, 0x65,
+	0xf5, 0x66, 0xbe, 0x43, 0x1e, 0x36, 0x5f, 0x3c, 0xdf, 0xae, 0x53, 0x55, 0x72, 0x70, 0x37, 0x69,
+	0x97, 0x57, 0x75, 0x93, 0xba, 0x37, 0xaf, 0x7c, 0xeb, 0xde, 0x1c, 0x2e, 0xd1, 0x5d, 0x2e, 0xa0,
+	0x42, 0x0e, 0x55, 0x5e, 0xac, 0xc1, 0xf4, 0xd1, 0x29, 0x79, 0x49, 0xe4, 0x45, 0xf6, 0x9c, 0x58,
+	0x32, 0x2b, 0x36, 0x88, 0x80, 0xcc, 0x9b, 0xac, 0x95, 0x2a, 0xde, 0x60, 0xd3, 0xf5, 0xcc, 0x29,
+	0xc9, 0xe3, 0x0e, 0xee, 0xaa, 0x53, 0x36, 0xe3, 0x62, 0x0e, 0x4e, 0x70, 0x3d, 0xdb, 0x8a, 0x52,
+	0xcd, 0xd7, 0x90, 0xa0, 0x93, 0x74, 0x9b, 0x58, 0xf1, 0xe1, 0x06, 0x30, 0xea, 0xa4, 0x88, 0xc4,
+	0xa5, 0x06, 0x12, 0x88, 0x09, 0xd0, 0x95, 0x94, 0x4d, 0x52, 0x84, 0xc9, 0x55, 0x12, 0x32, 0x09,
+	0xbe, 0xc3, 0x36, 0x5d, 0x71, 0x1a, 0x8d, 0xf2, 0xd2, 0x4d, 0x92, 0xe6, 0xc8, 0x3b, 0xca, 0x6a,
+	0x7c, 0x8f, 0xb5, 0x93, 0xc4, 0x47, 0xb2, 0xeb, 0xf2, 0x41, 0x20, 0xa6, 0x92, 0x18, 0x80, 0x46,
+	0x5c, 0xb4, 0xb6, 0x48, 0xa0, 0x6e, 0xcb, 0x5a, 0x35, 0x2e, 0x83, 0x03, 0x11, 0x2e, 0xe7, 0x91,
+	0x5a, 0xa4, 0x4d, 0x32, 0x54, 0x06, 0x5b, 0x92, 0x4e, 0xb2, 0xaf, 0xb3, 0x96, 0x8e, 0x70, 0x29,
+	0xd7, 0x21, 0xb9, 0x75, 0x4d, 0x24, 0xa1, 0x1d, 0xd6, 0x85, 0xab, 0xf4, 0xbd, 0x10, 0x7a, 0x19,
+	0x7b, 0x3a, 0x85, 0x75, 0xc3, 0x5e, 0x57, 0xae, 0xa7, 0xe9, 0xfb, 0x92, 0x6c, 0xbe, 0xcb, 0xea,
+	0x3a, 0x29, 0x42, 0xf4, 0x90, 0xd5, 0xc9, 0x05, 0x2b, 0x96, 0x9c, 0x20, 0x62, 0x42, 0x4d, 0xad,
+	0xde, 0x94, 0x70, 0x68, 0xfe, 0x8a, 0xd5, 0xd5, 0x85, 0x15, 0xbe, 0x34, 0xfc, 0x98, 0xad, 0x43,
+	0xfa, 0xc7, 0x63, 0xa4, 0xdf, 0x1b, 0x74, 0xbf, 0x07, 0xf1, 0x8d, 0x0f, 0x4c, 0x99, 0x67, 0x87,
+	0x26, 0xc9, 0x4b, 0x92, 0x79, 0x8b, 0xb5, 0x32, 0x32, 0xb8, 0x2d, 0xf2, 0x23, 0x1d, 0xd4, 0x34,
+	0x89, 0xbf, 0x5c, 0x4e, 0xbe, 0x6c, 0xde, 0x66, 0x46, 0x7c, 0x37, 0xd8, 0x96, 0xe8, 0xa3, 0x97,
+	0x94, 0xb9, 0xe5, 0x94, 0x9e, 0x52, 0xbc, 0x4f, 0x45, 0xa0, 0x62, 0x42, 0x4e, 0xcc, 0xc7, 0xa9,
+	0x24, 0x24, 0x31, 0x08, 0x5a, 0xd6, 0xba, 0x4a, 0x42, 0x2a, 0x2a, 0xf5, 0xa3, 0xc9, 0x21, 0x65,
+	0x21, 0xfd, 0x68, 0x22, 0x73, 0x52, 0xb2, 0x6c, 0x39, 0xbd, 0xec, 0x9c, 0x35, 0x74, 0xa2, 0xc9,
+	0x66, 0x64, 0xb9, 0x62, 0x37, 0x9f, 0x91, 0xd5, 0xa2, 0x89, 0x20, 0x7a, 0x47, 0xe8, 0xcc, 0x5c,
+	0x31, 0x1d, 0x25, 0x21, 0x44, 0xdf, 0x68, 0x58, 0x1d, 0xc9, 0x78, 0xa0, 0xe3, 0xc5, 0x7c, 0x87,
+	0xd5, 0xe4, 0xde, 0x0a, 0xd3, 0x57, 0x11, 0x46, 0xfd, 0xb5, 0xc4, 0x1a, 0x3a, 0x4f, 0x17, 0x2a,
+	0x65, 0x36, 0x5d, 0xfe, 0xa6, 0x9b, 0xfe, 0xdf, 0x27, 0x9e, 0x1b, 0x8c, 0xcb, 0xfc, 0x02, 0x79,
+	0xda, 0x71, 0x67, 0x23, 0x69, 0x6b, 0x99, 0x83, 0xba, 0xc4, 0x39, 0x22, 0xc6, 0x21, 0xd2, 0xf7,
+	0x3e, 0xaf, 0xb2, 0xce, 0xfe, 0xf0, 0xce, 0x01, 0xf8, 0xeb, 0xdc, 0x99, 0xd8, 0xd4, 0xfd, 0xed,
+	0xb2, 0x0a, 0x35, 0xc0, 0x05, 0x0f, 0xf8, 0xfd, 0xa2, 0x97, 0x18, 0xbe, 0xc7, 0xaa, 0xd4, 0x07,
+	0xf3, 0xa2, 0x77, 0xfc, 0x7e, 0xe1, 0x83, 0x0c, 0x7e, 0x44, 0x76, 0xca, 0xe7, 0x9f, 0xf3, 0xfb,
+	0x45, 0xaf, 0x32, 0x50, 0x8a, 0x18, 0x49, 0x83, 0xba, 0xea, 0x51, 0xbf, 0xbf, 0xf2, 0x7d, 0x06,
+	0xf5, 0x93, 0x32, 0x78, 0xd5, 0xdb, 0x74, 0x7f, 0xe5, 0x43, 0x06, 0xdc, 0x48, 0x5d, 0x77, 0x30,
+	0xc5, 0xcf, 0xee, 0xfd, 0x15, 0x6f, 0x27, 0x68, 0x1e, 0xd9, 0x73, 0x16, 0xfd, 0x36, 0xd0, 0x2f,
+	0x7c, 0xe0, 0x81, 0xf2, 0xa7, 0xa6, 0x0a, 0xa6, 0xc2, 0xa7, 0xf7, 0x7e, 0xf1, 0x0b, 0x08, 0x1e,
+	0x32, 0xe9, 0xba, 0x57, 0xfd, 0x7e, 0xd1, 0x5f, 0xf9, 0x12, 0x05, 0x8d, 0x3a, 0x4b, 0x75, 0x8e,
+	0x2b, 0x7f, 0x98, 0xe8, 0xaf, 0x7e, 0x61, 0xe2, 0xb7, 0x21, 0x4e, 0xe2, 0x57, 0xc3, 0xe2, 0x9f,
+	0x1a, 0xfa, 0xab, 0x1e, 0x7d, 0x86, 0x57, 0xbf, 0xfe, 0xea, 0x5a, 0xe9, 0x77, 0x2f, 0xae, 0x95,
+	0xbe, 0x80, 0xbf, 0x2f, 0xe1, 0xef, 0x2f, 0xf0, 0xf7, 0x77, 0xf8, 0xfb, 0xe3, 0x3f, 0xae, 0x95,
+	0xc6, 0x35, 0x72, 0xff, 0xf7, 0xfe, 0x0d, 0x37, 0x30, 0xfb, 0xbc, 0x5a, 0x1b, 0x00, 0x00,
 }
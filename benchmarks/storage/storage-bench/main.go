@@ -0,0 +1,126 @@
+/*
+	storage-bench runs the storage benchmarks against the db backends, and
+	outputs the results as JSON, for comparison.
+
+	Usage:
+			storage-bench [-backends goleveldb,memdb] [-run BlockStore] > results.json
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/benchmarks/storage"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+type result struct {
+	Name        string `json:"name"`
+	Backend     string `json:"backend,omitempty"`
+	N           int    `json:"n"`
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp int64  `json:"allocs_per_op"`
+	BytesPerOp  int64  `json:"bytes_per_op"`
+}
+
+type report struct {
+	Time      time.Time `json:"time"`
+	GoVersion string    `json:"go_version"`
+	GOOS      string    `json:"goos"`
+	GOARCH    string    `json:"goarch"`
+	NumCPU    int       `json:"num_cpu"`
+	Results   []result  `json:"results"`
+}
+
+func main() {
+	var backendsFlag, runFlag string
+	flag.StringVar(&backendsFlag, "backends", "", "Comma-separated db backends to run the benchmarks against (default all)")
+	flag.StringVar(&runFlag, "run", "", "Run only the benchmarks matching the regular expression")
+	flag.Parse()
+
+	backends := storage.Backends()
+	if backendsFlag != "" {
+		backends = nil
+		for _, backend := range strings.Split(backendsFlag, ",") {
+			if !isRegistered(dbm.DBBackendType(backend)) {
+				fmt.Fprintf(os.Stderr, "unknown db backend %s\n", backend)
+				os.Exit(1)
+			}
+			backends = append(backends, dbm.DBBackendType(backend))
+		}
+	}
+	run, err := regexp.Compile(runFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -run: %v\n", err)
+		os.Exit(1)
+	}
+
+	rep := report{
+		Time:      time.Now().UTC(),
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		NumCPU:    runtime.NumCPU(),
+		Results:   []result{},
+	}
+	for _, bm := range storage.DBBenchmarks {
+		if !run.MatchString(bm.Name) {
+			continue
+		}
+		for _, backend := range backends {
+			bm, backend := bm, backend
+			fmt.Fprintf(os.Stderr, "%s/%s\n", bm.Name, backend)
+			res := testing.Benchmark(func(b *testing.B) {
+				storage.RunDBBenchmark(b, bm, backend)
+			})
+			rep.Results = append(rep.Results, makeResult(bm.Name, string(backend), res))
+		}
+	}
+	for _, bm := range storage.WALBenchmarks {
+		if !run.MatchString(bm.Name) {
+			continue
+		}
+		bm := bm
+		fmt.Fprintf(os.Stderr, "%s\n", bm.Name)
+		res := testing.Benchmark(func(b *testing.B) {
+			storage.RunWALBenchmark(b, bm)
+		})
+		rep.Results = append(rep.Results, makeResult(bm.Name, "", res))
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rep); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func makeResult(name, backend string, res testing.BenchmarkResult) result {
+	return result{
+		Name:        name,
+		Backend:     backend,
+		N:           res.N,
+		NsPerOp:     res.NsPerOp(),
+		AllocsPerOp: res.AllocsPerOp(),
+		BytesPerOp:  res.AllocedBytesPerOp(),
+	}
+}
+
+func isRegistered(backend dbm.DBBackendType) bool {
+	for _, b := range dbm.Backends() {
+		if b == backend {
+			return true
+		}
+	}
+	return false
+}
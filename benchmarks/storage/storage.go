@@ -0,0 +1,283 @@
+// Package storage provides benchmarks of the storage of a node: the block
+// store, the state store and the tx indexer, run against each db backend, and
+// the consensus WAL.
+//
+// The data is generated from a fixed seed, so that the results of different
+// runs can be compared.
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/blockchain"
+	"github.com/tendermint/tendermint/consensus"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/state/txindex"
+	"github.com/tendermint/tendermint/state/txindex/kv"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	chainID = "storage-bench"
+
+	// the blocks have blockTxs txs of txSize bytes.
+	blockTxs = 100
+	txSize   = 250
+
+	// the state has numValidators validators, which all sign the commits.
+	numValidators = 100
+)
+
+var genesisTime = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// DBBenchmark is a benchmark of a store, run against a db.
+type DBBenchmark struct {
+	Name string
+	Run  func(b *testing.B, db dbm.DB)
+}
+
+// DBBenchmarks are run against each db backend.
+var DBBenchmarks = []DBBenchmark{
+	{"BlockStoreSaveBlock", benchmarkBlockStoreSaveBlock},
+	{"StateStoreSaveState", benchmarkStateStoreSaveState},
+	{"StateStoreLoadState", benchmarkStateStoreLoadState},
+	{"StateStoreLoadValidators", benchmarkStateStoreLoadValidators},
+	{"TxIndexAddBatch", benchmarkTxIndexAddBatch},
+}
+
+// WALBenchmark is a benchmark of the consensus WAL, written to walFile.
+type WALBenchmark struct {
+	Name string
+	Run  func(b *testing.B, walFile string)
+}
+
+// WALBenchmarks write the round steps to the WAL, fsyncing them as the
+// consensus does for the messages of the node itself (every message), or
+// never, or every 100 messages.
+var WALBenchmarks = []WALBenchmark{
+	{"WALWrite", func(b *testing.B, walFile string) { benchmarkWALWrite(b, walFile, 0) }},
+	{"WALWriteSync", func(b *testing.B, walFile string) { benchmarkWALWrite(b, walFile, 1) }},
+	{"WALWriteSyncEvery100", func(b *testing.B, walFile string) { benchmarkWALWrite(b, walFile, 100) }},
+}
+
+// Backends returns the db backends the benchmarks are run against: the
+// registered ones, but the legacy leveldb.
+func Backends() []dbm.DBBackendType {
+	var backends []dbm.DBBackendType
+	for _, backend := range dbm.Backends() {
+		if backend != dbm.LevelDBBackend {
+			backends = append(backends, backend)
+		}
+	}
+	return backends
+}
+
+// RunDBBenchmark runs the benchmark against a new db of the backend, in a
+// temporary directory.
+func RunDBBenchmark(b *testing.B, bm DBBenchmark, backend dbm.DBBackendType) {
+	dir, err := ioutil.TempDir("", "storage_bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db := dbm.NewDB(bm.Name, backend, dir)
+	defer db.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	bm.Run(b, db)
+}
+
+// RunWALBenchmark runs the benchmark against a new WAL, in a temporary
+// directory.
+func RunWALBenchmark(b *testing.B, bm WALBenchmark) {
+	dir, err := ioutil.TempDir("", "storage_bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	bm.Run(b, filepath.Join(dir, "wal"))
+}
+
+//-----------------------------------------------------------------------------
+
+func benchmarkBlockStoreSaveBlock(b *testing.B, db dbm.DB) {
+	r := rand.New(rand.NewSource(0))
+	store := blockchain.NewBlockStore(db)
+	lastCommit := makeCommit(r, 0)
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		height := int64(i + 1)
+		block := types.MakeBlock(height, makeTxs(r), lastCommit, nil)
+		block.ChainID = chainID
+		parts := block.MakePartSet(types.BlockPartSizeBytes)
+		seenCommit := makeCommit(r, height)
+		b.StartTimer()
+
+		store.SaveBlock(block, parts, seenCommit)
+		lastCommit = seenCommit
+	}
+}
+
+func benchmarkStateStoreSaveState(b *testing.B, db dbm.DB) {
+	state := makeState(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		state.LastBlockHeight = int64(i + 1)
+		sm.SaveState(db, state)
+	}
+}
+
+func benchmarkStateStoreLoadState(b *testing.B, db dbm.DB) {
+	sm.SaveState(db, makeState(b))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sm.LoadState(db)
+	}
+}
+
+func benchmarkStateStoreLoadValidators(b *testing.B, db dbm.DB) {
+	// the validators didn't change since the first height, so they are loaded
+	// from there.
+	const heights = 100
+	state := makeState(b)
+	for h := int64(1); h <= heights; h++ {
+		state.LastBlockHeight = h
+		sm.SaveState(db, state)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := sm.LoadValidators(db, heights); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkTxIndexAddBatch(b *testing.B, db dbm.DB) {
+	r := rand.New(rand.NewSource(0))
+	indexer := kv.NewTxIndex(db, kv.IndexAllTags())
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		batch := txindex.NewBatch(blockTxs)
+		for j, tx := range makeTxs(r) {
+			batch.Add(&types.TxResult{
+				Height: int64(i + 1),
+				Index:  uint32(j),
+				Tx:     tx,
+				Result: abci.ResponseDeliverTx{
+					Code: abci.CodeTypeOK,
+					Events: []abci.Event{
+						{Type: "account", Attributes: []cmn.KVPair{
+							{Key: []byte("owner"), Value: []byte(fmt.Sprintf("owner%d", r.Intn(1000)))},
+							{Key: []byte("number"), Value: []byte(fmt.Sprintf("%d", r.Intn(1000)))},
+						}},
+					},
+				},
+			})
+		}
+		b.StartTimer()
+
+		if err := indexer.AddBatch(batch); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchmarkWALWrite writes the round steps to the WAL, fsyncing every
+// syncEvery messages, never if 0.
+func benchmarkWALWrite(b *testing.B, walFile string, syncEvery int) {
+	wal, err := consensus.NewWAL(walFile)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := wal.Start(); err != nil {
+		b.Fatal(err)
+	}
+	defer func() {
+		wal.Stop()
+		wal.Wait()
+	}()
+
+	steps := []string{"RoundStepPropose", "RoundStepPrevote", "RoundStepPrecommit", "RoundStepCommit"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		msg := types.EventDataRoundState{
+			Height: int64(i/len(steps) + 1),
+			Step:   steps[i%len(steps)],
+		}
+		if syncEvery > 0 && (i+1)%syncEvery == 0 {
+			wal.WriteSync(msg)
+		} else {
+			wal.Write(msg)
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func makeTxs(r *rand.Rand) types.Txs {
+	txs := make(types.Txs, blockTxs)
+	for i := range txs {
+		txs[i] = make(types.Tx, txSize)
+		r.Read(txs[i])
+	}
+	return txs
+}
+
+func makeCommit(r *rand.Rand, height int64) *types.Commit {
+	if height == 0 {
+		return &types.Commit{}
+	}
+	blockID := types.BlockID{Hash: make([]byte, 32)}
+	r.Read(blockID.Hash)
+	precommits := make([]*types.CommitSig, numValidators)
+	for i := range precommits {
+		precommits[i] = &types.CommitSig{
+			Type:             types.PrecommitType,
+			Height:           height,
+			BlockID:          blockID,
+			Timestamp:        genesisTime.Add(time.Duration(height) * time.Second),
+			ValidatorAddress: make([]byte, 20),
+			ValidatorIndex:   i,
+			Signature:        make([]byte, 64),
+		}
+		r.Read(precommits[i].ValidatorAddress)
+		r.Read(precommits[i].Signature)
+	}
+	return types.NewCommit(blockID, precommits)
+}
+
+func makeState(b *testing.B) sm.State {
+	validators := make([]types.GenesisValidator, numValidators)
+	for i := range validators {
+		pubKey := ed25519.GenPrivKeyFromSecret([]byte(fmt.Sprintf("validator%d", i))).PubKey()
+		validators[i] = types.GenesisValidator{
+			Address: pubKey.Address(),
+			PubKey:  pubKey,
+			Power:   10,
+		}
+	}
+	state, err := sm.MakeGenesisState(&types.GenesisDoc{
+		GenesisTime: genesisTime,
+		ChainID:     chainID,
+		Validators:  validators,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return state
+}
@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+)
+
+func BenchmarkDB(b *testing.B) {
+	for _, bm := range DBBenchmarks {
+		for _, backend := range Backends() {
+			bm, backend := bm, backend
+			b.Run(fmt.Sprintf("%s/%s", bm.Name, backend), func(b *testing.B) {
+				RunDBBenchmark(b, bm, backend)
+			})
+		}
+	}
+}
+
+func BenchmarkWAL(b *testing.B) {
+	for _, bm := range WALBenchmarks {
+		bm := bm
+		b.Run(bm.Name, func(b *testing.B) {
+			RunWALBenchmark(b, bm)
+		})
+	}
+}
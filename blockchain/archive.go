@@ -0,0 +1,228 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+/*
+A block archive is a directory holding a range of consecutive blocks:
+
+ - manifest.json:               the ArchiveManifest, as JSON
+ - block-<height>.bin:          one file per block, with the height
+                                zero-padded to 20 digits
+
+Each block file holds the amino encoding (length-prefixed) of the block
+along with the commit for it, i.e. the LastCommit of the next block, or the
+commit seen for it by the exporting node if it was the last in its store.
+
+The manifest lists the SHA256 checksum of each block file, which is checked
+before the block is decoded on import.
+*/
+
+const archiveManifestFile = "manifest.json"
+
+// ArchiveManifest describes the content of a block archive.
+type ArchiveManifest struct {
+	ChainID string `json:"chain_id"`
+	From    int64  `json:"from"`
+	To      int64  `json:"to"`
+
+	// Checksums holds the SHA256 checksum of the file of each block, from
+	// height From to height To.
+	Checksums []cmn.HexBytes `json:"checksums"`
+}
+
+// ValidateBasic performs basic validation.
+func (m *ArchiveManifest) ValidateBasic() error {
+	if m.ChainID == "" {
+		return fmt.Errorf("missing chain ID")
+	}
+	if m.From < 1 || m.From > m.To {
+		return fmt.Errorf("invalid height range [%d, %d]", m.From, m.To)
+	}
+	if int64(len(m.Checksums)) != m.To-m.From+1 {
+		return fmt.Errorf("expected %d checksums, got %d", m.To-m.From+1, len(m.Checksums))
+	}
+	return nil
+}
+
+// checksum returns the checksum of the file of the block at the given height.
+func (m *ArchiveManifest) checksum(height int64) []byte {
+	return m.Checksums[height-m.From]
+}
+
+// archivedBlock is the content of a single block file of an archive: a block
+// along with the commit for it.
+type archivedBlock struct {
+	Block  *types.Block  `json:"block"`
+	Commit *types.Commit `json:"commit"`
+}
+
+// ArchiveBlockFile returns the path of the file holding the block at the
+// given height in the archive directory dir.
+func ArchiveBlockFile(dir string, height int64) string {
+	return filepath.Join(dir, fmt.Sprintf("block-%020d.bin", height))
+}
+
+// LoadArchiveManifest loads and validates the manifest of the archive
+// directory dir.
+func LoadArchiveManifest(dir string) (*ArchiveManifest, error) {
+	bz, err := ioutil.ReadFile(filepath.Join(dir, archiveManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m := new(ArchiveManifest)
+	if err := json.Unmarshal(bz, m); err != nil {
+		return nil, fmt.Errorf("error reading archive manifest: %v", err)
+	}
+	if err := m.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid archive manifest: %v", err)
+	}
+	return m, nil
+}
+
+// ExportBlocks writes the blocks from height from to height to (inclusive)
+// found in the store to the archive directory dir.
+func ExportBlocks(store *BlockStore, dir string, from, to int64) error {
+	if from < 1 || from > to {
+		return fmt.Errorf("invalid height range [%d, %d]", from, to)
+	}
+	if to > store.Height() {
+		return fmt.Errorf("height %d is above the store height %d", to, store.Height())
+	}
+	if err := cmn.EnsureDir(dir, 0700); err != nil {
+		return err
+	}
+
+	manifest := ArchiveManifest{
+		From:      from,
+		To:        to,
+		Checksums: make([]cmn.HexBytes, 0, to-from+1),
+	}
+	for height := from; height <= to; height++ {
+		block := store.LoadBlock(height)
+		if block == nil {
+			return fmt.Errorf("block at height %d not found", height)
+		}
+		var commit *types.Commit
+		if height < store.Height() {
+			commit = store.LoadBlockCommit(height)
+		} else {
+			commit = store.LoadSeenCommit(height)
+		}
+		if commit == nil {
+			return fmt.Errorf("commit for block at height %d not found", height)
+		}
+
+		bz, err := cdc.MarshalBinaryLengthPrefixed(archivedBlock{block, commit})
+		if err != nil {
+			return err
+		}
+		if err := cmn.WriteFileAtomic(ArchiveBlockFile(dir, height), bz, 0600); err != nil {
+			return err
+		}
+		checksum := sha256.Sum256(bz)
+		manifest.ChainID = block.ChainID
+		manifest.Checksums = append(manifest.Checksums, checksum[:])
+	}
+
+	// The manifest is written last, so an interrupted export is not mistaken
+	// for a complete archive.
+	bz, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return cmn.WriteFileAtomic(filepath.Join(dir, archiveManifestFile), bz, 0600)
+}
+
+// ImportBlocks reads the blocks following the last block of the given state
+// from the archive directory dir, up to height to, or to the end of the
+// archive if to is 0. Each block is checked against the manifest, verified
+// against its commit using the validators of the state, saved to the store
+// and applied using blockExec, as in fast sync. The state after the last
+// imported block is returned.
+func ImportBlocks(
+	store *BlockStore,
+	blockExec *sm.BlockExecutor,
+	state sm.State,
+	dir string,
+	to int64,
+	logger log.Logger,
+) (sm.State, error) {
+	if store.Height() != state.LastBlockHeight {
+		return state, fmt.Errorf("store height %d does not match state height %d",
+			store.Height(), state.LastBlockHeight)
+	}
+
+	manifest, err := LoadArchiveManifest(dir)
+	if err != nil {
+		return state, err
+	}
+	if manifest.ChainID != state.ChainID {
+		return state, fmt.Errorf("archive is for chain %q, expected %q", manifest.ChainID, state.ChainID)
+	}
+	if manifest.From > state.LastBlockHeight+1 {
+		return state, fmt.Errorf("archive starts at height %d, expected at most %d",
+			manifest.From, state.LastBlockHeight+1)
+	}
+	if to == 0 || to > manifest.To {
+		to = manifest.To
+	}
+
+	for height := state.LastBlockHeight + 1; height <= to; height++ {
+		ab, err := readArchivedBlock(dir, height, manifest.checksum(height))
+		if err != nil {
+			return state, err
+		}
+
+		block, commit := ab.Block, ab.Commit
+		parts := block.MakePartSet(types.BlockPartSizeBytes)
+		blockID := types.BlockID{Hash: block.Hash(), PartsHeader: parts.Header()}
+		err = state.Validators.VerifyCommit(state.ChainID, state.SignDomain, blockID, height, commit)
+		if err != nil {
+			return state, fmt.Errorf("invalid commit for block at height %d: %v", height, err)
+		}
+
+		store.SaveBlock(block, parts, commit)
+		state, err = blockExec.ApplyBlock(state, blockID, block)
+		if err != nil {
+			return state, fmt.Errorf("failed to apply block at height %d: %v", height, err)
+		}
+
+		if height%100 == 0 {
+			logger.Info("Imported blocks", "height", height)
+		}
+	}
+	return state, nil
+}
+
+func readArchivedBlock(dir string, height int64, checksum []byte) (*archivedBlock, error) {
+	bz, err := ioutil.ReadFile(ArchiveBlockFile(dir, height))
+	if err != nil {
+		return nil, err
+	}
+	if sum := sha256.Sum256(bz); !bytes.Equal(sum[:], checksum) {
+		return nil, fmt.Errorf("checksum mismatch for block at height %d", height)
+	}
+	ab := new(archivedBlock)
+	if err := cdc.UnmarshalBinaryLengthPrefixed(bz, ab); err != nil {
+		return nil, fmt.Errorf("error reading block at height %d: %v", height, err)
+	}
+	if ab.Block == nil || ab.Commit == nil {
+		return nil, fmt.Errorf("block or commit missing at height %d", height)
+	}
+	if ab.Block.Height != height {
+		return nil, fmt.Errorf("expected block at height %d, got %d", height, ab.Block.Height)
+	}
+	return ab, nil
+}
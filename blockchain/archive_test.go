@@ -0,0 +1,138 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/proxy"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+func newImportTarget(t *testing.T, genDoc *types.GenesisDoc) (*BlockStore, *sm.BlockExecutor, sm.State) {
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(&testApp{}))
+	require.NoError(t, proxyApp.Start())
+
+	stateDB := dbm.NewMemDB()
+	state, err := sm.LoadStateFromDBOrGenesisDoc(stateDB, genDoc)
+	require.NoError(t, err)
+	blockExec := sm.NewBlockExecutor(stateDB, log.TestingLogger(), proxyApp.Consensus(),
+		sm.MockMempool{}, sm.MockEvidencePool{})
+	return NewBlockStore(dbm.NewMemDB()), blockExec, state
+}
+
+func TestExportImportBlocks(t *testing.T) {
+	config = cfg.ResetTestRoot("blockchain_archive_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	dir, err := ioutil.TempDir("", "block_archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := newBlockchainReactor(log.TestingLogger(), genDoc, privVals, 20)
+	srcStore := src.reactor.store
+	require.NoError(t, ExportBlocks(srcStore, dir, 1, srcStore.Height()-1))
+
+	store, blockExec, state := newImportTarget(t, genDoc)
+	state, err = ImportBlocks(store, blockExec, state, dir, 10, log.TestingLogger())
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, state.LastBlockHeight)
+
+	// Resume up to the end of the archive.
+	state, err = ImportBlocks(store, blockExec, state, dir, 0, log.TestingLogger())
+	require.NoError(t, err)
+
+	assert.EqualValues(t, srcStore.Height()-1, state.LastBlockHeight)
+	assert.Equal(t, state.LastBlockHeight, store.Height())
+	for height := int64(1); height <= store.Height(); height++ {
+		assert.Equal(t, srcStore.LoadBlockMeta(height).BlockID, store.LoadBlockMeta(height).BlockID)
+	}
+}
+
+func TestImportBlocksInvalidCommit(t *testing.T) {
+	config = cfg.ResetTestRoot("blockchain_archive_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	dir, err := ioutil.TempDir("", "block_archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := newBlockchainReactor(log.TestingLogger(), genDoc, privVals, 5)
+	srcStore := src.reactor.store
+	require.NoError(t, ExportBlocks(srcStore, dir, 1, 4))
+
+	// Replace the commit for the third block with the one for the second,
+	// updating the manifest accordingly.
+	manifest, err := LoadArchiveManifest(dir)
+	require.NoError(t, err)
+	ab, err := readArchivedBlock(dir, 3, manifest.checksum(3))
+	require.NoError(t, err)
+	ab.Commit = srcStore.LoadBlockCommit(2)
+	bz := cdc.MustMarshalBinaryLengthPrefixed(ab)
+	require.NoError(t, cmn.WriteFileAtomic(ArchiveBlockFile(dir, 3), bz, 0600))
+	checksum := sha256.Sum256(bz)
+	manifest.Checksums[2] = checksum[:]
+	mbz, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, cmn.WriteFileAtomic(filepath.Join(dir, archiveManifestFile), mbz, 0600))
+
+	store, blockExec, state := newImportTarget(t, genDoc)
+	state, err = ImportBlocks(store, blockExec, state, dir, 0, log.TestingLogger())
+	assert.Error(t, err)
+	assert.EqualValues(t, 2, state.LastBlockHeight)
+	assert.EqualValues(t, 2, store.Height())
+}
+
+func TestImportBlocksChecksumMismatch(t *testing.T) {
+	config = cfg.ResetTestRoot("blockchain_archive_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	dir, err := ioutil.TempDir("", "block_archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := newBlockchainReactor(log.TestingLogger(), genDoc, privVals, 5)
+	require.NoError(t, ExportBlocks(src.reactor.store, dir, 1, 4))
+
+	// Overwrite the file of the third block with the one of the second.
+	bz2, err := ioutil.ReadFile(ArchiveBlockFile(dir, 2))
+	require.NoError(t, err)
+	require.NoError(t, cmn.WriteFileAtomic(ArchiveBlockFile(dir, 3), bz2, 0600))
+
+	store, blockExec, state := newImportTarget(t, genDoc)
+	state, err = ImportBlocks(store, blockExec, state, dir, 0, log.TestingLogger())
+	assert.Error(t, err)
+	assert.EqualValues(t, 2, state.LastBlockHeight)
+}
+
+func TestImportBlocksArchiveGap(t *testing.T) {
+	config = cfg.ResetTestRoot("blockchain_archive_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	dir, err := ioutil.TempDir("", "block_archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	src := newBlockchainReactor(log.TestingLogger(), genDoc, privVals, 5)
+	require.NoError(t, ExportBlocks(src.reactor.store, dir, 2, 4))
+
+	store, blockExec, state := newImportTarget(t, genDoc)
+	_, err = ImportBlocks(store, blockExec, state, dir, 0, log.TestingLogger())
+	assert.Error(t, err)
+	assert.EqualValues(t, 0, store.Height())
+}
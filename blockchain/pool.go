@@ -194,6 +194,27 @@ func (pool *BlockPool) PeekTwoBlocks() (first *types.Block, second *types.Block)
 	return
 }
 
+// PeekBlocks returns up to n consecutive blocks starting at pool.height,
+// stopping at the first one not yet received.
+func (pool *BlockPool) PeekBlocks(n int) []*types.Block {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	blocks := make([]*types.Block, 0, n)
+	for height := pool.height; len(blocks) < n; height++ {
+		r := pool.requesters[height]
+		if r == nil {
+			break
+		}
+		block := r.getBlock()
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
 // Pop the first block at pool.height
 // It must have been validated by 'second'.Commit from PeekTwoBlocks().
 func (pool *BlockPool) PopRequest() {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"runtime"
 	"time"
 
 	amino "github.com/tendermint/go-amino"
@@ -29,6 +30,9 @@ const (
 	// check if we should switch to consensus reactor
 	switchToConsensusIntervalSeconds = 1
 
+	// number of blocks ahead of the one being applied to verify in parallel
+	maxVerifyAheadBlocks = 20
+
 	// NOTE: keep up to date with bcBlockResponseMessage
 	bcBlockResponseMessagePrefixSize   = 4
 	bcBlockResponseMessageFieldKeySize = 1
@@ -62,6 +66,7 @@ type BlockchainReactor struct {
 	blockExec *sm.BlockExecutor
 	store     *BlockStore
 	pool      *BlockPool
+	verifier  *blockVerifier
 	fastSync  bool
 
 	requestsCh <-chan BlockRequest
@@ -93,6 +98,7 @@ func NewBlockchainReactor(state sm.State, blockExec *sm.BlockExecutor, store *Bl
 		blockExec:    blockExec,
 		store:        store,
 		pool:         pool,
+		verifier:     newBlockVerifier(state.ChainID, state.SignDomain, runtime.NumCPU()),
 		fastSync:     fastSync,
 		requestsCh:   requestsCh,
 		errorsCh:     errorsCh,
@@ -101,6 +107,28 @@ func NewBlockchainReactor(state sm.State, blockExec *sm.BlockExecutor, store *Bl
 	return bcR
 }
 
+// FastSyncStatus is the status of the fast sync of the blocks.
+type FastSyncStatus struct {
+	Enabled       bool  `json:"enabled"`
+	Syncing       bool  `json:"syncing"`
+	Height        int64 `json:"height"` // height of the next block to sync
+	MaxPeerHeight int64 `json:"max_peer_height"`
+	NumPending    int32 `json:"num_pending"`
+	NumRequesters int   `json:"num_requesters"`
+}
+
+// FastSyncStatus returns the status of the fast sync. It's no longer syncing
+// once it switched to the consensus.
+func (bcR *BlockchainReactor) FastSyncStatus() FastSyncStatus {
+	status := FastSyncStatus{
+		Enabled:       bcR.fastSync,
+		Syncing:       bcR.pool.IsRunning(),
+		MaxPeerHeight: bcR.pool.MaxPeerHeight(),
+	}
+	status.Height, status.NumPending, status.NumRequesters = bcR.pool.GetStatus()
+	return status
+}
+
 // SetLogger implements cmn.Service by setting the logger on reactor and pool.
 func (bcR *BlockchainReactor) SetLogger(l log.Logger) {
 	bcR.BaseService.Logger = l
@@ -288,6 +316,13 @@ FOR_LOOP:
 			// coupling them as it's written here.  TODO uncouple from request
 			// routine.
 
+			// Verify the commits of the next blocks in the background.
+			bcR.verifier.prefetch(
+				bcR.pool.PeekBlocks(maxVerifyAheadBlocks+1),
+				state.Validators,
+				state.NextValidators,
+			)
+
 			// See if there are any blocks to sync.
 			first, second := bcR.pool.PeekTwoBlocks()
 			//bcR.Logger.Info("TrySync peeked", "first", first, "second", second)
@@ -299,15 +334,25 @@ FOR_LOOP:
 				didProcessCh <- struct{}{}
 			}
 
-			firstParts := first.MakePartSet(types.BlockPartSizeBytes)
-			firstPartsHeader := firstParts.Header()
-			firstID := types.BlockID{Hash: first.Hash(), PartsHeader: firstPartsHeader}
-			// Finally, verify the first block using the second's commit
-			// NOTE: we can probably make this more efficient, but note that calling
-			// first.Hash() doesn't verify the tx contents, so MakePartSet() is
-			// currently necessary.
-			err := state.Validators.VerifyCommit(
-				chainID, firstID, first.Height, second.LastCommit)
+			var (
+				firstParts *types.PartSet
+				firstID    types.BlockID
+				err        error
+			)
+			if r := bcR.verifier.result(first, second, state.Validators); r != nil {
+				firstParts, firstID, err = r.parts, r.blockID, r.err
+			} else {
+				firstParts = first.MakePartSet(types.BlockPartSizeBytes)
+				firstPartsHeader := firstParts.Header()
+				firstID = types.BlockID{Hash: first.Hash(), PartsHeader: firstPartsHeader}
+				// Finally, verify the first block using the second's commit
+				// NOTE: we can probably make this more efficient, but note that calling
+				// first.Hash() doesn't verify the tx contents, so MakePartSet() is
+				// currently necessary.
+				err = state.Validators.VerifyCommit(
+					chainID, state.SignDomain, firstID, first.Height, second.LastCommit)
+			}
+			bcR.verifier.remove(first.Height)
 			if err != nil {
 				bcR.Logger.Error("Error in validation", "err", err)
 				peerID := bcR.pool.RedoRequest(first.Height)
@@ -0,0 +1,43 @@
+// +build gofuzz
+
+package blockchain
+
+import (
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/p2p/dummy"
+	sm "github.com/tendermint/tendermint/state"
+)
+
+var (
+	fuzzReactor *BlockchainReactor
+	fuzzPeer    p2p.Peer
+)
+
+// FuzzReactorReceive passes data to the Receive method of the reactor, which
+// is not fast syncing. It returns 1 for the data decoding to a message, to
+// give it priority.
+//
+// Build it with go-fuzz-build -func FuzzReactorReceive.
+func FuzzReactorReceive(data []byte) int {
+	if fuzzReactor == nil {
+		fuzzReactor, fuzzPeer = newFuzzReactor()
+	}
+	fuzzReactor.Receive(BlockchainChannel, fuzzPeer, data)
+	if _, err := decodeMsg(data); err != nil {
+		return 0
+	}
+	return 1
+}
+
+func newFuzzReactor() (*BlockchainReactor, p2p.Peer) {
+	bcR := NewBlockchainReactor(sm.State{}, nil, NewBlockStore(dbm.NewMemDB()), false)
+
+	p2pConfig := cfg.DefaultP2PConfig()
+	sw := p2p.NewSwitch(p2pConfig, p2p.NewMultiplexTransport(p2p.DefaultNodeInfo{},
+		p2p.NodeKey{PrivKey: ed25519.GenPrivKey()}, p2p.MConnConfig(p2pConfig)))
+	sw.AddReactor("BLOCKCHAIN", bcR)
+	return bcR, dummy.NewPeer()
+}
@@ -3,6 +3,7 @@ package blockchain
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
 	dbm "github.com/tendermint/tendermint/libs/db"
@@ -18,6 +19,9 @@ There are three types of information stored:
  - Block part:  Parts of each block, aggregated w/ PartSet
  - Commit:      The commit part of each block, for gossiping precommit votes
 
+The heights of the blocks are also indexed by time, from the first block saved
+by a version of the store which indexes them.
+
 Currently the precommit signatures are duplicated in the Block parts as
 well as the Commit.  In the future this may change, perhaps by moving
 the Commit data outside the Block. (TODO)
@@ -29,19 +33,42 @@ type BlockStore struct {
 	db dbm.DB
 
 	mtx    sync.RWMutex
+	base   int64 // first height, 0 if none
 	height int64
+
+	// first height indexed by time, 0 if none
+	timeIndexBase int64
 }
 
 // NewBlockStore returns a new BlockStore with the given DB,
 // initialized to the last height that was committed to the DB.
 func NewBlockStore(db dbm.DB) *BlockStore {
 	bsjson := LoadBlockStoreStateJSON(db)
+	var timeIndexBase int64
+	if bz := db.Get(blockTimeIndexBaseKey); len(bz) > 0 {
+		cdc.MustUnmarshalBinaryBare(bz, &timeIndexBase)
+	}
+	base := bsjson.Base
+	if base == 0 && bsjson.Height > 0 {
+		// saved before the base was
+		base = 1
+	}
 	return &BlockStore{
-		height: bsjson.Height,
-		db:     db,
+		base:          base,
+		height:        bsjson.Height,
+		db:            db,
+		timeIndexBase: timeIndexBase,
 	}
 }
 
+// Base returns the first height of the blocks, 0 if there is none. It is 1
+// unless the blocks below it were pruned.
+func (bs *BlockStore) Base() int64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.base
+}
+
 // Height returns the last known contiguous block height.
 func (bs *BlockStore) Height() int64 {
 	bs.mtx.RLock()
@@ -154,44 +181,195 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 		cmn.PanicSanity(fmt.Sprintf("BlockStore can only save complete block part sets"))
 	}
 
+	// All the writes of the block are batched, and flushed at once.
+	batch := bs.db.NewBatch()
+
 	// Save block meta
 	blockMeta := types.NewBlockMeta(block, blockParts)
 	metaBytes := cdc.MustMarshalBinaryBare(blockMeta)
-	bs.db.Set(calcBlockMetaKey(height), metaBytes)
+	batch.Set(calcBlockMetaKey(height), metaBytes)
 
 	// Save block parts
 	for i := 0; i < blockParts.Total(); i++ {
 		part := blockParts.GetPart(i)
-		bs.saveBlockPart(height, i, part)
+		bs.saveBlockPart(batch, height, i, part)
 	}
 
 	// Save block commit (duplicate and separate from the Block)
 	blockCommitBytes := cdc.MustMarshalBinaryBare(block.LastCommit)
-	bs.db.Set(calcBlockCommitKey(height-1), blockCommitBytes)
+	batch.Set(calcBlockCommitKey(height-1), blockCommitBytes)
 
 	// Save seen commit (seen +2/3 precommits for block)
 	// NOTE: we can delete this at a later height
 	seenCommitBytes := cdc.MustMarshalBinaryBare(seenCommit)
-	bs.db.Set(calcSeenCommitKey(height), seenCommitBytes)
+	batch.Set(calcSeenCommitKey(height), seenCommitBytes)
+
+	// Index the height by time
+	batch.Set(calcBlockTimeKey(block.Time), cdc.MustMarshalBinaryBare(height))
+	bs.mtx.RLock()
+	base, timeIndexBase := bs.base, bs.timeIndexBase
+	bs.mtx.RUnlock()
+	if timeIndexBase == 0 {
+		batch.Set(blockTimeIndexBaseKey, cdc.MustMarshalBinaryBare(height))
+		timeIndexBase = height
+	}
+	if base == 0 {
+		base = height
+	}
 
 	// Save new BlockStoreStateJSON descriptor
-	BlockStoreStateJSON{Height: height}.Save(bs.db)
+	batch.Set(blockStoreKey, BlockStoreStateJSON{Base: base, Height: height}.Bytes())
+
+	// Flush
+	batch.WriteSync()
 
 	// Done!
 	bs.mtx.Lock()
+	bs.base = base
 	bs.height = height
+	bs.timeIndexBase = timeIndexBase
 	bs.mtx.Unlock()
+}
 
-	// Flush
-	bs.db.SetSync(nil, nil)
+// PruneBlocks deletes the blocks below retainHeight, with their commits and
+// time index entries, in a single batch, and returns the number of blocks
+// pruned. The node must not be saving blocks meanwhile. The states of the
+// same heights are pruned by state.PruneStates.
+func (bs *BlockStore) PruneBlocks(retainHeight int64) (uint64, error) {
+	bs.mtx.RLock()
+	base, height, timeIndexBase := bs.base, bs.height, bs.timeIndexBase
+	bs.mtx.RUnlock()
+	if retainHeight <= 0 {
+		return 0, fmt.Errorf("retain height must be greater than 0")
+	}
+	if retainHeight > height {
+		return 0, fmt.Errorf("cannot prune beyond the last height %v", height)
+	}
+	if retainHeight <= base {
+		return 0, nil
+	}
+
+	batch := bs.db.NewBatch()
+	var pruned uint64
+	for h := base; h < retainHeight; h++ {
+		blockMeta := bs.LoadBlockMeta(h)
+		if blockMeta == nil {
+			continue
+		}
+		// a later block with the same time owns the time index entry
+		timeKey := calcBlockTimeKey(blockMeta.Header.Time)
+		if bz := bs.db.Get(timeKey); len(bz) > 0 {
+			var indexed int64
+			cdc.MustUnmarshalBinaryBare(bz, &indexed)
+			if indexed < retainHeight {
+				batch.Delete(timeKey)
+			}
+		}
+		for i := 0; i < blockMeta.BlockID.PartsHeader.Total; i++ {
+			batch.Delete(calcBlockPartKey(h, i))
+		}
+		batch.Delete(calcBlockMetaKey(h))
+		batch.Delete(calcBlockCommitKey(h))
+		batch.Delete(calcSeenCommitKey(h))
+		pruned++
+	}
+	if timeIndexBase != 0 && timeIndexBase < retainHeight {
+		timeIndexBase = retainHeight
+		batch.Set(blockTimeIndexBaseKey, cdc.MustMarshalBinaryBare(timeIndexBase))
+	}
+	batch.Set(blockStoreKey, BlockStoreStateJSON{Base: retainHeight, Height: height}.Bytes())
+	batch.WriteSync()
+
+	bs.mtx.Lock()
+	bs.base = retainHeight
+	bs.timeIndexBase = timeIndexBase
+	bs.mtx.Unlock()
+	return pruned, nil
 }
 
-func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
+// HeightsByTime returns the range of the heights of the blocks with a time
+// in [from, to]: minHeight > maxHeight if there is none.
+//
+// The heights are looked up in the time index, and the ones of the blocks
+// saved before it by a binary search, as the times of the blocks increase
+// with their heights.
+func (bs *BlockStore) HeightsByTime(from, to time.Time) (minHeight, maxHeight int64) {
+	bs.mtx.RLock()
+	base, height, timeIndexBase := bs.base, bs.height, bs.timeIndexBase
+	bs.mtx.RUnlock()
+
+	minHeight = bs.firstHeightFrom(from, base, height, timeIndexBase)
+	maxHeight = bs.firstHeightFrom(to.Add(time.Nanosecond), base, height, timeIndexBase) - 1
+	return minHeight, maxHeight
+}
+
+// HeightAtTime returns the height of the first block with a time after or at
+// t, or 0 if there is none.
+func (bs *BlockStore) HeightAtTime(t time.Time) int64 {
+	bs.mtx.RLock()
+	base, height, timeIndexBase := bs.base, bs.height, bs.timeIndexBase
+	bs.mtx.RUnlock()
+
+	if first := bs.firstHeightFrom(t, base, height, timeIndexBase); first <= height {
+		return first
+	}
+	return 0
+}
+
+// firstHeightFrom returns the first height in [base, height] of a block with
+// a time after or at t, or height+1 if there is none.
+func (bs *BlockStore) firstHeightFrom(t time.Time, base, height, timeIndexBase int64) int64 {
+	if base == 0 {
+		base = 1
+	}
+	if timeIndexBase == 0 {
+		return bs.searchFirstHeightFrom(t, base, height)
+	}
+	if timeIndexBase > base && !bs.blockTime(timeIndexBase-1).Before(t) {
+		return bs.searchFirstHeightFrom(t, base, timeIndexBase-1)
+	}
+
+	first := height + 1
+	itr := bs.db.Iterator(calcBlockTimeKey(t), calcBlockTimeKey(time.Unix(0, maxBlockTimeNanos)))
+	defer itr.Close()
+	if itr.Valid() {
+		cdc.MustUnmarshalBinaryBare(itr.Value(), &first)
+		if first > height {
+			// saved while looking up
+			first = height + 1
+		}
+	}
+	return first
+}
+
+// searchFirstHeightFrom returns the first height in [min, max] of a block
+// with a time after or at t, or max+1 if there is none.
+func (bs *BlockStore) searchFirstHeightFrom(t time.Time, min, max int64) int64 {
+	for min <= max {
+		mid := min + (max-min)/2
+		if bs.blockTime(mid).Before(t) {
+			min = mid + 1
+		} else {
+			max = mid - 1
+		}
+	}
+	return min
+}
+
+func (bs *BlockStore) blockTime(height int64) time.Time {
+	blockMeta := bs.LoadBlockMeta(height)
+	if blockMeta == nil {
+		panic(fmt.Sprintf("Missing block meta at height %v", height))
+	}
+	return blockMeta.Header.Time
+}
+
+func (bs *BlockStore) saveBlockPart(batch dbm.SetDeleter, height int64, index int, part *types.Part) {
 	if height != bs.Height()+1 {
 		cmn.PanicSanity(fmt.Sprintf("BlockStore can only save contiguous blocks. Wanted %v, got %v", bs.Height()+1, height))
 	}
 	partBytes := cdc.MustMarshalBinaryBare(part)
-	bs.db.Set(calcBlockPartKey(height, index), partBytes)
+	batch.Set(calcBlockPartKey(height, index), partBytes)
 }
 
 //-----------------------------------------------------------------------------
@@ -212,21 +390,46 @@ func calcSeenCommitKey(height int64) []byte {
 	return []byte(fmt.Sprintf("SC:%v", height))
 }
 
+// The block times are after 1970, and before 2262, so their Unix nanoseconds
+// are zero-padded to sort the keys by time.
+const maxBlockTimeNanos = 1<<63 - 1
+
+func calcBlockTimeKey(t time.Time) []byte {
+	var nanos int64
+	switch {
+	case t.Before(time.Unix(0, 0)):
+		nanos = 0
+	case t.After(time.Unix(0, maxBlockTimeNanos)):
+		nanos = maxBlockTimeNanos
+	default:
+		nanos = t.UnixNano()
+	}
+	return []byte(fmt.Sprintf("T:%019d", nanos))
+}
+
+var blockTimeIndexBaseKey = []byte("blockTimeIndexBase")
+
 //-----------------------------------------------------------------------------
 
 var blockStoreKey = []byte("blockStore")
 
 type BlockStoreStateJSON struct {
+	Base   int64 `json:"base"` // 0 if saved before the base was
 	Height int64 `json:"height"`
 }
 
 // Save persists the blockStore state to the database as JSON.
 func (bsj BlockStoreStateJSON) Save(db dbm.DB) {
+	db.SetSync(blockStoreKey, bsj.Bytes())
+}
+
+// Bytes returns the blockStore state as JSON.
+func (bsj BlockStoreStateJSON) Bytes() []byte {
 	bytes, err := cdc.MarshalJSON(bsj)
 	if err != nil {
 		cmn.PanicSanity(fmt.Sprintf("Could not marshal state bytes: %v", err))
 	}
-	db.SetSync(blockStoreKey, bytes)
+	return bytes
 }
 
 // LoadBlockStoreStateJSON returns the BlockStoreStateJSON as loaded from disk.
@@ -391,6 +391,117 @@ func TestBlockFetchAtHeight(t *testing.T) {
 	require.Nil(t, blockAtHeightPlus2, "expecting an unsuccessful load of Height()+2")
 }
 
+// batchOnlyDB fails the writes not made in a batch.
+type batchOnlyDB struct {
+	db.DB
+}
+
+func (batchOnlyDB) Set(key, value []byte)     { panic("write outside of a batch") }
+func (batchOnlyDB) SetSync(key, value []byte) { panic("write outside of a batch") }
+
+func TestBlockStoreSaveBlockBatched(t *testing.T) {
+	bs := NewBlockStore(batchOnlyDB{db.NewMemDB()})
+	block := makeBlock(1, state, new(types.Commit))
+	require.NotPanics(t, func() {
+		bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(1, tmtime.Now()))
+	})
+	assert.Equal(t, int64(1), bs.Height())
+	assert.Equal(t, block.Hash(), bs.LoadBlock(1).Hash())
+	assert.Equal(t, int64(1), LoadBlockStoreStateJSON(bs.db).Height)
+}
+
+func TestBlockStoreHeightsByTime(t *testing.T) {
+	state, bs, cleanup := makeStateAndBlockStore(log.NewTMLogger(new(bytes.Buffer)))
+	defer cleanup()
+
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	saveBlock := func(height int64) {
+		block := makeBlock(height, state, new(types.Commit))
+		block.Time = start.Add(time.Duration(height) * time.Minute)
+		bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(height, tmtime.Now()))
+	}
+
+	// the first blocks are saved before the time index
+	for height := int64(1); height <= 3; height++ {
+		saveBlock(height)
+		bs.db.Delete(calcBlockTimeKey(start.Add(time.Duration(height) * time.Minute)))
+	}
+	bs.db.Delete(blockTimeIndexBaseKey)
+	bs = NewBlockStore(bs.db)
+	for height := int64(4); height <= 6; height++ {
+		saveBlock(height)
+	}
+
+	at := func(minutes, seconds int) time.Time {
+		return start.Add(time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second)
+	}
+	cases := []struct {
+		from, to             time.Time
+		minHeight, maxHeight int64
+	}{
+		{at(0, 0), at(10, 0), 1, 6},
+		{at(1, 0), at(1, 0), 1, 1},
+		{at(1, 1), at(2, 59), 2, 2},
+		{at(2, 30), at(4, 30), 3, 4}, // across the base of the index
+		{at(4, 0), at(6, 0), 4, 6},
+		{at(5, 1), at(20, 0), 6, 6},
+		{at(6, 1), at(20, 0), 7, 6}, // after the last block
+		{at(0, 0), at(0, 59), 1, 0}, // before the first block
+		{at(3, 1), at(3, 59), 4, 3}, // between two blocks
+		{time.Time{}, at(2, 0), 1, 2},
+	}
+	for i, tc := range cases {
+		minHeight, maxHeight := bs.HeightsByTime(tc.from, tc.to)
+		assert.Equal(t, tc.minHeight, minHeight, "#%d", i)
+		assert.Equal(t, tc.maxHeight, maxHeight, "#%d", i)
+	}
+
+	assert.EqualValues(t, 1, bs.HeightAtTime(time.Time{}))
+	assert.EqualValues(t, 3, bs.HeightAtTime(at(2, 1)))
+	assert.EqualValues(t, 5, bs.HeightAtTime(at(5, 0)))
+	assert.EqualValues(t, 0, bs.HeightAtTime(at(6, 1)))
+}
+
+func TestBlockStorePruneBlocks(t *testing.T) {
+	state, bs, cleanup := makeStateAndBlockStore(log.NewTMLogger(new(bytes.Buffer)))
+	defer cleanup()
+	assert.EqualValues(t, 0, bs.Base())
+
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	for height := int64(1); height <= 6; height++ {
+		block := makeBlock(height, state, new(types.Commit))
+		block.Time = start.Add(time.Duration(height) * time.Minute)
+		bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(height, tmtime.Now()))
+	}
+	assert.EqualValues(t, 1, bs.Base())
+
+	_, err := bs.PruneBlocks(7)
+	assert.Error(t, err, "beyond the last height")
+	pruned, err := bs.PruneBlocks(4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, pruned)
+	pruned, err = bs.PruneBlocks(2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, pruned)
+
+	bs = NewBlockStore(bs.db)
+	assert.EqualValues(t, 4, bs.Base())
+	assert.EqualValues(t, 6, bs.Height())
+	for height := int64(1); height <= 6; height++ {
+		if height < 4 {
+			assert.Nil(t, bs.LoadBlock(height), "height %d", height)
+			assert.Nil(t, bs.LoadSeenCommit(height), "height %d", height)
+			assert.Nil(t, bs.LoadBlockCommit(height), "height %d", height)
+		} else {
+			assert.NotNil(t, bs.LoadBlock(height), "height %d", height)
+		}
+	}
+
+	minHeight, maxHeight := bs.HeightsByTime(time.Time{}, start.Add(time.Hour))
+	assert.EqualValues(t, 4, minHeight)
+	assert.EqualValues(t, 6, maxHeight)
+}
+
 func doFn(fn func() (interface{}, error)) (res interface{}, err error, panicErr error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -0,0 +1,126 @@
+package blockchain
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// blockVerifier verifies the commits of the blocks ahead of the one being
+// applied, using a bounded number of workers, so that commit verification
+// runs in parallel with the sequential application of blocks in fast sync.
+//
+// A block is verified ahead of time only if its header commits to one of
+// the known validator sets. The result is only used if the validator set of
+// the state the block is applied on matches the one used for verification.
+type blockVerifier struct {
+	chainID string
+	domain  types.SignDomain
+	workers chan struct{} // semaphore bounding the number of workers
+
+	mtx     sync.Mutex
+	results map[int64]*verifyResult
+}
+
+// verifyResult is the result of verifying first using second's LastCommit.
+type verifyResult struct {
+	first, second *types.Block
+	valsHash      []byte
+
+	done    chan struct{} // closed once verification completes
+	parts   *types.PartSet
+	blockID types.BlockID
+	err     error
+}
+
+func newBlockVerifier(chainID string, domain types.SignDomain, numWorkers int) *blockVerifier {
+	return &blockVerifier{
+		chainID: chainID,
+		domain:  domain,
+		workers: make(chan struct{}, numWorkers),
+		results: make(map[int64]*verifyResult),
+	}
+}
+
+// prefetch starts verifying each of the consecutive blocks using the commit
+// of the next one, for the blocks which aren't being verified yet and which
+// are signed by one of the given validator sets. It returns without waiting
+// once all workers are busy.
+func (bv *blockVerifier) prefetch(blocks []*types.Block, valSets ...*types.ValidatorSet) {
+	valsHashes := make([][]byte, len(valSets))
+	for i, vals := range valSets {
+		valsHashes[i] = vals.Hash()
+	}
+
+	bv.mtx.Lock()
+	defer bv.mtx.Unlock()
+
+	for i := 0; i+1 < len(blocks); i++ {
+		first, second := blocks[i], blocks[i+1]
+		if r, ok := bv.results[first.Height]; ok && r.first == first && r.second == second {
+			continue
+		}
+
+		j := 0
+		for ; j < len(valSets); j++ {
+			if bytes.Equal(valsHashes[j], first.ValidatorsHash) {
+				break
+			}
+		}
+		if j == len(valSets) {
+			continue // validator set not known yet
+		}
+
+		select {
+		case bv.workers <- struct{}{}:
+		default:
+			return
+		}
+
+		r := &verifyResult{
+			first:    first,
+			second:   second,
+			valsHash: valsHashes[j],
+			done:     make(chan struct{}),
+		}
+		bv.results[first.Height] = r
+
+		go func(r *verifyResult, vals *types.ValidatorSet) {
+			defer func() { <-bv.workers }()
+			r.verify(bv.chainID, bv.domain, vals)
+		}(r, valSets[j])
+	}
+}
+
+// result returns the result of verifying first using second's LastCommit
+// against vals, waiting for the verification to complete. It returns nil if
+// these blocks were not prefetched with the same validator set.
+func (bv *blockVerifier) result(first, second *types.Block, vals *types.ValidatorSet) *verifyResult {
+	bv.mtx.Lock()
+	r, ok := bv.results[first.Height]
+	bv.mtx.Unlock()
+
+	if !ok || r.first != first || r.second != second || !bytes.Equal(r.valsHash, vals.Hash()) {
+		return nil
+	}
+	<-r.done
+	return r
+}
+
+// remove forgets the result for the given height.
+func (bv *blockVerifier) remove(height int64) {
+	bv.mtx.Lock()
+	delete(bv.results, height)
+	bv.mtx.Unlock()
+}
+
+func (r *verifyResult) verify(chainID string, domain types.SignDomain, vals *types.ValidatorSet) {
+	defer close(r.done)
+
+	// NOTE: calling first.Hash() doesn't verify the tx contents, so
+	// MakePartSet() is currently necessary.
+	r.parts = r.first.MakePartSet(types.BlockPartSizeBytes)
+	r.blockID = types.BlockID{Hash: r.first.Hash(), PartsHeader: r.parts.Header()}
+	r.err = vals.VerifyCommit(chainID, domain, r.blockID, r.first.Height, r.second.LastCommit)
+}
@@ -0,0 +1,72 @@
+package blockchain
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestBlockVerifier(t *testing.T) {
+	config = cfg.ResetTestRoot("blockchain_verifier_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	maxBlockHeight := int64(10)
+	pair := newBlockchainReactor(log.TestingLogger(), genDoc, privVals, maxBlockHeight)
+	defer pair.app.Stop()
+
+	store := pair.reactor.store
+	vals := pair.reactor.initialState.Validators
+
+	blocks := make([]*types.Block, 0, maxBlockHeight)
+	for height := int64(1); height <= maxBlockHeight; height++ {
+		blocks = append(blocks, store.LoadBlock(height))
+	}
+
+	bv := newBlockVerifier(genDoc.ChainID, genDoc.GetSignDomain(), 4)
+	otherVals, _ := types.RandValidatorSet(1, 10)
+	bv.prefetch(blocks, otherVals, vals)
+
+	// the first 4 blocks are verified by the 4 workers
+	for i := 0; i < 4; i++ {
+		r := bv.result(blocks[i], blocks[i+1], vals)
+		require.NotNil(t, r, "no result for height %d", blocks[i].Height)
+		require.NoError(t, r.err)
+		assert.Equal(t, blocks[i].Hash(), r.blockID.Hash)
+		bv.remove(blocks[i].Height)
+	}
+
+	// the remaining blocks are verified as the workers are freed
+	for i := 4; i+1 < len(blocks); i++ {
+		r := waitResult(t, bv, blocks, i, vals)
+		require.NoError(t, r.err)
+		assert.Equal(t, blocks[i].Hash(), r.blockID.Hash)
+		bv.remove(blocks[i].Height)
+	}
+	bv.prefetch(blocks, vals)
+
+	// results are not used for another validator set or other blocks
+	assert.Nil(t, bv.result(blocks[0], blocks[1], otherVals))
+	assert.Nil(t, bv.result(blocks[1], blocks[3], vals))
+}
+
+// waitResult prefetches the blocks from the i-th one until the workers are
+// free to verify it, and returns its result.
+func waitResult(t *testing.T, bv *blockVerifier, blocks []*types.Block, i int, vals *types.ValidatorSet) *verifyResult {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		bv.prefetch(blocks[i:], vals)
+		if r := bv.result(blocks[i], blocks[i+1], vals); r != nil {
+			return r
+		}
+		require.True(t, time.Now().Before(deadline), "no result for height %d", blocks[i].Height)
+		time.Sleep(10 * time.Millisecond)
+	}
+}
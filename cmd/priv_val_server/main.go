@@ -10,12 +10,15 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 
 	"github.com/tendermint/tendermint/privval"
+	"github.com/tendermint/tendermint/types"
 )
 
 func main() {
 	var (
 		addr             = flag.String("addr", ":26659", "Address of client to connect to")
 		chainID          = flag.String("chain-id", "mychain", "chain id")
+		signDomainTag    = flag.String("sign-domain-tag", "", "tag of the sign domain of the chain, if it sets one in its genesis file")
+		signDomain       = flag.Int("sign-domain-version", types.SignBytesVersionLegacy, "version of the sign domain of the chain")
 		privValKeyPath   = flag.String("priv-key", "", "priv val key file path")
 		privValStatePath = flag.String("priv-state", "", "priv val state file path")
 
@@ -33,6 +36,12 @@ func main() {
 		"privStatePath", *privValStatePath,
 	)
 
+	domain := types.SignDomain{Version: *signDomain, Tag: *signDomainTag}
+	if err := domain.ValidateBasic(); err != nil {
+		logger.Error("Invalid sign domain", "err", err)
+		os.Exit(1)
+	}
+
 	pv := privval.LoadFilePV(*privValKeyPath, *privValStatePath)
 
 	var dialer privval.Dialer
@@ -49,6 +58,7 @@ func main() {
 	}
 
 	rs := privval.NewRemoteSigner(logger, *chainID, pv, dialer)
+	privval.RemoteSignerSignDomain(domain)(rs)
 	err := rs.Start()
 	if err != nil {
 		panic(err)
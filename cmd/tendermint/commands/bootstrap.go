@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var archiveDir string
+
+// BootstrapCmd imports the blocks of an archive created by export-blocks,
+// so that a new node does not need to sync them from the network.
+var BootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Import blocks exported by export-blocks into the blockstore",
+	Long: `Import all the blocks of an archive created by export-blocks into the
+blockstore, starting after the last block of this node's state.
+
+Each block is checked against the archive manifest, verified against its
+commit and executed against the ABCI application, as it would be during fast
+sync. The transactions of the imported blocks are not indexed.`,
+	RunE: bootstrap,
+}
+
+func init() {
+	BootstrapCmd.Flags().StringVar(&archiveDir, "from-archive", "", "Directory of the archive to import blocks from")
+	BootstrapCmd.Flags().String("proxy_app", config.ProxyApp, "Proxy app address, or one of: 'kvstore', 'persistent_kvstore', 'counter', 'counter_serial' or 'noop' for local testing.")
+}
+
+func bootstrap(cmd *cobra.Command, args []string) error {
+	if archiveDir == "" {
+		return fmt.Errorf("--from-archive is required")
+	}
+	return importArchive(archiveDir, 0)
+}
@@ -0,0 +1,357 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cfg "github.com/tendermint/tendermint/config"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	nm "github.com/tendermint/tendermint/node"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/privval"
+	"github.com/tendermint/tendermint/proxy"
+	rpcserver "github.com/tendermint/tendermint/rpc/lib/server"
+	"github.com/tendermint/tendermint/types"
+	tmtime "github.com/tendermint/tendermint/types/time"
+)
+
+// devnetFirstP2PPort is the port of the in-memory p2p address of the first
+// validator of a devnet, the next ones using the next ports.
+const devnetFirstP2PPort = 26656
+
+// setDevMode configures the node as a local devnet of one validator: the
+// keys and the genesis file are generated if missing, the kvstore app runs
+// in-process unless --proxy_app is set, all the RPC methods are enabled, and
+// blocks are produced right away, without waiting for txs nor peers.
+func setDevMode(cmd *cobra.Command, config *cfg.Config) error {
+	setDevConfig(cmd, config)
+	return initFilesWithConfig(config)
+}
+
+// setDevConfig sets the options of the dev mode shared by all the validators
+// of a devnet.
+func setDevConfig(cmd *cobra.Command, config *cfg.Config) {
+	if !cmd.Flags().Changed("proxy_app") {
+		config.ProxyApp = "kvstore"
+	}
+	config.RPC.Unsafe = true
+	config.RPC.DisableUnsafe = false
+	config.Consensus.CreateEmptyBlocks = true
+	config.Consensus.CreateEmptyBlocksInterval = 0
+	config.Consensus.WaitForPersistentPeers = false
+}
+
+// devKill is a kill of a validator of a devnet, after a delay since the start,
+// and its restart after a downtime, if any.
+type devKill struct {
+	validator int
+	after     time.Duration
+	downtime  time.Duration // 0 - not restarted
+}
+
+// parseDevKill parses a kill of the form validator@after[:downtime], e.g.
+// 1@30s:10s.
+func parseDevKill(s string) (devKill, error) {
+	spl := strings.SplitN(s, "@", 2)
+	if len(spl) != 2 {
+		return devKill{}, fmt.Errorf("invalid kill %q, expected validator@after[:downtime]", s)
+	}
+	validator, err := strconv.Atoi(spl[0])
+	if err != nil || validator < 0 {
+		return devKill{}, fmt.Errorf("invalid validator index in kill %q", s)
+	}
+	k := devKill{validator: validator}
+
+	durations := strings.SplitN(spl[1], ":", 2)
+	if k.after, err = time.ParseDuration(durations[0]); err != nil || k.after < 0 {
+		return devKill{}, fmt.Errorf("invalid delay in kill %q", s)
+	}
+	if len(durations) == 2 {
+		if k.downtime, err = time.ParseDuration(durations[1]); err != nil || k.downtime <= 0 {
+			return devKill{}, fmt.Errorf("invalid downtime in kill %q", s)
+		}
+	}
+	return k, nil
+}
+
+// devnet runs the validators of a local devnet as nodes of this process,
+// connected through a p2p.MemNetwork. The chain starts over at each run, and
+// the stores of each node are kept in memory across its restarts.
+type devnet struct {
+	network *p2p.MemNetwork
+	genDoc  *types.GenesisDoc
+	configs []*cfg.Config
+	dbs     []map[string]dbm.DB // by DBContext.ID
+
+	mtx     sync.Mutex
+	nodes   []*nm.Node // nil while killed
+	stopped bool
+}
+
+// newDevnet generates the keys of the validators in the devnet directory of
+// the root, if missing, and the genesis of a new chain.
+func newDevnet(config *cfg.Config, nValidators int) (*devnet, error) {
+	d := &devnet{
+		network: p2p.NewMemNetwork(),
+		nodes:   make([]*nm.Node, nValidators),
+	}
+	genVals := make([]types.GenesisValidator, nValidators)
+	peers := make([]string, nValidators)
+	for i := 0; i < nValidators; i++ {
+		nodeConfig := devnetNodeConfig(config, i)
+		for _, dir := range []string{"config", "data"} {
+			if err := cmn.EnsureDir(filepath.Join(nodeConfig.RootDir, dir), nodeDirPerm); err != nil {
+				return nil, err
+			}
+		}
+
+		pv := privval.LoadOrGenFilePV(nodeConfig.PrivValidatorKeyFile(), nodeConfig.PrivValidatorStateFile())
+		// the chain starts over
+		pv.Reset()
+		nodeKey, err := p2p.LoadOrGenNodeKey(nodeConfig.NodeKeyFile())
+		if err != nil {
+			return nil, err
+		}
+
+		genVals[i] = types.GenesisValidator{
+			Address: pv.GetPubKey().Address(),
+			PubKey:  pv.GetPubKey(),
+			Power:   10,
+			Name:    nodeConfig.Moniker,
+		}
+		peers[i] = p2p.IDAddressString(nodeKey.ID(), devnetP2PAddress(i))
+		d.configs = append(d.configs, nodeConfig)
+		d.dbs = append(d.dbs, make(map[string]dbm.DB))
+	}
+
+	d.genDoc = &types.GenesisDoc{
+		ChainID:         fmt.Sprintf("dev-chain-%v", cmn.RandStr(6)),
+		GenesisTime:     tmtime.Now(),
+		ConsensusParams: types.DefaultConsensusParams(),
+		Validators:      genVals,
+	}
+	for i, nodeConfig := range d.configs {
+		otherPeers := append(append([]string{}, peers[:i]...), peers[i+1:]...)
+		nodeConfig.P2P.PersistentPeers = strings.Join(otherPeers, ",")
+		if err := d.genDoc.SaveAs(nodeConfig.GenesisFile()); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// devnetNodeConfig returns a copy of the config for the i-th validator of a
+// devnet, running in memory. Only the first validator runs the RPC server,
+// serving the RPC of the other ones too (see devnet.rpcTenants).
+func devnetNodeConfig(config *cfg.Config, i int) *cfg.Config {
+	var (
+		nodeConfig      = *config
+		rpc             = *config.RPC
+		p2pConfig       = *config.P2P
+		mempool         = *config.Mempool
+		consensus       = *config.Consensus
+		txIndex         = *config.TxIndex
+		instrumentation = *config.Instrumentation
+	)
+	nodeConfig.RPC = &rpc
+	nodeConfig.P2P = &p2pConfig
+	nodeConfig.Mempool = &mempool
+	nodeConfig.Consensus = &consensus
+	nodeConfig.TxIndex = &txIndex
+	nodeConfig.Instrumentation = &instrumentation
+
+	nodeConfig.SetRoot(filepath.Join(config.RootDir, "devnet", fmt.Sprintf("node%d", i)))
+	nodeConfig.SetEphemeral()
+	nodeConfig.Moniker = fmt.Sprintf("%s-%d", config.Moniker, i)
+	nodeConfig.P2P.ListenAddress = "tcp://" + devnetP2PAddress(i)
+	nodeConfig.P2P.ExternalAddress = ""
+	nodeConfig.P2P.UPNP = false
+	nodeConfig.P2P.PexReactor = false
+	nodeConfig.P2P.AddrBookStrict = false
+	nodeConfig.P2P.AllowDuplicateIP = true
+	if i > 0 {
+		// the RPC is served by the first validator
+		nodeConfig.RPC.ListenAddress = ""
+		nodeConfig.RPC.GRPCListenAddress = ""
+		nodeConfig.RPC.PprofListenAddress = ""
+		nodeConfig.ProfListenAddress = ""
+		nodeConfig.Instrumentation.Prometheus = false
+	}
+	return &nodeConfig
+}
+
+// devnetP2PAddress returns the in-memory p2p address of the i-th validator.
+func devnetP2PAddress(i int) string {
+	return fmt.Sprintf("127.0.0.1:%d", devnetFirstP2PPort+i)
+}
+
+// newNode creates the node of the i-th validator, on the stores of its
+// previous run if any.
+func (d *devnet) newNode(i int) (*nm.Node, error) {
+	nodeConfig := d.configs[i]
+	nodeKey, err := p2p.LoadNodeKey(nodeConfig.NodeKeyFile())
+	if err != nil {
+		return nil, err
+	}
+	dbs := d.dbs[i]
+	return nm.NewNode(nodeConfig,
+		privval.LoadFilePV(nodeConfig.PrivValidatorKeyFile(), nodeConfig.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.DefaultClientCreator(nodeConfig.ProxyApp, nodeConfig.ABCI, nodeConfig.DBDir()),
+		func() (*types.GenesisDoc, error) { return d.genDoc, nil },
+		func(ctx *nm.DBContext) (dbm.DB, error) {
+			if db, ok := dbs[ctx.ID]; ok {
+				return db, nil
+			}
+			db := dbm.NewMemDB()
+			dbs[ctx.ID] = db
+			return db, nil
+		},
+		nm.DefaultMetricsProvider(nodeConfig.Instrumentation),
+		logger.With("validator", i),
+		nm.TransportOptions(
+			p2p.MultiplexTransportDialer(d.network),
+			p2p.MultiplexTransportListen(d.network.Listen),
+		),
+		nm.RPCTenants(d.rpcTenants(i)...),
+	)
+}
+
+// rpcTenants returns the tenants of the RPC server of the i-th validator: the
+// RPC of the other validators, under the /node<i> URL path prefixes, across
+// their restarts, if it's the first one, which runs the RPC server.
+func (d *devnet) rpcTenants(i int) []rpcserver.Tenant {
+	if i > 0 {
+		return nil
+	}
+	var tenants []rpcserver.Tenant
+	for j := 1; j < len(d.configs); j++ {
+		j := j
+		tenants = append(tenants, rpcserver.Tenant{
+			Name:   d.configs[j].Moniker,
+			Prefix: fmt.Sprintf("/node%d", j),
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				d.mtx.Lock()
+				n := d.nodes[j]
+				d.mtx.Unlock()
+				if n == nil {
+					http.Error(w, fmt.Sprintf("validator %d is killed", j), http.StatusServiceUnavailable)
+					return
+				}
+				n.RPCHandler().ServeHTTP(w, r)
+			}),
+		})
+	}
+	return tenants
+}
+
+// start creates and starts the node of the i-th validator.
+func (d *devnet) start(i int) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.stopped {
+		return fmt.Errorf("devnet stopped")
+	}
+	n, err := d.newNode(i)
+	if err != nil {
+		return err
+	}
+	if err := n.Start(); err != nil {
+		return err
+	}
+	d.nodes[i] = n
+	return nil
+}
+
+// stop stops the node of the i-th validator, if running.
+func (d *devnet) stop(i int) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if n := d.nodes[i]; n != nil && n.IsRunning() {
+		n.Stop()
+	}
+	d.nodes[i] = nil
+}
+
+// stopAll stops all the nodes, for good.
+func (d *devnet) stopAll() {
+	for i := range d.nodes {
+		d.stop(i)
+	}
+	d.mtx.Lock()
+	d.stopped = true
+	d.mtx.Unlock()
+}
+
+// kill stops the validator after the delay of the kill, and restarts it after
+// the downtime, if any.
+func (d *devnet) kill(k devKill) {
+	time.Sleep(k.after)
+	logger.Info("Killing validator", "validator", k.validator, "downtime", k.downtime)
+	d.stop(k.validator)
+	if k.downtime == 0 {
+		return
+	}
+
+	time.Sleep(k.downtime)
+	if err := d.start(k.validator); err != nil {
+		logger.Error("Failed to restart validator", "validator", k.validator, "err", err)
+		return
+	}
+	logger.Info("Restarted validator", "validator", k.validator)
+}
+
+// runDevnet runs a devnet of nValidators until SIGTERM or CTRL-C, killing and
+// restarting the validators as scheduled by the kills.
+func runDevnet(config *cfg.Config, nValidators int, kills []string) error {
+	switch config.ProxyApp {
+	case "kvstore", "counter", "counter_serial", "noop":
+	default:
+		return fmt.Errorf("the validators of a devnet need an in-memory app (kvstore, counter, counter_serial or noop), not %q", config.ProxyApp)
+	}
+	devKills := make([]devKill, len(kills))
+	for i, s := range kills {
+		k, err := parseDevKill(s)
+		if err != nil {
+			return err
+		}
+		if k.validator >= nValidators {
+			return fmt.Errorf("no validator %d in kill %q, the devnet has %d", k.validator, s, nValidators)
+		}
+		devKills[i] = k
+	}
+
+	d, err := newDevnet(config, nValidators)
+	if err != nil {
+		return fmt.Errorf("Failed to create the devnet: %v", err)
+	}
+	for i := range d.configs {
+		if err := d.start(i); err != nil {
+			d.stopAll()
+			return fmt.Errorf("Failed to start validator %d: %v", i, err)
+		}
+	}
+	logger.Info("Started the devnet", "validators", nValidators, "chainID", d.genDoc.ChainID,
+		"rpc", config.RPC.ListenAddress)
+	for _, k := range devKills {
+		go d.kill(k)
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	sig := <-c
+	logger.Error(fmt.Sprintf("captured %v, exiting...", sig))
+	d.stopAll()
+	return nil
+}
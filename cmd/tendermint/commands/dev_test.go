@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	rpcserver "github.com/tendermint/tendermint/rpc/lib/server"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestSetDevMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dev_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	config := cfg.DefaultConfig().SetRoot(dir)
+	cfg.EnsureRoot(dir)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("proxy_app", config.ProxyApp, "")
+	require.NoError(t, setDevMode(cmd, config))
+
+	assert.Equal(t, "kvstore", config.ProxyApp)
+	assert.True(t, config.RPC.IsUnsafeEnabled())
+	assert.True(t, cmn.FileExists(config.PrivValidatorKeyFile()))
+	assert.True(t, cmn.FileExists(config.NodeKeyFile()))
+	genDoc, err := types.GenesisDocFromFile(config.GenesisFile())
+	require.NoError(t, err)
+	assert.Len(t, genDoc.Validators, 1)
+
+	// an explicit app is kept
+	config.ProxyApp = "tcp://127.0.0.1:26658"
+	require.NoError(t, cmd.Flags().Set("proxy_app", config.ProxyApp))
+	require.NoError(t, setDevMode(cmd, config))
+	assert.Equal(t, "tcp://127.0.0.1:26658", config.ProxyApp)
+}
+
+func TestParseDevKill(t *testing.T) {
+	k, err := parseDevKill("1@30s:10s")
+	require.NoError(t, err)
+	assert.Equal(t, devKill{validator: 1, after: 30 * time.Second, downtime: 10 * time.Second}, k)
+
+	k, err = parseDevKill("0@1m")
+	require.NoError(t, err)
+	assert.Equal(t, devKill{validator: 0, after: time.Minute}, k)
+
+	for _, s := range []string{"", "1", "a@1s", "-1@1s", "1@x", "1@1s:", "1@1s:0s"} {
+		_, err := parseDevKill(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestDevnetNodeConfig(t *testing.T) {
+	config := cfg.DefaultConfig().SetRoot("/foo")
+
+	first := devnetNodeConfig(config, 0)
+	second := devnetNodeConfig(config, 1)
+	assert.Equal(t, "/foo/devnet/node1", second.RootDir)
+	assert.Equal(t, "tcp://127.0.0.1:26657", second.P2P.ListenAddress)
+	assert.Equal(t, "memdb", second.DBBackend)
+	assert.True(t, second.Consensus.WalDisabled)
+
+	// only the first validator runs the RPC server
+	assert.Equal(t, config.RPC.ListenAddress, first.RPC.ListenAddress)
+	assert.Equal(t, "", second.RPC.ListenAddress)
+
+	// the config is copied
+	assert.Equal(t, "/foo", config.RootDir)
+	assert.Equal(t, "leveldb", config.DBBackend)
+	assert.NotEqual(t, "", config.RPC.ListenAddress)
+}
+
+// The validators of a devnet start, connected through the MemNetwork, and make
+// blocks.
+func TestDevnetMakesBlocks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devnet_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	config := cfg.DefaultConfig().SetRoot(dir)
+	config.ProxyApp = "kvstore"
+	config.RPC.ListenAddress = ""
+	config.RPC.GRPCListenAddress = ""
+
+	d, err := newDevnet(config, 2)
+	require.NoError(t, err)
+	defer d.stopAll()
+	for i := range d.configs {
+		require.NoError(t, d.start(i))
+	}
+
+	blockCh := make(chan interface{}, 1)
+	require.NoError(t, d.nodes[1].EventBus().Subscribe(context.Background(), "devnet_test", types.EventQueryNewBlock, blockCh))
+	for height := int64(1); height <= 2; height++ {
+		select {
+		case <-blockCh:
+		case <-time.After(20 * time.Second):
+			t.Fatalf("timed out waiting for the block %d of the devnet", height)
+		}
+	}
+
+	// the first validator serves the RPC of the second one, while it runs
+	h, err := rpcserver.NewTenantsHandler(d.rpcTenants(0))
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/node1/status", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), d.configs[1].Moniker)
+	d.stop(1)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/node1/status", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	bc "github.com/tendermint/tendermint/blockchain"
+	nm "github.com/tendermint/tendermint/node"
+)
+
+var (
+	exportDir        string
+	exportFromHeight int64
+	exportToHeight   int64
+)
+
+// ExportBlocksCmd writes a range of blocks of the blockstore to an archive
+// which can be imported by other nodes using import-blocks or bootstrap.
+var ExportBlocksCmd = &cobra.Command{
+	Use:   "export-blocks",
+	Short: "Export a range of blocks of the blockstore to an archive",
+	RunE:  exportBlocks,
+}
+
+func init() {
+	ExportBlocksCmd.Flags().StringVar(&exportDir, "out", "", "Directory to write the archive to")
+	ExportBlocksCmd.Flags().Int64Var(&exportFromHeight, "from", 1, "First height to export")
+	ExportBlocksCmd.Flags().Int64Var(&exportToHeight, "to", 0, "Last height to export (0 for the last block in the blockstore)")
+}
+
+func exportBlocks(cmd *cobra.Command, args []string) error {
+	if exportDir == "" {
+		return fmt.Errorf("--out is required")
+	}
+
+	blockStoreDB, err := nm.DefaultDBProvider(&nm.DBContext{"blockstore", config})
+	if err != nil {
+		return err
+	}
+	defer blockStoreDB.Close()
+	blockStore := bc.NewBlockStore(blockStoreDB)
+
+	if blockStore.Height() == 0 {
+		return fmt.Errorf("the blockstore is empty")
+	}
+	to := exportToHeight
+	if to == 0 {
+		to = blockStore.Height()
+	}
+	if err := bc.ExportBlocks(blockStore, exportDir, exportFromHeight, to); err != nil {
+		return err
+	}
+
+	logger.Info("Exported blocks", "dir", exportDir, "from", exportFromHeight, "to", to)
+	return nil
+}
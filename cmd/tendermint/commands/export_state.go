@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	bc "github.com/tendermint/tendermint/blockchain"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	nm "github.com/tendermint/tendermint/node"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types/genesis"
+)
+
+var (
+	exportStateHeight int64
+	exportStateOut    string
+
+	importStateChainID  string
+	importStateAppState string
+	importStateOut      string
+)
+
+// ExportStateCmd exports the tendermint state after the block of a height, to
+// start a new chain from it with import-state.
+var ExportStateCmd = &cobra.Command{
+	Use:   "export-state",
+	Short: "Export the tendermint state (validators, consensus params, last results) after the block of a height",
+	Long: `Export the tendermint state after the block of a height: the validators and
+the consensus params of the next block, and the app and results hashes.
+
+Along with an export of the state of the app at the same height, it is used to
+start a new chain from it with import-state. The node must be stopped.`,
+	RunE: exportState,
+}
+
+// ImportStateCmd writes the genesis file of a new chain starting from a state
+// exported by export-state.
+var ImportStateCmd = &cobra.Command{
+	Use:   "import-state [file]",
+	Short: "Write the genesis file of a new chain starting from a state exported by export-state",
+	Long: `Write the genesis file of a new chain starting from a state exported by
+export-state, with its validators, consensus params and app hash.
+
+The app state of the genesis, passed to InitChain, is the export of the state
+of the app at the same height, if any (see --app_state).`,
+	Args: cobra.ExactArgs(1),
+	RunE: importState,
+}
+
+func init() {
+	ExportStateCmd.Flags().Int64Var(&exportStateHeight, "height", 0, "Height to export the state after (0 for the last block)")
+	ExportStateCmd.Flags().StringVar(&exportStateOut, "out", "", "File to write the state to (default: stdout)")
+
+	ImportStateCmd.Flags().StringVar(&importStateChainID, "chain_id", "", "Chain ID of the new chain, different from the exported one")
+	ImportStateCmd.Flags().StringVar(&importStateAppState, "app_state", "", "JSON file of the app state of the new chain")
+	ImportStateCmd.Flags().StringVar(&importStateOut, "out", "", "File to write the genesis to (default: the genesis file of the node)")
+}
+
+func exportState(cmd *cobra.Command, args []string) error {
+	blockStoreDB, err := nm.DefaultDBProvider(&nm.DBContext{"blockstore", config})
+	if err != nil {
+		return err
+	}
+	defer blockStoreDB.Close()
+	stateDB, err := nm.DefaultDBProvider(&nm.DBContext{"state", config})
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+
+	e, err := sm.ExportState(stateDB, bc.NewBlockStore(blockStoreDB), exportStateHeight)
+	if err != nil {
+		return err
+	}
+	bz, err := cdc.MarshalJSONIndent(e, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the state")
+	}
+
+	if exportStateOut == "" {
+		fmt.Println(string(bz))
+		return nil
+	}
+	if err := cmn.WriteFile(exportStateOut, bz, 0644); err != nil {
+		return err
+	}
+	logger.Info("Exported the state", "file", exportStateOut, "height", e.Height, "appHash", e.AppHash)
+	return nil
+}
+
+func importState(cmd *cobra.Command, args []string) error {
+	bz, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	var e sm.Export
+	if err := cdc.UnmarshalJSON(bz, &e); err != nil {
+		return errors.Wrap(err, "failed to read the state")
+	}
+	if err := e.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "invalid state")
+	}
+	if importStateChainID == "" || importStateChainID == e.ChainID {
+		return fmt.Errorf("--chain_id of the new chain is required, and must differ from %q", e.ChainID)
+	}
+
+	b := genesis.NewBuilder(importStateChainID)
+	if err := b.SetConsensusParams(e.ConsensusParams); err != nil {
+		return err
+	}
+	for _, val := range e.Validators.Validators {
+		if err := b.AddValidator(val.PubKey, val.VotingPower, ""); err != nil {
+			return err
+		}
+	}
+	b.SetAppHash(e.AppHash)
+	if importStateAppState != "" {
+		appState, err := ioutil.ReadFile(importStateAppState)
+		if err != nil {
+			return err
+		}
+		if err := b.SetAppState(appState); err != nil {
+			return err
+		}
+	}
+
+	out := importStateOut
+	if out == "" {
+		out = config.GenesisFile()
+	}
+	if cmn.FileExists(out) {
+		return fmt.Errorf("the genesis file %s already exists", out)
+	}
+	if err := b.Write(out); err != nil {
+		return err
+	}
+	logger.Info("Wrote the genesis of the new chain", "file", out, "chainID", importStateChainID,
+		"fromChainID", e.ChainID, "fromHeight", e.Height)
+	return nil
+}
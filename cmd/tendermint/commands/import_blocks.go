@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	bc "github.com/tendermint/tendermint/blockchain"
+	cs "github.com/tendermint/tendermint/consensus"
+	nm "github.com/tendermint/tendermint/node"
+	"github.com/tendermint/tendermint/proxy"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+var (
+	importDir      string
+	importToHeight int64
+)
+
+// ImportBlocksCmd imports the blocks of an archive created by export-blocks
+// which follow the last block of this node's state.
+var ImportBlocksCmd = &cobra.Command{
+	Use:   "import-blocks",
+	Short: "Import blocks from an archive created by export-blocks",
+	Long: `Import the blocks of an archive created by export-blocks into the
+blockstore, starting after the last block of this node's state.
+
+Each block is checked against the archive manifest, verified against its
+commit and executed against the ABCI application, as it would be during fast
+sync. The transactions of the imported blocks are not indexed.`,
+	RunE: importBlocks,
+}
+
+func init() {
+	ImportBlocksCmd.Flags().StringVar(&importDir, "in", "", "Directory of the archive to import blocks from")
+	ImportBlocksCmd.Flags().Int64Var(&importToHeight, "to", 0, "Last height to import (0 for the end of the archive)")
+	ImportBlocksCmd.Flags().String("proxy_app", config.ProxyApp, "Proxy app address, or one of: 'kvstore', 'persistent_kvstore', 'counter', 'counter_serial' or 'noop' for local testing.")
+}
+
+func importBlocks(cmd *cobra.Command, args []string) error {
+	if importDir == "" {
+		return fmt.Errorf("--in is required")
+	}
+	return importArchive(importDir, importToHeight)
+}
+
+// importArchive imports the blocks of the archive directory dir up to
+// height to, after syncing the ABCI application with the node's state.
+func importArchive(dir string, to int64) error {
+	blockStoreDB, err := nm.DefaultDBProvider(&nm.DBContext{"blockstore", config})
+	if err != nil {
+		return err
+	}
+	defer blockStoreDB.Close()
+	blockStore := bc.NewBlockStore(blockStoreDB)
+
+	stateDB, err := nm.DefaultDBProvider(&nm.DBContext{"state", config})
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+
+	genDoc, err := types.GenesisDocFromFile(config.GenesisFile())
+	if err != nil {
+		return err
+	}
+	if err := genDoc.UseMerkleHasher(); err != nil {
+		return err
+	}
+	state, err := sm.LoadStateFromDBOrGenesisDoc(stateDB, genDoc)
+	if err != nil {
+		return err
+	}
+
+	proxyApp := proxy.NewAppConns(proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir(),
+		abcicli.SocketClientMaxMsgSize(config.ABCIMaxMsgSize),
+		abcicli.SocketClientCredits(config.ABCIMaxInFlight)))
+	proxyApp.SetLogger(logger.With("module", "proxy"))
+	if err := proxyApp.Start(); err != nil {
+		return fmt.Errorf("Error starting proxy app connections: %v", err)
+	}
+	defer proxyApp.Stop()
+
+	// Sync the app with the state and the blockstore before importing.
+	handshaker := cs.NewHandshaker(stateDB, state, blockStore, genDoc)
+	handshaker.SetLogger(logger.With("module", "consensus"))
+	if err := handshaker.Handshake(proxyApp); err != nil {
+		return fmt.Errorf("Error during handshake: %v", err)
+	}
+	state = sm.LoadState(stateDB)
+
+	blockExec := sm.NewBlockExecutor(stateDB, logger.With("module", "state"), proxyApp.Consensus(),
+		sm.MockMempool{}, sm.MockEvidencePool{})
+	startHeight := state.LastBlockHeight
+	state, err = bc.ImportBlocks(blockStore, blockExec, state, dir, to, logger)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Imported blocks from archive", "dir", dir,
+		"from", startHeight+1, "to", state.LastBlockHeight)
+	return nil
+}
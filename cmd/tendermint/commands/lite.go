@@ -3,12 +3,15 @@ package commands
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/tendermint/tendermint/crypto/merkle"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/lite/proxy"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/tendermint/types"
 )
 
 // LiteCmd represents the base command when called without any subcommands
@@ -32,6 +35,10 @@ var (
 	home               string
 	maxOpenConnections int
 	cacheSize          int
+	archivalNodeAddrs  string
+	signDomainVersion  int
+	signDomainTag      string
+	merkleHasher       string
 )
 
 func init() {
@@ -41,6 +48,10 @@ func init() {
 	LiteCmd.Flags().StringVar(&home, "home-dir", ".tendermint-lite", "Specify the home directory")
 	LiteCmd.Flags().IntVar(&maxOpenConnections, "max-open-connections", 900, "Maximum number of simultaneous connections (including WebSocket).")
 	LiteCmd.Flags().IntVar(&cacheSize, "cache-size", 10, "Specify the memory trust store cache size")
+	LiteCmd.Flags().StringVar(&archivalNodeAddrs, "archival-nodes", "", "Comma-separated addresses of archival nodes to retry the queries of the heights the node pruned on")
+	LiteCmd.Flags().IntVar(&signDomainVersion, "sign-domain-version", types.SignBytesVersionLegacy, "Version of the sign domain of the chain, if its genesis file sets one")
+	LiteCmd.Flags().StringVar(&signDomainTag, "sign-domain-tag", "", "Tag of the sign domain of the chain")
+	LiteCmd.Flags().StringVar(&merkleHasher, "merkle-hasher", merkle.HasherSHA256, "Hash function of the Merkle trees and the headers of the chain, sha256 or blake2b, as in its genesis file")
 }
 
 func ensureAddrHasSchemeOrDefaultToTCP(addr string) (string, error) {
@@ -68,18 +79,47 @@ func runProxy(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	domain := types.SignDomain{Version: signDomainVersion, Tag: signDomainTag}
+	if err := domain.ValidateBasic(); err != nil {
+		return cmn.ErrorWrap(err, "invalid sign domain")
+	}
+
+	hasher, err := merkle.HasherByName(merkleHasher)
+	if err != nil {
+		return err
+	}
+	if err := merkle.UseHasher(hasher); err != nil {
+		return err
+	}
+
 	// First, connect a client
 	logger.Info("Connecting to source HTTP client...")
 	node := rpcclient.NewHTTP(nodeAddr, "/websocket")
 
 	logger.Info("Constructing Verifier...")
-	cert, err := proxy.NewVerifier(chainID, home, node, logger, cacheSize)
+	cert, err := proxy.NewVerifier(chainID, domain, home, node, logger, cacheSize)
 	if err != nil {
 		return cmn.ErrorWrap(err, "constructing Verifier")
 	}
 	cert.SetLogger(logger)
 	sc := proxy.SecureClient(node, cert)
 
+	var archivalNodes []rpcclient.Client
+	for _, addr := range strings.Split(archivalNodeAddrs, ",") {
+		if addr = strings.TrimSpace(addr); addr == "" {
+			continue
+		}
+		addr, err := ensureAddrHasSchemeOrDefaultToTCP(addr)
+		if err != nil {
+			return err
+		}
+		archivalNodes = append(archivalNodes, rpcclient.NewHTTP(addr, "/websocket"))
+	}
+	if len(archivalNodes) > 0 {
+		logger.Info("Retrying the queries of the pruned heights on archival nodes", "nodes", len(archivalNodes))
+		sc = sc.WithArchivalNodes(archivalNodes...)
+	}
+
 	logger.Info("Starting proxy...")
 	err = proxy.StartProxy(sc, listenAddr, logger, maxOpenConnections)
 	if err != nil {
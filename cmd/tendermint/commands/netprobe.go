@@ -0,0 +1,308 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+var (
+	netProbeFormat      string
+	netProbeMaxNodes    int
+	netProbeConcurrency int
+	netProbeTimeout     time.Duration
+	netProbeOut         string
+)
+
+// NetProbeCmd walks the network from the RPC of a node, through the RPC of
+// its peers, and prints the topology.
+var NetProbeCmd = &cobra.Command{
+	Use:   "netprobe [rpc_address]",
+	Short: "Walk the network from the RPC of a node and print its topology",
+	Long: `Walk the network from the RPC of a node: query the /status and /net_info of
+the node, then of its peers at the rpc_address of their NodeInfo (with the IP
+they are connected from if it listens on all interfaces), and so on.
+
+The topology is printed in JSON or in the graphviz dot format, with the version,
+the latest height and the latency of the /status query of each node. The peers
+whose RPC isn't reachable are listed with the NodeInfo seen by their peers.`,
+	Args: cobra.ExactArgs(1),
+	RunE: netProbe,
+}
+
+func init() {
+	NetProbeCmd.Flags().StringVar(&netProbeFormat, "format", "json", "Output format (json or dot)")
+	NetProbeCmd.Flags().IntVar(&netProbeMaxNodes, "max_nodes", 100, "Maximum number of nodes to query")
+	NetProbeCmd.Flags().IntVar(&netProbeConcurrency, "concurrency", 10, "Number of nodes queried at the same time")
+	NetProbeCmd.Flags().DurationVar(&netProbeTimeout, "timeout", 5*time.Second, "Timeout of the queries to each node")
+	NetProbeCmd.Flags().StringVar(&netProbeOut, "out", "", "File to write the topology to (default: stdout)")
+}
+
+// NetProbeNode is a node of the topology.
+type NetProbeNode struct {
+	ID                p2p.ID `json:"id"`
+	Moniker           string `json:"moniker"`
+	Network           string `json:"network"`
+	Version           string `json:"version"`
+	RPCAddress        string `json:"rpc_address,omitempty"`
+	Reachable         bool   `json:"reachable"`
+	LatestBlockHeight int64  `json:"latest_block_height,omitempty"`
+	Latency           string `json:"latency,omitempty"`
+	Error             string `json:"error,omitempty"`
+}
+
+// NetProbeEdge is a connection between two nodes, from the one which dialed
+// it.
+type NetProbeEdge struct {
+	From p2p.ID `json:"from"`
+	To   p2p.ID `json:"to"`
+}
+
+// NetProbeTopology is the topology of the network found by netprobe.
+type NetProbeTopology struct {
+	Time  time.Time       `json:"time"`
+	Nodes []*NetProbeNode `json:"nodes"`
+	Edges []NetProbeEdge  `json:"edges"`
+}
+
+func netProbe(cmd *cobra.Command, args []string) error {
+	if netProbeFormat != "json" && netProbeFormat != "dot" {
+		return fmt.Errorf("unknown format %q, expected json or dot", netProbeFormat)
+	}
+	if netProbeConcurrency < 1 {
+		return fmt.Errorf("concurrency must be at least 1")
+	}
+
+	topology := probeNetwork(args[0], netProbeMaxNodes, netProbeConcurrency, netProbeTimeout)
+
+	var w io.Writer = os.Stdout
+	if netProbeOut != "" {
+		f, err := os.Create(netProbeOut)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // nolint: errcheck
+		w = f
+	}
+	if netProbeFormat == "dot" {
+		return topology.WriteDot(w)
+	}
+	bz, err := json.MarshalIndent(topology, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(bz))
+	return err
+}
+
+// netProbeResult is the result of the queries to the RPC of a node.
+type netProbeResult struct {
+	rpcAddress string
+	status     *ctypes.ResultStatus
+	netInfo    *ctypes.ResultNetInfo
+	latency    time.Duration
+	err        error
+}
+
+// probeNetwork queries the nodes breadth first, starting from rpcAddress, up
+// to maxNodes of them.
+func probeNetwork(rpcAddress string, maxNodes, concurrency int, timeout time.Duration) *NetProbeTopology {
+	var (
+		nodes    = make(map[p2p.ID]*NetProbeNode)
+		edges    = make(map[NetProbeEdge]struct{})
+		errs     = make(map[string]error) // by RPC address
+		queried  = map[string]bool{rpcAddress: true}
+		next     = []string{rpcAddress}
+		nQueried = 1
+	)
+
+	for len(next) > 0 {
+		results := make([]netProbeResult, len(next))
+		var (
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, concurrency)
+		)
+		for i, addr := range next {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, addr string) {
+				defer wg.Done()
+				results[i] = queryNetProbeNode(addr, timeout)
+				<-sem
+			}(i, addr)
+		}
+		wg.Wait()
+
+		next = nil
+		for _, res := range results {
+			if res.err != nil {
+				errs[res.rpcAddress] = res.err
+				continue
+			}
+
+			info := res.status.NodeInfo
+			node := netProbeNodeFromInfo(nodes, info)
+			node.RPCAddress = res.rpcAddress
+			node.Reachable = true
+			node.LatestBlockHeight = res.status.SyncInfo.LatestBlockHeight
+			node.Latency = res.latency.String()
+
+			for _, peer := range res.netInfo.Peers {
+				peerNode := netProbeNodeFromInfo(nodes, peer.NodeInfo)
+				if peer.IsOutbound {
+					edges[NetProbeEdge{From: info.ID(), To: peerNode.ID}] = struct{}{}
+				} else {
+					edges[NetProbeEdge{From: peerNode.ID, To: info.ID()}] = struct{}{}
+				}
+
+				// query each node once, at the first address found
+				if peerNode.RPCAddress != "" || nQueried >= maxNodes {
+					continue
+				}
+				addr := peerRPCAddress(peer.NodeInfo.Other.RPCAddress, peer.RemoteIP)
+				if addr == "" || queried[addr] {
+					continue
+				}
+				queried[addr] = true
+				nQueried++
+				peerNode.RPCAddress = addr
+				next = append(next, addr)
+			}
+		}
+	}
+
+	topology := &NetProbeTopology{Time: time.Now().UTC()}
+	for _, node := range nodes {
+		if err, ok := errs[node.RPCAddress]; ok {
+			node.Error = err.Error()
+			delete(errs, node.RPCAddress)
+		}
+		topology.Nodes = append(topology.Nodes, node)
+	}
+	// the starting node, if not reachable
+	for addr, err := range errs {
+		topology.Nodes = append(topology.Nodes, &NetProbeNode{RPCAddress: addr, Error: err.Error()})
+	}
+	sort.Slice(topology.Nodes, func(i, j int) bool {
+		if topology.Nodes[i].ID != topology.Nodes[j].ID {
+			return topology.Nodes[i].ID < topology.Nodes[j].ID
+		}
+		return topology.Nodes[i].RPCAddress < topology.Nodes[j].RPCAddress
+	})
+	for edge := range edges {
+		topology.Edges = append(topology.Edges, edge)
+	}
+	sort.Slice(topology.Edges, func(i, j int) bool {
+		if topology.Edges[i].From != topology.Edges[j].From {
+			return topology.Edges[i].From < topology.Edges[j].From
+		}
+		return topology.Edges[i].To < topology.Edges[j].To
+	})
+	return topology
+}
+
+// netProbeNodeFromInfo returns the node of the NodeInfo, added to the nodes if
+// new.
+func netProbeNodeFromInfo(nodes map[p2p.ID]*NetProbeNode, info p2p.DefaultNodeInfo) *NetProbeNode {
+	node, ok := nodes[info.ID()]
+	if !ok {
+		node = &NetProbeNode{ID: info.ID()}
+		nodes[info.ID()] = node
+	}
+	node.Moniker = info.Moniker
+	node.Network = info.Network
+	node.Version = info.Version
+	return node
+}
+
+// queryNetProbeNode queries the /status and /net_info of the node, timing out
+// after timeout. The queries are canceled on the timeout, so that they don't
+// outlive the call and the goroutines in flight stay bounded by the
+// concurrency of probeNetwork.
+func queryNetProbeNode(rpcAddress string, timeout time.Duration) netProbeResult {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	res := netProbeResult{rpcAddress: rpcAddress}
+	c := client.NewHTTP(rpcAddress, "/websocket")
+	start := time.Now()
+	res.status, res.err = c.Status(ctx)
+	res.latency = time.Since(start)
+	if res.err == nil {
+		res.netInfo, res.err = c.NetInfo(ctx)
+	}
+	if res.err != nil && ctx.Err() == context.DeadlineExceeded {
+		res.err = fmt.Errorf("timed out after %v", timeout)
+	}
+	return res
+}
+
+// peerRPCAddress returns the address to query the RPC of a peer at, from the
+// rpc_address of its NodeInfo and the IP it is connected from, or "" if it
+// can't be reached.
+func peerRPCAddress(rpcAddress, remoteIP string) string {
+	protocol, address := cmn.ProtocolAndAddress(rpcAddress)
+	if protocol != "tcp" && protocol != "http" && protocol != "https" {
+		return ""
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return ""
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && (ip.IsUnspecified() || ip.IsLoopback())) {
+		if remoteIP == "" {
+			return ""
+		}
+		host = remoteIP
+	}
+	return protocol + "://" + net.JoinHostPort(host, port)
+}
+
+// WriteDot writes the topology in the graphviz dot format. The nodes whose RPC
+// couldn't be queried are dashed.
+func (t *NetProbeTopology) WriteDot(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph network {\n")
+	for _, node := range t.Nodes {
+		name := string(node.ID)
+		if name == "" {
+			name = node.RPCAddress
+		}
+		label := fmt.Sprintf("%s\n%s\n%s", node.Moniker, shortNodeID(node.ID), node.Version)
+		if node.Reachable {
+			label += fmt.Sprintf("\nheight %d, %s", node.LatestBlockHeight, node.Latency)
+		}
+		style := ""
+		if !node.Reachable {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&sb, "  %q [label=%q%s];\n", name, label, style)
+	}
+	for _, edge := range t.Edges {
+		fmt.Fprintf(&sb, "  %q -> %q;\n", string(edge.From), string(edge.To))
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func shortNodeID(id p2p.ID) string {
+	if len(id) > 12 {
+		return string(id[:12])
+	}
+	return string(id)
+}
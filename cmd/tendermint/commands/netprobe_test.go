@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerRPCAddress(t *testing.T) {
+	testCases := []struct {
+		rpcAddress, remoteIP, expected string
+	}{
+		{"tcp://0.0.0.0:26657", "1.2.3.4", "tcp://1.2.3.4:26657"},
+		{"tcp://127.0.0.1:26657", "1.2.3.4", "tcp://1.2.3.4:26657"},
+		{"tcp://[::]:26657", "1.2.3.4", "tcp://1.2.3.4:26657"},
+		{"tcp://5.6.7.8:26657", "1.2.3.4", "tcp://5.6.7.8:26657"},
+		{"5.6.7.8:26657", "1.2.3.4", "tcp://5.6.7.8:26657"},
+		{"tcp://0.0.0.0:26657", "", ""},
+		{"unix:///tmp/rpc.sock", "1.2.3.4", ""},
+		{"", "1.2.3.4", ""},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, peerRPCAddress(tc.rpcAddress, tc.remoteIP), "%v", tc)
+	}
+}
+
+func TestQueryNetProbeNodeTimeout(t *testing.T) {
+	canceled := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(canceled)
+	}))
+	defer ts.Close()
+
+	res := queryNetProbeNode(ts.URL, 100*time.Millisecond)
+	require.Error(t, res.err)
+	assert.Contains(t, res.err.Error(), "timed out")
+
+	// the query doesn't outlive the timeout
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the query was not canceled on the timeout")
+	}
+}
+
+func TestNetProbeTopologyWriteDot(t *testing.T) {
+	topology := &NetProbeTopology{
+		Nodes: []*NetProbeNode{
+			{ID: "aaaaaaaaaaaaaaaaaaaa", Moniker: "a", Version: "0.31.0", Reachable: true, LatestBlockHeight: 5, Latency: "1ms"},
+			{ID: "bbbbbbbbbbbbbbbbbbbb", Moniker: `b"`, Version: "0.30.0"},
+		},
+		Edges: []NetProbeEdge{{From: "aaaaaaaaaaaaaaaaaaaa", To: "bbbbbbbbbbbbbbbbbbbb"}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, topology.WriteDot(&buf))
+	assert.Equal(t, `digraph network {
+  "aaaaaaaaaaaaaaaaaaaa" [label="a\naaaaaaaaaaaa\n0.31.0\nheight 5, 1ms"];
+  "bbbbbbbbbbbbbbbbbbbb" [label="b\"\nbbbbbbbbbbbb\n0.30.0", style=dashed];
+  "aaaaaaaaaaaaaaaaaaaa" -> "bbbbbbbbbbbbbbbbbbbb";
+}
+`, buf.String())
+}
@@ -0,0 +1,238 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/p2p/pex"
+	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+)
+
+var verifyPeerTimeout time.Duration
+
+// P2PCmd groups the commands managing the node key and the address book of
+// this node.
+var P2PCmd = &cobra.Command{
+	Use:   "p2p",
+	Short: "Manage the node key and the address book of this node",
+}
+
+// P2PShowNodeIDCmd dumps node's ID to the standard output.
+var P2PShowNodeIDCmd = &cobra.Command{
+	Use:   "show-node-id",
+	Short: "Show this node's ID",
+	RunE:  showNodeID,
+}
+
+// P2PListAddressBookCmd prints the addresses of the address book.
+var P2PListAddressBookCmd = &cobra.Command{
+	Use:   "list-address-book",
+	Short: "List the addresses of the address book",
+	Long: `List the addresses of the address book, one per line, with the bucket they
+are in ("old" once connected to, "new" otherwise), the number of failed
+attempts since the last success, and the time of the last success.`,
+	RunE: listAddressBook,
+}
+
+// P2PAddPeerCmd adds an address to the address book.
+var P2PAddPeerCmd = &cobra.Command{
+	Use:   "add-peer [id@host:port]",
+	Short: "Add an address to the address book",
+	Long: `Add an address to the address book, replacing the one of the node if any.
+
+The node must be stopped, as it saves its own address book periodically.`,
+	Args: cobra.ExactArgs(1),
+	RunE: addPeer,
+}
+
+// P2PRmPeerCmd removes an address from the address book.
+var P2PRmPeerCmd = &cobra.Command{
+	Use:   "rm-peer [id or id@host:port]",
+	Short: "Remove the address of a node from the address book",
+	Long: `Remove the address of a node from the address book.
+
+The node must be stopped, as it saves its own address book periodically.`,
+	Args: cobra.ExactArgs(1),
+	RunE: rmPeer,
+}
+
+// P2PVerifyPeerCmd dials an address and checks the node is compatible.
+var P2PVerifyPeerCmd = &cobra.Command{
+	Use:   "verify-peer [id@host:port]",
+	Short: "Dial an address and check the node is a compatible peer",
+	Long: `Dial an address, with the node key and through the upstream proxy of this
+node if any, and perform the handshakes to check the ID of the node, and that it
+is on the same network, with the same genesis file. The NodeInfo of the node is
+printed.`,
+	Args: cobra.ExactArgs(1),
+	RunE: verifyPeer,
+}
+
+func init() {
+	P2PVerifyPeerCmd.Flags().DurationVar(&verifyPeerTimeout, "timeout", 0, "Timeout of the dial (default: p2p.dial_timeout)")
+
+	P2PCmd.AddCommand(
+		P2PShowNodeIDCmd,
+		P2PListAddressBookCmd,
+		P2PAddPeerCmd,
+		P2PRmPeerCmd,
+		P2PVerifyPeerCmd,
+	)
+}
+
+// withAddrBook loads the address book, and saves it once fn returns.
+func withAddrBook(fn func(book pex.AddrBook) error) error {
+	book := pex.NewAddrBook(config.P2P.AddrBookFile(), config.P2P.AddrBookStrict)
+	book.SetLogger(logger.With("book", config.P2P.AddrBookFile()))
+	if err := book.Start(); err != nil {
+		return err
+	}
+	err := fn(book)
+	// the book is saved when stopped
+	book.Stop()
+	book.Wait()
+	return err
+}
+
+func listAddressBook(cmd *cobra.Command, args []string) error {
+	return withAddrBook(func(book pex.AddrBook) error {
+		for _, ka := range book.ListOfKnownAddresses() {
+			bucket := "new"
+			if book.IsGood(ka.Addr) {
+				bucket = "old"
+			}
+			lastSuccess := "never"
+			if !ka.LastSuccess.IsZero() {
+				lastSuccess = ka.LastSuccess.UTC().Format(time.RFC3339)
+			}
+			fmt.Printf("%v bucket=%s attempts=%d last_success=%s\n", ka.Addr, bucket, ka.Attempts, lastSuccess)
+		}
+		return nil
+	})
+}
+
+func addPeer(cmd *cobra.Command, args []string) error {
+	addr, err := p2p.NewNetAddressString(args[0])
+	if err != nil {
+		return err
+	}
+	return withAddrBook(func(book pex.AddrBook) error {
+		book.RemoveAddress(addr)
+		if err := book.AddAddress(addr, addr); err != nil {
+			return err
+		}
+		logger.Info("Added the address", "addr", addr)
+		return nil
+	})
+}
+
+func rmPeer(cmd *cobra.Command, args []string) error {
+	id := p2p.ID(args[0])
+	if i := strings.Index(args[0], "@"); i != -1 {
+		id = p2p.ID(args[0][:i])
+	}
+	return withAddrBook(func(book pex.AddrBook) error {
+		for _, ka := range book.ListOfKnownAddresses() {
+			if ka.ID() == id {
+				book.RemoveAddress(ka.Addr)
+				logger.Info("Removed the address", "addr", ka.Addr)
+				return nil
+			}
+		}
+		return fmt.Errorf("%v is not in the address book", id)
+	})
+}
+
+func verifyPeer(cmd *cobra.Command, args []string) error {
+	addr, err := p2p.NewNetAddressString(args[0])
+	if err != nil {
+		return err
+	}
+	nodeKey, err := p2p.LoadNodeKey(config.NodeKeyFile())
+	if err != nil {
+		return err
+	}
+	genDoc, err := types.GenesisDocFromFile(config.GenesisFile())
+	if err != nil {
+		return err
+	}
+	genesisBytes, err := ioutil.ReadFile(config.GenesisFile())
+	if err != nil {
+		return err
+	}
+	genesisHash := sha256.Sum256(genesisBytes)
+
+	// No channels, so that the node is compatible with any set of channels.
+	nodeInfo := p2p.DefaultNodeInfo{
+		ProtocolVersion: p2p.NewProtocolVersion(version.P2PProtocol, version.BlockProtocol, 0),
+		ID_:             nodeKey.ID(),
+		ListenAddr:      config.P2P.ListenAddress,
+		Network:         genDoc.ChainID,
+		GenesisHash:     genesisHash[:],
+		Version:         version.TMCoreSemVer,
+		Moniker:         config.Moniker,
+	}
+	if certFile := config.P2P.CertFile(); certFile != "" {
+		if nodeInfo.Certificate, err = p2p.LoadPeerCertificate(certFile); err != nil {
+			return err
+		}
+	}
+
+	transport := p2p.NewMultiplexTransport(nodeInfo, *nodeKey, p2p.MConnConfig(config.P2P))
+	if config.P2P.UpstreamProxy != "" {
+		dialer, err := p2p.NewProxyDialer(config.P2P.UpstreamProxy, config.P2P.DialTimeout)
+		if err != nil {
+			return errors.Wrap(err, "Error creating upstream proxy dialer")
+		}
+		p2p.MultiplexTransportDialer(dialer)(transport)
+	}
+
+	// Dial in the background, to time it out.
+	timeout := verifyPeerTimeout
+	if timeout == 0 {
+		timeout = config.P2P.DialTimeout
+	}
+	type dialResult struct {
+		peerInfo p2p.NodeInfo
+		err      error
+	}
+	resCh := make(chan dialResult, 1)
+	go func() {
+		peerInfo, err := transport.DialNodeInfo(*addr)
+		resCh <- dialResult{peerInfo, err}
+	}()
+	var res dialResult
+	select {
+	case res = <-resCh:
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out dialing %v after %v", addr, timeout)
+	}
+	if res.err != nil {
+		return res.err
+	}
+
+	bz, err := cdc.MarshalJSONIndent(res.peerInfo, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(bz))
+
+	peerInfo, ok := res.peerInfo.(p2p.DefaultNodeInfo)
+	if !ok {
+		return fmt.Errorf("unexpected NodeInfo type %T", res.peerInfo)
+	}
+	if len(peerInfo.GenesisHash) > 0 && !bytes.Equal(peerInfo.GenesisHash, genesisHash[:]) {
+		return fmt.Errorf("the genesis file of the node is %X, not %X", peerInfo.GenesisHash, genesisHash)
+	}
+	logger.Info("The node is a compatible peer", "addr", addr, "moniker", peerInfo.Moniker)
+	return nil
+}
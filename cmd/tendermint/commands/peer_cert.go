@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/tendermint/tendermint/crypto"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/p2p"
+)
+
+var (
+	certCAKeyFile string
+	certNodeID    string
+	certOrg       string
+	certValidFor  time.Duration
+	certOut       string
+)
+
+// GenCAKeyCmd generates the key of a certificate authority of a permissioned
+// network. It prints the list of certificate authorities to put in the
+// p2p.ca_file of the nodes.
+var GenCAKeyCmd = &cobra.Command{
+	Use:   "gen_ca_key [file]",
+	Short: "Generate the key of a certificate authority of a permissioned network",
+	Long: `Generate the key of a certificate authority of a permissioned network, and
+print the list of certificate authorities to put in the p2p.ca_file of the nodes.
+
+The key signs the peer certificates (see sign_peer_cert), and must be kept
+offline.`,
+	Args: cobra.ExactArgs(1),
+	RunE: genCAKey,
+}
+
+// SignPeerCertCmd signs the certificate of a node of a permissioned network
+// with the key of a certificate authority.
+var SignPeerCertCmd = &cobra.Command{
+	Use:   "sign_peer_cert",
+	Short: "Sign the certificate binding the ID of a node to an organization",
+	Long: `Sign the certificate binding the ID of a node to an organization, with the key
+of a certificate authority generated by gen_ca_key.
+
+The certificate is the p2p.cert_file of the node.`,
+	RunE: signPeerCert,
+}
+
+func init() {
+	SignPeerCertCmd.Flags().StringVar(&certCAKeyFile, "ca_key", "", "File of the key of the certificate authority")
+	SignPeerCertCmd.Flags().StringVar(&certNodeID, "node_id", "", "ID of the node (see show_node_id)")
+	SignPeerCertCmd.Flags().StringVar(&certOrg, "org", "", "Organization of the node")
+	SignPeerCertCmd.Flags().DurationVar(&certValidFor, "valid_for", 0, "Validity of the certificate (default: never expires)")
+	SignPeerCertCmd.Flags().StringVar(&certOut, "out", "", "File to write the certificate to (default: stdout)")
+}
+
+func genCAKey(cmd *cobra.Command, args []string) error {
+	keyFile := args[0]
+	if cmn.FileExists(keyFile) {
+		return fmt.Errorf("key at %s already exists", keyFile)
+	}
+
+	caKey, err := p2p.LoadOrGenNodeKey(keyFile)
+	if err != nil {
+		return err
+	}
+	bz, err := cdc.MarshalJSONIndent([]crypto.PubKey{caKey.PubKey()}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the certificate authorities")
+	}
+	fmt.Println(string(bz))
+	return nil
+}
+
+func signPeerCert(cmd *cobra.Command, args []string) error {
+	if certCAKeyFile == "" {
+		return errors.New("--ca_key is required")
+	}
+	caKey, err := p2p.LoadNodeKey(certCAKeyFile)
+	if err != nil {
+		return err
+	}
+
+	var expires time.Time
+	if certValidFor > 0 {
+		expires = time.Now().Add(certValidFor).UTC()
+	}
+	cert, err := p2p.NewPeerCertificate(p2p.ID(certNodeID), certOrg, expires, caKey.PrivKey)
+	if err != nil {
+		return err
+	}
+
+	if certOut == "" {
+		bz, err := cdc.MarshalJSONIndent(cert, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the certificate")
+		}
+		fmt.Println(string(bz))
+		return nil
+	}
+	if err := cert.SaveAs(certOut); err != nil {
+		return err
+	}
+	logger.Info("Signed the peer certificate", "file", certOut,
+		"node", cert.NodeID, "org", cert.Org, "expires", cert.Expires)
+	return nil
+}
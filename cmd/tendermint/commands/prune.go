@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	bc "github.com/tendermint/tendermint/blockchain"
+	nm "github.com/tendermint/tendermint/node"
+	sm "github.com/tendermint/tendermint/state"
+)
+
+var pruneRetainHeight int64
+
+// PruneCmd deletes the blocks and the states below a height, to bound the
+// disk usage of a node which doesn't need the past heights.
+var PruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete the blocks and the states (validator sets, consensus params and ABCI responses) below a height",
+	Long: `Delete the blocks and the states (validator sets, consensus params and ABCI
+responses) below a height. The validator set and consensus params in effect at
+the height are kept, so that the next heights can still be verified.
+
+The node must be stopped, and its app must have committed the height, as the
+blocks it would be replayed from on startup are deleted. The pruned blocks
+can't be served to the peers fast syncing from below the height anymore.`,
+	RunE: prune,
+}
+
+func init() {
+	PruneCmd.Flags().Int64Var(&pruneRetainHeight, "retain_height", 0, "Height to keep the blocks and states from")
+}
+
+func prune(cmd *cobra.Command, args []string) error {
+	if pruneRetainHeight <= 0 {
+		return fmt.Errorf("--retain_height is required")
+	}
+
+	blockStoreDB, err := nm.DefaultDBProvider(&nm.DBContext{"blockstore", config})
+	if err != nil {
+		return err
+	}
+	defer blockStoreDB.Close()
+	stateDB, err := nm.DefaultDBProvider(&nm.DBContext{"state", config})
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+
+	// the states must be pruned along with the blocks, at the same height
+	if lastHeight := sm.LoadState(stateDB).LastBlockHeight; pruneRetainHeight > lastHeight {
+		return fmt.Errorf("cannot prune beyond the last height %v", lastHeight)
+	}
+	pruned, err := bc.NewBlockStore(blockStoreDB).PruneBlocks(pruneRetainHeight)
+	if err != nil {
+		return err
+	}
+	if err := sm.PruneStates(stateDB, pruneRetainHeight); err != nil {
+		return err
+	}
+
+	logger.Info("Pruned blocks and states", "retainHeight", pruneRetainHeight, "blocks", pruned)
+	return nil
+}
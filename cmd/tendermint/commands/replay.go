@@ -24,3 +24,18 @@ var ReplayConsoleCmd = &cobra.Command{
 		consensus.RunReplayFile(config.BaseConfig, config.Consensus, true)
 	},
 }
+
+// ReplayRecordingCmd allows replaying the consensus messages recorded from
+// peers (see consensus.record_file), to reproduce an incident.
+var ReplayRecordingCmd = &cobra.Command{
+	Use:   "replay_recording [file]",
+	Short: "Replay the recorded consensus messages (consensus.record_file by default)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := config.Consensus.RecordFile()
+		if len(args) > 0 {
+			file = args[0]
+		}
+		consensus.RunReplayRecording(config.BaseConfig, config.Consensus, file)
+	},
+}
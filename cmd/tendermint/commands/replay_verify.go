@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	bc "github.com/tendermint/tendermint/blockchain"
+	cs "github.com/tendermint/tendermint/consensus"
+	nm "github.com/tendermint/tendermint/node"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+var replayVerifyHeightRange string
+
+// ReplayVerifyCmd re-executes the blocks of the blockstore against the app,
+// to find the first height its app hash diverges at from the chain.
+var ReplayVerifyCmd = &cobra.Command{
+	Use:   "replay-verify",
+	Short: "Re-execute stored blocks against the ABCI application and compare the app hashes",
+	Long: `Re-execute the blocks of the blockstore against the ABCI application, and
+compare the app hash after each of them with the one committed by the chain,
+reporting the first height they differ at, e.g. to debug the non-determinism of
+a new version of the app.
+
+The app must have committed the block before the first height of the range, or
+be new if it is 1. It commits the blocks re-executed. The node must be stopped.`,
+	RunE: replayVerify,
+}
+
+func init() {
+	ReplayVerifyCmd.Flags().StringVar(&replayVerifyHeightRange, "height-range", "1:",
+		"Heights of the blocks to re-execute, as from:to (to defaults to the last height)")
+	ReplayVerifyCmd.Flags().String("proxy_app", config.ProxyApp, "Proxy app address, or one of: 'kvstore', 'persistent_kvstore', 'counter', 'counter_serial' or 'noop' for local testing.")
+}
+
+// parseHeightRange parses a range of heights of the form from:to, to being 0
+// if omitted.
+func parseHeightRange(s string) (from, to int64, err error) {
+	spl := strings.SplitN(s, ":", 2)
+	if len(spl) != 2 {
+		return 0, 0, fmt.Errorf("invalid height range %q, expected from:to", s)
+	}
+	if from, err = strconv.ParseInt(spl[0], 10, 64); err != nil || from < 1 {
+		return 0, 0, fmt.Errorf("invalid first height in range %q", s)
+	}
+	if spl[1] != "" {
+		if to, err = strconv.ParseInt(spl[1], 10, 64); err != nil || to < from {
+			return 0, 0, fmt.Errorf("invalid last height in range %q", s)
+		}
+	}
+	return from, to, nil
+}
+
+func replayVerify(cmd *cobra.Command, args []string) error {
+	from, to, err := parseHeightRange(replayVerifyHeightRange)
+	if err != nil {
+		return err
+	}
+
+	blockStoreDB, err := nm.DefaultDBProvider(&nm.DBContext{"blockstore", config})
+	if err != nil {
+		return err
+	}
+	defer blockStoreDB.Close()
+	stateDB, err := nm.DefaultDBProvider(&nm.DBContext{"state", config})
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+	genDoc, err := types.GenesisDocFromFile(config.GenesisFile())
+	if err != nil {
+		return err
+	}
+	if err := genDoc.UseMerkleHasher(); err != nil {
+		return err
+	}
+
+	proxyApp := proxy.NewAppConns(proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir(),
+		abcicli.SocketClientMaxMsgSize(config.ABCIMaxMsgSize),
+		abcicli.SocketClientCredits(config.ABCIMaxInFlight)))
+	proxyApp.SetLogger(logger.With("module", "proxy"))
+	if err := proxyApp.Start(); err != nil {
+		return fmt.Errorf("Error starting proxy app connections: %v", err)
+	}
+	defer proxyApp.Stop()
+
+	err = cs.VerifyReplay(stateDB, bc.NewBlockStore(blockStoreDB), genDoc, proxyApp, from, to,
+		logger.With("module", "consensus"))
+	if err != nil {
+		return err
+	}
+	logger.Info("Verified the app hashes", "from", from, "to", to)
+	return nil
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHeightRange(t *testing.T) {
+	testCases := []struct {
+		s        string
+		from, to int64
+		ok       bool
+	}{
+		{"1:", 1, 0, true},
+		{"5:10", 5, 10, true},
+		{"5:5", 5, 5, true},
+		{"0:10", 0, 0, false},
+		{"10:5", 0, 0, false},
+		{"5", 0, 0, false},
+		{"a:b", 0, 0, false},
+	}
+	for _, tc := range testCases {
+		from, to, err := parseHeightRange(tc.s)
+		if !tc.ok {
+			assert.Error(t, err, tc.s)
+			continue
+		}
+		if assert.NoError(t, err, tc.s) {
+			assert.Equal(t, tc.from, from, tc.s)
+			assert.Equal(t, tc.to, to, tc.s)
+		}
+	}
+}
@@ -67,6 +67,9 @@ func resetFilePV(privValKeyFile, privValStateFile string, logger log.Logger) {
 }
 
 func removeAddrBook(addrBookFile string, logger log.Logger) {
+	if addrBookFile == "" {
+		return
+	}
 	if err := os.Remove(addrBookFile); err == nil {
 		logger.Info("Removed existing address book", "file", addrBookFile)
 	} else if !os.IsNotExist(err) {
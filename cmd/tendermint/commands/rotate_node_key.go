@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/p2p"
+)
+
+// RotateNodeKeyCmd replaces the node key of this node with a new one, and
+// signs the rotation with the old one. It prints the new ID of the node to the
+// standard output.
+var RotateNodeKeyCmd = &cobra.Command{
+	Use:   "rotate-node-key",
+	Short: "Replace the node key of this node, signing the rotation with the old one, and print the new ID",
+	Long: `Replace the node key of this node with a new one, and sign the rotation with the
+old one. The rotation is saved to the node_key_rotation_file and presented to
+the peers in the handshake: they move the reputation of the old ID in their
+address book to the new one, and accept the new ID when dialing the old one.
+
+The old keys are kept in the node_key_file with the .old.1 suffix, for the
+most recent one, .old.2 for the one before, and so on, up to --keep-old-keys
+of them. Stop the node before rotating its key.`,
+	RunE: rotateNodeKey,
+}
+
+var keepOldNodeKeys int
+
+func init() {
+	RotateNodeKeyCmd.Flags().IntVar(&keepOldNodeKeys, "keep-old-keys", p2p.DefaultOldNodeKeys,
+		"Number of old node keys to keep, the oldest ones beyond are removed")
+}
+
+func rotateNodeKey(cmd *cobra.Command, args []string) error {
+	nodeKeyFile := config.NodeKeyFile()
+	if !cmn.FileExists(nodeKeyFile) {
+		return fmt.Errorf("no node key at %s", nodeKeyFile)
+	}
+
+	nodeKey, rot, err := p2p.RotateNodeKey(nodeKeyFile, config.NodeKeyRotationFile(), keepOldNodeKeys)
+	if err != nil {
+		return err
+	}
+	logger.Info("Rotated the node key", "old", rot.OldID(), "new", nodeKey.ID())
+	fmt.Println(nodeKey.ID())
+	return nil
+}
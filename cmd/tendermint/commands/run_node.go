@@ -2,13 +2,17 @@ package commands
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
 	"github.com/spf13/cobra"
 
+	cmn "github.com/tendermint/tendermint/libs/common"
 	nm "github.com/tendermint/tendermint/node"
+	"github.com/tendermint/tendermint/privval"
 )
 
 // AddNodeFlags exposes some common configuration options on the command-line
@@ -22,6 +26,7 @@ func AddNodeFlags(cmd *cobra.Command) {
 
 	// node flags
 	cmd.Flags().Bool("fast_sync", config.FastSync, "Fast blockchain syncing")
+	cmd.Flags().Bool("replica", config.Replica, "Run as a read-only replica, never signing nor voting")
 
 	// abci flags
 	cmd.Flags().String("proxy_app", config.ProxyApp, "Proxy app address, or one of: 'kvstore', 'persistent_kvstore', 'counter', 'counter_serial' or 'noop' for local testing.")
@@ -31,6 +36,7 @@ func AddNodeFlags(cmd *cobra.Command) {
 	cmd.Flags().String("rpc.laddr", config.RPC.ListenAddress, "RPC listen address. Port required")
 	cmd.Flags().String("rpc.grpc_laddr", config.RPC.GRPCListenAddress, "GRPC listen address (BroadcastTx only). Port required")
 	cmd.Flags().Bool("rpc.unsafe", config.RPC.Unsafe, "Enabled unsafe rpc methods")
+	cmd.Flags().Bool("rpc.disable_unsafe", config.RPC.DisableUnsafe, "Disable the unsafe rpc methods, even if enabled")
 
 	// p2p flags
 	cmd.Flags().String("p2p.laddr", config.P2P.ListenAddress, "Node listen address. (0.0.0.0:0 means any interface, any port)")
@@ -43,15 +49,46 @@ func AddNodeFlags(cmd *cobra.Command) {
 
 	// consensus flags
 	cmd.Flags().Bool("consensus.create_empty_blocks", config.Consensus.CreateEmptyBlocks, "Set this to false to only produce blocks when there are txs or when the AppHash changes")
+	cmd.Flags().Bool("consensus.wal_disabled", config.Consensus.WalDisabled, "Run without a WAL, for test nodes which don't need to recover from a crash")
 }
 
 // NewRunNodeCmd returns the command that allows the CLI to start a node.
 // It can be used with a custom PrivValidator and in-process ABCI application.
 func NewRunNodeCmd(nodeProvider nm.NodeProvider) *cobra.Command {
+	var (
+		ephemeral, dev bool
+		devValidators  int
+		devKills       []string
+	)
 	cmd := &cobra.Command{
-		Use:   "node",
-		Short: "Run the tendermint node",
+		Use:     "node",
+		Aliases: []string{"start"},
+		Short:   "Run the tendermint node",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if devValidators > 1 {
+				if !dev {
+					return fmt.Errorf("--dev_validators requires --dev")
+				}
+				setDevConfig(cmd, config)
+				return runDevnet(config, devValidators, devKills)
+			}
+			if dev {
+				if err := setDevMode(cmd, config); err != nil {
+					return fmt.Errorf("Failed to set up the dev mode: %v", err)
+				}
+				logger.Info("Running a local devnet", "proxy_app", config.ProxyApp, "rpc", config.RPC.ListenAddress)
+			}
+			cleanup := func() {}
+			if ephemeral {
+				config.SetEphemeral()
+				var err error
+				if cleanup, err = useEphemeralPVState(); err != nil {
+					return fmt.Errorf("Failed to set up the ephemeral sign state: %v", err)
+				}
+				defer cleanup()
+				logger.Info("Running in memory, the chain is lost when the node stops")
+			}
+
 			n, err := nodeProvider(config, logger)
 			if err != nil {
 				return fmt.Errorf("Failed to create node: %v", err)
@@ -66,6 +103,7 @@ func NewRunNodeCmd(nodeProvider nm.NodeProvider) *cobra.Command {
 					if n.IsRunning() {
 						n.Stop()
 					}
+					cleanup()
 					os.Exit(1)
 				}
 			}()
@@ -81,5 +119,33 @@ func NewRunNodeCmd(nodeProvider nm.NodeProvider) *cobra.Command {
 	}
 
 	AddNodeFlags(cmd)
+	cmd.Flags().BoolVar(&ephemeral, "ephemeral", false, "Run in memory, with the stores in memdb and no WAL, for tests and short-lived devnets")
+	cmd.Flags().BoolVar(&dev, "dev", false, "Run a local devnet of one validator, generating the keys and genesis file, with the kvstore app and all the RPC methods")
+	cmd.Flags().IntVar(&devValidators, "dev_validators", 1, "Number of validators of the devnet, run in memory in this process (with --dev)")
+	cmd.Flags().StringSliceVar(&devKills, "dev_kill", nil, "Kill a validator of the devnet after a delay, and restart it after a downtime if any (validator@after[:downtime], e.g. 1@30s:10s)")
 	return cmd
 }
+
+// useEphemeralPVState points the file private validator of an ephemeral node
+// to an empty last signed state in a temporary directory, as its chain starts
+// over on each run. The state in the root, which protects the validator from
+// double signing on its real chain, is never touched. The returned function
+// removes the temporary directory.
+func useEphemeralPVState() (func(), error) {
+	if config.PrivValidatorListenAddr != "" {
+		return func() {}, nil
+	}
+	dir, err := ioutil.TempDir("", "tendermint_ephemeral")
+	if err != nil {
+		return nil, err
+	}
+	stateFile := filepath.Join(dir, filepath.Base(config.PrivValidatorStateFile()))
+	if cmn.FileExists(config.PrivValidatorKeyFile()) {
+		// otherwise the node generates the key, and saves the state here
+		pv := privval.LoadFilePVEmptyState(config.PrivValidatorKeyFile(), stateFile)
+		pv.LastSignState.Save()
+	}
+	config.PrivValidatorState = stateFile
+	logger.Info("Keeping the last signed state of the private validator in a temporary file", "file", stateFile)
+	return func() { os.RemoveAll(dir) }, nil
+}
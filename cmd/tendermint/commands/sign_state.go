@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/privval"
+)
+
+var signStateOut string
+
+// ExportSignStateCmd exports the last sign state of this node's validator, to
+// import it into the signer the validator key is migrated to.
+var ExportSignStateCmd = &cobra.Command{
+	Use:   "export_sign_state",
+	Short: "Export the last sign state (height, round, step and signature) of this node's validator",
+	Long: `Export the last sign state (height, round, step and signature) of this node's validator.
+
+The node must be stopped. The export freezes its validator: the frozen flag is
+saved in its state file, and it refuses to sign afterwards, so that it can't
+double sign once the state is imported into the new signer (see
+import_sign_state).`,
+	RunE: exportSignState,
+}
+
+// ImportSignStateCmd imports a sign state exported by export_sign_state into
+// this node's validator.
+var ImportSignStateCmd = &cobra.Command{
+	Use:   "import_sign_state [file]",
+	Short: "Import a sign state exported by export_sign_state into this node's validator",
+	Long: `Import a sign state exported by export_sign_state into this node's validator.
+
+The import fails if the state is behind the current one of the validator.`,
+	Args: cobra.ExactArgs(1),
+	RunE: importSignState,
+}
+
+func init() {
+	ExportSignStateCmd.Flags().StringVar(&signStateOut, "out", "", "File to write the sign state to (default: stdout)")
+}
+
+func loadFilePVForSignState() (*privval.FilePV, error) {
+	keyFilePath := config.PrivValidatorKeyFile()
+	if !cmn.FileExists(keyFilePath) {
+		return nil, fmt.Errorf("private validator file %s does not exist", keyFilePath)
+	}
+	return privval.LoadFilePV(keyFilePath, config.PrivValidatorStateFile()), nil
+}
+
+func exportSignState(cmd *cobra.Command, args []string) error {
+	pv, err := loadFilePVForSignState()
+	if err != nil {
+		return err
+	}
+	state, err := pv.ExportSignState(true)
+	if err != nil {
+		return err
+	}
+	bz, err := cdc.MarshalJSONIndent(state, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the sign state")
+	}
+
+	if signStateOut == "" {
+		fmt.Println(string(bz))
+		return nil
+	}
+	if err := cmn.WriteFileAtomic(signStateOut, bz, 0600); err != nil {
+		return err
+	}
+	logger.Info("Exported the sign state", "file", signStateOut,
+		"height", state.Height, "round", state.Round, "step", state.Step)
+	return nil
+}
+
+func importSignState(cmd *cobra.Command, args []string) error {
+	bz, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	var state privval.FilePVLastSignState
+	if err := cdc.UnmarshalJSON(bz, &state); err != nil {
+		return errors.Wrap(err, "failed to read the sign state")
+	}
+
+	pv, err := loadFilePVForSignState()
+	if err != nil {
+		return err
+	}
+	if err := pv.ImportSignState(state); err != nil {
+		return err
+	}
+	logger.Info("Imported the sign state", "file", config.PrivValidatorStateFile(),
+		"height", state.Height, "round", state.Round, "step", state.Step)
+	return nil
+}
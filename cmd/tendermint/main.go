@@ -14,18 +14,33 @@ import (
 func main() {
 	rootCmd := cmd.RootCmd
 	rootCmd.AddCommand(
+		cmd.BootstrapCmd,
+		cmd.ExportBlocksCmd,
+		cmd.ImportBlocksCmd,
+		cmd.ExportStateCmd,
+		cmd.ImportStateCmd,
+		cmd.PruneCmd,
+		cmd.ExportSignStateCmd,
+		cmd.ImportSignStateCmd,
 		cmd.GenValidatorCmd,
 		cmd.InitFilesCmd,
 		cmd.ProbeUpnpCmd,
 		cmd.LiteCmd,
 		cmd.ReplayCmd,
 		cmd.ReplayConsoleCmd,
+		cmd.ReplayRecordingCmd,
+		cmd.ReplayVerifyCmd,
 		cmd.ResetAllCmd,
 		cmd.ResetPrivValidatorCmd,
 		cmd.ShowValidatorCmd,
 		cmd.TestnetFilesCmd,
 		cmd.ShowNodeIDCmd,
+		cmd.P2PCmd,
+		cmd.NetProbeCmd,
 		cmd.GenNodeKeyCmd,
+		cmd.RotateNodeKeyCmd,
+		cmd.GenCAKeyCmd,
+		cmd.SignPeerCertCmd,
 		cmd.VersionCmd)
 
 	// NOTE:
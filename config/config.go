@@ -1,9 +1,13 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -19,6 +23,11 @@ const (
 	LogFormatPlain = "plain"
 	// LogFormatJSON is a format for json output
 	LogFormatJSON = "json"
+
+	// SigningLockFile is a signing lock stored in a file
+	SigningLockFile = "file"
+	// SigningLockRemote is a signing lock in the remote signer
+	SigningLockRemote = "remote"
 )
 
 // NOTE: Most of the structs & relevant comments + the
@@ -37,17 +46,25 @@ var (
 
 	defaultPrivValKeyName   = "priv_validator_key.json"
 	defaultPrivValStateName = "priv_validator_state.json"
+	defaultPrivValLockName  = "priv_validator_lock.json"
+
+	defaultNodeKeyName         = "node_key.json"
+	defaultNodeKeyRotationName = "node_key_rotation.json"
+	defaultAddrBookName        = "addrbook.json"
 
-	defaultNodeKeyName  = "node_key.json"
-	defaultAddrBookName = "addrbook.json"
+	defaultValidatorMonikersName = "validator_monikers.json"
 
 	defaultConfigFilePath   = filepath.Join(defaultConfigDir, defaultConfigFileName)
 	defaultGenesisJSONPath  = filepath.Join(defaultConfigDir, defaultGenesisJSONName)
 	defaultPrivValKeyPath   = filepath.Join(defaultConfigDir, defaultPrivValKeyName)
 	defaultPrivValStatePath = filepath.Join(defaultDataDir, defaultPrivValStateName)
+	defaultPrivValLockPath  = filepath.Join(defaultDataDir, defaultPrivValLockName)
+
+	defaultNodeKeyPath         = filepath.Join(defaultConfigDir, defaultNodeKeyName)
+	defaultNodeKeyRotationPath = filepath.Join(defaultConfigDir, defaultNodeKeyRotationName)
+	defaultAddrBookPath        = filepath.Join(defaultConfigDir, defaultAddrBookName)
 
-	defaultNodeKeyPath  = filepath.Join(defaultConfigDir, defaultNodeKeyName)
-	defaultAddrBookPath = filepath.Join(defaultConfigDir, defaultAddrBookName)
+	defaultValidatorMonikersPath = filepath.Join(defaultConfigDir, defaultValidatorMonikersName)
 )
 
 var (
@@ -105,6 +122,19 @@ func (cfg *Config) SetRoot(root string) *Config {
 	return cfg
 }
 
+// SetEphemeral configures the node to run in memory: the stores in memdb, no
+// consensus nor mempool WAL, and an address book which isn't saved. The keys
+// and the genesis file are still read from the root, and the last signed state
+// of the private validator written to it: `tendermint node --ephemeral` keeps
+// it in a temporary file instead.
+func (cfg *Config) SetEphemeral() *Config {
+	cfg.DBBackend = "memdb"
+	cfg.Consensus.WalDisabled = true
+	cfg.Mempool.WalPath = ""
+	cfg.P2P.AddrBook = ""
+	return cfg
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *Config) ValidateBasic() error {
@@ -123,6 +153,9 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.Consensus.ValidateBasic(); err != nil {
 		return errors.Wrap(err, "Error in [consensus] section")
 	}
+	if err := cfg.TxIndex.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "Error in [tx_index] section")
+	}
 	return errors.Wrap(
 		cfg.Instrumentation.ValidateBasic(),
 		"Error in [instrumentation] section",
@@ -153,6 +186,12 @@ type BaseConfig struct {
 	// and verifying their commits
 	FastSync bool `mapstructure:"fast_sync"`
 
+	// Run as a read-only replica: sync and gossip the blocks and serve the
+	// RPC, but never sign nor vote, even if the validator key is in the
+	// validator set, e.g. for RPC replicas set up from a validator's backup.
+	// The priv_validator_laddr and priv_validator_lock are ignored.
+	Replica bool `mapstructure:"replica"`
+
 	// Database backend: leveldb | memdb | cleveldb
 	DBBackend string `mapstructure:"db_backend"`
 
@@ -165,9 +204,17 @@ type BaseConfig struct {
 	// Output format: 'plain' (colored text) or 'json'
 	LogFormat string `mapstructure:"log_format"`
 
+	// Number of info and error lines kept in memory for each module, served by
+	// /unsafe_logs if the unsafe RPC commands are enabled. 0 disables it.
+	LogBufferLines int `mapstructure:"log_buffer_lines"`
+
 	// Path to the JSON file containing the initial validator set and other meta data
 	Genesis string `mapstructure:"genesis_file"`
 
+	// Expected SHA-256 hash of the genesis file, hex encoded. The node fails
+	// to start if the genesis file has another hash. Empty to not check it.
+	GenesisHash string `mapstructure:"genesis_hash"`
+
 	// Path to the JSON file containing the private key to use as a validator in the consensus protocol
 	PrivValidatorKey string `mapstructure:"priv_validator_key_file"`
 
@@ -175,40 +222,123 @@ type BaseConfig struct {
 	PrivValidatorState string `mapstructure:"priv_validator_state_file"`
 
 	// TCP or UNIX socket address for Tendermint to listen on for
-	// connections from an external PrivValidator process
+	// connections from an external PrivValidator process.
+	// Comma separated addresses of several PrivValidator processes, in
+	// priority order, fail over to the next one when the active one is
+	// unhealthy for priv_validator_failover_timeout.
 	PrivValidatorListenAddr string `mapstructure:"priv_validator_laddr"`
 
+	// How long the active external PrivValidator process must be unhealthy
+	// before failing over to another one. It should be longer than
+	// priv_validator_lock_lease.
+	PrivValidatorFailoverTimeout time.Duration `mapstructure:"priv_validator_failover_timeout"`
+
+	// Signing lock preventing two nodes from signing with the validator key
+	// at the same time: "" (none), "file" (lease in a file shared by the
+	// nodes) or "remote" (lease in the remote signer, see
+	// priv_validator_laddr). The node fails to start if another node holds
+	// the lock.
+	PrivValidatorLock string `mapstructure:"priv_validator_lock"`
+
+	// Path to the file of the "file" signing lock
+	PrivValidatorLockPath string `mapstructure:"priv_validator_lock_file"`
+
+	// Lease of the signing lock, renewed every third of it. The node stops
+	// signing when it expires, until it is renewed.
+	PrivValidatorLockLease time.Duration `mapstructure:"priv_validator_lock_lease"`
+
 	// A JSON file containing the private key to use for p2p authenticated encryption
 	NodeKey string `mapstructure:"node_key_file"`
 
+	// A JSON file containing the rotation of the node key, signed by the
+	// previous key and presented to the peers, written by rotate-node-key.
+	// Ignored if it doesn't exist.
+	NodeKeyRotation string `mapstructure:"node_key_rotation_file"`
+
+	// Path to the JSON file mapping the hex addresses of the validators to
+	// their monikers, shown in the consensus logs, /consensus_state and
+	// /validators along with the names of the genesis validators. The file is
+	// optional, and reloaded when it changes.
+	ValidatorMonikers string `mapstructure:"validator_monikers_file"`
+
 	// Mechanism to connect to the ABCI application: socket | grpc
 	ABCI string `mapstructure:"abci"`
 
+	// Maximum size of the messages exchanged with the ABCI application over
+	// a socket, in bytes. The application may negotiate a smaller one.
+	ABCIMaxMsgSize int `mapstructure:"abci_max_msg_size"`
+
+	// Maximum number of requests sent to the ABCI application over a socket
+	// before receiving their responses, 0 for unlimited. The application may
+	// negotiate fewer.
+	ABCIMaxInFlight int `mapstructure:"abci_max_in_flight"`
+
 	// TCP or UNIX socket address for the profiling server to listen on
+	// Deprecated: use rpc.pprof_laddr.
 	ProfListenAddress string `mapstructure:"prof_laddr"`
 
 	// If true, query the ABCI app on connecting to a new peer
 	// so the app can decide if we should keep the connection or not
 	FilterPeers bool `mapstructure:"filter_peers"` // false
+
+	// Memory budget of the node, in bytes. If set, the mempool, the number of
+	// peers, the event queues of the websocket clients and the db caches are
+	// sized to fit in it, and the RPC server rejects the requests while the
+	// memory used exceeds it.
+	// 0 - unlimited.
+	MemoryBudget int64 `mapstructure:"memory_budget"`
+
+	// Directory to write the crash reports to, when a reactor or an RPC
+	// handler panics. Empty disables the reports.
+	CrashDir string `mapstructure:"crash_dir"`
+
+	// If true, the crash reports include the last info and error lines
+	// logged, which are then kept in memory as they are logged.
+	CrashReportLogs bool `mapstructure:"crash_report_logs"`
+
+	// If true, the node shuts down when a reactor or an RPC handler panics,
+	// instead of stopping the peer or failing the request.
+	CrashShutdown bool `mapstructure:"crash_shutdown"`
+
+	// If true, check invariants of the state machine at each block (the
+	// validator set hashes against the header, the continuity of the app
+	// hash, the consistency of the WAL with the block store...), and panic
+	// with diagnostics at the first inconsistency, instead of failing blocks
+	// later. For debugging: it slows the blocks down.
+	CheckInvariants bool `mapstructure:"check_invariants"`
 }
 
 // DefaultBaseConfig returns a default base configuration for a Tendermint node
 func DefaultBaseConfig() BaseConfig {
 	return BaseConfig{
-		Genesis:            defaultGenesisJSONPath,
-		PrivValidatorKey:   defaultPrivValKeyPath,
-		PrivValidatorState: defaultPrivValStatePath,
-		NodeKey:            defaultNodeKeyPath,
-		Moniker:            defaultMoniker,
-		ProxyApp:           "tcp://127.0.0.1:26658",
-		ABCI:               "socket",
-		LogLevel:           DefaultPackageLogLevels(),
-		LogFormat:          LogFormatPlain,
-		ProfListenAddress:  "",
-		FastSync:           true,
-		FilterPeers:        false,
-		DBBackend:          "leveldb",
-		DBPath:             "data",
+		Genesis:                      defaultGenesisJSONPath,
+		PrivValidatorKey:             defaultPrivValKeyPath,
+		PrivValidatorState:           defaultPrivValStatePath,
+		PrivValidatorLockPath:        defaultPrivValLockPath,
+		PrivValidatorLockLease:       10 * time.Second,
+		PrivValidatorFailoverTimeout: 30 * time.Second,
+		NodeKey:                      defaultNodeKeyPath,
+		NodeKeyRotation:              defaultNodeKeyRotationPath,
+		ValidatorMonikers:            defaultValidatorMonikersPath,
+		Moniker:                      defaultMoniker,
+		ProxyApp:                     "tcp://127.0.0.1:26658",
+		ABCI:                         "socket",
+		ABCIMaxMsgSize:               104857600, // 100MB
+		ABCIMaxInFlight:              256,
+		LogLevel:                     DefaultPackageLogLevels(),
+		LogFormat:                    LogFormatPlain,
+		LogBufferLines:               1000,
+		ProfListenAddress:            "",
+		FastSync:                     true,
+		Replica:                      false,
+		FilterPeers:                  false,
+		DBBackend:                    "leveldb",
+		DBPath:                       "data",
+		MemoryBudget:                 0,
+		CrashDir:                     "data/crash",
+		CrashReportLogs:              false,
+		CrashShutdown:                false,
+		CheckInvariants:              false,
 	}
 }
 
@@ -241,6 +371,11 @@ func (cfg BaseConfig) PrivValidatorStateFile() string {
 	return rootify(cfg.PrivValidatorState, cfg.RootDir)
 }
 
+// PrivValidatorLockFile returns the full path to the file of the signing lock
+func (cfg BaseConfig) PrivValidatorLockFile() string {
+	return rootify(cfg.PrivValidatorLockPath, cfg.RootDir)
+}
+
 // OldPrivValidatorFile returns the full path of the priv_validator.json from pre v0.28.0.
 // TODO: eventually remove.
 func (cfg BaseConfig) OldPrivValidatorFile() string {
@@ -252,11 +387,32 @@ func (cfg BaseConfig) NodeKeyFile() string {
 	return rootify(cfg.NodeKey, cfg.RootDir)
 }
 
+// NodeKeyRotationFile returns the full path to the node_key_rotation.json
+// file
+func (cfg BaseConfig) NodeKeyRotationFile() string {
+	return rootify(cfg.NodeKeyRotation, cfg.RootDir)
+}
+
+// ValidatorMonikersFile returns the full path to the validator_monikers.json
+// file
+func (cfg BaseConfig) ValidatorMonikersFile() string {
+	return rootify(cfg.ValidatorMonikers, cfg.RootDir)
+}
+
 // DBDir returns the full path to the database directory
 func (cfg BaseConfig) DBDir() string {
 	return rootify(cfg.DBPath, cfg.RootDir)
 }
 
+// CrashReportDir returns the full path to the crash reports directory, or ""
+// if the reports are disabled
+func (cfg BaseConfig) CrashReportDir() string {
+	if cfg.CrashDir == "" {
+		return ""
+	}
+	return rootify(cfg.CrashDir, cfg.RootDir)
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg BaseConfig) ValidateBasic() error {
@@ -265,6 +421,45 @@ func (cfg BaseConfig) ValidateBasic() error {
 	default:
 		return errors.New("unknown log_format (must be 'plain' or 'json')")
 	}
+	switch cfg.PrivValidatorLock {
+	case "", SigningLockFile:
+	case SigningLockRemote:
+		if cfg.PrivValidatorListenAddr == "" {
+			return errors.New("the remote signing lock requires priv_validator_laddr")
+		}
+	default:
+		return errors.New("unknown priv_validator_lock (must be '', 'file' or 'remote')")
+	}
+	if cfg.PrivValidatorLock != "" && cfg.PrivValidatorLockLease <= 0 {
+		return errors.New("priv_validator_lock_lease must be positive")
+	}
+	if cfg.GenesisHash != "" {
+		if hash, err := hex.DecodeString(cfg.GenesisHash); err != nil || len(hash) != sha256.Size {
+			return errors.New("genesis_hash must be a hex encoded SHA-256 hash")
+		}
+	}
+	if cfg.ABCIMaxMsgSize <= 0 {
+		return errors.New("abci_max_msg_size must be positive")
+	}
+	if cfg.ABCIMaxInFlight < 0 {
+		return errors.New("abci_max_in_flight can't be negative")
+	}
+	if cfg.PrivValidatorFailoverTimeout < 0 {
+		return errors.New("priv_validator_failover_timeout can't be negative")
+	}
+	if cfg.PrivValidatorLock == SigningLockRemote && cfg.PrivValidatorFailoverTimeout <= cfg.PrivValidatorLockLease &&
+		strings.Contains(cfg.PrivValidatorListenAddr, ",") {
+		return errors.New("priv_validator_failover_timeout must be longer than priv_validator_lock_lease")
+	}
+	if cfg.LogBufferLines < 0 {
+		return errors.New("log_buffer_lines can't be negative")
+	}
+	if cfg.MemoryBudget < 0 {
+		return errors.New("memory_budget can't be negative")
+	}
+	if cfg.MemoryBudget > 0 && cfg.MemoryBudget < MinMemoryBudget {
+		return fmt.Errorf("memory_budget must be at least %d bytes", MinMemoryBudget)
+	}
 	return nil
 }
 
@@ -315,6 +510,11 @@ type RPCConfig struct {
 	// Activate unsafe RPC commands like /dial_persistent_peers and /unsafe_flush_mempool
 	Unsafe bool `mapstructure:"unsafe"`
 
+	// If true, the unsafe RPC commands are disabled, even if unsafe is set
+	// (e.g. by the --rpc.unsafe flag), for public RPC servers.
+	// NOTE: the binaries built with the nounsafe tag don't have them at all.
+	DisableUnsafe bool `mapstructure:"disable_unsafe"`
+
 	// Maximum number of simultaneous connections (including WebSocket).
 	// Does not include gRPC connections. See grpc_max_open_connections
 	// If you want to accept a larger number than the default, make sure
@@ -323,6 +523,97 @@ type RPCConfig struct {
 	// Should be < {ulimit -Sn} - {MaxNumInboundPeers} - {MaxNumOutboundPeers} - {N of wal, db and other open files}
 	// 1024 - 40 - 10 - 50 = 924 = ~900
 	MaxOpenConnections int `mapstructure:"max_open_connections"`
+
+	// Maximum number of simultaneous WebSocket connections from a single IP.
+	// 0 - unlimited.
+	// NOTE: all the connections made through a reverse proxy have the IP of
+	// the proxy.
+	MaxWSConnectionsPerIP int `mapstructure:"max_ws_connections_per_ip"`
+
+	// Maximum number of unique queries a given WebSocket client can
+	// /subscribe to.
+	// 0 - unlimited.
+	MaxSubscriptionsPerClient int `mapstructure:"max_subscriptions_per_client"`
+
+	// Maximum number of WebSocket clients at a time, over all the IPs, which
+	// along with max_subscriptions_per_client bounds the subscriptions. When
+	// reached, a new client is handled by subscription_clients_eviction.
+	// 0 - unlimited.
+	MaxSubscriptionClients int `mapstructure:"max_subscription_clients"`
+
+	// What to do with a new WebSocket client over max_subscription_clients:
+	// "reject" - reject it,
+	// "oldest" - evict the oldest client for it,
+	// "lru" - evict the client which sent a request the longest time ago for it.
+	SubscriptionClientsEviction string `mapstructure:"subscription_clients_eviction"`
+
+	// Maximum number of conditions of a /subscribe query, counting the
+	// conditions of each of the queries OR'd together.
+	// 0 - unlimited.
+	MaxQueryConditions int `mapstructure:"max_query_conditions"`
+
+	// Maximum number of events of a /subscribe in the ack mode kept until the
+	// client acks them. The subscription is dropped when it exceeds it.
+	// 0 - the ack mode is disabled.
+	MaxUnackedEvents int `mapstructure:"max_unacked_events"`
+
+	// Maximum number of /subscribe in the ack mode, over all the clients. They
+	// outlive the connections of the clients, until unsubscribed or dropped.
+	// 0 - unlimited.
+	MaxAckSubscriptions int `mapstructure:"max_ack_subscriptions"`
+
+	// Number of the last events of a query buffered for the /events long
+	// polling, from the first /events of the query until it isn't polled for
+	// EventsBufferTimeout.
+	// 0 - /events is disabled.
+	EventsBufferSize int `mapstructure:"events_buffer_size"`
+
+	// How long the events of a query stay buffered after its last /events.
+	EventsBufferTimeout time.Duration `mapstructure:"events_buffer_timeout"`
+
+	// Maximum number of queries buffered for /events at a time.
+	// 0 - unlimited.
+	MaxEventsBuffers int `mapstructure:"max_events_buffers"`
+
+	// Fraction of the HTTP requests logged, with their endpoint, status and
+	// duration, from 0 (none) to 1 (all), at the info level of the rpc-server
+	// module.
+	AccessLogSampleRate float64 `mapstructure:"access_log_sample_rate"`
+
+	// HTTP requests taking longer are logged as slow, at the error level,
+	// sampled or not.
+	// 0 - the slow requests are not logged.
+	SlowRequestThreshold time.Duration `mapstructure:"slow_request_threshold"`
+
+	// The slow_request_threshold of some endpoints, as "endpoint=duration",
+	// e.g. "broadcast_tx_commit=15s".
+	SlowRequestThresholds []string `mapstructure:"slow_request_thresholds"`
+
+	// Host the RPC of the node is served on, matched against the Host header
+	// of the requests, without the port, so that one RPC server can front
+	// several nodes of the process (see the RPCTenants node option).
+	// Any host if empty.
+	TenantHost string `mapstructure:"tenant_host"`
+
+	// URL path prefix the RPC of the node is served under, e.g. "/chain-a".
+	// None if empty.
+	TenantPrefix string `mapstructure:"tenant_prefix"`
+
+	// Tokens accepted in an "Authorization: Bearer <token>" header of the
+	// requests. The requests aren't authenticated if empty.
+	AuthTokens []string `mapstructure:"auth_tokens"`
+
+	// Maximum number of requests per second, with bursts of up to rate_burst
+	// requests. Over it, the requests are rejected with an HTTP 429 error.
+	// 0 - unlimited.
+	RateLimit float64 `mapstructure:"rate_limit"`
+	RateBurst int     `mapstructure:"rate_burst"`
+
+	// Address to serve the net/http/pprof profiles (CPU, heap, goroutine,
+	// mutex...) on, e.g. "localhost:6060". Empty to disable.
+	// NOTE: the profiles expose the internals of the node, and collecting them
+	// slows it down: don't make it public.
+	PprofListenAddress string `mapstructure:"pprof_laddr"`
 }
 
 // DefaultRPCConfig returns a default configuration for the RPC server
@@ -336,7 +627,30 @@ func DefaultRPCConfig() *RPCConfig {
 		GRPCMaxOpenConnections: 900,
 
 		Unsafe:             false,
+		DisableUnsafe:      false,
 		MaxOpenConnections: 900,
+
+		MaxWSConnectionsPerIP:       0,
+		MaxSubscriptionsPerClient:   5,
+		MaxSubscriptionClients:      100,
+		SubscriptionClientsEviction: "reject",
+		MaxQueryConditions:          10,
+		MaxUnackedEvents:            1000,
+		MaxAckSubscriptions:         100,
+		EventsBufferSize:            100,
+		EventsBufferTimeout:         30 * time.Second,
+		MaxEventsBuffers:            100,
+		AccessLogSampleRate:         0,
+		SlowRequestThreshold:        0,
+		SlowRequestThresholds:       []string{},
+
+		TenantHost:   "",
+		TenantPrefix: "",
+		AuthTokens:   []string{},
+		RateLimit:    0,
+		RateBurst:    0,
+
+		PprofListenAddress: "",
 	}
 }
 
@@ -358,14 +672,89 @@ func (cfg *RPCConfig) ValidateBasic() error {
 	if cfg.MaxOpenConnections < 0 {
 		return errors.New("max_open_connections can't be negative")
 	}
+	if cfg.MaxWSConnectionsPerIP < 0 {
+		return errors.New("max_ws_connections_per_ip can't be negative")
+	}
+	if cfg.MaxSubscriptionsPerClient < 0 {
+		return errors.New("max_subscriptions_per_client can't be negative")
+	}
+	if cfg.MaxSubscriptionClients < 0 {
+		return errors.New("max_subscription_clients can't be negative")
+	}
+	switch cfg.SubscriptionClientsEviction {
+	case "reject", "oldest", "lru":
+	default:
+		return fmt.Errorf("unknown subscription_clients_eviction %q, expected \"reject\", \"oldest\" or \"lru\"",
+			cfg.SubscriptionClientsEviction)
+	}
+	if cfg.MaxQueryConditions < 0 {
+		return errors.New("max_query_conditions can't be negative")
+	}
+	if cfg.MaxUnackedEvents < 0 {
+		return errors.New("max_unacked_events can't be negative")
+	}
+	if cfg.MaxAckSubscriptions < 0 {
+		return errors.New("max_ack_subscriptions can't be negative")
+	}
+	if cfg.EventsBufferSize < 0 {
+		return errors.New("events_buffer_size can't be negative")
+	}
+	if cfg.EventsBufferTimeout < 0 {
+		return errors.New("events_buffer_timeout can't be negative")
+	}
+	if cfg.MaxEventsBuffers < 0 {
+		return errors.New("max_events_buffers can't be negative")
+	}
+	if cfg.AccessLogSampleRate < 0 || cfg.AccessLogSampleRate > 1 {
+		return errors.New("access_log_sample_rate must be between 0 and 1")
+	}
+	if cfg.SlowRequestThreshold < 0 {
+		return errors.New("slow_request_threshold can't be negative")
+	}
+	if _, err := cfg.SlowRequestThresholdsByEndpoint(); err != nil {
+		return err
+	}
+	if cfg.TenantPrefix != "" && (!strings.HasPrefix(cfg.TenantPrefix, "/") || strings.HasSuffix(cfg.TenantPrefix, "/")) {
+		return errors.New("tenant_prefix must start with a slash and not end with one")
+	}
+	if cfg.RateLimit < 0 {
+		return errors.New("rate_limit can't be negative")
+	}
+	if cfg.RateLimit > 0 && cfg.RateBurst < 1 {
+		return errors.New("rate_burst must be positive with a rate_limit")
+	}
 	return nil
 }
 
+// SlowRequestThresholdsByEndpoint returns the slow_request_thresholds by
+// endpoint.
+func (cfg *RPCConfig) SlowRequestThresholdsByEndpoint() (map[string]time.Duration, error) {
+	thresholds := make(map[string]time.Duration, len(cfg.SlowRequestThresholds))
+	for _, s := range cfg.SlowRequestThresholds {
+		parts := strings.SplitN(s, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid slow_request_thresholds entry %q, expected \"endpoint=duration\"", s)
+		}
+		threshold, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("invalid slow_request_thresholds duration in %q", s)
+		}
+		thresholds[strings.TrimSpace(parts[0])] = threshold
+	}
+	return thresholds, nil
+}
+
 // IsCorsEnabled returns true if cross-origin resource sharing is enabled.
 func (cfg *RPCConfig) IsCorsEnabled() bool {
 	return len(cfg.CORSAllowedOrigins) != 0
 }
 
+// IsUnsafeEnabled returns true if the unsafe RPC commands are activated and
+// not disabled.
+func (cfg *RPCConfig) IsUnsafeEnabled() bool {
+	return cfg.Unsafe && !cfg.DisableUnsafe
+}
+
 //-----------------------------------------------------------------------------
 // P2PConfig
 
@@ -386,10 +775,15 @@ type P2PConfig struct {
 	// Comma separated list of nodes to keep persistent connections to
 	PersistentPeers string `mapstructure:"persistent_peers"`
 
+	// Semicolon separated list of groups of nodes, of which at least K nodes
+	// of each group are kept connected, in the form "K:addr,addr;K:addr,...".
+	// Useful for sentries which don't need connections to all validators.
+	PersistentPeerGroups string `mapstructure:"persistent_peer_groups"`
+
 	// UPNP port forwarding
 	UPNP bool `mapstructure:"upnp"`
 
-	// Path to address book
+	// Path to address book ("" - kept in memory only)
 	AddrBook string `mapstructure:"addr_book_file"`
 
 	// Set true for strict address routability rules
@@ -414,6 +808,13 @@ type P2PConfig struct {
 	// Rate at which packets can be received, in bytes/second
 	RecvRate int64 `mapstructure:"recv_rate"`
 
+	// Compression of the large messages (block parts, txs) sent to the peers
+	// with the same compression: "none" or "snappy"
+	Compression string `mapstructure:"compression"`
+
+	// Minimum size of the messages compressed, in bytes
+	CompressionMinSize int `mapstructure:"compression_min_size"`
+
 	// Set true to enable the peer-exchange reactor
 	PexReactor bool `mapstructure:"pex"`
 
@@ -434,6 +835,29 @@ type P2PConfig struct {
 	HandshakeTimeout time.Duration `mapstructure:"handshake_timeout"`
 	DialTimeout      time.Duration `mapstructure:"dial_timeout"`
 
+	// SOCKS5 proxy to route all outbound connections through
+	// (e.g. "socks5://127.0.0.1:9050" for Tor). Required to dial .onion peers.
+	UpstreamProxy string `mapstructure:"upstream_proxy"`
+
+	// Dial the persistent peers with the Noise IK handshake instead of the
+	// STS one, once their key is pinned by a first connection. Inbound
+	// connections accept both, so enable it only when the persistent peers
+	// support it.
+	NoiseHandshake bool `mapstructure:"noise_handshake"`
+
+	// Path to a JSON list of the public keys of the certificate authorities
+	// of a permissioned network. If set, peers must present a certificate
+	// signed by one of them, binding their node ID to an organization.
+	CA string `mapstructure:"ca_file"`
+
+	// Path to the certificate of this node, presented to the peers.
+	Cert string `mapstructure:"cert_file"`
+
+	// Warn when more than this ratio of the peers run a newer p2p or block
+	// protocol version, i.e. this node is likely behind an upgrade (0 to
+	// disable)
+	NewerPeersWarnRatio float64 `mapstructure:"newer_peers_warn_ratio"`
+
 	// Testing params.
 	// Force dial to fail
 	TestDialFail bool `mapstructure:"test_dial_fail"`
@@ -456,11 +880,18 @@ func DefaultP2PConfig() *P2PConfig {
 		MaxPacketMsgPayloadSize: 1024,    // 1 kB
 		SendRate:                5120000, // 5 mB/s
 		RecvRate:                5120000, // 5 mB/s
+		Compression:             "none",
+		CompressionMinSize:      1024, // 1 kB
 		PexReactor:              true,
 		SeedMode:                false,
 		AllowDuplicateIP:        false,
 		HandshakeTimeout:        20 * time.Second,
 		DialTimeout:             3 * time.Second,
+		UpstreamProxy:           "",
+		NoiseHandshake:          false,
+		CA:                      "",
+		Cert:                    "",
+		NewerPeersWarnRatio:     0.5,
 		TestDialFail:            false,
 		TestFuzz:                false,
 		TestFuzzConfig:          DefaultFuzzConnConfig(),
@@ -476,11 +907,33 @@ func TestP2PConfig() *P2PConfig {
 	return cfg
 }
 
-// AddrBookFile returns the full path to the address book
+// AddrBookFile returns the full path to the address book, empty if it is kept
+// in memory only.
 func (cfg *P2PConfig) AddrBookFile() string {
+	if cfg.AddrBook == "" {
+		return ""
+	}
 	return rootify(cfg.AddrBook, cfg.RootDir)
 }
 
+// CAFile returns the full path to the certificate authorities file, empty if
+// the network is not permissioned.
+func (cfg *P2PConfig) CAFile() string {
+	if cfg.CA == "" {
+		return ""
+	}
+	return rootify(cfg.CA, cfg.RootDir)
+}
+
+// CertFile returns the full path to the certificate of the node, empty if it
+// has none.
+func (cfg *P2PConfig) CertFile() string {
+	if cfg.Cert == "" {
+		return ""
+	}
+	return rootify(cfg.Cert, cfg.RootDir)
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *P2PConfig) ValidateBasic() error {
@@ -502,6 +955,26 @@ func (cfg *P2PConfig) ValidateBasic() error {
 	if cfg.RecvRate < 0 {
 		return errors.New("recv_rate can't be negative")
 	}
+	switch cfg.Compression {
+	case "none", "snappy":
+	default:
+		return fmt.Errorf("unknown compression %q, must be \"none\" or \"snappy\"", cfg.Compression)
+	}
+	if cfg.CompressionMinSize < 0 {
+		return errors.New("compression_min_size can't be negative")
+	}
+	if cfg.NewerPeersWarnRatio < 0 || cfg.NewerPeersWarnRatio > 1 {
+		return errors.New("newer_peers_warn_ratio must be between 0 and 1")
+	}
+	if cfg.UpstreamProxy != "" {
+		u, err := url.Parse(cfg.UpstreamProxy)
+		if err != nil {
+			return errors.Wrap(err, "invalid upstream_proxy")
+		}
+		if u.Scheme != "socks5" {
+			return errors.New("upstream_proxy must be a socks5:// URL")
+		}
+	}
 	return nil
 }
 
@@ -536,6 +1009,11 @@ type MempoolConfig struct {
 	WalPath   string `mapstructure:"wal_dir"`
 	Size      int    `mapstructure:"size"`
 	CacheSize int    `mapstructure:"cache_size"`
+
+	// Chaos option for soak testing, never to be set in production: evict
+	// this ratio of the txs left in the mempool after each block, at random
+	// (0 - disabled).
+	ChaosEvictRatio float64 `mapstructure:"chaos_evict_ratio"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool
@@ -546,8 +1024,9 @@ func DefaultMempoolConfig() *MempoolConfig {
 		WalPath:   "",
 		// Each signature verification takes .5ms, size reduced until we implement
 		// ABCI Recheck
-		Size:      5000,
-		CacheSize: 10000,
+		Size:            5000,
+		CacheSize:       10000,
+		ChaosEvictRatio: 0,
 	}
 }
 
@@ -577,6 +1056,9 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.CacheSize < 0 {
 		return errors.New("cache_size can't be negative")
 	}
+	if cfg.ChaosEvictRatio < 0 || cfg.ChaosEvictRatio > 1 {
+		return errors.New("chaos_evict_ratio must be between 0 and 1")
+	}
 	return nil
 }
 
@@ -590,6 +1072,18 @@ type ConsensusConfig struct {
 	WalPath string `mapstructure:"wal_file"`
 	walFile string // overrides WalPath if set
 
+	// Run without a WAL, and so without its fsyncs, for test nodes and
+	// devnets which don't need to recover from a crash.
+	WalDisabled bool `mapstructure:"wal_disabled"`
+
+	// Record the consensus messages received from peers, with the time they
+	// were received, to a rolling group of files ("" - disabled). The
+	// recording can be replayed with `tendermint replay_recording`.
+	RecordPath string `mapstructure:"record_file"`
+	// Maximum total size of the recording files, in bytes. The oldest files
+	// are deleted to stay under it.
+	RecordMaxSize int64 `mapstructure:"record_max_size"`
+
 	TimeoutPropose        time.Duration `mapstructure:"timeout_propose"`
 	TimeoutProposeDelta   time.Duration `mapstructure:"timeout_propose_delta"`
 	TimeoutPrevote        time.Duration `mapstructure:"timeout_prevote"`
@@ -601,22 +1095,62 @@ type ConsensusConfig struct {
 	// Make progress as soon as we have all the precommits (as if TimeoutCommit = 0)
 	SkipTimeoutCommit bool `mapstructure:"skip_timeout_commit"`
 
+	// Adjust TimeoutPropose and TimeoutCommit to the latencies of the recent
+	// proposals and precommits: twice the longest of them, between
+	// TimeoutProposeMin and TimeoutPropose, and between TimeoutCommitMin and
+	// TimeoutCommit.
+	AdaptiveTimeouts  bool          `mapstructure:"adaptive_timeouts"`
+	TimeoutProposeMin time.Duration `mapstructure:"timeout_propose_min"`
+	TimeoutCommitMin  time.Duration `mapstructure:"timeout_commit_min"`
+
 	// EmptyBlocks mode and possible interval between empty blocks
 	CreateEmptyBlocks         bool          `mapstructure:"create_empty_blocks"`
 	CreateEmptyBlocksInterval time.Duration `mapstructure:"create_empty_blocks_interval"`
 
+	// Wait for more than 2/3 of the persistent peers to be connected before
+	// proposing the first block, not to waste rounds at the launch of the
+	// network
+	WaitForPersistentPeers bool `mapstructure:"wait_for_persistent_peers"`
+
 	// Reactor sleep duration parameters
 	PeerGossipSleepDuration     time.Duration `mapstructure:"peer_gossip_sleep_duration"`
 	PeerQueryMaj23SleepDuration time.Duration `mapstructure:"peer_query_maj23_sleep_duration"`
 
 	// Block time parameters. Corresponds to the minimum time increment between consecutive blocks.
 	BlockTimeIota time.Duration `mapstructure:"blocktime_iota"`
+
+	// Warn when the local clock is skewed by more than this, compared to the
+	// clocks of the peers and the times of the recent blocks (0 - disabled).
+	MaxClockSkew time.Duration `mapstructure:"max_clock_skew"`
+	// Don't propose blocks while the local clock is skewed by more than
+	// MaxClockSkew.
+	SkipProposeOnClockSkew bool `mapstructure:"skip_propose_on_clock_skew"`
+
+	// Erasure code the blocks we propose with this ratio of parity parts to
+	// block parts, so that peers reconstruct them from any subset of the parts
+	// and parity parts as large as the block (0 - disabled).
+	BlockPartsParityRatio float64 `mapstructure:"block_parts_parity_ratio"`
+
+	// Send the proposal blocks to the peers as compact blocks, with the hashes
+	// of the txs instead of the txs, which the peers find in their mempools.
+	CompactBlocks bool `mapstructure:"compact_blocks"`
+
+	// Chaos options for soak testing, never to be set in production, to shake
+	// out the assumptions on the order of the messages: delay each message
+	// received from peers by a random duration up to ChaosMessageDelay
+	// (0 - disabled), and deliver it twice with a ChaosDuplicateRatio
+	// probability (0 - disabled).
+	ChaosMessageDelay   time.Duration `mapstructure:"chaos_message_delay"`
+	ChaosDuplicateRatio float64       `mapstructure:"chaos_duplicate_ratio"`
 }
 
 // DefaultConsensusConfig returns a default configuration for the consensus service
 func DefaultConsensusConfig() *ConsensusConfig {
 	return &ConsensusConfig{
 		WalPath:                     filepath.Join(defaultDataDir, "cs.wal", "wal"),
+		WalDisabled:                 false,
+		RecordPath:                  "",
+		RecordMaxSize:               1024 * 1024 * 1024, // 1GB
 		TimeoutPropose:              3000 * time.Millisecond,
 		TimeoutProposeDelta:         500 * time.Millisecond,
 		TimeoutPrevote:              1000 * time.Millisecond,
@@ -625,11 +1159,20 @@ func DefaultConsensusConfig() *ConsensusConfig {
 		TimeoutPrecommitDelta:       500 * time.Millisecond,
 		TimeoutCommit:               1000 * time.Millisecond,
 		SkipTimeoutCommit:           false,
+		AdaptiveTimeouts:            false,
+		TimeoutProposeMin:           500 * time.Millisecond,
+		TimeoutCommitMin:            100 * time.Millisecond,
 		CreateEmptyBlocks:           true,
 		CreateEmptyBlocksInterval:   0 * time.Second,
 		PeerGossipSleepDuration:     100 * time.Millisecond,
 		PeerQueryMaj23SleepDuration: 2000 * time.Millisecond,
 		BlockTimeIota:               1000 * time.Millisecond,
+		MaxClockSkew:                5 * time.Second,
+		SkipProposeOnClockSkew:      false,
+		BlockPartsParityRatio:       0,
+		CompactBlocks:               false,
+		ChaosMessageDelay:           0,
+		ChaosDuplicateRatio:         0,
 	}
 }
 
@@ -644,6 +1187,8 @@ func TestConsensusConfig() *ConsensusConfig {
 	cfg.TimeoutPrecommitDelta = 1 * time.Millisecond
 	cfg.TimeoutCommit = 10 * time.Millisecond
 	cfg.SkipTimeoutCommit = true
+	cfg.TimeoutProposeMin = 10 * time.Millisecond
+	cfg.TimeoutCommitMin = 1 * time.Millisecond
 	cfg.PeerGossipSleepDuration = 5 * time.Millisecond
 	cfg.PeerQueryMaj23SleepDuration = 250 * time.Millisecond
 	cfg.BlockTimeIota = 10 * time.Millisecond
@@ -661,6 +1206,21 @@ func (cfg *ConsensusConfig) WaitForTxs() bool {
 	return !cfg.CreateEmptyBlocks || cfg.CreateEmptyBlocksInterval > 0
 }
 
+// EmptyBlocksInterval returns the amount of time to wait for txs before
+// proposing an empty block, after emptyBlocks consecutive empty blocks: the
+// interval doubles after each of them, up to maxInterval of the consensus
+// params (0 - constant interval)
+func (cfg *ConsensusConfig) EmptyBlocksInterval(emptyBlocks int, maxInterval time.Duration) time.Duration {
+	interval := cfg.CreateEmptyBlocksInterval
+	for i := 0; i < emptyBlocks && interval < maxInterval; i++ {
+		interval *= 2
+	}
+	if maxInterval > 0 && interval > maxInterval {
+		interval = maxInterval
+	}
+	return interval
+}
+
 // Propose returns the amount of time to wait for a proposal
 func (cfg *ConsensusConfig) Propose(round int) time.Duration {
 	return time.Duration(
@@ -700,6 +1260,17 @@ func (cfg *ConsensusConfig) SetWalFile(walFile string) {
 	cfg.walFile = walFile
 }
 
+// RecordFile returns the full path to the file consensus messages are
+// recorded to.
+func (cfg *ConsensusConfig) RecordFile() string {
+	return rootify(cfg.RecordPath, cfg.RootDir)
+}
+
+// RecordEnabled returns true if consensus messages are recorded.
+func (cfg *ConsensusConfig) RecordEnabled() bool {
+	return cfg.RecordPath != ""
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg *ConsensusConfig) ValidateBasic() error {
@@ -724,6 +1295,20 @@ func (cfg *ConsensusConfig) ValidateBasic() error {
 	if cfg.TimeoutCommit < 0 {
 		return errors.New("timeout_commit can't be negative")
 	}
+	if cfg.TimeoutProposeMin < 0 {
+		return errors.New("timeout_propose_min can't be negative")
+	}
+	if cfg.TimeoutCommitMin < 0 {
+		return errors.New("timeout_commit_min can't be negative")
+	}
+	if cfg.AdaptiveTimeouts {
+		if cfg.TimeoutProposeMin > cfg.TimeoutPropose {
+			return errors.New("timeout_propose_min can't be greater than timeout_propose")
+		}
+		if cfg.TimeoutCommitMin > cfg.TimeoutCommit {
+			return errors.New("timeout_commit_min can't be greater than timeout_commit")
+		}
+	}
 	if cfg.CreateEmptyBlocksInterval < 0 {
 		return errors.New("create_empty_blocks_interval can't be negative")
 	}
@@ -736,6 +1321,21 @@ func (cfg *ConsensusConfig) ValidateBasic() error {
 	if cfg.BlockTimeIota < 0 {
 		return errors.New("blocktime_iota can't be negative")
 	}
+	if cfg.RecordMaxSize < 0 {
+		return errors.New("record_max_size can't be negative")
+	}
+	if cfg.MaxClockSkew < 0 {
+		return errors.New("max_clock_skew can't be negative")
+	}
+	if cfg.BlockPartsParityRatio < 0 {
+		return errors.New("block_parts_parity_ratio can't be negative")
+	}
+	if cfg.ChaosMessageDelay < 0 {
+		return errors.New("chaos_message_delay can't be negative")
+	}
+	if cfg.ChaosDuplicateRatio < 0 || cfg.ChaosDuplicateRatio > 1 {
+		return errors.New("chaos_duplicate_ratio must be between 0 and 1")
+	}
 	return nil
 }
 
@@ -756,18 +1356,28 @@ type TxIndexConfig struct {
 	//
 	// You can also index transactions by height by adding "tx.height" tag here.
 	//
+	// The tags of the events of DeliverTx responses are the composite keys of
+	// their attributes, "type.key" (e.g. "transfer.sender").
+	//
 	// It's recommended to index only a subset of tags due to possible memory
 	// bloat. This is, of course, depends on the indexer's DB and the volume of
 	// transactions.
 	IndexTags string `mapstructure:"index_tags"`
 
 	// When set to true, tells indexer to index all tags (predefined tags:
-	// "tx.hash", "tx.height" and all tags from DeliverTx responses).
+	// "tx.hash", "tx.height" and all the attributes of the events of DeliverTx
+	// responses).
 	//
 	// Note this may be not desirable (see the comment above). IndexTags has a
 	// precedence over IndexAllTags (i.e. when given both, IndexTags will be
 	// indexed).
 	IndexAllTags bool `mapstructure:"index_all_tags"`
+
+	// Encoding of the values of the tags in the queries of the txs and the
+	// events, and in the events of the RPC: "string" (default), for text
+	// values, "hex" or "base64", for binary values. The keys of the tags are
+	// strings.
+	TagEncoding string `mapstructure:"tag_encoding"`
 }
 
 // DefaultTxIndexConfig returns a default configuration for the transaction indexer.
@@ -776,6 +1386,18 @@ func DefaultTxIndexConfig() *TxIndexConfig {
 		Indexer:      "kv",
 		IndexTags:    "",
 		IndexAllTags: false,
+		TagEncoding:  "string",
+	}
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *TxIndexConfig) ValidateBasic() error {
+	switch cfg.TagEncoding {
+	case "string", "hex", "base64":
+		return nil
+	default:
+		return fmt.Errorf("unknown tag_encoding %q, expected string, hex or base64", cfg.TagEncoding)
 	}
 }
 
@@ -805,6 +1427,11 @@ type InstrumentationConfig struct {
 
 	// Instrumentation namespace.
 	Namespace string `mapstructure:"namespace"`
+
+	// Number of the last blocks over which the blocks signed and missed by
+	// each validator are counted, served by /validator_uptime.
+	// 0 - disabled.
+	UptimeWindow int `mapstructure:"uptime_window"`
 }
 
 // DefaultInstrumentationConfig returns a default configuration for metrics
@@ -815,6 +1442,7 @@ func DefaultInstrumentationConfig() *InstrumentationConfig {
 		PrometheusListenAddr: ":26660",
 		MaxOpenConnections:   3,
 		Namespace:            "tendermint",
+		UptimeWindow:         1000,
 	}
 }
 
@@ -830,9 +1458,102 @@ func (cfg *InstrumentationConfig) ValidateBasic() error {
 	if cfg.MaxOpenConnections < 0 {
 		return errors.New("max_open_connections can't be negative")
 	}
+	if cfg.UptimeWindow < 0 {
+		return errors.New("uptime_window can't be negative")
+	}
 	return nil
 }
 
+//-----------------------------------------------------------------------------
+// Memory budget
+
+// MinMemoryBudget is the smallest memory budget a node can run in.
+const MinMemoryBudget = 128 << 20 // 128MB
+
+// The shares of the memory budget, and the estimated memory of each item
+// sized from them.
+const (
+	mempoolBudgetShare = 0.25
+	mempoolTxMemory    = 1024 // a tx, with its list element and gossip state
+
+	mempoolCacheBudgetShare = 0.02
+	mempoolCacheTxMemory    = 128 // a tx hash, with its map and list entries
+
+	peersBudgetShare = 0.25
+	peerMemory       = 4 << 20 // the buffers and queues of the connection and reactors
+
+	eventQueuesBudgetShare = 0.05
+	eventMemory            = 1024 // an event sent to a websocket client
+	maxEventQueueSize      = 1000 // the default size of the queue of a client
+
+	dbCachesBudgetShare = 0.20
+	numDBs              = 4 // blockstore, state, tx_index and evidence
+)
+
+// MemoryBudgetSizes are the sizes derived from the memory budget.
+type MemoryBudgetSizes struct {
+	MempoolSize      int // txs
+	MempoolCacheSize int // txs
+	MaxNumPeers      int // inbound and outbound
+	EventQueueSize   int // events queued for each websocket client
+	DBCacheSize      int // bytes, for each db
+}
+
+// MemoryBudgetSizes returns the sizes derived from the memory budget, which
+// must be set.
+func (cfg *Config) MemoryBudgetSizes() MemoryBudgetSizes {
+	budget := float64(cfg.MemoryBudget)
+	share := func(budgetShare float64, itemMemory int) int {
+		return int(budget * budgetShare / float64(itemMemory))
+	}
+
+	maxWSConns := cfg.RPC.MaxOpenConnections
+	if maxWSConns == 0 {
+		maxWSConns = DefaultRPCConfig().MaxOpenConnections
+	}
+	eventQueueSize := share(eventQueuesBudgetShare, eventMemory) / maxWSConns
+	if eventQueueSize > maxEventQueueSize {
+		eventQueueSize = maxEventQueueSize
+	}
+
+	return MemoryBudgetSizes{
+		MempoolSize:      share(mempoolBudgetShare, mempoolTxMemory),
+		MempoolCacheSize: share(mempoolCacheBudgetShare, mempoolCacheTxMemory),
+		MaxNumPeers:      share(peersBudgetShare, peerMemory),
+		EventQueueSize:   eventQueueSize,
+		DBCacheSize:      share(dbCachesBudgetShare, numDBs),
+	}
+}
+
+// WithMemoryBudget returns the config with the size of the mempool, of its
+// cache, and the number of inbound peers bounded by the sizes derived from the
+// memory budget, if set. The outbound peers are kept, if the budget allows for
+// as many peers. The config is copied, not modified, if any size is bounded.
+func (cfg *Config) WithMemoryBudget() *Config {
+	if cfg.MemoryBudget == 0 {
+		return cfg
+	}
+	sizes := cfg.MemoryBudgetSizes()
+	budgeted := *cfg
+	mempool, p2p := *cfg.Mempool, *cfg.P2P
+	budgeted.Mempool, budgeted.P2P = &mempool, &p2p
+
+	if mempool.Size > sizes.MempoolSize {
+		mempool.Size = sizes.MempoolSize
+	}
+	if mempool.CacheSize > sizes.MempoolCacheSize {
+		mempool.CacheSize = sizes.MempoolCacheSize
+	}
+	maxNumInboundPeers := sizes.MaxNumPeers - p2p.MaxNumOutboundPeers
+	if maxNumInboundPeers < 0 {
+		maxNumInboundPeers = 0
+	}
+	if p2p.MaxNumInboundPeers > maxNumInboundPeers {
+		p2p.MaxNumInboundPeers = maxNumInboundPeers
+	}
+	return &budgeted
+}
+
 //-----------------------------------------------------------------------------
 // Utils
 
@@ -35,4 +35,98 @@ func TestConfigValidateBasic(t *testing.T) {
 	// tamper with timeout_propose
 	cfg.Consensus.TimeoutPropose = -10 * time.Second
 	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = DefaultConfig()
+	cfg.TxIndex.TagEncoding = "utf16"
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestRPCConfigIsUnsafeEnabled(t *testing.T) {
+	cfg := DefaultRPCConfig()
+	assert.False(t, cfg.IsUnsafeEnabled())
+
+	cfg.Unsafe = true
+	assert.True(t, cfg.IsUnsafeEnabled())
+
+	// disable_unsafe wins
+	cfg.DisableUnsafe = true
+	assert.False(t, cfg.IsUnsafeEnabled())
+}
+
+func TestRPCConfigSlowRequestThresholds(t *testing.T) {
+	cfg := DefaultRPCConfig()
+	cfg.SlowRequestThresholds = []string{"broadcast_tx_commit=15s", " tx_search = 2s"}
+	thresholds, err := cfg.SlowRequestThresholdsByEndpoint()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"broadcast_tx_commit": 15 * time.Second,
+		"tx_search":           2 * time.Second,
+	}, thresholds)
+	assert.NoError(t, cfg.ValidateBasic())
+
+	for _, invalid := range []string{"tx_search", "=2s", "tx_search=2", "tx_search=-2s"} {
+		cfg.SlowRequestThresholds = []string{invalid}
+		assert.Error(t, cfg.ValidateBasic(), invalid)
+	}
+}
+
+func TestConsensusConfigEmptyBlocksInterval(t *testing.T) {
+	cfg := DefaultConsensusConfig()
+	cfg.CreateEmptyBlocksInterval = 5 * time.Second
+	assert.NoError(t, cfg.ValidateBasic())
+	assert.Equal(t, 5*time.Second, cfg.EmptyBlocksInterval(0, 0))
+	assert.Equal(t, 5*time.Second, cfg.EmptyBlocksInterval(10, 0))
+
+	assert.Equal(t, 5*time.Second, cfg.EmptyBlocksInterval(0, time.Minute))
+	assert.Equal(t, 10*time.Second, cfg.EmptyBlocksInterval(1, time.Minute))
+	assert.Equal(t, 40*time.Second, cfg.EmptyBlocksInterval(3, time.Minute))
+	assert.Equal(t, time.Minute, cfg.EmptyBlocksInterval(4, time.Minute))
+	assert.Equal(t, time.Minute, cfg.EmptyBlocksInterval(1000, time.Minute))
+
+	// The interval is bounded by the consensus params.
+	assert.Equal(t, time.Second, cfg.EmptyBlocksInterval(0, time.Second))
+}
+
+func TestConfigSetEphemeral(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SetRoot("/foo")
+	assert.Equal(t, "/foo/config/addrbook.json", cfg.P2P.AddrBookFile())
+
+	cfg.SetEphemeral()
+	assert.NoError(t, cfg.ValidateBasic())
+	assert.Equal(t, "memdb", cfg.DBBackend)
+	assert.True(t, cfg.Consensus.WalDisabled)
+	assert.False(t, cfg.Mempool.WalEnabled())
+	assert.Equal(t, "", cfg.P2P.AddrBookFile())
+}
+
+func TestConfigMemoryBudget(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.Equal(t, cfg, cfg.WithMemoryBudget(), "no budget")
+
+	cfg.MemoryBudget = 1 << 20
+	assert.Error(t, cfg.ValidateBasic())
+
+	// a budget allowing for the default sizes
+	cfg.MemoryBudget = 1 << 30
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg = cfg.WithMemoryBudget()
+	assert.Equal(t, DefaultMempoolConfig().Size, cfg.Mempool.Size)
+	assert.Equal(t, DefaultP2PConfig().MaxNumInboundPeers, cfg.P2P.MaxNumInboundPeers)
+	sizes := cfg.MemoryBudgetSizes()
+	assert.Equal(t, 58, sizes.EventQueueSize)
+	assert.Equal(t, 53687091, sizes.DBCacheSize)
+
+	// a budget halving the peers
+	cfg = DefaultConfig()
+	cfg.MemoryBudget = 256 << 20
+	cfg.Mempool.Size = 100000
+	budgeted := cfg.WithMemoryBudget()
+	assert.Equal(t, 65536, budgeted.Mempool.Size)
+	assert.Equal(t, 6, budgeted.P2P.MaxNumInboundPeers)
+	assert.Equal(t, 10, budgeted.P2P.MaxNumOutboundPeers)
+
+	// the config is not modified
+	assert.Equal(t, 100000, cfg.Mempool.Size)
+	assert.Equal(t, DefaultP2PConfig().MaxNumInboundPeers, cfg.P2P.MaxNumInboundPeers)
 }
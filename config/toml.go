@@ -81,6 +81,12 @@ moniker = "{{ .BaseConfig.Moniker }}"
 # and verifying their commits
 fast_sync = {{ .BaseConfig.FastSync }}
 
+# Run as a read-only replica: sync and gossip the blocks and serve the RPC, but
+# never sign nor vote, even if the validator key is in the validator set, e.g.
+# for RPC replicas set up from a validator's backup. The priv_validator_laddr
+# and priv_validator_lock are ignored.
+replica = {{ .BaseConfig.Replica }}
+
 # Database backend: leveldb | memdb | cleveldb
 db_backend = "{{ .BaseConfig.DBBackend }}"
 
@@ -93,11 +99,19 @@ log_level = "{{ .BaseConfig.LogLevel }}"
 # Output format: 'plain' (colored text) or 'json'
 log_format = "{{ .BaseConfig.LogFormat }}"
 
+# Number of info and error lines kept in memory for each module, served by
+# /unsafe_logs if the unsafe RPC commands are enabled. 0 disables it.
+log_buffer_lines = {{ .BaseConfig.LogBufferLines }}
+
 ##### additional base config options #####
 
 # Path to the JSON file containing the initial validator set and other meta data
 genesis_file = "{{ js .BaseConfig.Genesis }}"
 
+# Expected SHA-256 hash of the genesis file, hex encoded. The node fails
+# to start if the genesis file has another hash. Empty to not check it.
+genesis_hash = "{{ .BaseConfig.GenesisHash }}"
+
 # Path to the JSON file containing the private key to use as a validator in the consensus protocol
 priv_validator_key_file = "{{ js .BaseConfig.PrivValidatorKey }}"
 
@@ -105,22 +119,90 @@ priv_validator_key_file = "{{ js .BaseConfig.PrivValidatorKey }}"
 priv_validator_state_file = "{{ js .BaseConfig.PrivValidatorState }}"
 
 # TCP or UNIX socket address for Tendermint to listen on for
-# connections from an external PrivValidator process
+# connections from an external PrivValidator process.
+# Comma separated addresses of several PrivValidator processes, in priority
+# order, fail over to the next one when the active one is unhealthy for
+# priv_validator_failover_timeout.
 priv_validator_laddr = "{{ .BaseConfig.PrivValidatorListenAddr }}"
 
+# How long the active external PrivValidator process must be unhealthy before
+# failing over to another one. It should be longer than
+# priv_validator_lock_lease.
+priv_validator_failover_timeout = "{{ .BaseConfig.PrivValidatorFailoverTimeout }}"
+
+# Signing lock preventing two nodes from signing with the validator key at
+# the same time: "" (none), "file" (lease in a file shared by the nodes) or
+# "remote" (lease in the remote signer, see priv_validator_laddr). The node
+# fails to start if another node holds the lock.
+priv_validator_lock = "{{ .BaseConfig.PrivValidatorLock }}"
+
+# Path to the file of the "file" signing lock
+priv_validator_lock_file = "{{ js .BaseConfig.PrivValidatorLockPath }}"
+
+# Lease of the signing lock, renewed every third of it. The node stops
+# signing when it expires, until it is renewed.
+priv_validator_lock_lease = "{{ .BaseConfig.PrivValidatorLockLease }}"
+
 # Path to the JSON file containing the private key to use for node authentication in the p2p protocol
 node_key_file = "{{ js .BaseConfig.NodeKey }}"
 
+# Path to the JSON file containing the rotation of the node key, signed by the
+# previous key and presented to the peers (see rotate-node-key). Ignored if it
+# doesn't exist.
+node_key_rotation_file = "{{ js .BaseConfig.NodeKeyRotation }}"
+
+# Path to the JSON file mapping the hex addresses of the validators to their
+# monikers, shown in the consensus logs, /consensus_state and /validators
+# along with the names of the genesis validators. The file is optional, and
+# reloaded when it changes.
+validator_monikers_file = "{{ js .BaseConfig.ValidatorMonikers }}"
+
 # Mechanism to connect to the ABCI application: socket | grpc
 abci = "{{ .BaseConfig.ABCI }}"
 
+# Maximum size of the messages exchanged with the ABCI application over a
+# socket, in bytes. The application may negotiate a smaller one.
+abci_max_msg_size = {{ .BaseConfig.ABCIMaxMsgSize }}
+
+# Maximum number of requests sent to the ABCI application over a socket
+# before receiving their responses, 0 for unlimited. The application may
+# negotiate fewer.
+abci_max_in_flight = {{ .BaseConfig.ABCIMaxInFlight }}
+
 # TCP or UNIX socket address for the profiling server to listen on
+# Deprecated: use rpc.pprof_laddr.
 prof_laddr = "{{ .BaseConfig.ProfListenAddress }}"
 
 # If true, query the ABCI app on connecting to a new peer
 # so the app can decide if we should keep the connection or not
 filter_peers = {{ .BaseConfig.FilterPeers }}
 
+# Memory budget of the node, in bytes. If set, the mempool, the number of
+# peers, the event queues of the websocket clients and the db caches are
+# sized to fit in it, and the RPC server rejects the requests while the
+# memory used exceeds it.
+# 0 - unlimited.
+memory_budget = {{ .BaseConfig.MemoryBudget }}
+
+# Directory to write the crash reports to, when a reactor or an RPC
+# handler panics. Empty disables the reports.
+crash_dir = "{{ js .BaseConfig.CrashDir }}"
+
+# If true, the crash reports include the last info and error lines
+# logged, which are then kept in memory as they are logged.
+crash_report_logs = {{ .BaseConfig.CrashReportLogs }}
+
+# If true, the node shuts down when a reactor or an RPC handler panics,
+# instead of stopping the peer or failing the request.
+crash_shutdown = {{ .BaseConfig.CrashShutdown }}
+
+# If true, check invariants of the state machine at each block (the validator
+# set hashes against the header, the continuity of the app hash, the
+# consistency of the WAL with the block store...), and panic with diagnostics
+# at the first inconsistency, instead of failing blocks later. For debugging:
+# it slows the blocks down.
+check_invariants = {{ .BaseConfig.CheckInvariants }}
+
 ##### advanced configuration options #####
 
 ##### rpc server configuration options #####
@@ -156,6 +238,11 @@ grpc_max_open_connections = {{ .RPC.GRPCMaxOpenConnections }}
 # Activate unsafe RPC commands like /dial_seeds and /unsafe_flush_mempool
 unsafe = {{ .RPC.Unsafe }}
 
+# If true, the unsafe RPC commands are disabled, even if unsafe is set
+# (e.g. by the --rpc.unsafe flag), for public RPC servers.
+# NOTE: the binaries built with the nounsafe tag don't have them at all.
+disable_unsafe = {{ .RPC.DisableUnsafe }}
+
 # Maximum number of simultaneous connections (including WebSocket).
 # Does not include gRPC connections. See grpc_max_open_connections
 # If you want to accept a larger number than the default, make sure
@@ -165,6 +252,94 @@ unsafe = {{ .RPC.Unsafe }}
 # 1024 - 40 - 10 - 50 = 924 = ~900
 max_open_connections = {{ .RPC.MaxOpenConnections }}
 
+# Maximum number of simultaneous WebSocket connections from a single IP.
+# 0 - unlimited.
+# NOTE: all the connections made through a reverse proxy have the IP of the proxy.
+max_ws_connections_per_ip = {{ .RPC.MaxWSConnectionsPerIP }}
+
+# Maximum number of unique queries a given WebSocket client can /subscribe to.
+# 0 - unlimited.
+max_subscriptions_per_client = {{ .RPC.MaxSubscriptionsPerClient }}
+
+# Maximum number of WebSocket clients at a time, over all the IPs, which along
+# with max_subscriptions_per_client bounds the subscriptions. When reached, a
+# new client is handled by subscription_clients_eviction.
+# 0 - unlimited.
+max_subscription_clients = {{ .RPC.MaxSubscriptionClients }}
+
+# What to do with a new WebSocket client over max_subscription_clients:
+# "reject" - reject it,
+# "oldest" - evict the oldest client for it,
+# "lru" - evict the client which sent a request the longest time ago for it.
+subscription_clients_eviction = "{{ .RPC.SubscriptionClientsEviction }}"
+
+# Maximum number of conditions of a /subscribe query, counting the conditions
+# of each of the queries OR'd together.
+# 0 - unlimited.
+max_query_conditions = {{ .RPC.MaxQueryConditions }}
+
+# Maximum number of events of a /subscribe in the ack mode kept until the
+# client acks them. The subscription is dropped when it exceeds it.
+# 0 - the ack mode is disabled.
+max_unacked_events = {{ .RPC.MaxUnackedEvents }}
+
+# Maximum number of /subscribe in the ack mode, over all the clients. They
+# outlive the connections of the clients, until unsubscribed or dropped.
+# 0 - unlimited.
+max_ack_subscriptions = {{ .RPC.MaxAckSubscriptions }}
+
+# Number of the last events of a query buffered for the /events long polling,
+# from the first /events of the query until it isn't polled for
+# events_buffer_timeout.
+# 0 - /events is disabled.
+events_buffer_size = {{ .RPC.EventsBufferSize }}
+
+# How long the events of a query stay buffered after its last /events.
+events_buffer_timeout = "{{ .RPC.EventsBufferTimeout }}"
+
+# Maximum number of queries buffered for /events at a time.
+# 0 - unlimited.
+max_events_buffers = {{ .RPC.MaxEventsBuffers }}
+
+# Fraction of the HTTP requests logged, with their endpoint, status and
+# duration, from 0 (none) to 1 (all), at the info level of the rpc-server
+# module.
+access_log_sample_rate = {{ .RPC.AccessLogSampleRate }}
+
+# HTTP requests taking longer are logged as slow, at the error level, sampled
+# or not.
+# 0 - the slow requests are not logged.
+slow_request_threshold = "{{ .RPC.SlowRequestThreshold }}"
+
+# The slow_request_threshold of some endpoints, as "endpoint=duration",
+# e.g. ["broadcast_tx_commit=15s", "tx_search=2s"].
+slow_request_thresholds = [{{ range .RPC.SlowRequestThresholds }}{{ printf "%q, " . }}{{end}}]
+
+# Host the RPC of the node is served on, matched against the Host header of
+# the requests, without the port, so that one RPC server can front several
+# nodes of the process. Any host if empty.
+tenant_host = "{{ .RPC.TenantHost }}"
+
+# URL path prefix the RPC of the node is served under, e.g. "/chain-a".
+# None if empty.
+tenant_prefix = "{{ .RPC.TenantPrefix }}"
+
+# Tokens accepted in an "Authorization: Bearer <token>" header of the requests.
+# The requests aren't authenticated if empty.
+auth_tokens = [{{ range .RPC.AuthTokens }}{{ printf "%q, " . }}{{end}}]
+
+# Maximum number of requests per second, with bursts of up to rate_burst
+# requests. Over it, the requests are rejected with an HTTP 429 error.
+# 0 - unlimited.
+rate_limit = {{ .RPC.RateLimit }}
+rate_burst = {{ .RPC.RateBurst }}
+
+# Address to serve the net/http/pprof profiles (CPU, heap, goroutine, mutex...)
+# on, e.g. "localhost:6060". Empty to disable.
+# NOTE: the profiles expose the internals of the node, and collecting them
+# slows it down: don't make it public.
+pprof_laddr = "{{ .RPC.PprofListenAddress }}"
+
 ##### peer to peer configuration options #####
 [p2p]
 
@@ -183,10 +358,15 @@ seeds = "{{ .P2P.Seeds }}"
 # Comma separated list of nodes to keep persistent connections to
 persistent_peers = "{{ .P2P.PersistentPeers }}"
 
+# Semicolon separated list of groups of nodes, of which at least K nodes
+# of each group are kept connected, in the form "K:addr,addr;K:addr,...".
+# Useful for sentries which don't need connections to all validators.
+persistent_peer_groups = "{{ .P2P.PersistentPeerGroups }}"
+
 # UPNP port forwarding
 upnp = {{ .P2P.UPNP }}
 
-# Path to address book
+# Path to address book ("" - kept in memory only)
 addr_book_file = "{{ js .P2P.AddrBook }}"
 
 # Set true for strict address routability rules
@@ -211,6 +391,13 @@ send_rate = {{ .P2P.SendRate }}
 # Rate at which packets can be received, in bytes/second
 recv_rate = {{ .P2P.RecvRate }}
 
+# Compression of the large messages (block parts, txs) sent to the peers with
+# the same compression, negotiated in the handshake: "none" or "snappy"
+compression = "{{ .P2P.Compression }}"
+
+# Minimum size of the messages compressed, in bytes
+compression_min_size = {{ .P2P.CompressionMinSize }}
+
 # Set true to enable the peer-exchange reactor
 pex = {{ .P2P.PexReactor }}
 
@@ -230,6 +417,27 @@ allow_duplicate_ip = {{ .P2P.AllowDuplicateIP }}
 handshake_timeout = "{{ .P2P.HandshakeTimeout }}"
 dial_timeout = "{{ .P2P.DialTimeout }}"
 
+# SOCKS5 proxy to route all outbound connections through
+# (e.g. "socks5://127.0.0.1:9050" for Tor). Required to dial .onion peers.
+upstream_proxy = "{{ .P2P.UpstreamProxy }}"
+
+# Dial the persistent peers with the Noise IK handshake instead of the STS one,
+# once their key is pinned by a first connection. Inbound connections accept
+# both, so enable it only when the persistent peers support it.
+noise_handshake = {{ .P2P.NoiseHandshake }}
+
+# Path to a JSON list of the public keys of the certificate authorities of a
+# permissioned network. If set, peers must present a certificate signed by one
+# of them, binding their node ID to an organization.
+ca_file = "{{ js .P2P.CA }}"
+
+# Path to the certificate of this node, presented to the peers.
+cert_file = "{{ js .P2P.Cert }}"
+
+# Warn when more than this ratio of the peers run a newer p2p or block protocol
+# version, i.e. this node is likely behind an upgrade (0 to disable).
+newer_peers_warn_ratio = {{ .P2P.NewerPeersWarnRatio }}
+
 ##### mempool configuration options #####
 [mempool]
 
@@ -243,11 +451,28 @@ size = {{ .Mempool.Size }}
 # size of the cache (used to filter transactions we saw earlier)
 cache_size = {{ .Mempool.CacheSize }}
 
+# Chaos option for soak testing, never to be set in production: ratio of the
+# txs left in the mempool after each block evicted at random (0 - disabled)
+chaos_evict_ratio = {{ .Mempool.ChaosEvictRatio }}
+
 ##### consensus configuration options #####
 [consensus]
 
 wal_file = "{{ js .Consensus.WalPath }}"
 
+# Run without a WAL, and so without its fsyncs. The node can't recover the
+# votes it signed at the height it crashed at: only use it for test nodes and
+# local devnets
+wal_disabled = {{ .Consensus.WalDisabled }}
+
+# Record the consensus messages received from peers, with the time they were
+# received, to a rolling group of files ("" - disabled). The recording can be
+# replayed with "tendermint replay_recording".
+record_file = "{{ js .Consensus.RecordPath }}"
+
+# Maximum total size of the recording files, in bytes
+record_max_size = {{ .Consensus.RecordMaxSize }}
+
 timeout_propose = "{{ .Consensus.TimeoutPropose }}"
 timeout_propose_delta = "{{ .Consensus.TimeoutProposeDelta }}"
 timeout_prevote = "{{ .Consensus.TimeoutPrevote }}"
@@ -259,10 +484,23 @@ timeout_commit = "{{ .Consensus.TimeoutCommit }}"
 # Make progress as soon as we have all the precommits (as if TimeoutCommit = 0)
 skip_timeout_commit = {{ .Consensus.SkipTimeoutCommit }}
 
+# Adjust timeout_propose and timeout_commit to the latencies of the recent
+# proposals and precommits: twice the longest of them, between
+# timeout_propose_min and timeout_propose, and between timeout_commit_min and
+# timeout_commit.
+adaptive_timeouts = {{ .Consensus.AdaptiveTimeouts }}
+timeout_propose_min = "{{ .Consensus.TimeoutProposeMin }}"
+timeout_commit_min = "{{ .Consensus.TimeoutCommitMin }}"
+
 # EmptyBlocks mode and possible interval between empty blocks
 create_empty_blocks = {{ .Consensus.CreateEmptyBlocks }}
 create_empty_blocks_interval = "{{ .Consensus.CreateEmptyBlocksInterval }}"
 
+# Wait for more than 2/3 of the persistent peers to be connected before
+# proposing the first block, not to waste rounds at the launch of the network.
+# The first block is never started before the genesis time
+wait_for_persistent_peers = {{ .Consensus.WaitForPersistentPeers }}
+
 # Reactor sleep duration parameters
 peer_gossip_sleep_duration = "{{ .Consensus.PeerGossipSleepDuration }}"
 peer_query_maj23_sleep_duration = "{{ .Consensus.PeerQueryMaj23SleepDuration }}"
@@ -270,6 +508,31 @@ peer_query_maj23_sleep_duration = "{{ .Consensus.PeerQueryMaj23SleepDuration }}"
 # Block time parameters. Corresponds to the minimum time increment between consecutive blocks.
 blocktime_iota = "{{ .Consensus.BlockTimeIota }}"
 
+# Warn when the local clock is skewed by more than this, compared to the
+# clocks of the peers and the times of the recent blocks (0 - disabled)
+max_clock_skew = "{{ .Consensus.MaxClockSkew }}"
+
+# Don't propose blocks while the local clock is skewed by more than max_clock_skew
+skip_propose_on_clock_skew = {{ .Consensus.SkipProposeOnClockSkew }}
+
+# Erasure code the blocks we propose with this ratio of parity parts to block
+# parts, so that peers reconstruct them from any subset of the parts and parity
+# parts as large as the block (0 - disabled). Blocks of more than 255 parts are
+# not erasure coded
+block_parts_parity_ratio = {{ .Consensus.BlockPartsParityRatio }}
+
+# Send the proposal blocks to the peers as compact blocks, with the hashes of the
+# txs instead of the txs, which the peers find in their mempools. The peers
+# request the missing txs, or fall back to the block parts
+compact_blocks = {{ .Consensus.CompactBlocks }}
+
+# Chaos options for soak testing, never to be set in production, to shake out
+# the assumptions on the order of the messages:
+# maximum random delay of each message received from peers (0 - disabled)
+chaos_message_delay = "{{ .Consensus.ChaosMessageDelay }}"
+# probability of delivering a message received from peers twice (0 - disabled)
+chaos_duplicate_ratio = {{ .Consensus.ChaosDuplicateRatio }}
+
 ##### transactions indexer configuration options #####
 [tx_index]
 
@@ -284,19 +547,29 @@ indexer = "{{ .TxIndex.Indexer }}"
 #
 # You can also index transactions by height by adding "tx.height" tag here.
 #
+# The tags of the events of DeliverTx responses are the composite keys of
+# their attributes, "type.key" (e.g. "transfer.sender").
+#
 # It's recommended to index only a subset of tags due to possible memory
 # bloat. This is, of course, depends on the indexer's DB and the volume of
 # transactions.
 index_tags = "{{ .TxIndex.IndexTags }}"
 
 # When set to true, tells indexer to index all tags (predefined tags:
-# "tx.hash", "tx.height" and all tags from DeliverTx responses).
+# "tx.hash", "tx.height" and all the attributes of the events of DeliverTx
+# responses).
 #
 # Note this may be not desirable (see the comment above). IndexTags has a
 # precedence over IndexAllTags (i.e. when given both, IndexTags will be
 # indexed).
 index_all_tags = {{ .TxIndex.IndexAllTags }}
 
+# Encoding of the values of the tags in the queries of the txs and the
+# events, and in the events of the RPC: "string" (default), for text
+# values, "hex" or "base64", for binary values. The keys of the tags are
+# strings.
+tag_encoding = "{{ .TxIndex.TagEncoding }}"
+
 ##### instrumentation configuration options #####
 [instrumentation]
 
@@ -316,6 +589,11 @@ max_open_connections = {{ .Instrumentation.MaxOpenConnections }}
 
 # Instrumentation namespace
 namespace = "{{ .Instrumentation.Namespace }}"
+
+# Number of the last blocks over which the blocks signed and missed by each
+# validator are counted, served by /validator_uptime.
+# 0 - disabled.
+uptime_window = {{ .Instrumentation.UptimeWindow }}
 `
 
 /****** these are for test settings ***********/
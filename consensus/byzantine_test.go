@@ -177,7 +177,7 @@ func byzantineDecideProposalFunc(t *testing.T, height int64, round int, cs *Cons
 	block1, blockParts1 := cs.createProposalBlock()
 	polRound, propBlockID := cs.ValidRound, types.BlockID{block1.Hash(), blockParts1.Header()}
 	proposal1 := types.NewProposal(height, round, polRound, propBlockID)
-	if err := cs.privValidator.SignProposal(cs.state.ChainID, proposal1); err != nil {
+	if err := cs.privValidator.SignProposal(cs.state.ChainID, types.SignDomain{}, proposal1); err != nil {
 		t.Error(err)
 	}
 
@@ -185,7 +185,7 @@ func byzantineDecideProposalFunc(t *testing.T, height int64, round int, cs *Cons
 	block2, blockParts2 := cs.createProposalBlock()
 	polRound, propBlockID = cs.ValidRound, types.BlockID{block2.Hash(), blockParts2.Header()}
 	proposal2 := types.NewProposal(height, round, polRound, propBlockID)
-	if err := cs.privValidator.SignProposal(cs.state.ChainID, proposal2); err != nil {
+	if err := cs.privValidator.SignProposal(cs.state.ChainID, types.SignDomain{}, proposal2); err != nil {
 		t.Error(err)
 	}
 
@@ -0,0 +1,39 @@
+package consensus
+
+import (
+	"time"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// queuePeerMsg queues a message received from a peer for the state machine,
+// without blocking: it returns false if the queue is full, dropping the
+// message. With the chaos options of the config, for soak testing, it is
+// delivered after a random delay up to ChaosMessageDelay, and twice with a
+// ChaosDuplicateRatio probability, the copies being delayed independently.
+func (cs *ConsensusState) queuePeerMsg(mi msgInfo) bool {
+	n := 1
+	if ratio := cs.config.ChaosDuplicateRatio; ratio > 0 && cmn.RandFloat64() < ratio {
+		n = 2
+	}
+	maxDelay := cs.config.ChaosMessageDelay
+	queued := true
+	for i := 0; i < n; i++ {
+		if maxDelay <= 0 {
+			select {
+			case cs.peerMsgQueue <- mi:
+			default:
+				queued = false
+			}
+			continue
+		}
+		go func(delay time.Duration) {
+			time.Sleep(delay)
+			select {
+			case cs.peerMsgQueue <- mi:
+			case <-cs.Quit():
+			}
+		}(time.Duration(cmn.RandInt63n(int64(maxDelay))))
+	}
+	return queued
+}
@@ -0,0 +1,35 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/p2p"
+)
+
+func TestQueuePeerMsgChaos(t *testing.T) {
+	cs, _ := randConsensusState(1)
+	mi := msgInfo{&HasVoteMessage{Height: 1}, p2p.ID("peer")}
+
+	// delivered once, right away, without the chaos options
+	cs.queuePeerMsg(mi)
+	require.Len(t, cs.peerMsgQueue, 1)
+	<-cs.peerMsgQueue
+
+	// delayed and duplicated with them
+	cs.config.ChaosMessageDelay = 50 * time.Millisecond
+	cs.config.ChaosDuplicateRatio = 1
+	cs.queuePeerMsg(mi)
+	assert.Len(t, cs.peerMsgQueue, 0)
+	for i := 0; i < 2; i++ {
+		select {
+		case got := <-cs.peerMsgQueue:
+			assert.Equal(t, mi, got)
+		case <-time.After(time.Second):
+			t.Fatal("expected the message to be delivered twice")
+		}
+	}
+}
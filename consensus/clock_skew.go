@@ -0,0 +1,125 @@
+package consensus
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/p2p"
+)
+
+// clockSkewBlocks is the number of recent blocks the local clock is compared
+// against.
+const clockSkewBlocks = 100
+
+// clockSkewMonitor estimates the skew of the local clock, i.e. how far ahead
+// of the clocks of the other nodes it is (behind if negative), as the median
+// of the skews measured against the peers at the handshake, and against the
+// times of the recent blocks. It warns when the skew exceeds maxSkew.
+type clockSkewMonitor struct {
+	mtx sync.Mutex
+
+	peers     map[p2p.ID]time.Duration
+	blocks    []time.Duration // ring of the last clockSkewBlocks blocks
+	nextBlock int
+	skew      time.Duration
+	exceeded  bool
+	maxSkew   time.Duration // 0 to never warn
+	logger    log.Logger
+	metrics   *Metrics
+}
+
+func newClockSkewMonitor(maxSkew time.Duration, metrics *Metrics) *clockSkewMonitor {
+	return &clockSkewMonitor{
+		peers:   make(map[p2p.ID]time.Duration),
+		blocks:  make([]time.Duration, 0, clockSkewBlocks),
+		maxSkew: maxSkew,
+		logger:  log.NewNopLogger(),
+		metrics: metrics,
+	}
+}
+
+func (m *clockSkewMonitor) SetLogger(l log.Logger) {
+	m.mtx.Lock()
+	m.logger = l
+	m.mtx.Unlock()
+}
+
+// AddPeer records the skew of the local clock against the one of the peer.
+func (m *clockSkewMonitor) AddPeer(id p2p.ID, skew time.Duration) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.peers[id] = skew
+	m.update()
+}
+
+// RemovePeer forgets the skew against the peer.
+func (m *clockSkewMonitor) RemovePeer(id p2p.ID) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if _, ok := m.peers[id]; ok {
+		delete(m.peers, id)
+		m.update()
+	}
+}
+
+// AddBlock records the skew of the local clock against the time of a block,
+// replacing the oldest one if there are already clockSkewBlocks.
+func (m *clockSkewMonitor) AddBlock(skew time.Duration) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if len(m.blocks) < clockSkewBlocks {
+		m.blocks = append(m.blocks, skew)
+	} else {
+		m.blocks[m.nextBlock] = skew
+	}
+	m.nextBlock = (m.nextBlock + 1) % clockSkewBlocks
+	m.update()
+}
+
+// Skew returns the estimated skew of the local clock, 0 if unknown.
+func (m *clockSkewMonitor) Skew() time.Duration {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.skew
+}
+
+// Exceeded returns true if the skew of the local clock exceeds maxSkew.
+func (m *clockSkewMonitor) Exceeded() bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.exceeded
+}
+
+// update computes the skew again, and warns if it starts or stops exceeding
+// maxSkew. m.mtx must be held.
+func (m *clockSkewMonitor) update() {
+	skews := make([]time.Duration, 0, len(m.peers)+len(m.blocks))
+	for _, skew := range m.peers {
+		skews = append(skews, skew)
+	}
+	skews = append(skews, m.blocks...)
+
+	m.skew = 0
+	if len(skews) > 0 {
+		sort.Slice(skews, func(i, j int) bool { return skews[i] < skews[j] })
+		mid := len(skews) / 2
+		if len(skews)%2 == 1 {
+			m.skew = skews[mid]
+		} else {
+			m.skew = skews[mid-1] + (skews[mid]-skews[mid-1])/2
+		}
+	}
+	m.metrics.ClockSkewSeconds.Set(m.skew.Seconds())
+
+	exceeded := m.maxSkew > 0 && (m.skew > m.maxSkew || m.skew < -m.maxSkew)
+	switch {
+	case exceeded && !m.exceeded:
+		m.logger.Error("The local clock is skewed, check it's synchronized (e.g. with NTP)",
+			"skew", m.skew, "max", m.maxSkew, "peers", len(m.peers), "blocks", len(m.blocks))
+	case !exceeded && m.exceeded:
+		m.logger.Info("The local clock is no longer skewed", "skew", m.skew)
+	}
+	m.exceeded = exceeded
+}
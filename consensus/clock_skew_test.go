@@ -0,0 +1,50 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tendermint/tendermint/p2p"
+)
+
+func TestClockSkewMonitor(t *testing.T) {
+	m := newClockSkewMonitor(time.Second, NopMetrics())
+	assert.Equal(t, time.Duration(0), m.Skew())
+	assert.False(t, m.Exceeded())
+
+	// the median ignores a peer with a wrong clock
+	m.AddPeer(p2p.ID("a"), 2*time.Second)
+	m.AddPeer(p2p.ID("b"), 2*time.Second)
+	m.AddPeer(p2p.ID("c"), -time.Hour)
+	assert.Equal(t, 2*time.Second, m.Skew())
+	assert.True(t, m.Exceeded())
+
+	// even number of samples
+	m.RemovePeer(p2p.ID("a"))
+	assert.Equal(t, -time.Hour/2+time.Second, m.Skew())
+
+	m.RemovePeer(p2p.ID("b"))
+	m.RemovePeer(p2p.ID("c"))
+	assert.Equal(t, time.Duration(0), m.Skew())
+	assert.False(t, m.Exceeded())
+
+	// only the last clockSkewBlocks blocks are kept
+	for i := 0; i < clockSkewBlocks; i++ {
+		m.AddBlock(time.Minute)
+	}
+	assert.True(t, m.Exceeded())
+	for i := 0; i < clockSkewBlocks; i++ {
+		m.AddBlock(100 * time.Millisecond)
+	}
+	assert.Equal(t, 100*time.Millisecond, m.Skew())
+	assert.False(t, m.Exceeded())
+}
+
+func TestClockSkewMonitorDisabled(t *testing.T) {
+	m := newClockSkewMonitor(0, NopMetrics())
+	m.AddBlock(time.Hour)
+	assert.Equal(t, time.Hour, m.Skew())
+	assert.False(t, m.Exceeded())
+}
@@ -86,7 +86,7 @@ func (vs *validatorStub) signVote(voteType types.SignedMsgType, hash []byte, hea
 		Type:             voteType,
 		BlockID:          types.BlockID{hash, header},
 	}
-	err := vs.PrivValidator.SignVote(config.ChainID(), vote)
+	err := vs.PrivValidator.SignVote(config.ChainID(), types.SignDomain{}, vote)
 	return vote, err
 }
 
@@ -143,7 +143,7 @@ func decideProposal(cs1 *ConsensusState, vs *validatorStub, height int64, round
 	cs1.mtx.RUnlock()
 	polRound, propBlockID := validRound, types.BlockID{block.Hash(), blockParts.Header()}
 	proposal = types.NewProposal(height, round, polRound, propBlockID)
-	if err := vs.SignProposal(chainID, proposal); err != nil {
+	if err := vs.SignProposal(chainID, types.SignDomain{}, proposal); err != nil {
 		panic(err)
 	}
 	return
@@ -0,0 +1,230 @@
+package consensus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	cstypes "github.com/tendermint/tendermint/consensus/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+// compactBlockTimeout is how long we wait for a peer to reconstruct the
+// proposal block from the compact block we sent it, before sending it the
+// block parts.
+const compactBlockTimeout = 500 * time.Millisecond
+
+// txFinder is an interface to the mempool to find the txs of the compact
+// proposal blocks received from peers.
+type txFinder interface {
+	TxsByHashes(hashes [][]byte) types.Txs
+}
+
+// compactBlockCache caches the encoded compact block of the proposal block,
+// sent to all the peers.
+type compactBlockCache struct {
+	round    int
+	hash     []byte
+	msgBytes []byte // nil if too large
+}
+
+// pendingCompactBlock is a compact block received from a peer, waiting for
+// the txs missing from our mempool.
+type pendingCompactBlock struct {
+	msg *CompactBlockMessage
+	txs types.Txs // nil for the missing ones
+}
+
+func makeCompactBlockMessage(height int64, round int, block *types.Block) *CompactBlockMessage {
+	txHashes := make([]cmn.HexBytes, len(block.Txs))
+	for i, tx := range block.Txs {
+		txHashes[i] = tx.Hash()
+	}
+	return &CompactBlockMessage{
+		Height:     height,
+		Round:      round,
+		Header:     block.Header,
+		TxHashes:   txHashes,
+		Evidence:   block.Evidence,
+		LastCommit: block.LastCommit,
+	}
+}
+
+// missing returns the txs missing from the block.
+func (pcb *pendingCompactBlock) missing() *cmn.BitArray {
+	missing := cmn.NewBitArray(len(pcb.txs))
+	for i, tx := range pcb.txs {
+		if tx == nil {
+			missing.SetIndex(i, true)
+		}
+	}
+	return missing
+}
+
+// addTxs adds the missing txs, in order, and returns false if one doesn't
+// have the expected hash.
+func (pcb *pendingCompactBlock) addTxs(txs types.Txs) bool {
+	for i := range pcb.txs {
+		if len(txs) == 0 {
+			break
+		}
+		if pcb.txs[i] != nil {
+			continue
+		}
+		if !bytes.Equal(txs[0].Hash(), pcb.msg.TxHashes[i]) {
+			return false
+		}
+		pcb.txs[i], txs = txs[0], txs[1:]
+	}
+	return true
+}
+
+// block returns the reconstructed block, all the txs being present.
+func (pcb *pendingCompactBlock) block() *types.Block {
+	return &types.Block{
+		Header:     pcb.msg.Header,
+		Data:       types.Data{Txs: pcb.txs},
+		Evidence:   pcb.msg.Evidence,
+		LastCommit: pcb.msg.LastCommit,
+	}
+}
+
+// sendCompactBlock sends the compact proposal block to a peer which doesn't
+// have any part of it yet, and returns true if it was sent.
+func (conR *ConsensusReactor) sendCompactBlock(peer p2p.Peer, ps *PeerState,
+	rs *cstypes.RoundState, prs *cstypes.PeerRoundState) bool {
+
+	if !conR.conS.config.CompactBlocks || rs.ProposalBlock == nil || prs.ProposalCompactBlock ||
+		!prs.ProposalBlockParts.IsEmpty() || !peerHasChannel(peer, CompactBlockChannel) {
+		return false
+	}
+	msgBytes := conR.compactBlockMsgBytes(rs)
+	if msgBytes == nil {
+		return false
+	}
+	conR.Logger.Debug("Sending compact block", "peer", peer, "height", prs.Height, "round", prs.Round)
+	if peer.Send(CompactBlockChannel, msgBytes) {
+		ps.SetHasProposalCompactBlock(prs.Height, prs.Round)
+	}
+	return true
+}
+
+// compactBlockMsgBytes returns the encoded compact proposal block, nil if it
+// is too large.
+func (conR *ConsensusReactor) compactBlockMsgBytes(rs *cstypes.RoundState) []byte {
+	hash := rs.ProposalBlock.Hash()
+
+	conR.compactMtx.Lock()
+	defer conR.compactMtx.Unlock()
+	if cache := conR.compactBlock; cache.round == rs.Round && bytes.Equal(cache.hash, hash) {
+		return cache.msgBytes
+	}
+	msgBytes := cdc.MustMarshalBinaryBare(makeCompactBlockMessage(rs.Height, rs.Round, rs.ProposalBlock))
+	if len(msgBytes) > maxMsgSize {
+		msgBytes = nil
+	}
+	conR.compactBlock = compactBlockCache{round: rs.Round, hash: hash, msgBytes: msgBytes}
+	return msgBytes
+}
+
+// handleCompactBlock reconstructs the proposal block from the compact block
+// and the txs of our mempool, requesting the missing txs from the peer.
+func (conR *ConsensusReactor) handleCompactBlock(src p2p.Peer, ps *PeerState, msg *CompactBlockMessage) {
+	finder, ok := conR.conS.txNotifier.(txFinder)
+	if !ok {
+		return
+	}
+	rs := conR.conS.GetRoundState()
+	if rs.Height != msg.Height || (rs.ProposalBlockParts != nil && rs.ProposalBlockParts.IsComplete()) {
+		return
+	}
+
+	hashes := make([][]byte, len(msg.TxHashes))
+	for i, hash := range msg.TxHashes {
+		hashes[i] = hash
+	}
+	conR.tryReconstructCompactBlock(src, ps, &pendingCompactBlock{
+		msg: msg,
+		txs: finder.TxsByHashes(hashes),
+	})
+}
+
+// handleCompactBlockTxs adds the txs the peer sent us to its pending compact
+// block.
+func (conR *ConsensusReactor) handleCompactBlockTxs(src p2p.Peer, ps *PeerState, msg *CompactBlockTxsMessage) {
+	pending := ps.PendingCompactBlock()
+	if pending == nil || pending.msg.Height != msg.Height || pending.msg.Round != msg.Round {
+		return
+	}
+	if !pending.addTxs(msg.Txs) {
+		conR.Logger.Error("Peer sent txs not in its compact block", "peer", src)
+		ps.SetPendingCompactBlock(nil)
+		return
+	}
+	conR.tryReconstructCompactBlock(src, ps, pending)
+}
+
+// tryReconstructCompactBlock requests the txs missing from the compact block,
+// or reconstructs the block and sends its parts to the consensus state.
+func (conR *ConsensusReactor) tryReconstructCompactBlock(src p2p.Peer, ps *PeerState, pending *pendingCompactBlock) {
+	msg := pending.msg
+	if missing := pending.missing(); !missing.IsEmpty() {
+		ps.SetPendingCompactBlock(pending)
+		src.TrySend(CompactBlockChannel, cdc.MustMarshalBinaryBare(&GetCompactBlockTxsMessage{
+			Height:    msg.Height,
+			Round:     msg.Round,
+			BlockHash: msg.Header.Hash(),
+			Missing:   missing,
+		}))
+		return
+	}
+	ps.SetPendingCompactBlock(nil)
+
+	parts := pending.block().MakePartSet(types.BlockPartSizeBytes)
+	rs := conR.conS.GetRoundState()
+	if rs.ProposalBlockParts != nil && !rs.ProposalBlockParts.HasHeader(parts.Header()) {
+		conR.Logger.Info("Compact block doesn't match the proposal", "peer", src,
+			"height", msg.Height, "round", msg.Round)
+		return
+	}
+	conR.Logger.Debug("Reconstructed compact block", "peer", src, "height", msg.Height, "round", msg.Round)
+	for i := 0; i < parts.Total(); i++ {
+		conR.queuePeerMsg(CompactBlockChannel, src, &BlockPartMessage{msg.Height, msg.Round, parts.GetPart(i)})
+	}
+}
+
+// handleGetCompactBlockTxs sends the peer the txs missing from the compact
+// proposal block we sent it, as many as fit in a message.
+func (conR *ConsensusReactor) handleGetCompactBlockTxs(src p2p.Peer, msg *GetCompactBlockTxsMessage) {
+	rs := conR.conS.GetRoundState()
+	block := rs.ProposalBlock
+	if rs.Height != msg.Height || block == nil || !block.HashesTo(msg.BlockHash) ||
+		msg.Missing.Size() != len(block.Txs) {
+		return
+	}
+
+	var (
+		txs  types.Txs
+		size = 0
+	)
+	for i, tx := range block.Txs {
+		if !msg.Missing.GetIndex(i) {
+			continue
+		}
+		size += len(tx) + binary.MaxVarintLen64 + 1
+		if size > maxMsgSize-1024 {
+			break
+		}
+		txs = append(txs, tx)
+	}
+	if len(txs) == 0 {
+		return
+	}
+	src.TrySend(CompactBlockChannel, cdc.MustMarshalBinaryBare(&CompactBlockTxsMessage{
+		Height: msg.Height,
+		Round:  msg.Round,
+		Txs:    txs,
+	}))
+}
@@ -0,0 +1,63 @@
+package consensus
+
+import (
+	"fmt"
+
+	sm "github.com/tendermint/tendermint/state"
+)
+
+// StateInvariantChecks makes the ConsensusState panic with an
+// sm.ErrInvariantViolation at the first inconsistency between the WAL, the
+// block store and the state.
+func StateInvariantChecks() StateOption {
+	return func(cs *ConsensusState) { cs.checkInvariants = true }
+}
+
+// checkStartInvariants checks, before the WAL catchup, that the block store is
+// at the height of the state, and that the WAL is not ahead of them.
+func (cs *ConsensusState) checkStartInvariants() {
+	lastHeight := cs.state.LastBlockHeight
+	if storeHeight := cs.blockStore.Height(); storeHeight != lastHeight {
+		panic(sm.ErrInvariantViolation{
+			Invariant: "block store height",
+			Height:    lastHeight,
+			Diagnostics: []string{
+				fmt.Sprintf("state.LastBlockHeight: %d", lastHeight),
+				fmt.Sprintf("blockStore.Height(): %d", storeHeight),
+			},
+		})
+	}
+
+	if _, ok := cs.wal.(nilWAL); ok || cs.config.WalDisabled {
+		return
+	}
+	gr, found, err := cs.wal.SearchForEndHeight(lastHeight+1, &WALSearchOptions{IgnoreDataCorruptionErrors: true})
+	if gr != nil {
+		gr.Close()
+	}
+	if err == nil && found {
+		panic(sm.ErrInvariantViolation{
+			Invariant: "WAL height",
+			Height:    lastHeight,
+			Diagnostics: []string{
+				fmt.Sprintf("state.LastBlockHeight: %d", lastHeight),
+				fmt.Sprintf("WAL: #ENDHEIGHT %d, written only once the block is stored", lastHeight+1),
+			},
+		})
+	}
+}
+
+// checkEndHeightInvariants checks, before writing the #ENDHEIGHT of height to
+// the WAL, that the block of height is stored.
+func (cs *ConsensusState) checkEndHeightInvariants(height int64) {
+	if storeHeight := cs.blockStore.Height(); storeHeight != height {
+		panic(sm.ErrInvariantViolation{
+			Invariant: "WAL height",
+			Height:    height,
+			Diagnostics: []string{
+				fmt.Sprintf("blockStore.Height(): %d", storeHeight),
+				fmt.Sprintf("WAL: writing #ENDHEIGHT %d", height),
+			},
+		})
+	}
+}
@@ -55,6 +55,21 @@ func TestMempoolProgressAfterCreateEmptyBlocksInterval(t *testing.T) {
 	ensureNewEventOnChannel(newBlockCh)   // until the CreateEmptyBlocksInterval has passed
 }
 
+func TestProgressAfterPersistentPeersQuorum(t *testing.T) {
+	config := ResetConfig("consensus_persistent_peers_quorum_test")
+	defer os.RemoveAll(config.RootDir)
+	config.Consensus.WaitForPersistentPeers = true
+	state, privVals := randGenesisState(1, false, 10)
+	cs := newConsensusStateWithConfig(config, state, privVals[0], NewCounterApplication())
+	height, round := cs.Height, cs.Round
+	newBlockCh := subscribe(cs.eventBus, types.EventQueryNewBlock)
+	startTestRound(cs, height, round)
+
+	ensureNoNewEventOnChannel(newBlockCh) // we dont make the first block ...
+	cs.SetPersistentPeersQuorum()
+	ensureNewEventOnChannel(newBlockCh) // until a quorum of the persistent peers is connected
+}
+
 func TestMempoolProgressInHigherRound(t *testing.T) {
 	config := ResetConfig("consensus_mempool_txs_available_test")
 	defer os.RemoveAll(config.RootDir)
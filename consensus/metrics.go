@@ -21,6 +21,11 @@ type Metrics struct {
 
 	// Number of rounds.
 	Rounds metrics.Gauge
+	// Number of times the round advanced past the current one, by reason
+	// (timeout, polka or commit).
+	RoundsAdvanced metrics.Counter
+	// Number of rounds which ended without a complete proposal, by proposer.
+	MissingProposalRounds metrics.Counter
 
 	// Number of validators.
 	Validators metrics.Gauge
@@ -37,6 +42,8 @@ type Metrics struct {
 
 	// Time between this and the last block.
 	BlockIntervalSeconds metrics.Gauge
+	// Histogram of the time between the blocks.
+	BlockIntervalHistogram metrics.Histogram
 
 	// Number of transactions.
 	NumTxs metrics.Gauge
@@ -51,6 +58,17 @@ type Metrics struct {
 
 	// Number of blockparts transmitted by peer.
 	BlockParts metrics.Counter
+
+	// Estimated skew of the local clock, ahead of the other nodes if positive.
+	ClockSkewSeconds metrics.Gauge
+
+	// Time to write a block to the block store, in one batch.
+	BlockStoreCommitTime metrics.Histogram
+
+	// timeout_propose and timeout_commit, adjusted to the observed latencies
+	// with adaptive_timeouts.
+	TimeoutProposeSeconds metrics.Gauge
+	TimeoutCommitSeconds  metrics.Gauge
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -74,6 +92,18 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "rounds",
 			Help:      "Number of rounds.",
 		}, labels).With(labelsAndValues...),
+		RoundsAdvanced: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rounds_advanced",
+			Help:      "Number of times the round advanced past the current one, by reason (timeout, polka or commit).",
+		}, append(labels, "reason")).With(labelsAndValues...),
+		MissingProposalRounds: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "missing_proposal_rounds",
+			Help:      "Number of rounds which ended without a complete proposal, by proposer.",
+		}, append(labels, "proposer_address")).With(labelsAndValues...),
 
 		Validators: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
 			Namespace: namespace,
@@ -118,6 +148,13 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "block_interval_seconds",
 			Help:      "Time between this and the last block.",
 		}, labels).With(labelsAndValues...),
+		BlockIntervalHistogram: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_interval_histogram_seconds",
+			Help:      "Histogram of the time between the blocks.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.25, 2, 10),
+		}, labels).With(labelsAndValues...),
 
 		NumTxs: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
 			Namespace: namespace,
@@ -155,6 +192,31 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "block_parts",
 			Help:      "Number of blockparts transmitted by peer.",
 		}, append(labels, "peer_id")).With(labelsAndValues...),
+		ClockSkewSeconds: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "clock_skew_seconds",
+			Help:      "Estimated skew of the local clock, ahead of the other nodes if positive.",
+		}, labels).With(labelsAndValues...),
+		BlockStoreCommitTime: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_store_commit_time",
+			Help:      "Time to write a block to the block store in ms.",
+			Buckets:   stdprometheus.ExponentialBuckets(1, 2, 12),
+		}, labels).With(labelsAndValues...),
+		TimeoutProposeSeconds: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "timeout_propose_seconds",
+			Help:      "timeout_propose, adjusted to the observed latencies.",
+		}, labels).With(labelsAndValues...),
+		TimeoutCommitSeconds: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "timeout_commit_seconds",
+			Help:      "timeout_commit, adjusted to the observed latencies.",
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -163,7 +225,9 @@ func NopMetrics() *Metrics {
 	return &Metrics{
 		Height: discard.NewGauge(),
 
-		Rounds: discard.NewGauge(),
+		Rounds:                discard.NewGauge(),
+		RoundsAdvanced:        discard.NewCounter(),
+		MissingProposalRounds: discard.NewCounter(),
 
 		Validators:               discard.NewGauge(),
 		ValidatorsPower:          discard.NewGauge(),
@@ -172,7 +236,8 @@ func NopMetrics() *Metrics {
 		ByzantineValidators:      discard.NewGauge(),
 		ByzantineValidatorsPower: discard.NewGauge(),
 
-		BlockIntervalSeconds: discard.NewGauge(),
+		BlockIntervalSeconds:   discard.NewGauge(),
+		BlockIntervalHistogram: discard.NewHistogram(),
 
 		NumTxs:          discard.NewGauge(),
 		BlockSizeBytes:  discard.NewGauge(),
@@ -180,5 +245,11 @@ func NopMetrics() *Metrics {
 		CommittedHeight: discard.NewGauge(),
 		FastSyncing:     discard.NewGauge(),
 		BlockParts:      discard.NewCounter(),
+
+		ClockSkewSeconds:     discard.NewGauge(),
+		BlockStoreCommitTime: discard.NewHistogram(),
+
+		TimeoutProposeSeconds: discard.NewGauge(),
+		TimeoutCommitSeconds:  discard.NewGauge(),
 	}
 }
@@ -1,6 +1,7 @@
 package consensus
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
 	"sync"
@@ -10,6 +11,7 @@ import (
 
 	amino "github.com/tendermint/go-amino"
 	cstypes "github.com/tendermint/tendermint/consensus/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	tmevents "github.com/tendermint/tendermint/libs/events"
 	"github.com/tendermint/tendermint/libs/log"
@@ -20,15 +22,22 @@ import (
 )
 
 const (
-	StateChannel       = byte(0x20)
-	DataChannel        = byte(0x21)
-	VoteChannel        = byte(0x22)
-	VoteSetBitsChannel = byte(0x23)
+	StateChannel        = byte(0x20)
+	DataChannel         = byte(0x21)
+	VoteChannel         = byte(0x22)
+	VoteSetBitsChannel  = byte(0x23)
+	DataParityChannel   = byte(0x24)
+	CompactBlockChannel = byte(0x25)
+	CommitChannel       = byte(0x26)
 
 	maxMsgSize = 1048576 // 1MB; NOTE/TODO: keep in sync with types.PartSet sizes.
 
 	blocksToContributeToBecomeGoodPeer = 10000
 	votesToContributeToBecomeGoodPeer  = 10000
+
+	// Interval of checking whether the msg queue of the consensus state
+	// drained, to set the busy peers ready again.
+	busyPeersCheckInterval = 10 * time.Millisecond
 )
 
 //-----------------------------------------------------------------------------
@@ -43,7 +52,23 @@ type ConsensusReactor struct {
 	fastSync bool
 	eventBus *types.EventBus
 
-	metrics *Metrics
+	metrics  *Metrics
+	recorder *MsgRecorder // nil - received messages aren't recorded
+
+	compactMtx   sync.Mutex
+	compactBlock compactBlockCache
+
+	numPersistentPeers int // to wait for a quorum of before the first block
+
+	// the channels of the peers set busy while the msg queue of the
+	// consensus state is full.
+	busyMtx   sync.Mutex
+	busyPeers map[busyPeerKey]p2p.Peer
+}
+
+type busyPeerKey struct {
+	id   p2p.ID
+	chID byte
 }
 
 type ReactorOption func(*ConsensusReactor)
@@ -52,9 +77,10 @@ type ReactorOption func(*ConsensusReactor)
 // consensusState.
 func NewConsensusReactor(consensusState *ConsensusState, fastSync bool, options ...ReactorOption) *ConsensusReactor {
 	conR := &ConsensusReactor{
-		conS:     consensusState,
-		fastSync: fastSync,
-		metrics:  NopMetrics(),
+		conS:      consensusState,
+		fastSync:  fastSync,
+		metrics:   NopMetrics(),
+		busyPeers: make(map[busyPeerKey]p2p.Peer),
 	}
 	conR.updateFastSyncingMetric()
 	conR.BaseReactor = *p2p.NewBaseReactor("ConsensusReactor", conR)
@@ -76,6 +102,14 @@ func (conR *ConsensusReactor) OnStart() error {
 
 	conR.subscribeToBroadcastEvents()
 
+	if conR.recorder != nil {
+		if err := conR.recorder.Start(); err != nil {
+			return err
+		}
+	}
+
+	conR.checkPersistentPeersQuorum(nil)
+
 	if !conR.FastSync() {
 		err := conR.conS.Start()
 		if err != nil {
@@ -94,6 +128,9 @@ func (conR *ConsensusReactor) OnStop() {
 	if !conR.FastSync() {
 		conR.conS.Wait()
 	}
+	if conR.recorder != nil {
+		conR.recorder.Stop()
+	}
 }
 
 // SwitchToConsensus switches from fast_sync mode to consensus mode.
@@ -152,6 +189,27 @@ func (conR *ConsensusReactor) GetChannels() []*p2p.ChannelDescriptor {
 			RecvBufferCapacity:  1024,
 			RecvMessageCapacity: maxMsgSize,
 		},
+		{
+			ID:                  DataParityChannel, // parity parts of erasure coded proposal blocks
+			Priority:            10,
+			SendQueueCapacity:   100,
+			RecvBufferCapacity:  50 * 4096,
+			RecvMessageCapacity: maxMsgSize,
+		},
+		{
+			ID:                  CompactBlockChannel, // compact proposal blocks and their missing txs
+			Priority:            10,
+			SendQueueCapacity:   10,
+			RecvBufferCapacity:  50 * 4096,
+			RecvMessageCapacity: maxMsgSize,
+		},
+		{
+			ID:                  CommitChannel, // commits of the peers lagging behind
+			Priority:            5,
+			SendQueueCapacity:   2,
+			RecvBufferCapacity:  100 * 100,
+			RecvMessageCapacity: maxMsgSize,
+		},
 	}
 }
 
@@ -165,6 +223,19 @@ func (conR *ConsensusReactor) AddPeer(peer p2p.Peer) {
 	peerState := NewPeerState(peer).SetLogger(conR.Logger)
 	peer.Set(types.PeerStateKey, peerState)
 
+	// Measure the skew of our clock against the one of the peer at the
+	// handshake, which was just before, or use the offset estimated after it,
+	// which accounts for the network delay.
+	if offset := peer.ClockOffset(); offset != nil {
+		conR.conS.clockSkew.AddPeer(peer.ID(), -offset.Offset)
+	} else if nodeInfo, ok := peer.NodeInfo().(p2p.DefaultNodeInfo); ok && !nodeInfo.Time.IsZero() {
+		conR.conS.clockSkew.AddPeer(peer.ID(), tmtime.Now().Sub(nodeInfo.Time))
+	}
+
+	if peer.IsPersistent() {
+		conR.checkPersistentPeersQuorum(peer)
+	}
+
 	// Begin routines for this peer.
 	go conR.gossipDataRoutine(peer, peerState)
 	go conR.gossipVotesRoutine(peer, peerState)
@@ -182,6 +253,7 @@ func (conR *ConsensusReactor) RemovePeer(peer p2p.Peer, reason interface{}) {
 	if !conR.IsRunning() {
 		return
 	}
+	conR.conS.clockSkew.RemovePeer(peer.ID())
 	// TODO
 	// ps, ok := peer.Get(PeerStateKey).(*PeerState)
 	// if !ok {
@@ -190,6 +262,26 @@ func (conR *ConsensusReactor) RemovePeer(peer p2p.Peer, reason interface{}) {
 	// ps.Disconnect()
 }
 
+// checkPersistentPeersQuorum lets the consensus state propose the first block
+// once more than 2/3 of the persistent peers are connected, including the
+// peer being added.
+func (conR *ConsensusReactor) checkPersistentPeersQuorum(added p2p.Peer) {
+	connected := 0
+	if added != nil {
+		connected++
+	}
+	if conR.Switch != nil {
+		for _, peer := range conR.Switch.Peers().List() {
+			if peer.IsPersistent() && (added == nil || peer.ID() != added.ID()) {
+				connected++
+			}
+		}
+	}
+	if conR.numPersistentPeers == 0 || connected*3 > conR.numPersistentPeers*2 {
+		conR.conS.SetPersistentPeersQuorum()
+	}
+}
+
 // Receive implements Reactor
 // NOTE: We process these messages even when we're fast_syncing.
 // Messages affect either a peer state or the consensus state.
@@ -217,6 +309,10 @@ func (conR *ConsensusReactor) Receive(chID byte, src p2p.Peer, msgBytes []byte)
 
 	conR.Logger.Debug("Receive", "src", src, "chId", chID, "msg", msg)
 
+	if conR.recorder != nil {
+		conR.recorder.Record(msg, src.ID())
+	}
+
 	// Get peer states
 	ps, ok := src.Get(types.PeerStateKey).(*PeerState)
 	if !ok {
@@ -276,13 +372,47 @@ func (conR *ConsensusReactor) Receive(chID byte, src p2p.Peer, msgBytes []byte)
 		switch msg := msg.(type) {
 		case *ProposalMessage:
 			ps.SetHasProposal(msg.Proposal)
-			conR.conS.peerMsgQueue <- msgInfo{msg, src.ID()}
+			conR.queuePeerMsg(chID, src, msg)
 		case *ProposalPOLMessage:
 			ps.ApplyProposalPOLMessage(msg)
 		case *BlockPartMessage:
 			ps.SetHasProposalBlockPart(msg.Height, msg.Round, msg.Part.Index)
 			conR.metrics.BlockParts.With("peer_id", string(src.ID())).Add(1)
-			conR.conS.peerMsgQueue <- msgInfo{msg, src.ID()}
+			conR.queuePeerMsg(chID, src, msg)
+		default:
+			conR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
+		}
+
+	case DataParityChannel:
+		if conR.FastSync() {
+			conR.Logger.Info("Ignoring message received during fastSync", "msg", msg)
+			return
+		}
+		switch msg := msg.(type) {
+		case *BlockParityPartMessage:
+			ps.SetHasProposalBlockParityPart(msg.Height, msg.Round, msg.Part.Index, msg.Part.Total)
+			conR.metrics.BlockParts.With("peer_id", string(src.ID())).Add(1)
+			conR.queuePeerMsg(chID, src, msg)
+		case *HasProposalBlockMessage:
+			ps.SetHasProposalBlock(msg.Height, msg.Round)
+		default:
+			conR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
+		}
+
+	case CompactBlockChannel:
+		if conR.FastSync() {
+			conR.Logger.Info("Ignoring message received during fastSync", "msg", msg)
+			return
+		}
+		switch msg := msg.(type) {
+		case *CompactBlockMessage:
+			// The peer has the complete block.
+			ps.SetHasProposalBlock(msg.Height, msg.Round)
+			conR.handleCompactBlock(src, ps, msg)
+		case *GetCompactBlockTxsMessage:
+			conR.handleGetCompactBlockTxs(src, msg)
+		case *CompactBlockTxsMessage:
+			conR.handleCompactBlockTxs(src, ps, msg)
 		default:
 			conR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
 		}
@@ -302,13 +432,25 @@ func (conR *ConsensusReactor) Receive(chID byte, src p2p.Peer, msgBytes []byte)
 			ps.EnsureVoteBitArrays(height-1, lastCommitSize)
 			ps.SetHasVote(msg.Vote)
 
-			cs.peerMsgQueue <- msgInfo{msg, src.ID()}
+			conR.queuePeerMsg(chID, src, msg)
 
 		default:
 			// don't punish (leave room for soft upgrades)
 			conR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
 		}
 
+	case CommitChannel:
+		if conR.FastSync() {
+			conR.Logger.Info("Ignoring message received during fastSync", "msg", msg)
+			return
+		}
+		switch msg := msg.(type) {
+		case *CommitMessage:
+			conR.handleCommit(src, ps, msg)
+		default:
+			conR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
+		}
+
 	case VoteSetBitsChannel:
 		if conR.FastSync() {
 			conR.Logger.Info("Ignoring message received during fastSync", "msg", msg)
@@ -384,6 +526,10 @@ func (conR *ConsensusReactor) subscribeToBroadcastEvents() {
 			conR.broadcastHasVoteMessage(data.(*types.Vote))
 		})
 
+	conR.conS.evsw.AddListenerForEvent(subscriber, types.EventCompleteProposal,
+		func(data tmevents.EventData) {
+			conR.broadcastHasProposalBlockMessage(data.(*cstypes.RoundState))
+		})
 }
 
 func (conR *ConsensusReactor) unsubscribeFromBroadcastEvents() {
@@ -396,6 +542,16 @@ func (conR *ConsensusReactor) broadcastNewRoundStepMessage(rs *cstypes.RoundStat
 	conR.Switch.Broadcast(StateChannel, cdc.MustMarshalBinaryBare(nrsMsg))
 }
 
+// Tells the peers supporting erasure coded proposal blocks that we have the
+// complete proposal block, so that they stop sending us parts.
+func (conR *ConsensusReactor) broadcastHasProposalBlockMessage(rs *cstypes.RoundState) {
+	msg := &HasProposalBlockMessage{
+		Height: rs.Height,
+		Round:  rs.Round,
+	}
+	conR.Switch.Broadcast(DataParityChannel, cdc.MustMarshalBinaryBare(msg))
+}
+
 func (conR *ConsensusReactor) broadcastNewValidBlockMessage(rs *cstypes.RoundState) {
 	csMsg := &NewValidBlockMessage{
 		Height:           rs.Height,
@@ -468,7 +624,36 @@ OUTER_LOOP:
 
 		// Send proposal Block parts?
 		if rs.ProposalBlockParts.HasHeader(prs.ProposalBlockPartsHeader) {
-			if index, ok := rs.ProposalBlockParts.BitArray().Sub(prs.ProposalBlockParts.Copy()).PickRandom(); ok {
+			// Send the compact block first, and give the peer time to
+			// reconstruct the block from its mempool.
+			if conR.sendCompactBlock(peer, ps, rs, prs) {
+				continue OUTER_LOOP
+			}
+			if ps.AwaitingCompactBlock(compactBlockTimeout) {
+				time.Sleep(conR.conS.config.PeerGossipSleepDuration)
+				continue OUTER_LOOP
+			}
+			missing := rs.ProposalBlockParts.BitArray().Sub(prs.ProposalBlockParts.Copy())
+			// Send a parity part instead, with the probability of parity
+			// parts among the parts the peer doesn't have.
+			if index, ok := conR.pickProposalBlockParityPart(peer, rs, prs, missing); ok {
+				part := rs.ProposalBlockParts.GetParityPart(index)
+				if part == nil {
+					// the parity parts were dropped meanwhile
+					continue OUTER_LOOP
+				}
+				msg := &BlockParityPartMessage{
+					Height: rs.Height,
+					Round:  rs.Round,
+					Part:   part,
+				}
+				logger.Debug("Sending block parity part", "height", prs.Height, "round", prs.Round)
+				if peer.Send(DataParityChannel, cdc.MustMarshalBinaryBare(msg)) {
+					ps.SetHasProposalBlockParityPart(prs.Height, prs.Round, index, part.Total)
+				}
+				continue OUTER_LOOP
+			}
+			if index, ok := missing.PickRandom(); ok {
 				part := rs.ProposalBlockParts.GetPart(index)
 				msg := &BlockPartMessage{
 					Height: rs.Height, // This tells peer that this part applies to us.
@@ -547,6 +732,125 @@ OUTER_LOOP:
 	}
 }
 
+// pickProposalBlockParityPart picks a parity part of the proposal block the
+// peer doesn't have, if the block is erasure coded and the peer supports it,
+// with the probability of parity parts among the parts (missing) and parity
+// parts the peer doesn't have. It returns false if a part should be sent
+// instead, or nothing.
+func (conR *ConsensusReactor) pickProposalBlockParityPart(
+	peer p2p.Peer,
+	rs *cstypes.RoundState,
+	prs *cstypes.PeerRoundState,
+	missing *cmn.BitArray,
+) (int, bool) {
+	parity := rs.ProposalBlockParts.ParityBitArray()
+	if parity == nil || !peerHasChannel(peer, DataParityChannel) {
+		return 0, false
+	}
+	if peerParity := prs.ProposalBlockParityParts; peerParity != nil && peerParity.Size() == parity.Size() {
+		parity = parity.Sub(peerParity.Copy())
+	}
+	numMissing, numParity := countTrue(missing), countTrue(parity)
+	// The peer has the block if it doesn't miss any part.
+	if numMissing == 0 || numParity == 0 || cmn.RandIntn(numMissing+numParity) < numMissing {
+		return 0, false
+	}
+	return parity.PickRandom()
+}
+
+// handleCommit queues the precommits of the commit the peer sent us, if it is
+// the one of our height, as votes of the peer, which has them.
+func (conR *ConsensusReactor) handleCommit(src p2p.Peer, ps *PeerState, msg *CommitMessage) {
+	cs := conR.conS
+	cs.mtx.RLock()
+	height, valSize := cs.Height, cs.Validators.Size()
+	cs.mtx.RUnlock()
+	// Only the precommits of our height are of use, the peer is ahead.
+	if msg.Commit.Height() != height {
+		return
+	}
+	ps.EnsureVoteBitArrays(height, valSize)
+	for i := range msg.Commit.Precommits {
+		if vote := msg.Commit.GetByIndex(i); vote != nil {
+			ps.SetHasVote(vote)
+			conR.queuePeerMsg(CommitChannel, src, &VoteMessage{vote})
+		}
+	}
+}
+
+// queuePeerMsg queues the msg the peer sent on the channel for the consensus
+// state, without blocking. Once the queue is full, the channel of the peer is
+// set busy, so the connection queues its next msgs until the queue drains. A
+// msg finding the queue full anyway, e.g. sent by another peer meanwhile, is
+// dropped: the peers send the votes and block parts we miss again, once they
+// learn it from our VoteSetBits and round steps.
+func (conR *ConsensusReactor) queuePeerMsg(chID byte, src p2p.Peer, msg ConsensusMessage) {
+	cs := conR.conS
+	queued := cs.queuePeerMsg(msgInfo{msg, src.ID()})
+	if !queued {
+		conR.Logger.Debug("Dropping msg, the consensus queue is full", "peer", src, "msg", msg)
+	}
+	if conR.Switch != nil && (!queued || len(cs.peerMsgQueue) >= cap(cs.peerMsgQueue)) {
+		conR.setPeerBusy(chID, src)
+	}
+}
+
+func (conR *ConsensusReactor) setPeerBusy(chID byte, peer p2p.Peer) {
+	conR.busyMtx.Lock()
+	defer conR.busyMtx.Unlock()
+	key := busyPeerKey{peer.ID(), chID}
+	if _, ok := conR.busyPeers[key]; ok {
+		return
+	}
+	if len(conR.busyPeers) == 0 {
+		go conR.readyPeersRoutine()
+	}
+	conR.busyPeers[key] = peer
+	conR.Switch.SetPeerBusy(peer, chID)
+}
+
+// readyPeersRoutine sets the busy peers ready once the msg queue of the
+// consensus state is drained to half of its capacity.
+func (conR *ConsensusReactor) readyPeersRoutine() {
+	ticker := time.NewTicker(busyPeersCheckInterval)
+	defer ticker.Stop()
+	queue := conR.conS.peerMsgQueue
+	for {
+		select {
+		case <-ticker.C:
+		case <-conR.Quit():
+			return
+		}
+		if len(queue) > cap(queue)/2 {
+			continue
+		}
+		conR.busyMtx.Lock()
+		for key, peer := range conR.busyPeers {
+			conR.Switch.SetPeerReady(peer, key.chID)
+			delete(conR.busyPeers, key)
+		}
+		conR.busyMtx.Unlock()
+		return
+	}
+}
+
+// peerHasChannel returns true if the peer knows about the channel.
+func peerHasChannel(peer p2p.Peer, chID byte) bool {
+	nodeInfo, ok := peer.NodeInfo().(p2p.DefaultNodeInfo)
+	return ok && bytes.IndexByte(nodeInfo.Channels, chID) != -1
+}
+
+// countTrue returns the number of bits set in bA.
+func countTrue(bA *cmn.BitArray) int {
+	n := 0
+	for i := 0; i < bA.Size(); i++ {
+		if bA.GetIndex(i) {
+			n++
+		}
+	}
+	return n
+}
+
 func (conR *ConsensusReactor) gossipDataForCatchup(logger log.Logger, rs *cstypes.RoundState,
 	prs *cstypes.PeerRoundState, ps *PeerState, peer p2p.Peer) {
 
@@ -639,6 +943,11 @@ OUTER_LOOP:
 			// Load the block commit for prs.Height,
 			// which contains precommit signatures for prs.Height.
 			commit := conR.conS.blockStore.LoadBlockCommit(prs.Height)
+			// Send the whole commit at once, then the votes it misses, if any.
+			if ps.PickSendCommit(commit) {
+				logger.Debug("Sent Catchup commit", "height", prs.Height)
+				continue OUTER_LOOP
+			}
 			if ps.PickSendVote(commit) {
 				logger.Debug("Picked Catchup commit to send", "height", prs.Height)
 				continue OUTER_LOOP
@@ -829,7 +1138,7 @@ func (conR *ConsensusReactor) peerStatsRoutine() {
 				if numVotes := ps.RecordVote(); numVotes%votesToContributeToBecomeGoodPeer == 0 {
 					conR.Switch.MarkPeerAsGood(peer)
 				}
-			case *BlockPartMessage:
+			case *BlockPartMessage, *BlockParityPartMessage:
 				if numParts := ps.RecordBlockPart(); numParts%blocksToContributeToBecomeGoodPeer == 0 {
 					conR.Switch.MarkPeerAsGood(peer)
 				}
@@ -881,6 +1190,19 @@ func ReactorMetrics(metrics *Metrics) ReactorOption {
 	return func(conR *ConsensusReactor) { conR.metrics = metrics }
 }
 
+// ReactorPersistentPeers sets the number of persistent peers, more than 2/3 of
+// which must be connected to propose the first block with
+// WaitForPersistentPeers.
+func ReactorPersistentPeers(numPersistentPeers int) ReactorOption {
+	return func(conR *ConsensusReactor) { conR.numPersistentPeers = numPersistentPeers }
+}
+
+// ReactorMsgRecorder sets the recorder of the received messages. The reactor
+// starts and stops it.
+func ReactorMsgRecorder(recorder *MsgRecorder) ReactorOption {
+	return func(conR *ConsensusReactor) { conR.recorder = recorder }
+}
+
 //-----------------------------------------------------------------------------
 
 var (
@@ -899,6 +1221,10 @@ type PeerState struct {
 	mtx   sync.Mutex             // NOTE: Modify below using setters, never directly.
 	PRS   cstypes.PeerRoundState `json:"round_state"` // Exposed.
 	Stats *peerStateStats        `json:"stats"`       // Exposed.
+
+	compactBlockSentAt time.Time            // when the compact proposal block was sent
+	compactBlock       *pendingCompactBlock // compact proposal block received, waiting for txs
+	commitSentHeight   int64                // height of the last catchup commit sent
 }
 
 // peerStateStats holds internal statistics for a peer.
@@ -982,6 +1308,7 @@ func (ps *PeerState) SetHasProposal(proposal *types.Proposal) {
 
 	ps.PRS.ProposalBlockPartsHeader = proposal.BlockID.PartsHeader
 	ps.PRS.ProposalBlockParts = cmn.NewBitArray(proposal.BlockID.PartsHeader.Total)
+	ps.PRS.ProposalBlockParityParts = nil
 	ps.PRS.ProposalPOLRound = proposal.POLRound
 	ps.PRS.ProposalPOL = nil // Nil until ProposalPOLMessage received.
 }
@@ -997,6 +1324,7 @@ func (ps *PeerState) InitProposalBlockParts(partsHeader types.PartSetHeader) {
 
 	ps.PRS.ProposalBlockPartsHeader = partsHeader
 	ps.PRS.ProposalBlockParts = cmn.NewBitArray(partsHeader.Total)
+	ps.PRS.ProposalBlockParityParts = nil
 }
 
 // SetHasProposalBlockPart sets the given block part index as known for the peer.
@@ -1011,6 +1339,77 @@ func (ps *PeerState) SetHasProposalBlockPart(height int64, round int, index int)
 	ps.PRS.ProposalBlockParts.SetIndex(index, true)
 }
 
+// SetHasProposalBlockParityPart sets the given block parity part index, of
+// total parity parts, as known for the peer.
+func (ps *PeerState) SetHasProposalBlockParityPart(height int64, round int, index int, total int) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	if ps.PRS.Height != height || ps.PRS.Round != round {
+		return
+	}
+
+	if ps.PRS.ProposalBlockParityParts.Size() != total {
+		ps.PRS.ProposalBlockParityParts = cmn.NewBitArray(total)
+	}
+	ps.PRS.ProposalBlockParityParts.SetIndex(index, true)
+}
+
+// SetHasProposalBlock sets all the block parts as known for the peer, which
+// has the complete proposal block.
+func (ps *PeerState) SetHasProposalBlock(height int64, round int) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	if ps.PRS.Height != height || ps.PRS.Round != round || ps.PRS.ProposalBlockParts == nil {
+		return
+	}
+
+	ps.PRS.ProposalBlockParts = cmn.NewBitArray(ps.PRS.ProposalBlockPartsHeader.Total).Not()
+}
+
+// SetHasProposalCompactBlock records that the compact proposal block was sent
+// to the peer.
+func (ps *PeerState) SetHasProposalCompactBlock(height int64, round int) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	if ps.PRS.Height != height || ps.PRS.Round != round {
+		return
+	}
+
+	ps.PRS.ProposalCompactBlock = true
+	ps.compactBlockSentAt = time.Now()
+}
+
+// AwaitingCompactBlock returns true if the peer may still be reconstructing
+// the proposal block from the compact block sent less than timeout ago.
+func (ps *PeerState) AwaitingCompactBlock(timeout time.Duration) bool {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	return ps.PRS.ProposalCompactBlock && time.Since(ps.compactBlockSentAt) < timeout &&
+		!ps.PRS.ProposalBlockParts.IsFull()
+}
+
+// PendingCompactBlock returns the compact proposal block received from the
+// peer and waiting for the txs missing from our mempool, or nil.
+func (ps *PeerState) PendingCompactBlock() *pendingCompactBlock {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	return ps.compactBlock
+}
+
+// SetPendingCompactBlock sets the compact proposal block received from the
+// peer and waiting for txs.
+func (ps *PeerState) SetPendingCompactBlock(pending *pendingCompactBlock) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	ps.compactBlock = pending
+}
+
 // PickSendVote picks a vote and sends it to the peer.
 // Returns true if vote was sent.
 func (ps *PeerState) PickSendVote(votes types.VoteSetReader) bool {
@@ -1026,6 +1425,56 @@ func (ps *PeerState) PickSendVote(votes types.VoteSetReader) bool {
 	return false
 }
 
+// PickSendCommit sends the commit of the peer's height to the peer, at most
+// once per height, unless it has all of its precommits already, or it doesn't
+// know about the CommitChannel.
+// Returns true if the commit was sent.
+func (ps *PeerState) PickSendCommit(commit *types.Commit) bool {
+	if commit == nil || !peerHasChannel(ps.peer, CommitChannel) || !ps.pickCommitToSend(commit) {
+		return false
+	}
+	msg := &CommitMessage{commit}
+	ps.logger.Debug("Sending commit message", "ps", ps, "height", commit.Height())
+	if ps.peer.Send(CommitChannel, cdc.MustMarshalBinaryBare(msg)) {
+		ps.setHasCommit(commit)
+		return true
+	}
+	return false
+}
+
+func (ps *PeerState) pickCommitToSend(commit *types.Commit) bool {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	height, round, size := commit.Height(), commit.Round(), commit.Size()
+	if size == 0 || ps.PRS.Height != height || ps.commitSentHeight == height {
+		return false
+	}
+	ps.ensureCatchupCommitRound(height, round, size)
+	ps.ensureVoteBitArrays(height, size)
+
+	psVotes := ps.getVoteBitArray(height, round, types.PrecommitType)
+	if psVotes == nil {
+		return false
+	}
+	missing := commit.BitArray().Sub(psVotes)
+	return !missing.IsEmpty()
+}
+
+// setHasCommit marks the precommits of the commit as sent to the peer.
+func (ps *PeerState) setHasCommit(commit *types.Commit) {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	height, round := commit.Height(), commit.Round()
+	ps.commitSentHeight = height
+	for index, precommit := range commit.Precommits {
+		if precommit != nil {
+			ps.setHasVote(height, round, types.PrecommitType, index)
+		}
+	}
+}
+
 // PickVoteToSend picks a vote to send to the peer.
 // Returns true if a vote was picked.
 // NOTE: `votes` must be the correct Size() for the Height().
@@ -1237,8 +1686,11 @@ func (ps *PeerState) ApplyNewRoundStepMessage(msg *NewRoundStepMessage) {
 		ps.PRS.Proposal = false
 		ps.PRS.ProposalBlockPartsHeader = types.PartSetHeader{}
 		ps.PRS.ProposalBlockParts = nil
+		ps.PRS.ProposalBlockParityParts = nil
+		ps.PRS.ProposalCompactBlock = false
 		ps.PRS.ProposalPOLRound = -1
 		ps.PRS.ProposalPOL = nil
+		ps.compactBlock = nil
 		// We'll update the BitArray capacity later.
 		ps.PRS.Prevotes = nil
 		ps.PRS.Precommits = nil
@@ -1280,6 +1732,7 @@ func (ps *PeerState) ApplyNewValidBlockMessage(msg *NewValidBlockMessage) {
 
 	ps.PRS.ProposalBlockPartsHeader = msg.BlockPartsHeader
 	ps.PRS.ProposalBlockParts = msg.BlockParts
+	ps.PRS.ProposalBlockParityParts = nil
 }
 
 // ApplyProposalPOLMessage updates the peer state for the new proposal POL.
@@ -1367,10 +1820,16 @@ func RegisterConsensusMessages(cdc *amino.Codec) {
 	cdc.RegisterConcrete(&ProposalMessage{}, "tendermint/Proposal", nil)
 	cdc.RegisterConcrete(&ProposalPOLMessage{}, "tendermint/ProposalPOL", nil)
 	cdc.RegisterConcrete(&BlockPartMessage{}, "tendermint/BlockPart", nil)
+	cdc.RegisterConcrete(&BlockParityPartMessage{}, "tendermint/BlockParityPart", nil)
+	cdc.RegisterConcrete(&HasProposalBlockMessage{}, "tendermint/HasProposalBlock", nil)
+	cdc.RegisterConcrete(&CompactBlockMessage{}, "tendermint/CompactBlock", nil)
+	cdc.RegisterConcrete(&GetCompactBlockTxsMessage{}, "tendermint/GetCompactBlockTxs", nil)
+	cdc.RegisterConcrete(&CompactBlockTxsMessage{}, "tendermint/CompactBlockTxs", nil)
 	cdc.RegisterConcrete(&VoteMessage{}, "tendermint/Vote", nil)
 	cdc.RegisterConcrete(&HasVoteMessage{}, "tendermint/HasVote", nil)
 	cdc.RegisterConcrete(&VoteSetMaj23Message{}, "tendermint/VoteSetMaj23", nil)
 	cdc.RegisterConcrete(&VoteSetBitsMessage{}, "tendermint/VoteSetBits", nil)
+	cdc.RegisterConcrete(&CommitMessage{}, "tendermint/Commit", nil)
 }
 
 func decodeMsg(bz []byte) (msg ConsensusMessage, err error) {
@@ -1533,6 +1992,168 @@ func (m *BlockPartMessage) String() string {
 
 //-------------------------------------
 
+// BlockParityPartMessage is sent when gossiping a parity part of an erasure
+// coded proposal block, on the DataParityChannel.
+type BlockParityPartMessage struct {
+	Height int64
+	Round  int
+	Part   *types.ParityPart
+}
+
+// ValidateBasic performs basic validation.
+func (m *BlockParityPartMessage) ValidateBasic() error {
+	if m.Height < 0 {
+		return errors.New("Negative Height")
+	}
+	if m.Round < 0 {
+		return errors.New("Negative Round")
+	}
+	if m.Part == nil {
+		return errors.New("Missing Part")
+	}
+	if err := m.Part.ValidateBasic(); err != nil {
+		return fmt.Errorf("Wrong Part: %v", err)
+	}
+	return nil
+}
+
+// String returns a string representation.
+func (m *BlockParityPartMessage) String() string {
+	return fmt.Sprintf("[BlockParityPart H:%v R:%v P:%v]", m.Height, m.Round, m.Part)
+}
+
+//-------------------------------------
+
+// HasProposalBlockMessage is sent on the DataParityChannel when a node has
+// the complete proposal block, so that its peers stop sending it parts.
+type HasProposalBlockMessage struct {
+	Height int64
+	Round  int
+}
+
+// ValidateBasic performs basic validation.
+func (m *HasProposalBlockMessage) ValidateBasic() error {
+	if m.Height < 0 {
+		return errors.New("Negative Height")
+	}
+	if m.Round < 0 {
+		return errors.New("Negative Round")
+	}
+	return nil
+}
+
+// String returns a string representation.
+func (m *HasProposalBlockMessage) String() string {
+	return fmt.Sprintf("[HasProposalBlock H:%v R:%v]", m.Height, m.Round)
+}
+
+//-------------------------------------
+
+// CompactBlockMessage is sent on the CompactBlockChannel to relay the proposal
+// block with the hashes of its txs instead of the txs, which the peer finds
+// in its mempool.
+type CompactBlockMessage struct {
+	Height     int64
+	Round      int
+	Header     types.Header
+	TxHashes   []cmn.HexBytes
+	Evidence   types.EvidenceData
+	LastCommit *types.Commit
+}
+
+// ValidateBasic performs basic validation.
+func (m *CompactBlockMessage) ValidateBasic() error {
+	if m.Height < 0 {
+		return errors.New("Negative Height")
+	}
+	if m.Round < 0 {
+		return errors.New("Negative Round")
+	}
+	if m.Header.Height != m.Height {
+		return fmt.Errorf("Wrong Header.Height: %v, expected %v", m.Header.Height, m.Height)
+	}
+	if int64(len(m.TxHashes)) != m.Header.NumTxs {
+		return fmt.Errorf("Wrong number of TxHashes: %v, expected %v", len(m.TxHashes), m.Header.NumTxs)
+	}
+	for i, hash := range m.TxHashes {
+		if len(hash) != tmhash.Size {
+			return fmt.Errorf("Wrong TxHashes #%d: expected size to be %d bytes, got %d bytes",
+				i, tmhash.Size, len(hash))
+		}
+	}
+	if m.LastCommit == nil {
+		return errors.New("Missing LastCommit")
+	}
+	return nil
+}
+
+// String returns a string representation.
+func (m *CompactBlockMessage) String() string {
+	return fmt.Sprintf("[CompactBlock H:%v R:%v Txs:%v]", m.Height, m.Round, len(m.TxHashes))
+}
+
+//-------------------------------------
+
+// GetCompactBlockTxsMessage is sent on the CompactBlockChannel to request the
+// txs of a compact block missing from the mempool.
+type GetCompactBlockTxsMessage struct {
+	Height    int64
+	Round     int
+	BlockHash cmn.HexBytes
+	Missing   *cmn.BitArray
+}
+
+// ValidateBasic performs basic validation.
+func (m *GetCompactBlockTxsMessage) ValidateBasic() error {
+	if m.Height < 0 {
+		return errors.New("Negative Height")
+	}
+	if m.Round < 0 {
+		return errors.New("Negative Round")
+	}
+	if err := types.ValidateHash(m.BlockHash); err != nil {
+		return fmt.Errorf("Wrong BlockHash: %v", err)
+	}
+	if m.Missing == nil {
+		return errors.New("Missing Missing")
+	}
+	return nil
+}
+
+// String returns a string representation.
+func (m *GetCompactBlockTxsMessage) String() string {
+	return fmt.Sprintf("[GetCompactBlockTxs H:%v R:%v %X %v]", m.Height, m.Round,
+		cmn.Fingerprint(m.BlockHash), m.Missing)
+}
+
+//-------------------------------------
+
+// CompactBlockTxsMessage is sent on the CompactBlockChannel in response to a
+// GetCompactBlockTxsMessage, with the missing txs in order.
+type CompactBlockTxsMessage struct {
+	Height int64
+	Round  int
+	Txs    types.Txs
+}
+
+// ValidateBasic performs basic validation.
+func (m *CompactBlockTxsMessage) ValidateBasic() error {
+	if m.Height < 0 {
+		return errors.New("Negative Height")
+	}
+	if m.Round < 0 {
+		return errors.New("Negative Round")
+	}
+	return nil
+}
+
+// String returns a string representation.
+func (m *CompactBlockTxsMessage) String() string {
+	return fmt.Sprintf("[CompactBlockTxs H:%v R:%v Txs:%v]", m.Height, m.Round, len(m.Txs))
+}
+
+//-------------------------------------
+
 // VoteMessage is sent when voting for a proposal (or lack thereof).
 type VoteMessage struct {
 	Vote *types.Vote
@@ -1550,6 +2171,28 @@ func (m *VoteMessage) String() string {
 
 //-------------------------------------
 
+// CommitMessage is sent on the CommitChannel to a peer lagging behind by more
+// than one height, with the commit of its height, so it gets the precommits at once instead of one
+// vote at a time.
+type CommitMessage struct {
+	Commit *types.Commit
+}
+
+// ValidateBasic performs basic validation.
+func (m *CommitMessage) ValidateBasic() error {
+	if m.Commit == nil {
+		return errors.New("Nil Commit")
+	}
+	return m.Commit.ValidateBasic()
+}
+
+// String returns a string representation.
+func (m *CommitMessage) String() string {
+	return fmt.Sprintf("[Commit H:%v R:%v]", m.Commit.Height(), m.Commit.Round())
+}
+
+//-------------------------------------
+
 // HasVoteMessage is sent to indicate that a particular vote has been received.
 type HasVoteMessage struct {
 	Height int64
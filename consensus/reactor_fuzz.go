@@ -0,0 +1,77 @@
+// +build gofuzz
+
+package consensus
+
+import (
+	bc "github.com/tendermint/tendermint/blockchain"
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/p2p/dummy"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+var (
+	fuzzReactor *ConsensusReactor
+	fuzzPeer    p2p.Peer
+)
+
+// FuzzReactorReceive passes data to the Receive method of the reactor, on
+// the channel chosen by its first byte. The consensus state doesn't run: the
+// messages the reactor passes to it are dropped. It returns 1 for the data
+// decoding to a message, to give it priority.
+//
+// Build it with go-fuzz-build -func FuzzReactorReceive.
+func FuzzReactorReceive(data []byte) int {
+	if len(data) == 0 {
+		return 0
+	}
+	if fuzzReactor == nil {
+		fuzzReactor, fuzzPeer = newFuzzReactor()
+	}
+	chs := fuzzReactor.GetChannels()
+	fuzzReactor.Receive(chs[int(data[0])%len(chs)].ID, fuzzPeer, data[1:])
+	if _, err := decodeMsg(data[1:]); err != nil {
+		return 0
+	}
+	return 1
+}
+
+func newFuzzReactor() (*ConsensusReactor, p2p.Peer) {
+	pv := types.NewMockPV()
+	state, err := sm.MakeGenesisState(&types.GenesisDoc{
+		ChainID:    "fuzz",
+		Validators: []types.GenesisValidator{{PubKey: pv.GetPubKey(), Power: 10}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	stateDB := dbm.NewMemDB()
+	blockExec := sm.NewBlockExecutor(stateDB, log.NewNopLogger(), nil, sm.MockMempool{}, sm.MockEvidencePool{})
+	conS := NewConsensusState(cfg.TestConsensusConfig(), state, blockExec,
+		bc.NewBlockStore(dbm.NewMemDB()), sm.MockMempool{}, sm.MockEvidencePool{})
+	go func() {
+		for range conS.peerMsgQueue {
+		}
+	}()
+
+	conR := NewConsensusReactor(conS, true)
+	p2pConfig := cfg.DefaultP2PConfig()
+	sw := p2p.NewSwitch(p2pConfig, p2p.NewMultiplexTransport(p2p.DefaultNodeInfo{},
+		p2p.NodeKey{PrivKey: ed25519.GenPrivKey()}, p2p.MConnConfig(p2pConfig)))
+	sw.AddReactor("CONSENSUS", conR)
+	if err := conR.Start(); err != nil {
+		panic(err)
+	}
+	// receive the messages of every channel, without running the consensus
+	conR.mtx.Lock()
+	conR.fastSync = false
+	conR.mtx.Unlock()
+
+	peer := dummy.NewPeer()
+	peer.Set(types.PeerStateKey, NewPeerState(peer))
+	return conR, peer
+}
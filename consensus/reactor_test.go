@@ -25,6 +25,7 @@ import (
 	"github.com/tendermint/tendermint/p2p"
 	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
+	tmtime "github.com/tendermint/tendermint/types/time"
 )
 
 //----------------------------------------------
@@ -235,6 +236,229 @@ func TestReactorCreatesBlockWhenEmptyBlocksFalse(t *testing.T) {
 	}, css)
 }
 
+// Ensure we can make blocks when the proposers erasure code them.
+func TestReactorWithErasureCodedBlockParts(t *testing.T) {
+	N := 4
+	css, cleanup := randConsensusNet(N, "consensus_reactor_test", newMockTickerFunc(true), newCounter,
+		func(c *cfg.Config) {
+			c.Consensus.BlockPartsParityRatio = 1
+		})
+	defer cleanup()
+	reactors, eventChans, eventBuses := startConsensusNet(t, css, N)
+	defer stopConsensusNet(log.TestingLogger(), reactors, eventBuses)
+
+	// wait till everyone makes the first two blocks
+	for i := 0; i < 2; i++ {
+		timeoutWaitGroup(t, N, func(j int) {
+			<-eventChans[j]
+		}, css)
+	}
+}
+
+// Ensure we can make blocks when the proposers send compact blocks, whose
+// txs are missing from the mempools of the peers.
+func TestReactorWithCompactBlocks(t *testing.T) {
+	N := 4
+	css, cleanup := randConsensusNet(N, "consensus_reactor_test", newMockTickerFunc(true), newCounter,
+		func(c *cfg.Config) {
+			c.Consensus.CreateEmptyBlocks = false
+			c.Consensus.CompactBlocks = true
+		})
+	defer cleanup()
+	reactors, eventChans, eventBuses := startConsensusNet(t, css, N)
+	defer stopConsensusNet(log.TestingLogger(), reactors, eventBuses)
+
+	// send a tx to a single validator, the others requesting it
+	if err := assertMempool(css[3].txNotifier).CheckTx([]byte{1, 2, 3}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// wait till everyone makes the first new block
+	timeoutWaitGroup(t, N, func(j int) {
+		<-eventChans[j]
+	}, css)
+}
+
+func TestPendingCompactBlock(t *testing.T) {
+	txs := types.Txs{types.Tx("a"), types.Tx("b"), types.Tx("c")}
+	block := types.MakeBlock(1, txs, &types.Commit{}, nil)
+	msg := makeCompactBlockMessage(1, 0, block)
+	require.NoError(t, msg.ValidateBasic())
+
+	pending := &pendingCompactBlock{msg: msg, txs: types.Txs{txs[0], nil, nil}}
+	missing := pending.missing()
+	assert.Equal(t, []bool{false, true, true}, []bool{missing.GetIndex(0), missing.GetIndex(1), missing.GetIndex(2)})
+
+	// wrong tx
+	assert.False(t, (&pendingCompactBlock{msg: msg, txs: types.Txs{txs[0], nil, nil}}).addTxs(types.Txs{txs[2]}))
+
+	assert.True(t, pending.addTxs(types.Txs{txs[1]}))
+	assert.False(t, pending.missing().IsEmpty())
+	assert.True(t, pending.addTxs(types.Txs{txs[2]}))
+	assert.True(t, pending.missing().IsEmpty())
+	assert.Equal(t, block.Hash(), pending.block().Hash())
+}
+
+// sendRecordingPeer records the messages sent to it.
+type sendRecordingPeer struct {
+	p2p.Peer
+	channels []byte
+	msgs     []ConsensusMessage
+}
+
+func (p *sendRecordingPeer) ID() p2p.ID { return "recording" }
+
+func (p *sendRecordingPeer) NodeInfo() p2p.NodeInfo {
+	return p2p.DefaultNodeInfo{Channels: p.channels}
+}
+
+func (p *sendRecordingPeer) Send(chID byte, msgBytes []byte) bool {
+	msg, err := decodeMsg(msgBytes)
+	if err != nil {
+		panic(err)
+	}
+	p.msgs = append(p.msgs, msg)
+	return true
+}
+
+func TestPeerStatePickSendCommit(t *testing.T) {
+	blockID := types.BlockID{Hash: []byte("hash"), PartsHeader: types.PartSetHeader{Total: 1, Hash: []byte("parts")}}
+	precommits := make([]*types.CommitSig, 4)
+	for _, i := range []int{0, 1, 3} {
+		vote := &types.Vote{
+			Type:             types.PrecommitType,
+			Height:           5,
+			Round:            1,
+			BlockID:          blockID,
+			Timestamp:        tmtime.Now(),
+			ValidatorIndex:   i,
+			ValidatorAddress: []byte(fmt.Sprintf("validator%d", i)),
+			Signature:        []byte("signature"),
+		}
+		precommits[i] = vote.CommitSig()
+	}
+	commit := types.NewCommit(blockID, precommits)
+	require.NoError(t, (&CommitMessage{commit}).ValidateBasic())
+
+	peer := &sendRecordingPeer{}
+	ps := NewPeerState(peer).SetLogger(log.TestingLogger())
+
+	// not at the height of the commit
+	ps.PRS.Height = 4
+	assert.False(t, ps.PickSendCommit(commit))
+
+	// not knowing about the CommitChannel
+	ps.PRS.Height = 5
+	assert.False(t, ps.PickSendCommit(commit))
+	assert.Empty(t, peer.msgs)
+
+	peer.channels = []byte{VoteChannel, CommitChannel}
+
+	ps.PRS.Height = 5
+	require.True(t, ps.PickSendCommit(commit))
+	require.Len(t, peer.msgs, 1)
+	msg, ok := peer.msgs[0].(*CommitMessage)
+	require.True(t, ok)
+	assert.Equal(t, commit.Hash(), msg.Commit.Hash())
+
+	// sent once, with all of its votes
+	assert.False(t, ps.PickSendCommit(commit))
+	assert.False(t, ps.PickSendVote(commit))
+	assert.Len(t, peer.msgs, 1)
+}
+
+func TestReactorHandleCommit(t *testing.T) {
+	cs, vss := randConsensusState(4)
+	conR := NewConsensusReactor(cs, false)
+	conR.SetLogger(log.TestingLogger())
+
+	blockID := types.BlockID{Hash: []byte("hash"), PartsHeader: types.PartSetHeader{Total: 1, Hash: []byte("parts")}}
+	votes := signVotes(types.PrecommitType, blockID.Hash, blockID.PartsHeader, vss...)
+	precommits := make([]*types.CommitSig, len(votes))
+	for i, vote := range votes {
+		precommits[i] = vote.CommitSig()
+	}
+	commit := types.NewCommit(blockID, precommits)
+
+	// the peer is at the next height
+	peer := &sendRecordingPeer{}
+	ps := NewPeerState(peer).SetLogger(log.TestingLogger())
+	ps.PRS.Height = cs.Height + 1
+	ps.PRS.LastCommitRound = 0
+
+	conR.handleCommit(peer, ps, &CommitMessage{commit})
+	assert.Len(t, cs.peerMsgQueue, len(votes))
+	// the peer has the votes, which are not sent back to it
+	assert.False(t, ps.PickSendVote(commit))
+	for i := range votes {
+		assert.True(t, ps.PRS.LastCommit.GetIndex(i))
+	}
+}
+
+// pauseRecordingPeer records whether receiving from it is paused.
+type pauseRecordingPeer struct {
+	sendRecordingPeer
+	mtx    sync.Mutex
+	paused map[byte]bool
+}
+
+func (p *pauseRecordingPeer) String() string { return "pauseRecordingPeer" }
+
+func (p *pauseRecordingPeer) PauseRecv(chID byte) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.paused[chID] = true
+}
+
+func (p *pauseRecordingPeer) ResumeRecv(chID byte) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.paused[chID] = false
+}
+
+func (p *pauseRecordingPeer) isPaused(chID byte) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.paused[chID]
+}
+
+func TestReactorSetsPeerBusyWhileQueueFull(t *testing.T) {
+	cs, vss := randConsensusState(2)
+	cs.peerMsgQueue = make(chan msgInfo, 2)
+	conR := NewConsensusReactor(cs, false)
+	conR.SetLogger(log.TestingLogger())
+	sw := p2p.NewSwitch(config.P2P, nil)
+	sw.SetLogger(log.TestingLogger())
+	conR.SetSwitch(sw)
+
+	peer := &pauseRecordingPeer{paused: make(map[byte]bool)}
+	vote := &VoteMessage{signVote(vss[1], types.PrevoteType, nil, types.PartSetHeader{})}
+	conR.queuePeerMsg(VoteChannel, peer, vote)
+	assert.False(t, peer.isPaused(VoteChannel))
+
+	// The msg filling the queue sets the peer busy.
+	conR.queuePeerMsg(VoteChannel, peer, vote)
+	assert.True(t, peer.isPaused(VoteChannel))
+
+	// A msg finding the queue full is dropped, without blocking.
+	conR.queuePeerMsg(VoteChannel, peer, vote)
+	assert.Len(t, cs.peerMsgQueue, 2)
+
+	// Once drained, the peer is ready again.
+	for i := 0; i < 2; i++ {
+		<-cs.peerMsgQueue
+	}
+	waitPaused(t, peer, VoteChannel, false)
+}
+
+func waitPaused(t *testing.T, peer *pauseRecordingPeer, chID byte, paused bool) {
+	for start := time.Now(); peer.isPaused(chID) != paused; time.Sleep(time.Millisecond) {
+		if time.Since(start) > time.Second {
+			t.Fatalf("Timed out waiting for the channel %X to be paused=%v", chID, paused)
+		}
+	}
+}
+
 // Test we record stats about votes and block parts from other peers.
 func TestReactorRecordsVotesAndBlockParts(t *testing.T) {
 	N := 4
@@ -0,0 +1,214 @@
+package consensus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	cfg "github.com/tendermint/tendermint/config"
+	auto "github.com/tendermint/tendermint/libs/autofile"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+	tmtime "github.com/tendermint/tendermint/types/time"
+)
+
+const (
+	// how often the recorded messages are flushed to disk
+	recorderFlushInterval = 1 * time.Second
+)
+
+//--------------------------------------------------------
+// recording of the received consensus messages ("black box")
+
+// MsgRecorder records the consensus messages received from peers, with the
+// time they were received, to a rolling group of files, so that an incident
+// can be reproduced with ReplayRecording.
+//
+// The messages are recorded as msgInfos, in the format of the WAL (see
+// WALEncoder), so the recording can also be read with scripts/wal2json.
+type MsgRecorder struct {
+	cmn.BaseService
+
+	mtx   sync.Mutex
+	group *auto.Group
+	enc   *WALEncoder
+}
+
+// NewMsgRecorder returns a recorder writing to the group of files at file,
+// whose total size is at most maxSize bytes (0 - unlimited).
+func NewMsgRecorder(file string, maxSize int64) (*MsgRecorder, error) {
+	err := cmn.EnsureDir(filepath.Dir(file), 0700)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to ensure recording directory is in place")
+	}
+
+	group, err := auto.OpenGroup(file, auto.GroupTotalSizeLimit(maxSize))
+	if err != nil {
+		return nil, err
+	}
+	r := &MsgRecorder{
+		group: group,
+		enc:   NewWALEncoder(group),
+	}
+	r.BaseService = *cmn.NewBaseService(nil, "MsgRecorder", r)
+	return r, nil
+}
+
+// SetLogger sets the logger of the recorder and its group.
+func (r *MsgRecorder) SetLogger(l log.Logger) {
+	r.BaseService.Logger = l
+	r.group.SetLogger(l)
+}
+
+// OnStart implements cmn.Service.
+func (r *MsgRecorder) OnStart() error {
+	if err := r.group.Start(); err != nil {
+		return err
+	}
+	go r.flushRoutine()
+	return nil
+}
+
+// OnStop implements cmn.Service.
+func (r *MsgRecorder) OnStop() {
+	r.group.Flush()
+	r.group.Stop()
+	r.group.Close()
+}
+
+// Record records the message received from the given peer. Errors are
+// logged, as the recording must not get in the way of the consensus.
+func (r *MsgRecorder) Record(msg ConsensusMessage, peerID p2p.ID) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if err := r.enc.Encode(&TimedWALMessage{tmtime.Now(), msgInfo{msg, peerID}}); err != nil {
+		r.Logger.Error("Failed to record consensus message", "msg", msg, "peer", peerID, "err", err)
+	}
+}
+
+func (r *MsgRecorder) flushRoutine() {
+	ticker := time.NewTicker(recorderFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.group.Flush(); err != nil {
+				r.Logger.Error("Failed to flush recorded consensus messages", "err", err)
+			}
+		case <-r.Quit():
+			return
+		}
+	}
+}
+
+//--------------------------------------------------------
+// replay of a recording
+
+// RunReplayRecording replays the recording at file to a consensus state
+// created from the given config, see ReplayRecording, logging its steps. It
+// keeps running after the replay, until interrupted.
+func RunReplayRecording(config cfg.BaseConfig, csConfig *cfg.ConsensusConfig, file string) {
+	consensusState := newConsensusStateForReplay(config, csConfig, true)
+	consensusState.SetLogger(log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "consensus"))
+
+	newStepCh := make(chan interface{}, 1)
+	err := consensusState.eventBus.Subscribe(context.Background(), subscriber, types.EventQueryNewRoundStep, newStepCh)
+	if err != nil {
+		cmn.Exit(fmt.Sprintf("Failed to subscribe %s to %v", subscriber, types.EventQueryNewRoundStep))
+	}
+	go func() {
+		for step := range newStepCh {
+			rs := step.(types.EventDataRoundState)
+			fmt.Printf("Height %d, round %d: %s\n", rs.Height, rs.Round, rs.Step)
+		}
+	}()
+
+	if err := consensusState.ReplayRecording(file); err != nil {
+		cmn.Exit(fmt.Sprintf("Error during replay of the recording: %v", err))
+	}
+	cmn.TrapSignal(func() {
+		consensusState.Stop()
+	})
+}
+
+// ReplayRecording starts the consensus state and drives it with the messages
+// of the recording at file (see MsgRecorder), as if they were received from
+// their peers again, with the same delays between them. Only the messages
+// the consensus state acts on (proposals, block parts and votes) are replayed.
+//
+// The consensus state must be at the height of the start of the recording,
+// e.g. using a copy of the data directory of the node made before the
+// incident. It is left running after the last message, so that what follows
+// (e.g. timeouts) can be observed: the caller must stop it.
+func (cs *ConsensusState) ReplayRecording(file string) error {
+	if cs.IsRunning() {
+		return errors.New("cs is already running, cannot replay")
+	}
+
+	group, err := auto.OpenGroup(file)
+	if err != nil {
+		return err
+	}
+	defer group.Close()
+	gr, err := group.NewReader(group.MinIndex())
+	if err != nil {
+		return err
+	}
+	defer gr.Close() // nolint: errcheck
+
+	if err := cs.Start(); err != nil {
+		return err
+	}
+
+	dec := NewWALDecoder(gr)
+	var (
+		count                     int
+		firstMsgTime, replayStart time.Time
+	)
+	for {
+		msg, err := dec.Decode()
+		if err == io.EOF {
+			break
+		} else if IsDataCorruptionError(err) {
+			// The last message is truncated if the node crashed while
+			// recording it.
+			cs.Logger.Error("Corrupted message, stopping the replay", "err", err)
+			break
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to read the recording after %d messages", count)
+		}
+		mi, ok := msg.Msg.(msgInfo)
+		if !ok {
+			continue
+		}
+		switch mi.Msg.(type) {
+		case *ProposalMessage, *BlockPartMessage, *VoteMessage:
+		default:
+			continue
+		}
+
+		// Wait as long as between the first message and this one.
+		if count == 0 {
+			firstMsgTime, replayStart = msg.Time, time.Now()
+		}
+		time.Sleep(time.Until(replayStart.Add(msg.Time.Sub(firstMsgTime))))
+
+		select {
+		case cs.peerMsgQueue <- mi:
+		case <-cs.Quit():
+			return errors.New("cs stopped during the replay")
+		}
+		count++
+	}
+
+	cs.Logger.Info("Replayed the recording", "file", file, "msgs", count)
+	return nil
+}
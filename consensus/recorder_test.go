@@ -0,0 +1,49 @@
+package consensus
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestMsgRecorderReplay(t *testing.T) {
+	cs1, vss := randConsensusState(4)
+	height := cs1.Height
+	newRoundCh := subscribe(cs1.eventBus, types.EventQueryNewRound)
+
+	dir, err := ioutil.TempDir("", "recorder_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "recording")
+
+	recorder, err := NewMsgRecorder(file, 0)
+	require.NoError(t, err)
+	recorder.SetLogger(log.TestingLogger())
+	require.NoError(t, recorder.Start())
+
+	// Record the nil prevotes and precommits of the other validators for the
+	// first round.
+	peerID := p2p.ID("peer")
+	for _, voteType := range []types.SignedMsgType{types.PrevoteType, types.PrecommitType} {
+		for _, vote := range signVotes(voteType, nil, types.PartSetHeader{}, vss[1:]...) {
+			recorder.Record(&VoteMessage{vote}, peerID)
+		}
+	}
+	// This one isn't replayed.
+	recorder.Record(&NewRoundStepMessage{Height: height, Round: 5}, peerID)
+	recorder.Stop()
+
+	require.NoError(t, cs1.ReplayRecording(file))
+	defer cs1.Stop()
+
+	// The nil precommits of 3/4 of the validators move cs1 to the next round.
+	ensureNewRound(newRoundCh, height, 0)
+	ensureNewRound(newRoundCh, height, 1)
+}
@@ -286,21 +286,7 @@ func (h *Handshaker) ReplayBlocks(
 
 	// If appBlockHeight == 0 it means that we are at genesis and hence should send InitChain.
 	if appBlockHeight == 0 {
-		validators := make([]*types.Validator, len(h.genDoc.Validators))
-		for i, val := range h.genDoc.Validators {
-			validators[i] = types.NewValidator(val.PubKey, val.Power)
-		}
-		validatorSet := types.NewValidatorSet(validators)
-		nextVals := types.TM2PB.ValidatorUpdates(validatorSet)
-		csParams := types.TM2PB.ConsensusParams(h.genDoc.ConsensusParams)
-		req := abci.RequestInitChain{
-			Time:            h.genDoc.GenesisTime,
-			ChainId:         h.genDoc.ChainID,
-			ConsensusParams: csParams,
-			Validators:      nextVals,
-			AppStateBytes:   h.genDoc.AppState,
-		}
-		res, err := proxyApp.Consensus().InitChainSync(req)
+		res, err := proxyApp.Consensus().InitChainSync(initChainRequest(h.genDoc))
 		if err != nil {
 			return nil, err
 		}
@@ -312,6 +298,9 @@ func (h *Handshaker) ReplayBlocks(
 				if err != nil {
 					return nil, err
 				}
+				if err := state.ConsensusParams.Validator.ValidateVotingPowers(vals); err != nil {
+					return nil, fmt.Errorf("Invalid validators returned by InitChain: %v", err)
+				}
 				state.Validators = types.NewValidatorSet(vals)
 				state.NextValidators = types.NewValidatorSet(vals)
 			} else {
@@ -322,7 +311,13 @@ func (h *Handshaker) ReplayBlocks(
 			}
 
 			if res.ConsensusParams != nil {
+				blockSizeParams := state.ConsensusParams.BlockSize
+				validatorParams := state.ConsensusParams.Validator
 				state.ConsensusParams = types.PB2TM.ConsensusParams(res.ConsensusParams)
+				// Not ABCI params, only set in the genesis file.
+				state.ConsensusParams.BlockSize.MaxEmptyIntervalMs = blockSizeParams.MaxEmptyIntervalMs
+				state.ConsensusParams.Validator.MaxPower = validatorParams.MaxPower
+				state.ConsensusParams.Validator.ProposerGraceRounds = validatorParams.ProposerGraceRounds
 			}
 			sm.SaveState(h.stateDB, state)
 		}
@@ -395,6 +390,24 @@ func (h *Handshaker) ReplayBlocks(
 	return nil, nil
 }
 
+// initChainRequest returns the InitChain request of the genesis.
+func initChainRequest(genDoc *types.GenesisDoc) abci.RequestInitChain {
+	validators := make([]*types.Validator, len(genDoc.Validators))
+	for i, val := range genDoc.Validators {
+		validators[i] = types.NewValidator(val.PubKey, val.Power)
+	}
+	validatorSet := types.NewValidatorSet(validators)
+	nextVals := types.TM2PB.ValidatorUpdates(validatorSet)
+	csParams := types.TM2PB.ConsensusParams(genDoc.ConsensusParams)
+	return abci.RequestInitChain{
+		Time:            genDoc.GenesisTime,
+		ChainId:         genDoc.ChainID,
+		ConsensusParams: csParams,
+		Validators:      nextVals,
+		AppStateBytes:   genDoc.AppState,
+	}
+}
+
 func (h *Handshaker) replayBlocks(state sm.State, proxyApp proxy.AppConns, appBlockHeight, storeBlockHeight int64, mutateState bool) ([]byte, error) {
 	// App is further behind than it should be, so we need to replay blocks.
 	// We replay all blocks from appBlockHeight+1.
@@ -11,6 +11,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	abcicli "github.com/tendermint/tendermint/abci/client"
 	bc "github.com/tendermint/tendermint/blockchain"
 	cfg "github.com/tendermint/tendermint/config"
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -31,7 +32,7 @@ const (
 
 // replay the wal file
 func RunReplayFile(config cfg.BaseConfig, csConfig *cfg.ConsensusConfig, console bool) {
-	consensusState := newConsensusStateForReplay(config, csConfig)
+	consensusState := newConsensusStateForReplay(config, csConfig, false)
 
 	if err := consensusState.ReplayFile(csConfig.WalFile(), console); err != nil {
 		cmn.Exit(fmt.Sprintf("Error during consensus replay: %v", err))
@@ -298,8 +299,9 @@ func (pb *playback) replayConsoleLoop() int {
 
 //--------------------------------------------------------------------------------
 
-// convenience for replay mode
-func newConsensusStateForReplay(config cfg.BaseConfig, csConfig *cfg.ConsensusConfig) *ConsensusState {
+// convenience for replay mode. The consensus state starts from the genesis
+// state, or from the latest state if fromLatestState is true.
+func newConsensusStateForReplay(config cfg.BaseConfig, csConfig *cfg.ConsensusConfig, fromLatestState bool) *ConsensusState {
 	dbType := dbm.DBBackendType(config.DBBackend)
 	// Get BlockStore
 	blockStoreDB := dbm.NewDB("blockstore", dbType, config.DBDir())
@@ -311,13 +313,23 @@ func newConsensusStateForReplay(config cfg.BaseConfig, csConfig *cfg.ConsensusCo
 	if err != nil {
 		cmn.Exit(err.Error())
 	}
-	state, err := sm.MakeGenesisState(gdoc)
+	if err := gdoc.UseMerkleHasher(); err != nil {
+		cmn.Exit(err.Error())
+	}
+	var state sm.State
+	if fromLatestState {
+		state, err = sm.LoadStateFromDBOrGenesisDoc(stateDB, gdoc)
+	} else {
+		state, err = sm.MakeGenesisState(gdoc)
+	}
 	if err != nil {
 		cmn.Exit(err.Error())
 	}
 
 	// Create proxyAppConn connection (consensus, mempool, query)
-	clientCreator := proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir())
+	clientCreator := proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir(),
+		abcicli.SocketClientMaxMsgSize(config.ABCIMaxMsgSize),
+		abcicli.SocketClientCredits(config.ABCIMaxInFlight))
 	proxyApp := proxy.NewAppConns(clientCreator)
 	err = proxyApp.Start()
 	if err != nil {
@@ -335,6 +347,9 @@ func newConsensusStateForReplay(config cfg.BaseConfig, csConfig *cfg.ConsensusCo
 	if err != nil {
 		cmn.Exit(fmt.Sprintf("Error on handshake: %v", err))
 	}
+	if fromLatestState {
+		state = sm.LoadState(stateDB)
+	}
 
 	mempool, evpool := sm.MockMempool{}, sm.MockEvidencePool{}
 	blockExec := sm.NewBlockExecutor(stateDB, log.TestingLogger(), proxyApp.Consensus(), mempool, evpool)
@@ -618,6 +618,7 @@ func NewMockBlockStore(config *cfg.Config, params types.ConsensusParams) *mockBl
 	return &mockBlockStore{config, params, nil, nil}
 }
 
+func (bs *mockBlockStore) Base() int64                         { return 1 }
 func (bs *mockBlockStore) Height() int64                       { return int64(len(bs.chain)) }
 func (bs *mockBlockStore) LoadBlock(height int64) *types.Block { return bs.chain[height-1] }
 func (bs *mockBlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
@@ -684,3 +685,73 @@ func (ica *initChainApp) InitChain(req abci.RequestInitChain) abci.ResponseInitC
 		Validators: ica.vals,
 	}
 }
+
+//----------------------------------------
+
+func TestVerifyReplay(t *testing.T) {
+	config := ResetConfig("replay_verify_test_")
+	defer os.RemoveAll(config.RootDir)
+
+	walBody, err := WALWithNBlocks(t, NUM_BLOCKS)
+	require.NoError(t, err)
+	walFile := tempWALWithData(walBody)
+	defer os.Remove(walFile)
+	wal, err := NewWAL(walFile)
+	require.NoError(t, err)
+	wal.SetLogger(log.TestingLogger())
+	require.NoError(t, wal.Start())
+	defer wal.Stop()
+	chain, commits, err := makeBlockchainFromWAL(wal)
+	require.NoError(t, err)
+
+	privVal := privval.LoadFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+	stateDB, state, store := stateAndStore(config, privVal.GetPubKey(), kvstore.ProtocolVersion)
+	store.chain = chain
+	store.commits = commits
+	sm.SaveState(stateDB, state)
+	buildTMStateFromChain(config, stateDB, state, chain, 0)
+	genDoc, err := sm.MakeGenesisDocFromFile(config.GenesisFile())
+	require.NoError(t, err)
+
+	verify := func(app abci.Application, from int64) error {
+		proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(app))
+		require.NoError(t, proxyApp.Start())
+		defer proxyApp.Stop()
+		return VerifyReplay(stateDB, store, genDoc, proxyApp, from, 0, log.TestingLogger())
+	}
+
+	// the same app computes the same hashes
+	app := kvstore.NewPersistentKVStoreApplication(path.Join(config.DBDir(), "verify"))
+	assert.NoError(t, verify(app, 1))
+
+	// and the app must be at the height before the first one
+	assert.Error(t, verify(app, 1))
+
+	// a non-deterministic app diverges
+	app = kvstore.NewPersistentKVStoreApplication(path.Join(config.DBDir(), "diverging"))
+	err = verify(&divergingApp{PersistentKVStoreApplication: app, height: 3}, 1)
+	require.Error(t, err)
+	divergence, ok := err.(ErrAppHashDivergence)
+	require.True(t, ok, "expected an ErrAppHashDivergence, got %v", err)
+	assert.EqualValues(t, 3, divergence.Height)
+}
+
+// divergingApp returns another app hash from the height on.
+type divergingApp struct {
+	*kvstore.PersistentKVStoreApplication
+	height     int64
+	lastHeight int64
+}
+
+func (app *divergingApp) EndBlock(req abci.RequestEndBlock) abci.ResponseEndBlock {
+	app.lastHeight = req.Height
+	return app.PersistentKVStoreApplication.EndBlock(req)
+}
+
+func (app *divergingApp) Commit() abci.ResponseCommit {
+	res := app.PersistentKVStoreApplication.Commit()
+	if app.lastHeight >= app.height {
+		res.Data = append([]byte("diverged"), res.Data...)
+	}
+	return res
+}
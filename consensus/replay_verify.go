@@ -0,0 +1,97 @@
+package consensus
+
+import (
+	"bytes"
+	"fmt"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/proxy"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+// ErrAppHashDivergence is returned by VerifyReplay for the first height whose
+// block re-executed by the app results in another app hash than the one
+// committed by the chain.
+type ErrAppHashDivergence struct {
+	Height   int64
+	Expected cmn.HexBytes
+	Got      cmn.HexBytes
+}
+
+func (e ErrAppHashDivergence) Error() string {
+	return fmt.Sprintf("App hash diverged at height %d: expected %v, got %v", e.Height, e.Expected, e.Got)
+}
+
+// VerifyReplay re-executes the blocks of the store from height from to height
+// to, 0 for the last height of the state, against the app, and compares the
+// app hash after each of them with the one committed by the chain: the one of
+// the header of the next block, or of the state for its last height. It
+// returns an ErrAppHashDivergence for the first height they differ at.
+//
+// The app must have committed the block before from, or be new if from is 1,
+// in which case it is initialized with the genesis doc. The blocks are
+// committed by the app, which is left at the divergence if any.
+func VerifyReplay(
+	stateDB dbm.DB,
+	store sm.BlockStore,
+	genDoc *types.GenesisDoc,
+	proxyApp proxy.AppConns,
+	from, to int64,
+	logger log.Logger,
+) error {
+	state := sm.LoadState(stateDB)
+	if to == 0 {
+		to = state.LastBlockHeight
+	}
+	if from < 1 || from > to || to > state.LastBlockHeight {
+		return fmt.Errorf("invalid height range %d:%d, the last height is %d", from, to, state.LastBlockHeight)
+	}
+
+	res, err := proxyApp.Query().InfoSync(proxy.RequestInfo)
+	if err != nil {
+		return fmt.Errorf("Error calling Info: %v", err)
+	}
+	if res.LastBlockHeight != from-1 {
+		return fmt.Errorf("the app is at height %d, it must have committed the block before height %d",
+			res.LastBlockHeight, from)
+	}
+	if from == 1 {
+		if _, err := proxyApp.Consensus().InitChainSync(initChainRequest(genDoc)); err != nil {
+			return err
+		}
+	}
+
+	for height := from; height <= to; height++ {
+		block := store.LoadBlock(height)
+		if block == nil {
+			return sm.ErrUnknownBlock{Height: height}
+		}
+		lastValSet := types.NewValidatorSet(nil)
+		if height > 1 {
+			if lastValSet, err = sm.LoadValidators(stateDB, height-1); err != nil {
+				return err
+			}
+		}
+		appHash, err := sm.ExecCommitBlock(proxyApp.Consensus(), block, logger, lastValSet, stateDB)
+		if err != nil {
+			return err
+		}
+
+		expected := state.AppHash
+		if height < state.LastBlockHeight {
+			next := store.LoadBlockMeta(height + 1)
+			if next == nil {
+				return sm.ErrUnknownBlock{Height: height + 1}
+			}
+			expected = next.Header.AppHash
+		}
+		if !bytes.Equal(appHash, expected) {
+			return ErrAppHashDivergence{Height: height, Expected: expected, Got: appHash}
+		}
+		logger.Debug("Verified the app hash", "height", height, "appHash", cmn.HexBytes(appHash))
+	}
+	return nil
+}
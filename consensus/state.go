@@ -6,10 +6,12 @@ import (
 	"reflect"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 
+	auto "github.com/tendermint/tendermint/libs/autofile"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/fail"
 	"github.com/tendermint/tendermint/libs/log"
@@ -23,6 +25,38 @@ import (
 	"github.com/tendermint/tendermint/types"
 )
 
+//-----------------------------------------------------------------------------
+// WAL catchup
+
+// WALCatchupStatus is the status of the catchup replay of the WAL, when the
+// consensus starts.
+type WALCatchupStatus int32
+
+const (
+	WALCatchupPending WALCatchupStatus = iota
+	WALCatchupReplaying
+	WALCatchupDone
+	WALCatchupFailed
+	WALCatchupSkipped
+)
+
+func (s WALCatchupStatus) String() string {
+	switch s {
+	case WALCatchupPending:
+		return "pending"
+	case WALCatchupReplaying:
+		return "replaying"
+	case WALCatchupDone:
+		return "done"
+	case WALCatchupFailed:
+		return "failed"
+	case WALCatchupSkipped:
+		return "skipped"
+	default:
+		return fmt.Sprintf("WALCatchupStatus(%d)", int32(s))
+	}
+}
+
 //-----------------------------------------------------------------------------
 // Errors
 
@@ -37,6 +71,10 @@ var (
 
 var (
 	msgQueueSize = 1000
+
+	// maxPausedMsgs is the number of msgs for the height the node is paused
+	// at, or later ones, kept until it's resumed. The next ones are dropped.
+	maxPausedMsgs = 1000
 )
 
 // msgs from the reactor which may update the state
@@ -113,8 +151,9 @@ type ConsensusState struct {
 	// a Write-Ahead Log ensures we can recover from any kind of crash
 	// and helps us avoid signing conflicting votes
 	wal          WAL
-	replayMode   bool // so we don't log signing errors during replay
-	doWALCatchup bool // determines if we even try to do the catchup
+	replayMode   bool  // so we don't log signing errors during replay
+	doWALCatchup bool  // determines if we even try to do the catchup
+	walCatchup   int32 // atomic WALCatchupStatus
 
 	// for tests where we want to limit the number of transitions the state makes
 	nSteps int
@@ -133,6 +172,39 @@ type ConsensusState struct {
 
 	// for reporting metrics
 	metrics *Metrics
+
+	// monikers of the validators, for the logs and the RPC
+	monikers *types.ValidatorMonikers
+
+	// panic at the first inconsistency, see StateInvariantChecks
+	checkInvariants bool
+
+	// height the node doesn't enter until resumed, 0 if not paused.
+	// haltHeight is protected by mtx.
+	haltHeight int64
+	resumeCh   chan struct{}
+	// msgs received while paused, handled once resumed, except the ones for
+	// later heights, kept until the node reaches pausedMsgsHeight, the lowest
+	// of them. pausedMsgs is used by the receive routine only.
+	pausedMsgs       []msgInfo
+	pausedMsgsHeight int64
+
+	// estimates the skew of the local clock, against the blocks using the
+	// time we committed the last one at
+	clockSkew        *clockSkewMonitor
+	lastCommitTime   time.Time
+	lastCommitHeight int64
+
+	// adjusts timeout_propose and timeout_commit with AdaptiveTimeouts, nil
+	// otherwise, to the latencies of the proposals, from proposeStartTime,
+	// and of the precommits, from CommitTime to lastCommitAllTime
+	timeouts          *timeoutTuner
+	proposeStartTime  time.Time
+	lastCommitAllTime time.Time
+
+	// true once a quorum of the persistent peers is connected, to propose
+	// the first block with WaitForPersistentPeers. Protected by mtx.
+	peersQuorum bool
 }
 
 // StateOption sets an optional parameter on the ConsensusState.
@@ -158,11 +230,13 @@ func NewConsensusState(
 		timeoutTicker:    NewTimeoutTicker(),
 		statsMsgQueue:    make(chan msgInfo, msgQueueSize),
 		done:             make(chan struct{}),
+		resumeCh:         make(chan struct{}, 1),
 		doWALCatchup:     true,
 		wal:              nilWAL{},
 		evpool:           evpool,
 		evsw:             tmevents.NewEventSwitch(),
 		metrics:          NopMetrics(),
+		monikers:         types.NewValidatorMonikers(nil),
 	}
 	// set function defaults (may be overwritten before calling Start)
 	cs.decideProposal = cs.defaultDecideProposal
@@ -178,6 +252,11 @@ func NewConsensusState(
 	for _, option := range options {
 		option(cs)
 	}
+	cs.clockSkew = newClockSkewMonitor(config.MaxClockSkew, cs.metrics)
+	if config.AdaptiveTimeouts {
+		cs.timeouts = newTimeoutTuner(config.TimeoutProposeMin, config.TimeoutPropose,
+			config.TimeoutCommitMin, config.TimeoutCommit, cs.metrics)
+	}
 	return cs
 }
 
@@ -188,6 +267,7 @@ func NewConsensusState(
 func (cs *ConsensusState) SetLogger(l log.Logger) {
 	cs.BaseService.Logger = l
 	cs.timeoutTicker.SetLogger(l)
+	cs.clockSkew.SetLogger(l)
 }
 
 // SetEventBus sets event bus.
@@ -201,6 +281,12 @@ func StateMetrics(metrics *Metrics) StateOption {
 	return func(cs *ConsensusState) { cs.metrics = metrics }
 }
 
+// StateValidatorMonikers sets the monikers of the validators, shown in the
+// logs and the round state.
+func StateValidatorMonikers(monikers *types.ValidatorMonikers) StateOption {
+	return func(cs *ConsensusState) { cs.monikers = monikers }
+}
+
 // String returns a string.
 func (cs *ConsensusState) String() string {
 	// better not to access shared variables
@@ -230,6 +316,24 @@ func (cs *ConsensusState) GetRoundState() *cstypes.RoundState {
 	return &rs
 }
 
+// WALCatchupStatus returns the status of the catchup replay of the WAL.
+func (cs *ConsensusState) WALCatchupStatus() WALCatchupStatus {
+	return WALCatchupStatus(atomic.LoadInt32(&cs.walCatchup))
+}
+
+// WALGroupInfo returns the info of the files of the WAL, or false if the WAL
+// isn't open yet.
+func (cs *ConsensusState) WALGroupInfo() (auto.GroupInfo, bool) {
+	cs.mtx.RLock()
+	wal := cs.wal
+	cs.mtx.RUnlock()
+	group := wal.Group()
+	if group == nil {
+		return auto.GroupInfo{}, false
+	}
+	return group.ReadGroupInfo(), true
+}
+
 // GetRoundStateJSON returns a json of RoundState, marshalled using go-amino.
 func (cs *ConsensusState) GetRoundStateJSON() ([]byte, error) {
 	cs.mtx.RLock()
@@ -241,7 +345,9 @@ func (cs *ConsensusState) GetRoundStateJSON() ([]byte, error) {
 func (cs *ConsensusState) GetRoundStateSimpleJSON() ([]byte, error) {
 	cs.mtx.RLock()
 	defer cs.mtx.RUnlock()
-	return cdc.MarshalJSON(cs.RoundState.RoundStateSimple())
+	rss := cs.RoundState.RoundStateSimple()
+	rss.SetMonikers(cs.monikers)
+	return cdc.MarshalJSON(rss)
 }
 
 // GetValidators returns a copy of the current validators.
@@ -265,6 +371,61 @@ func (cs *ConsensusState) SetTimeoutTicker(timeoutTicker TimeoutTicker) {
 	cs.mtx.Unlock()
 }
 
+// PauseAtHeight makes the node stop participating in consensus before
+// entering the height (the next one if 0), until StepHeight or Resume is
+// called. Messages for the height and later ones are kept while paused, up to
+// maxPausedMsgs, and handled once resumed.
+// It returns the height the node will pause at. The pause doesn't survive a
+// restart.
+func (cs *ConsensusState) PauseAtHeight(height int64) (int64, error) {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+	next := cs.nextHeight()
+	if height == 0 {
+		height = next
+	}
+	if height < next {
+		return 0, fmt.Errorf("height %d was already entered, the next height is %d", height, next)
+	}
+	cs.haltHeight = height
+	cs.Logger.Info("Pausing consensus", "height", height)
+	return height, nil
+}
+
+// StepHeight lets the node paused at a height commit it, then pause again at
+// the next one. It returns the height the node will pause at.
+func (cs *ConsensusState) StepHeight() (int64, error) {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+	if !cs.paused() {
+		return 0, errors.New("consensus is not paused")
+	}
+	cs.haltHeight = cs.Height + 1
+	cs.Logger.Info("Stepping consensus", "height", cs.Height)
+	cs.resume()
+	return cs.haltHeight, nil
+}
+
+// Resume makes the node participate in consensus again, cancelling any pause.
+func (cs *ConsensusState) Resume() {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+	wasPaused := cs.paused()
+	cs.haltHeight = 0
+	cs.Logger.Info("Resuming consensus")
+	if wasPaused {
+		cs.resume()
+	}
+}
+
+// PausedAt returns the height the node pauses at, 0 if not paused, and
+// whether it has stopped there.
+func (cs *ConsensusState) PausedAt() (height int64, paused bool) {
+	cs.mtx.RLock()
+	defer cs.mtx.RUnlock()
+	return cs.haltHeight, cs.paused()
+}
+
 // LoadCommit loads the commit for a given height.
 func (cs *ConsensusState) LoadCommit(height int64) *types.Commit {
 	cs.mtx.RLock()
@@ -284,14 +445,16 @@ func (cs *ConsensusState) OnStart() error {
 
 	// we may set the WAL in testing before calling Start,
 	// so only OpenWAL if its still the nilWAL
-	if _, ok := cs.wal.(nilWAL); ok {
+	if _, ok := cs.wal.(nilWAL); ok && !cs.config.WalDisabled {
 		walFile := cs.config.WalFile()
 		wal, err := cs.OpenWAL(walFile)
 		if err != nil {
 			cs.Logger.Error("Error loading ConsensusState wal", "err", err.Error())
 			return err
 		}
+		cs.mtx.Lock()
 		cs.wal = wal
+		cs.mtx.Unlock()
 	}
 
 	// we need the timeoutRoutine for replay so
@@ -303,10 +466,16 @@ func (cs *ConsensusState) OnStart() error {
 		return err
 	}
 
+	if cs.checkInvariants {
+		cs.checkStartInvariants()
+	}
+
 	// we may have lost some votes if the process crashed
 	// reload from consensus log to catchup
-	if cs.doWALCatchup {
+	if cs.doWALCatchup && !cs.config.WalDisabled {
+		atomic.StoreInt32(&cs.walCatchup, int32(WALCatchupReplaying))
 		if err := cs.catchupReplay(cs.Height); err != nil {
+			atomic.StoreInt32(&cs.walCatchup, int32(WALCatchupFailed))
 			// don't try to recover from data corruption error
 			if IsDataCorruptionError(err) {
 				cs.Logger.Error("Encountered corrupt WAL file", "err", err.Error())
@@ -327,7 +496,11 @@ go run scripts/json2wal/main.go wal.json $WALFILE # rebuild the file without cor
 			cs.Logger.Error("Error on catchup replay. Proceeding to start ConsensusState anyway", "err", err.Error())
 			// NOTE: if we ever do return an error here,
 			// make sure to stop the timeoutTicker
+		} else {
+			atomic.StoreInt32(&cs.walCatchup, int32(WALCatchupDone))
 		}
+	} else {
+		atomic.StoreInt32(&cs.walCatchup, int32(WALCatchupSkipped))
 	}
 
 	// now start the receiveRoutine
@@ -458,6 +631,28 @@ func (cs *ConsensusState) scheduleRound0(rs *cstypes.RoundState) {
 	cs.scheduleTimeout(sleepDuration, rs.Height, 0, cstypes.RoundStepNewHeight)
 }
 
+// nextHeight returns the first height not entered yet.
+func (cs *ConsensusState) nextHeight() int64 {
+	if cs.Step == cstypes.RoundStepNewHeight {
+		return cs.Height
+	}
+	return cs.Height + 1
+}
+
+// paused returns true if the node has stopped before entering its height.
+func (cs *ConsensusState) paused() bool {
+	return cs.haltHeight > 0 && cs.Height >= cs.haltHeight && cs.Step == cstypes.RoundStepNewHeight
+}
+
+// resume makes the receive routine enter the height the node was paused at,
+// unless it's too early to, in which case its timeout will.
+func (cs *ConsensusState) resume() {
+	select {
+	case cs.resumeCh <- struct{}{}:
+	default:
+	}
+}
+
 // Attempt to schedule a timeout (by sending timeoutInfo on the tickChan)
 func (cs *ConsensusState) scheduleTimeout(duration time.Duration, height int64, round int, step cstypes.RoundStepType) {
 	cs.timeoutTicker.ScheduleTimeout(timeoutInfo{duration, height, round, step})
@@ -484,7 +679,7 @@ func (cs *ConsensusState) reconstructLastCommit(state sm.State) {
 		return
 	}
 	seenCommit := cs.blockStore.LoadSeenCommit(state.LastBlockHeight)
-	lastPrecommits := types.NewVoteSet(state.ChainID, state.LastBlockHeight, seenCommit.Round(), types.PrecommitType, state.LastValidators)
+	lastPrecommits := types.NewVoteSet(state.ChainID, state.SignDomain, state.LastBlockHeight, seenCommit.Round(), types.PrecommitType, state.LastValidators)
 	for _, precommit := range seenCommit.Precommits {
 		if precommit == nil {
 			continue
@@ -547,9 +742,18 @@ func (cs *ConsensusState) updateToState(state sm.State) {
 		// to be gathered for the first block.
 		// And alternative solution that relies on clocks:
 		//  cs.StartTime = state.LastBlockTime.Add(timeoutCommit)
-		cs.StartTime = cs.config.Commit(tmtime.Now())
+		cs.StartTime = cs.commitTime(tmtime.Now())
+		// Don't start the first block before the genesis time.
+		if height == 1 && cs.StartTime.Before(state.LastBlockTime) {
+			cs.StartTime = state.LastBlockTime
+		}
 	} else {
-		cs.StartTime = cs.config.Commit(cs.CommitTime)
+		cs.StartTime = cs.commitTime(cs.CommitTime)
+	}
+	if lastPrecommits != nil && lastPrecommits.HasAll() {
+		cs.lastCommitAllTime = tmtime.Now()
+	} else {
+		cs.lastCommitAllTime = time.Time{}
 	}
 
 	cs.Validators = validators
@@ -562,7 +766,7 @@ func (cs *ConsensusState) updateToState(state sm.State) {
 	cs.ValidRound = -1
 	cs.ValidBlock = nil
 	cs.ValidBlockParts = nil
-	cs.Votes = cstypes.NewHeightVoteSet(state.ChainID, height, validators)
+	cs.Votes = cstypes.NewHeightVoteSet(state.ChainID, state.SignDomain, height, validators)
 	cs.CommitRound = -1
 	cs.LastCommit = lastPrecommits
 	cs.LastValidators = state.LastValidators
@@ -574,6 +778,37 @@ func (cs *ConsensusState) updateToState(state sm.State) {
 	cs.newStep()
 }
 
+// commitTime returns the time to start the next height at, after committing
+// at t.
+func (cs *ConsensusState) commitTime(t time.Time) time.Time {
+	if cs.timeouts == nil {
+		return cs.config.Commit(t)
+	}
+	return t.Add(cs.timeouts.Commit())
+}
+
+// proposeTimeout returns the timeout of the propose step of the round.
+func (cs *ConsensusState) proposeTimeout(round int) time.Duration {
+	if cs.timeouts == nil {
+		return cs.config.Propose(round)
+	}
+	return cs.timeouts.Propose() + cs.config.TimeoutProposeDelta*time.Duration(round)
+}
+
+// sampleCommitLatency records the latency of the precommits of the last
+// height, from +2/3 of them to all of them, or the whole timeout_commit if
+// some are still missing.
+func (cs *ConsensusState) sampleCommitLatency() {
+	if cs.timeouts == nil || cs.replayMode || cs.CommitTime.IsZero() || cs.LastCommit == nil {
+		return
+	}
+	if cs.lastCommitAllTime.IsZero() {
+		cs.timeouts.AddCommitLatency(cs.timeouts.Commit())
+	} else {
+		cs.timeouts.AddCommitLatency(cs.lastCommitAllTime.Sub(cs.CommitTime))
+	}
+}
+
 func (cs *ConsensusState) newStep() {
 	rs := cs.RoundStateEvent()
 	cs.wal.Write(rs)
@@ -632,8 +867,22 @@ func (cs *ConsensusState) receiveRoutine(maxSteps int) {
 		rs := cs.RoundState
 		var mi msgInfo
 
+		// leave the notification of available txs for when we're resumed
+		txsAvailable := cs.txNotifier.TxsAvailable()
+		replayPausedMsgs := false
+		cs.mtx.RLock()
+		if cs.paused() {
+			txsAvailable = nil
+		} else {
+			replayPausedMsgs = len(cs.pausedMsgs) > 0 && cs.Height >= cs.pausedMsgsHeight
+		}
+		cs.mtx.RUnlock()
+		if replayPausedMsgs {
+			cs.handlePausedMsgs()
+		}
+
 		select {
-		case <-cs.txNotifier.TxsAvailable():
+		case <-txsAvailable:
 			cs.handleTxsAvailable()
 		case mi = <-cs.peerMsgQueue:
 			cs.wal.Write(mi)
@@ -658,6 +907,15 @@ func (cs *ConsensusState) receiveRoutine(maxSteps int) {
 			// if the timeout is relevant to the rs
 			// go to the next step
 			cs.handleTimeout(ti, rs)
+		case <-cs.resumeCh:
+			// The timeout to enter the height was ignored while paused, so
+			// replay it, if it has fired.
+			if !tmtime.Now().Before(rs.StartTime) {
+				ti := timeoutInfo{0, rs.Height, 0, cstypes.RoundStepNewHeight}
+				cs.wal.Write(ti)
+				cs.handleTimeout(ti, rs)
+			}
+			cs.handlePausedMsgs()
 		case <-cs.Quit():
 			onExit(cs)
 			return
@@ -665,6 +923,37 @@ func (cs *ConsensusState) receiveRoutine(maxSteps int) {
 	}
 }
 
+// handlePausedMsgs handles the msgs received while paused, in order. The ones
+// for a later height are kept until the node reaches it, instead of being
+// dropped as too early.
+func (cs *ConsensusState) handlePausedMsgs() {
+	for {
+		height := cs.Height
+		msgs := cs.pausedMsgs
+		cs.pausedMsgs = nil
+		var later []msgInfo
+		for _, mi := range msgs {
+			if msgHeight(mi.Msg) > cs.Height {
+				later = append(later, mi)
+				continue
+			}
+			cs.handleMsg(mi)
+		}
+		cs.pausedMsgs = append(later, cs.pausedMsgs...)
+		// Handling the msgs may have committed the height.
+		if cs.Height == height || len(later) == 0 || cs.paused() {
+			break
+		}
+	}
+
+	cs.pausedMsgsHeight = 0
+	for _, mi := range cs.pausedMsgs {
+		if h := msgHeight(mi.Msg); cs.pausedMsgsHeight == 0 || h < cs.pausedMsgsHeight {
+			cs.pausedMsgsHeight = h
+		}
+	}
+}
+
 // state transitions on complete-proposal, 2/3-any, 2/3-one
 func (cs *ConsensusState) handleMsg(mi msgInfo) {
 	cs.mtx.Lock()
@@ -672,6 +961,14 @@ func (cs *ConsensusState) handleMsg(mi msgInfo) {
 
 	var err error
 	msg, peerID := mi.Msg, mi.PeerID
+	if cs.paused() && msgHeight(msg) >= cs.Height {
+		if len(cs.pausedMsgs) >= maxPausedMsgs {
+			cs.Logger.Debug("Dropping msg while paused", "height", cs.Height, "type", reflect.TypeOf(msg), "peer", peerID)
+			return
+		}
+		cs.pausedMsgs = append(cs.pausedMsgs, mi)
+		return
+	}
 	switch msg := msg.(type) {
 	case *ProposalMessage:
 		// will not cause transition.
@@ -688,6 +985,18 @@ func (cs *ConsensusState) handleMsg(mi msgInfo) {
 			cs.Logger.Debug("Received block part from wrong round", "height", cs.Height, "csRound", cs.Round, "blockRound", msg.Round)
 			err = nil
 		}
+	case *BlockParityPartMessage:
+		// if the proposal block is reconstructed, we'll enterPrevote or tryFinalizeCommit
+		var added bool
+		added, err = cs.addProposalBlockParityPart(msg, peerID)
+		if added {
+			cs.statsMsgQueue <- mi
+		}
+
+		if err != nil && msg.Round != cs.Round {
+			cs.Logger.Debug("Received block parity part from wrong round", "height", cs.Height, "csRound", cs.Round, "blockRound", msg.Round)
+			err = nil
+		}
 	case *VoteMessage:
 		// attempt to add the vote and dupeout the validator if its a duplicate signature
 		// if the vote gives us a 2/3-any or 2/3-one, we transition
@@ -718,6 +1027,22 @@ func (cs *ConsensusState) handleMsg(mi msgInfo) {
 	}
 }
 
+// msgHeight returns the height of a message handled by handleMsg.
+func msgHeight(msg ConsensusMessage) int64 {
+	switch msg := msg.(type) {
+	case *ProposalMessage:
+		return msg.Proposal.Height
+	case *BlockPartMessage:
+		return msg.Height
+	case *BlockParityPartMessage:
+		return msg.Height
+	case *VoteMessage:
+		return msg.Vote.Height
+	default:
+		return 0
+	}
+}
+
 func (cs *ConsensusState) handleTimeout(ti timeoutInfo, rs cstypes.RoundState) {
 	cs.Logger.Debug("Received tock", "timeout", ti.Duration, "height", ti.Height, "round", ti.Round, "step", ti.Step)
 
@@ -747,7 +1072,7 @@ func (cs *ConsensusState) handleTimeout(ti timeoutInfo, rs cstypes.RoundState) {
 	case cstypes.RoundStepPrecommitWait:
 		cs.eventBus.PublishEventTimeoutWait(cs.RoundStateEvent())
 		cs.enterPrecommit(ti.Height, ti.Round)
-		cs.enterNewRound(ti.Height, ti.Round+1)
+		cs.advanceRound(ti.Height, ti.Round+1, roundAdvanceTimeout)
 	default:
 		panic(fmt.Sprintf("Invalid timeout step: %v", ti.Step))
 	}
@@ -780,17 +1105,26 @@ func (cs *ConsensusState) enterNewRound(height int64, round int) {
 		return
 	}
 
+	if cs.paused() {
+		logger.Info(fmt.Sprintf("enterNewRound(%v/%v): Consensus is paused", height, round))
+		return
+	}
+
 	if now := tmtime.Now(); cs.StartTime.After(now) {
 		logger.Info("Need to set a buffer and log message here for sanity.", "startTime", cs.StartTime, "now", now)
 	}
 
 	logger.Info(fmt.Sprintf("enterNewRound(%v/%v). Current: %v/%v/%v", height, round, cs.Height, cs.Round, cs.Step))
 
+	if round == 0 {
+		cs.sampleCommitLatency()
+	}
+
 	// Increment validators if necessary
 	validators := cs.Validators
 	if cs.Round < round {
 		validators = validators.Copy()
-		validators.IncrementProposerPriority(round - cs.Round)
+		validators.IncrementProposerPriority(int64(round - cs.Round))
 	}
 
 	// Setup new round
@@ -818,16 +1152,43 @@ func (cs *ConsensusState) enterNewRound(height int64, round int) {
 	// before we enterPropose in round 0. If the last block changed the app hash,
 	// we may need an empty "proof" block, and enterPropose immediately.
 	waitForTxs := cs.config.WaitForTxs() && round == 0 && !cs.needProofBlock(height)
-	if waitForTxs {
+	if cs.waitForPeers(height, round) {
+		logger.Info("Waiting for a quorum of the persistent peers to propose the first block")
+	} else if waitForTxs {
 		if cs.config.CreateEmptyBlocksInterval > 0 {
-			cs.scheduleTimeout(cs.config.CreateEmptyBlocksInterval, height, round,
-				cstypes.RoundStepNewRound)
+			maxInterval := cs.state.ConsensusParams.BlockSize.MaxEmptyInterval()
+			cs.scheduleTimeout(cs.config.EmptyBlocksInterval(cs.emptyBlocks(height, maxInterval), maxInterval),
+				height, round, cstypes.RoundStepNewRound)
 		}
 	} else {
 		cs.enterPropose(height, round)
 	}
 }
 
+// waitForPeers returns true if we wait for a quorum of the persistent peers to
+// be connected before entering the propose step of the first block, not to
+// waste rounds at the launch of the network.
+func (cs *ConsensusState) waitForPeers(height int64, round int) bool {
+	return cs.config.WaitForPersistentPeers && !cs.peersQuorum && height == 1 && round == 0
+}
+
+// SetPersistentPeersQuorum records that a quorum of the persistent peers is
+// connected, entering the propose step of the first block if we were waiting
+// for them.
+func (cs *ConsensusState) SetPersistentPeersQuorum() {
+	cs.mtx.Lock()
+	defer cs.mtx.Unlock()
+
+	if cs.peersQuorum {
+		return
+	}
+	cs.peersQuorum = true
+	if cs.config.WaitForPersistentPeers && cs.Height == 1 && cs.Round == 0 && cs.Step == cstypes.RoundStepNewRound {
+		cs.Logger.Info("A quorum of the persistent peers is connected")
+		cs.scheduleTimeout(0, cs.Height, 0, cstypes.RoundStepNewRound)
+	}
+}
+
 // needProofBlock returns true on the first height (so the genesis app hash is signed right away)
 // and where the last block (height-1) caused the app hash to change
 func (cs *ConsensusState) needProofBlock(height int64) bool {
@@ -839,6 +1200,49 @@ func (cs *ConsensusState) needProofBlock(height int64) bool {
 	return !bytes.Equal(cs.state.AppHash, lastBlockMeta.Header.AppHash)
 }
 
+// emptyBlocks returns the number of consecutive empty blocks before height,
+// as many as needed to reach the maxInterval of the consensus params.
+func (cs *ConsensusState) emptyBlocks(height int64, maxInterval time.Duration) int {
+	emptyBlocks := 0
+	for h := height - 1; h > 0; h-- {
+		if cs.config.EmptyBlocksInterval(emptyBlocks, maxInterval) >= maxInterval {
+			break
+		}
+		blockMeta := cs.blockStore.LoadBlockMeta(h)
+		if blockMeta == nil || blockMeta.Header.NumTxs > 0 {
+			break
+		}
+		emptyBlocks++
+	}
+	return emptyBlocks
+}
+
+// Reasons for the round to advance past the current one, as labels of the
+// RoundsAdvanced metric.
+const (
+	roundAdvanceTimeout = "timeout"
+	roundAdvancePolka   = "polka"
+	roundAdvanceCommit  = "commit"
+)
+
+// advanceRound calls enterNewRound and, if it moved past the current round,
+// records the reason and whether the proposer of the current round failed to
+// deliver its proposal.
+func (cs *ConsensusState) advanceRound(height int64, round int, reason string) {
+	prevRound := cs.Round
+	proposer := cs.Validators.GetProposer()
+	missingProposal := cs.Proposal == nil || cs.ProposalBlock == nil
+
+	cs.enterNewRound(height, round)
+	if cs.Height != height || cs.Round != round || round <= prevRound {
+		return
+	}
+	cs.metrics.RoundsAdvanced.With("reason", reason).Add(1)
+	if missingProposal && proposer != nil {
+		cs.metrics.MissingProposalRounds.With("proposer_address", proposer.Address.String()).Add(1)
+	}
+}
+
 // Enter (CreateEmptyBlocks): from enterNewRound(height,round)
 // Enter (CreateEmptyBlocks, CreateEmptyBlocksInterval > 0 ): after enterNewRound(height,round), after timeout of CreateEmptyBlocksInterval
 // Enter (!CreateEmptyBlocks) : after enterNewRound(height,round), once txs are in the mempool
@@ -865,7 +1269,8 @@ func (cs *ConsensusState) enterPropose(height int64, round int) {
 	}()
 
 	// If we don't get the proposal and all block parts quick enough, enterPrevote
-	cs.scheduleTimeout(cs.config.Propose(round), height, round, cstypes.RoundStepPropose)
+	cs.proposeStartTime = tmtime.Now()
+	cs.scheduleTimeout(cs.proposeTimeout(round), height, round, cstypes.RoundStepPropose)
 
 	// Nothing more to do if we're not a validator
 	if cs.privValidator == nil {
@@ -882,10 +1287,14 @@ func (cs *ConsensusState) enterPropose(height int64, round int) {
 	logger.Debug("This node is a validator")
 
 	if cs.isProposer(address) {
-		logger.Info("enterPropose: Our turn to propose", "proposer", cs.Validators.GetProposer().Address, "privValidator", cs.privValidator)
+		logger.Info("enterPropose: Our turn to propose", "proposer", cs.Validators.GetProposer().Address, "proposerMoniker", cs.monikers.Moniker(cs.Validators.GetProposer().Address), "privValidator", cs.privValidator)
+		if cs.config.SkipProposeOnClockSkew && cs.clockSkew.Exceeded() {
+			logger.Error("enterPropose: Not proposing, the local clock is skewed", "skew", cs.clockSkew.Skew())
+			return
+		}
 		cs.decideProposal(height, round)
 	} else {
-		logger.Info("enterPropose: Not our turn to propose", "proposer", cs.Validators.GetProposer().Address, "privValidator", cs.privValidator)
+		logger.Info("enterPropose: Not our turn to propose", "proposer", cs.Validators.GetProposer().Address, "proposerMoniker", cs.monikers.Moniker(cs.Validators.GetProposer().Address), "privValidator", cs.privValidator)
 	}
 }
 
@@ -912,7 +1321,8 @@ func (cs *ConsensusState) defaultDecideProposal(height int64, round int) {
 	// Make proposal
 	propBlockId := types.BlockID{Hash: block.Hash(), PartsHeader: blockParts.Header()}
 	proposal := types.NewProposal(height, round, cs.ValidRound, propBlockId)
-	if err := cs.privValidator.SignProposal(cs.state.ChainID, proposal); err == nil {
+	proposal.ParityPartsHeader = cs.proposalParityHeader(blockParts)
+	if err := cs.privValidator.SignProposal(cs.state.ChainID, cs.state.SignDomain, proposal); err == nil {
 
 		// send proposal and block parts on internal msg queue
 		cs.sendInternalMessage(msgInfo{&ProposalMessage{proposal}, ""})
@@ -929,6 +1339,22 @@ func (cs *ConsensusState) defaultDecideProposal(height int64, round int) {
 	}
 }
 
+// proposalParityHeader erasure codes the parts of the block we propose, and
+// returns the header of its parity parts for the proposal, nil if it isn't
+// erasure coded.
+func (cs *ConsensusState) proposalParityHeader(blockParts *types.PartSet) *types.PartSetHeader {
+	parityTotal := types.ParityPartsTotal(blockParts.Total(), cs.config.BlockPartsParityRatio)
+	if parityTotal == 0 {
+		return nil
+	}
+	if err := blockParts.MakeParityParts(parityTotal); err != nil {
+		cs.Logger.Error("Failed to make the parity parts of the proposal block", "err", err)
+		return nil
+	}
+	header := blockParts.ParityHeader()
+	return &header
+}
+
 // Returns true if the proposal block is complete &&
 // (if POLRound was proposed, we have +2/3 prevotes from there).
 func (cs *ConsensusState) isProposalComplete() bool {
@@ -987,6 +1413,11 @@ func (cs *ConsensusState) enterPrevote(height int64, round int) {
 
 	cs.Logger.Info(fmt.Sprintf("enterPrevote(%v/%v). Current: %v/%v/%v", height, round, cs.Height, cs.Round, cs.Step))
 
+	if cs.timeouts != nil && !cs.replayMode && !cs.proposeStartTime.IsZero() {
+		cs.timeouts.AddProposeLatency(tmtime.Now().Sub(cs.proposeStartTime))
+	}
+	cs.proposeStartTime = time.Time{}
+
 	// Sign and broadcast vote as necessary
 	cs.doPrevote(height, round)
 
@@ -1290,7 +1721,9 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 		// but may differ from the LastCommit included in the next block
 		precommits := cs.Votes.Precommits(cs.CommitRound)
 		seenCommit := precommits.MakeCommit()
+		startTime := time.Now()
 		cs.blockStore.SaveBlock(block, blockParts, seenCommit)
+		cs.metrics.BlockStoreCommitTime.Observe(float64(time.Since(startTime)) / float64(time.Millisecond))
 	} else {
 		// Happens during replay if we already saved the block but didn't commit
 		cs.Logger.Info("Calling finalizeCommit on already stored block", "height", block.Height)
@@ -1311,6 +1744,9 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 	// Either way, the ConsensusState should not be resumed until we
 	// successfully call ApplyBlock (ie. later here, or in Handshake after
 	// restart).
+	if cs.checkInvariants {
+		cs.checkEndHeightInvariants(height)
+	}
 	cs.wal.WriteSync(EndHeightMessage{height}) // NOTE: fsync
 
 	fail.Fail() // XXX
@@ -1335,6 +1771,7 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 
 	// must be called before we update state
 	cs.recordMetrics(height, block)
+	cs.recordBlockTime(block)
 
 	// NewHeightStep!
 	cs.updateToState(stateCopy)
@@ -1351,6 +1788,16 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 	// * cs.StartTime is set to when we will start round0.
 }
 
+// recordBlockTime measures the skew of the local clock against the time of
+// the block, which is the median of the times of the precommits for the
+// previous block, signed when we committed it.
+func (cs *ConsensusState) recordBlockTime(block *types.Block) {
+	if !cs.replayMode && !cs.lastCommitTime.IsZero() && cs.lastCommitHeight == block.Height-1 {
+		cs.clockSkew.AddBlock(cs.lastCommitTime.Sub(block.Time))
+	}
+	cs.lastCommitTime, cs.lastCommitHeight = cs.CommitTime, block.Height
+}
+
 func (cs *ConsensusState) recordMetrics(height int64, block *types.Block) {
 	cs.metrics.Validators.Set(float64(cs.Validators.Size()))
 	cs.metrics.ValidatorsPower.Set(float64(cs.Validators.TotalVotingPower()))
@@ -1379,9 +1826,9 @@ func (cs *ConsensusState) recordMetrics(height int64, block *types.Block) {
 
 	if height > 1 {
 		lastBlockMeta := cs.blockStore.LoadBlockMeta(height - 1)
-		cs.metrics.BlockIntervalSeconds.Set(
-			block.Time.Sub(lastBlockMeta.Header.Time).Seconds(),
-		)
+		interval := block.Time.Sub(lastBlockMeta.Header.Time).Seconds()
+		cs.metrics.BlockIntervalSeconds.Set(interval)
+		cs.metrics.BlockIntervalHistogram.Observe(interval)
 	}
 
 	cs.metrics.NumTxs.Set(float64(block.NumTxs))
@@ -1412,7 +1859,7 @@ func (cs *ConsensusState) defaultSetProposal(proposal *types.Proposal) error {
 	}
 
 	// Verify signature
-	if !cs.Validators.GetProposer().PubKey.VerifyBytes(proposal.SignBytes(cs.state.ChainID), proposal.Signature) {
+	if !cs.Validators.GetProposer().PubKey.VerifyBytes(proposal.SignBytes(cs.state.ChainID, cs.state.SignDomain), proposal.Signature) {
 		return ErrInvalidProposalSignature
 	}
 
@@ -1423,6 +1870,11 @@ func (cs *ConsensusState) defaultSetProposal(proposal *types.Proposal) error {
 	if cs.ProposalBlockParts == nil {
 		cs.ProposalBlockParts = types.NewPartSetFromHeader(proposal.BlockID.PartsHeader)
 	}
+	// The parity parts of the block are verified against the header signed
+	// with the proposal.
+	if proposal.ParityPartsHeader != nil && cs.ProposalBlockParts.HasHeader(proposal.BlockID.PartsHeader) {
+		cs.ProposalBlockParts.SetParityHeader(*proposal.ParityPartsHeader)
+	}
 	cs.Logger.Info("Received proposal", "proposal", proposal)
 	return nil
 }
@@ -1453,49 +1905,105 @@ func (cs *ConsensusState) addProposalBlockPart(msg *BlockPartMessage, peerID p2p
 	}
 	if added && cs.ProposalBlockParts.IsComplete() {
 		// Added and completed!
-		_, err = cdc.UnmarshalBinaryLengthPrefixedReader(
-			cs.ProposalBlockParts.GetReader(),
-			&cs.ProposalBlock,
-			int64(cs.state.ConsensusParams.BlockSize.MaxBytes),
-		)
-		if err != nil {
-			return added, err
-		}
-		// NOTE: it's possible to receive complete proposal blocks for future rounds without having the proposal
-		cs.Logger.Info("Received complete proposal block", "height", cs.ProposalBlock.Height, "hash", cs.ProposalBlock.Hash())
-		cs.eventBus.PublishEventCompleteProposal(cs.CompleteProposalEvent())
-
-		// Update Valid* if we can.
-		prevotes := cs.Votes.Prevotes(cs.Round)
-		blockID, hasTwoThirds := prevotes.TwoThirdsMajority()
-		if hasTwoThirds && !blockID.IsZero() && (cs.ValidRound < cs.Round) {
-			if cs.ProposalBlock.HashesTo(blockID.Hash) {
-				cs.Logger.Info("Updating valid block to new proposal block",
-					"valid-round", cs.Round, "valid-block-hash", cs.ProposalBlock.Hash())
-				cs.ValidRound = cs.Round
-				cs.ValidBlock = cs.ProposalBlock
-				cs.ValidBlockParts = cs.ProposalBlockParts
-			}
-			// TODO: In case there is +2/3 majority in Prevotes set for some
-			// block and cs.ProposalBlock contains different block, either
-			// proposer is faulty or voting power of faulty processes is more
-			// than 1/3. We should trigger in the future accountability
-			// procedure at this point.
+		return added, cs.handleCompleteProposalBlock(height)
+	}
+	return added, nil
+}
+
+// addProposalBlockParityPart adds a parity part of an erasure coded proposal
+// block, which reconstructs the block once there are enough parts.
+func (cs *ConsensusState) addProposalBlockParityPart(msg *BlockParityPartMessage, peerID p2p.ID) (added bool, err error) {
+	height, round, part := msg.Height, msg.Round, msg.Part
+
+	// Blocks might be reused, so round mismatch is OK
+	if cs.Height != height {
+		cs.Logger.Debug("Received block parity part from wrong height", "height", height, "round", round)
+		return false, nil
+	}
+
+	// We're not expecting a block part.
+	if cs.ProposalBlockParts == nil {
+		cs.Logger.Info("Received a block parity part when we're not expecting any",
+			"height", height, "round", round, "index", part.Index, "peer", peerID)
+		return false, nil
+	}
+
+	added, err = cs.ProposalBlockParts.AddParityPart(part)
+	if err != nil {
+		return added, err
+	}
+	if added && cs.ProposalBlockParts.IsComplete() {
+		// Reconstructed!
+		cs.Logger.Info("Reconstructed the proposal block parts from parity parts", "height", height,
+			"parts", cs.ProposalBlockParts.Total(), "parity", cs.ProposalBlockParts.ParityTotal())
+		return added, cs.handleCompleteProposalBlock(height)
+	}
+	return added, nil
+}
+
+// makeProposalBlockParityParts erasure codes the complete proposal block for
+// the peers, if its proposal has the header of parity parts, which they must
+// match.
+func (cs *ConsensusState) makeProposalBlockParityParts() {
+	parts := cs.ProposalBlockParts
+	parityTotal := parts.ParityHeader().Total
+	if parityTotal == 0 {
+		return
+	}
+	// The gossip of the parts isn't held up by the encoding.
+	go func() {
+		if err := parts.MakeParityParts(parityTotal); err != nil {
+			cs.Logger.Error("Failed to make the parity parts of the proposal block", "err", err)
 		}
+	}()
+}
 
-		if cs.Step <= cstypes.RoundStepPropose && cs.isProposalComplete() {
-			// Move onto the next step
-			cs.enterPrevote(height, cs.Round)
-			if hasTwoThirds { // this is optimisation as this will be triggered when prevote is added
-				cs.enterPrecommit(height, cs.Round)
-			}
-		} else if cs.Step == cstypes.RoundStepCommit {
-			// If we're waiting on the proposal block...
-			cs.tryFinalizeCommit(height)
+// handleCompleteProposalBlock decodes the proposal block once all its parts
+// are received, and moves onto the next step if we can.
+func (cs *ConsensusState) handleCompleteProposalBlock(height int64) error {
+	_, err := cdc.UnmarshalBinaryLengthPrefixedReader(
+		cs.ProposalBlockParts.GetReader(),
+		&cs.ProposalBlock,
+		int64(cs.state.ConsensusParams.BlockSize.MaxBytes),
+	)
+	if err != nil {
+		return err
+	}
+	// NOTE: it's possible to receive complete proposal blocks for future rounds without having the proposal
+	cs.Logger.Info("Received complete proposal block", "height", cs.ProposalBlock.Height, "hash", cs.ProposalBlock.Hash())
+	cs.eventBus.PublishEventCompleteProposal(cs.CompleteProposalEvent())
+	cs.evsw.FireEvent(types.EventCompleteProposal, &cs.RoundState)
+	cs.makeProposalBlockParityParts()
+
+	// Update Valid* if we can.
+	prevotes := cs.Votes.Prevotes(cs.Round)
+	blockID, hasTwoThirds := prevotes.TwoThirdsMajority()
+	if hasTwoThirds && !blockID.IsZero() && (cs.ValidRound < cs.Round) {
+		if cs.ProposalBlock.HashesTo(blockID.Hash) {
+			cs.Logger.Info("Updating valid block to new proposal block",
+				"valid-round", cs.Round, "valid-block-hash", cs.ProposalBlock.Hash())
+			cs.ValidRound = cs.Round
+			cs.ValidBlock = cs.ProposalBlock
+			cs.ValidBlockParts = cs.ProposalBlockParts
+		}
+		// TODO: In case there is +2/3 majority in Prevotes set for some
+		// block and cs.ProposalBlock contains different block, either
+		// proposer is faulty or voting power of faulty processes is more
+		// than 1/3. We should trigger in the future accountability
+		// procedure at this point.
+	}
+
+	if cs.Step <= cstypes.RoundStepPropose && cs.isProposalComplete() {
+		// Move onto the next step
+		cs.enterPrevote(height, cs.Round)
+		if hasTwoThirds { // this is optimisation as this will be triggered when prevote is added
+			cs.enterPrecommit(height, cs.Round)
 		}
-		return added, nil
+	} else if cs.Step == cstypes.RoundStepCommit {
+		// If we're waiting on the proposal block...
+		cs.tryFinalizeCommit(height)
 	}
-	return added, nil
+	return nil
 }
 
 // Attempt to add the vote. if its a duplicate signature, dupeout the validator
@@ -1508,8 +2016,7 @@ func (cs *ConsensusState) tryAddVote(vote *types.Vote, peerID p2p.ID) (bool, err
 		if err == ErrVoteHeightMismatch {
 			return added, err
 		} else if voteErr, ok := err.(*types.ErrVoteConflictingVotes); ok {
-			addr := cs.privValidator.GetPubKey().Address()
-			if bytes.Equal(vote.ValidatorAddress, addr) {
+			if cs.privValidator != nil && bytes.Equal(vote.ValidatorAddress, cs.privValidator.GetPubKey().Address()) {
 				cs.Logger.Error("Found conflicting vote from ourselves. Did you unsafe_reset a validator?", "height", vote.Height, "round", vote.Round, "type", vote.Type)
 				return added, err
 			}
@@ -1546,6 +2053,9 @@ func (cs *ConsensusState) addVote(vote *types.Vote, peerID p2p.ID) (added bool,
 		cs.Logger.Info(fmt.Sprintf("Added to lastPrecommits: %v", cs.LastCommit.StringShort()))
 		cs.eventBus.PublishEventVote(types.EventDataVote{Vote: vote})
 		cs.evsw.FireEvent(types.EventVote, vote)
+		if cs.LastCommit.HasAll() && cs.lastCommitAllTime.IsZero() {
+			cs.lastCommitAllTime = tmtime.Now()
+		}
 
 		// if we can skip timeoutCommit and have all the votes now,
 		if cs.config.SkipTimeoutCommit && cs.LastCommit.HasAll() {
@@ -1578,7 +2088,7 @@ func (cs *ConsensusState) addVote(vote *types.Vote, peerID p2p.ID) (added bool,
 	switch vote.Type {
 	case types.PrevoteType:
 		prevotes := cs.Votes.Prevotes(vote.Round)
-		cs.Logger.Info("Added to prevote", "vote", vote, "prevotes", prevotes.StringShort())
+		cs.Logger.Info("Added to prevote", "vote", vote, "moniker", cs.monikers.Moniker(vote.ValidatorAddress), "prevotes", prevotes.StringShort())
 
 		// If +2/3 prevotes for a block or nil for *any* round:
 		if blockID, ok := prevotes.TwoThirdsMajority(); ok {
@@ -1629,7 +2139,7 @@ func (cs *ConsensusState) addVote(vote *types.Vote, peerID p2p.ID) (added bool,
 		// If +2/3 prevotes for *anything* for future round:
 		if cs.Round < vote.Round && prevotes.HasTwoThirdsAny() {
 			// Round-skip if there is any 2/3+ of votes ahead of us
-			cs.enterNewRound(height, vote.Round)
+			cs.advanceRound(height, vote.Round, roundAdvancePolka)
 		} else if cs.Round == vote.Round && cstypes.RoundStepPrevote <= cs.Step { // current round
 			blockID, ok := prevotes.TwoThirdsMajority()
 			if ok && (cs.isProposalComplete() || len(blockID.Hash) == 0) {
@@ -1646,12 +2156,12 @@ func (cs *ConsensusState) addVote(vote *types.Vote, peerID p2p.ID) (added bool,
 
 	case types.PrecommitType:
 		precommits := cs.Votes.Precommits(vote.Round)
-		cs.Logger.Info("Added to precommit", "vote", vote, "precommits", precommits.StringShort())
+		cs.Logger.Info("Added to precommit", "vote", vote, "moniker", cs.monikers.Moniker(vote.ValidatorAddress), "precommits", precommits.StringShort())
 
 		blockID, ok := precommits.TwoThirdsMajority()
 		if ok {
 			// Executed as TwoThirdsMajority could be from a higher round
-			cs.enterNewRound(height, vote.Round)
+			cs.advanceRound(height, vote.Round, roundAdvanceCommit)
 			cs.enterPrecommit(height, vote.Round)
 			if len(blockID.Hash) != 0 {
 				cs.enterCommit(height, vote.Round)
@@ -1662,7 +2172,7 @@ func (cs *ConsensusState) addVote(vote *types.Vote, peerID p2p.ID) (added bool,
 				cs.enterPrecommitWait(height, vote.Round)
 			}
 		} else if cs.Round <= vote.Round && precommits.HasTwoThirdsAny() {
-			cs.enterNewRound(height, vote.Round)
+			cs.advanceRound(height, vote.Round, roundAdvanceCommit)
 			cs.enterPrecommitWait(height, vote.Round)
 		}
 
@@ -1686,7 +2196,7 @@ func (cs *ConsensusState) signVote(type_ types.SignedMsgType, hash []byte, heade
 		Type:             type_,
 		BlockID:          types.BlockID{Hash: hash, PartsHeader: header},
 	}
-	err := cs.privValidator.SignVote(cs.state.ChainID, vote)
+	err := cs.privValidator.SignVote(cs.state.ChainID, cs.state.SignDomain, vote)
 	return vote, err
 }
 
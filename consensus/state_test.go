@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"testing"
 	"time"
 
@@ -194,7 +195,7 @@ func TestStateBadProposal(t *testing.T) {
 	propBlockParts := propBlock.MakePartSet(partSize)
 	blockID := types.BlockID{propBlock.Hash(), propBlockParts.Header()}
 	proposal := types.NewProposal(vs2.Height, round, -1, blockID)
-	if err := vs2.SignProposal(config.ChainID(), proposal); err != nil {
+	if err := vs2.SignProposal(config.ChainID(), types.SignDomain{}, proposal); err != nil {
 		t.Fatal("failed to sign bad proposal", err)
 	}
 
@@ -223,6 +224,39 @@ func TestStateBadProposal(t *testing.T) {
 	signAddVotes(cs1, types.PrecommitType, propBlock.Hash(), propBlock.MakePartSet(partSize).Header(), vs2)
 }
 
+// Ensure the proposal block is reconstructed from parity parts alone, verified
+// against the parity header of the proposal.
+func TestStateProposalBlockFromParityParts(t *testing.T) {
+	cs1, vss := randConsensusState(2)
+	height, round := cs1.Height, cs1.Round
+	vs2 := vss[1]
+
+	proposalCh := subscribe(cs1.eventBus, types.EventQueryCompleteProposal)
+
+	// make the second validator the proposer by incrementing round
+	round = round + 1
+	incrementRound(vss[1:]...)
+
+	proposal, propBlock := decideProposal(cs1, vs2, vs2.Height, round)
+	propBlockParts := propBlock.MakePartSet(types.BlockPartSizeBytes)
+	require.NoError(t, propBlockParts.MakeParityParts(2))
+	parityHeader := propBlockParts.ParityHeader()
+	proposal.ParityPartsHeader = &parityHeader
+	require.NoError(t, vs2.SignProposal(cs1.state.ChainID, types.SignDomain{}, proposal))
+
+	startTestRound(cs1, height, round)
+	require.NoError(t, cs1.SetProposal(proposal, "some peer"))
+
+	// the invalid parity part is rejected, the valid ones reconstruct the block
+	invalid := *propBlockParts.GetParityPart(0)
+	invalid.Bytes = append([]byte{invalid.Bytes[0] + 1}, invalid.Bytes[1:]...)
+	for _, part := range []*types.ParityPart{&invalid, propBlockParts.GetParityPart(1), propBlockParts.GetParityPart(0)} {
+		cs1.peerMsgQueue <- msgInfo{&BlockParityPartMessage{height, round, part}, "some peer"}
+	}
+	ensureProposal(proposalCh, height, round, proposal.BlockID)
+	assert.True(t, bytes.Equal(propBlock.Hash(), cs1.GetRoundState().ProposalBlock.Hash()))
+}
+
 //----------------------------------------------------------------------------------------------------
 // FullRoundSuite
 
@@ -855,7 +889,7 @@ func TestStateLockPOLSafety2(t *testing.T) {
 	round = round + 1 // moving to the next round
 	// in round 2 we see the polkad block from round 0
 	newProp := types.NewProposal(height, round, 0, propBlockID0)
-	if err := vs3.SignProposal(config.ChainID(), newProp); err != nil {
+	if err := vs3.SignProposal(config.ChainID(), types.SignDomain{}, newProp); err != nil {
 		t.Fatal(err)
 	}
 	if err := cs1.SetProposalAndBlock(newProp, propBlock0, propBlockParts0, "some peer"); err != nil {
@@ -1619,6 +1653,91 @@ func TestStateOutputVoteStats(t *testing.T) {
 
 }
 
+func TestStatePauseStepResume(t *testing.T) {
+	cs, _ := randConsensusState(1)
+	height, round := cs.Height, cs.Round
+
+	newRoundCh := subscribe(cs.eventBus, types.EventQueryNewRound)
+	newBlockCh := subscribe(cs.eventBus, types.EventQueryNewBlock)
+
+	pauseHeight, err := cs.PauseAtHeight(height + 1)
+	require.NoError(t, err)
+	assert.Equal(t, height+1, pauseHeight)
+
+	startTestRound(cs, height, round)
+	defer cs.Stop()
+
+	// the node commits the first height, and doesn't enter the next one
+	ensureNewRound(newRoundCh, height, round)
+	ensureNewBlock(newBlockCh, height)
+	ensureNoNewEventOnChannel(newRoundCh)
+	pauseHeight, paused := cs.PausedAt()
+	assert.Equal(t, height+1, pauseHeight)
+	assert.True(t, paused)
+
+	_, err = cs.PauseAtHeight(height)
+	assert.Error(t, err, "the height was already entered")
+
+	// stepping commits exactly one height
+	pauseHeight, err = cs.StepHeight()
+	require.NoError(t, err)
+	assert.Equal(t, height+2, pauseHeight)
+	ensureNewRound(newRoundCh, height+1, 0)
+	ensureNewBlock(newBlockCh, height+1)
+	ensureNoNewEventOnChannel(newRoundCh)
+
+	cs.Resume()
+	_, err = cs.StepHeight()
+	assert.Error(t, err, "consensus is not paused")
+	ensureNewRound(newRoundCh, height+2, 0)
+	ensureNewBlock(newBlockCh, height+2)
+	ensureNewRound(newRoundCh, height+3, 0)
+}
+
+func TestStateKeepsMsgsWhilePaused(t *testing.T) {
+	cs, vss := randConsensusState(2)
+	height, round := cs.Height, cs.Round
+
+	_, err := cs.PauseAtHeight(height)
+	require.NoError(t, err)
+	vote := signVote(vss[1], types.PrevoteType, nil, types.PartSetHeader{})
+	cs.handleMsg(msgInfo{&VoteMessage{vote}, "peer"})
+	incrementHeight(vss[1])
+	nextVote := signVote(vss[1], types.PrevoteType, nil, types.PartSetHeader{})
+	cs.handleMsg(msgInfo{&VoteMessage{nextVote}, "peer"})
+	assert.Len(t, cs.pausedMsgs, 2)
+	assert.Nil(t, cs.Votes.Prevotes(round).GetByIndex(1))
+
+	// the msgs are handled once resumed, but the ones for the next height
+	// are kept until the node reaches it
+	cs.Resume()
+	cs.handlePausedMsgs()
+	assert.Equal(t, vote, cs.Votes.Prevotes(round).GetByIndex(1))
+	require.Len(t, cs.pausedMsgs, 1)
+	assert.Equal(t, nextVote, cs.pausedMsgs[0].Msg.(*VoteMessage).Vote)
+	assert.Equal(t, height+1, cs.pausedMsgsHeight)
+}
+
+func TestStateWalDisabled(t *testing.T) {
+	cs, _ := randConsensusState(1)
+	cs.config.WalDisabled = true
+	height := cs.Height
+
+	newBlockCh := subscribe(cs.eventBus, types.EventQueryNewBlock)
+
+	require.NoError(t, cs.Start())
+	defer cs.Stop()
+
+	// the node commits blocks without opening the WAL, nor replaying it
+	ensureNewBlock(newBlockCh, height)
+	ensureNewBlock(newBlockCh, height+1)
+	assert.Equal(t, WALCatchupSkipped, cs.WALCatchupStatus())
+	_, ok := cs.WALGroupInfo()
+	assert.False(t, ok)
+	_, err := os.Stat(cs.config.WalFile())
+	assert.True(t, os.IsNotExist(err))
+}
+
 // subscribe subscribes test client to the given query and returns a channel with cap = 1.
 func subscribe(eventBus *types.EventBus, q tmpubsub.Query) <-chan interface{} {
 	out := make(chan interface{}, 1)
@@ -0,0 +1,104 @@
+package consensus
+
+import (
+	"time"
+)
+
+// timeoutTunerSamples is the number of recent latencies the timeouts are
+// adjusted to.
+const timeoutTunerSamples = 20
+
+// timeoutTuner adjusts timeout_propose and timeout_commit to the latencies
+// observed by the consensus, within their bounds: each timeout is twice the
+// longest of the recent latencies, so that a fast network doesn't wait for
+// the timeouts configured for a slow one.
+//
+// The propose latency is the time from entering the propose step to entering
+// the prevote one, i.e. to receiving the complete proposal, or +2/3 prevotes,
+// or timing out. The commit latency is the time from +2/3 precommits to
+// receiving the precommits of all the validators, the whole timeout if some
+// are missing.
+//
+// It is only used by the receiveRoutine of the ConsensusState.
+type timeoutTuner struct {
+	propose, commit latencyWindow
+	metrics         *Metrics
+}
+
+func newTimeoutTuner(proposeMin, proposeMax, commitMin, commitMax time.Duration, metrics *Metrics) *timeoutTuner {
+	t := &timeoutTuner{
+		propose: newLatencyWindow(proposeMin, proposeMax),
+		commit:  newLatencyWindow(commitMin, commitMax),
+		metrics: metrics,
+	}
+	t.updateMetrics()
+	return t
+}
+
+// Propose returns the timeout of the propose step of round 0.
+func (t *timeoutTuner) Propose() time.Duration {
+	return t.propose.timeout
+}
+
+// Commit returns the timeout of the commit.
+func (t *timeoutTuner) Commit() time.Duration {
+	return t.commit.timeout
+}
+
+// AddProposeLatency records a propose latency, and adjusts the timeouts.
+func (t *timeoutTuner) AddProposeLatency(latency time.Duration) {
+	t.propose.add(latency)
+	t.updateMetrics()
+}
+
+// AddCommitLatency records a commit latency, and adjusts the timeouts.
+func (t *timeoutTuner) AddCommitLatency(latency time.Duration) {
+	t.commit.add(latency)
+	t.updateMetrics()
+}
+
+func (t *timeoutTuner) updateMetrics() {
+	t.metrics.TimeoutProposeSeconds.Set(t.propose.timeout.Seconds())
+	t.metrics.TimeoutCommitSeconds.Set(t.commit.timeout.Seconds())
+}
+
+// latencyWindow is the timeout adjusted to the last timeoutTunerSamples
+// latencies, within [min, max]: max until a latency is recorded.
+type latencyWindow struct {
+	min, max  time.Duration
+	latencies []time.Duration // ring
+	next      int
+	timeout   time.Duration
+}
+
+func newLatencyWindow(min, max time.Duration) latencyWindow {
+	return latencyWindow{
+		min:       min,
+		max:       max,
+		latencies: make([]time.Duration, 0, timeoutTunerSamples),
+		timeout:   max,
+	}
+}
+
+func (w *latencyWindow) add(latency time.Duration) {
+	if len(w.latencies) < timeoutTunerSamples {
+		w.latencies = append(w.latencies, latency)
+	} else {
+		w.latencies[w.next] = latency
+	}
+	w.next = (w.next + 1) % timeoutTunerSamples
+
+	var longest time.Duration
+	for _, l := range w.latencies {
+		if l > longest {
+			longest = l
+		}
+	}
+	w.timeout = 2 * longest
+	if w.timeout < w.min {
+		w.timeout = w.min
+	}
+	if w.timeout > w.max {
+		w.timeout = w.max
+	}
+}
@@ -0,0 +1,38 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutTuner(t *testing.T) {
+	tt := newTimeoutTuner(100*time.Millisecond, 3*time.Second, 50*time.Millisecond, time.Second, NopMetrics())
+
+	// the configured timeouts until a latency is observed
+	assert.Equal(t, 3*time.Second, tt.Propose())
+	assert.Equal(t, time.Second, tt.Commit())
+
+	// twice the longest latency
+	tt.AddProposeLatency(200 * time.Millisecond)
+	tt.AddProposeLatency(300 * time.Millisecond)
+	tt.AddProposeLatency(100 * time.Millisecond)
+	assert.Equal(t, 600*time.Millisecond, tt.Propose())
+	tt.AddCommitLatency(100 * time.Millisecond)
+	assert.Equal(t, 200*time.Millisecond, tt.Commit())
+
+	// within the bounds
+	tt.AddCommitLatency(time.Millisecond)
+	assert.Equal(t, 200*time.Millisecond, tt.Commit())
+	tt.AddProposeLatency(time.Minute)
+	assert.Equal(t, 3*time.Second, tt.Propose())
+
+	// only the last timeoutTunerSamples latencies are kept
+	for i := 0; i < timeoutTunerSamples; i++ {
+		tt.AddProposeLatency(10 * time.Millisecond)
+		tt.AddCommitLatency(10 * time.Millisecond)
+	}
+	assert.Equal(t, 100*time.Millisecond, tt.Propose())
+	assert.Equal(t, 50*time.Millisecond, tt.Commit())
+}
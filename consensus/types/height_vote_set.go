@@ -36,6 +36,7 @@ One for their LastCommit round, and another for the official commit round.
 */
 type HeightVoteSet struct {
 	chainID string
+	domain  types.SignDomain
 	height  int64
 	valSet  *types.ValidatorSet
 
@@ -45,9 +46,10 @@ type HeightVoteSet struct {
 	peerCatchupRounds map[p2p.ID][]int     // keys: peer.ID; values: at most 2 rounds
 }
 
-func NewHeightVoteSet(chainID string, height int64, valSet *types.ValidatorSet) *HeightVoteSet {
+func NewHeightVoteSet(chainID string, domain types.SignDomain, height int64, valSet *types.ValidatorSet) *HeightVoteSet {
 	hvs := &HeightVoteSet{
 		chainID: chainID,
+		domain:  domain,
 	}
 	hvs.Reset(height, valSet)
 	return hvs
@@ -99,8 +101,8 @@ func (hvs *HeightVoteSet) addRound(round int) {
 		cmn.PanicSanity("addRound() for an existing round")
 	}
 	// log.Debug("addRound(round)", "round", round)
-	prevotes := types.NewVoteSet(hvs.chainID, hvs.height, round, types.PrevoteType, hvs.valSet)
-	precommits := types.NewVoteSet(hvs.chainID, hvs.height, round, types.PrecommitType, hvs.valSet)
+	prevotes := types.NewVoteSet(hvs.chainID, hvs.domain, hvs.height, round, types.PrevoteType, hvs.valSet)
+	precommits := types.NewVoteSet(hvs.chainID, hvs.domain, hvs.height, round, types.PrecommitType, hvs.valSet)
 	hvs.roundVoteSets[round] = RoundVoteSet{
 		Prevotes:   prevotes,
 		Precommits: precommits,
@@ -252,6 +254,70 @@ func (hvs *HeightVoteSet) toAllRoundVotes() []roundVotes {
 	return allVotes
 }
 
+// MissingVotes returns the validators whose votes are missing in the current
+// round, and how many votes of each validator are missing over the last
+// rounds rounds, including the current one.
+func (hvs *HeightVoteSet) MissingVotes(rounds int) MissingVotes {
+	hvs.mtx.Lock()
+	defer hvs.mtx.Unlock()
+
+	fromRound := hvs.round - rounds + 1
+	if fromRound < 0 {
+		fromRound = 0
+	}
+	missing := MissingVotes{
+		Rounds:     hvs.round - fromRound + 1,
+		Prevotes:   []types.Address{},
+		Precommits: []types.Address{},
+		Validators: []ValidatorMissingVotes{},
+	}
+	current := hvs.roundVoteSets[hvs.round]
+	for idx, val := range hvs.valSet.Validators {
+		if current.Prevotes.GetByIndex(idx) == nil {
+			missing.Prevotes = append(missing.Prevotes, val.Address)
+		}
+		if current.Precommits.GetByIndex(idx) == nil {
+			missing.Precommits = append(missing.Precommits, val.Address)
+		}
+
+		valMissing := ValidatorMissingVotes{Address: val.Address, Index: idx}
+		for round := fromRound; round <= hvs.round; round++ {
+			rvs := hvs.roundVoteSets[round]
+			if rvs.Prevotes.GetByIndex(idx) == nil {
+				valMissing.Prevotes++
+			}
+			if rvs.Precommits.GetByIndex(idx) == nil {
+				valMissing.Precommits++
+			}
+		}
+		if valMissing.Prevotes > 0 || valMissing.Precommits > 0 {
+			missing.Validators = append(missing.Validators, valMissing)
+		}
+	}
+	return missing
+}
+
+// MissingVotes lists the missing votes of the validators at a height.
+type MissingVotes struct {
+	// Number of rounds the Validators counts are over, up to the current one.
+	Rounds int `json:"rounds"`
+	// Validators whose prevotes and precommits are missing in the current
+	// round.
+	Prevotes   []types.Address `json:"prevotes"`
+	Precommits []types.Address `json:"precommits"`
+	// Validators missing votes in any of the rounds.
+	Validators []ValidatorMissingVotes `json:"validators"`
+}
+
+// ValidatorMissingVotes is the number of missing votes of a validator.
+type ValidatorMissingVotes struct {
+	Address    types.Address `json:"address"`
+	Index      int           `json:"index"`
+	Moniker    string        `json:"moniker,omitempty"`
+	Prevotes   int           `json:"missing_prevotes"`
+	Precommits int           `json:"missing_precommits"`
+}
+
 type roundVotes struct {
 	Round              int      `json:"round"`
 	Prevotes           []string `json:"prevotes"`
@@ -5,6 +5,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/types"
 	tmtime "github.com/tendermint/tendermint/types/time"
@@ -22,7 +25,7 @@ func TestMain(m *testing.M) {
 func TestPeerCatchupRounds(t *testing.T) {
 	valSet, privVals := types.RandValidatorSet(10, 1)
 
-	hvs := NewHeightVoteSet(config.ChainID(), 1, valSet)
+	hvs := NewHeightVoteSet(config.ChainID(), types.SignDomain{}, 1, valSet)
 
 	vote999_0 := makeVoteHR(t, 1, 999, privVals, 0)
 	added, err := hvs.AddVote(vote999_0, "peer1")
@@ -52,6 +55,38 @@ func TestPeerCatchupRounds(t *testing.T) {
 
 }
 
+func TestMissingVotes(t *testing.T) {
+	valSet, privVals := types.RandValidatorSet(4, 1)
+
+	hvs := NewHeightVoteSet(config.ChainID(), types.SignDomain{}, 1, valSet)
+	hvs.SetRound(1)
+
+	// Validator 0 precommits in both rounds, 1 in round 1 only.
+	for _, vote := range []*types.Vote{
+		makeVoteHR(t, 1, 0, privVals, 0),
+		makeVoteHR(t, 1, 1, privVals, 0),
+		makeVoteHR(t, 1, 1, privVals, 1),
+	} {
+		added, err := hvs.AddVote(vote, "peer1")
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+
+	missing := hvs.MissingVotes(10)
+	assert.Equal(t, 2, missing.Rounds)
+	assert.Len(t, missing.Prevotes, 4)
+	assert.Equal(t, []types.Address{valSet.Validators[2].Address, valSet.Validators[3].Address}, missing.Precommits)
+	require.Len(t, missing.Validators, 4)
+	assert.Equal(t, ValidatorMissingVotes{valSet.Validators[0].Address, 0, "", 2, 0}, missing.Validators[0])
+	assert.Equal(t, ValidatorMissingVotes{valSet.Validators[1].Address, 1, "", 2, 1}, missing.Validators[1])
+	assert.Equal(t, ValidatorMissingVotes{valSet.Validators[2].Address, 2, "", 2, 2}, missing.Validators[2])
+
+	// Only the current round.
+	missing = hvs.MissingVotes(1)
+	assert.Equal(t, 1, missing.Rounds)
+	assert.Equal(t, ValidatorMissingVotes{valSet.Validators[1].Address, 1, "", 1, 0}, missing.Validators[1])
+}
+
 func makeVoteHR(t *testing.T, height int64, round int, privVals []types.PrivValidator, valIndex int) *types.Vote {
 	privVal := privVals[valIndex]
 	addr := privVal.GetPubKey().Address()
@@ -65,7 +100,7 @@ func makeVoteHR(t *testing.T, height int64, round int, privVals []types.PrivVali
 		BlockID:          types.BlockID{[]byte("fakehash"), types.PartSetHeader{}},
 	}
 	chainID := config.ChainID()
-	err := privVal.SignVote(chainID, vote)
+	err := privVal.SignVote(chainID, types.SignDomain{}, vote)
 	if err != nil {
 		panic(fmt.Sprintf("Error signing vote: %v", err))
 	}
@@ -20,6 +20,8 @@ type PeerRoundState struct {
 	Proposal                 bool                `json:"proposal"`                    // True if peer has proposal for this round
 	ProposalBlockPartsHeader types.PartSetHeader `json:"proposal_block_parts_header"` //
 	ProposalBlockParts       *cmn.BitArray       `json:"proposal_block_parts"`        //
+	ProposalBlockParityParts *cmn.BitArray       `json:"proposal_block_parity_parts"` // nil until a parity part is sent or received.
+	ProposalCompactBlock     bool                `json:"proposal_compact_block"`      // True if the compact proposal block was sent to peer
 	ProposalPOLRound         int                 `json:"proposal_pol_round"`          // Proposal's POL round. -1 if none.
 	ProposalPOL              *cmn.BitArray       `json:"proposal_pol"`                // nil until ProposalPOLMessage received.
 	Prevotes                 *cmn.BitArray       `json:"prevotes"`                    // All votes peer has for this round
@@ -87,6 +87,10 @@ type RoundState struct {
 	TriggeredTimeoutPrecommit bool                `json:"triggered_timeout_precommit"`
 }
 
+// MissingVotesRounds is the number of rounds over which the missing votes of
+// RoundStateSimple are counted.
+const MissingVotesRounds = 10
+
 // Compressed version of the RoundState for use in RPC
 type RoundStateSimple struct {
 	HeightRoundStep   string          `json:"height/round/step"`
@@ -95,6 +99,18 @@ type RoundStateSimple struct {
 	LockedBlockHash   cmn.HexBytes    `json:"locked_block_hash"`
 	ValidBlockHash    cmn.HexBytes    `json:"valid_block_hash"`
 	Votes             json.RawMessage `json:"height_vote_set"`
+	MissingVotes      MissingVotes    `json:"missing_votes"`
+
+	Proposer types.ValidatorInfo `json:"proposer"`
+}
+
+// SetMonikers sets the monikers of the proposer and of the validators missing
+// votes.
+func (rss *RoundStateSimple) SetMonikers(monikers *types.ValidatorMonikers) {
+	rss.Proposer.Moniker = monikers.Moniker(rss.Proposer.Address)
+	for i, val := range rss.MissingVotes.Validators {
+		rss.MissingVotes.Validators[i].Moniker = monikers.Moniker(val.Address)
+	}
 }
 
 // Compress the RoundState to RoundStateSimple
@@ -110,22 +126,27 @@ func (rs *RoundState) RoundStateSimple() RoundStateSimple {
 		LockedBlockHash:   rs.LockedBlock.Hash(),
 		ValidBlockHash:    rs.ValidBlock.Hash(),
 		Votes:             votesJSON,
+		MissingVotes:      rs.Votes.MissingVotes(MissingVotesRounds),
+		Proposer:          rs.proposerInfo(),
 	}
 }
 
 // NewRoundEvent returns the RoundState with proposer information as an event.
 func (rs *RoundState) NewRoundEvent() types.EventDataNewRound {
+	return types.EventDataNewRound{
+		Height:   rs.Height,
+		Round:    rs.Round,
+		Step:     rs.Step.String(),
+		Proposer: rs.proposerInfo(),
+	}
+}
+
+func (rs *RoundState) proposerInfo() types.ValidatorInfo {
 	addr := rs.Validators.GetProposer().Address
 	idx, _ := rs.Validators.GetByAddress(addr)
-
-	return types.EventDataNewRound{
-		Height: rs.Height,
-		Round:  rs.Round,
-		Step:   rs.Step.String(),
-		Proposer: types.ValidatorInfo{
-			Address: addr,
-			Index:   idx,
-		},
+	return types.ValidatorInfo{
+		Address: addr,
+		Index:   idx,
 	}
 }
 
@@ -3,6 +3,9 @@ package types
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	amino "github.com/tendermint/go-amino"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -87,3 +90,25 @@ func BenchmarkRoundStateDeepCopy(b *testing.B) {
 		amino.DeepCopy(rs)
 	}
 }
+
+func TestRoundStateSimpleMonikers(t *testing.T) {
+	valSet, _ := types.RandValidatorSet(2, 1)
+	rs := &RoundState{
+		Height:     1,
+		Validators: valSet,
+		Votes:      NewHeightVoteSet(config.ChainID(), types.SignDomain{}, 1, valSet),
+	}
+	proposer := valSet.GetProposer()
+	monikers := types.NewValidatorMonikers([]types.GenesisValidator{
+		{Address: proposer.Address, PubKey: proposer.PubKey, Power: 1, Name: "alice"},
+	})
+
+	rss := rs.RoundStateSimple()
+	rss.SetMonikers(monikers)
+	assert.Equal(t, proposer.Address, rss.Proposer.Address)
+	assert.Equal(t, "alice", rss.Proposer.Moniker)
+	require.Len(t, rss.MissingVotes.Validators, 2)
+	for _, val := range rss.MissingVotes.Validators {
+		assert.Equal(t, monikers.Moniker(val.Address), val.Moniker)
+	}
+}
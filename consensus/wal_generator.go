@@ -48,6 +48,9 @@ func WALGenerateNBlocks(t *testing.T, wr io.Writer, numBlocks int) (err error) {
 	if err != nil {
 		return errors.Wrap(err, "failed to read genesis file")
 	}
+	if err := genDoc.UseMerkleHasher(); err != nil {
+		return errors.Wrap(err, "failed to set the merkle hasher")
+	}
 	stateDB := db.NewMemDB()
 	blockStoreDB := db.NewMemDB()
 	state, err := sm.MakeGenesisState(genDoc)
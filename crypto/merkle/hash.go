@@ -1,21 +1,17 @@
 package merkle
 
-import (
-	"github.com/tendermint/tendermint/crypto/tmhash"
-)
-
 // TODO: make these have a large predefined capacity
 var (
 	leafPrefix  = []byte{0}
 	innerPrefix = []byte{1}
 )
 
-// returns tmhash(0x00 || leaf)
-func leafHash(leaf []byte) []byte {
-	return tmhash.Sum(append(leafPrefix, leaf...))
+// returns hash(0x00 || leaf), with the Hasher h
+func leafHash(h Hasher, leaf []byte) []byte {
+	return h.Sum(append(leafPrefix, leaf...))
 }
 
-// returns tmhash(0x01 || left || right)
-func innerHash(left []byte, right []byte) []byte {
-	return tmhash.Sum(append(innerPrefix, append(left, right...)...))
+// returns hash(0x01 || left || right), with the Hasher h
+func innerHash(h Hasher, left []byte, right []byte) []byte {
+	return h.Sum(append(innerPrefix, append(left, right...)...))
 }
@@ -0,0 +1,99 @@
+package merkle
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+const (
+	// HasherSHA256 is the name of the default Hasher, SHA256.
+	HasherSHA256 = "sha256"
+	// HasherBlake2b is the name of the BLAKE2b-256 Hasher.
+	HasherBlake2b = "blake2b"
+)
+
+// Hasher is the hash function of the leaves and inner nodes of the simple
+// Merkle trees. The hashes are 32 bytes long, as the ones of SHA256, which the
+// block IDs expect. The simple maps and the SimpleValueOps, proving the values
+// of the apps, always use SHA256.
+type Hasher interface {
+	Name() string
+	Sum(bz []byte) []byte
+}
+
+type sha256Hasher struct{}
+
+func (sha256Hasher) Name() string { return HasherSHA256 }
+
+func (sha256Hasher) Sum(bz []byte) []byte { return tmhash.Sum(bz) }
+
+type blake2bHasher struct{}
+
+func (blake2bHasher) Name() string { return HasherBlake2b }
+
+func (blake2bHasher) Sum(bz []byte) []byte {
+	h := blake2b.Sum256(bz)
+	return h[:]
+}
+
+// HasherByName returns the Hasher of the name, the SHA256 one if it is empty.
+func HasherByName(name string) (Hasher, error) {
+	switch name {
+	case "", HasherSHA256:
+		return sha256Hasher{}, nil
+	case HasherBlake2b:
+		return blake2bHasher{}, nil
+	default:
+		return nil, fmt.Errorf("unknown merkle hasher %q", name)
+	}
+}
+
+// hasher holds the hasherValue of the trees.
+var hasher atomic.Value
+
+// hasherValue wraps the Hasher, as an atomic.Value holds values of a single
+// concrete type.
+type hasherValue struct {
+	Hasher
+}
+
+func init() {
+	hasher.Store(hasherValue{sha256Hasher{}})
+}
+
+var (
+	usedHasherMtx sync.Mutex
+	usedHasher    string // name of the Hasher of UseHasher, if called
+)
+
+// SetHasher sets the Hasher of the trees. It must be set before any tree is
+// hashed, as the hashes of the blocks and the proofs depend on it. See
+// UseHasher to set the one of the genesis file of a chain.
+func SetHasher(h Hasher) {
+	hasher.Store(hasherValue{h})
+}
+
+// UseHasher sets the Hasher of the trees to the one of the genesis file of a
+// chain. The Hasher is the one of the whole process: all the chains of the
+// nodes of a process must use the same one, and UseHasher returns an error if
+// another one is in use already.
+func UseHasher(h Hasher) error {
+	usedHasherMtx.Lock()
+	defer usedHasherMtx.Unlock()
+	if usedHasher != "" && usedHasher != h.Name() {
+		return fmt.Errorf("merkle hasher %q is in use by the process, cannot use %q", usedHasher, h.Name())
+	}
+	usedHasher = h.Name()
+	SetHasher(h)
+	return nil
+}
+
+// GetHasher returns the Hasher of the trees, SHA256 unless set otherwise.
+func GetHasher() Hasher {
+	return hasher.Load().(hasherValue).Hasher
+}
@@ -0,0 +1,80 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasherByName(t *testing.T) {
+	testCases := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"", HasherSHA256, true},
+		{HasherSHA256, HasherSHA256, true},
+		{HasherBlake2b, HasherBlake2b, true},
+		{"md5", "", false},
+	}
+	for _, tc := range testCases {
+		h, err := HasherByName(tc.name)
+		if !tc.ok {
+			assert.Error(t, err, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.want, h.Name())
+		assert.Len(t, h.Sum([]byte("abc")), 32)
+	}
+}
+
+func TestSimpleHashBlake2b(t *testing.T) {
+	items := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	sha256Root := SimpleHashFromByteSlices(items)
+
+	SetHasher(blake2bHasher{})
+	defer SetHasher(sha256Hasher{})
+	assert.Equal(t, HasherBlake2b, GetHasher().Name())
+
+	assert.Equal(t, "03170a2e7597b7b7e3d84c05391d139a62b157e78786d8c082f29dcf4c111314",
+		hex.EncodeToString(leafHash(GetHasher(), nil)))
+	root, proofs := SimpleProofsFromByteSlices(items)
+	assert.Equal(t, "17321db51c1ef3ec1f77e271aa300b4e5c6091708bcba37e46025774a26142ee",
+		hex.EncodeToString(root))
+	assert.NotEqual(t, sha256Root, root)
+	for i, item := range items {
+		assert.NoError(t, proofs[i].Verify(root, item))
+		assert.Error(t, proofs[i].Verify(sha256Root, item))
+	}
+
+	// The maps and the values proven to the apps stay on SHA256.
+	m := map[string][]byte{"key1": []byte("value1"), "key2": []byte("value2")}
+	root, mapProofs, _ := SimpleProofsFromMap(m)
+	assert.Equal(t, root, SimpleHashFromMap(m))
+	op := NewSimpleValueOp([]byte("key1"), mapProofs["key1"])
+	res, err := op.Run([][]byte{[]byte("value1")})
+	require.NoError(t, err)
+	assert.Equal(t, root, res[0])
+
+	SetHasher(sha256Hasher{})
+	sha256MapRoot, _, _ := SimpleProofsFromMap(m)
+	assert.Equal(t, sha256MapRoot, root)
+	res, err = op.Run([][]byte{[]byte("value1")})
+	require.NoError(t, err)
+	assert.Equal(t, root, res[0])
+}
+
+func TestUseHasher(t *testing.T) {
+	defer func() {
+		usedHasher = ""
+		SetHasher(sha256Hasher{})
+	}()
+
+	require.NoError(t, UseHasher(sha256Hasher{}))
+	require.NoError(t, UseHasher(sha256Hasher{}))
+	assert.Error(t, UseHasher(blake2bHasher{}))
+	assert.Equal(t, HasherSHA256, GetHasher().Name())
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 
-	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmn "github.com/tendermint/tendermint/libs/common"
 )
 
@@ -67,22 +66,22 @@ func (op SimpleValueOp) Run(args [][]byte) ([][]byte, error) {
 		return nil, cmn.NewError("expected 1 arg, got %v", len(args))
 	}
 	value := args[0]
-	hasher := tmhash.New()
-	hasher.Write(value) // does not error
-	vhash := hasher.Sum(nil)
+	// The values of the apps are proven with SHA256, whatever the Hasher of
+	// the trees of the chain.
+	vhash := sha256Hasher{}.Sum(value)
 
 	bz := new(bytes.Buffer)
 	// Wrap <op.Key, vhash> to hash the KVPair.
 	encodeByteSlice(bz, []byte(op.key)) // does not error
 	encodeByteSlice(bz, []byte(vhash))  // does not error
-	kvhash := leafHash(bz.Bytes())
+	kvhash := leafHash(sha256Hasher{}, bz.Bytes())
 
 	if !bytes.Equal(kvhash, op.Proof.LeafHash) {
 		return nil, cmn.NewError("leaf hash mismatch: want %X got %X", op.Proof.LeafHash, kvhash)
 	}
 
 	return [][]byte{
-		op.Proof.ComputeRootHash(),
+		op.Proof.computeRootHash(sha256Hasher{}),
 	}, nil
 }
 
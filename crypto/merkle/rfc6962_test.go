@@ -24,9 +24,9 @@ import (
 )
 
 func TestRFC6962Hasher(t *testing.T) {
-	_, leafHashTrail := trailsFromByteSlices([][]byte{[]byte("L123456")})
+	_, leafHashTrail := trailsFromByteSlices(sha256Hasher{}, [][]byte{[]byte("L123456")})
 	leafHash := leafHashTrail.Hash
-	_, leafHashTrail = trailsFromByteSlices([][]byte{{}})
+	_, leafHashTrail = trailsFromByteSlices(sha256Hasher{}, [][]byte{{}})
 	emptyLeafHash := leafHashTrail.Hash
 	for _, tc := range []struct {
 		desc string
@@ -53,7 +53,7 @@ func TestRFC6962Hasher(t *testing.T) {
 		{
 			desc: "RFC6962 Node",
 			want: "aa217fe888e47007fa15edab33c2b492a722cb106c64667fc2b044444de66bbb"[:tmhash.Size*2],
-			got:  innerHash([]byte("N123"), []byte("N456")),
+			got:  innerHash(sha256Hasher{}, []byte("N123"), []byte("N456")),
 		},
 	} {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -71,25 +71,25 @@ func TestRFC6962Hasher(t *testing.T) {
 func TestRFC6962HasherCollisions(t *testing.T) {
 	// Check that different leaves have different hashes.
 	leaf1, leaf2 := []byte("Hello"), []byte("World")
-	_, leafHashTrail := trailsFromByteSlices([][]byte{leaf1})
+	_, leafHashTrail := trailsFromByteSlices(sha256Hasher{}, [][]byte{leaf1})
 	hash1 := leafHashTrail.Hash
-	_, leafHashTrail = trailsFromByteSlices([][]byte{leaf2})
+	_, leafHashTrail = trailsFromByteSlices(sha256Hasher{}, [][]byte{leaf2})
 	hash2 := leafHashTrail.Hash
 	if bytes.Equal(hash1, hash2) {
 		t.Errorf("Leaf hashes should differ, but both are %x", hash1)
 	}
 	// Compute an intermediate subtree hash.
-	_, subHash1Trail := trailsFromByteSlices([][]byte{hash1, hash2})
+	_, subHash1Trail := trailsFromByteSlices(sha256Hasher{}, [][]byte{hash1, hash2})
 	subHash1 := subHash1Trail.Hash
 	// Check that this is not the same as a leaf hash of their concatenation.
 	preimage := append(hash1, hash2...)
-	_, forgedHashTrail := trailsFromByteSlices([][]byte{preimage})
+	_, forgedHashTrail := trailsFromByteSlices(sha256Hasher{}, [][]byte{preimage})
 	forgedHash := forgedHashTrail.Hash
 	if bytes.Equal(subHash1, forgedHash) {
 		t.Errorf("Hasher is not second-preimage resistant")
 	}
 	// Swap the order of nodes and check that the hash is different.
-	_, subHash2Trail := trailsFromByteSlices([][]byte{hash2, hash1})
+	_, subHash2Trail := trailsFromByteSlices(sha256Hasher{}, [][]byte{hash2, hash1})
 	subHash2 := subHash2Trail.Hash
 	if bytes.Equal(subHash1, subHash2) {
 		t.Errorf("Subtree hash does not depend on the order of leaves")
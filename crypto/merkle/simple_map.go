@@ -4,7 +4,6 @@ import (
 	"bytes"
 
 	amino "github.com/tendermint/go-amino"
-	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmn "github.com/tendermint/tendermint/libs/common"
 )
 
@@ -31,7 +30,7 @@ func (sm *simpleMap) Set(key string, value []byte) {
 	// The value is hashed, so you can
 	// check for equality with a cached value (say)
 	// and make a determination to fetch or not.
-	vhash := tmhash.Sum(value)
+	vhash := sha256Hasher{}.Sum(value)
 
 	sm.kvs = append(sm.kvs, cmn.KVPair{
 		Key:   []byte(key),
@@ -90,5 +89,5 @@ func hashKVPairs(kvs cmn.KVPairs) []byte {
 	for i, kvp := range kvs {
 		kvsH[i] = KVPair(kvp).Bytes()
 	}
-	return SimpleHashFromByteSlices(kvsH)
+	return simpleHashFromByteSlices(sha256Hasher{}, kvsH)
 }
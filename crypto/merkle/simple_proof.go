@@ -25,7 +25,11 @@ type SimpleProof struct {
 // SimpleProofsFromByteSlices computes inclusion proof for given items.
 // proofs[0] is the proof for items[0].
 func SimpleProofsFromByteSlices(items [][]byte) (rootHash []byte, proofs []*SimpleProof) {
-	trails, rootSPN := trailsFromByteSlices(items)
+	return simpleProofsFromByteSlices(GetHasher(), items)
+}
+
+func simpleProofsFromByteSlices(h Hasher, items [][]byte) (rootHash []byte, proofs []*SimpleProof) {
+	trails, rootSPN := trailsFromByteSlices(h, items)
 	rootHash = rootSPN.Hash
 	proofs = make([]*SimpleProof, len(items))
 	for i, trail := range trails {
@@ -42,6 +46,8 @@ func SimpleProofsFromByteSlices(items [][]byte) (rootHash []byte, proofs []*Simp
 // SimpleProofsFromMap generates proofs from a map. The keys/values of the map will be used as the keys/values
 // in the underlying key-value pairs.
 // The keys are sorted before the proofs are computed.
+// As the ones of SimpleHashFromMap, they are computed with SHA256, see
+// SimpleValueOp.
 func SimpleProofsFromMap(m map[string][]byte) (rootHash []byte, proofs map[string]*SimpleProof, keys []string) {
 	sm := newSimpleMap()
 	for k, v := range m {
@@ -54,7 +60,7 @@ func SimpleProofsFromMap(m map[string][]byte) (rootHash []byte, proofs map[strin
 		kvsBytes[i] = KVPair(kvp).Bytes()
 	}
 
-	rootHash, proofList := SimpleProofsFromByteSlices(kvsBytes)
+	rootHash, proofList := simpleProofsFromByteSlices(sha256Hasher{}, kvsBytes)
 	proofs = make(map[string]*SimpleProof)
 	keys = make([]string, len(proofList))
 	for i, kvp := range kvs {
@@ -67,7 +73,7 @@ func SimpleProofsFromMap(m map[string][]byte) (rootHash []byte, proofs map[strin
 // Verify that the SimpleProof proves the root hash.
 // Check sp.Index/sp.Total manually if needed
 func (sp *SimpleProof) Verify(rootHash []byte, leaf []byte) error {
-	leafHash := leafHash(leaf)
+	leafHash := leafHash(GetHasher(), leaf)
 	if sp.Total < 0 {
 		return errors.New("Proof total must be positive")
 	}
@@ -77,7 +83,7 @@ func (sp *SimpleProof) Verify(rootHash []byte, leaf []byte) error {
 	if !bytes.Equal(sp.LeafHash, leafHash) {
 		return cmn.NewError("invalid leaf hash: wanted %X got %X", leafHash, sp.LeafHash)
 	}
-	computedHash := sp.ComputeRootHash()
+	computedHash := sp.computeRootHash(GetHasher())
 	if !bytes.Equal(computedHash, rootHash) {
 		return cmn.NewError("invalid root hash: wanted %X got %X", rootHash, computedHash)
 	}
@@ -86,7 +92,12 @@ func (sp *SimpleProof) Verify(rootHash []byte, leaf []byte) error {
 
 // Compute the root hash given a leaf hash.  Does not verify the result.
 func (sp *SimpleProof) ComputeRootHash() []byte {
+	return sp.computeRootHash(GetHasher())
+}
+
+func (sp *SimpleProof) computeRootHash(h Hasher) []byte {
 	return computeHashFromAunts(
+		h,
 		sp.Index,
 		sp.Total,
 		sp.LeafHash,
@@ -112,7 +123,7 @@ func (sp *SimpleProof) StringIndented(indent string) string {
 // Use the leafHash and innerHashes to get the root merkle hash.
 // If the length of the innerHashes slice isn't exactly correct, the result is nil.
 // Recursive impl.
-func computeHashFromAunts(index int, total int, leafHash []byte, innerHashes [][]byte) []byte {
+func computeHashFromAunts(h Hasher, index int, total int, leafHash []byte, innerHashes [][]byte) []byte {
 	if index >= total || index < 0 || total <= 0 {
 		return nil
 	}
@@ -130,17 +141,17 @@ func computeHashFromAunts(index int, total int, leafHash []byte, innerHashes [][
 		}
 		numLeft := getSplitPoint(total)
 		if index < numLeft {
-			leftHash := computeHashFromAunts(index, numLeft, leafHash, innerHashes[:len(innerHashes)-1])
+			leftHash := computeHashFromAunts(h, index, numLeft, leafHash, innerHashes[:len(innerHashes)-1])
 			if leftHash == nil {
 				return nil
 			}
-			return innerHash(leftHash, innerHashes[len(innerHashes)-1])
+			return innerHash(h, leftHash, innerHashes[len(innerHashes)-1])
 		}
-		rightHash := computeHashFromAunts(index-numLeft, total-numLeft, leafHash, innerHashes[:len(innerHashes)-1])
+		rightHash := computeHashFromAunts(h, index-numLeft, total-numLeft, leafHash, innerHashes[:len(innerHashes)-1])
 		if rightHash == nil {
 			return nil
 		}
-		return innerHash(innerHashes[len(innerHashes)-1], rightHash)
+		return innerHash(h, innerHashes[len(innerHashes)-1], rightHash)
 	}
 }
 
@@ -176,19 +187,19 @@ func (spn *SimpleProofNode) FlattenAunts() [][]byte {
 
 // trails[0].Hash is the leaf hash for items[0].
 // trails[i].Parent.Parent....Parent == root for all i.
-func trailsFromByteSlices(items [][]byte) (trails []*SimpleProofNode, root *SimpleProofNode) {
+func trailsFromByteSlices(h Hasher, items [][]byte) (trails []*SimpleProofNode, root *SimpleProofNode) {
 	// Recursive impl.
 	switch len(items) {
 	case 0:
 		return nil, nil
 	case 1:
-		trail := &SimpleProofNode{leafHash(items[0]), nil, nil, nil}
+		trail := &SimpleProofNode{leafHash(h, items[0]), nil, nil, nil}
 		return []*SimpleProofNode{trail}, trail
 	default:
 		k := getSplitPoint(len(items))
-		lefts, leftRoot := trailsFromByteSlices(items[:k])
-		rights, rightRoot := trailsFromByteSlices(items[k:])
-		rootHash := innerHash(leftRoot.Hash, rightRoot.Hash)
+		lefts, leftRoot := trailsFromByteSlices(h, items[:k])
+		rights, rightRoot := trailsFromByteSlices(h, items[k:])
+		rootHash := innerHash(h, leftRoot.Hash, rightRoot.Hash)
 		root := &SimpleProofNode{rootHash, nil, nil, nil}
 		leftRoot.Parent = root
 		leftRoot.Right = rightRoot
@@ -7,16 +7,20 @@ import (
 // SimpleHashFromByteSlices computes a Merkle tree where the leaves are the byte slice,
 // in the provided order.
 func SimpleHashFromByteSlices(items [][]byte) []byte {
+	return simpleHashFromByteSlices(GetHasher(), items)
+}
+
+func simpleHashFromByteSlices(h Hasher, items [][]byte) []byte {
 	switch len(items) {
 	case 0:
 		return nil
 	case 1:
-		return leafHash(items[0])
+		return leafHash(h, items[0])
 	default:
 		k := getSplitPoint(len(items))
-		left := SimpleHashFromByteSlices(items[:k])
-		right := SimpleHashFromByteSlices(items[k:])
-		return innerHash(left, right)
+		left := simpleHashFromByteSlices(h, items[:k])
+		right := simpleHashFromByteSlices(h, items[k:])
+		return innerHash(h, left, right)
 	}
 }
 
@@ -24,6 +28,8 @@ func SimpleHashFromByteSlices(items [][]byte) []byte {
 // Like calling SimpleHashFromHashers with
 // `item = []byte(Hash(key) | Hash(value))`,
 // sorted by `item`.
+// The maps hold the values proven to the apps, so they are hashed with SHA256
+// whatever the Hasher of the trees.
 func SimpleHashFromMap(m map[string][]byte) []byte {
 	sm := newSimpleMap()
 	for k, v := range m {
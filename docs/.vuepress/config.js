@@ -58,6 +58,8 @@ module.exports = {
           "/tendermint-core/rpc",
           "/tendermint-core/running-in-production",
           "/tendermint-core/fast-sync",
+          "/tendermint-core/block-archives",
+          "/tendermint-core/state-export",
           "/tendermint-core/how-to-read-logs",
           "/tendermint-core/block-structure",
           "/tendermint-core/light-client-protocol",
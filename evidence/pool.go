@@ -123,7 +123,7 @@ func (evpool *EvidencePool) MarkEvidenceAsCommitted(height int64, evidence []typ
 	// make a map of committed evidence to remove from the clist
 	blockEvidenceMap := make(map[string]struct{})
 	for _, ev := range evidence {
-		evpool.evidenceStore.MarkEvidenceAsCommitted(ev)
+		evpool.evidenceStore.MarkEvidenceAsCommitted(height, ev)
 		blockEvidenceMap[evMapKey(ev)] = struct{}{}
 	}
 
@@ -133,6 +133,17 @@ func (evpool *EvidencePool) MarkEvidenceAsCommitted(height int64, evidence []typ
 
 }
 
+// CommittedEvidence returns the evidence committed in the block at the height.
+func (evpool *EvidencePool) CommittedEvidence(height int64) []EvidenceInfo {
+	return evpool.evidenceStore.CommittedEvidence(height)
+}
+
+// SearchCommittedEvidence returns the evidence committed against the
+// validator with the address, by increasing height of the blocks.
+func (evpool *EvidencePool) SearchCommittedEvidence(address []byte) []EvidenceInfo {
+	return evpool.evidenceStore.SearchCommittedEvidence(address)
+}
+
 // IsCommitted returns true if we have already seen this exact evidence and it is already marked as committed.
 func (evpool *EvidencePool) IsCommitted(evidence types.Evidence) bool {
 	ei := evpool.evidenceStore.getEvidenceInfo(evidence)
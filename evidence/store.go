@@ -16,25 +16,33 @@ Requirements:
 Impl:
 	- First commit atomically in outqueue, pending, lookup.
 	- Once broadcast, remove from outqueue. No need to sync
-	- Once committed, atomically remove from pending and update lookup.
+	- Once committed, atomically remove from pending and update lookup, and
+	  index it by the height of the block and the address of the validator.
 
 Schema for indexing evidence (note you need both height and hash to find a piece of evidence):
 
 "evidence-lookup"/<evidence-height>/<evidence-hash> -> EvidenceInfo
 "evidence-outqueue"/<priority>/<evidence-height>/<evidence-hash> -> EvidenceInfo
 "evidence-pending"/<evidence-height>/<evidence-hash> -> EvidenceInfo
+"evidence-committed"/<block-height>/<evidence-height>/<evidence-hash> -> lookup key
+"evidence-address"/<address>/<block-height>/<evidence-height>/<evidence-hash> -> lookup key
 */
 
 type EvidenceInfo struct {
 	Committed bool
 	Priority  int64
 	Evidence  types.Evidence
+
+	// the height of the block the evidence was committed in, if committed
+	CommittedHeight int64
 }
 
 const (
-	baseKeyLookup   = "evidence-lookup"   // all evidence
-	baseKeyOutqueue = "evidence-outqueue" // not-yet broadcast
-	baseKeyPending  = "evidence-pending"  // broadcast but not committed
+	baseKeyLookup    = "evidence-lookup"    // all evidence
+	baseKeyOutqueue  = "evidence-outqueue"  // not-yet broadcast
+	baseKeyPending   = "evidence-pending"   // broadcast but not committed
+	baseKeyCommitted = "evidence-committed" // committed, by block height
+	baseKeyAddress   = "evidence-address"   // committed, by validator address
 )
 
 func keyLookup(evidence types.Evidence) []byte {
@@ -58,6 +66,14 @@ func keyPending(evidence types.Evidence) []byte {
 	return _key("%s/%s/%X", baseKeyPending, bE(evidence.Height()), evidence.Hash())
 }
 
+func keyCommitted(height int64, evidence types.Evidence) []byte {
+	return _key("%s/%s/%s/%X", baseKeyCommitted, bE(height), bE(evidence.Height()), evidence.Hash())
+}
+
+func keyAddress(height int64, evidence types.Evidence) []byte {
+	return _key("%s/%X/%s/%s/%X", baseKeyAddress, evidence.Address(), bE(height), bE(evidence.Height()), evidence.Hash())
+}
+
 func _key(fmt_ string, o ...interface{}) []byte {
 	return []byte(fmt.Sprintf(fmt_, o...))
 }
@@ -117,6 +133,37 @@ func (store *EvidenceStore) listEvidence(prefixKey string, maxNum int64) (eviden
 	return evidence
 }
 
+// CommittedEvidence returns the evidence committed in the block at the height.
+func (store *EvidenceStore) CommittedEvidence(height int64) []EvidenceInfo {
+	return store.listIndexedEvidence(_key("%s/%s/", baseKeyCommitted, bE(height)))
+}
+
+// SearchCommittedEvidence returns the evidence committed against the
+// validator with the address, by increasing height of the blocks.
+func (store *EvidenceStore) SearchCommittedEvidence(address []byte) []EvidenceInfo {
+	return store.listIndexedEvidence(_key("%s/%X/", baseKeyAddress, address))
+}
+
+// listIndexedEvidence lists the evidence referenced by the lookup keys for the
+// given prefix key.
+func (store *EvidenceStore) listIndexedEvidence(prefixKey []byte) (eis []EvidenceInfo) {
+	iter := dbm.IteratePrefix(store.db, prefixKey)
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		val := store.db.Get(iter.Value())
+		if len(val) == 0 {
+			panic(fmt.Sprintf("indexed evidence %s not found", iter.Key()))
+		}
+		var ei EvidenceInfo
+		err := cdc.UnmarshalBinaryBare(val, &ei)
+		if err != nil {
+			panic(err)
+		}
+		eis = append(eis, ei)
+	}
+	return eis
+}
+
 // GetEvidenceInfo fetches the EvidenceInfo with the given height and hash.
 // If not found, ei.Evidence is nil.
 func (store *EvidenceStore) GetEvidenceInfo(height int64, hash []byte) EvidenceInfo {
@@ -175,8 +222,9 @@ func (store *EvidenceStore) MarkEvidenceAsBroadcasted(evidence types.Evidence) {
 	store.db.Delete(key)
 }
 
-// MarkEvidenceAsCommitted removes evidence from pending and outqueue and sets the state to committed.
-func (store *EvidenceStore) MarkEvidenceAsCommitted(evidence types.Evidence) {
+// MarkEvidenceAsCommitted removes evidence from pending and outqueue and sets the state to committed
+// in the block at the height.
+func (store *EvidenceStore) MarkEvidenceAsCommitted(height int64, evidence types.Evidence) {
 	// if its committed, its been broadcast
 	store.MarkEvidenceAsBroadcasted(evidence)
 
@@ -185,12 +233,15 @@ func (store *EvidenceStore) MarkEvidenceAsCommitted(evidence types.Evidence) {
 
 	// committed EvidenceInfo doens't need priority
 	ei := EvidenceInfo{
-		Committed: true,
-		Evidence:  evidence,
-		Priority:  0,
+		Committed:       true,
+		Evidence:        evidence,
+		Priority:        0,
+		CommittedHeight: height,
 	}
 
 	lookupKey := keyLookup(evidence)
+	store.db.Set(keyCommitted(height, evidence), lookupKey)
+	store.db.Set(keyAddress(height, evidence), lookupKey)
 	store.db.SetSync(lookupKey, cdc.MustMarshalBinaryBare(ei))
 }
 
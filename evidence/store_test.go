@@ -36,7 +36,7 @@ func TestStoreCommitDuplicate(t *testing.T) {
 	priority := int64(10)
 	ev := types.NewMockGoodEvidence(2, 1, []byte("val1"))
 
-	store.MarkEvidenceAsCommitted(ev)
+	store.MarkEvidenceAsCommitted(3, ev)
 
 	added := store.AddNewEvidence(ev, priority)
 	assert.False(added)
@@ -80,7 +80,7 @@ func TestStoreMark(t *testing.T) {
 	assert.Equal(1, len(pendingEv))
 
 	// priority and pending are now empty
-	store.MarkEvidenceAsCommitted(ev)
+	store.MarkEvidenceAsCommitted(3, ev)
 	priorityEv = store.PriorityEvidence()
 	pendingEv = store.PendingEvidence(-1)
 	assert.Equal(0, len(priorityEv))
@@ -123,3 +123,35 @@ func TestStorePriority(t *testing.T) {
 		assert.Equal(ev, cases[i].ev)
 	}
 }
+
+func TestStoreCommittedEvidence(t *testing.T) {
+	assert := assert.New(t)
+
+	db := dbm.NewMemDB()
+	store := NewEvidenceStore(db)
+
+	ev1 := types.NewMockGoodEvidence(2, 1, []byte("val1"))
+	ev2 := types.NewMockGoodEvidence(3, 2, []byte("val2"))
+	ev3 := types.NewMockGoodEvidence(4, 1, []byte("val1"))
+	store.AddNewEvidence(ev1, 10)
+	store.MarkEvidenceAsCommitted(5, ev1)
+	store.MarkEvidenceAsCommitted(5, ev2)
+	store.MarkEvidenceAsCommitted(6, ev3)
+
+	eis := store.CommittedEvidence(5)
+	if assert.Equal(2, len(eis)) {
+		assert.Equal(ev1, eis[0].Evidence)
+		assert.Equal(ev2, eis[1].Evidence)
+		assert.EqualValues(5, eis[1].CommittedHeight)
+	}
+	assert.Empty(store.CommittedEvidence(4))
+
+	eis = store.SearchCommittedEvidence([]byte("val1"))
+	if assert.Equal(2, len(eis)) {
+		assert.Equal(ev1, eis[0].Evidence)
+		assert.EqualValues(5, eis[0].CommittedHeight)
+		assert.Equal(ev3, eis[1].Evidence)
+		assert.EqualValues(6, eis[1].CommittedHeight)
+	}
+	assert.Empty(store.SearchCommittedEvidence([]byte("val3")))
+}
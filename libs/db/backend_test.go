@@ -156,6 +156,17 @@ func TestGoLevelDBBackend(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestNewDBWithCacheSize(t *testing.T) {
+	for _, dbType := range []DBBackendType{GoLevelDBBackend, MemDBBackend} {
+		name := fmt.Sprintf("test_%x", cmn.RandStr(12))
+		db := NewDBWithCacheSize(name, dbType, "", 1<<20)
+		db.Set([]byte("key"), []byte("value"))
+		assert.Equal(t, []byte("value"), db.Get([]byte("key")), dbType)
+		db.Close()
+		cleanupDBDir("", name)
+	}
+}
+
 func TestDBIterator(t *testing.T) {
 	for dbType := range backends {
 		t.Run(fmt.Sprintf("%v", dbType), func(t *testing.T) {
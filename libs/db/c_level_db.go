@@ -16,6 +16,12 @@ func init() {
 	}
 	registerDBCreator(LevelDBBackend, dbCreator, true)
 	registerDBCreator(CLevelDBBackend, dbCreator, false)
+
+	dbCreatorWithCacheSize := func(name string, dir string, cacheSize int) (DB, error) {
+		return NewCLevelDBWithCacheSize(name, dir, cacheSize)
+	}
+	registerDBCreatorWithCacheSize(LevelDBBackend, dbCreatorWithCacheSize, true)
+	registerDBCreatorWithCacheSize(CLevelDBBackend, dbCreatorWithCacheSize, false)
 }
 
 var _ DB = (*CLevelDB)(nil)
@@ -28,10 +34,16 @@ type CLevelDB struct {
 }
 
 func NewCLevelDB(name string, dir string) (*CLevelDB, error) {
+	return NewCLevelDBWithCacheSize(name, dir, 1<<30)
+}
+
+// NewCLevelDBWithCacheSize returns a CLevelDB with a block cache of cacheSize
+// bytes.
+func NewCLevelDBWithCacheSize(name string, dir string, cacheSize int) (*CLevelDB, error) {
 	dbPath := filepath.Join(dir, name+".db")
 
 	opts := levigo.NewOptions()
-	opts.SetCache(levigo.NewLRUCache(1 << 30))
+	opts.SetCache(levigo.NewLRUCache(cacheSize))
 	opts.SetCreateIfMissing(true)
 	db, err := levigo.Open(dbPath, opts)
 	if err != nil {
@@ -194,6 +206,33 @@ func (db *CLevelDB) ReverseIterator(start, end []byte) Iterator {
 	return newCLevelDBIterator(itr, start, end, true)
 }
 
+var _ Snapshotter = (*CLevelDB)(nil)
+
+// Implements Snapshotter.
+func (db *CLevelDB) Snapshot() (Snapshot, error) {
+	snap := db.db.NewSnapshot()
+	ro := levigo.NewReadOptions()
+	ro.SetSnapshot(snap)
+	return cLevelDBSnapshot{db.db, snap, ro}, nil
+}
+
+type cLevelDBSnapshot struct {
+	db   *levigo.DB
+	snap *levigo.Snapshot
+	ro   *levigo.ReadOptions
+}
+
+// Implements Snapshot.
+func (s cLevelDBSnapshot) Iterator() Iterator {
+	return newCLevelDBIterator(s.db.NewIterator(s.ro), nil, nil, false)
+}
+
+// Implements Snapshot.
+func (s cLevelDBSnapshot) Release() {
+	s.ro.Close()
+	s.db.ReleaseSnapshot(s.snap)
+}
+
 var _ Iterator = (*cLevelDBIterator)(nil)
 
 type cLevelDBIterator struct {
@@ -2,6 +2,7 @@ package db
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -30,6 +31,29 @@ func registerDBCreator(backend DBBackendType, creator dbCreator, force bool) {
 	backends[backend] = creator
 }
 
+type dbCreatorWithCacheSize func(name string, dir string, cacheSize int) (DB, error)
+
+// the backends with a block cache.
+var cacheSizeBackends = map[DBBackendType]dbCreatorWithCacheSize{}
+
+func registerDBCreatorWithCacheSize(backend DBBackendType, creator dbCreatorWithCacheSize, force bool) {
+	_, ok := cacheSizeBackends[backend]
+	if !force && ok {
+		return
+	}
+	cacheSizeBackends[backend] = creator
+}
+
+// Backends returns the registered db backends, in alphabetical order.
+func Backends() []DBBackendType {
+	backendTypes := make([]DBBackendType, 0, len(backends))
+	for backend := range backends {
+		backendTypes = append(backendTypes, backend)
+	}
+	sort.Slice(backendTypes, func(i, j int) bool { return backendTypes[i] < backendTypes[j] })
+	return backendTypes
+}
+
 // NewDB creates a new database of type backend with the given name.
 // NOTE: function panics if:
 //   - backend is unknown (not registered)
@@ -52,3 +76,19 @@ func NewDB(name string, backend DBBackendType, dir string) DB {
 	}
 	return db
 }
+
+// NewDBWithCacheSize creates a new database of type backend with the given
+// name, with a block cache of cacheSize bytes if the backend has one.
+// NOTE: function panics like NewDB.
+func NewDBWithCacheSize(name string, backend DBBackendType, dir string, cacheSize int) DB {
+	dbCreator, ok := cacheSizeBackends[backend]
+	if !ok {
+		return NewDB(name, backend, dir)
+	}
+
+	db, err := dbCreator(name, dir, cacheSize)
+	if err != nil {
+		panic(fmt.Sprintf("Error initializing DB: %v", err))
+	}
+	return db
+}
@@ -19,6 +19,12 @@ func init() {
 	}
 	registerDBCreator(LevelDBBackend, dbCreator, false)
 	registerDBCreator(GoLevelDBBackend, dbCreator, false)
+
+	dbCreatorWithCacheSize := func(name string, dir string, cacheSize int) (DB, error) {
+		return NewGoLevelDBWithOpts(name, dir, &opt.Options{BlockCacheCapacity: cacheSize})
+	}
+	registerDBCreatorWithCacheSize(LevelDBBackend, dbCreatorWithCacheSize, false)
+	registerDBCreatorWithCacheSize(GoLevelDBBackend, dbCreatorWithCacheSize, false)
 }
 
 var _ DB = (*GoLevelDB)(nil)
@@ -108,6 +114,12 @@ func (db *GoLevelDB) Close() {
 	db.db.Close()
 }
 
+var _ errCloser = (*GoLevelDB)(nil)
+
+func (db *GoLevelDB) closeErr() error {
+	return db.db.Close()
+}
+
 // Implements DB.
 func (db *GoLevelDB) Print() {
 	str, _ := db.db.GetProperty("leveldb.stats")
@@ -201,6 +213,31 @@ func (db *GoLevelDB) ReverseIterator(start, end []byte) Iterator {
 	return newGoLevelDBIterator(itr, start, end, true)
 }
 
+var _ Snapshotter = (*GoLevelDB)(nil)
+
+// Implements Snapshotter.
+func (db *GoLevelDB) Snapshot() (Snapshot, error) {
+	snap, err := db.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return goLevelDBSnapshot{snap}, nil
+}
+
+type goLevelDBSnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+// Implements Snapshot.
+func (s goLevelDBSnapshot) Iterator() Iterator {
+	return newGoLevelDBIterator(s.snap.NewIterator(nil, nil), nil, nil, false)
+}
+
+// Implements Snapshot.
+func (s goLevelDBSnapshot) Release() {
+	s.snap.Release()
+}
+
 type goLevelDBIterator struct {
 	source    iterator.Iterator
 	start     []byte
@@ -167,6 +167,33 @@ func (db *MemDB) ReverseIterator(start, end []byte) Iterator {
 	return newMemDBIterator(db, keys, start, end)
 }
 
+var _ Snapshotter = (*MemDB)(nil)
+
+// Implements Snapshotter.
+func (db *MemDB) Snapshot() (Snapshot, error) {
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	// Values are never modified in place, so copying the map is enough.
+	snap := NewMemDB()
+	for key, value := range db.db {
+		snap.db[key] = value
+	}
+	return memDBSnapshot{snap}, nil
+}
+
+type memDBSnapshot struct {
+	db *MemDB
+}
+
+// Implements Snapshot.
+func (s memDBSnapshot) Iterator() Iterator {
+	return s.db.Iterator(nil, nil)
+}
+
+// Implements Snapshot.
+func (s memDBSnapshot) Release() {}
+
 // We need a copy of all of the keys.
 // Not the best, but probably not a bottleneck depending.
 type memDBIterator struct {
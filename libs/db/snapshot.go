@@ -0,0 +1,75 @@
+package db
+
+import "fmt"
+
+// snapshotCopyBatchSize is the number of items written per batch when
+// copying a snapshot.
+const snapshotCopyBatchSize = 1000
+
+// Snapshotter is implemented by the DBs which can provide a consistent,
+// read-only view of their content while they are being written to.
+type Snapshotter interface {
+	Snapshot() (Snapshot, error)
+}
+
+// Snapshot is a read-only view of a DB at the time it was taken.
+type Snapshot interface {
+	// Iterator iterates over all the items of the snapshot, in ascending
+	// order of keys.
+	Iterator() Iterator
+
+	// Release releases the snapshot. It must be called once done with it.
+	Release()
+}
+
+// TakeSnapshot returns a snapshot of db, or an error if its backend does not
+// support snapshots.
+func TakeSnapshot(db DB) (Snapshot, error) {
+	s, ok := db.(Snapshotter)
+	if !ok {
+		return nil, fmt.Errorf("snapshots are not supported by %T", db)
+	}
+	return s.Snapshot()
+}
+
+// CopySnapshot writes all the items of snap to db, returning the error of
+// writing them if any.
+func CopySnapshot(snap Snapshot, db DB) (err error) {
+	// The batches panic on write errors.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("error writing the snapshot: %v", r)
+		}
+	}()
+
+	itr := snap.Iterator()
+	defer itr.Close()
+
+	batch, n := db.NewBatch(), 0
+	for ; itr.Valid(); itr.Next() {
+		batch.Set(itr.Key(), itr.Value())
+		n++
+		if n == snapshotCopyBatchSize {
+			batch.Write()
+			batch, n = db.NewBatch(), 0
+		}
+	}
+	batch.WriteSync()
+	return nil
+}
+
+// errCloser is implemented by the DBs whose backend reports the error of
+// closing them.
+type errCloser interface {
+	closeErr() error
+}
+
+// CloseDB closes db, returning the error of closing it if its backend reports
+// one.
+func CloseDB(db DB) error {
+	if c, ok := db.(errCloser); ok {
+		return c.closeErr()
+	}
+	db.Close()
+	return nil
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendsSnapshot(t *testing.T) {
+	for dbType := range backends {
+		t.Run(fmt.Sprintf("%v", dbType), func(t *testing.T) {
+			db, dir := newTempDB(t, dbType)
+			defer cleanupDBDir(dir, "testdb")
+
+			if _, ok := db.(Snapshotter); !ok {
+				_, err := TakeSnapshot(db)
+				assert.Error(t, err)
+				return
+			}
+
+			for i := 0; i < 2*snapshotCopyBatchSize+1; i++ {
+				db.Set([]byte(fmt.Sprintf("key%05d", i)), []byte{byte(i)})
+			}
+			snap, err := TakeSnapshot(db)
+			require.NoError(t, err)
+			defer snap.Release()
+
+			// Writes after the snapshot is taken are not part of it.
+			db.Set([]byte("key00000"), []byte("changed"))
+			db.Set([]byte("new"), []byte("new"))
+			db.Delete([]byte("key00001"))
+
+			copyDB := NewMemDB()
+			require.NoError(t, CopySnapshot(snap, copyDB))
+			assert.Len(t, copyDB.db, 2*snapshotCopyBatchSize+1)
+			checkValue(t, copyDB, []byte("key00000"), []byte{0})
+			checkValue(t, copyDB, []byte("key00001"), []byte{1})
+			checkValue(t, copyDB, []byte("new"), nil)
+		})
+	}
+}
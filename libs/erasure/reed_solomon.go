@@ -0,0 +1,242 @@
+// Package erasure provides a systematic Reed-Solomon erasure code, to
+// reconstruct data split into shards from any subset of the data and parity
+// shards as large as the data.
+package erasure
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxShards is the maximum number of data and parity shards of a code, the
+// size of the field GF(2^8).
+const MaxShards = 256
+
+var (
+	ErrShardSize       = errors.New("shards must all have the same size")
+	ErrTooFewShards    = errors.New("too few shards to reconstruct the data")
+	ErrInvalidShardNum = errors.New("invalid number of shards")
+)
+
+// Code is a Reed-Solomon code over GF(2^8), whose parity shards are the data
+// shards multiplied by a Cauchy matrix, so that any square submatrix of the
+// encoding matrix (the identity, then the Cauchy matrix) is invertible.
+type Code struct {
+	dataShards   int
+	parityShards int
+	parity       [][]byte // parityShards x dataShards
+}
+
+// NewCode returns the code of dataShards data shards and parityShards parity
+// shards, at most MaxShards in total.
+func NewCode(dataShards, parityShards int) (*Code, error) {
+	if dataShards <= 0 || parityShards < 0 || dataShards+parityShards > MaxShards {
+		return nil, fmt.Errorf("%v: %d data and %d parity shards (max: %d)",
+			ErrInvalidShardNum, dataShards, parityShards, MaxShards)
+	}
+	parity := make([][]byte, parityShards)
+	for i := range parity {
+		parity[i] = make([]byte, dataShards)
+		for j := range parity[i] {
+			// x_i = dataShards+i and y_j = j are distinct elements.
+			parity[i][j] = gfInv(byte(dataShards+i) ^ byte(j))
+		}
+	}
+	return &Code{
+		dataShards:   dataShards,
+		parityShards: parityShards,
+		parity:       parity,
+	}, nil
+}
+
+// DataShards returns the number of data shards.
+func (c *Code) DataShards() int { return c.dataShards }
+
+// ParityShards returns the number of parity shards.
+func (c *Code) ParityShards() int { return c.parityShards }
+
+// Encode returns the parity shards of the data shards, which must all have
+// the same size.
+func (c *Code) Encode(data [][]byte) ([][]byte, error) {
+	if len(data) != c.dataShards {
+		return nil, ErrInvalidShardNum
+	}
+	size, err := shardSize(data)
+	if err != nil {
+		return nil, err
+	}
+	parity := make([][]byte, c.parityShards)
+	for i := range parity {
+		parity[i] = make([]byte, size)
+		for j, shard := range data {
+			gfMulAdd(parity[i], shard, c.parity[i][j])
+		}
+	}
+	return parity, nil
+}
+
+// ReconstructData reconstructs the missing (nil) data shards of shards, the
+// data shards followed by the parity shards. At least DataShards shards must
+// be present.
+func (c *Code) ReconstructData(shards [][]byte) error {
+	if len(shards) != c.dataShards+c.parityShards {
+		return ErrInvalidShardNum
+	}
+	size, err := shardSize(shards)
+	if err != nil {
+		return err
+	}
+
+	// The rows of the encoding matrix of the first dataShards present shards.
+	var (
+		rows    = make([][]byte, 0, c.dataShards)
+		present = make([][]byte, 0, c.dataShards)
+		missing = false
+	)
+	for i, shard := range shards {
+		if len(rows) == c.dataShards {
+			break
+		}
+		if shard == nil {
+			missing = missing || i < c.dataShards
+			continue
+		}
+		row := make([]byte, c.dataShards)
+		if i < c.dataShards {
+			row[i] = 1
+		} else {
+			copy(row, c.parity[i-c.dataShards])
+		}
+		rows = append(rows, row)
+		present = append(present, shard)
+	}
+	if !missing {
+		return nil
+	}
+	if len(rows) < c.dataShards {
+		return ErrTooFewShards
+	}
+
+	// The data shards are the inverse of the rows times the present shards.
+	decode, err := gfInvertMatrix(rows)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < c.dataShards; i++ {
+		if shards[i] != nil {
+			continue
+		}
+		shard := make([]byte, size)
+		for j, p := range present {
+			gfMulAdd(shard, p, decode[i][j])
+		}
+		shards[i] = shard
+	}
+	return nil
+}
+
+// shardSize returns the size of the non-nil shards, which must not be empty.
+func shardSize(shards [][]byte) (int, error) {
+	size := -1
+	for _, shard := range shards {
+		if shard == nil {
+			continue
+		}
+		if size == -1 {
+			size = len(shard)
+		} else if len(shard) != size {
+			return 0, ErrShardSize
+		}
+	}
+	switch {
+	case size == -1:
+		return 0, ErrTooFewShards
+	case size == 0:
+		return 0, ErrShardSize
+	}
+	return size, nil
+}
+
+//-----------------------------------------------------------------------------
+// GF(2^8) arithmetic, with the polynomial x^8+x^4+x^3+x^2+1.
+
+var (
+	gfExp [2 * 255]byte
+	gfLog [256]int
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfExp[i+255] = byte(x)
+		gfLog[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+gfLog[b]]
+}
+
+// gfInv returns the inverse of a, which must not be 0.
+func gfInv(a byte) byte {
+	return gfExp[255-gfLog[a]]
+}
+
+// gfMulAdd adds c*src to dst.
+func gfMulAdd(dst, src []byte, c byte) {
+	if c == 0 {
+		return
+	}
+	var table [256]byte
+	for i := range table {
+		table[i] = gfMul(byte(i), c)
+	}
+	for i, b := range src {
+		dst[i] ^= table[b]
+	}
+}
+
+// gfInvertMatrix returns the inverse of the square matrix m, by Gauss-Jordan
+// elimination. m is modified.
+func gfInvertMatrix(m [][]byte) ([][]byte, error) {
+	n := len(m)
+	inv := make([][]byte, n)
+	for i := range inv {
+		inv[i] = make([]byte, n)
+		inv[i][i] = 1
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for pivot < n && m[pivot][col] == 0 {
+			pivot++
+		}
+		if pivot == n {
+			return nil, errors.New("singular matrix")
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+
+		if c := m[col][col]; c != 1 {
+			cInv := gfInv(c)
+			for j := 0; j < n; j++ {
+				m[col][j] = gfMul(m[col][j], cInv)
+				inv[col][j] = gfMul(inv[col][j], cInv)
+			}
+		}
+		for row := 0; row < n; row++ {
+			if c := m[row][col]; row != col && c != 0 {
+				gfMulAdd(m[row], m[col], c)
+				gfMulAdd(inv[row], inv[col], c)
+			}
+		}
+	}
+	return inv, nil
+}
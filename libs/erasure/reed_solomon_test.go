@@ -0,0 +1,75 @@
+package erasure
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randShards(n, size int) [][]byte {
+	shards := make([][]byte, n)
+	for i := range shards {
+		shards[i] = make([]byte, size)
+		rand.Read(shards[i])
+	}
+	return shards
+}
+
+func TestCodeReconstructData(t *testing.T) {
+	testCases := []struct {
+		dataShards, parityShards int
+	}{
+		{1, 1},
+		{1, 3},
+		{4, 2},
+		{10, 5},
+		{100, 50},
+		{200, 56},
+	}
+
+	for _, tc := range testCases {
+		code, err := NewCode(tc.dataShards, tc.parityShards)
+		require.NoError(t, err)
+		data := randShards(tc.dataShards, 64)
+		parity, err := code.Encode(data)
+		require.NoError(t, err)
+		require.Len(t, parity, tc.parityShards)
+
+		// Drop as many random shards as there are parity shards.
+		shards := append(append([][]byte{}, data...), parity...)
+		for _, i := range rand.Perm(len(shards))[:tc.parityShards] {
+			shards[i] = nil
+		}
+		require.NoError(t, code.ReconstructData(shards), "%d+%d", tc.dataShards, tc.parityShards)
+		assert.Equal(t, data, shards[:tc.dataShards], "%d+%d", tc.dataShards, tc.parityShards)
+
+		// One more is too many.
+		if tc.parityShards < len(shards) {
+			shards = append(append([][]byte{}, data...), parity...)
+			for _, i := range rand.Perm(tc.dataShards)[:1] {
+				shards[i] = nil
+			}
+			for i := tc.dataShards; i < len(shards); i++ {
+				shards[i] = nil
+			}
+			assert.Equal(t, ErrTooFewShards, code.ReconstructData(shards))
+		}
+	}
+}
+
+func TestCodeInvalid(t *testing.T) {
+	_, err := NewCode(0, 1)
+	assert.Error(t, err)
+	_, err = NewCode(200, 57)
+	assert.Error(t, err)
+
+	code, err := NewCode(2, 1)
+	require.NoError(t, err)
+	_, err = code.Encode([][]byte{{1, 2}, {3}})
+	assert.Equal(t, ErrShardSize, err)
+	_, err = code.Encode([][]byte{{1, 2}})
+	assert.Equal(t, ErrInvalidShardNum, err)
+	assert.Equal(t, ErrShardSize, code.ReconstructData([][]byte{nil, {3}, {4, 5}}))
+}
@@ -0,0 +1,167 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecentLogs keeps the last lines logged through the loggers returned by
+// NewRecentLogger, e.g. for the crash reports.
+type RecentLogs struct {
+	mtx   sync.Mutex
+	lines []string
+	next  int // index of the next line in lines, once full
+}
+
+// NewRecentLogs returns a RecentLogs keeping the last size lines.
+func NewRecentLogs(size int) *RecentLogs {
+	return &RecentLogs{lines: make([]string, 0, size)}
+}
+
+// Lines returns the last lines logged, the oldest first.
+func (r *RecentLogs) Lines() []string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	lines := make([]string, 0, len(r.lines))
+	lines = append(lines, r.lines[r.next:]...)
+	return append(lines, r.lines[:r.next]...)
+}
+
+func (r *RecentLogs) record(module, line string) {
+	r.add(line)
+}
+
+func (r *RecentLogs) add(line string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if len(r.lines) < cap(r.lines) {
+		r.lines = append(r.lines, line)
+		return
+	}
+	if len(r.lines) == 0 {
+		return
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+}
+
+// RecentModuleLogs keeps the last lines logged by each module through the
+// loggers returned by NewRecentModuleLogger. The module of a line is the value
+// of its "module" key, or "" if it has none.
+type RecentModuleLogs struct {
+	mtx     sync.Mutex
+	size    int
+	modules map[string]*RecentLogs
+}
+
+// NewRecentModuleLogs returns a RecentModuleLogs keeping the last size lines
+// of each module.
+func NewRecentModuleLogs(size int) *RecentModuleLogs {
+	return &RecentModuleLogs{size: size, modules: make(map[string]*RecentLogs)}
+}
+
+// Modules returns the sorted modules which logged lines.
+func (r *RecentModuleLogs) Modules() []string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	modules := make([]string, 0, len(r.modules))
+	for module := range r.modules {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// Lines returns the last lines logged by the module, the oldest first.
+func (r *RecentModuleLogs) Lines(module string) []string {
+	r.mtx.Lock()
+	recent, ok := r.modules[module]
+	r.mtx.Unlock()
+	if !ok {
+		return []string{}
+	}
+	return recent.Lines()
+}
+
+func (r *RecentModuleLogs) record(module, line string) {
+	r.mtx.Lock()
+	recent, ok := r.modules[module]
+	if !ok {
+		recent = NewRecentLogs(r.size)
+		r.modules[module] = recent
+	}
+	r.mtx.Unlock()
+	recent.add(line)
+}
+
+type recentRecorder interface {
+	record(module, line string)
+}
+
+// NewRecentLogger records the info and error lines logged through it in
+// recent, before passing them on to next, which may filter them. The debug
+// lines aren't recorded, as there are too many of them to format.
+func NewRecentLogger(next Logger, recent *RecentLogs) Logger {
+	return &recentLogger{next: next, recent: recent}
+}
+
+// NewRecentModuleLogger is like NewRecentLogger, recording the lines by
+// module in recent.
+func NewRecentModuleLogger(next Logger, recent *RecentModuleLogs) Logger {
+	return &recentLogger{next: next, recent: recent}
+}
+
+type recentLogger struct {
+	next    Logger
+	recent  recentRecorder
+	keyvals []interface{} // of With
+	module  string        // of With
+}
+
+func (l *recentLogger) Debug(msg string, keyvals ...interface{}) {
+	l.next.Debug(msg, keyvals...)
+}
+
+func (l *recentLogger) Info(msg string, keyvals ...interface{}) {
+	l.record("I", msg, keyvals)
+	l.next.Info(msg, keyvals...)
+}
+
+func (l *recentLogger) Error(msg string, keyvals ...interface{}) {
+	l.record("E", msg, keyvals)
+	l.next.Error(msg, keyvals...)
+}
+
+func (l *recentLogger) With(keyvals ...interface{}) Logger {
+	return &recentLogger{
+		next:    l.next.With(keyvals...),
+		recent:  l.recent,
+		keyvals: append(l.keyvals[:len(l.keyvals):len(l.keyvals)], keyvals...),
+		module:  moduleOf(keyvals, l.module),
+	}
+}
+
+// moduleOf returns the value of the last "module" key of keyvals, or module if
+// there is none.
+func moduleOf(keyvals []interface{}, module string) string {
+	for i := 0; i < len(keyvals)-1; i += 2 {
+		if keyvals[i] == moduleKey {
+			module = fmt.Sprintf("%v", keyvals[i+1])
+		}
+	}
+	return module
+}
+
+func (l *recentLogger) record(level, msg string, keyvals []interface{}) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s[%s] %s", level, time.Now().UTC().Format(time.RFC3339Nano), msg)
+	for _, kvs := range [][]interface{}{l.keyvals, keyvals} {
+		for i := 0; i < len(kvs)-1; i += 2 {
+			fmt.Fprintf(&b, " %v=%v", kvs[i], kvs[i+1])
+		}
+	}
+	l.recent.record(moduleOf(keyvals, l.module), b.String())
+}
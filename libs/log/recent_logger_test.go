@@ -0,0 +1,63 @@
+package log_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestRecentLogger(t *testing.T) {
+	var buf bytes.Buffer
+	recent := log.NewRecentLogs(2)
+	logger := log.NewRecentLogger(log.NewTMJSONLogger(&buf), recent)
+
+	logger.Info("foo")
+	logger.With("module", "consensus").Error("bar", "height", 1)
+	if len(recent.Lines()) != 2 {
+		t.Fatalf("want 2 lines, have %v", recent.Lines())
+	}
+	logger.Debug("debug")
+	logger.Info("baz")
+
+	lines := recent.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("want 2 lines, have %v", lines)
+	}
+	if !strings.HasPrefix(lines[0], "E[") || !strings.HasSuffix(lines[0], "] bar module=consensus height=1") {
+		t.Errorf("unexpected first line %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "I[") || !strings.HasSuffix(lines[1], "] baz") {
+		t.Errorf("unexpected second line %q", lines[1])
+	}
+
+	// the lines are passed on
+	if !strings.Contains(buf.String(), `"_msg":"bar"`) {
+		t.Errorf("the lines aren't passed on: %s", buf.String())
+	}
+}
+
+func TestRecentModuleLogger(t *testing.T) {
+	recent := log.NewRecentModuleLogs(1)
+	logger := log.NewRecentModuleLogger(log.NewNopLogger(), recent)
+
+	logger.Info("foo")
+	consensusLogger := logger.With("module", "consensus")
+	consensusLogger.Info("bar")
+	consensusLogger.Error("baz", "height", 1)
+	logger.Info("qux", "module", "p2p")
+
+	if modules := recent.Modules(); len(modules) != 3 || modules[0] != "" || modules[1] != "consensus" || modules[2] != "p2p" {
+		t.Fatalf("unexpected modules %v", modules)
+	}
+	if lines := recent.Lines("consensus"); len(lines) != 1 || !strings.HasSuffix(lines[0], "] baz module=consensus height=1") {
+		t.Errorf("unexpected consensus lines %v", lines)
+	}
+	if lines := recent.Lines("p2p"); len(lines) != 1 || !strings.HasSuffix(lines[0], "] qux module=p2p") {
+		t.Errorf("unexpected p2p lines %v", lines)
+	}
+	if lines := recent.Lines("mempool"); len(lines) != 0 {
+		t.Errorf("unexpected mempool lines %v", lines)
+	}
+}
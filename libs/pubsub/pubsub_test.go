@@ -182,6 +182,27 @@ func TestUnsubscribeAll(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestNumClientSubscriptions(t *testing.T) {
+	s := pubsub.NewServer()
+	s.SetLogger(log.TestingLogger())
+	s.Start()
+	defer s.Stop()
+
+	ctx := context.Background()
+	assert.Equal(t, 0, s.NumClientSubscriptions(clientID))
+
+	err := s.Subscribe(ctx, clientID, query.MustParse("tm.events.type='NewBlock'"), make(chan interface{}))
+	require.NoError(t, err)
+	err = s.Subscribe(ctx, clientID, query.MustParse("tm.events.type='NewBlockHeader'"), make(chan interface{}))
+	require.NoError(t, err)
+	assert.Equal(t, 2, s.NumClientSubscriptions(clientID))
+	assert.Equal(t, 0, s.NumClientSubscriptions("other"))
+
+	err = s.UnsubscribeAll(ctx, clientID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, s.NumClientSubscriptions(clientID))
+}
+
 func TestBufferCapacity(t *testing.T) {
 	s := pubsub.NewServer(pubsub.BufferCapacity(2))
 	s.SetLogger(log.TestingLogger())
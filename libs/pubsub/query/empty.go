@@ -14,3 +14,9 @@ func (Empty) Matches(tags pubsub.TagMap) bool {
 func (Empty) String() string {
 	return "empty"
 }
+
+// Wildcard returns the Wildcard, the query string parsed as Empty by Parse,
+// e.g. to subscribe to all the events over the RPC.
+func (Empty) Wildcard() string {
+	return Wildcard
+}
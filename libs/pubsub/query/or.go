@@ -0,0 +1,91 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/tendermint/tendermint/libs/pubsub"
+)
+
+// Wildcard is the query string matching any set of tags, see Parse.
+const Wildcard = "*"
+
+const or = " OR "
+
+// Or is a query matching the tags matched by any of its queries.
+type Or struct {
+	str     string
+	queries []*Query
+}
+
+// NewOr parses the given string as queries separated by OR, e.g.
+//
+//		tm.event='NewBlock' OR tm.event='Tx' AND tx.height=5
+//
+// AND takes precedence over OR. It returns an error if any of the queries is
+// invalid.
+func NewOr(s string) (*Or, error) {
+	parts := splitOr(s)
+	queries := make([]*Query, len(parts))
+	for i, part := range parts {
+		q, err := New(part)
+		if err != nil {
+			return nil, err
+		}
+		queries[i] = q
+	}
+	return &Or{str: s, queries: queries}, nil
+}
+
+// Matches returns true if any of the queries matches the given set of tags.
+func (q *Or) Matches(tags pubsub.TagMap) bool {
+	for _, query := range q.queries {
+		if query.Matches(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the original string.
+func (q *Or) String() string {
+	return q.str
+}
+
+// Queries returns the queries OR'd together.
+func (q *Or) Queries() []*Query {
+	return q.queries
+}
+
+// Parse parses the given string as a subscription query: either the
+// Wildcard, which matches any set of tags, queries separated by OR, or a
+// single query.
+func Parse(s string) (pubsub.Query, error) {
+	switch {
+	case s == Wildcard:
+		return Empty{}, nil
+	case len(splitOr(s)) > 1:
+		return NewOr(s)
+	default:
+		return New(s)
+	}
+}
+
+// splitOr splits s around the OR separators which are not within quotes.
+func splitOr(s string) []string {
+	var (
+		parts   []string
+		start   int
+		inQuote bool
+	)
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\'':
+			inQuote = !inQuote
+		case !inQuote && strings.HasPrefix(s[i:], or):
+			parts = append(parts, s[start:i])
+			start = i + len(or)
+			i = start - 1
+		}
+	}
+	return append(parts, s[start:])
+}
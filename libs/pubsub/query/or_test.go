@@ -0,0 +1,62 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/pubsub"
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+)
+
+func TestOrMatches(t *testing.T) {
+	testCases := []struct {
+		s       string
+		tags    map[string]string
+		err     bool
+		matches bool
+	}{
+		{"tm.event='NewBlock' OR tm.event='Tx'", map[string]string{"tm.event": "NewBlock"}, false, true},
+		{"tm.event='NewBlock' OR tm.event='Tx'", map[string]string{"tm.event": "Tx"}, false, true},
+		{"tm.event='NewBlock' OR tm.event='Tx'", map[string]string{"tm.event": "Vote"}, false, false},
+		{"tm.event='Tx' AND tx.height=5 OR tm.event='NewBlock'", map[string]string{"tm.event": "Tx", "tx.height": "5"}, false, true},
+		{"tm.event='Tx' AND tx.height=5 OR tm.event='NewBlock'", map[string]string{"tm.event": "Tx", "tx.height": "6"}, false, false},
+		{"tm.event='Tx' AND tx.height=5 OR tm.event='NewBlock'", map[string]string{"tm.event": "NewBlock"}, false, true},
+		// OR within quotes is part of the value
+		{"abci.owner.name='Igor OR Ivan'", map[string]string{"abci.owner.name": "Igor OR Ivan"}, false, true},
+		{"abci.owner.name='Igor OR Ivan'", map[string]string{"abci.owner.name": "Igor"}, false, false},
+
+		{"tm.event='NewBlock' OR", nil, true, false},
+		{"OR tm.event='NewBlock'", nil, true, false},
+	}
+
+	for _, tc := range testCases {
+		q, err := query.NewOr(tc.s)
+		if tc.err {
+			assert.Error(t, err, tc.s)
+			continue
+		}
+		require.NoError(t, err, tc.s)
+		assert.Equal(t, tc.s, q.String())
+		assert.Equal(t, tc.matches, q.Matches(pubsub.NewTagMap(tc.tags)), tc.s)
+	}
+}
+
+func TestParse(t *testing.T) {
+	q, err := query.Parse(query.Wildcard)
+	require.NoError(t, err)
+	assert.Equal(t, query.Empty{}, q)
+	assert.Equal(t, query.Wildcard, query.Empty{}.Wildcard())
+
+	q, err = query.Parse("tm.event='NewBlock' OR tm.event='Tx'")
+	require.NoError(t, err)
+	assert.IsType(t, &query.Or{}, q)
+
+	q, err = query.Parse("tm.event='NewBlock'")
+	require.NoError(t, err)
+	assert.IsType(t, &query.Query{}, q)
+
+	_, err = query.Parse("")
+	assert.Error(t, err)
+}
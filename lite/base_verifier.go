@@ -17,18 +17,20 @@ var _ Verifier = (*BaseVerifier)(nil)
 // TODO: Handle unbonding time.
 type BaseVerifier struct {
 	chainID string
+	domain  types.SignDomain
 	height  int64
 	valset  *types.ValidatorSet
 }
 
 // NewBaseVerifier returns a new Verifier initialized with a validator set at
-// some height.
-func NewBaseVerifier(chainID string, height int64, valset *types.ValidatorSet) *BaseVerifier {
+// some height, verifying the commits signed in the sign domain of the chain.
+func NewBaseVerifier(chainID string, domain types.SignDomain, height int64, valset *types.ValidatorSet) *BaseVerifier {
 	if valset.IsNilOrEmpty() {
 		panic("NewBaseVerifier requires a valid valset")
 	}
 	return &BaseVerifier{
 		chainID: chainID,
+		domain:  domain,
 		height:  height,
 		valset:  valset,
 	}
@@ -68,7 +70,7 @@ func (bv *BaseVerifier) Verify(signedHeader types.SignedHeader) error {
 
 	// Check commit signatures.
 	err = bv.valset.VerifyCommit(
-		bv.chainID, signedHeader.Commit.BlockID,
+		bv.chainID, bv.domain, signedHeader.Commit.BlockID,
 		signedHeader.Height, signedHeader.Commit)
 	if err != nil {
 		return cmn.ErrorWrap(err, "in verify")
@@ -17,7 +17,7 @@ func TestBaseCert(t *testing.T) {
 	vals := keys.ToValidators(20, 10)
 	// and a Verifier based on our known set
 	chainID := "test-static"
-	cert := NewBaseVerifier(chainID, 2, vals)
+	cert := NewBaseVerifier(chainID, types.SignDomain{}, 2, vals)
 
 	cases := []struct {
 		keys        privKeys
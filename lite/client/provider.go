@@ -6,6 +6,7 @@ and validators directly from a Tendermint client.
 package client
 
 import (
+	"context"
 	"fmt"
 
 	log "github.com/tendermint/tendermint/libs/log"
@@ -75,7 +76,7 @@ func (p *provider) LatestFullCommit(chainID string, minHeight, maxHeight int64)
 
 // fetchLatestCommit fetches the latest commit from the client.
 func (p *provider) fetchLatestCommit(minHeight int64, maxHeight int64) (*ctypes.ResultCommit, error) {
-	status, err := p.client.Status()
+	status, err := p.client.Status(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +90,7 @@ func (p *provider) fetchLatestCommit(minHeight int64, maxHeight int64) (*ctypes.
 	} else if status.SyncInfo.LatestBlockHeight < maxHeight {
 		maxHeight = status.SyncInfo.LatestBlockHeight
 	}
-	return p.client.Commit(&maxHeight)
+	return p.client.Commit(context.Background(), &maxHeight)
 }
 
 // Implements Provider.
@@ -106,7 +107,7 @@ func (p *provider) getValidatorSet(chainID string, height int64) (valset *types.
 		err = fmt.Errorf("expected height >= 1, got height %v", height)
 		return
 	}
-	res, err := p.client.Validators(&height)
+	res, err := p.client.Validators(context.Background(), &height, 0, 0)
 	if err != nil {
 		// TODO pass through other types of errors.
 		return nil, lerr.ErrUnknownValidators(chainID, height)
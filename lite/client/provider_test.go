@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -39,7 +40,7 @@ func TestProvider(t *testing.T) {
 	require.NotNil(t, p)
 
 	// let it produce some blocks
-	err = rpcclient.WaitForHeight(p.(*provider).client, 6, nil)
+	err = rpcclient.WaitForHeight(context.Background(), p.(*provider).client, 6, nil)
 	require.Nil(err)
 
 	// let's get the highest block
@@ -50,7 +51,7 @@ func TestProvider(t *testing.T) {
 	assert.True(sh < 5000)
 
 	// let's check this is valid somehow
-	assert.Nil(fc.ValidateFull(chainID))
+	assert.Nil(fc.ValidateFull(chainID, types.SignDomain{}))
 
 	// historical queries now work :)
 	lower := sh - 5
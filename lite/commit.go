@@ -32,8 +32,8 @@ func NewFullCommit(signedHeader types.SignedHeader, valset, nextValset *types.Va
 // This also checks to make sure that Validators actually
 // signed the SignedHeader.Commit.
 // If > 2/3 did not sign the Commit from fc.Validators, it
-// is not a valid commit!
-func (fc FullCommit) ValidateFull(chainID string) error {
+// is not a valid commit! The votes are signed in the sign domain of the chain.
+func (fc FullCommit) ValidateFull(chainID string, domain types.SignDomain) error {
 	// Ensure that Validators exists and matches the header.
 	if fc.Validators.Size() == 0 {
 		return errors.New("need FullCommit.Validators")
@@ -66,7 +66,7 @@ func (fc FullCommit) ValidateFull(chainID string) error {
 	// Validate the signatures on the commit.
 	hdr, cmt := fc.SignedHeader.Header, fc.SignedHeader.Commit
 	return fc.Validators.VerifyCommit(
-		hdr.ChainID, cmt.BlockID,
+		hdr.ChainID, domain, cmt.BlockID,
 		hdr.Height, cmt)
 }
 
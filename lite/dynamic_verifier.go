@@ -23,6 +23,7 @@ var _ Verifier = (*DynamicVerifier)(nil)
 // see https://github.com/tendermint/tendermint/issues/3170
 type DynamicVerifier struct {
 	chainID string
+	domain  types.SignDomain
 	logger  log.Logger
 
 	// Already validated, stored locally
@@ -38,14 +39,16 @@ type DynamicVerifier struct {
 
 // NewDynamicVerifier returns a new DynamicVerifier. It uses the
 // trusted provider to store validated data and the source provider to
-// obtain missing data (e.g. FullCommits).
+// obtain missing data (e.g. FullCommits). The commits are verified in the sign
+// domain of the chain.
 //
 // The trusted provider should be a DBProvider.
 // The source provider should be a client.HTTPProvider.
-func NewDynamicVerifier(chainID string, trusted PersistentProvider, source Provider) *DynamicVerifier {
+func NewDynamicVerifier(chainID string, domain types.SignDomain, trusted PersistentProvider, source Provider) *DynamicVerifier {
 	return &DynamicVerifier{
 		logger:               log.NewNopLogger(),
 		chainID:              chainID,
+		domain:               domain,
 		trusted:              trusted,
 		source:               source,
 		pendingVerifications: make(map[int64]chan struct{}, sizeOfPendingMap),
@@ -146,7 +149,7 @@ func (dv *DynamicVerifier) Verify(shdr types.SignedHeader) error {
 	}
 
 	// Verify the signed header using the matching valset.
-	cert := NewBaseVerifier(dv.chainID, trustedFC.Height()+1, trustedFC.NextValidators)
+	cert := NewBaseVerifier(dv.chainID, dv.domain, trustedFC.Height()+1, trustedFC.NextValidators)
 	err = cert.Verify(shdr)
 	if err != nil {
 		return err
@@ -176,7 +179,7 @@ func (dv *DynamicVerifier) Verify(shdr types.SignedHeader) error {
 	}
 	// Validate the full commit.  This checks the cryptographic
 	// signatures of Commit against Validators.
-	if err := nfc.ValidateFull(dv.chainID); err != nil {
+	if err := nfc.ValidateFull(dv.chainID, dv.domain); err != nil {
 		return err
 	}
 	// Trust it.
@@ -193,7 +196,7 @@ func (dv *DynamicVerifier) verifyAndSave(trustedFC, sourceFC FullCommit) error {
 	}
 	err := trustedFC.NextValidators.VerifyFutureCommit(
 		sourceFC.Validators,
-		dv.chainID, sourceFC.SignedHeader.Commit.BlockID,
+		dv.chainID, dv.domain, sourceFC.SignedHeader.Commit.BlockID,
 		sourceFC.SignedHeader.Height, sourceFC.SignedHeader.Commit,
 	)
 	if err != nil {
@@ -223,7 +226,7 @@ func (dv *DynamicVerifier) updateToHeight(h int64) (FullCommit, error) {
 
 	// Validate the full commit.  This checks the cryptographic
 	// signatures of Commit against Validators.
-	if err := sourceFC.ValidateFull(dv.chainID); err != nil {
+	if err := sourceFC.ValidateFull(dv.chainID, dv.domain); err != nil {
 		return FullCommit{}, err
 	}
 
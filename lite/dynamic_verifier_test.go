@@ -46,7 +46,7 @@ func TestInquirerValidPath(t *testing.T) {
 	// Initialize a Verifier with the initial state.
 	err := trust.SaveFullCommit(fcz[0])
 	require.Nil(err)
-	cert := NewDynamicVerifier(chainID, trust, source)
+	cert := NewDynamicVerifier(chainID, types.SignDomain{}, trust, source)
 	cert.SetLogger(log.TestingLogger())
 
 	// This should fail validation:
@@ -111,7 +111,7 @@ func TestDynamicVerify(t *testing.T) {
 	// Initialize a Verifier with the initial state.
 	err := trust.SaveFullCommit(fcz[0])
 	require.Nil(t, err)
-	ver := NewDynamicVerifier(chainID, trust, source)
+	ver := NewDynamicVerifier(chainID, types.SignDomain{}, trust, source)
 	ver.SetLogger(log.TestingLogger())
 
 	// fetch the latest from the source
@@ -168,7 +168,7 @@ func TestInquirerVerifyHistorical(t *testing.T) {
 	// Initialize a Verifier with the initial state.
 	err := trust.SaveFullCommit(fcz[0])
 	require.Nil(err)
-	cert := NewDynamicVerifier(chainID, trust, source)
+	cert := NewDynamicVerifier(chainID, types.SignDomain{}, trust, source)
 	cert.SetLogger(log.TestingLogger())
 
 	// Store a few full commits as trust.
@@ -251,7 +251,7 @@ func TestConcurrencyInquirerVerify(t *testing.T) {
 	// Initialize a Verifier with the initial state.
 	err := trust.SaveFullCommit(fcz[0])
 	require.Nil(err)
-	cert := NewDynamicVerifier(chainID, trust, source)
+	cert := NewDynamicVerifier(chainID, types.SignDomain{}, trust, source)
 	cert.SetLogger(log.TestingLogger())
 
 	err = source.SaveFullCommit(fcz[7])
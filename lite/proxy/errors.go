@@ -1,6 +1,8 @@
 package proxy
 
 import (
+	"fmt"
+
 	cmn "github.com/tendermint/tendermint/libs/common"
 )
 
@@ -22,3 +24,27 @@ func IsErrNoData(err error) bool {
 func ErrNoData() error {
 	return cmn.ErrorWrap(errNoData{}, "")
 }
+
+type errQueryFailed struct {
+	key  []byte
+	code uint32
+	log  string
+}
+
+func (e errQueryFailed) Error() string {
+	return fmt.Sprintf("Query error for key %X: %d %s", e.key, e.code, e.log)
+}
+
+// IsErrQueryFailed checks whether an error is due to the node returning an
+// error for the query, e.g. for a height it pruned.
+func IsErrQueryFailed(err error) bool {
+	if err_, ok := err.(cmn.Error); ok {
+		_, ok := err_.Data().(errQueryFailed)
+		return ok
+	}
+	return false
+}
+
+func ErrQueryFailed(key []byte, code uint32, log string) error {
+	return cmn.ErrorWrap(errQueryFailed{key: key, code: code, log: log}, "")
+}
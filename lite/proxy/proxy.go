@@ -28,7 +28,7 @@ func StartProxy(c rpcclient.Client, listenAddr string, logger log.Logger, maxOpe
 
 	cdc := amino.NewCodec()
 	ctypes.RegisterAmino(cdc)
-	r := RPCRoutes(c)
+	r := RPCRoutes(c, logger)
 
 	// build the handler...
 	mux := http.NewServeMux()
@@ -36,7 +36,6 @@ func StartProxy(c rpcclient.Client, listenAddr string, logger log.Logger, maxOpe
 
 	wm := rpcserver.NewWebsocketManager(r, cdc, rpcserver.EventSubscriber(c))
 	wm.SetLogger(logger)
-	core.SetLogger(logger)
 	mux.HandleFunc(wsEndpoint, wm.WebsocketHandler)
 
 	l, err := rpcserver.Listen(listenAddr, rpcserver.Config{MaxOpenConnections: maxOpenConnections})
@@ -50,14 +49,15 @@ func StartProxy(c rpcclient.Client, listenAddr string, logger log.Logger, maxOpe
 // a tendermint fullnode.
 //
 // if we want security, the client must implement it as a secure client
-func RPCRoutes(c rpcclient.Client) map[string]*rpcserver.RPCFunc {
+func RPCRoutes(c rpcclient.Client, logger log.Logger) map[string]*rpcserver.RPCFunc {
+	env := &core.Environment{Logger: logger}
 
 	return map[string]*rpcserver.RPCFunc{
 		// Subscribe/unsubscribe are reserved for websocket events.
 		// We can just use the core tendermint impl, which uses the
 		// EventSwitch we registered in NewWebsocketManager above
-		"subscribe":   rpcserver.NewWSRPCFunc(core.Subscribe, "query"),
-		"unsubscribe": rpcserver.NewWSRPCFunc(core.Unsubscribe, "query"),
+		"subscribe":   rpcserver.NewWSRPCFunc(env.Subscribe, "query,header_only,ack_id,schema"),
+		"unsubscribe": rpcserver.NewWSRPCFunc(env.Unsubscribe, "query,ack_id"),
 
 		// info API
 		"status":     rpcserver.NewRPCFunc(c.Status, ""),
@@ -66,7 +66,7 @@ func RPCRoutes(c rpcclient.Client) map[string]*rpcserver.RPCFunc {
 		"block":      rpcserver.NewRPCFunc(c.Block, "height"),
 		"commit":     rpcserver.NewRPCFunc(c.Commit, "height"),
 		"tx":         rpcserver.NewRPCFunc(c.Tx, "hash,prove"),
-		"validators": rpcserver.NewRPCFunc(c.Validators, ""),
+		"validators": rpcserver.NewRPCFunc(c.Validators, "height,page,per_page"),
 
 		// broadcast API
 		"broadcast_tx_commit": rpcserver.NewRPCFunc(c.BroadcastTxCommit, "tx"),
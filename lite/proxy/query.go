@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -17,7 +18,7 @@ import (
 // a valid proof, as defined by the Verifier.
 //
 // If there is any error in checking, returns an error.
-func GetWithProof(prt *merkle.ProofRuntime, key []byte, reqHeight int64, node rpcclient.Client,
+func GetWithProof(ctx context.Context, prt *merkle.ProofRuntime, key []byte, reqHeight int64, node rpcclient.Client,
 	cert lite.Verifier) (
 	val cmn.HexBytes, height int64, proof *merkle.Proof, err error) {
 
@@ -26,7 +27,7 @@ func GetWithProof(prt *merkle.ProofRuntime, key []byte, reqHeight int64, node rp
 		return
 	}
 
-	res, err := GetWithProofOptions(prt, "/key", key,
+	res, err := GetWithProofOptions(ctx, prt, "/key", key,
 		rpcclient.ABCIQueryOptions{Height: int64(reqHeight), Prove: true},
 		node, cert)
 	if err != nil {
@@ -40,7 +41,7 @@ func GetWithProof(prt *merkle.ProofRuntime, key []byte, reqHeight int64, node rp
 
 // GetWithProofOptions is useful if you want full access to the ABCIQueryOptions.
 // XXX Usage of path?  It's not used, and sometimes it's /, sometimes /key, sometimes /store.
-func GetWithProofOptions(prt *merkle.ProofRuntime, path string, key []byte, opts rpcclient.ABCIQueryOptions,
+func GetWithProofOptions(ctx context.Context, prt *merkle.ProofRuntime, path string, key []byte, opts rpcclient.ABCIQueryOptions,
 	node rpcclient.Client, cert lite.Verifier) (
 	*ctypes.ResultABCIQuery, error) {
 
@@ -48,7 +49,7 @@ func GetWithProofOptions(prt *merkle.ProofRuntime, path string, key []byte, opts
 		return nil, cmn.NewError("require ABCIQueryOptions.Prove to be true")
 	}
 
-	res, err := node.ABCIQueryWithOptions(path, key, opts)
+	res, err := node.ABCIQueryWithOptions(ctx, path, key, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -56,8 +57,7 @@ func GetWithProofOptions(prt *merkle.ProofRuntime, path string, key []byte, opts
 
 	// Validate the response, e.g. height.
 	if resp.IsErr() {
-		err = cmn.NewError("Query error for key %d: %d", key, resp.Code)
-		return nil, err
+		return nil, ErrQueryFailed(key, resp.Code, resp.Log)
 	}
 
 	if len(resp.Key) == 0 || resp.Proof == nil {
@@ -68,7 +68,7 @@ func GetWithProofOptions(prt *merkle.ProofRuntime, path string, key []byte, opts
 	}
 
 	// AppHash for height H is in header H+1
-	signedHeader, err := GetCertifiedCommit(resp.Height+1, node, cert)
+	signedHeader, err := GetCertifiedCommit(ctx, resp.Height+1, node, cert)
 	if err != nil {
 		return nil, err
 	}
@@ -96,13 +96,13 @@ func GetWithProofOptions(prt *merkle.ProofRuntime, path string, key []byte, opts
 
 // GetCertifiedCommit gets the signed header for a given height and certifies
 // it. Returns error if unable to get a proven header.
-func GetCertifiedCommit(h int64, client rpcclient.Client, cert lite.Verifier) (types.SignedHeader, error) {
+func GetCertifiedCommit(ctx context.Context, h int64, client rpcclient.Client, cert lite.Verifier) (types.SignedHeader, error) {
 
 	// FIXME: cannot use cert.GetByHeight for now, as it also requires
 	// Validators and will fail on querying tendermint for non-current height.
 	// When this is supported, we should use it instead...
-	rpcclient.WaitForHeight(client, h, nil)
-	cresp, err := client.Commit(&h)
+	rpcclient.WaitForHeight(ctx, client, h, nil)
+	cresp, err := client.Commit(ctx, &h)
 	if err != nil {
 		return types.SignedHeader{}, err
 	}
@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -48,13 +49,13 @@ func _TestAppProofs(t *testing.T) {
 
 	prt := defaultProofRuntime()
 	cl := client.NewLocal(node)
-	client.WaitForHeight(cl, 1, nil)
+	client.WaitForHeight(context.Background(), cl, 1, nil)
 
 	// This sets up our trust on the node based on some past point.
 	source := certclient.NewProvider(chainID, cl)
 	seed, err := source.LatestFullCommit(chainID, 1, 1)
 	require.NoError(err, "%#v", err)
-	cert := lite.NewBaseVerifier(chainID, seed.Height(), seed.Validators)
+	cert := lite.NewBaseVerifier(chainID, types.SignDomain{}, seed.Height(), seed.Validators)
 
 	// Wait for tx confirmation.
 	done := make(chan int64)
@@ -69,7 +70,7 @@ func _TestAppProofs(t *testing.T) {
 	k := []byte("my-key")
 	v := []byte("my-value")
 	tx := kvstoreTx(k, v)
-	br, err := cl.BroadcastTxCommit(tx)
+	br, err := cl.BroadcastTxCommit(context.Background(), tx)
 	require.NoError(err, "%#v", err)
 	require.EqualValues(0, br.CheckTx.Code, "%#v", br.CheckTx)
 	require.EqualValues(0, br.DeliverTx.Code)
@@ -83,7 +84,7 @@ func _TestAppProofs(t *testing.T) {
 	if rootHash == nil {
 		// Fetch one block later, AppHash hasn't been committed yet.
 		// TODO find a way to avoid doing this.
-		client.WaitForHeight(cl, latest.SignedHeader.Height+1, nil)
+		client.WaitForHeight(context.Background(), cl, latest.SignedHeader.Height+1, nil)
 		latest, err = source.LatestFullCommit(chainID, latest.SignedHeader.Height+1, 1<<63-1)
 		require.NoError(err, "%#v", err)
 		rootHash = latest.SignedHeader.AppHash
@@ -91,7 +92,7 @@ func _TestAppProofs(t *testing.T) {
 	require.NotNil(rootHash)
 
 	// verify a query before the tx block has no data (and valid non-exist proof)
-	bs, height, proof, err := GetWithProof(prt, k, brh-1, cl, cert)
+	bs, height, proof, err := GetWithProof(context.Background(), prt, k, brh-1, cl, cert)
 	require.NoError(err, "%#v", err)
 	// require.NotNil(proof)
 	// TODO: Ensure that *some* keys will be there, ensuring that proof is nil,
@@ -100,7 +101,7 @@ func _TestAppProofs(t *testing.T) {
 	require.Nil(bs)
 
 	// but given that block it is good
-	bs, height, proof, err = GetWithProof(prt, k, brh, cl, cert)
+	bs, height, proof, err = GetWithProof(context.Background(), prt, k, brh, cl, cert)
 	require.NoError(err, "%#v", err)
 	require.NotNil(proof)
 	require.Equal(height, brh)
@@ -111,7 +112,7 @@ func _TestAppProofs(t *testing.T) {
 
 	// Test non-existing key.
 	missing := []byte("my-missing-key")
-	bs, _, proof, err = GetWithProof(prt, missing, 0, cl, cert)
+	bs, _, proof, err = GetWithProof(context.Background(), prt, missing, 0, cl, cert)
 	require.NoError(err)
 	require.Nil(bs)
 	require.NotNil(proof)
@@ -125,10 +126,10 @@ func TestTxProofs(t *testing.T) {
 	assert, require := assert.New(t), require.New(t)
 
 	cl := client.NewLocal(node)
-	client.WaitForHeight(cl, 1, nil)
+	client.WaitForHeight(context.Background(), cl, 1, nil)
 
 	tx := kvstoreTx([]byte("key-a"), []byte("value-a"))
-	br, err := cl.BroadcastTxCommit(tx)
+	br, err := cl.BroadcastTxCommit(context.Background(), tx)
 	require.NoError(err, "%#v", err)
 	require.EqualValues(0, br.CheckTx.Code, "%#v", br.CheckTx)
 	require.EqualValues(0, br.DeliverTx.Code)
@@ -137,24 +138,32 @@ func TestTxProofs(t *testing.T) {
 	source := certclient.NewProvider(chainID, cl)
 	seed, err := source.LatestFullCommit(chainID, brh-2, brh-2)
 	require.NoError(err, "%#v", err)
-	cert := lite.NewBaseVerifier(chainID, seed.Height(), seed.Validators)
+	cert := lite.NewBaseVerifier(chainID, types.SignDomain{}, seed.Height(), seed.Validators)
 
 	// First let's make sure a bogus transaction hash returns a valid non-existence proof.
 	key := types.Tx([]byte("bogus")).Hash()
-	res, err := cl.Tx(key, true)
+	res, err := cl.Tx(context.Background(), key, true, false)
 	require.NotNil(err)
 	require.Contains(err.Error(), "not found")
 
 	// Now let's check with the real tx root hash.
 	key = types.Tx(tx).Hash()
-	res, err = cl.Tx(key, true)
+	res, err = cl.Tx(context.Background(), key, true, true)
 	require.NoError(err, "%#v", err)
 	require.NotNil(res)
 	keyHash := merkle.SimpleHashFromByteSlices([][]byte{key})
 	err = res.Proof.Validate(keyHash)
 	assert.NoError(err, "%#v", err)
 
-	commit, err := GetCertifiedCommit(br.Height, cl, cert)
+	commit, err := GetCertifiedCommit(context.Background(), br.Height, cl, cert)
 	require.Nil(err, "%#v", err)
 	require.Equal(res.Proof.RootHash, commit.Header.DataHash)
+
+	// The results of the block are in the next header.
+	nextCommit, err := GetCertifiedCommit(context.Background(), br.Height+1, cl, cert)
+	require.Nil(err, "%#v", err)
+	err = res.ResultProof.Validate(nextCommit.Header.LastResultsHash)
+	assert.NoError(err, "%#v", err)
+	assert.EqualValues(br.DeliverTx.Code, res.ResultProof.Data.Code)
+	assert.EqualValues(br.DeliverTx.Data, res.ResultProof.Data.Data)
 }
@@ -1,12 +1,18 @@
 package proxy
 
 import (
+	"bytes"
+	"context"
+	"errors"
+
+	abci "github.com/tendermint/tendermint/abci/types"
 	cmn "github.com/tendermint/tendermint/libs/common"
 
 	"github.com/tendermint/tendermint/crypto/merkle"
 	"github.com/tendermint/tendermint/lite"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
 )
 
 var _ rpcclient.Client = Wrapper{}
@@ -15,8 +21,9 @@ var _ rpcclient.Client = Wrapper{}
 // provable before passing it along. Allows you to make any rpcclient fully secure.
 type Wrapper struct {
 	rpcclient.Client
-	cert *lite.DynamicVerifier
-	prt  *merkle.ProofRuntime
+	cert     *lite.DynamicVerifier
+	prt      *merkle.ProofRuntime
+	archival []rpcclient.Client
 }
 
 // SecureClient uses a given Verifier to wrap an connection to an untrusted
@@ -25,7 +32,7 @@ type Wrapper struct {
 // If it is wrapping an HTTP rpcclient, it will also wrap the websocket interface
 func SecureClient(c rpcclient.Client, cert *lite.DynamicVerifier) Wrapper {
 	prt := defaultProofRuntime()
-	wrap := Wrapper{c, cert, prt}
+	wrap := Wrapper{c, cert, prt, nil}
 	// TODO: no longer possible as no more such interface exposed....
 	// if we wrap http client, then we can swap out the event switch to filter
 	// if hc, ok := c.(*rpcclient.HTTP); ok {
@@ -35,40 +42,91 @@ func SecureClient(c rpcclient.Client, cert *lite.DynamicVerifier) Wrapper {
 	return wrap
 }
 
+// WithArchivalNodes returns the wrapper retrying the queries, commits and
+// validators of the heights the node pruned, i.e. below its earliest block
+// height, against the archival nodes, in order. Their proofs are verified the
+// same way.
+func (w Wrapper) WithArchivalNodes(nodes ...rpcclient.Client) Wrapper {
+	w.archival = nodes
+	return w
+}
+
+// pruned returns true if the node pruned the height, which the archival nodes
+// may have.
+func (w Wrapper) pruned(ctx context.Context, height int64) bool {
+	if height <= 0 || len(w.archival) == 0 {
+		return false
+	}
+	status, err := w.Client.Status(ctx)
+	return err == nil && height < status.SyncInfo.EarliestBlockHeight
+}
+
 // ABCIQueryWithOptions exposes all options for the ABCI query and verifies the returned proof
-func (w Wrapper) ABCIQueryWithOptions(path string, data cmn.HexBytes,
+func (w Wrapper) ABCIQueryWithOptions(ctx context.Context, path string, data cmn.HexBytes,
 	opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
 
-	res, err := GetWithProofOptions(w.prt, path, data, opts, w.Client, w.cert)
-	return res, err
+	res, err := GetWithProofOptions(ctx, w.prt, path, data, opts, w.Client, w.cert)
+	if err == nil || !w.pruned(ctx, opts.Height) {
+		return res, err
+	}
+	for _, node := range w.archival {
+		if res, archivalErr := GetWithProofOptions(ctx, w.prt, path, data, opts, node, w.cert); archivalErr == nil {
+			return res, nil
+		}
+	}
+	return nil, err
 }
 
 // ABCIQuery uses default options for the ABCI query and verifies the returned proof
-func (w Wrapper) ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
-	return w.ABCIQueryWithOptions(path, data, rpcclient.DefaultABCIQueryOptions)
+func (w Wrapper) ABCIQuery(ctx context.Context, path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
+	return w.ABCIQueryWithOptions(ctx, path, data, rpcclient.DefaultABCIQueryOptions)
 }
 
-// Tx queries for a given tx and verifies the proof if it was requested
-func (w Wrapper) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
-	res, err := w.Client.Tx(hash, prove)
-	if !prove || err != nil {
+// Tx queries for a given tx and verifies the proofs if they were requested.
+// The result proof is verified against the header following the block of
+// the tx, so it waits for that block to be committed.
+func (w Wrapper) Tx(ctx context.Context, hash []byte, prove, proveResult bool) (*ctypes.ResultTx, error) {
+	res, err := w.Client.Tx(ctx, hash, prove, proveResult)
+	if err != nil {
 		return res, err
 	}
 	h := int64(res.Height)
-	sh, err := GetCertifiedCommit(h, w.Client, w.cert)
-	if err != nil {
-		return res, err
+	if prove {
+		sh, err := GetCertifiedCommit(ctx, h, w.Client, w.cert)
+		if err != nil {
+			return res, err
+		}
+		if err = res.Proof.Validate(sh.DataHash); err != nil {
+			return res, err
+		}
 	}
-	err = res.Proof.Validate(sh.DataHash)
-	return res, err
+	if proveResult {
+		sh, err := GetCertifiedCommit(ctx, h+1, w.Client, w.cert)
+		if err != nil {
+			return res, err
+		}
+		if err = res.ResultProof.Validate(sh.LastResultsHash); err != nil {
+			return res, err
+		}
+		if !resultMatches(res.ResultProof.Data, res.TxResult) {
+			return res, errors.New("tx result doesn't match the proven result")
+		}
+	}
+	return res, nil
+}
+
+// resultMatches returns true if the deterministic part of the DeliverTx
+// response is the proven result.
+func resultMatches(proven types.ABCIResult, result abci.ResponseDeliverTx) bool {
+	return bytes.Equal(proven.Bytes(), types.NewResultFromResponse(&result).Bytes())
 }
 
 // BlockchainInfo requests a list of headers and verifies them all...
 // Rather expensive.
 //
 // TODO: optimize this if used for anything needing performance
-func (w Wrapper) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
-	r, err := w.Client.BlockchainInfo(minHeight, maxHeight)
+func (w Wrapper) BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
+	r, err := w.Client.BlockchainInfo(ctx, minHeight, maxHeight)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +134,7 @@ func (w Wrapper) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlock
 	// go and verify every blockmeta in the result....
 	for _, meta := range r.BlockMetas {
 		// get a checkpoint to verify from
-		res, err := w.Commit(&meta.Header.Height)
+		res, err := w.Commit(ctx, &meta.Header.Height)
 		if err != nil {
 			return nil, err
 		}
@@ -91,13 +149,13 @@ func (w Wrapper) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlock
 }
 
 // Block returns an entire block and verifies all signatures
-func (w Wrapper) Block(height *int64) (*ctypes.ResultBlock, error) {
-	resBlock, err := w.Client.Block(height)
+func (w Wrapper) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	resBlock, err := w.Client.Block(ctx, height)
 	if err != nil {
 		return nil, err
 	}
 	// get a checkpoint to verify from
-	resCommit, err := w.Commit(height)
+	resCommit, err := w.Commit(ctx, height)
 	if err != nil {
 		return nil, err
 	}
@@ -118,9 +176,9 @@ func (w Wrapper) Block(height *int64) (*ctypes.ResultBlock, error) {
 // Commit downloads the Commit and certifies it with the lite.
 //
 // This is the foundation for all other verification in this module
-func (w Wrapper) Commit(height *int64) (*ctypes.ResultCommit, error) {
+func (w Wrapper) Commit(ctx context.Context, height *int64) (*ctypes.ResultCommit, error) {
 	if height == nil {
-		resStatus, err := w.Client.Status()
+		resStatus, err := w.Client.Status(ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -135,8 +193,16 @@ func (w Wrapper) Commit(height *int64) (*ctypes.ResultCommit, error) {
 		height = new(int64)
 		*height = resStatus.SyncInfo.LatestBlockHeight
 	}
-	rpcclient.WaitForHeight(w.Client, *height, nil)
-	res, err := w.Client.Commit(height)
+	rpcclient.WaitForHeight(ctx, w.Client, *height, nil)
+	res, err := w.Client.Commit(ctx, height)
+	if err != nil && w.pruned(ctx, *height) {
+		for _, node := range w.archival {
+			archivalRes, archivalErr := node.Commit(ctx, height)
+			if archivalErr == nil && w.cert.Verify(archivalRes.SignedHeader) == nil {
+				return archivalRes, nil
+			}
+		}
+	}
 	// if we got it, then verify it
 	if err == nil {
 		sh := res.SignedHeader
@@ -145,6 +211,35 @@ func (w Wrapper) Commit(height *int64) (*ctypes.ResultCommit, error) {
 	return res, err
 }
 
+// Validators returns the validators of the height, retried against the
+// archival nodes if the node pruned it. The whole sets of the archival nodes
+// are verified against the certified header of the height, their pages
+// aren't, as the ones of the node.
+func (w Wrapper) Validators(ctx context.Context, height *int64, page, perPage int) (*ctypes.ResultValidators, error) {
+	res, err := w.Client.Validators(ctx, height, page, perPage)
+	if err == nil || height == nil || !w.pruned(ctx, *height) {
+		return res, err
+	}
+	for _, node := range w.archival {
+		archivalRes, archivalErr := node.Validators(ctx, height, page, perPage)
+		if archivalErr == nil && (archivalRes.Count != archivalRes.Total || w.validatorsCertified(ctx, archivalRes)) {
+			return archivalRes, nil
+		}
+	}
+	return nil, err
+}
+
+// validatorsCertified returns true if the validators are the set of the
+// certified header of their height.
+func (w Wrapper) validatorsCertified(ctx context.Context, res *ctypes.ResultValidators) bool {
+	resCommit, err := w.Commit(ctx, &res.BlockHeight)
+	if err != nil {
+		return false
+	}
+	valSet := types.NewValidatorSet(res.Validators)
+	return bytes.Equal(valSet.Hash(), resCommit.SignedHeader.ValidatorsHash)
+}
+
 // // WrappedSwitch creates a websocket connection that auto-verifies any info
 // // coming through before passing it along.
 // //
@@ -0,0 +1,86 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/tendermint/rpc/client/mock"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// prunedStatus is the status of a node which pruned the heights below 10.
+var prunedStatus = &mock.StatusMock{Call: mock.Call{
+	Response: &ctypes.ResultStatus{SyncInfo: ctypes.SyncInfo{LatestBlockHeight: 20, EarliestBlockHeight: 10}},
+}}
+
+func TestWrapperArchivalNodes(t *testing.T) {
+	pruned := mock.Client{
+		ABCIClient: mock.ABCIMock{
+			Query: mock.Call{Response: abci.ResponseQuery{Code: 1, Log: "height pruned"}},
+		},
+		StatusClient: prunedStatus,
+	}
+	archival := mock.NewABCIRecorder(mock.ABCIMock{
+		Query: mock.Call{Response: abci.ResponseQuery{Code: 2, Log: "unknown key"}},
+	})
+	w := SecureClient(pruned, nil).WithArchivalNodes(mock.Client{ABCIClient: archival})
+
+	// the queries of the latest height are not retried
+	_, err := w.ABCIQueryWithOptions(context.Background(), "/key", []byte("key"), client.ABCIQueryOptions{Prove: true})
+	require.Error(t, err)
+	assert.True(t, IsErrQueryFailed(err))
+	assert.Empty(t, archival.Calls)
+
+	// the ones of a past height are, returning the error of the node if they
+	// fail on the archival nodes too
+	_, err = w.ABCIQueryWithOptions(context.Background(), "/key", []byte("key"), client.ABCIQueryOptions{Height: 5, Prove: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "height pruned")
+	require.Len(t, archival.Calls, 1)
+	assert.Equal(t, mock.QueryArgs{Path: "/key", Data: []byte("key"), Height: 5, Prove: true}, archival.Calls[0].Args)
+
+	// but not the ones of a height the node didn't prune
+	_, err = w.ABCIQueryWithOptions(context.Background(), "/key", []byte("key"), client.ABCIQueryOptions{Height: 15, Prove: true})
+	require.Error(t, err)
+	assert.Len(t, archival.Calls, 1)
+}
+
+// validatorsClient returns the validators set.
+type validatorsClient struct {
+	client.SignClient
+	res *ctypes.ResultValidators
+	err error
+}
+
+func (c validatorsClient) Validators(ctx context.Context, height *int64, page, perPage int) (*ctypes.ResultValidators, error) {
+	return c.res, c.err
+}
+
+func TestWrapperArchivalNodesValidators(t *testing.T) {
+	pruned := mock.Client{
+		SignClient:   validatorsClient{err: errors.New("height 5 is pruned, the lowest height available is 10")},
+		StatusClient: prunedStatus,
+	}
+	page := &ctypes.ResultValidators{BlockHeight: 5, Count: 1, Total: 2}
+	archival := mock.Client{SignClient: validatorsClient{res: page}}
+	w := SecureClient(pruned, nil).WithArchivalNodes(archival)
+
+	// the pages of the pruned heights are retried
+	height := int64(5)
+	res, err := w.Validators(context.Background(), &height, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, page, res)
+
+	// not the other heights
+	height = 15
+	_, err = w.Validators(context.Background(), &height, 1, 1)
+	assert.Error(t, err)
+	_, err = w.Validators(context.Background(), nil, 1, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package mempool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// DumpTxs writes the txs of the mempool to w, in order, each prefixed by its
+// length as a uvarint. It returns the number of txs written.
+func (mem *Mempool) DumpTxs(w io.Writer) (int, error) {
+	txs := mem.ReapMaxTxs(-1)
+
+	bw := bufio.NewWriter(w)
+	var buf [binary.MaxVarintLen64]byte
+	for _, tx := range txs {
+		n := binary.PutUvarint(buf[:], uint64(len(tx)))
+		if _, err := bw.Write(buf[:n]); err != nil {
+			return 0, err
+		}
+		if _, err := bw.Write(tx); err != nil {
+			return 0, err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, err
+	}
+	return len(txs), nil
+}
+
+// LoadTxs checks the txs read from r, written by DumpTxs, adding the valid
+// ones to the mempool. The txs already in the cache are skipped. It returns
+// the number of txs checked.
+func (mem *Mempool) LoadTxs(r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	checked := 0
+	for {
+		size, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return checked, nil
+		}
+		if err != nil {
+			return checked, err
+		}
+		if size > maxTxSize {
+			return checked, ErrTxTooLarge
+		}
+		tx := make(types.Tx, size)
+		if _, err := io.ReadFull(br, tx); err != nil {
+			return checked, fmt.Errorf("error reading tx: %v", err)
+		}
+
+		switch err := mem.CheckTx(tx, nil); err {
+		case nil:
+			checked++
+		case ErrTxInCache:
+		default:
+			return checked, err
+		}
+	}
+}
@@ -123,6 +123,62 @@ func PostCheckMaxGas(maxGas int64) PostCheckFunc {
 	}
 }
 
+// TxMode restricts the use of a tx added to the mempool.
+type TxMode byte
+
+const (
+	// TxModeDefault txs are gossiped to the peers and proposed.
+	TxModeDefault TxMode = iota
+	// TxModeLocal txs are proposed, but not gossiped to the peers.
+	TxModeLocal
+	// TxModeGossip txs are gossiped to the peers, but not proposed.
+	TxModeGossip
+)
+
+// SenderAttributeKey is the key of the attributes of the events of CheckTx
+// holding the senders of the tx, by which the txs of the mempool can be
+// filtered.
+const SenderAttributeKey = "sender"
+
+// TxFilter selects the txs of the mempool: by sender, one of the values of the
+// SenderAttributeKey attributes of the events of their CheckTx, by the gas
+// they want, and by how long they've been in the mempool. Its zero values
+// select all the txs.
+type TxFilter struct {
+	Sender string
+	MinGas int64
+	MinAge time.Duration
+}
+
+func (f TxFilter) matches(memTx *mempoolTx, now time.Time) bool {
+	if memTx.gasWanted < f.MinGas || now.Sub(memTx.added) < f.MinAge {
+		return false
+	}
+	if f.Sender == "" {
+		return true
+	}
+	for _, sender := range memTx.senders {
+		if sender == f.Sender {
+			return true
+		}
+	}
+	return false
+}
+
+// txSenders returns the values of the SenderAttributeKey attributes of the
+// events.
+func txSenders(events []abci.Event) []string {
+	var senders []string
+	for _, event := range events {
+		for _, attr := range event.Attributes {
+			if string(attr.Key) == SenderAttributeKey {
+				senders = append(senders, string(attr.Value))
+			}
+		}
+	}
+	return senders
+}
+
 // TxID is the hex encoded hash of the bytes as a types.Tx.
 func TxID(tx []byte) string {
 	return fmt.Sprintf("%X", types.Tx(tx).Hash())
@@ -147,10 +203,27 @@ type Mempool struct {
 	preCheck             PreCheckFunc
 	postCheck            PostCheckFunc
 
+	// Filters of the node, run after the ones derived from the state, which
+	// Update replaces.
+	customPreChecks  []PreCheckFunc
+	customPostChecks []PostCheckFunc
+
 	// Keep a cache of already-seen txs.
 	// This reduces the pressure on the proxyApp.
 	cache txCache
 
+	// Modes of the txs being checked, other than TxModeDefault.
+	txModesMtx sync.Mutex
+	txModes    map[[sha256.Size]byte]TxMode
+
+	// Txs of the mempool by their replacement key, if any.
+	replacementsMtx sync.Mutex
+	replacements    map[string]*clist.CElement
+
+	// Txs of the mempool by their hash.
+	txsByHashMtx sync.Mutex
+	txsByHash    map[string]*clist.CElement
+
 	// A log of mempool txs
 	wal *auto.AutoFile
 
@@ -177,6 +250,9 @@ func NewMempool(
 		rechecking:    0,
 		recheckCursor: nil,
 		recheckEnd:    nil,
+		txModes:       make(map[[sha256.Size]byte]TxMode),
+		replacements:  make(map[string]*clist.CElement),
+		txsByHash:     make(map[string]*clist.CElement),
 		logger:        log.NewNopLogger(),
 		metrics:       NopMetrics(),
 	}
@@ -216,6 +292,51 @@ func WithPostCheck(f PostCheckFunc) MempoolOption {
 	return func(mem *Mempool) { mem.postCheck = f }
 }
 
+// WithCustomPreChecks appends filters run, in order, after the one set by
+// WithPreCheck or Update, e.g. to screen the format of the signatures or a
+// blacklist without the round trip to the application. Unlike the latter,
+// they are kept across the updates.
+func WithCustomPreChecks(fs ...PreCheckFunc) MempoolOption {
+	return func(mem *Mempool) { mem.customPreChecks = append(mem.customPreChecks, fs...) }
+}
+
+// WithCustomPostChecks appends filters run, in order, after the one set by
+// WithPostCheck or Update. Unlike the latter, they are kept across the
+// updates.
+func WithCustomPostChecks(fs ...PostCheckFunc) MempoolOption {
+	return func(mem *Mempool) { mem.customPostChecks = append(mem.customPostChecks, fs...) }
+}
+
+// runPreChecks returns the error of the first pre check rejecting the tx.
+func (mem *Mempool) runPreChecks(tx types.Tx) error {
+	if mem.preCheck != nil {
+		if err := mem.preCheck(tx); err != nil {
+			return err
+		}
+	}
+	for _, preCheck := range mem.customPreChecks {
+		if err := preCheck(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPostChecks returns the error of the first post check rejecting the tx.
+func (mem *Mempool) runPostChecks(tx types.Tx, res *abci.ResponseCheckTx) error {
+	if mem.postCheck != nil {
+		if err := mem.postCheck(tx, res); err != nil {
+			return err
+		}
+	}
+	for _, postCheck := range mem.customPostChecks {
+		if err := postCheck(tx, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // WithMetrics sets the metrics.
 func WithMetrics(metrics *Metrics) MempoolOption {
 	return func(mem *Mempool) { mem.metrics = metrics }
@@ -282,6 +403,14 @@ func (mem *Mempool) Flush() {
 		mem.txs.Remove(e)
 		e.DetachPrev()
 	}
+
+	mem.replacementsMtx.Lock()
+	mem.replacements = make(map[string]*clist.CElement)
+	mem.replacementsMtx.Unlock()
+
+	mem.txsByHashMtx.Lock()
+	mem.txsByHash = make(map[string]*clist.CElement)
+	mem.txsByHashMtx.Unlock()
 }
 
 // TxsFront returns the first transaction in the ordered list for peer
@@ -304,6 +433,13 @@ func (mem *Mempool) TxsWaitChan() <-chan struct{} {
 //     It gets called from another goroutine.
 // CONTRACT: Either cb will get called, or err returned.
 func (mem *Mempool) CheckTx(tx types.Tx, cb func(*abci.Response)) (err error) {
+	return mem.CheckTxWithMode(tx, TxModeDefault, cb)
+}
+
+// CheckTxWithMode is like CheckTx, the tx being added to the mempool with
+// the given mode: it can be kept from being gossiped to the peers, or from
+// being proposed.
+func (mem *Mempool) CheckTxWithMode(tx types.Tx, mode TxMode, cb func(*abci.Response)) (err error) {
 	mem.proxyMtx.Lock()
 	// use defer to unlock mutex because application (*local client*) might panic
 	defer mem.proxyMtx.Unlock()
@@ -319,10 +455,8 @@ func (mem *Mempool) CheckTx(tx types.Tx, cb func(*abci.Response)) (err error) {
 		return ErrTxTooLarge
 	}
 
-	if mem.preCheck != nil {
-		if err := mem.preCheck(tx); err != nil {
-			return ErrPreCheck{err}
-		}
+	if err := mem.runPreChecks(tx); err != nil {
+		return ErrPreCheck{err}
 	}
 
 	// CACHE
@@ -349,6 +483,11 @@ func (mem *Mempool) CheckTx(tx types.Tx, cb func(*abci.Response)) (err error) {
 	if err = mem.proxyAppConn.Error(); err != nil {
 		return err
 	}
+	if mode != TxModeDefault {
+		mem.txModesMtx.Lock()
+		mem.txModes[sha256.Sum256(tx)] = mode
+		mem.txModesMtx.Unlock()
+	}
 	reqRes := mem.proxyAppConn.CheckTxAsync(tx)
 	if cb != nil {
 		reqRes.SetCallback(cb)
@@ -372,17 +511,28 @@ func (mem *Mempool) resCbNormal(req *abci.Request, res *abci.Response) {
 	switch r := res.Value.(type) {
 	case *abci.Response_CheckTx:
 		tx := req.GetCheckTx().Tx
-		var postCheckErr error
-		if mem.postCheck != nil {
-			postCheckErr = mem.postCheck(tx, r.CheckTx)
-		}
+		mode := mem.popTxMode(tx)
+		postCheckErr := mem.runPostChecks(tx, r.CheckTx)
 		if (r.CheckTx.Code == abci.CodeTypeOK) && postCheckErr == nil {
 			memTx := &mempoolTx{
-				height:    mem.height,
-				gasWanted: r.CheckTx.GasWanted,
-				tx:        tx,
+				height:         mem.height,
+				gasWanted:      r.CheckTx.GasWanted,
+				tx:             tx,
+				hash:           types.Tx(tx).Hash(),
+				mode:           mode,
+				replacementKey: r.CheckTx.ReplacementKey,
+				priority:       r.CheckTx.Priority,
+				senders:        txSenders(r.CheckTx.Events),
+				added:          time.Now(),
+			}
+			if !mem.addTx(memTx) {
+				mem.logger.Info("Rejected transaction not replacing the one of the same key",
+					"tx", TxID(tx), "key", fmt.Sprintf("%X", memTx.replacementKey))
+				mem.metrics.FailedTxs.Add(1)
+				// remove from cache (it might replace it later)
+				mem.cache.Remove(tx)
+				return
 			}
-			mem.txs.PushBack(memTx)
 			mem.logger.Info("Added good transaction",
 				"tx", TxID(tx),
 				"res", r,
@@ -390,7 +540,9 @@ func (mem *Mempool) resCbNormal(req *abci.Request, res *abci.Response) {
 				"total", mem.Size(),
 			)
 			mem.metrics.TxSizeBytes.Observe(float64(len(tx)))
-			mem.notifyTxsAvailable()
+			if mode != TxModeGossip {
+				mem.notifyTxsAvailable()
+			}
 		} else {
 			// ignore bad transaction
 			mem.logger.Info("Rejected bad transaction", "tx", TxID(tx), "res", r, "err", postCheckErr)
@@ -403,6 +555,86 @@ func (mem *Mempool) resCbNormal(req *abci.Request, res *abci.Response) {
 	}
 }
 
+// addTx adds a good tx to the mempool. A tx with a replacement key replaces
+// the tx of the same key if it has a higher priority, and isn't added
+// otherwise, in which case addTx returns false.
+func (mem *Mempool) addTx(memTx *mempoolTx) bool {
+	if len(memTx.replacementKey) == 0 {
+		mem.indexTxHash(mem.txs.PushBack(memTx))
+		return true
+	}
+
+	mem.replacementsMtx.Lock()
+	defer mem.replacementsMtx.Unlock()
+
+	key := string(memTx.replacementKey)
+	if e, ok := mem.replacements[key]; ok {
+		replacedTx := e.Value.(*mempoolTx)
+		if memTx.priority <= replacedTx.priority {
+			return false
+		}
+		mem.txs.Remove(e)
+		e.DetachPrev()
+		mem.forgetTxHash(e)
+		// NOTE: we keep the replaced tx in the cache, so it isn't added back.
+		mem.logger.Info("Replaced transaction", "tx", TxID(replacedTx.tx), "by", TxID(memTx.tx))
+	}
+	e := mem.txs.PushBack(memTx)
+	mem.replacements[key] = e
+	mem.indexTxHash(e)
+	return true
+}
+
+// indexTxHash indexes a tx added to the mempool by its hash.
+func (mem *Mempool) indexTxHash(e *clist.CElement) {
+	mem.txsByHashMtx.Lock()
+	defer mem.txsByHashMtx.Unlock()
+
+	mem.txsByHash[string(e.Value.(*mempoolTx).hash)] = e
+}
+
+// forgetTxHash forgets the hash of a tx removed from the mempool.
+func (mem *Mempool) forgetTxHash(e *clist.CElement) {
+	mem.txsByHashMtx.Lock()
+	defer mem.txsByHashMtx.Unlock()
+
+	key := string(e.Value.(*mempoolTx).hash)
+	if mem.txsByHash[key] == e {
+		delete(mem.txsByHash, key)
+	}
+}
+
+// forgetReplacementKey forgets the replacement key of a tx removed from the
+// mempool.
+func (mem *Mempool) forgetReplacementKey(e *clist.CElement) {
+	memTx := e.Value.(*mempoolTx)
+	if len(memTx.replacementKey) == 0 {
+		return
+	}
+
+	mem.replacementsMtx.Lock()
+	defer mem.replacementsMtx.Unlock()
+
+	key := string(memTx.replacementKey)
+	if mem.replacements[key] == e {
+		delete(mem.replacements, key)
+	}
+}
+
+// popTxMode returns the mode of a tx being checked, and forgets it.
+func (mem *Mempool) popTxMode(tx types.Tx) TxMode {
+	mem.txModesMtx.Lock()
+	defer mem.txModesMtx.Unlock()
+
+	if len(mem.txModes) == 0 {
+		return TxModeDefault
+	}
+	txHash := sha256.Sum256(tx)
+	mode := mem.txModes[txHash]
+	delete(mem.txModes, txHash)
+	return mode
+}
+
 func (mem *Mempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 	switch r := res.Value.(type) {
 	case *abci.Response_CheckTx:
@@ -414,10 +646,7 @@ func (mem *Mempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 				memTx.tx,
 				tx))
 		}
-		var postCheckErr error
-		if mem.postCheck != nil {
-			postCheckErr = mem.postCheck(tx, r.CheckTx)
-		}
+		postCheckErr := mem.runPostChecks(tx, r.CheckTx)
 		if (r.CheckTx.Code == abci.CodeTypeOK) && postCheckErr == nil {
 			// Good, nothing to do.
 		} else {
@@ -425,6 +654,8 @@ func (mem *Mempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 			mem.logger.Info("Tx is no longer valid", "tx", TxID(tx), "res", r, "err", postCheckErr)
 			mem.txs.Remove(mem.recheckCursor)
 			mem.recheckCursor.DetachPrev()
+			mem.forgetReplacementKey(mem.recheckCursor)
+			mem.forgetTxHash(mem.recheckCursor)
 
 			// remove from cache (it might be good later)
 			mem.cache.Remove(tx)
@@ -439,8 +670,8 @@ func (mem *Mempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 			atomic.StoreInt32(&mem.rechecking, 0)
 			mem.logger.Info("Done rechecking txs")
 
-			// incase the recheck removed all txs
-			if mem.Size() > 0 {
+			// incase the recheck removed all the txs to propose
+			if mem.hasProposableTxs() {
 				mem.notifyTxsAvailable()
 			}
 		}
@@ -470,10 +701,22 @@ func (mem *Mempool) notifyTxsAvailable() {
 	}
 }
 
+// hasProposableTxs returns true if the mempool has txs to reap, i.e. other
+// than the TxModeGossip ones.
+func (mem *Mempool) hasProposableTxs() bool {
+	for e := mem.txs.Front(); e != nil; e = e.Next() {
+		if e.Value.(*mempoolTx).mode != TxModeGossip {
+			return true
+		}
+	}
+	return false
+}
+
 // ReapMaxBytesMaxGas reaps transactions from the mempool up to maxBytes bytes total
 // with the condition that the total gasWanted must be less than maxGas.
 // If both maxes are negative, there is no cap on the size of all returned
 // transactions (~ all available transactions).
+// The TxModeGossip transactions are not reaped.
 func (mem *Mempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 	mem.proxyMtx.Lock()
 	defer mem.proxyMtx.Unlock()
@@ -491,6 +734,9 @@ func (mem *Mempool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 	txs := make([]types.Tx, 0, mem.txs.Len())
 	for e := mem.txs.Front(); e != nil; e = e.Next() {
 		memTx := e.Value.(*mempoolTx)
+		if memTx.mode == TxModeGossip {
+			continue
+		}
 		// Check total size requirement
 		aminoOverhead := types.ComputeAminoOverhead(memTx.tx, 1)
 		if maxBytes > -1 && totalBytes+int64(len(memTx.tx))+aminoOverhead > maxBytes {
@@ -535,6 +781,48 @@ func (mem *Mempool) ReapMaxTxs(max int) types.Txs {
 	return txs
 }
 
+// ReapFilteredTxs returns the txs of the mempool selected by the filter, in
+// order, up to max of them. If max is negative, there is no cap.
+func (mem *Mempool) ReapFilteredTxs(filter TxFilter, max int) types.Txs {
+	mem.proxyMtx.Lock()
+	defer mem.proxyMtx.Unlock()
+
+	if max < 0 {
+		max = mem.txs.Len()
+	}
+
+	for atomic.LoadInt32(&mem.rechecking) > 0 {
+		// TODO: Something better?
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	now := time.Now()
+	txs := make([]types.Tx, 0, cmn.MinInt(mem.txs.Len(), max))
+	for e := mem.txs.Front(); e != nil && len(txs) < max; e = e.Next() {
+		memTx := e.Value.(*mempoolTx)
+		if filter.matches(memTx, now) {
+			txs = append(txs, memTx.tx)
+		}
+	}
+	return txs
+}
+
+// TxsByHashes returns the transactions of the mempool with the given hashes,
+// nil for the ones it doesn't have. It doesn't wait for the txs being checked
+// or updated.
+func (mem *Mempool) TxsByHashes(hashes [][]byte) types.Txs {
+	mem.txsByHashMtx.Lock()
+	defer mem.txsByHashMtx.Unlock()
+
+	txs := make(types.Txs, len(hashes))
+	for i, hash := range hashes {
+		if e, ok := mem.txsByHash[string(hash)]; ok {
+			txs[i] = e.Value.(*mempoolTx).tx
+		}
+	}
+	return txs
+}
+
 // Update informs the mempool that the given txs were committed and can be discarded.
 // NOTE: this should be called *after* block is committed by consensus.
 // NOTE: unsafe; Lock/Unlock must be managed by caller
@@ -572,7 +860,7 @@ func (mem *Mempool) Update(
 			// At this point, mem.txs are being rechecked.
 			// mem.recheckCursor re-scans mem.txs and possibly removes some txs.
 			// Before mem.Reap(), we should wait for mem.recheckCursor to be nil.
-		} else {
+		} else if mem.hasProposableTxs() {
 			mem.notifyTxsAvailable()
 		}
 	}
@@ -598,10 +886,22 @@ func (mem *Mempool) removeTxs(txs types.Txs) []types.Tx {
 			// remove from clist
 			mem.txs.Remove(e)
 			e.DetachPrev()
+			mem.forgetReplacementKey(e)
+			mem.forgetTxHash(e)
 
 			// NOTE: we don't remove committed txs from the cache.
 			continue
 		}
+		// Evict the tx at random, for soak testing.
+		if ratio := mem.config.ChaosEvictRatio; ratio > 0 && cmn.RandFloat64() < ratio {
+			mem.txs.Remove(e)
+			e.DetachPrev()
+			mem.forgetReplacementKey(e)
+			mem.forgetTxHash(e)
+			mem.cache.Remove(memTx.tx)
+			mem.logger.Debug("Evicted tx (chaos)", "tx", TxID(memTx.tx))
+			continue
+		}
 		txsLeft = append(txsLeft, memTx.tx)
 	}
 	return txsLeft
@@ -631,6 +931,14 @@ type mempoolTx struct {
 	height    int64    // height that this tx had been validated in
 	gasWanted int64    // amount of gas this tx states it will require
 	tx        types.Tx //
+	hash      []byte   // of the tx, its key in txsByHash
+	mode      TxMode   // whether it is gossiped and proposed
+
+	replacementKey []byte // txs of the same key replace each other
+	priority       int64  // a tx replaces one of a lower priority
+
+	senders []string  // from the events of its CheckTx
+	added   time.Time // to the mempool
 }
 
 // Height returns the height for this transaction
@@ -1,9 +1,11 @@
 package mempool
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -171,6 +173,41 @@ func TestMempoolFilters(t *testing.T) {
 	}
 }
 
+func TestMempoolCustomFilters(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	blacklisted := types.Tx("blacklisted")
+	WithCustomPreChecks(func(tx types.Tx) error {
+		if bytes.Equal(tx, blacklisted) {
+			return errors.New("blacklisted")
+		}
+		return nil
+	})(mempool)
+	WithCustomPostChecks(func(tx types.Tx, res *abci.ResponseCheckTx) error {
+		if bytes.HasPrefix(tx, []byte("bad")) {
+			return errors.New("bad")
+		}
+		return nil
+	})(mempool)
+
+	// the custom filters are kept across the updates
+	mempool.Update(1, []types.Tx{}, PreCheckAminoMaxBytes(22), PostCheckMaxGas(-1))
+
+	err := mempool.CheckTx(blacklisted, nil)
+	assert.True(t, IsPreCheckError(err), "%v", err)
+	require.NoError(t, mempool.CheckTx(types.Tx("bad tx"), nil))
+	require.NoError(t, mempool.CheckTx(types.Tx("good tx"), nil))
+	err = mempool.CheckTx(types.Tx("a tx too large for the max bytes"), nil)
+	assert.True(t, IsPreCheckError(err), "%v", err)
+
+	txs := mempool.ReapMaxTxs(-1)
+	require.Len(t, txs, 1)
+	assert.Equal(t, types.Tx("good tx"), txs[0])
+}
+
 func TestMempoolUpdateAddsTxsToCache(t *testing.T) {
 	app := kvstore.NewKVStoreApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -183,6 +220,176 @@ func TestMempoolUpdateAddsTxsToCache(t *testing.T) {
 	}
 }
 
+func TestMempoolTxsByHashes(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	txs := checkTxs(t, mempool, 3)
+	missing := types.Tx([]byte{0x01})
+	found := mempool.TxsByHashes([][]byte{txs[2].Hash(), missing.Hash(), txs[0].Hash()})
+	assert.Equal(t, types.Txs{txs[2], nil, txs[0]}, found)
+
+	// the committed txs are forgotten
+	mempool.Lock()
+	err := mempool.Update(1, types.Txs{txs[0]}, nil, nil)
+	mempool.Unlock()
+	require.NoError(t, err)
+	found = mempool.TxsByHashes([][]byte{txs[0].Hash(), txs[1].Hash()})
+	assert.Equal(t, types.Txs{nil, txs[1]}, found)
+
+	mempool.Flush()
+	assert.Equal(t, types.Txs{nil}, mempool.TxsByHashes([][]byte{txs[1].Hash()}))
+}
+
+func TestMempoolTxModes(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	txs := types.Txs{[]byte{0x01}, []byte{0x02}, []byte{0x03}}
+	require.NoError(t, mempool.CheckTx(txs[0], nil))
+	require.NoError(t, mempool.CheckTxWithMode(txs[1], TxModeLocal, nil))
+	require.NoError(t, mempool.CheckTxWithMode(txs[2], TxModeGossip, nil))
+	assert.Empty(t, mempool.txModes)
+
+	// the gossip only txs are not proposed
+	assert.Equal(t, txs, mempool.ReapMaxTxs(-1))
+	assert.Equal(t, txs[:2], mempool.ReapMaxBytesMaxGas(-1, -1))
+}
+
+// replacementApp replaces the txs of the same first byte, by the second byte
+// as priority.
+type replacementApp struct {
+	abci.BaseApplication
+}
+
+func (replacementApp) CheckTx(tx []byte) abci.ResponseCheckTx {
+	return abci.ResponseCheckTx{
+		Code:           abci.CodeTypeOK,
+		ReplacementKey: tx[:1],
+		Priority:       int64(tx[1]),
+	}
+}
+
+func TestMempoolReplaceTxs(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(replacementApp{})
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	checkTxs := func(txs ...types.Tx) {
+		for _, tx := range txs {
+			require.NoError(t, mempool.CheckTx(tx, nil))
+		}
+	}
+	checkTxs([]byte{0x01, 0x01}, []byte{0x02, 0x05})
+	// replaces the first tx
+	checkTxs([]byte{0x01, 0x02})
+	// don't replace txs of a higher or the same priority
+	checkTxs([]byte{0x02, 0x04}, []byte{0x02, 0x05, 0x00})
+	assert.Equal(t, types.Txs{{0x02, 0x05}, {0x01, 0x02}}, mempool.ReapMaxTxs(-1))
+
+	// the rejected txs can be checked again
+	checkTxs([]byte{0x02, 0x05, 0x00})
+	assert.Equal(t, 2, mempool.Size())
+
+	// a committed tx can be replaced by the next one of its key
+	mempool.Lock()
+	require.NoError(t, mempool.Update(1, types.Txs{{0x01, 0x02}}, nil, nil))
+	mempool.Unlock()
+	checkTxs([]byte{0x01, 0x00})
+	assert.Equal(t, types.Txs{{0x02, 0x05}, {0x01, 0x00}}, mempool.ReapMaxTxs(-1))
+
+	mempool.Flush()
+	assert.Empty(t, mempool.replacements)
+}
+
+// senderApp sends the txs from the sender of their first byte, with the gas of
+// their second byte.
+type senderApp struct {
+	abci.BaseApplication
+}
+
+func (senderApp) CheckTx(tx []byte) abci.ResponseCheckTx {
+	return abci.ResponseCheckTx{
+		Code:      abci.CodeTypeOK,
+		GasWanted: int64(tx[1]),
+		Events: []abci.Event{
+			{Type: "transfer", Attributes: []cmn.KVPair{{Key: []byte(SenderAttributeKey), Value: tx[:1]}}},
+		},
+	}
+}
+
+func TestMempoolReapFilteredTxs(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(senderApp{})
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	txs := types.Txs{{'a', 1}, {'b', 5}, {'a', 10}}
+	for _, tx := range txs {
+		require.NoError(t, mempool.CheckTx(tx, nil))
+	}
+
+	assert.Equal(t, txs, mempool.ReapFilteredTxs(TxFilter{}, -1))
+	assert.Equal(t, txs[:2], mempool.ReapFilteredTxs(TxFilter{}, 2))
+	assert.Equal(t, types.Txs{txs[0], txs[2]}, mempool.ReapFilteredTxs(TxFilter{Sender: "a"}, -1))
+	assert.Equal(t, txs[1:], mempool.ReapFilteredTxs(TxFilter{MinGas: 5}, -1))
+	assert.Equal(t, txs[2:], mempool.ReapFilteredTxs(TxFilter{Sender: "a", MinGas: 5}, -1))
+	assert.Empty(t, mempool.ReapFilteredTxs(TxFilter{Sender: "c"}, -1))
+	assert.Empty(t, mempool.ReapFilteredTxs(TxFilter{MinAge: time.Hour}, -1))
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, mempool.CheckTx(types.Tx{'b', 1}, nil))
+	assert.Equal(t, txs, mempool.ReapFilteredTxs(TxFilter{MinAge: 50 * time.Millisecond}, -1))
+}
+
+func TestMempoolDumpLoadTxs(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	txs := checkTxs(t, mempool, 10)
+	buf := new(bytes.Buffer)
+	n, err := mempool.DumpTxs(buf)
+	require.NoError(t, err)
+	assert.Equal(t, 10, n)
+
+	mempool2, cleanup2 := newMempoolWithApp(cc)
+	defer cleanup2()
+	n, err = mempool2.LoadTxs(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, 10, n)
+	assert.Equal(t, txs, mempool2.ReapMaxTxs(-1))
+
+	// the txs already in the cache are skipped
+	n, err = mempool2.LoadTxs(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// truncated dump
+	_, err = mempool2.LoadTxs(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	assert.Error(t, err)
+}
+
+func TestMempoolChaosEvict(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	txs := checkTxs(t, mempool, 10)
+	mempool.config.ChaosEvictRatio = 1
+	require.NoError(t, mempool.Update(1, txs[:2], nil, nil))
+	assert.Equal(t, 0, mempool.Size())
+
+	// the evicted txs can be resubmitted
+	require.NoError(t, mempool.CheckTx(txs[2], nil))
+	assert.Equal(t, 1, mempool.Size())
+}
+
 func TestTxsAvailable(t *testing.T) {
 	app := kvstore.NewKVStoreApplication()
 	cc := proxy.NewLocalClientCreator(app)
@@ -227,6 +434,28 @@ func TestTxsAvailable(t *testing.T) {
 	ensureNoFire(t, mempool.TxsAvailable(), timeoutMS)
 }
 
+func TestTxsAvailableGossipOnly(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	mempool.EnableTxsAvailable()
+
+	timeoutMS := 500
+
+	// the gossip only txs are not proposed, so they don't fire
+	require.NoError(t, mempool.CheckTxWithMode([]byte{0x01}, TxModeGossip, nil))
+	ensureNoFire(t, mempool.TxsAvailable(), timeoutMS)
+
+	// neither when they're left after an update
+	require.NoError(t, mempool.Update(1, nil, nil, nil))
+	ensureNoFire(t, mempool.TxsAvailable(), timeoutMS)
+
+	// a tx to propose fires
+	require.NoError(t, mempool.CheckTx([]byte{0x02}, nil))
+	ensureFire(t, mempool.TxsAvailable(), timeoutMS)
+}
+
 func TestSerialReap(t *testing.T) {
 	app := counter.NewCounterApplication(true)
 	app.SetOption(abci.RequestSetOption{Key: "serial", Value: "on"})
@@ -0,0 +1,50 @@
+// +build gofuzz
+
+package mempool
+
+import (
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/p2p/dummy"
+	"github.com/tendermint/tendermint/proxy"
+)
+
+var (
+	fuzzReactor *MempoolReactor
+	fuzzPeer    p2p.Peer
+)
+
+// FuzzReactorReceive passes data to the Receive method of the reactor. It
+// returns 1 for the data decoding to a message, to give it priority.
+//
+// Build it with go-fuzz-build -func FuzzReactorReceive.
+func FuzzReactorReceive(data []byte) int {
+	if fuzzReactor == nil {
+		fuzzReactor, fuzzPeer = newFuzzReactor()
+	}
+	fuzzReactor.Receive(MempoolChannel, fuzzPeer, data)
+	if _, err := decodeMsg(data); err != nil {
+		return 0
+	}
+	return 1
+}
+
+func newFuzzReactor() (*MempoolReactor, p2p.Peer) {
+	appConnMem, err := proxy.NewLocalClientCreator(kvstore.NewKVStoreApplication()).NewABCIClient()
+	if err != nil {
+		panic(err)
+	}
+	if err := appConnMem.Start(); err != nil {
+		panic(err)
+	}
+	config := cfg.DefaultMempoolConfig()
+	memR := NewMempoolReactor(config, NewMempool(config, appConnMem, 0))
+
+	p2pConfig := cfg.DefaultP2PConfig()
+	sw := p2p.NewSwitch(p2pConfig, p2p.NewMultiplexTransport(p2p.DefaultNodeInfo{},
+		p2p.NodeKey{PrivKey: ed25519.GenPrivKey()}, p2p.MConnConfig(p2pConfig)))
+	sw.AddReactor("MEMPOOL", memR)
+	return memR, dummy.NewPeer()
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/fortytw2/leaktest"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/go-kit/kit/log/term"
 
@@ -128,6 +129,32 @@ func TestReactorBroadcastTxMessage(t *testing.T) {
 	waitForTxs(t, txs, reactors)
 }
 
+func TestReactorNoBroadcastLocalTxs(t *testing.T) {
+	config := cfg.TestConfig()
+	const N = 2
+	reactors := makeAndConnectMempoolReactors(config, N)
+	defer func() {
+		for _, r := range reactors {
+			r.Stop()
+		}
+	}()
+	for _, r := range reactors {
+		for _, peer := range r.Switch.Peers().List() {
+			peer.Set(types.PeerStateKey, peerState{1})
+		}
+	}
+
+	// the local txs are not received by the other reactor, unlike the
+	// following ones
+	local := types.Txs{[]byte{0x01}, []byte{0x02}}
+	for _, tx := range local {
+		require.NoError(t, reactors[0].Mempool.CheckTxWithMode(tx, TxModeLocal, nil))
+	}
+	txs := checkTxs(t, reactors[0].Mempool, 10)
+	waitForTxs(t, txs, reactors[1:])
+	assert.Equal(t, len(local)+len(txs), reactors[0].Mempool.Size())
+}
+
 func TestBroadcastTxForPeerStopsWhenPeerStops(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping test in short mode.")
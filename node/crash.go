@@ -0,0 +1,140 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"time"
+
+	cs "github.com/tendermint/tendermint/consensus"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// number of recent log lines in the crash reports
+	crashReportLogLines = 100
+
+	// time to wait for the consensus state, which may be locked by the panic
+	crashReportRoundStateTimeout = time.Second
+)
+
+// CrashReport is written to the crash directory when a reactor or an RPC
+// handler panics.
+type CrashReport struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Panic  string    `json:"panic"`
+	Stack  string    `json:"stack"`
+
+	// consensus HRS, empty if the consensus state couldn't be read in time
+	Height int64  `json:"height"`
+	Round  int    `json:"round"`
+	Step   string `json:"step"`
+
+	// last info and error lines logged by the node, the oldest first, if
+	// reported
+	Logs []string `json:"logs,omitempty"`
+}
+
+// crashReporter writes the crash reports of the panics, and shuts the node
+// down if configured to.
+type crashReporter struct {
+	dir      string // "" if the reports are disabled
+	shutdown bool
+
+	recent         *log.RecentLogs // nil if the logs are not reported
+	logger         log.Logger
+	consensusState *cs.ConsensusState // nil until created
+
+	shutdownOnce sync.Once
+}
+
+func newCrashReporter(dir string, shutdown, reportLogs bool, logger log.Logger) *crashReporter {
+	cr := &crashReporter{
+		dir:      dir,
+		shutdown: shutdown,
+		logger:   logger,
+	}
+	if dir != "" && reportLogs {
+		cr.recent = log.NewRecentLogs(crashReportLogLines)
+	}
+	return cr
+}
+
+// onPanic is the panic handler of the switch and the RPC server.
+func (cr *crashReporter) onPanic(source string, v interface{}, stack []byte) {
+	if cr.dir != "" {
+		report := cr.report(source, v, stack)
+		if path, err := cr.write(report); err != nil {
+			cr.logger.Error("Failed to write the crash report", "source", source, "err", err)
+		} else {
+			cr.logger.Error("Wrote the crash report", "source", source, "path", path)
+		}
+	}
+
+	if cr.shutdown {
+		cr.shutdownOnce.Do(func() {
+			cr.logger.Error("Shutting down after panic", "source", source)
+			// stop the node as on SIGTERM, and exit if nothing traps it
+			if err := cmn.Kill(); err != nil {
+				cmn.Exit(fmt.Sprintf("Panic in %s: %v", source, v))
+			}
+		})
+	}
+}
+
+func (cr *crashReporter) report(source string, v interface{}, stack []byte) CrashReport {
+	report := CrashReport{
+		Time:   time.Now().UTC(),
+		Source: source,
+		Panic:  fmt.Sprintf("%v", v),
+		Stack:  string(stack),
+	}
+	if cr.recent != nil {
+		report.Logs = cr.recent.Lines()
+	}
+
+	if cr.consensusState != nil {
+		type hrs struct {
+			height int64
+			round  int
+			step   string
+		}
+		hrsCh := make(chan hrs, 1)
+		go func() {
+			rs := cr.consensusState.GetRoundState()
+			hrsCh <- hrs{rs.Height, rs.Round, rs.Step.String()}
+		}()
+		select {
+		case hrs := <-hrsCh:
+			report.Height, report.Round, report.Step = hrs.height, hrs.round, hrs.step
+		case <-time.After(crashReportRoundStateTimeout):
+		}
+	}
+	return report
+}
+
+// write writes the report to a new file of the crash directory, and returns
+// its path.
+func (cr *crashReporter) write(report CrashReport) (string, error) {
+	if err := cmn.EnsureDir(cr.dir, 0700); err != nil {
+		return "", err
+	}
+	bz, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(cr.dir, fmt.Sprintf("crash-%s.json", report.Time.Format("20060102T150405.000000000Z")))
+	if err := ioutil.WriteFile(path, bz, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// enabled returns whether the panics are reported or shut the node down.
+func (cr *crashReporter) enabled() bool {
+	return cr.dir != "" || cr.shutdown
+}
@@ -0,0 +1,68 @@
+package node
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestNodeCrashReport(t *testing.T) {
+	config := cfg.ResetTestRoot("node_crash_test")
+	defer os.RemoveAll(config.RootDir)
+	config.CrashReportLogs = true
+
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	require.True(t, n.crashReporter.enabled())
+
+	n.Logger.Info("Before the panic")
+	n.crashReporter.onPanic("test", "boom", []byte("stack"))
+
+	files, err := ioutil.ReadDir(config.CrashReportDir())
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasPrefix(files[0].Name(), "crash-"))
+
+	bz, err := ioutil.ReadFile(filepath.Join(config.CrashReportDir(), files[0].Name()))
+	require.NoError(t, err)
+	var report CrashReport
+	require.NoError(t, json.Unmarshal(bz, &report))
+	assert.Equal(t, "test", report.Source)
+	assert.Equal(t, "boom", report.Panic)
+	assert.Equal(t, "stack", report.Stack)
+	assert.EqualValues(t, 1, report.Height)
+	assert.Equal(t, "RoundStepNewHeight", report.Step)
+	require.NotEmpty(t, report.Logs)
+	assert.Contains(t, report.Logs[len(report.Logs)-1], "Before the panic")
+}
+
+func TestNodeCrashReportNoLogs(t *testing.T) {
+	config := cfg.ResetTestRoot("node_crash_test")
+	defer os.RemoveAll(config.RootDir)
+
+	// the logs are only kept if reported
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	require.True(t, n.crashReporter.enabled())
+	require.Nil(t, n.crashReporter.recent)
+
+	n.crashReporter.onPanic("test", "boom", []byte("stack"))
+	files, err := ioutil.ReadDir(config.CrashReportDir())
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	bz, err := ioutil.ReadFile(filepath.Join(config.CrashReportDir(), files[0].Name()))
+	require.NoError(t, err)
+	var report CrashReport
+	require.NoError(t, json.Unmarshal(bz, &report))
+	assert.Equal(t, "boom", report.Panic)
+	assert.Empty(t, report.Logs)
+}
@@ -0,0 +1,90 @@
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+
+	bc "github.com/tendermint/tendermint/blockchain"
+)
+
+// DebugState is the internal state of the services of the node, served by
+// /debug/state, for tools to diagnose where the startup is stuck.
+//
+// NOTE: the handshake with the app, which replays the blocks it's missing, is
+// done by NewNode, before the RPC server is started.
+type DebugState struct {
+	// whether the services and the reactors are running, by name
+	Services map[string]bool `json:"services"`
+	Reactors map[string]bool `json:"reactors"`
+
+	FastSync  bc.FastSyncStatus   `json:"fast_sync"`
+	Consensus DebugConsensusState `json:"consensus"`
+}
+
+// DebugConsensusState is the state of the consensus of the node.
+type DebugConsensusState struct {
+	WaitingForFastSync bool   `json:"waiting_for_fast_sync"`
+	WALCatchup         string `json:"wal_catchup"`
+	// nil until the WAL is open
+	WAL *WALPosition `json:"wal"`
+
+	Height int64  `json:"height"`
+	Round  int    `json:"round"`
+	Step   string `json:"step"`
+}
+
+// WALPosition is the position of the consensus WAL, in its files.
+type WALPosition struct {
+	MinIndex  int   `json:"min_index"`
+	MaxIndex  int   `json:"max_index"`
+	HeadSize  int64 `json:"head_size"`
+	TotalSize int64 `json:"total_size"`
+}
+
+// DebugState returns the internal state of the services of the node.
+func (n *Node) DebugState() DebugState {
+	state := DebugState{
+		Services: map[string]bool{
+			"proxy_app": n.proxyApp.IsRunning(),
+			"event_bus": n.eventBus.IsRunning(),
+			"indexer":   n.indexerService.IsRunning(),
+			"switch":    n.sw.IsRunning(),
+			"consensus": n.consensusState.IsRunning(),
+		},
+		Reactors: make(map[string]bool),
+		FastSync: n.bcReactor.FastSyncStatus(),
+	}
+	if n.memoryMonitor != nil {
+		state.Services["memory_monitor"] = n.memoryMonitor.IsRunning()
+	}
+	for name, reactor := range n.sw.Reactors() {
+		state.Reactors[name] = reactor.IsRunning()
+	}
+
+	rs := n.consensusState.GetRoundState()
+	state.Consensus = DebugConsensusState{
+		WaitingForFastSync: n.consensusReactor.FastSync(),
+		WALCatchup:         n.consensusState.WALCatchupStatus().String(),
+		Height:             rs.Height,
+		Round:              rs.Round,
+		Step:               rs.Step.String(),
+	}
+	if info, ok := n.consensusState.WALGroupInfo(); ok {
+		state.Consensus.WAL = &WALPosition{
+			MinIndex:  info.MinIndex,
+			MaxIndex:  info.MaxIndex,
+			HeadSize:  info.HeadSize,
+			TotalSize: info.TotalSize,
+		}
+	}
+	return state
+}
+
+func (n *Node) debugStateHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(n.DebugState()); err != nil {
+		n.Logger.Error("Failed to write the debug state", "err", err)
+	}
+}
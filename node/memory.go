@@ -0,0 +1,81 @@
+package node
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+const (
+	memoryCheckInterval = time.Second
+
+	// The RPC requests are rejected while the heap exceeds the soft limit of
+	// the memory budget, until it's back under the resume limit.
+	memorySoftLimit   = 0.9
+	memoryResumeLimit = 0.8
+)
+
+// memoryMonitor checks the memory used against the memory budget, to shed
+// the RPC load while it exceeds it.
+type memoryMonitor struct {
+	cmn.BaseService
+
+	softLimit   uint64
+	resumeLimit uint64
+	overloaded  int32 // atomic
+	heapAlloc   func() uint64
+}
+
+func newMemoryMonitor(budget int64) *memoryMonitor {
+	m := &memoryMonitor{
+		softLimit:   uint64(float64(budget) * memorySoftLimit),
+		resumeLimit: uint64(float64(budget) * memoryResumeLimit),
+		heapAlloc:   heapAlloc,
+	}
+	m.BaseService = *cmn.NewBaseService(nil, "MemoryMonitor", m)
+	return m
+}
+
+// OnStart implements cmn.Service.
+func (m *memoryMonitor) OnStart() error {
+	go m.checkRoutine()
+	return nil
+}
+
+// Overloaded returns true if the memory used exceeds the soft limit.
+func (m *memoryMonitor) Overloaded() bool {
+	return atomic.LoadInt32(&m.overloaded) == 1
+}
+
+func (m *memoryMonitor) checkRoutine() {
+	ticker := time.NewTicker(memoryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.check()
+		case <-m.Quit():
+			return
+		}
+	}
+}
+
+func (m *memoryMonitor) check() {
+	alloc := m.heapAlloc()
+	switch {
+	case !m.Overloaded() && alloc > m.softLimit:
+		m.Logger.Error("Memory budget exceeded, rejecting the RPC requests", "heapAlloc", alloc)
+		atomic.StoreInt32(&m.overloaded, 1)
+	case m.Overloaded() && alloc < m.resumeLimit:
+		m.Logger.Info("Memory back under budget, serving the RPC requests", "heapAlloc", alloc)
+		atomic.StoreInt32(&m.overloaded, 0)
+	}
+}
+
+func heapAlloc() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
@@ -3,12 +3,16 @@ package node
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
-	_ "net/http/pprof"
+	"net/http/pprof"
 	"os"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -17,6 +21,7 @@ import (
 	"github.com/rs/cors"
 
 	amino "github.com/tendermint/go-amino"
+	abcicli "github.com/tendermint/tendermint/abci/client"
 	abci "github.com/tendermint/tendermint/abci/types"
 	bc "github.com/tendermint/tendermint/blockchain"
 	cfg "github.com/tendermint/tendermint/config"
@@ -39,6 +44,7 @@ import (
 	"github.com/tendermint/tendermint/state/txindex"
 	"github.com/tendermint/tendermint/state/txindex/kv"
 	"github.com/tendermint/tendermint/state/txindex/null"
+	"github.com/tendermint/tendermint/state/uptime"
 	"github.com/tendermint/tendermint/types"
 	tmtime "github.com/tendermint/tendermint/types/time"
 	"github.com/tendermint/tendermint/version"
@@ -59,6 +65,10 @@ type DBProvider func(*DBContext) (dbm.DB, error)
 // specified in the ctx.Config.
 func DefaultDBProvider(ctx *DBContext) (dbm.DB, error) {
 	dbType := dbm.DBBackendType(ctx.Config.DBBackend)
+	if ctx.Config.MemoryBudget > 0 {
+		cacheSize := ctx.Config.MemoryBudgetSizes().DBCacheSize
+		return dbm.NewDBWithCacheSize(ctx.ID, dbType, ctx.Config.DBDir(), cacheSize), nil
+	}
 	return dbm.NewDB(ctx.ID, dbType, ctx.Config.DBDir()), nil
 }
 
@@ -108,7 +118,9 @@ func DefaultNewNode(config *cfg.Config, logger log.Logger) (*Node, error) {
 	return NewNode(config,
 		privval.LoadOrGenFilePV(newPrivValKey, newPrivValState),
 		nodeKey,
-		proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()),
+		proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir(),
+			abcicli.SocketClientMaxMsgSize(config.ABCIMaxMsgSize),
+			abcicli.SocketClientCredits(config.ABCIMaxInFlight)),
 		DefaultGenesisDocProviderFunc(config),
 		DefaultDBProvider,
 		DefaultMetricsProvider(config.Instrumentation),
@@ -116,7 +128,7 @@ func DefaultNewNode(config *cfg.Config, logger log.Logger) (*Node, error) {
 	)
 }
 
-// MetricsProvider returns a consensus, p2p and mempool Metrics.
+// MetricsProvider returns a consensus, p2p, mempool and state Metrics.
 type MetricsProvider func(chainID string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics)
 
 // DefaultMetricsProvider returns Metrics build using Prometheus client library
@@ -133,6 +145,119 @@ func DefaultMetricsProvider(config *cfg.InstrumentationConfig) MetricsProvider {
 	}
 }
 
+// RPCMetricsProvider returns the rpc Metrics.
+type RPCMetricsProvider func(chainID string) *rpcserver.Metrics
+
+// DefaultRPCMetricsProvider returns the rpc Metrics build using Prometheus
+// client library if Prometheus is enabled. Otherwise, it returns no-op
+// Metrics.
+func DefaultRPCMetricsProvider(config *cfg.InstrumentationConfig) RPCMetricsProvider {
+	return func(chainID string) *rpcserver.Metrics {
+		if config.Prometheus {
+			return rpcserver.PrometheusMetrics(config.Namespace, "chain_id", chainID)
+		}
+		return rpcserver.NopMetrics()
+	}
+}
+
+// PrivvalMetricsProvider returns the privval Metrics.
+type PrivvalMetricsProvider func(chainID string) *privval.Metrics
+
+// DefaultPrivvalMetricsProvider returns the privval Metrics build using
+// Prometheus client library if Prometheus is enabled. Otherwise, it returns
+// no-op Metrics.
+func DefaultPrivvalMetricsProvider(config *cfg.InstrumentationConfig) PrivvalMetricsProvider {
+	return func(chainID string) *privval.Metrics {
+		if config.Prometheus {
+			return privval.PrometheusMetrics(config.Namespace, "chain_id", chainID)
+		}
+		return privval.NopMetrics()
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// Option sets a parameter for the node.
+type Option func(*Node)
+
+// ConnFilters appends custom filters for new connections. They are run
+// alongside the filters derived from the config (duplicate IP, ABCI query),
+// so embedders can implement their own admission logic in Go.
+func ConnFilters(filters ...p2p.ConnFilterFunc) Option {
+	return func(n *Node) {
+		n.connFilters = append(n.connFilters, filters...)
+	}
+}
+
+// PeerFilters appends custom filters for new peers. They are run alongside
+// the filters derived from the config (ABCI query), so embedders can
+// implement their own admission logic in Go (e.g. stake-weighted slots).
+func PeerFilters(filters ...p2p.PeerFilterFunc) Option {
+	return func(n *Node) {
+		n.peerFilters = append(n.peerFilters, filters...)
+	}
+}
+
+// MempoolPreChecks appends filters run by the mempool before CheckTx, after
+// the ones derived from the consensus params (max bytes), so embedders can
+// cheaply drop txs in Go (e.g. malformed signatures, blacklisted senders)
+// without the round trip to the application.
+func MempoolPreChecks(fs ...mempl.PreCheckFunc) Option {
+	return func(n *Node) {
+		n.mempoolPreChecks = append(n.mempoolPreChecks, fs...)
+	}
+}
+
+// MempoolPostChecks appends filters run by the mempool after CheckTx, after
+// the ones derived from the consensus params (max gas).
+func MempoolPostChecks(fs ...mempl.PostCheckFunc) Option {
+	return func(n *Node) {
+		n.mempoolPostChecks = append(n.mempoolPostChecks, fs...)
+	}
+}
+
+// TransportOptions sets options of the p2p transport, e.g. to connect the
+// node to the other nodes of the process through a p2p.MemNetwork.
+func TransportOptions(options ...p2p.MultiplexTransportOption) Option {
+	return func(n *Node) {
+		n.transportOptions = append(n.transportOptions, options...)
+	}
+}
+
+// RPCMetrics sets the provider of the rpc Metrics, DefaultRPCMetricsProvider
+// by default.
+func RPCMetrics(provider RPCMetricsProvider) Option {
+	return func(n *Node) {
+		n.rpcMetricsProvider = provider
+	}
+}
+
+// PrivvalMetrics sets the provider of the privval Metrics of the remote
+// signers, DefaultPrivvalMetricsProvider by default.
+func PrivvalMetrics(provider PrivvalMetricsProvider) Option {
+	return func(n *Node) {
+		n.privvalMetricsProvider = provider
+	}
+}
+
+// RPCTenants serves the tenants on the RPC server of the node, before its own
+// RPC (see the rpc.tenant_* config), e.g. the RPC of the other nodes of the
+// process, with their RPCHandler.
+func RPCTenants(tenants ...rpcserver.Tenant) Option {
+	return func(n *Node) {
+		n.rpcTenants = append(n.rpcTenants, tenants...)
+	}
+}
+
+// ProxyAppOptions appends options for the connections to the application,
+// e.g. proxy.AppConnsSyncInterceptors to wrap the ABCI calls with logging,
+// metrics or fault injection.
+func ProxyAppOptions(options ...proxy.AppConnsOption) Option {
+	return func(n *Node) {
+		n.proxyAppOptions = append(n.proxyAppOptions, options...)
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // Node is the highest level interface to a full Tendermint node.
@@ -143,6 +268,7 @@ type Node struct {
 	// config
 	config        *cfg.Config
 	genesisDoc    *types.GenesisDoc   // initial validator set
+	genesisHash   cmn.HexBytes        // hash of the genesis file, if any
 	privValidator types.PrivValidator // local node's validator key
 
 	// network
@@ -152,6 +278,16 @@ type Node struct {
 	nodeInfo    p2p.NodeInfo
 	nodeKey     *p2p.NodeKey // our node privkey
 	isListening bool
+	connFilters []p2p.ConnFilterFunc
+	peerFilters []p2p.PeerFilterFunc
+
+	// options of the components, applied as NewNode creates them
+	transportOptions       []p2p.MultiplexTransportOption
+	mempoolPreChecks       []mempl.PreCheckFunc
+	mempoolPostChecks      []mempl.PostCheckFunc
+	rpcMetricsProvider     RPCMetricsProvider
+	privvalMetricsProvider PrivvalMetricsProvider
+	rpcTenants             []rpcserver.Tenant
 
 	// services
 	eventBus         *types.EventBus // pub/sub for services
@@ -163,10 +299,22 @@ type Node struct {
 	consensusReactor *cs.ConsensusReactor   // for participating in the consensus
 	evidencePool     *evidence.EvidencePool // tracking evidence
 	proxyApp         proxy.AppConns         // connection to the application
+	proxyAppOptions  []proxy.AppConnsOption // options of the proxyApp
 	rpcListeners     []net.Listener         // rpc servers
+	rpcMetrics       *rpcserver.Metrics
+	rpcOnce          sync.Once
+	rpcEnv           *rpccore.Environment
+	rpcHandler       http.Handler
 	txIndexer        txindex.TxIndexer
 	indexerService   *txindex.IndexerService
+	uptimeTracker    *uptime.Tracker // nil if disabled
+	memoryMonitor    *memoryMonitor  // nil if no memory budget
 	prometheusSrv    *http.Server
+	pprofSrv         *http.Server
+	crashReporter    *crashReporter
+	recentLogs       *log.RecentModuleLogs // nil if disabled
+	dbs              map[string]dbm.DB     // by name, for backups
+	monikers         *types.ValidatorMonikers
 }
 
 // NewNode returns a new, ready to go, Tendermint Node.
@@ -177,7 +325,26 @@ func NewNode(config *cfg.Config,
 	genesisDocProvider GenesisDocProvider,
 	dbProvider DBProvider,
 	metricsProvider MetricsProvider,
-	logger log.Logger) (*Node, error) {
+	logger log.Logger,
+	options ...Option) (*Node, error) {
+
+	// Size the mempool and the peers to fit in the memory budget, if any,
+	// leaving the config of the caller as is.
+	config = config.WithMemoryBudget()
+
+	// Record the recent log lines for the crash reports, if any.
+	crashReporter := newCrashReporter(config.CrashReportDir(), config.CrashShutdown, config.CrashReportLogs,
+		logger.With("module", "crash"))
+	if crashReporter.recent != nil {
+		logger = log.NewRecentLogger(logger, crashReporter.recent)
+	}
+
+	// Keep the recent log lines of each module for /unsafe_logs, if enabled.
+	var recentLogs *log.RecentModuleLogs
+	if config.RPC.IsUnsafeEnabled() && rpccore.UnsafeRoutesAvailable && config.LogBufferLines > 0 {
+		recentLogs = log.NewRecentModuleLogs(config.LogBufferLines)
+		logger = log.NewRecentModuleLogger(logger, recentLogs)
+	}
 
 	// Get BlockStore
 	blockStoreDB, err := dbProvider(&DBContext{"blockstore", config})
@@ -191,6 +358,7 @@ func NewNode(config *cfg.Config,
 	if err != nil {
 		return nil, err
 	}
+	dbs := map[string]dbm.DB{"blockstore": blockStoreDB, "state": stateDB}
 
 	// Get genesis doc
 	// TODO: move to state package?
@@ -205,24 +373,53 @@ func NewNode(config *cfg.Config,
 		saveGenesisDoc(stateDB, genDoc)
 	}
 
+	// Check the genesis file is the expected one.
+	genesisHash, err := genesisFileHash(config.GenesisFile())
+	if err != nil {
+		return nil, err
+	}
+	if config.GenesisHash != "" {
+		if genesisHash == nil {
+			return nil, fmt.Errorf("genesis_hash is set but there is no genesis file %v", config.GenesisFile())
+		}
+		if !strings.EqualFold(genesisHash.String(), config.GenesisHash) {
+			return nil, fmt.Errorf("the genesis file %v has hash %v, expected %v",
+				config.GenesisFile(), genesisHash, config.GenesisHash)
+		}
+	}
+
+	// Hash the trees and the headers with the hash function of the genesis,
+	// before the state of the genesis hashes its validators.
+	if err := genDoc.UseMerkleHasher(); err != nil {
+		return nil, err
+	}
+
 	state, err := sm.LoadStateFromDBOrGenesisDoc(stateDB, genDoc)
 	if err != nil {
 		return nil, err
 	}
 
+	// The options are applied once the node is built, but the options of the
+	// proxyApp, the mempool, the transport and the rpc metrics are needed to
+	// create them.
+	var optioned Node
+	for _, option := range options {
+		option(&optioned)
+	}
+
 	// Create the proxyApp and establish connections to the ABCI app (consensus, mempool, query).
-	proxyApp := proxy.NewAppConns(clientCreator)
+	proxyApp := proxy.NewAppConns(clientCreator, optioned.proxyAppOptions...)
 	proxyApp.SetLogger(logger.With("module", "proxy"))
 	if err := proxyApp.Start(); err != nil {
 		return nil, fmt.Errorf("Error starting proxy app connections: %v", err)
 	}
 
 	// EventBus and IndexerService must be started before the handshake because
-	// we might need to index the txs of the replayed block as this might not have happened
-	// when the node stopped last time (i.e. the node stopped after it saved the block
-	// but before it indexed the txs, or, endblocker panicked)
+	// the IndexerService must learn the height of the replayed block, to index
+	// its txs once their results are saved.
 	eventBus := types.NewEventBus()
 	eventBus.SetLogger(logger.With("module", "events"))
+	eventBus.SetTagEncoding(types.TagEncoding(config.TxIndex.TagEncoding))
 
 	err = eventBus.Start()
 	if err != nil {
@@ -231,24 +428,28 @@ func NewNode(config *cfg.Config,
 
 	// Transaction indexing
 	var txIndexer txindex.TxIndexer
+	var txIndexDB dbm.DB // of the indexing progress
 	switch config.TxIndex.Indexer {
 	case "kv":
 		store, err := dbProvider(&DBContext{"tx_index", config})
 		if err != nil {
 			return nil, err
 		}
+		dbs["tx_index"] = store
+		txIndexDB = store
+		tagEncoding := kv.TagEncoding(types.TagEncoding(config.TxIndex.TagEncoding))
 		if config.TxIndex.IndexTags != "" {
-			txIndexer = kv.NewTxIndex(store, kv.IndexTags(splitAndTrimEmpty(config.TxIndex.IndexTags, ",", " ")))
+			txIndexer = kv.NewTxIndex(store, kv.IndexTags(splitAndTrimEmpty(config.TxIndex.IndexTags, ",", " ")), tagEncoding)
 		} else if config.TxIndex.IndexAllTags {
-			txIndexer = kv.NewTxIndex(store, kv.IndexAllTags())
+			txIndexer = kv.NewTxIndex(store, kv.IndexAllTags(), tagEncoding)
 		} else {
-			txIndexer = kv.NewTxIndex(store)
+			txIndexer = kv.NewTxIndex(store, tagEncoding)
 		}
 	default:
 		txIndexer = &null.TxIndex{}
 	}
 
-	indexerService := txindex.NewIndexerService(txIndexer, eventBus)
+	indexerService := txindex.NewIndexerService(txIndexer, eventBus, txIndexDB, stateDB, blockStore)
 	indexerService.SetLogger(logger.With("module", "txindex"))
 
 	err = indexerService.Start()
@@ -271,6 +472,14 @@ func NewNode(config *cfg.Config,
 	// what happened during block replay).
 	state = sm.LoadState(stateDB)
 
+	// Uptime tracking, started with the node, after the handshake, so the
+	// validators of the replayed blocks are saved.
+	var uptimeTracker *uptime.Tracker
+	if config.Instrumentation.UptimeWindow > 0 {
+		uptimeTracker = uptime.NewTracker(config.Instrumentation.UptimeWindow, stateDB, blockStore, eventBus)
+		uptimeTracker.SetLogger(logger.With("module", "uptime"))
+	}
+
 	// Log the version info.
 	logger.Info("Version info",
 		"software", version.TMCoreSemVer,
@@ -286,20 +495,49 @@ func NewNode(config *cfg.Config,
 		)
 	}
 
-	if config.PrivValidatorListenAddr != "" {
+	csMetrics, p2pMetrics, memplMetrics, smMetrics := metricsProvider(genDoc.ChainID)
+	rpcMetricsProvider := optioned.rpcMetricsProvider
+	if rpcMetricsProvider == nil {
+		rpcMetricsProvider = DefaultRPCMetricsProvider(config.Instrumentation)
+	}
+	rpcMetrics := rpcMetricsProvider(genDoc.ChainID)
+	privvalMetricsProvider := optioned.privvalMetricsProvider
+	if privvalMetricsProvider == nil {
+		privvalMetricsProvider = DefaultPrivvalMetricsProvider(config.Instrumentation)
+	}
+	privvalMetrics := privvalMetricsProvider(genDoc.ChainID)
+
+	if config.Replica {
+		// A replica never signs: don't connect to the signer, nor take the
+		// signing lock.
+		logger.Info("Running as a read-only replica: the validator key is never used to sign")
+	} else if config.PrivValidatorListenAddr != "" {
 		// If an address is provided, listen on the socket for a connection from an
 		// external signing process.
 		// FIXME: we should start services inside OnStart
-		privValidator, err = createAndStartPrivValidatorSocketClient(config.PrivValidatorListenAddr, logger)
+		privValidator, err = createAndStartPrivValidatorSocketClient(
+			splitAndTrimEmpty(config.PrivValidatorListenAddr, ",", " "),
+			config.PrivValidatorFailoverTimeout,
+			privvalMetrics,
+			logger,
+		)
 		if err != nil {
 			return nil, errors.Wrap(err, "Error with private validator socket client")
 		}
 	}
 
+	if config.PrivValidatorLock != "" && !config.Replica {
+		// Refuse to start if another node signs with the same key.
+		privValidator, err = createAndStartLockedPV(config, privValidator, nodeKey.ID(), logger)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error with private validator signing lock")
+		}
+	}
+
 	// Decide whether to fast-sync or not
-	// We don't fast-sync when the only validator is us.
+	// We don't fast-sync when the only validator is us, unless we're a replica.
 	fastSync := config.FastSync
-	if state.Validators.Size() == 1 {
+	if state.Validators.Size() == 1 && !config.Replica {
 		addr, _ := state.Validators.GetByIndex(0)
 		privValAddr := privValidator.GetPubKey().Address()
 		if bytes.Equal(privValAddr, addr) {
@@ -310,14 +548,14 @@ func NewNode(config *cfg.Config,
 	pubKey := privValidator.GetPubKey()
 	addr := pubKey.Address()
 	// Log whether this node is a validator or an observer
-	if state.Validators.HasAddress(addr) {
+	if config.Replica {
+		consensusLogger.Info("This node is a read-only replica", "addr", addr, "pubKey", pubKey)
+	} else if state.Validators.HasAddress(addr) {
 		consensusLogger.Info("This node is a validator", "addr", addr, "pubKey", pubKey)
 	} else {
 		consensusLogger.Info("This node is not a validator", "addr", addr, "pubKey", pubKey)
 	}
 
-	csMetrics, p2pMetrics, memplMetrics, smMetrics := metricsProvider(genDoc.ChainID)
-
 	// Make MempoolReactor
 	mempool := mempl.NewMempool(
 		config.Mempool,
@@ -326,6 +564,8 @@ func NewNode(config *cfg.Config,
 		mempl.WithMetrics(memplMetrics),
 		mempl.WithPreCheck(sm.TxPreCheck(state)),
 		mempl.WithPostCheck(sm.TxPostCheck(state)),
+		mempl.WithCustomPreChecks(optioned.mempoolPreChecks...),
+		mempl.WithCustomPostChecks(optioned.mempoolPostChecks...),
 	)
 	mempoolLogger := logger.With("module", "mempool")
 	mempool.SetLogger(mempoolLogger)
@@ -344,6 +584,7 @@ func NewNode(config *cfg.Config,
 	if err != nil {
 		return nil, err
 	}
+	dbs["evidence"] = evidenceDB
 	evidenceLogger := logger.With("module", "evidence")
 	evidencePool := evidence.NewEvidencePool(stateDB, evidenceDB)
 	evidencePool.SetLogger(evidenceLogger)
@@ -352,20 +593,34 @@ func NewNode(config *cfg.Config,
 
 	blockExecLogger := logger.With("module", "state")
 	// make block executor for consensus and blockchain reactors to execute blocks
+	blockExecOptions := []sm.BlockExecutorOption{sm.BlockExecutorWithMetrics(smMetrics)}
+	if config.CheckInvariants {
+		blockExecOptions = append(blockExecOptions, sm.BlockExecutorWithInvariantChecks())
+	}
 	blockExec := sm.NewBlockExecutor(
 		stateDB,
 		blockExecLogger,
 		proxyApp.Consensus(),
 		mempool,
 		evidencePool,
-		sm.BlockExecutorWithMetrics(smMetrics),
+		blockExecOptions...,
 	)
 
 	// Make BlockchainReactor
 	bcReactor := bc.NewBlockchainReactor(state.Copy(), blockExec, blockStore, fastSync)
 	bcReactor.SetLogger(logger.With("module", "blockchain"))
 
+	// Monikers of the validators, for the consensus logs and the RPC
+	monikers := types.NewValidatorMonikers(genDoc.Validators)
+	if err := monikers.LoadFile(config.ValidatorMonikersFile()); err != nil {
+		return nil, err
+	}
+
 	// Make ConsensusReactor
+	csOptions := []cs.StateOption{cs.StateMetrics(csMetrics), cs.StateValidatorMonikers(monikers)}
+	if config.CheckInvariants {
+		csOptions = append(csOptions, cs.StateInvariantChecks())
+	}
 	consensusState := cs.NewConsensusState(
 		config.Consensus,
 		state.Copy(),
@@ -373,13 +628,26 @@ func NewNode(config *cfg.Config,
 		blockStore,
 		mempool,
 		evidencePool,
-		cs.StateMetrics(csMetrics),
+		csOptions...,
 	)
 	consensusState.SetLogger(consensusLogger)
-	if privValidator != nil {
+	if privValidator != nil && !config.Replica {
 		consensusState.SetPrivValidator(privValidator)
 	}
-	consensusReactor := cs.NewConsensusReactor(consensusState, fastSync, cs.ReactorMetrics(csMetrics))
+	csReactorOptions := []cs.ReactorOption{cs.ReactorMetrics(csMetrics)}
+	if config.Consensus.RecordEnabled() {
+		recorder, err := cs.NewMsgRecorder(config.Consensus.RecordFile(), config.Consensus.RecordMaxSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create the consensus message recorder")
+		}
+		recorder.SetLogger(consensusLogger.With("recorder", "msgs"))
+		csReactorOptions = append(csReactorOptions, cs.ReactorMsgRecorder(recorder))
+	}
+	if config.Consensus.WaitForPersistentPeers {
+		persistentPeers := splitAndTrimEmpty(config.P2P.PersistentPeers, ",", " ")
+		csReactorOptions = append(csReactorOptions, cs.ReactorPersistentPeers(len(persistentPeers)))
+	}
+	consensusReactor := cs.NewConsensusReactor(consensusState, fastSync, csReactorOptions...)
 	consensusReactor.SetLogger(consensusLogger)
 
 	// services which will be publishing and/or subscribing for messages (events)
@@ -392,6 +660,7 @@ func NewNode(config *cfg.Config,
 		nodeKey.ID(),
 		txIndexer,
 		genDoc.ChainID,
+		genesisHash,
 		p2p.NewProtocolVersion(
 			version.P2PProtocol, // global
 			state.Version.Consensus.Block,
@@ -454,15 +723,54 @@ func NewNode(config *cfg.Config,
 		)
 	}
 
-	p2p.MultiplexTransportConnFilters(connFilters...)(transport)
+	// Route outbound connections through the upstream proxy, if any.
+	if config.P2P.UpstreamProxy != "" {
+		dialer, err := p2p.NewProxyDialer(config.P2P.UpstreamProxy, config.P2P.DialTimeout)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error creating upstream proxy dialer")
+		}
+		p2p.MultiplexTransportDialer(dialer)(transport)
+	}
+
+	// Dial the persistent peers with the Noise handshake once their key is
+	// pinned.
+	p2p.MultiplexTransportNoiseHandshake(config.P2P.NoiseHandshake)(transport)
+
+	// Only connect to certified peers in a permissioned network.
+	if caFile := config.P2P.CAFile(); caFile != "" {
+		cas, err := p2p.LoadCertificateAuthorities(caFile)
+		if err != nil {
+			return nil, err
+		}
+		ni := nodeInfo.(p2p.DefaultNodeInfo)
+		if ni.Certificate == nil {
+			return nil, errors.New("the network is permissioned, but this node has no certificate (p2p.cert_file)")
+		}
+		if err := ni.Certificate.Verify(ni.ID(), cas, time.Now()); err != nil {
+			return nil, errors.Wrap(err, "Invalid certificate of this node")
+		}
+		p2p.MultiplexTransportCertificateAuthorities(cas)(transport)
+	}
+
+	for _, option := range optioned.transportOptions {
+		option(transport)
+	}
+
+	peerGroups, err := p2p.NewPeerGroupsString(config.P2P.PersistentPeerGroups)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing persistent_peer_groups")
+	}
 
 	// Setup Switch.
-	sw := p2p.NewSwitch(
-		config.P2P,
-		transport,
+	swOptions := []p2p.SwitchOption{
 		p2p.WithMetrics(p2pMetrics),
-		p2p.SwitchPeerFilters(peerFilters...),
-	)
+		p2p.SwitchPeerGroups(peerGroups...),
+	}
+	if crashReporter.enabled() {
+		crashReporter.consensusState = consensusState
+		swOptions = append(swOptions, p2p.SwitchPanicHandler(crashReporter.onPanic))
+	}
+	sw := p2p.NewSwitch(config.P2P, transport, swOptions...)
 	sw.SetLogger(p2pLogger)
 	sw.AddReactor("MEMPOOL", mempoolReactor)
 	sw.AddReactor("BLOCKCHAIN", bcReactor)
@@ -504,17 +812,10 @@ func NewNode(config *cfg.Config,
 
 	sw.SetAddrBook(addrBook)
 
-	// run the profile server
-	profileHost := config.ProfListenAddress
-	if profileHost != "" {
-		go func() {
-			logger.Error("Profile server", "err", http.ListenAndServe(profileHost, nil))
-		}()
-	}
-
 	node := &Node{
 		config:        config,
 		genesisDoc:    genDoc,
+		genesisHash:   genesisHash,
 		privValidator: privValidator,
 
 		transport: transport,
@@ -523,6 +824,9 @@ func NewNode(config *cfg.Config,
 		nodeInfo:  nodeInfo,
 		nodeKey:   nodeKey,
 
+		connFilters: connFilters,
+		peerFilters: peerFilters,
+
 		stateDB:          stateDB,
 		blockStore:       blockStore,
 		bcReactor:        bcReactor,
@@ -533,24 +837,54 @@ func NewNode(config *cfg.Config,
 		proxyApp:         proxyApp,
 		txIndexer:        txIndexer,
 		indexerService:   indexerService,
+		rpcMetrics:       rpcMetrics,
+		uptimeTracker:    uptimeTracker,
 		eventBus:         eventBus,
+		crashReporter:    crashReporter,
+		recentLogs:       recentLogs,
+		dbs:              dbs,
+		monikers:         monikers,
 	}
 	node.BaseService = *cmn.NewBaseService(logger, "Node", node)
+
+	if config.MemoryBudget > 0 {
+		node.memoryMonitor = newMemoryMonitor(config.MemoryBudget)
+		node.memoryMonitor.SetLogger(logger.With("module", "memory"))
+	}
+
+	for _, option := range options {
+		option(node)
+	}
+
+	p2p.MultiplexTransportConnFilters(node.connFilters...)(transport)
+	p2p.SwitchPeerFilters(node.peerFilters...)(sw)
+
 	return node, nil
 }
 
 // OnStart starts the Node. It implements cmn.Service.
 func (n *Node) OnStart() error {
-	now := tmtime.Now()
-	genTime := n.genesisDoc.GenesisTime
-	if genTime.After(now) {
-		n.Logger.Info("Genesis time is in the future. Sleeping until then...", "genTime", genTime)
-		time.Sleep(genTime.Sub(now))
+	// The consensus doesn't start the first block before the genesis time.
+	if genTime := n.genesisDoc.GenesisTime; genTime.After(tmtime.Now()) {
+		n.Logger.Info("Genesis time is in the future. Waiting until then to start the first block...",
+			"genTime", genTime)
 	}
 
 	// Add private IDs to addrbook to block those peers being added
 	n.addrBook.AddPrivateIDs(splitAndTrimEmpty(n.config.P2P.PrivatePeerIDs, ",", " "))
 
+	if n.memoryMonitor != nil {
+		if err := n.memoryMonitor.Start(); err != nil {
+			return err
+		}
+	}
+
+	if n.uptimeTracker != nil {
+		if err := n.uptimeTracker.Start(); err != nil {
+			return err
+		}
+	}
+
 	// Start the RPC server before the P2P server
 	// so we can eg. receive txs for the first block
 	if n.config.RPC.ListenAddress != "" {
@@ -566,6 +900,14 @@ func (n *Node) OnStart() error {
 		n.prometheusSrv = n.startPrometheusServer(n.config.Instrumentation.PrometheusListenAddr)
 	}
 
+	pprofAddr := n.config.RPC.PprofListenAddress
+	if pprofAddr == "" {
+		pprofAddr = n.config.ProfListenAddress // deprecated
+	}
+	if pprofAddr != "" {
+		n.pprofSrv = n.startPprofServer(pprofAddr)
+	}
+
 	// Start the transport.
 	addr, err := p2p.NewNetAddressStringWithOptionalID(n.config.P2P.ListenAddress)
 	if err != nil {
@@ -591,9 +933,31 @@ func (n *Node) OnStart() error {
 		}
 	}
 
+	go n.reloadValidatorMonikersRoutine()
+
 	return nil
 }
 
+// How often the validator monikers file is checked for updates.
+const validatorMonikersReloadInterval = 10 * time.Second
+
+// reloadValidatorMonikersRoutine picks up the updates of the validator
+// monikers file until the node stops.
+func (n *Node) reloadValidatorMonikersRoutine() {
+	ticker := time.NewTicker(validatorMonikersReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := n.monikers.LoadFile(n.config.ValidatorMonikersFile()); err != nil {
+				n.Logger.Error("Error reloading validator monikers", "err", err)
+			}
+		case <-n.Quit():
+			return
+		}
+	}
+}
+
 // OnStop stops the Node. It implements cmn.Service.
 func (n *Node) OnStop() {
 	n.BaseService.OnStop()
@@ -601,8 +965,14 @@ func (n *Node) OnStop() {
 	n.Logger.Info("Stopping Node")
 
 	// first stop the non-reactor services
+	if n.uptimeTracker != nil {
+		n.uptimeTracker.Stop()
+	}
 	n.eventBus.Stop()
 	n.indexerService.Stop()
+	if n.memoryMonitor != nil {
+		n.memoryMonitor.Stop()
+	}
 
 	// now stop the reactors
 	// TODO: gracefully disconnect from peers.
@@ -637,49 +1007,131 @@ func (n *Node) OnStop() {
 			n.Logger.Error("Prometheus HTTP server Shutdown", "err", err)
 		}
 	}
+
+	if n.pprofSrv != nil {
+		if err := n.pprofSrv.Shutdown(context.Background()); err != nil {
+			n.Logger.Error("Pprof HTTP server Shutdown", "err", err)
+		}
+	}
 }
 
-// ConfigureRPC sets all variables in rpccore so they will serve
-// rpc calls from this node
-func (n *Node) ConfigureRPC() {
-	rpccore.SetStateDB(n.stateDB)
-	rpccore.SetBlockStore(n.blockStore)
-	rpccore.SetConsensusState(n.consensusState)
-	rpccore.SetMempool(n.mempoolReactor.Mempool)
-	rpccore.SetEvidencePool(n.evidencePool)
-	rpccore.SetP2PPeers(n.sw)
-	rpccore.SetP2PTransport(n)
-	pubKey := n.privValidator.GetPubKey()
-	rpccore.SetPubKey(pubKey)
-	rpccore.SetGenesisDoc(n.genesisDoc)
-	rpccore.SetAddrBook(n.addrBook)
-	rpccore.SetProxyAppQuery(n.proxyApp.Query())
-	rpccore.SetTxIndexer(n.txIndexer)
-	rpccore.SetConsensusReactor(n.consensusReactor)
-	rpccore.SetEventBus(n.eventBus)
-	rpccore.SetLogger(n.Logger.With("module", "rpc"))
+// ConfigureRPC returns the environment of the RPC of the node, serving the rpc
+// calls from this node. It is created on the first call.
+func (n *Node) ConfigureRPC() *rpccore.Environment {
+	n.rpcOnce.Do(n.configureRPC)
+	return n.rpcEnv
 }
 
-func (n *Node) startRPC() ([]net.Listener, error) {
-	n.ConfigureRPC()
-	listenAddrs := splitAndTrimEmpty(n.config.RPC.ListenAddress, ",", " ")
+// RPCHandler returns the handler of the RPC routes of the node, e.g. to serve
+// it as a tenant of the RPC server of another node of the process (see the
+// RPCTenants option). It is created on the first call.
+func (n *Node) RPCHandler() http.Handler {
+	n.rpcOnce.Do(n.configureRPC)
+	return n.rpcHandler
+}
+
+func (n *Node) configureRPC() {
+	env := &rpccore.Environment{
+		Config:           *n.config.RPC,
+		StateDB:          n.stateDB,
+		BlockStore:       n.blockStore,
+		BlockTimeIndex:   n.blockStore,
+		Consensus:        n.consensusState,
+		Mempool:          n.mempoolReactor.Mempool,
+		EvidencePool:     n.evidencePool,
+		EvidenceIndex:    n.evidencePool,
+		P2PPeers:         n.sw,
+		P2PTransport:     n,
+		PubKey:           n.privValidator.GetPubKey(),
+		GenDoc:           n.genesisDoc,
+		GenesisHash:      n.genesisHash,
+		AddrBook:         n.addrBook,
+		ProxyAppQuery:    n.proxyApp.Query(),
+		TxIndexer:        n.txIndexer,
+		ConsensusReactor: n.consensusReactor,
+		EventBus:         n.eventBus,
+		TagEncoding:      n.eventBus.TagEncoding(),
+		Monikers:         n.monikers,
+		UptimeTracker:    n.uptimeTracker,
+		BackupDBs:        n.dbs,
+		BackupDBBackend:  dbm.DBBackendType(n.config.DBBackend),
+		Logger:           n.Logger.With("module", "rpc"),
+		RecentLogs:       n.recentLogs,
+	}
+	n.rpcEnv = env
+
 	coreCodec := amino.NewCodec()
 	ctypes.RegisterAmino(coreCodec)
 
-	if n.config.RPC.Unsafe {
-		rpccore.AddUnsafeRoutes()
+	routes := env.Routes()
+	switch {
+	case n.config.RPC.Unsafe && n.config.RPC.DisableUnsafe:
+		n.Logger.Info("The unsafe RPC commands are disabled by rpc.disable_unsafe")
+	case n.config.RPC.Unsafe && !rpccore.UnsafeRoutesAvailable:
+		n.Logger.Info("The unsafe RPC commands are not available in this build")
+	case n.config.RPC.Unsafe:
+		env.AddUnsafeRoutes(routes)
+	}
+
+	// the websocket connections are limited over all the listeners
+	rpcLogger := n.Logger.With("module", "rpc-server")
+	var wm *rpcserver.WebsocketManager
+	if n.config.MemoryBudget > 0 {
+		wm = rpcserver.NewWebsocketManager(routes, coreCodec, rpcserver.EventSubscriber(n.eventBus),
+			rpcserver.WriteChanCapacity(n.config.MemoryBudgetSizes().EventQueueSize))
+	} else {
+		wm = rpcserver.NewWebsocketManager(routes, coreCodec, rpcserver.EventSubscriber(n.eventBus))
+	}
+	wm.SetLogger(rpcLogger.With("protocol", "websocket"))
+	wm.SetMaxConnectionsPerIP(n.config.RPC.MaxWSConnectionsPerIP)
+	wm.SetMaxConnections(n.config.RPC.MaxSubscriptionClients,
+		rpcserver.EvictionPolicy(n.config.RPC.SubscriptionClientsEviction))
+	wm.SetMetrics(n.rpcMetrics)
+	if n.crashReporter.enabled() {
+		wm.SetPanicHandler(n.crashReporter.onPanic)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/websocket", wm.WebsocketHandler)
+	mux.HandleFunc("/debug/state", n.debugStateHandler)
+	rpcserver.RegisterRPCFuncs(mux, routes, coreCodec, rpcLogger)
+	n.rpcHandler = mux
+}
+
+func (n *Node) startRPC() ([]net.Listener, error) {
+	env := n.ConfigureRPC()
+	listenAddrs := splitAndTrimEmpty(n.config.RPC.ListenAddress, ",", " ")
+	rpcLogger := n.Logger.With("module", "rpc-server")
+
+	// the RPC of the node is served after the tenants of the RPCTenants option
+	tenants := append([]rpcserver.Tenant{}, n.rpcTenants...)
+	tenants = append(tenants, rpcserver.Tenant{
+		Name:       n.config.Moniker,
+		Host:       n.config.RPC.TenantHost,
+		Prefix:     n.config.RPC.TenantPrefix,
+		Handler:    n.RPCHandler(),
+		AuthTokens: n.config.RPC.AuthTokens,
+		RateLimit:  n.config.RPC.RateLimit,
+		RateBurst:  n.config.RPC.RateBurst,
+	})
+	tenantsHandler, err := rpcserver.NewTenantsHandler(tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	slowThresholds, err := n.config.RPC.SlowRequestThresholdsByEndpoint()
+	if err != nil {
+		return nil, err
+	}
+	accessLogConfig := rpcserver.AccessLogConfig{
+		SampleRate:     n.config.RPC.AccessLogSampleRate,
+		SlowThreshold:  n.config.RPC.SlowRequestThreshold,
+		SlowThresholds: slowThresholds,
 	}
 
 	// we may expose the rpc over both a unix and tcp socket
 	listeners := make([]net.Listener, len(listenAddrs))
 	for i, listenAddr := range listenAddrs {
-		mux := http.NewServeMux()
-		rpcLogger := n.Logger.With("module", "rpc-server")
-		wm := rpcserver.NewWebsocketManager(rpccore.Routes, coreCodec, rpcserver.EventSubscriber(n.eventBus))
-		wm.SetLogger(rpcLogger.With("protocol", "websocket"))
-		mux.HandleFunc("/websocket", wm.WebsocketHandler)
-		rpcserver.RegisterRPCFuncs(mux, rpccore.Routes, coreCodec, rpcLogger)
-
 		listener, err := rpcserver.Listen(
 			listenAddr,
 			rpcserver.Config{MaxOpenConnections: n.config.RPC.MaxOpenConnections},
@@ -688,14 +1140,21 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 			return nil, err
 		}
 
-		var rootHandler http.Handler = mux
+		rootHandler := tenantsHandler
 		if n.config.RPC.IsCorsEnabled() {
 			corsMiddleware := cors.New(cors.Options{
 				AllowedOrigins: n.config.RPC.CORSAllowedOrigins,
 				AllowedMethods: n.config.RPC.CORSAllowedMethods,
 				AllowedHeaders: n.config.RPC.CORSAllowedHeaders,
 			})
-			rootHandler = corsMiddleware.Handler(mux)
+			rootHandler = corsMiddleware.Handler(rootHandler)
+		}
+		if n.memoryMonitor != nil {
+			rootHandler = rpcserver.ShedLoadHandler(rootHandler, n.memoryMonitor.Overloaded)
+		}
+		rootHandler = rpcserver.AccessLogHandler(rootHandler, rpcLogger, accessLogConfig)
+		if n.crashReporter.enabled() {
+			rootHandler = rpcserver.PanicHandler(rootHandler, n.crashReporter.onPanic)
 		}
 
 		go rpcserver.StartHTTPServer(
@@ -714,7 +1173,7 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 		if err != nil {
 			return nil, err
 		}
-		go grpccore.StartGRPCServer(listener)
+		go grpccore.StartGRPCServer(env, listener)
 		listeners = append(listeners, listener)
 	}
 
@@ -742,6 +1201,34 @@ func (n *Node) startPrometheusServer(addr string) *http.Server {
 	return srv
 }
 
+// The pprof server samples 1 in mutexProfileFraction mutex contention events.
+const mutexProfileFraction = 100
+
+// startPprofServer starts a pprof server at the given address, serving the
+// profiles under /debug/pprof/. It samples the mutex contention events too,
+// for the mutex profile.
+func (n *Node) startPprofServer(addr string) *http.Server {
+	runtime.SetMutexProfileFraction(mutexProfileFraction)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+	go func() {
+		if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+			// Error starting or closing listener:
+			n.Logger.Error("Pprof HTTP server ListenAndServe", "err", err)
+		}
+	}()
+	return srv
+}
+
 // Switch returns the Node's Switch.
 func (n *Node) Switch() *p2p.Switch {
 	return n.sw
@@ -788,6 +1275,12 @@ func (n *Node) GenesisDoc() *types.GenesisDoc {
 	return n.genesisDoc
 }
 
+// GenesisHash returns the SHA-256 hash of the Node's genesis file, nil if
+// there is none.
+func (n *Node) GenesisHash() cmn.HexBytes {
+	return n.genesisHash
+}
+
 // ProxyApp returns the Node's AppConns, representing its connections to the ABCI application.
 func (n *Node) ProxyApp() proxy.AppConns {
 	return n.proxyApp
@@ -820,6 +1313,7 @@ func makeNodeInfo(
 	nodeID p2p.ID,
 	txIndexer txindex.TxIndexer,
 	chainID string,
+	genesisHash cmn.HexBytes,
 	protocolVersion p2p.ProtocolVersion,
 ) (p2p.NodeInfo, error) {
 	txIndexerStatus := "on"
@@ -830,10 +1324,12 @@ func makeNodeInfo(
 		ProtocolVersion: protocolVersion,
 		ID_:             nodeID,
 		Network:         chainID,
+		GenesisHash:     genesisHash,
 		Version:         version.TMCoreSemVer,
 		Channels: []byte{
 			bc.BlockchainChannel,
 			cs.StateChannel, cs.DataChannel, cs.VoteChannel, cs.VoteSetBitsChannel,
+			cs.DataParityChannel, cs.CompactBlockChannel, cs.CommitChannel,
 			mempl.MempoolChannel,
 			evidence.EvidenceChannel,
 		},
@@ -842,12 +1338,22 @@ func makeNodeInfo(
 			TxIndex:    txIndexerStatus,
 			RPCAddress: config.RPC.ListenAddress,
 		},
+		ClockSync: true,
 	}
 
 	if config.P2P.PexReactor {
 		nodeInfo.Channels = append(nodeInfo.Channels, pex.PexChannel)
 	}
 
+	if config.P2P.Compression != "none" {
+		nodeInfo.Compression = config.P2P.Compression
+		nodeInfo.CompressedChannels = []byte{
+			bc.BlockchainChannel,
+			cs.DataChannel, cs.CompactBlockChannel,
+			mempl.MempoolChannel,
+		}
+	}
+
 	lAddr := config.P2P.ExternalAddress
 
 	if lAddr == "" {
@@ -856,6 +1362,28 @@ func makeNodeInfo(
 
 	nodeInfo.ListenAddr = lAddr
 
+	if certFile := config.P2P.CertFile(); certFile != "" {
+		cert, err := p2p.LoadPeerCertificate(certFile)
+		if err != nil {
+			return nil, err
+		}
+		if cert.NodeID != nodeID {
+			return nil, fmt.Errorf("the certificate in %v is the one of node %v, not %v", certFile, cert.NodeID, nodeID)
+		}
+		nodeInfo.Certificate = cert
+	}
+
+	if rotationFile := config.NodeKeyRotationFile(); cmn.FileExists(rotationFile) {
+		rot, err := p2p.LoadKeyRotation(rotationFile)
+		if err != nil {
+			return nil, err
+		}
+		if rot.NewID != nodeID {
+			return nil, fmt.Errorf("the key rotation in %v is the one to node %v, not %v", rotationFile, rot.NewID, nodeID)
+		}
+		nodeInfo.KeyRotation = rot
+	}
+
 	err := nodeInfo.Validate()
 	return nodeInfo, err
 }
@@ -889,10 +1417,60 @@ func saveGenesisDoc(db dbm.DB, genDoc *types.GenesisDoc) {
 	db.SetSync(genesisDocKey, bytes)
 }
 
+// genesisFileHash returns the SHA-256 hash of the genesis file, nil if there
+// is none (e.g. the genesis doc is provided otherwise).
+func genesisFileHash(genesisFile string) (cmn.HexBytes, error) {
+	bytes, err := ioutil.ReadFile(genesisFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(bytes)
+	return hash[:], nil
+}
+
+// createAndStartPrivValidatorSocketClient listens for the external signing
+// processes at listenAddrs. With several addresses, it fails over from one to
+// the next, in priority order.
 func createAndStartPrivValidatorSocketClient(
-	listenAddr string,
+	listenAddrs []string,
+	failoverTimeout time.Duration,
+	metrics *privval.Metrics,
 	logger log.Logger,
 ) (types.PrivValidator, error) {
+	if len(listenAddrs) == 1 {
+		pvsc, err := createPrivValidatorSocketClient(listenAddrs[0], metrics, logger)
+		if err != nil {
+			return nil, err
+		}
+		if err := pvsc.Start(); err != nil {
+			return nil, errors.Wrap(err, "failed to start private validator")
+		}
+		return pvsc, nil
+	}
+
+	newEndpoints := make([]privval.SocketValFactory, len(listenAddrs))
+	for i, listenAddr := range listenAddrs {
+		listenAddr, logger := listenAddr, logger.With("endpoint", i)
+		newEndpoints[i] = func() (*privval.SocketVal, error) {
+			return createPrivValidatorSocketClient(listenAddr, metrics, logger)
+		}
+	}
+	pv := privval.NewFailoverPV(newEndpoints, privval.FailoverPVTimeout(failoverTimeout))
+	pv.SetLogger(logger.With("module", "privval"))
+	if err := pv.Start(); err != nil {
+		return nil, errors.Wrap(err, "failed to start private validator")
+	}
+	return pv, nil
+}
+
+func createPrivValidatorSocketClient(
+	listenAddr string,
+	metrics *privval.Metrics,
+	logger log.Logger,
+) (*privval.SocketVal, error) {
 	var listener net.Listener
 
 	protocol, address := cmn.ProtocolAndAddress(listenAddr)
@@ -914,12 +1492,37 @@ func createAndStartPrivValidatorSocketClient(
 		)
 	}
 
-	pvsc := privval.NewSocketVal(logger.With("module", "privval"), listener)
-	if err := pvsc.Start(); err != nil {
-		return nil, errors.Wrap(err, "failed to start private validator")
+	return privval.NewSocketVal(logger.With("module", "privval"), listener, privval.SocketValMetrics(metrics)), nil
+}
+
+// createAndStartLockedPV wraps privValidator to only sign while the node
+// holds the signing lock of the config.
+func createAndStartLockedPV(
+	config *cfg.Config,
+	privValidator types.PrivValidator,
+	nodeID p2p.ID,
+	logger log.Logger,
+) (*privval.LockedPV, error) {
+	var lock privval.SigningLock
+	switch config.PrivValidatorLock {
+	case cfg.SigningLockFile:
+		lock = privval.NewFileSigningLock(config.PrivValidatorLockFile())
+	case cfg.SigningLockRemote:
+		remoteLock, ok := privValidator.(privval.SigningLock)
+		if !ok {
+			return nil, errors.New("the remote signing lock requires a remote signer")
+		}
+		lock = remoteLock
+	default:
+		return nil, fmt.Errorf("unknown signing lock %q", config.PrivValidatorLock)
 	}
 
-	return pvsc, nil
+	pv := privval.NewLockedPV(privValidator, lock, string(nodeID), config.PrivValidatorLockLease)
+	pv.SetLogger(logger.With("module", "privval"))
+	if err := pv.Start(); err != nil {
+		return nil, err
+	}
+	return pv, nil
 }
 
 // splitAndTrimEmpty slices s into all subslices separated by sep and returns a
@@ -2,8 +2,12 @@ package node
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"syscall"
 	"testing"
@@ -23,6 +27,7 @@ import (
 	"github.com/tendermint/tendermint/p2p"
 	"github.com/tendermint/tendermint/privval"
 	"github.com/tendermint/tendermint/proxy"
+	rpcserver "github.com/tendermint/tendermint/rpc/lib/server"
 	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
 	tmtime "github.com/tendermint/tendermint/types/time"
@@ -36,8 +41,10 @@ func TestNodeStartStop(t *testing.T) {
 	// create & start node
 	n, err := DefaultNewNode(config, log.TestingLogger())
 	require.NoError(t, err)
+	require.False(t, n.uptimeTracker.IsRunning(), "the services are started with the node")
 	err = n.Start()
 	require.NoError(t, err)
+	require.True(t, n.uptimeTracker.IsRunning())
 
 	t.Logf("Started node %v", n.sw.NodeInfo())
 
@@ -68,6 +75,73 @@ func TestNodeStartStop(t *testing.T) {
 		fmt.Println(err)
 		t.Fatal("timed out waiting for shutdown")
 	}
+	assert.False(t, n.uptimeTracker.IsRunning())
+}
+
+func TestNodeReplica(t *testing.T) {
+	config := cfg.ResetTestRoot("node_replica_test")
+	defer os.RemoveAll(config.RootDir)
+	config.Replica = true
+
+	// the validator key is the one of the only validator, but is never used
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	assert.True(t, n.consensusState.GetState().Validators.HasAddress(n.PrivValidator().GetPubKey().Address()))
+	err = n.Start()
+	require.NoError(t, err)
+	defer n.Stop()
+
+	blockCh := make(chan interface{})
+	err = n.EventBus().Subscribe(context.Background(), "node_test", types.EventQueryNewBlock, blockCh)
+	require.NoError(t, err)
+	select {
+	case <-blockCh:
+		t.Fatal("a replica produced a block")
+	case <-time.After(2 * time.Second):
+	}
+}
+
+func TestNodeDebugState(t *testing.T) {
+	config := cfg.ResetTestRoot("node_debug_state_test")
+	defer os.RemoveAll(config.RootDir)
+
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+
+	state := n.DebugState()
+	assert.False(t, state.Services["consensus"])
+	assert.Equal(t, "pending", state.Consensus.WALCatchup)
+	assert.Nil(t, state.Consensus.WAL)
+
+	err = n.Start()
+	require.NoError(t, err)
+	defer n.Stop()
+
+	// wait for the node to produce a block
+	blockCh := make(chan interface{}, 1)
+	err = n.EventBus().Subscribe(context.Background(), "node_test", types.EventQueryNewBlock, blockCh)
+	require.NoError(t, err)
+	select {
+	case <-blockCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the node to produce a block")
+	}
+	err = n.EventBus().UnsubscribeAll(context.Background(), "node_test")
+	require.NoError(t, err)
+
+	state = n.DebugState()
+	for name, running := range state.Services {
+		assert.True(t, running, name)
+	}
+	assert.True(t, state.Reactors["CONSENSUS"])
+	// the only validator doesn't fast sync
+	assert.False(t, state.FastSync.Enabled)
+	assert.False(t, state.Consensus.WaitingForFastSync)
+	assert.Equal(t, "done", state.Consensus.WALCatchup)
+	if assert.NotNil(t, state.Consensus.WAL) {
+		assert.True(t, state.Consensus.WAL.HeadSize > 0)
+	}
+	assert.True(t, state.Consensus.Height > 1)
 }
 
 func TestSplitAndTrimEmpty(t *testing.T) {
@@ -92,16 +166,30 @@ func TestSplitAndTrimEmpty(t *testing.T) {
 func TestNodeDelayedStart(t *testing.T) {
 	config := cfg.ResetTestRoot("node_delayed_start_test")
 	defer os.RemoveAll(config.RootDir)
-	now := tmtime.Now()
+	genTime := tmtime.Now().Add(2 * time.Second)
+	genDoc, err := types.GenesisDocFromFile(config.GenesisFile())
+	require.NoError(t, err)
+	genDoc.GenesisTime = genTime
+	require.NoError(t, genDoc.SaveAs(config.GenesisFile()))
 
 	// create & start node
 	n, err := DefaultNewNode(config, log.TestingLogger())
-	n.GenesisDoc().GenesisTime = now.Add(2 * time.Second)
 	require.NoError(t, err)
+	blockCh := make(chan interface{})
+	err = n.EventBus().Subscribe(context.Background(), "node_test", types.EventQueryNewBlock, blockCh)
+	require.NoError(t, err)
+	err = n.Start()
+	require.NoError(t, err)
+	defer n.Stop()
 
-	n.Start()
-	startTime := tmtime.Now()
-	assert.Equal(t, true, startTime.After(n.GenesisDoc().GenesisTime))
+	// the node starts right away, but not the first block
+	assert.True(t, tmtime.Now().Before(genTime))
+	select {
+	case <-blockCh:
+		assert.True(t, tmtime.Now().After(genTime))
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the node to produce a block")
+	}
 }
 
 func TestNodeSetAppVersion(t *testing.T) {
@@ -123,6 +211,192 @@ func TestNodeSetAppVersion(t *testing.T) {
 	assert.Equal(t, n.nodeInfo.(p2p.DefaultNodeInfo).ProtocolVersion.App, appVersion)
 }
 
+func TestNodeGenesisHash(t *testing.T) {
+	config := cfg.ResetTestRoot("node_genesis_hash_test")
+	defer os.RemoveAll(config.RootDir)
+	genesis, err := ioutil.ReadFile(config.GenesisFile())
+	require.NoError(t, err)
+	hash := sha256.Sum256(genesis)
+
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	assert.Equal(t, cmn.HexBytes(hash[:]), n.GenesisHash())
+	assert.Equal(t, cmn.HexBytes(hash[:]), n.nodeInfo.(p2p.DefaultNodeInfo).GenesisHash)
+
+	// the expected hash is checked
+	config.GenesisHash = fmt.Sprintf("%x", hash)
+	_, err = DefaultNewNode(config, log.TestingLogger())
+	assert.NoError(t, err)
+
+	hash[0]++
+	config.GenesisHash = fmt.Sprintf("%x", hash)
+	_, err = DefaultNewNode(config, log.TestingLogger())
+	assert.Error(t, err)
+}
+
+func TestMemoryMonitor(t *testing.T) {
+	m := newMemoryMonitor(1000)
+	var alloc uint64
+	m.heapAlloc = func() uint64 { return alloc }
+
+	for _, tc := range []struct {
+		alloc      uint64
+		overloaded bool
+	}{
+		{500, false},
+		{901, true},
+		{850, true}, // until back under the resume limit
+		{799, false},
+		{850, false},
+	} {
+		alloc = tc.alloc
+		m.check()
+		assert.Equal(t, tc.overloaded, m.Overloaded(), "heap alloc %d", tc.alloc)
+	}
+}
+
+func TestNodeFilterOptions(t *testing.T) {
+	config := cfg.ResetTestRoot("node_filter_options_test")
+	defer os.RemoveAll(config.RootDir)
+	config.P2P.AllowDuplicateIP = false
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+
+	connFilter := func(p2p.ConnSet, net.Conn, []net.IP) error { return nil }
+	peerFilter := func(p2p.IPeerSet, p2p.Peer) error { return nil }
+
+	n, err := NewNode(config,
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()),
+		DefaultGenesisDocProviderFunc(config),
+		DefaultDBProvider,
+		DefaultMetricsProvider(config.Instrumentation),
+		log.TestingLogger(),
+		ConnFilters(connFilter),
+		PeerFilters(peerFilter, peerFilter),
+	)
+	require.NoError(t, err)
+
+	// custom filters are appended to the ones derived from the config
+	assert.Len(t, n.connFilters, 2)
+	assert.Len(t, n.peerFilters, 2)
+}
+
+func TestNodeMempoolCheckOptions(t *testing.T) {
+	config := cfg.ResetTestRoot("node_mempool_check_options_test")
+	defer os.RemoveAll(config.RootDir)
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+
+	errPreCheck := errors.New("pre check")
+	n, err := NewNode(config,
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()),
+		DefaultGenesisDocProviderFunc(config),
+		DefaultDBProvider,
+		DefaultMetricsProvider(config.Instrumentation),
+		log.TestingLogger(),
+		MempoolPreChecks(func(tx types.Tx) error {
+			if string(tx) == "pre" {
+				return errPreCheck
+			}
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	// the checks are the ones of the mempool built by NewNode
+	mempool := n.MempoolReactor().Mempool
+	assert.Equal(t, mempl.ErrPreCheck{Reason: errPreCheck}, mempool.CheckTx(types.Tx("pre"), nil))
+	assert.NoError(t, mempool.CheckTx(types.Tx("ok"), nil))
+}
+
+func TestNodeTransportOptions(t *testing.T) {
+	config := cfg.ResetTestRoot("node_transport_options_test")
+	defer os.RemoveAll(config.RootDir)
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+
+	listened := make(chan string, 1)
+	n, err := NewNode(config,
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()),
+		DefaultGenesisDocProviderFunc(config),
+		DefaultDBProvider,
+		DefaultMetricsProvider(config.Instrumentation),
+		log.TestingLogger(),
+		TransportOptions(p2p.MultiplexTransportListen(func(network, address string) (net.Listener, error) {
+			listened <- address
+			return net.Listen(network, address)
+		})),
+	)
+	require.NoError(t, err)
+
+	// the transport built by NewNode listens with the option
+	require.NoError(t, n.Start())
+	defer n.Stop()
+	select {
+	case <-listened:
+	default:
+		t.Fatal("the transport didn't listen with the option")
+	}
+}
+
+func TestNodeRPCTenants(t *testing.T) {
+	config := cfg.ResetTestRoot("node_rpc_tenants_test")
+	defer os.RemoveAll(config.RootDir)
+	config.RPC.ListenAddress = "tcp://127.0.0.1:0"
+	config.RPC.GRPCListenAddress = ""
+	config.RPC.TenantPrefix = "/self"
+	config.RPC.AuthTokens = []string{"secret"}
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+
+	other := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	n, err := NewNode(config,
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()),
+		DefaultGenesisDocProviderFunc(config),
+		DefaultDBProvider,
+		DefaultMetricsProvider(config.Instrumentation),
+		log.TestingLogger(),
+		RPCTenants(rpcserver.Tenant{Name: "other", Prefix: "/other", Handler: other}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, n.Start())
+	defer n.Stop()
+
+	get := func(path, token string) int {
+		req, err := http.NewRequest("GET", "http://"+n.rpcListeners[0].Addr().String()+path, nil)
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		res.Body.Close()
+		return res.StatusCode
+	}
+	assert.Equal(t, http.StatusNoContent, get("/other/status", ""))
+	assert.Equal(t, http.StatusOK, get("/self/status", "secret"))
+	assert.Equal(t, http.StatusUnauthorized, get("/self/status", ""))
+	assert.Equal(t, http.StatusNotFound, get("/status", "secret"))
+
+	// the RPC of the node is served from its own environment
+	assert.True(t, n.ConfigureRPC() == n.ConfigureRPC())
+	assert.True(t, n.EventBus() == n.ConfigureRPC().EventBus)
+}
+
 func TestNodeSetPrivValTCP(t *testing.T) {
 	addr := "tcp://" + testFreeAddr(t)
 
@@ -0,0 +1,157 @@
+package p2p
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tendermint/tendermint/crypto"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+const (
+	maxCertificateOrgLength       = 256
+	maxCertificateSignatureLength = 128
+)
+
+// PeerCertificate binds the ID of a node to the identity of an organization.
+// It is signed by a certificate authority of a permissioned network, whose
+// nodes only connect to peers presenting a valid certificate in their
+// NodeInfo.
+type PeerCertificate struct {
+	NodeID    ID            `json:"node_id"`
+	Org       string        `json:"org"`
+	Expires   time.Time     `json:"expires"` // never if zero
+	CAPubKey  crypto.PubKey `json:"ca_pub_key"`
+	Signature []byte        `json:"signature"`
+}
+
+// NewPeerCertificate returns a certificate binding the node to the
+// organization until expires (never if zero), signed by the certificate
+// authority.
+func NewPeerCertificate(
+	nodeID ID,
+	org string,
+	expires time.Time,
+	caPrivKey crypto.PrivKey,
+) (*PeerCertificate, error) {
+	cert := &PeerCertificate{
+		NodeID:   nodeID,
+		Org:      org,
+		Expires:  expires,
+		CAPubKey: caPrivKey.PubKey(),
+	}
+	sig, err := caPrivKey.Sign(cert.SignBytes())
+	if err != nil {
+		return nil, err
+	}
+	cert.Signature = sig
+	return cert, cert.ValidateBasic()
+}
+
+// SignBytes returns the bytes signed by the certificate authority.
+func (cert PeerCertificate) SignBytes() []byte {
+	cert.Signature = nil
+	return cdc.MustMarshalBinaryBare(cert)
+}
+
+// ValidateBasic performs basic validation, without checking the signature.
+func (cert *PeerCertificate) ValidateBasic() error {
+	if err := validateID(cert.NodeID); err != nil {
+		return errors.Wrap(err, "invalid certificate node ID")
+	}
+	if len(cert.Org) > maxCertificateOrgLength {
+		return fmt.Errorf("certificate org is too long (%d). Max is %d", len(cert.Org), maxCertificateOrgLength)
+	}
+	if !cmn.IsASCIIText(cert.Org) || cmn.ASCIITrim(cert.Org) == "" {
+		return fmt.Errorf("certificate org must be valid non-empty ASCII text without tabs, but got %v", cert.Org)
+	}
+	if cert.CAPubKey == nil {
+		return errors.New("certificate has no certificate authority")
+	}
+	if len(cert.Signature) == 0 || len(cert.Signature) > maxCertificateSignatureLength {
+		return fmt.Errorf("certificate signature must be between 1 and %d bytes, got %d",
+			maxCertificateSignatureLength, len(cert.Signature))
+	}
+	return nil
+}
+
+// Verify returns an error if the certificate is not the one of the node, is
+// not signed by one of the certificate authorities, or is expired at now.
+func (cert *PeerCertificate) Verify(nodeID ID, cas []crypto.PubKey, now time.Time) error {
+	if err := cert.ValidateBasic(); err != nil {
+		return err
+	}
+	if cert.NodeID != nodeID {
+		return fmt.Errorf("certificate of node %v, not %v", cert.NodeID, nodeID)
+	}
+	if !cert.Expires.IsZero() && !now.Before(cert.Expires) {
+		return fmt.Errorf("certificate expired at %v", cert.Expires)
+	}
+	for _, ca := range cas {
+		if ca.Equals(cert.CAPubKey) {
+			if !ca.VerifyBytes(cert.SignBytes(), cert.Signature) {
+				return errors.New("invalid certificate signature")
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("certificate signed by unknown certificate authority %v", cert.CAPubKey)
+}
+
+// SaveAs persists the certificate to filePath.
+func (cert *PeerCertificate) SaveAs(filePath string) error {
+	jsonBytes, err := cdc.MarshalJSONIndent(cert, "", "  ")
+	if err != nil {
+		return err
+	}
+	return cmn.WriteFileAtomic(filePath, jsonBytes, 0644)
+}
+
+// LoadPeerCertificate loads a certificate saved with SaveAs.
+func LoadPeerCertificate(filePath string) (*PeerCertificate, error) {
+	jsonBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	cert := new(PeerCertificate)
+	if err := cdc.UnmarshalJSON(jsonBytes, cert); err != nil {
+		return nil, fmt.Errorf("Error reading PeerCertificate from %v: %v", filePath, err)
+	}
+	if err := cert.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("Invalid PeerCertificate in %v: %v", filePath, err)
+	}
+	return cert, nil
+}
+
+// LoadCertificateAuthorities loads the public keys of the certificate
+// authorities from filePath, a JSON list.
+func LoadCertificateAuthorities(filePath string) ([]crypto.PubKey, error) {
+	jsonBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var cas []crypto.PubKey
+	if err := cdc.UnmarshalJSON(jsonBytes, &cas); err != nil {
+		return nil, fmt.Errorf("Error reading the certificate authorities from %v: %v", filePath, err)
+	}
+	if len(cas) == 0 {
+		return nil, fmt.Errorf("No certificate authority in %v", filePath)
+	}
+	return cas, nil
+}
+
+// validateID returns an error if id is not a hex-encoded address.
+func validateID(id ID) error {
+	bz, err := hex.DecodeString(string(id))
+	if err != nil {
+		return err
+	}
+	if len(bz) != IDByteLength {
+		return fmt.Errorf("ID must be %d bytes, got %d", IDByteLength, len(bz))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package p2p
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+func TestPeerCertificateVerify(t *testing.T) {
+	caKey := ed25519.GenPrivKey()
+	cas := []crypto.PubKey{ed25519.GenPrivKey().PubKey(), caKey.PubKey()}
+	nodeID := PubKeyToID(ed25519.GenPrivKey().PubKey())
+	now := time.Now()
+
+	cert, err := NewPeerCertificate(nodeID, "org", now.Add(time.Hour), caKey)
+	require.NoError(t, err)
+	assert.NoError(t, cert.Verify(nodeID, cas, now))
+
+	// expired
+	assert.Error(t, cert.Verify(nodeID, cas, now.Add(time.Hour)))
+	// another node
+	assert.Error(t, cert.Verify(PubKeyToID(ed25519.GenPrivKey().PubKey()), cas, now))
+	// unknown CA
+	assert.Error(t, cert.Verify(nodeID, cas[:1], now))
+
+	// tampered
+	tampered := *cert
+	tampered.Org = "other org"
+	assert.Error(t, tampered.Verify(nodeID, cas, now))
+
+	// never expires
+	cert, err = NewPeerCertificate(nodeID, "org", time.Time{}, caKey)
+	require.NoError(t, err)
+	assert.NoError(t, cert.Verify(nodeID, cas, now.Add(100*365*24*time.Hour)))
+}
+
+func TestPeerCertificateValidateBasic(t *testing.T) {
+	caKey := ed25519.GenPrivKey()
+	nodeID := PubKeyToID(ed25519.GenPrivKey().PubKey())
+
+	_, err := NewPeerCertificate("not an ID", "org", time.Time{}, caKey)
+	assert.Error(t, err)
+	_, err = NewPeerCertificate(nodeID, "", time.Time{}, caKey)
+	assert.Error(t, err)
+	_, err = NewPeerCertificate(nodeID, "org\twith tab", time.Time{}, caKey)
+	assert.Error(t, err)
+
+	cert, err := NewPeerCertificate(nodeID, "org", time.Time{}, caKey)
+	require.NoError(t, err)
+	cert.Signature = make([]byte, maxCertificateSignatureLength+1)
+	assert.Error(t, cert.ValidateBasic())
+}
+
+func TestPeerCertificateSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "peer_certificate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	caKey := ed25519.GenPrivKey()
+	nodeID := PubKeyToID(ed25519.GenPrivKey().PubKey())
+	cert, err := NewPeerCertificate(nodeID, "org", time.Now().Add(time.Hour).UTC(), caKey)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "cert.json")
+	require.NoError(t, cert.SaveAs(certFile))
+	loaded, err := LoadPeerCertificate(certFile)
+	require.NoError(t, err)
+	assert.NoError(t, loaded.Verify(nodeID, []crypto.PubKey{caKey.PubKey()}, time.Now()))
+
+	caFile := filepath.Join(dir, "ca.json")
+	bz, err := cdc.MarshalJSON([]crypto.PubKey{caKey.PubKey()})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(caFile, bz, 0644))
+	cas, err := LoadCertificateAuthorities(caFile)
+	require.NoError(t, err)
+	require.Len(t, cas, 1)
+	assert.True(t, cas[0].Equals(caKey.PubKey()))
+
+	require.NoError(t, ioutil.WriteFile(caFile, []byte("[]"), 0644))
+	_, err = LoadCertificateAuthorities(caFile)
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+package p2p
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+const maxClockSyncMsgSize = 64
+
+// ClockOffset is the offset of the clock of a peer against ours, estimated
+// from the timestamps exchanged after the handshake, as NTP does. The
+// estimation is off by at most half of the RTT.
+type ClockOffset struct {
+	Offset time.Duration `json:"offset"` // peer clock minus our clock
+	RTT    time.Duration `json:"rtt"`    // round trip time of the exchange
+}
+
+// clockSyncMsg is sent twice by both nodes: first as a ping, then as the pong
+// of the ping of the other node, with the time it received it.
+type clockSyncMsg struct {
+	Received time.Time // zero in the ping
+	Sent     time.Time
+}
+
+// syncClocks exchanges the timestamps estimating the ClockOffset of the peer,
+// with the nodes which announce it in their NodeInfo.
+func syncClocks(c net.Conn, timeout time.Duration) (*ClockOffset, error) {
+	if err := c.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	var (
+		errc  = make(chan error, 2)
+		pongc = make(chan clockSyncMsg, 1)
+
+		// Our times keep their monotonic clock reading, to measure the RTT.
+		pingTime time.Time
+		pongTime time.Time
+		peerPong clockSyncMsg
+	)
+
+	go func(errc chan<- error, c net.Conn) {
+		pingTime = time.Now()
+		_, err := cdc.MarshalBinaryLengthPrefixedWriter(c, clockSyncMsg{Sent: pingTime.UTC()})
+		if err != nil {
+			errc <- err
+			return
+		}
+		pong, ok := <-pongc
+		if !ok {
+			// The reader failed.
+			errc <- nil
+			return
+		}
+		pong.Sent = time.Now().UTC()
+		_, err = cdc.MarshalBinaryLengthPrefixedWriter(c, pong)
+		errc <- err
+	}(errc, c)
+	go func(errc chan<- error, c net.Conn) {
+		defer close(pongc)
+
+		var peerPing clockSyncMsg
+		_, err := cdc.UnmarshalBinaryLengthPrefixedReader(c, &peerPing, maxClockSyncMsgSize)
+		if err != nil {
+			errc <- err
+			return
+		}
+		pongc <- clockSyncMsg{Received: time.Now().UTC()}
+
+		_, err = cdc.UnmarshalBinaryLengthPrefixedReader(c, &peerPong, maxClockSyncMsgSize)
+		pongTime = time.Now()
+		errc <- err
+	}(errc, c)
+
+	for i := 0; i < cap(errc); i++ {
+		err := <-errc
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if peerPong.Received.IsZero() || peerPong.Sent.Before(peerPong.Received) {
+		return nil, errors.New("invalid clock sync pong")
+	}
+	offset := clockOffset(pingTime, peerPong.Received, peerPong.Sent, pongTime)
+	return offset, c.SetDeadline(time.Time{})
+}
+
+// clockOffset returns the ClockOffset of the peer from the time we sent our
+// ping, the ones the peer received it and sent its pong, and the one we
+// received the pong.
+func clockOffset(pingTime, pingReceived, pongSent, pongTime time.Time) *ClockOffset {
+	rtt := pongTime.Sub(pingTime) - pongSent.Sub(pingReceived)
+	if rtt < 0 {
+		rtt = 0
+	}
+	return &ClockOffset{
+		Offset: (pingReceived.Sub(pingTime) + pongSent.Sub(pongTime)) / 2,
+		RTT:    rtt,
+	}
+}
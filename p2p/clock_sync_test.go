@@ -0,0 +1,68 @@
+package p2p
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockOffset(t *testing.T) {
+	var (
+		now = time.Now()
+		at  = func(ms int) time.Time { return now.Add(time.Duration(ms) * time.Millisecond) }
+	)
+
+	testCases := []struct {
+		name                                     string
+		pingTime, pingReceived, pongSent, pongAt time.Time
+		offset, rtt                              time.Duration
+	}{
+		{"same clocks", at(0), at(50), at(60), at(110), 0, 100 * time.Millisecond},
+		{"peer ahead", at(0), at(1050), at(1060), at(110), time.Second, 100 * time.Millisecond},
+		{"peer behind", at(0), at(-950), at(-940), at(110), -time.Second, 100 * time.Millisecond},
+		{"asymmetric delays", at(0), at(80), at(80), at(100), 30 * time.Millisecond, 100 * time.Millisecond},
+		{"negative rtt", at(0), at(0), at(200), at(100), 50 * time.Millisecond, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			offset := clockOffset(tc.pingTime, tc.pingReceived, tc.pongSent, tc.pongAt)
+			assert.Equal(t, tc.offset, offset.Offset)
+			assert.Equal(t, tc.rtt, offset.RTT)
+		})
+	}
+}
+
+func TestSyncClocks(t *testing.T) {
+	c1, c2 := net.Pipe()
+
+	offsetc := make(chan *ClockOffset, 1)
+	go func() {
+		offset, err := syncClocks(c2, time.Second)
+		assert.NoError(t, err)
+		offsetc <- offset
+	}()
+
+	offset, err := syncClocks(c1, time.Second)
+	require.NoError(t, err)
+	peerOffset := <-offsetc
+	require.NotNil(t, peerOffset)
+
+	// Both nodes share the clock, off by at most half of the RTT.
+	for _, o := range []*ClockOffset{offset, peerOffset} {
+		assert.True(t, o.RTT >= 0 && o.RTT < time.Second, "rtt %v", o.RTT)
+		assert.True(t, o.Offset <= o.RTT/2+time.Millisecond && o.Offset >= -o.RTT/2-time.Millisecond,
+			"offset %v, rtt %v", o.Offset, o.RTT)
+	}
+}
+
+func TestSyncClocksTimeout(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+
+	// The peer does not sync the clocks, e.g. it is an older node.
+	_, err := syncClocks(c1, 20*time.Millisecond)
+	assert.Error(t, err)
+}
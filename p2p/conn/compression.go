@@ -0,0 +1,61 @@
+package conn
+
+import (
+	"fmt"
+
+	"github.com/golang/snappy"
+)
+
+const (
+	// CompressionNone disables the compression of the messages.
+	CompressionNone = "none"
+	// CompressionSnappy compresses the messages with snappy.
+	CompressionSnappy = "snappy"
+)
+
+// Prefix of the messages of the compressed channels.
+const (
+	msgUncompressed = byte(0x00)
+	msgSnappy       = byte(0x01)
+)
+
+// compressMsg prefixes the message with its compression, compressing it with
+// snappy if it is at least minSize bytes and compressing makes it smaller.
+func compressMsg(msgBytes []byte, minSize int) []byte {
+	if len(msgBytes) >= minSize {
+		compressed := make([]byte, 1+snappy.MaxEncodedLen(len(msgBytes)))
+		compressed[0] = msgSnappy
+		compressed = compressed[:1+len(snappy.Encode(compressed[1:], msgBytes))]
+		if len(compressed) < 1+len(msgBytes) {
+			return compressed
+		}
+	}
+	return append([]byte{msgUncompressed}, msgBytes...)
+}
+
+// decompressMsg returns the message prefixed with its compression, which must
+// be at most maxSize bytes once decompressed.
+func decompressMsg(msgBytes []byte, maxSize int) ([]byte, error) {
+	if len(msgBytes) == 0 {
+		return nil, fmt.Errorf("Missing compression of the message")
+	}
+	switch prefix, msgBytes := msgBytes[0], msgBytes[1:]; prefix {
+	case msgUncompressed:
+		return msgBytes, nil
+	case msgSnappy:
+		size, err := snappy.DecodedLen(msgBytes)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid compressed message: %v", err)
+		}
+		if size > maxSize {
+			return nil, fmt.Errorf("Decompressed message exceeds available capacity: %v < %v", maxSize, size)
+		}
+		decompressed, err := snappy.Decode(make([]byte, size), msgBytes)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid compressed message: %v", err)
+		}
+		return decompressed, nil
+	default:
+		return nil, fmt.Errorf("Unknown compression of the message %X", prefix)
+	}
+}
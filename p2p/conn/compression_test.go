@@ -0,0 +1,38 @@
+package conn
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressMsg(t *testing.T) {
+	small := []byte("small")
+	compressed := compressMsg(small, 10)
+	assert.Equal(t, append([]byte{msgUncompressed}, small...), compressed)
+	decompressed, err := decompressMsg(compressed, len(small))
+	require.NoError(t, err)
+	assert.Equal(t, small, decompressed)
+
+	large := bytes.Repeat([]byte("large"), 100)
+	compressed = compressMsg(large, 10)
+	assert.Equal(t, msgSnappy, compressed[0])
+	assert.True(t, len(compressed) < len(large))
+	decompressed, err = decompressMsg(compressed, len(large))
+	require.NoError(t, err)
+	assert.Equal(t, large, decompressed)
+
+	// too large once decompressed
+	_, err = decompressMsg(compressed, len(large)-1)
+	assert.Error(t, err)
+
+	// invalid
+	_, err = decompressMsg(nil, 10)
+	assert.Error(t, err)
+	_, err = decompressMsg([]byte{0x02, 0x01}, 10)
+	assert.Error(t, err)
+	_, err = decompressMsg([]byte{msgSnappy, 0xff}, 10)
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package conn
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -26,6 +27,10 @@ const (
 	minWriteBufferSize = 65536
 	updateStats        = 2 * time.Second
 
+	// maxRecvPausedMsgs is the number of msgs queued on a channel whose
+	// receiving is paused, past which the connection stops reading.
+	maxRecvPausedMsgs = 100
+
 	// some of these defaults are written in the user config
 	// flushThrottle, sendRate, recvRate
 	// TODO: remove values present in config
@@ -39,6 +44,7 @@ const (
 	defaultSendTimeout         = 10 * time.Second
 	defaultPingInterval        = 60 * time.Second
 	defaultPongTimeout         = 45 * time.Second
+	defaultCompressionMinSize  = 1024
 )
 
 type receiveCbFunc func(chID byte, msgBytes []byte)
@@ -67,6 +73,11 @@ request times out.  The message `msg` is serialized using Go-Amino.
 channel's queue is full.
 
 Inbound message bytes are handled with an onReceive callback function.
+Receiving can be paused per channel with `PauseRecv(chID)`, in which case the
+msgs of the channel are queued until `ResumeRecv(chID)` is called, while the
+other channels and the pings/pongs are still read. If too many msgs get
+queued, the connection stops reading, applying backpressure to the remote
+peer.
 */
 type MConnection struct {
 	cmn.BaseService
@@ -94,6 +105,10 @@ type MConnection struct {
 	// are safe to call concurrently.
 	stopMtx sync.Mutex
 
+	// serializes the onReceive calls of the recvRoutine and of the channels
+	// delivering the msgs queued while paused.
+	onReceiveMtx sync.Mutex
+
 	flushTimer *cmn.ThrottleTimer // flush writes as necessary but throttled.
 	pingTimer  *cmn.RepeatTimer   // send pings periodically
 
@@ -124,6 +139,13 @@ type MConnConfig struct {
 
 	// Maximum wait time for pongs
 	PongTimeout time.Duration `mapstructure:"pong_timeout"`
+
+	// Channels whose messages are compressed with snappy, negotiated with the
+	// peer in the handshake
+	CompressedChannels []byte `mapstructure:"compressed_channels"`
+
+	// Minimum size of the messages compressed
+	CompressionMinSize int `mapstructure:"compression_min_size"`
 }
 
 // DefaultMConnConfig returns the default config.
@@ -135,6 +157,7 @@ func DefaultMConnConfig() MConnConfig {
 		FlushThrottle:           defaultFlushThrottle,
 		PingInterval:            defaultPingInterval,
 		PongTimeout:             defaultPongTimeout,
+		CompressionMinSize:      defaultCompressionMinSize,
 	}
 }
 
@@ -316,6 +339,43 @@ func (c *MConnection) stopForError(r interface{}) {
 	}
 }
 
+// PauseRecv pauses the delivery of messages received on the channel: they are
+// queued, and delivered in order once ResumeRecv is called, while the other
+// channels and the pings/pongs are still read. The messages are delivered one
+// at a time, whether they were queued or not. Once maxRecvPausedMsgs messages
+// are queued, the recvRoutine blocks until ResumeRecv is called, so the remote
+// peer gets throttled by TCP flow control instead of us buffering its
+// messages.
+// NOTE: while blocked, no other channel, nor pings/pongs, are read, so such
+// pauses longer than the pong timeout will cause the connection to be closed.
+// Goroutine-safe.
+func (c *MConnection) PauseRecv(chID byte) {
+	channel, ok := c.channelsIdx[chID]
+	if !ok {
+		c.Logger.Error(fmt.Sprintf("Cannot pause receiving, unknown channel %X", chID))
+		return
+	}
+	channel.pauseRecv()
+}
+
+// ResumeRecv resumes the delivery of messages received on the channel.
+// Goroutine-safe.
+func (c *MConnection) ResumeRecv(chID byte) {
+	channel, ok := c.channelsIdx[chID]
+	if !ok {
+		c.Logger.Error(fmt.Sprintf("Cannot resume receiving, unknown channel %X", chID))
+		return
+	}
+	channel.resumeRecv()
+}
+
+// deliver calls onReceive with the msg, one msg of the connection at a time.
+func (c *MConnection) deliver(chID byte, msgBytes []byte) {
+	c.onReceiveMtx.Lock()
+	defer c.onReceiveMtx.Unlock()
+	c.onReceive(chID, msgBytes)
+}
+
 // Queues a message to be sent to channel.
 func (c *MConnection) Send(chID byte, msgBytes []byte) bool {
 	if !c.IsRunning() {
@@ -331,7 +391,7 @@ func (c *MConnection) Send(chID byte, msgBytes []byte) bool {
 		return false
 	}
 
-	success := channel.sendBytes(msgBytes)
+	success := channel.sendBytes(channel.compressMsg(msgBytes))
 	if success {
 		// Wake up sendRoutine if necessary
 		select {
@@ -360,7 +420,7 @@ func (c *MConnection) TrySend(chID byte, msgBytes []byte) bool {
 		return false
 	}
 
-	ok = channel.trySendBytes(msgBytes)
+	ok = channel.trySendBytes(channel.compressMsg(msgBytes))
 	if ok {
 		// Wake up sendRoutine if necessary
 		select {
@@ -577,6 +637,11 @@ FOR_LOOP:
 				// never block
 			}
 		case PacketMsg:
+			if err := pkt.ValidateBasic(c.config.MaxPacketMsgPayloadSize); err != nil {
+				c.Logger.Error("Connection failed @ recvRoutine", "conn", c, "err", err)
+				c.stopForError(err)
+				break FOR_LOOP
+			}
 			channel, ok := c.channelsIdx[pkt.ChannelID]
 			if !ok || channel == nil {
 				err := fmt.Errorf("Unknown channel %X", pkt.ChannelID)
@@ -593,10 +658,21 @@ FOR_LOOP:
 				}
 				break FOR_LOOP
 			}
+			if msgBytes != nil && channel.compressed {
+				msgBytes, err = decompressMsg(msgBytes, channel.desc.RecvMessageCapacity)
+				if err != nil {
+					c.Logger.Error("Connection failed @ recvRoutine", "conn", c, "err", err)
+					c.stopForError(err)
+					break FOR_LOOP
+				}
+			}
 			if msgBytes != nil {
 				c.Logger.Debug("Received bytes", "chID", pkt.ChannelID, "msgBytes", fmt.Sprintf("%X", msgBytes))
-				// NOTE: This means the reactor.Receive runs in the same thread as the p2p recv routine
-				c.onReceive(pkt.ChannelID, msgBytes)
+				// NOTE: This means the reactor.Receive runs in the same thread as the p2p recv routine,
+				// but for the msgs queued while the receiving reactor is busy with this channel.
+				if !channel.recvMsg(msgBytes) {
+					break FOR_LOOP
+				}
 			}
 		default:
 			err := fmt.Errorf("Unknown message type %v", reflect.TypeOf(packet))
@@ -644,6 +720,11 @@ type ChannelStatus struct {
 	SendQueueSize     int
 	Priority          int
 	RecentlySent      int64
+
+	// Size of the messages sent on a compressed channel, before and after
+	// compression.
+	UncompressedBytes int64
+	CompressedBytes   int64
 }
 
 func (c *MConnection) Status() ConnectionStatus {
@@ -659,6 +740,8 @@ func (c *MConnection) Status() ConnectionStatus {
 			SendQueueSize:     int(atomic.LoadInt32(&channel.sendQueueSize)),
 			Priority:          channel.desc.Priority,
 			RecentlySent:      atomic.LoadInt64(&channel.recentlySent),
+			UncompressedBytes: atomic.LoadInt64(&channel.uncompressedBytes),
+			CompressedBytes:   atomic.LoadInt64(&channel.compressedBytes),
 		}
 	}
 	return status
@@ -699,6 +782,20 @@ type Channel struct {
 	sending       []byte
 	recentlySent  int64 // exponential moving average
 
+	// messages are prefixed with their compression.
+	compressed        bool
+	uncompressedBytes int64 // atomic.
+	compressedBytes   int64 // atomic.
+
+	// closed and reset to nil on resume, non-nil while receiving is paused.
+	recvMtx      sync.Mutex
+	recvResumeCh chan struct{}
+	// msgs received while paused, or while the ones received before are
+	// delivered by recvPausedRoutine.
+	recvPaused     [][]byte
+	recvDelivering bool
+	recvFullCh     chan struct{} // closed once recvPaused is no longer full
+
 	maxPacketMsgPayloadSize int
 
 	Logger log.Logger
@@ -714,6 +811,7 @@ func newChannel(conn *MConnection, desc ChannelDescriptor) *Channel {
 		desc:                    desc,
 		sendQueue:               make(chan []byte, desc.SendQueueCapacity),
 		recving:                 make([]byte, 0, desc.RecvBufferCapacity),
+		compressed:              bytes.IndexByte(conn.config.CompressedChannels, desc.ID) != -1,
 		maxPacketMsgPayloadSize: conn.config.MaxPacketMsgPayloadSize,
 	}
 }
@@ -722,6 +820,18 @@ func (ch *Channel) SetLogger(l log.Logger) {
 	ch.Logger = l
 }
 
+// Prefixes the message with its compression if the channel is compressed.
+// Goroutine-safe
+func (ch *Channel) compressMsg(msgBytes []byte) []byte {
+	if !ch.compressed {
+		return msgBytes
+	}
+	compressed := compressMsg(msgBytes, ch.conn.config.CompressionMinSize)
+	atomic.AddInt64(&ch.uncompressedBytes, int64(len(msgBytes)))
+	atomic.AddInt64(&ch.compressedBytes, int64(len(compressed)))
+	return compressed
+}
+
 // Queues message to send to this channel.
 // Goroutine-safe
 // Times out (and returns false) after defaultSendTimeout
@@ -805,6 +915,9 @@ func (ch *Channel) writePacketMsgTo(w io.Writer) (n int64, err error) {
 func (ch *Channel) recvPacketMsg(packet PacketMsg) ([]byte, error) {
 	ch.Logger.Debug("Read PacketMsg", "conn", ch.conn, "packet", packet)
 	var recvCap, recvReceived = ch.desc.RecvMessageCapacity, len(ch.recving) + len(packet.Bytes)
+	if ch.compressed {
+		recvCap++ // compression prefix
+	}
 	if recvCap < recvReceived {
 		return nil, fmt.Errorf("Received message exceeds available capacity: %v < %v", recvCap, recvReceived)
 	}
@@ -822,6 +935,90 @@ func (ch *Channel) recvPacketMsg(packet PacketMsg) ([]byte, error) {
 	return nil, nil
 }
 
+// Goroutine-safe
+func (ch *Channel) pauseRecv() {
+	ch.recvMtx.Lock()
+	defer ch.recvMtx.Unlock()
+	if ch.recvResumeCh == nil {
+		ch.recvResumeCh = make(chan struct{})
+	}
+}
+
+// Goroutine-safe
+func (ch *Channel) resumeRecv() {
+	ch.recvMtx.Lock()
+	defer ch.recvMtx.Unlock()
+	if ch.recvResumeCh == nil {
+		return
+	}
+	close(ch.recvResumeCh)
+	ch.recvResumeCh = nil
+	if len(ch.recvPaused) > 0 && !ch.recvDelivering {
+		ch.recvDelivering = true
+		go ch.recvPausedRoutine()
+	}
+}
+
+// Delivers the received msg, or queues it if receiving is paused or the msgs
+// queued before are not delivered yet. Blocks while maxRecvPausedMsgs msgs
+// are queued, and returns false if the connection stops meanwhile.
+// Not goroutine-safe, called by the recvRoutine.
+func (ch *Channel) recvMsg(msgBytes []byte) bool {
+	ch.recvMtx.Lock()
+	if ch.recvResumeCh == nil && len(ch.recvPaused) == 0 && !ch.recvDelivering {
+		ch.recvMtx.Unlock()
+		ch.conn.deliver(ch.desc.ID, msgBytes)
+		return true
+	}
+	// msgBytes is the recving buffer of the channel, reused for the next msg.
+	ch.recvPaused = append(ch.recvPaused, append([]byte(nil), msgBytes...))
+	var fullCh chan struct{}
+	if len(ch.recvPaused) >= maxRecvPausedMsgs {
+		if ch.recvFullCh == nil {
+			ch.recvFullCh = make(chan struct{})
+		}
+		fullCh = ch.recvFullCh
+	}
+	ch.recvMtx.Unlock()
+
+	if fullCh != nil {
+		select {
+		case <-fullCh:
+		case <-ch.conn.quitSendRoutine:
+			return false
+		}
+	}
+	return true
+}
+
+// Delivers the msgs queued while receiving was paused, in order, until none
+// is left or receiving is paused again.
+func (ch *Channel) recvPausedRoutine() {
+	for {
+		ch.recvMtx.Lock()
+		if len(ch.recvPaused) == 0 || ch.recvResumeCh != nil {
+			ch.recvDelivering = false
+			ch.recvMtx.Unlock()
+			return
+		}
+		msgBytes := ch.recvPaused[0]
+		ch.recvPaused[0] = nil
+		ch.recvPaused = ch.recvPaused[1:]
+		if ch.recvFullCh != nil {
+			close(ch.recvFullCh)
+			ch.recvFullCh = nil
+		}
+		ch.recvMtx.Unlock()
+
+		select {
+		case <-ch.conn.quitSendRoutine:
+			return
+		default:
+		}
+		ch.conn.deliver(ch.desc.ID, msgBytes)
+	}
+}
+
 // Call this periodically to update stats for throttling purposes.
 // Not goroutine-safe
 func (ch *Channel) updateStats() {
@@ -860,6 +1057,18 @@ type PacketMsg struct {
 	Bytes     []byte
 }
 
+// ValidateBasic returns an error if the payload of the packet is larger than
+// maxPayloadSize, or its EOF is neither 0 nor 1.
+func (mp PacketMsg) ValidateBasic(maxPayloadSize int) error {
+	if len(mp.Bytes) > maxPayloadSize {
+		return fmt.Errorf("PacketMsg payload is too large: %d > %d", len(mp.Bytes), maxPayloadSize)
+	}
+	if mp.EOF > 0x01 {
+		return fmt.Errorf("Invalid PacketMsg EOF %X", mp.EOF)
+	}
+	return nil
+}
+
 func (mp PacketMsg) String() string {
 	return fmt.Sprintf("PacketMsg{%X:%X T:%X}", mp.ChannelID, mp.Bytes, mp.EOF)
 }
@@ -0,0 +1,29 @@
+// +build gofuzz
+
+package conn
+
+import (
+	"bytes"
+)
+
+// Fuzz decodes the packets in data as the receiving routine of a connection
+// does, returning 1 if they are all valid.
+func Fuzz(data []byte) int {
+	config := DefaultMConnConfig()
+	maxPacketMsgSize := (&MConnection{config: config}).maxPacketMsgSize()
+
+	r := bytes.NewReader(data)
+	for r.Len() > 0 {
+		var packet Packet
+		_, err := cdc.UnmarshalBinaryLengthPrefixedReader(r, &packet, int64(maxPacketMsgSize))
+		if err != nil {
+			return 0
+		}
+		if pkt, ok := packet.(PacketMsg); ok {
+			if err := pkt.ValidateBasic(config.MaxPacketMsgPayloadSize); err != nil {
+				return 0
+			}
+		}
+	}
+	return 1
+}
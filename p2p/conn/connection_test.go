@@ -140,6 +140,139 @@ func TestMConnectionReceive(t *testing.T) {
 	}
 }
 
+func TestMConnectionReceiveCompressed(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close() // nolint: errcheck
+	defer client.Close() // nolint: errcheck
+
+	createMConnection := func(conn net.Conn, onReceive func(chID byte, msgBytes []byte)) *MConnection {
+		cfg := DefaultMConnConfig()
+		cfg.CompressedChannels = []byte{0x01}
+		cfg.CompressionMinSize = 10
+		chDescs := []*ChannelDescriptor{{ID: 0x01, Priority: 1, SendQueueCapacity: 1}}
+		c := NewMConnectionWithConfig(conn, chDescs, onReceive, func(r interface{}) {}, cfg)
+		c.SetLogger(log.TestingLogger())
+		return c
+	}
+
+	receivedCh := make(chan []byte)
+	mconn1 := createMConnection(client, func(chID byte, msgBytes []byte) {
+		receivedCh <- append([]byte{}, msgBytes...)
+	})
+	err := mconn1.Start()
+	require.Nil(t, err)
+	defer mconn1.Stop()
+
+	mconn2 := createMConnection(server, func(chID byte, msgBytes []byte) {})
+	err = mconn2.Start()
+	require.Nil(t, err)
+	defer mconn2.Stop()
+
+	for _, msg := range [][]byte{[]byte("Storm"), bytes.Repeat([]byte("Wolverine"), 1000)} {
+		assert.True(t, mconn2.Send(0x01, msg))
+
+		select {
+		case receivedBytes := <-receivedCh:
+			assert.Equal(t, msg, receivedBytes)
+		case <-time.After(500 * time.Millisecond):
+			t.Fatalf("Did not receive %d bytes message in 500ms", len(msg))
+		}
+	}
+
+	status := mconn2.Status()
+	assert.EqualValues(t, 5+9000, status.Channels[0].UncompressedBytes)
+	assert.True(t, status.Channels[0].CompressedBytes < 1000)
+}
+
+func TestMConnectionPauseRecv(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close() // nolint: errcheck
+	defer client.Close() // nolint: errcheck
+
+	receivedCh := make(chan []byte)
+	errorsCh := make(chan interface{})
+	onReceive := func(chID byte, msgBytes []byte) {
+		receivedCh <- msgBytes
+	}
+	onError := func(r interface{}) {
+		errorsCh <- r
+	}
+	mconn1 := createMConnectionWithCallbacks(client, onReceive, onError)
+	err := mconn1.Start()
+	require.Nil(t, err)
+	defer mconn1.Stop()
+
+	mconn2 := createTestMConnection(server)
+	err = mconn2.Start()
+	require.Nil(t, err)
+	defer mconn2.Stop()
+
+	mconn1.PauseRecv(0x01)
+
+	msg := []byte("Iceman")
+	assert.True(t, mconn2.Send(0x01, msg))
+
+	select {
+	case receivedBytes := <-receivedCh:
+		t.Fatalf("Expected no message while paused, got %s", receivedBytes)
+	case err := <-errorsCh:
+		t.Fatalf("Expected no message while paused, got %+v", err)
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	mconn1.ResumeRecv(0x01)
+
+	select {
+	case receivedBytes := <-receivedCh:
+		assert.Equal(t, []byte(msg), receivedBytes)
+	case err := <-errorsCh:
+		t.Fatalf("Expected %s, got %+v", msg, err)
+	case <-time.After(500 * time.Millisecond):
+		t.Fatalf("Did not receive %s message in 500ms", msg)
+	}
+}
+
+func TestMConnectionPauseRecvReadsPings(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	receivedCh := make(chan []byte, 2)
+	onReceive := func(chID byte, msgBytes []byte) {
+		receivedCh <- msgBytes
+	}
+	onError := func(r interface{}) {}
+	mconn := createMConnectionWithCallbacks(client, onReceive, onError)
+	err := mconn.Start()
+	require.Nil(t, err)
+	defer mconn.Stop()
+
+	mconn.PauseRecv(0x01)
+
+	// The msgs are queued, and the pings still answered.
+	for _, msg := range []string{"Ice", "man"} {
+		_, err = server.Write(cdc.MustMarshalBinaryLengthPrefixed(PacketMsg{ChannelID: 0x01, EOF: 1, Bytes: []byte(msg)}))
+		require.Nil(t, err)
+	}
+	_, err = server.Write(cdc.MustMarshalBinaryLengthPrefixed(PacketPing{}))
+	require.Nil(t, err)
+	var pkt PacketPong
+	_, err = cdc.UnmarshalBinaryLengthPrefixedReader(server, &pkt, maxPingPongPacketSize)
+	require.Nil(t, err)
+	assert.Len(t, receivedCh, 0)
+
+	mconn.ResumeRecv(0x01)
+	for _, msg := range []string{"Ice", "man"} {
+		select {
+		case receivedBytes := <-receivedCh:
+			assert.Equal(t, []byte(msg), receivedBytes)
+		case <-time.After(500 * time.Millisecond):
+			t.Fatalf("Did not receive %s message in 500ms", msg)
+		}
+	}
+	assert.True(t, mconn.IsRunning())
+}
+
 func TestMConnectionStatus(t *testing.T) {
 	server, client := NetPipe()
 	defer server.Close() // nolint: errcheck
@@ -485,6 +618,25 @@ func TestMConnectionReadErrorLongMessage(t *testing.T) {
 	assert.True(t, expectSend(chOnErr), "msg too long")
 }
 
+func TestMConnectionReadErrorInvalidEOF(t *testing.T) {
+	chOnErr := make(chan struct{})
+	mconnClient, mconnServer := newClientAndServerConnsForReadErrors(t, chOnErr)
+	defer mconnClient.Stop()
+	defer mconnServer.Stop()
+
+	bz := cdc.MustMarshalBinaryLengthPrefixed(PacketMsg{ChannelID: 0x01, EOF: 0x02, Bytes: []byte("Ant-Man")})
+	_, err := mconnClient.conn.Write(bz)
+	assert.Nil(t, err)
+	assert.True(t, expectSend(chOnErr), "invalid EOF")
+}
+
+func TestPacketMsgValidateBasic(t *testing.T) {
+	assert.NoError(t, PacketMsg{EOF: 0x00, Bytes: make([]byte, 10)}.ValidateBasic(10))
+	assert.NoError(t, PacketMsg{EOF: 0x01}.ValidateBasic(10))
+	assert.Error(t, PacketMsg{EOF: 0x01, Bytes: make([]byte, 11)}.ValidateBasic(10))
+	assert.Error(t, PacketMsg{EOF: 0xFF}.ValidateBasic(10))
+}
+
 func TestMConnectionReadErrorUnknownMsgType(t *testing.T) {
 	chOnErr := make(chan struct{})
 	mconnClient, mconnServer := newClientAndServerConnsForReadErrors(t, chOnErr)
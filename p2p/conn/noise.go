@@ -0,0 +1,309 @@
+package conn
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+// The initiator of a connection chooses the handshake with the first byte it
+// sends. The one of the STS handshake is the length prefix of the ephemeral
+// public key message, so that peers not knowing about versions are supported.
+const (
+	secretConnVersionSTS     = byte(0x21)
+	secretConnVersionNoiseIK = byte(0x01)
+)
+
+// noiseProtocolName is the name of the Noise protocol of the handshake: the
+// IK pattern with X25519, ChaCha20-Poly1305 and SHA-256. See
+// https://noiseprotocol.org/noise.html.
+const noiseProtocolName = "Noise_IK_25519_ChaChaPoly_SHA256"
+
+// Sizes of the IK handshake messages: the version byte, the ephemeral key,
+// the encrypted static key and the encrypted ed25519 public key of the
+// initiator, then the ephemeral key and the authentication tag of an empty
+// payload of the responder.
+const (
+	noiseIKMsg1Size = 1 + 32 + (32 + aeadSizeOverhead) + (ed25519.PubKeyEd25519Size + aeadSizeOverhead)
+	noiseIKMsg2Size = 32 + aeadSizeOverhead
+)
+
+var errNoiseKeyType = errors.New("the Noise handshake requires ed25519 keys")
+
+// curve25519P is the order of the field of curve25519 and ed25519, 2^255-19.
+var curve25519P, _ = new(big.Int).SetString("7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffed", 16)
+
+// MakeNoiseSecretConnection performs the Noise IK handshake with the remote
+// peer of public key remPubKey, and returns a new authenticated
+// SecretConnection. The Noise static keys are the X25519 forms of the ed25519
+// keys of the peers, so that the one of the remote peer is known in advance,
+// e.g. pinned by a previous connection.
+// Returns nil if there is an error in handshake.
+// Caller should call conn.Close()
+func MakeNoiseSecretConnection(conn io.ReadWriteCloser, locPrivKey crypto.PrivKey, remPubKey crypto.PubKey) (*SecretConnection, error) {
+	locKey, ok := locPrivKey.(ed25519.PrivKeyEd25519)
+	if !ok {
+		return nil, errNoiseKeyType
+	}
+	remKey, ok := remPubKey.(ed25519.PubKeyEd25519)
+	if !ok {
+		return nil, errNoiseKeyType
+	}
+	locStaticPriv, locStaticPub := noiseStaticKeys(locKey)
+	remStaticPub, err := ed25519PubKeyToCurve25519(remKey)
+	if err != nil {
+		return nil, err
+	}
+	locEphPub, locEphPriv := genEphKeys()
+
+	s := newNoiseState(secretConnVersionNoiseIK)
+	// <- s
+	s.mixHash(remStaticPub[:])
+
+	// -> e, es, s, ss
+	msg1 := make([]byte, 0, noiseIKMsg1Size)
+	msg1 = append(msg1, secretConnVersionNoiseIK)
+	s.mixHash(locEphPub[:])
+	msg1 = append(msg1, locEphPub[:]...)
+	s.mixKey(computeDHSecret(remStaticPub, locEphPriv))
+	msg1 = append(msg1, s.encryptAndHash(locStaticPub[:])...)
+	s.mixKey(computeDHSecret(remStaticPub, locStaticPriv))
+	locPubKey := locKey.PubKey().(ed25519.PubKeyEd25519)
+	msg1 = append(msg1, s.encryptAndHash(locPubKey[:])...)
+	if _, err := conn.Write(msg1); err != nil {
+		return nil, err
+	}
+
+	// <- e, ee, se
+	msg2 := make([]byte, noiseIKMsg2Size)
+	if _, err := io.ReadFull(conn, msg2); err != nil {
+		return nil, err
+	}
+	var remEphPub [32]byte
+	copy(remEphPub[:], msg2[:32])
+	if hasSmallOrder(remEphPub) {
+		return nil, ErrSmallOrderRemotePubKey
+	}
+	s.mixHash(remEphPub[:])
+	s.mixKey(computeDHSecret(&remEphPub, locEphPriv))
+	s.mixKey(computeDHSecret(&remEphPub, locStaticPriv))
+	if _, err := s.decryptAndHash(msg2[32:]); err != nil {
+		return nil, errors.New("Noise handshake failed: the remote peer doesn't have the expected key")
+	}
+
+	sendSecret, recvSecret := s.split()
+	return &SecretConnection{
+		conn:       conn,
+		recvBuffer: nil,
+		recvNonce:  new([aeadNonceSize]byte),
+		sendNonce:  new([aeadNonceSize]byte),
+		recvSecret: recvSecret,
+		sendSecret: sendSecret,
+		remPubKey:  remKey,
+	}, nil
+}
+
+// acceptNoiseConnection performs the responder side of the Noise IK
+// handshake, the version byte being read.
+func acceptNoiseConnection(conn io.ReadWriteCloser, locPrivKey crypto.PrivKey) (*SecretConnection, error) {
+	locKey, ok := locPrivKey.(ed25519.PrivKeyEd25519)
+	if !ok {
+		return nil, errNoiseKeyType
+	}
+	locStaticPriv, locStaticPub := noiseStaticKeys(locKey)
+
+	s := newNoiseState(secretConnVersionNoiseIK)
+	// <- s
+	s.mixHash(locStaticPub[:])
+
+	// -> e, es, s, ss
+	msg1 := make([]byte, noiseIKMsg1Size-1)
+	if _, err := io.ReadFull(conn, msg1); err != nil {
+		return nil, err
+	}
+	var remEphPub, remStaticPub [32]byte
+	copy(remEphPub[:], msg1[:32])
+	if hasSmallOrder(remEphPub) {
+		return nil, ErrSmallOrderRemotePubKey
+	}
+	s.mixHash(remEphPub[:])
+	s.mixKey(computeDHSecret(&remEphPub, locStaticPriv))
+	staticPub, err := s.decryptAndHash(msg1[32 : 32+32+aeadSizeOverhead])
+	if err != nil {
+		return nil, errors.New("Noise handshake failed: the remote peer doesn't know our key")
+	}
+	copy(remStaticPub[:], staticPub)
+	if hasSmallOrder(remStaticPub) {
+		return nil, ErrSmallOrderRemotePubKey
+	}
+	s.mixKey(computeDHSecret(&remStaticPub, locStaticPriv))
+	payload, err := s.decryptAndHash(msg1[32+32+aeadSizeOverhead:])
+	if err != nil {
+		return nil, errors.New("Noise handshake failed: invalid remote static key")
+	}
+	var remPubKey ed25519.PubKeyEd25519
+	copy(remPubKey[:], payload)
+	expectedStaticPub, err := ed25519PubKeyToCurve25519(remPubKey)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(expectedStaticPub[:], remStaticPub[:]) != 1 {
+		return nil, errors.New("Noise handshake failed: the remote static key doesn't match its public key")
+	}
+
+	// <- e, ee, se
+	locEphPub, locEphPriv := genEphKeys()
+	msg2 := make([]byte, 0, noiseIKMsg2Size)
+	s.mixHash(locEphPub[:])
+	msg2 = append(msg2, locEphPub[:]...)
+	s.mixKey(computeDHSecret(&remEphPub, locEphPriv))
+	s.mixKey(computeDHSecret(&remStaticPub, locEphPriv))
+	msg2 = append(msg2, s.encryptAndHash(nil)...)
+	if _, err := conn.Write(msg2); err != nil {
+		return nil, err
+	}
+
+	recvSecret, sendSecret := s.split()
+	return &SecretConnection{
+		conn:       conn,
+		recvBuffer: nil,
+		recvNonce:  new([aeadNonceSize]byte),
+		sendNonce:  new([aeadNonceSize]byte),
+		recvSecret: recvSecret,
+		sendSecret: sendSecret,
+		remPubKey:  remPubKey,
+	}, nil
+}
+
+// noiseState is the symmetric state of a Noise handshake.
+type noiseState struct {
+	ck [32]byte // chaining key
+	h  [32]byte // handshake hash
+	k  [aeadKeySize]byte
+	n  uint64
+}
+
+func newNoiseState(prologue byte) *noiseState {
+	s := &noiseState{}
+	// The protocol name is 32 bytes long, i.e. the size of a hash.
+	copy(s.h[:], noiseProtocolName)
+	s.ck = s.h
+	s.mixHash([]byte{prologue})
+	return s
+}
+
+func (s *noiseState) mixHash(data []byte) {
+	s.h = sha256.Sum256(append(s.h[:], data...))
+}
+
+func (s *noiseState) mixKey(dhSecret *[32]byte) {
+	s.ck, s.k = noiseHKDF(s.ck[:], dhSecret[:])
+	s.n = 0
+}
+
+func (s *noiseState) encryptAndHash(plaintext []byte) []byte {
+	aead, err := chacha20poly1305.New(s.k[:])
+	if err != nil {
+		panic(err)
+	}
+	ciphertext := aead.Seal(nil, s.nonce(), plaintext, s.h[:])
+	s.n++
+	s.mixHash(ciphertext)
+	return ciphertext
+}
+
+func (s *noiseState) decryptAndHash(ciphertext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(s.k[:])
+	if err != nil {
+		panic(err)
+	}
+	plaintext, err := aead.Open(nil, s.nonce(), ciphertext, s.h[:])
+	if err != nil {
+		return nil, err
+	}
+	s.n++
+	s.mixHash(ciphertext)
+	return plaintext, nil
+}
+
+// nonce returns the nonce of the next message: 4 zero bytes, then n in
+// little endian.
+func (s *noiseState) nonce() []byte {
+	nonce := make([]byte, aeadNonceSize)
+	binary.LittleEndian.PutUint64(nonce[4:], s.n)
+	return nonce
+}
+
+// split returns the keys of the messages sent by the initiator, then of the
+// ones sent by the responder.
+func (s *noiseState) split() (*[aeadKeySize]byte, *[aeadKeySize]byte) {
+	k1, k2 := noiseHKDF(s.ck[:], nil)
+	return &k1, &k2
+}
+
+func noiseHKDF(ck, ikm []byte) (out1, out2 [32]byte) {
+	r := hkdf.New(sha256.New, ikm, ck, nil)
+	if _, err := io.ReadFull(r, out1[:]); err != nil {
+		panic(err)
+	}
+	if _, err := io.ReadFull(r, out2[:]); err != nil {
+		panic(err)
+	}
+	return
+}
+
+// noiseStaticKeys returns the X25519 key pair of the ed25519 private key,
+// whose public key is the X25519 form of the ed25519 one.
+func noiseStaticKeys(privKey ed25519.PrivKeyEd25519) (priv, pub *[32]byte) {
+	// The ed25519 scalar, curve25519.ScalarMult clamping it.
+	digest := sha512.Sum512(privKey[:32])
+	priv, pub = new([32]byte), new([32]byte)
+	copy(priv[:], digest[:32])
+	curve25519.ScalarBaseMult(pub, priv)
+	return
+}
+
+// ed25519PubKeyToCurve25519 returns the X25519 form of the ed25519 public
+// key, i.e. the u-coordinate (1+y)/(1-y) of the point on the birationally
+// equivalent Montgomery curve.
+func ed25519PubKeyToCurve25519(pubKey ed25519.PubKeyEd25519) (*[32]byte, error) {
+	// y is encoded in little endian, the top bit being the sign of x.
+	var buf [32]byte
+	for i := range buf {
+		buf[i] = pubKey[31-i]
+	}
+	buf[0] &= 0x7f
+	y := new(big.Int).SetBytes(buf[:])
+	if y.Cmp(curve25519P) >= 0 {
+		return nil, errors.New("invalid ed25519 public key")
+	}
+
+	one := big.NewInt(1)
+	den := new(big.Int).Sub(one, y)
+	den.Mod(den, curve25519P)
+	if den.Sign() == 0 {
+		return nil, errors.New("invalid ed25519 public key")
+	}
+	u := new(big.Int).Add(one, y)
+	u.Mul(u, den.ModInverse(den, curve25519P))
+	u.Mod(u, curve25519P)
+
+	// Back to little endian.
+	uBytes := u.Bytes()
+	pub := new([32]byte)
+	for i, b := range uBytes {
+		pub[len(uBytes)-1-i] = b
+	}
+	return pub, nil
+}
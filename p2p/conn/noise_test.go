@@ -0,0 +1,104 @@
+package conn
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// makeAcceptedConnPair makes a connection from foo to bar with dial, bar
+// accepting it.
+func makeAcceptedConnPair(
+	t *testing.T,
+	dial func(kvstoreConn, crypto.PrivKey, crypto.PubKey) (*SecretConnection, error),
+) (fooSecConn, barSecConn *SecretConnection, fooErr, barErr error) {
+	var fooConn, barConn = makeKVStoreConnPair()
+	var fooPrvKey = ed25519.GenPrivKey()
+	var barPrvKey = ed25519.GenPrivKey()
+
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		fooSecConn, fooErr = dial(fooConn, fooPrvKey, barPrvKey.PubKey())
+		if fooErr != nil {
+			fooConn.Close()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		barSecConn, barErr = AcceptSecretConnection(barConn, barPrvKey)
+		if barErr != nil {
+			barConn.Close()
+		}
+	}()
+	wg.Wait()
+
+	if fooErr == nil {
+		assert.True(t, fooSecConn.RemotePubKey().Equals(barPrvKey.PubKey()))
+	}
+	if barErr == nil {
+		assert.True(t, barSecConn.RemotePubKey().Equals(fooPrvKey.PubKey()))
+	}
+	return
+}
+
+func testSecretConnPairReadWrite(t *testing.T, fooSecConn, barSecConn *SecretConnection) {
+	fooWriteText := cmn.RandStr(dataMaxSize)
+	n := 10
+	wg := new(sync.WaitGroup)
+	wg.Add(2)
+	go writeLots(t, wg, fooSecConn, fooWriteText, n)
+	readLots(t, wg, barSecConn, n)
+	wg.Wait()
+}
+
+func TestNoiseSecretConnectionHandshake(t *testing.T) {
+	fooSecConn, barSecConn, fooErr, barErr := makeAcceptedConnPair(t,
+		func(conn kvstoreConn, locPrivKey crypto.PrivKey, remPubKey crypto.PubKey) (*SecretConnection, error) {
+			return MakeNoiseSecretConnection(conn, locPrivKey, remPubKey)
+		})
+	require.NoError(t, fooErr)
+	require.NoError(t, barErr)
+
+	testSecretConnPairReadWrite(t, fooSecConn, barSecConn)
+	testSecretConnPairReadWrite(t, barSecConn, fooSecConn)
+}
+
+func TestNoiseSecretConnectionWrongKey(t *testing.T) {
+	_, _, fooErr, barErr := makeAcceptedConnPair(t,
+		func(conn kvstoreConn, locPrivKey crypto.PrivKey, _ crypto.PubKey) (*SecretConnection, error) {
+			// not the key of bar
+			return MakeNoiseSecretConnection(conn, locPrivKey, ed25519.GenPrivKey().PubKey())
+		})
+	assert.Error(t, fooErr)
+	assert.Error(t, barErr)
+}
+
+func TestAcceptSecretConnectionSTS(t *testing.T) {
+	fooSecConn, barSecConn, fooErr, barErr := makeAcceptedConnPair(t,
+		func(conn kvstoreConn, locPrivKey crypto.PrivKey, _ crypto.PubKey) (*SecretConnection, error) {
+			return MakeSecretConnection(conn, locPrivKey)
+		})
+	require.NoError(t, fooErr)
+	require.NoError(t, barErr)
+
+	testSecretConnPairReadWrite(t, fooSecConn, barSecConn)
+	testSecretConnPairReadWrite(t, barSecConn, fooSecConn)
+}
+
+func TestEd25519PubKeyToCurve25519(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		privKey := ed25519.GenPrivKey()
+		_, staticPub := noiseStaticKeys(privKey)
+		pub, err := ed25519PubKeyToCurve25519(privKey.PubKey().(ed25519.PubKeyEd25519))
+		require.NoError(t, err)
+		assert.Equal(t, staticPub, pub)
+	}
+}
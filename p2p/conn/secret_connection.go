@@ -7,6 +7,7 @@ import (
 	"crypto/subtle"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"sync"
@@ -29,6 +30,13 @@ const aeadSizeOverhead = 16 // overhead of poly 1305 authentication tag
 const aeadKeySize = chacha20poly1305.KeySize
 const aeadNonceSize = chacha20poly1305.NonceSize
 
+// Maximum sizes of the amino encoded messages exchanged during the handshake,
+// to not allocate more for a malformed length prefix. The ephemeral public
+// key takes 33 bytes, the auth signature message about 100 bytes with an
+// ed25519 key.
+const maxEphPubKeyMsgSize = 64
+const maxAuthSigMsgSize = 1024
+
 var ErrSmallOrderRemotePubKey = errors.New("detected low order point from remote peer")
 
 // SecretConnection implements net.Conn.
@@ -69,6 +77,38 @@ type SecretConnection struct {
 // Caller should call conn.Close()
 // See docs/sts-final.pdf for more information.
 func MakeSecretConnection(conn io.ReadWriteCloser, locPrivKey crypto.PrivKey) (*SecretConnection, error) {
+	return makeSTSConnection(conn, conn, locPrivKey)
+}
+
+// AcceptSecretConnection performs the handshake chosen by the remote peer,
+// which initiated the connection, and returns a new authenticated
+// SecretConnection. The handshake is the STS one of MakeSecretConnection, or
+// the Noise IK one of MakeNoiseSecretConnection.
+// Returns nil if there is an error in handshake.
+// Caller should call conn.Close()
+func AcceptSecretConnection(conn io.ReadWriteCloser, locPrivKey crypto.PrivKey) (*SecretConnection, error) {
+	var version [1]byte
+	if _, err := io.ReadFull(conn, version[:]); err != nil {
+		return nil, err
+	}
+	switch version[0] {
+	case secretConnVersionSTS:
+		// The version is the first byte of the ephemeral public key message.
+		ephConn := struct {
+			io.Reader
+			io.Writer
+		}{io.MultiReader(bytes.NewReader(version[:]), conn), conn}
+		return makeSTSConnection(conn, ephConn, locPrivKey)
+	case secretConnVersionNoiseIK:
+		return acceptNoiseConnection(conn, locPrivKey)
+	default:
+		return nil, fmt.Errorf("unknown secret connection version %X", version[0])
+	}
+}
+
+// makeSTSConnection performs the STS handshake, sharing the ephemeral public
+// keys over ephConn.
+func makeSTSConnection(conn io.ReadWriteCloser, ephConn io.ReadWriter, locPrivKey crypto.PrivKey) (*SecretConnection, error) {
 	locPubKey := locPrivKey.PubKey()
 
 	// Generate ephemeral keys for perfect forward secrecy.
@@ -77,7 +117,7 @@ func MakeSecretConnection(conn io.ReadWriteCloser, locPrivKey crypto.PrivKey) (*
 	// Write local ephemeral pubkey and receive one too.
 	// NOTE: every 32-byte string is accepted as a Curve25519 public key
 	// (see DJB's Curve25519 paper: http://cr.yp.to/ecdh/curve25519-20060209.pdf)
-	remEphPub, err := shareEphPubKey(conn, locEphPub)
+	remEphPub, err := shareEphPubKey(ephConn, locEphPub)
 	if err != nil {
 		return nil, err
 	}
@@ -237,7 +277,7 @@ func genEphKeys() (ephPub, ephPriv *[32]byte) {
 	return
 }
 
-func shareEphPubKey(conn io.ReadWriteCloser, locEphPub *[32]byte) (remEphPub *[32]byte, err error) {
+func shareEphPubKey(conn io.ReadWriter, locEphPub *[32]byte) (remEphPub *[32]byte, err error) {
 
 	// Send our pubkey and receive theirs in tandem.
 	var trs, _ = cmn.Parallel(
@@ -250,7 +290,7 @@ func shareEphPubKey(conn io.ReadWriteCloser, locEphPub *[32]byte) (remEphPub *[3
 		},
 		func(_ int) (val interface{}, err error, abort bool) {
 			var _remEphPub [32]byte
-			var _, err2 = cdc.UnmarshalBinaryLengthPrefixedReader(conn, &_remEphPub, 1024*1024) // TODO
+			var _, err2 = cdc.UnmarshalBinaryLengthPrefixedReader(conn, &_remEphPub, maxEphPubKeyMsgSize)
 			if err2 != nil {
 				return nil, err2, true // abort
 			}
@@ -393,7 +433,7 @@ func shareAuthSignature(sc *SecretConnection, pubKey crypto.PubKey, signature []
 		},
 		func(_ int) (val interface{}, err error, abort bool) {
 			var _recvMsg authSigMessage
-			var _, err2 = cdc.UnmarshalBinaryLengthPrefixedReader(sc, &_recvMsg, 1024*1024) // TODO
+			var _, err2 = cdc.UnmarshalBinaryLengthPrefixedReader(sc, &_recvMsg, maxAuthSigMsgSize)
 			if err2 != nil {
 				return nil, err2, true // abort
 			}
@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"net"
 	"os"
@@ -17,6 +18,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	amino "github.com/tendermint/go-amino"
+
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	cmn "github.com/tendermint/tendermint/libs/common"
 )
@@ -126,6 +129,24 @@ func TestShareLowOrderPubkey(t *testing.T) {
 	}
 }
 
+func TestShareEphPubKeyTooLarge(t *testing.T) {
+	var fooConn, barConn = makeKVStoreConnPair()
+	defer fooConn.Close()
+	defer barConn.Close()
+	locEphPub, _ := genEphKeys()
+
+	go func() {
+		// drain the key of foo, and announce a huge one
+		_, _ = io.Copy(ioutil.Discard, barConn)
+	}()
+	go func() {
+		_ = amino.EncodeUvarint(barConn, 1024*1024)
+	}()
+
+	_, err := shareEphPubKey(fooConn, locEphPub)
+	require.Error(t, err)
+}
+
 func TestConcurrentWrite(t *testing.T) {
 	fooSecConn, barSecConn := makeSecretConnPair(t)
 	fooWriteText := cmn.RandStr(dataMaxSize)
@@ -80,6 +80,12 @@ func (p *peer) TrySend(byte, []byte) bool {
 	return true
 }
 
+// PauseRecv does not do anything.
+func (p *peer) PauseRecv(byte) {}
+
+// ResumeRecv does not do anything.
+func (p *peer) ResumeRecv(byte) {}
+
 // Set records value under key specified in the map.
 func (p *peer) Set(key string, value interface{}) {
 	p.kv[key] = value
@@ -98,3 +104,8 @@ func (p *peer) Get(key string) interface{} {
 func (p *peer) OriginalAddr() *p2p.NetAddress {
 	return nil
 }
+
+// ClockOffset always returns nil.
+func (p *peer) ClockOffset() *p2p.ClockOffset {
+	return nil
+}
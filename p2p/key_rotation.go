@@ -0,0 +1,175 @@
+package p2p
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+const maxKeyRotationSignatureLength = 128
+
+// KeyRotation announces that a node rotated its node key: it's the new ID of
+// the node, signed by its old key. The node presents it in its NodeInfo, so
+// that its peers move what they know about the old ID, e.g. its reputation in
+// their address book, to the new one, and accept the new ID when dialing the
+// old one.
+type KeyRotation struct {
+	OldPubKey crypto.PubKey `json:"old_pub_key"`
+	NewID     ID            `json:"new_id"`
+	Signature []byte        `json:"signature"`
+}
+
+// NewKeyRotation returns the rotation of the node key oldPrivKey to the key of
+// newID, signed by oldPrivKey.
+func NewKeyRotation(oldPrivKey crypto.PrivKey, newID ID) (*KeyRotation, error) {
+	rot := &KeyRotation{
+		OldPubKey: oldPrivKey.PubKey(),
+		NewID:     newID,
+	}
+	sig, err := oldPrivKey.Sign(rot.SignBytes())
+	if err != nil {
+		return nil, err
+	}
+	rot.Signature = sig
+	return rot, rot.ValidateBasic()
+}
+
+// OldID returns the ID of the node before the rotation.
+func (rot *KeyRotation) OldID() ID {
+	return PubKeyToID(rot.OldPubKey)
+}
+
+// SignBytes returns the bytes signed by the old key.
+func (rot KeyRotation) SignBytes() []byte {
+	rot.Signature = nil
+	return cdc.MustMarshalBinaryBare(rot)
+}
+
+// ValidateBasic performs basic validation, without checking the signature.
+func (rot *KeyRotation) ValidateBasic() error {
+	if rot.OldPubKey == nil {
+		return errors.New("key rotation has no old key")
+	}
+	if err := validateID(rot.NewID); err != nil {
+		return errors.Wrap(err, "invalid key rotation new ID")
+	}
+	if rot.OldID() == rot.NewID {
+		return errors.New("key rotation to the same key")
+	}
+	if len(rot.Signature) == 0 || len(rot.Signature) > maxKeyRotationSignatureLength {
+		return fmt.Errorf("key rotation signature must be between 1 and %d bytes, got %d",
+			maxKeyRotationSignatureLength, len(rot.Signature))
+	}
+	return nil
+}
+
+// Verify returns an error if the rotation is not the one of the node, or is
+// not signed by the old key.
+func (rot *KeyRotation) Verify(nodeID ID) error {
+	if err := rot.ValidateBasic(); err != nil {
+		return err
+	}
+	if rot.NewID != nodeID {
+		return fmt.Errorf("key rotation to node %v, not %v", rot.NewID, nodeID)
+	}
+	if !rot.OldPubKey.VerifyBytes(rot.SignBytes(), rot.Signature) {
+		return errors.New("invalid key rotation signature")
+	}
+	return nil
+}
+
+// SaveAs persists the rotation to filePath.
+func (rot *KeyRotation) SaveAs(filePath string) error {
+	jsonBytes, err := cdc.MarshalJSONIndent(rot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return cmn.WriteFileAtomic(filePath, jsonBytes, 0644)
+}
+
+// LoadKeyRotation loads a rotation saved with SaveAs.
+func LoadKeyRotation(filePath string) (*KeyRotation, error) {
+	jsonBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	rot := new(KeyRotation)
+	if err := cdc.UnmarshalJSON(jsonBytes, rot); err != nil {
+		return nil, fmt.Errorf("Error reading KeyRotation from %v: %v", filePath, err)
+	}
+	if err := rot.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("Invalid KeyRotation in %v: %v", filePath, err)
+	}
+	return rot, nil
+}
+
+// DefaultOldNodeKeys is the default number of old node keys kept by
+// RotateNodeKey.
+const DefaultOldNodeKeys = 5
+
+// OldNodeKeyFile returns the file of the n-th most recent old node key kept by
+// RotateNodeKey, from 1.
+func OldNodeKeyFile(nodeKeyFile string, n int) string {
+	return fmt.Sprintf("%s.old.%d", nodeKeyFile, n)
+}
+
+// RotateNodeKey replaces the NodeKey in nodeKeyFile with a new one, and saves
+// the rotation to rotationFile, presented to the peers. The keepOld most
+// recent old keys, at least one, are kept in the OldNodeKeyFiles, the old key
+// replaced being the first one, and the older ones beyond are removed.
+func RotateNodeKey(nodeKeyFile, rotationFile string, keepOld int) (*NodeKey, *KeyRotation, error) {
+	if keepOld < 1 {
+		return nil, nil, errors.New("at least one old node key must be kept")
+	}
+	oldKey, err := LoadNodeKey(nodeKeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	newKey := &NodeKey{PrivKey: ed25519.GenPrivKey()}
+	rot, err := NewKeyRotation(oldKey.PrivKey, newKey.ID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := shiftOldNodeKeys(nodeKeyFile, keepOld); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to back up the old node keys")
+	}
+	if err := oldKey.SaveAs(OldNodeKeyFile(nodeKeyFile, 1)); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to back up the old node key")
+	}
+	if err := rot.SaveAs(rotationFile); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to save the key rotation")
+	}
+	if err := newKey.SaveAs(nodeKeyFile); err != nil {
+		os.Remove(rotationFile) // nolint: errcheck
+		return nil, nil, errors.Wrap(err, "failed to save the new node key")
+	}
+	return newKey, rot, nil
+}
+
+// shiftOldNodeKeys makes room for the first old node key, shifting the ones
+// kept by one, and removing those beyond keepOld.
+func shiftOldNodeKeys(nodeKeyFile string, keepOld int) error {
+	n := keepOld
+	for cmn.FileExists(OldNodeKeyFile(nodeKeyFile, n+1)) {
+		n++
+	}
+	for ; n >= keepOld; n-- {
+		if err := os.Remove(OldNodeKeyFile(nodeKeyFile, n)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	for ; n >= 1; n-- {
+		err := os.Rename(OldNodeKeyFile(nodeKeyFile, n), OldNodeKeyFile(nodeKeyFile, n+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
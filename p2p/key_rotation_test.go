@@ -0,0 +1,109 @@
+package p2p
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+func TestKeyRotationVerify(t *testing.T) {
+	oldKey := ed25519.GenPrivKey()
+	newID := PubKeyToID(ed25519.GenPrivKey().PubKey())
+
+	rot, err := NewKeyRotation(oldKey, newID)
+	require.NoError(t, err)
+	assert.Equal(t, PubKeyToID(oldKey.PubKey()), rot.OldID())
+	assert.NoError(t, rot.Verify(newID))
+
+	// another node
+	assert.Error(t, rot.Verify(PubKeyToID(ed25519.GenPrivKey().PubKey())))
+
+	// tampered
+	tampered := *rot
+	tampered.NewID = PubKeyToID(ed25519.GenPrivKey().PubKey())
+	assert.Error(t, tampered.Verify(tampered.NewID))
+
+	// signed by another key
+	forged := *rot
+	forged.OldPubKey = ed25519.GenPrivKey().PubKey()
+	assert.Error(t, forged.Verify(newID))
+
+	// to the same key
+	_, err = NewKeyRotation(oldKey, PubKeyToID(oldKey.PubKey()))
+	assert.Error(t, err)
+	_, err = NewKeyRotation(oldKey, "not an ID")
+	assert.Error(t, err)
+}
+
+func TestRotateNodeKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "key_rotation_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	nodeKeyFile := filepath.Join(dir, "node_key.json")
+	rotationFile := filepath.Join(dir, "node_key_rotation.json")
+
+	_, _, err = RotateNodeKey(nodeKeyFile, rotationFile, DefaultOldNodeKeys)
+	assert.Error(t, err, "no node key to rotate")
+
+	oldKey, err := LoadOrGenNodeKey(nodeKeyFile)
+	require.NoError(t, err)
+	_, _, err = RotateNodeKey(nodeKeyFile, rotationFile, 0)
+	assert.Error(t, err, "no old node key kept")
+	newKey, rot, err := RotateNodeKey(nodeKeyFile, rotationFile, DefaultOldNodeKeys)
+	require.NoError(t, err)
+	assert.NotEqual(t, oldKey.ID(), newKey.ID())
+	assert.Equal(t, oldKey.ID(), rot.OldID())
+
+	loaded, err := LoadNodeKey(nodeKeyFile)
+	require.NoError(t, err)
+	assert.Equal(t, newKey.ID(), loaded.ID())
+	backup, err := LoadNodeKey(OldNodeKeyFile(nodeKeyFile, 1))
+	require.NoError(t, err)
+	assert.Equal(t, oldKey.ID(), backup.ID())
+
+	loadedRot, err := LoadKeyRotation(rotationFile)
+	require.NoError(t, err)
+	assert.NoError(t, loadedRot.Verify(newKey.ID()))
+}
+
+func TestRotateNodeKeyOldKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "key_rotation_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	nodeKeyFile := filepath.Join(dir, "node_key.json")
+	rotationFile := filepath.Join(dir, "node_key_rotation.json")
+
+	key, err := LoadOrGenNodeKey(nodeKeyFile)
+	require.NoError(t, err)
+	ids := []ID{key.ID()}
+	for i := 0; i < 4; i++ {
+		key, _, err = RotateNodeKey(nodeKeyFile, rotationFile, 3)
+		require.NoError(t, err)
+		ids = append(ids, key.ID())
+	}
+
+	// the 3 most recent old keys are kept, the most recent first
+	for n := 1; n <= 3; n++ {
+		old, err := LoadNodeKey(OldNodeKeyFile(nodeKeyFile, n))
+		require.NoError(t, err)
+		assert.Equal(t, ids[len(ids)-1-n], old.ID(), "old key %d", n)
+	}
+	assert.False(t, cmn.FileExists(OldNodeKeyFile(nodeKeyFile, 4)))
+
+	// keeping fewer removes the oldest ones
+	_, _, err = RotateNodeKey(nodeKeyFile, rotationFile, 1)
+	require.NoError(t, err)
+	old, err := LoadNodeKey(OldNodeKeyFile(nodeKeyFile, 1))
+	require.NoError(t, err)
+	assert.Equal(t, ids[len(ids)-1], old.ID())
+	for n := 2; n <= 4; n++ {
+		assert.False(t, cmn.FileExists(OldNodeKeyFile(nodeKeyFile, n)), "old key %d", n)
+	}
+}
@@ -0,0 +1,129 @@
+package p2p
+
+import (
+	"fmt"
+	"net"
+	"sync"
+)
+
+// memNetworkFirstPort is the first port of the dialing ends of the
+// connections of a MemNetwork.
+const memNetworkFirstPort = 32768
+
+// MemNetwork connects MultiplexTransports in memory, through net.Pipe, to run
+// several nodes in one process. Its Dial and Listen replace the ones of the
+// net package, see MultiplexTransportDialer and MultiplexTransportListen.
+type MemNetwork struct {
+	mtx       sync.Mutex
+	listeners map[string]*memListener // by address
+	nextPort  int
+}
+
+// NewMemNetwork returns an empty MemNetwork.
+func NewMemNetwork() *MemNetwork {
+	return &MemNetwork{
+		listeners: make(map[string]*memListener),
+		nextPort:  memNetworkFirstPort,
+	}
+}
+
+// Listen returns a listener accepting the connections dialed to the IP:port
+// address, until closed.
+func (mn *MemNetwork) Listen(network, address string) (net.Listener, error) {
+	addr, err := net.ResolveTCPAddr("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	mn.mtx.Lock()
+	defer mn.mtx.Unlock()
+	if _, ok := mn.listeners[addr.String()]; ok {
+		return nil, fmt.Errorf("listen %v: address already in use", addr)
+	}
+	ln := &memListener{
+		network: mn,
+		addr:    addr,
+		connc:   make(chan net.Conn),
+		closec:  make(chan struct{}),
+	}
+	mn.listeners[addr.String()] = ln
+	return ln, nil
+}
+
+// Dial connects to the listener of the address. It implements Dialer.
+func (mn *MemNetwork) Dial(network, address string) (net.Conn, error) {
+	addr, err := net.ResolveTCPAddr("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	mn.mtx.Lock()
+	ln, ok := mn.listeners[addr.String()]
+	localAddr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: mn.nextPort}
+	mn.nextPort++
+	mn.mtx.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("dial %v: connection refused", addr)
+	}
+
+	c, s := net.Pipe()
+	client := &memConn{Conn: c, localAddr: localAddr, remoteAddr: ln.addr}
+	server := &memConn{Conn: s, localAddr: ln.addr, remoteAddr: localAddr}
+	select {
+	case ln.connc <- server:
+		return client, nil
+	case <-ln.closec:
+		_ = c.Close()
+		_ = s.Close()
+		return nil, fmt.Errorf("dial %v: connection refused", addr)
+	}
+}
+
+// memListener is a listener of a MemNetwork.
+type memListener struct {
+	network   *MemNetwork
+	addr      *net.TCPAddr
+	connc     chan net.Conn
+	closec    chan struct{}
+	closeOnce sync.Once
+}
+
+var _ net.Listener = (*memListener)(nil)
+
+func (ln *memListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-ln.connc:
+		return c, nil
+	case <-ln.closec:
+		return nil, fmt.Errorf("accept %v: listener closed", ln.addr)
+	}
+}
+
+func (ln *memListener) Close() error {
+	ln.closeOnce.Do(func() {
+		ln.network.mtx.Lock()
+		delete(ln.network.listeners, ln.addr.String())
+		ln.network.mtx.Unlock()
+		close(ln.closec)
+	})
+	return nil
+}
+
+func (ln *memListener) Addr() net.Addr {
+	return ln.addr
+}
+
+// memConn is an end of a connection of a MemNetwork, with TCP addresses as
+// expected by the transport.
+type memConn struct {
+	net.Conn
+	localAddr, remoteAddr *net.TCPAddr
+}
+
+func (c *memConn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+func (c *memConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
@@ -25,6 +25,9 @@ type Metrics struct {
 	PeerPendingSendBytes metrics.Gauge
 	// Number of transactions submitted by each peer.
 	NumTxs metrics.Gauge
+	// Ratio of the size of the messages sent to a given peer on a compressed
+	// channel to their compressed size.
+	PeerCompressionRatio metrics.Gauge
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -66,6 +69,12 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "num_txs",
 			Help:      "Number of transactions submitted by each peer.",
 		}, append(labels, "peer_id")).With(labelsAndValues...),
+		PeerCompressionRatio: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_compression_ratio",
+			Help:      "Ratio of the size of the messages sent to a given peer on a compressed channel to their compressed size.",
+		}, append(labels, "peer_id", "channel_id")).With(labelsAndValues...),
 	}
 }
 
@@ -77,5 +86,6 @@ func NopMetrics() *Metrics {
 		PeerSendBytesTotal:    discard.NewCounter(),
 		PeerPendingSendBytes:  discard.NewGauge(),
 		NumTxs:                discard.NewGauge(),
+		PeerCompressionRatio:  discard.NewGauge(),
 	}
 }
@@ -25,8 +25,9 @@ type NetAddress struct {
 	IP   net.IP `json:"ip"`
 	Port uint16 `json:"port"`
 
-	// TODO:
-	// Name string `json:"name"` // optional DNS name
+	// Name is set instead of IP for Tor hidden service (.onion) addresses,
+	// which can't be resolved locally and must be dialed through a proxy.
+	Name string `json:"name,omitempty"`
 
 	// memoize .String()
 	str string
@@ -43,7 +44,6 @@ func IDAddressString(id ID, protocolHostPort string) string {
 // address. When testing, other net.Addr (except TCP) will result in
 // using 0.0.0.0:0. When normal run, other net.Addr (except TCP) will
 // panic.
-// TODO: socks proxies?
 func NewNetAddress(id ID, addr net.Addr) *NetAddress {
 	tcpAddr, ok := addr.(*net.TCPAddr)
 	if !ok {
@@ -107,6 +107,24 @@ func NewNetAddressStringWithOptionalID(addr string) (*NetAddress, error) {
 			errors.New("host is empty")}
 	}
 
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, ErrNetAddressInvalid{portStr, err}
+	}
+
+	// Onion addresses are only resolvable by the proxy, so keep the name.
+	if isOnionHost(host) {
+		name := strings.ToLower(host)
+		if !validOnionName(name) {
+			return nil, ErrNetAddressInvalid{
+				addrWithoutProtocol,
+				errors.New("invalid onion address")}
+		}
+		na := NewNetAddressOnionPort(name, uint16(port))
+		na.ID = id
+		return na, nil
+	}
+
 	ip := net.ParseIP(host)
 	if ip == nil {
 		ips, err := net.LookupIP(host)
@@ -116,11 +134,6 @@ func NewNetAddressStringWithOptionalID(addr string) (*NetAddress, error) {
 		ip = ips[0]
 	}
 
-	port, err := strconv.ParseUint(portStr, 10, 16)
-	if err != nil {
-		return nil, ErrNetAddressInvalid{portStr, err}
-	}
-
 	na := NewNetAddressIPPort(ip, uint16(port))
 	na.ID = id
 	return na, nil
@@ -151,6 +164,15 @@ func NewNetAddressIPPort(ip net.IP, port uint16) *NetAddress {
 	}
 }
 
+// NewNetAddressOnionPort returns a new NetAddress using the provided
+// .onion name and port number.
+func NewNetAddressOnionPort(name string, port uint16) *NetAddress {
+	return &NetAddress{
+		Name: name,
+		Port: port,
+	}
+}
+
 // Equals reports whether na and other are the same addresses,
 // including their ID, IP, and Port.
 func (na *NetAddress) Equals(other interface{}) bool {
@@ -192,12 +214,21 @@ func (na *NetAddress) DialString() string {
 	if na == nil {
 		return "<nil-NetAddress>"
 	}
+	host := na.IP.String()
+	if na.IsOnion() {
+		host = na.Name
+	}
 	return net.JoinHostPort(
-		na.IP.String(),
+		host,
 		strconv.FormatUint(uint64(na.Port), 10),
 	)
 }
 
+// IsOnion returns true if the address is a Tor hidden service.
+func (na *NetAddress) IsOnion() bool {
+	return na.Name != ""
+}
+
 // Dial calls net.Dial on the address.
 func (na *NetAddress) Dial() (net.Conn, error) {
 	conn, err := net.Dial("tcp", na.DialString())
@@ -232,6 +263,9 @@ func (na *NetAddress) Valid() bool {
 			return false
 		}
 	}
+	if na.IsOnion() {
+		return validOnionName(na.Name)
+	}
 	return na.IP != nil && !(na.IP.IsUnspecified() || na.RFC3849() ||
 		na.IP.Equal(net.IPv4bcast))
 }
@@ -333,6 +367,27 @@ func (na *NetAddress) RFC4862() bool { return rfc4862.Contains(na.IP) }
 func (na *NetAddress) RFC6052() bool { return rfc6052.Contains(na.IP) }
 func (na *NetAddress) RFC6145() bool { return rfc6145.Contains(na.IP) }
 
+const onionSuffix = ".onion"
+
+func isOnionHost(host string) bool {
+	return strings.HasSuffix(strings.ToLower(host), onionSuffix)
+}
+
+// validOnionName checks for a v2 (16 chars) or v3 (56 chars) base32
+// encoded hidden service name.
+func validOnionName(name string) bool {
+	label := strings.TrimSuffix(name, onionSuffix)
+	if len(label) != 16 && len(label) != 56 {
+		return false
+	}
+	for _, c := range label {
+		if !(c >= 'a' && c <= 'z') && !(c >= '2' && c <= '7') {
+			return false
+		}
+	}
+	return true
+}
+
 func removeProtocolIfDefined(addr string) string {
 	if strings.Contains(addr, "://") {
 		return strings.Split(addr, "://")[1]
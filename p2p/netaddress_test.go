@@ -55,6 +55,12 @@ func TestNewNetAddressStringWithOptionalID(t *testing.T) {
 		{"node id delimiter 1", "@", "", false},
 		{"node id delimiter 2", " @", "", false},
 		{"node id delimiter 3", " @ ", "", false},
+
+		{"onion v2", "expyuzz4wqqyqhjn.onion:26656", "expyuzz4wqqyqhjn.onion:26656", true},
+		{"onion v3 w/ nodeId", "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef@pg6mmjiyjmcrsslvykfwnntlaru7p5svn6y2ymmju6nubxndf4pscryd.onion:26656", "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef@pg6mmjiyjmcrsslvykfwnntlaru7p5svn6y2ymmju6nubxndf4pscryd.onion:26656", true},
+		{"onion upper case", "EXPYUZZ4WQQYQHJN.onion:26656", "expyuzz4wqqyqhjn.onion:26656", true},
+		{"onion invalid length", "expyuzz4wqqyqhj.onion:26656", "", false},
+		{"onion invalid chars", "expyuzz4wqqyqhj1.onion:26656", "", false},
 	}
 
 	for _, tc := range testCases {
@@ -117,6 +123,7 @@ func TestNetAddressProperties(t *testing.T) {
 	}{
 		{"127.0.0.1:8080", true, true, false},
 		{"ya.ru:80", true, false, true},
+		{"expyuzz4wqqyqhjn.onion:26656", true, false, true},
 	}
 
 	for _, tc := range testCases {
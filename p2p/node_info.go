@@ -1,10 +1,14 @@
 package p2p
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
+	"time"
 
+	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/p2p/conn"
 	"github.com/tendermint/tendermint/version"
 )
 
@@ -90,6 +94,28 @@ type DefaultNodeInfo struct {
 	// ASCIIText fields
 	Moniker string               `json:"moniker"` // arbitrary moniker
 	Other   DefaultNodeInfoOther `json:"other"`   // other application specific data
+
+	// Local time of the node at the handshake, to detect clock skews.
+	Time time.Time `json:"time"`
+
+	// Certificate of the node, required by the nodes of a permissioned
+	// network.
+	Certificate *PeerCertificate `json:"certificate"`
+
+	// Compression of the messages of the CompressedChannels, used with the
+	// peers having the same compression.
+	Compression        string       `json:"compression"`
+	CompressedChannels cmn.HexBytes `json:"compressed_channels"`
+
+	// SHA-256 hash of the genesis file of the node, if any.
+	GenesisHash cmn.HexBytes `json:"genesis_hash"`
+
+	// Rotation of the node key to the ID of the node, if it rotated it.
+	KeyRotation *KeyRotation `json:"key_rotation"`
+
+	// Whether the node exchanges the timestamps estimating the ClockOffset
+	// after the handshake.
+	ClockSync bool `json:"clock_sync"`
 }
 
 // DefaultNodeInfoOther is the misc. applcation specific data
@@ -148,6 +174,28 @@ func (info DefaultNodeInfo) Validate() error {
 		channels[ch] = struct{}{}
 	}
 
+	// Validate Compression - unknown compressions are not used.
+	if len(info.Compression) > 0 &&
+		(!cmn.IsASCIIText(info.Compression) || cmn.ASCIITrim(info.Compression) == "") {
+
+		return fmt.Errorf("info.Compression must be valid ASCII text without tabs, but got %v", info.Compression)
+	}
+	compressedChannels := make(map[byte]struct{})
+	for _, ch := range info.CompressedChannels {
+		if _, ok := channels[ch]; !ok {
+			return fmt.Errorf("info.CompressedChannels contains unknown channel id %v", ch)
+		}
+		if _, ok := compressedChannels[ch]; ok {
+			return fmt.Errorf("info.CompressedChannels contains duplicate channel id %v", ch)
+		}
+		compressedChannels[ch] = struct{}{}
+	}
+
+	// Validate GenesisHash.
+	if len(info.GenesisHash) != 0 && len(info.GenesisHash) != tmhash.Size {
+		return fmt.Errorf("info.GenesisHash must be %v bytes, got %v", tmhash.Size, len(info.GenesisHash))
+	}
+
 	// Validate Moniker.
 	if !cmn.IsASCIIText(info.Moniker) || cmn.ASCIITrim(info.Moniker) == "" {
 		return fmt.Errorf("info.Moniker must be valid non-empty ASCII text without tabs, but got %v", info.Moniker)
@@ -167,6 +215,20 @@ func (info DefaultNodeInfo) Validate() error {
 		return fmt.Errorf("info.Other.RPCAddress=%v must be valid ASCII text without tabs", rpcAddr)
 	}
 
+	// Validate Certificate, verified by the peers requiring it.
+	if info.Certificate != nil {
+		if err := info.Certificate.ValidateBasic(); err != nil {
+			return fmt.Errorf("info.Certificate is invalid: %v", err)
+		}
+	}
+
+	// Validate KeyRotation.
+	if info.KeyRotation != nil {
+		if err := info.KeyRotation.Verify(info.ID()); err != nil {
+			return fmt.Errorf("info.KeyRotation is invalid: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -211,6 +273,23 @@ OUTER_LOOP:
 	return nil
 }
 
+// compressedChannelsWith returns the channels whose messages are compressed
+// with the other node: the CompressedChannels of both nodes, if they have the
+// same Compression.
+func (info DefaultNodeInfo) compressedChannelsWith(other DefaultNodeInfo) []byte {
+	if info.Compression == "" || info.Compression == conn.CompressionNone ||
+		info.Compression != other.Compression {
+		return nil
+	}
+	var channels []byte
+	for _, ch := range info.CompressedChannels {
+		if bytes.IndexByte(other.CompressedChannels, ch) != -1 {
+			channels = append(channels, ch)
+		}
+	}
+	return channels
+}
+
 // NetAddress returns a NetAddress derived from the DefaultNodeInfo -
 // it includes the authenticated peer ID and the self-reported
 // ListenAddr. Note that the ListenAddr is not authenticated and
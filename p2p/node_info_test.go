@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/p2p/conn"
 )
 
 func TestNodeInfoValidate(t *testing.T) {
@@ -35,6 +36,15 @@ func TestNodeInfoValidate(t *testing.T) {
 		{"Duplicate Channel", func(ni *DefaultNodeInfo) { ni.Channels = dupChannels }, true},
 		{"Good Channels", func(ni *DefaultNodeInfo) { ni.Channels = ni.Channels[:5] }, false},
 
+		{"Unknown CompressedChannels", func(ni *DefaultNodeInfo) { ni.CompressedChannels = []byte{byte(maxNumChannels)} }, true},
+		{"Duplicate CompressedChannels", func(ni *DefaultNodeInfo) { ni.CompressedChannels = []byte{0x01, 0x01} }, true},
+		{"Good CompressedChannels", func(ni *DefaultNodeInfo) { ni.CompressedChannels = []byte{0x01, 0x02} }, false},
+		{"Non-ASCII Compression", func(ni *DefaultNodeInfo) { ni.Compression = nonAscii }, true},
+		{"Unknown Compression", func(ni *DefaultNodeInfo) { ni.Compression = "zstd" }, false},
+
+		{"Short GenesisHash", func(ni *DefaultNodeInfo) { ni.GenesisHash = make([]byte, 20) }, true},
+		{"Good GenesisHash", func(ni *DefaultNodeInfo) { ni.GenesisHash = make([]byte, 32) }, false},
+
 		{"Invalid NetAddress", func(ni *DefaultNodeInfo) { ni.ListenAddr = "not-an-address" }, true},
 		{"Good NetAddress", func(ni *DefaultNodeInfo) { ni.ListenAddr = "0.0.0.0:26656" }, false},
 
@@ -121,3 +131,25 @@ func TestNodeInfoCompatible(t *testing.T) {
 		assert.Error(t, ni1.CompatibleWith(ni))
 	}
 }
+
+func TestNodeInfoCompressedChannels(t *testing.T) {
+	nodeKey1 := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	nodeKey2 := NodeKey{PrivKey: ed25519.GenPrivKey()}
+	name := "testing"
+
+	ni1 := testNodeInfo(nodeKey1.ID(), name).(DefaultNodeInfo)
+	ni1.Compression = conn.CompressionSnappy
+	ni1.CompressedChannels = []byte{0x01, 0x02, 0x03}
+	ni2 := testNodeInfo(nodeKey2.ID(), name).(DefaultNodeInfo)
+	ni2.Compression = conn.CompressionSnappy
+	ni2.CompressedChannels = []byte{0x03, 0x02, 0x04}
+	assert.Equal(t, []byte{0x02, 0x03}, ni1.compressedChannelsWith(ni2))
+
+	// different compression
+	ni2.Compression = "zstd"
+	assert.Empty(t, ni1.compressedChannelsWith(ni2))
+
+	// no compression
+	ni1.Compression, ni2.Compression = conn.CompressionNone, conn.CompressionNone
+	assert.Empty(t, ni1.compressedChannelsWith(ni2))
+}
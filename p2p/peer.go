@@ -3,6 +3,7 @@ package p2p
 import (
 	"fmt"
 	"net"
+	"runtime/debug"
 	"time"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -30,10 +31,14 @@ type Peer interface {
 	NodeInfo() NodeInfo // peer's info
 	Status() tmconn.ConnectionStatus
 	OriginalAddr() *NetAddress // original address for outbound peers
+	ClockOffset() *ClockOffset // estimated at the handshake, nil if unknown
 
 	Send(byte, []byte) bool
 	TrySend(byte, []byte) bool
 
+	PauseRecv(byte)  // stop delivering msgs from the channel (backpressure)
+	ResumeRecv(byte) // resume delivering msgs from the channel
+
 	Set(string, interface{})
 	Get(string) interface{}
 }
@@ -48,6 +53,9 @@ type peerConn struct {
 
 	originalAddr *NetAddress // nil for inbound connections
 
+	// estimated after the handshake, nil if the peer does not support it
+	clockOffset *ClockOffset
+
 	// cached RemoteIP()
 	ip net.IP
 }
@@ -114,6 +122,9 @@ type peer struct {
 
 	metrics       *Metrics
 	metricsTicker *time.Ticker
+
+	// called with the panics of the reactors receiving from the peer
+	onReceivePanic func(p Peer, chID byte, v interface{}, stack []byte)
 }
 
 type PeerOption func(*peer)
@@ -232,6 +243,12 @@ func (p *peer) OriginalAddr() *NetAddress {
 	return nil
 }
 
+// ClockOffset returns the offset of the clock of the peer against ours,
+// estimated after the handshake, or nil if the peer does not support it.
+func (p *peer) ClockOffset() *ClockOffset {
+	return p.peerConn.clockOffset
+}
+
 // Status returns the peer's ConnectionStatus.
 func (p *peer) Status() tmconn.ConnectionStatus {
 	return p.mconn.Status()
@@ -269,6 +286,18 @@ func (p *peer) TrySend(chID byte, msgBytes []byte) bool {
 	return res
 }
 
+// PauseRecv stops reading msgs from the peer once the next msg on the channel
+// identified by chID byte is received, until ResumeRecv is called.
+func (p *peer) PauseRecv(chID byte) {
+	p.mconn.PauseRecv(chID)
+}
+
+// ResumeRecv resumes reading msgs from the peer on the channel identified by
+// chID byte.
+func (p *peer) ResumeRecv(chID byte) {
+	p.mconn.ResumeRecv(chID)
+}
+
 // Get the data for a given key.
 func (p *peer) Get(key string) interface{} {
 	return p.Data.Get(key)
@@ -334,6 +363,14 @@ func PeerMetrics(metrics *Metrics) PeerOption {
 	}
 }
 
+// PeerOnReceivePanic sets the function called with the panics of the reactors
+// receiving from the peer, before the peer is stopped for the error.
+func PeerOnReceivePanic(onPanic func(p Peer, chID byte, v interface{}, stack []byte)) PeerOption {
+	return func(p *peer) {
+		p.onReceivePanic = onPanic
+	}
+}
+
 func (p *peer) metricsReporter() {
 	for {
 		select {
@@ -342,6 +379,11 @@ func (p *peer) metricsReporter() {
 			var sendQueueSize float64
 			for _, chStatus := range status.Channels {
 				sendQueueSize += float64(chStatus.SendQueueSize)
+				if chStatus.CompressedBytes > 0 {
+					p.metrics.PeerCompressionRatio.With("peer_id", string(p.ID()),
+						"channel_id", fmt.Sprintf("%#x", chStatus.ID)).
+						Set(float64(chStatus.UncompressedBytes) / float64(chStatus.CompressedBytes))
+				}
 			}
 
 			p.metrics.PeerPendingSendBytes.With("peer_id", string(p.ID())).Set(sendQueueSize)
@@ -364,6 +406,15 @@ func createMConnection(
 ) *tmconn.MConnection {
 
 	onReceive := func(chID byte, msgBytes []byte) {
+		if p.onReceivePanic != nil {
+			defer func() {
+				if r := recover(); r != nil {
+					p.onReceivePanic(p, chID, r, debug.Stack())
+					// caught again in the conn._recover
+					panic(r)
+				}
+			}()
+		}
 		reactor := reactorsByCh[chID]
 		if reactor == nil {
 			// Note that its ok to panic here as it's caught in the conn._recover,
@@ -0,0 +1,73 @@
+package p2p
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PeerGroup is a set of redundant peers (e.g. the validators behind a
+// sentry), of which the switch keeps at least MinConnected connected instead
+// of maintaining persistent connections to all of them.
+type PeerGroup struct {
+	MinConnected int
+	Addrs        []*NetAddress
+}
+
+// NewPeerGroupsString returns the peer groups described by s, in the form
+// "K:ID@IP:Port,ID@IP:Port;K:ID@IP:Port,...", where K is the minimum number
+// of peers of the group to stay connected to.
+func NewPeerGroupsString(s string) ([]PeerGroup, error) {
+	groups := make([]PeerGroup, 0)
+	for _, groupStr := range strings.Split(s, ";") {
+		groupStr = strings.TrimSpace(groupStr)
+		if groupStr == "" {
+			continue
+		}
+
+		spl := strings.SplitN(groupStr, ":", 2)
+		if len(spl) != 2 {
+			return nil, fmt.Errorf("peer group %q: expected K:addrs", groupStr)
+		}
+		minConnected, err := strconv.Atoi(strings.TrimSpace(spl[0]))
+		if err != nil {
+			return nil, fmt.Errorf("peer group %q: invalid K: %v", groupStr, err)
+		}
+
+		addrs := make([]*NetAddress, 0)
+		for _, addrStr := range strings.Split(spl[1], ",") {
+			addrStr = strings.TrimSpace(addrStr)
+			if addrStr == "" {
+				continue
+			}
+			addr, err := NewNetAddressString(addrStr)
+			if err != nil {
+				return nil, fmt.Errorf("peer group %q: %v", groupStr, err)
+			}
+			addrs = append(addrs, addr)
+		}
+
+		if minConnected < 1 || minConnected > len(addrs) {
+			return nil, fmt.Errorf(
+				"peer group %q: K must be between 1 and the number of peers (%d), got %d",
+				groupStr,
+				len(addrs),
+				minConnected,
+			)
+		}
+
+		groups = append(groups, PeerGroup{MinConnected: minConnected, Addrs: addrs})
+	}
+	return groups, nil
+}
+
+// NumConnected returns the number of peers of the group present in peers.
+func (pg PeerGroup) NumConnected(peers IPeerSet) int {
+	n := 0
+	for _, addr := range pg.Addrs {
+		if peers.Has(addr.ID) {
+			n++
+		}
+	}
+	return n
+}
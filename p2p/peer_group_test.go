@@ -0,0 +1,47 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPeerGroupsString(t *testing.T) {
+	const (
+		addr1 = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef@127.0.0.1:8080"
+		addr2 = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeed@127.0.0.2:8080"
+	)
+
+	testCases := []struct {
+		name    string
+		groups  string
+		sizes   []int
+		correct bool
+	}{
+		{"empty", "", []int{}, true},
+		{"one group", "1:" + addr1 + "," + addr2, []int{2}, true},
+		{"two groups", "1:" + addr1 + ";1:" + addr2 + ";", []int{1, 1}, true},
+		{"whitespace", " 2 : " + addr1 + " , " + addr2 + " ", []int{2}, true},
+		{"no K", addr1, nil, false},
+		{"invalid K", "x:" + addr1, nil, false},
+		{"zero K", "0:" + addr1, nil, false},
+		{"K too big", "2:" + addr1, nil, false},
+		{"invalid addr", "1:127.0.0.1:8080", nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			groups, err := NewPeerGroupsString(tc.groups)
+			if !tc.correct {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, groups, len(tc.sizes))
+			for i, size := range tc.sizes {
+				assert.Len(t, groups[i].Addrs, size)
+			}
+		})
+	}
+}
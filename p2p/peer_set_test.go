@@ -30,8 +30,11 @@ func (mp *mockPeer) Get(s string) interface{}                { return s }
 func (mp *mockPeer) Set(string, interface{})                 {}
 func (mp *mockPeer) RemoteIP() net.IP                        { return mp.ip }
 func (mp *mockPeer) OriginalAddr() *NetAddress               { return nil }
+func (mp *mockPeer) ClockOffset() *ClockOffset               { return nil }
 func (mp *mockPeer) RemoteAddr() net.Addr                    { return &net.TCPAddr{IP: mp.ip, Port: 8800} }
 func (mp *mockPeer) CloseConn() error                        { return nil }
+func (mp *mockPeer) PauseRecv(chID byte)                     {}
+func (mp *mockPeer) ResumeRecv(chID byte)                    {}
 
 // Returns a mock peer
 func newMockPeer(ip net.IP) *mockPeer {
@@ -0,0 +1,80 @@
+package p2p
+
+import (
+	"sort"
+)
+
+// PeerVersion is the number of peers running a version.
+type PeerVersion struct {
+	Version         string          `json:"version"`
+	ProtocolVersion ProtocolVersion `json:"protocol_version"`
+	NPeers          int             `json:"n_peers"`
+}
+
+// PeerVersions returns the versions run by the peers, the most common first.
+func PeerVersions(peers IPeerSet) []PeerVersion {
+	type key struct {
+		version         string
+		protocolVersion ProtocolVersion
+	}
+	counts := make(map[key]int)
+	for _, peer := range peers.List() {
+		info, ok := peer.NodeInfo().(DefaultNodeInfo)
+		if !ok {
+			continue
+		}
+		counts[key{info.Version, info.ProtocolVersion}]++
+	}
+
+	versions := make([]PeerVersion, 0, len(counts))
+	for k, n := range counts {
+		versions = append(versions, PeerVersion{
+			Version:         k.version,
+			ProtocolVersion: k.protocolVersion,
+			NPeers:          n,
+		})
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].NPeers != versions[j].NPeers {
+			return versions[i].NPeers > versions[j].NPeers
+		}
+		return versions[i].Version < versions[j].Version
+	})
+	return versions
+}
+
+// newerThan returns whether the p2p or block protocol version is newer than
+// the other one. The app version is the one of the app, not of the binary.
+func (pv ProtocolVersion) newerThan(other ProtocolVersion) bool {
+	return pv.P2P > other.P2P || pv.Block > other.Block
+}
+
+// checkPeerVersions warns once when more than NewerPeersWarnRatio of the peers
+// run a newer protocol version, and again if it happens after it stopped.
+func (sw *Switch) checkPeerVersions() {
+	ourInfo, ok := sw.nodeInfo.(DefaultNodeInfo)
+	if !ok || sw.config.NewerPeersWarnRatio <= 0 {
+		return
+	}
+
+	peers := sw.peers.List()
+	if len(peers) == 0 {
+		return
+	}
+	newer := 0
+	for _, peer := range peers {
+		info, ok := peer.NodeInfo().(DefaultNodeInfo)
+		if ok && info.ProtocolVersion.newerThan(ourInfo.ProtocolVersion) {
+			newer++
+		}
+	}
+	behind := float64(newer) > sw.config.NewerPeersWarnRatio*float64(len(peers))
+
+	sw.newerPeersMtx.Lock()
+	defer sw.newerPeersMtx.Unlock()
+	if behind && !sw.newerPeersWarned {
+		sw.Logger.Error("Peers run a newer protocol version, this node is likely behind an upgrade",
+			"newer", newer, "peers", len(peers), "ours", ourInfo.ProtocolVersion, "version", ourInfo.Version)
+	}
+	sw.newerPeersWarned = behind
+}
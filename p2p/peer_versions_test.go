@@ -0,0 +1,68 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/version"
+)
+
+// versionedPeer is a mockPeer with a version
+type versionedPeer struct {
+	*mockPeer
+	info DefaultNodeInfo
+}
+
+func (vp versionedPeer) NodeInfo() NodeInfo { return vp.info }
+
+func newVersionedPeer(semver string, block uint64) versionedPeer {
+	return versionedPeer{
+		mockPeer: newMockPeer(nil),
+		info: DefaultNodeInfo{
+			Version:         semver,
+			ProtocolVersion: NewProtocolVersion(7, version.Protocol(block), 0),
+		},
+	}
+}
+
+func TestPeerVersions(t *testing.T) {
+	peers := NewPeerSet()
+	for _, p := range []versionedPeer{
+		newVersionedPeer("0.31.0", 9),
+		newVersionedPeer("0.30.0", 8),
+		newVersionedPeer("0.31.0", 9),
+	} {
+		require.NoError(t, peers.Add(p))
+	}
+
+	assert.Equal(t, []PeerVersion{
+		{Version: "0.31.0", ProtocolVersion: NewProtocolVersion(7, 9, 0), NPeers: 2},
+		{Version: "0.30.0", ProtocolVersion: NewProtocolVersion(7, 8, 0), NPeers: 1},
+	}, PeerVersions(peers))
+}
+
+func TestSwitchCheckPeerVersions(t *testing.T) {
+	cfg := config.DefaultP2PConfig()
+	cfg.NewerPeersWarnRatio = 0.5
+	sw := NewSwitch(cfg, nil)
+	sw.SetLogger(log.TestingLogger())
+	sw.SetNodeInfo(DefaultNodeInfo{Version: "0.30.0", ProtocolVersion: NewProtocolVersion(7, 8, 0)})
+
+	newer := newVersionedPeer("0.31.0", 9)
+	AddPeerToSwitch(sw, newer)
+	AddPeerToSwitch(sw, newVersionedPeer("0.30.0", 8))
+	sw.checkPeerVersions()
+	assert.False(t, sw.newerPeersWarned, "half of the peers are newer")
+
+	AddPeerToSwitch(sw, newVersionedPeer("0.31.0", 9))
+	sw.checkPeerVersions()
+	assert.True(t, sw.newerPeersWarned)
+
+	sw.peers.Remove(newer)
+	sw.checkPeerVersions()
+	assert.False(t, sw.newerPeersWarned)
+}
@@ -44,6 +44,9 @@ type AddrBook interface {
 	// Check if the address is in the book
 	HasAddress(*p2p.NetAddress) bool
 
+	// Move an address to the new ID of a node which rotated its key
+	RotateID(oldID, newID p2p.ID)
+
 	// Do we need more peers?
 	NeedMoreAddrs() bool
 	// Is Address Book Empty? Answer should not depend on being in your own
@@ -211,6 +214,33 @@ func (a *addrBook) RemoveAddress(addr *p2p.NetAddress) {
 	a.removeFromAllBuckets(ka)
 }
 
+// RotateID implements AddrBook - moves the address of the node of oldID, and
+// what is known about it, to newID, once the node announced the rotation of
+// its key. An address of newID already in the book is replaced.
+func (a *addrBook) RotateID(oldID, newID p2p.ID) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ka := a.addrLookup[oldID]
+	if ka == nil || oldID == newID {
+		return
+	}
+	if existing := a.addrLookup[newID]; existing != nil {
+		a.removeFromAllBuckets(existing)
+	}
+
+	addr := &p2p.NetAddress{ID: newID, IP: ka.Addr.IP, Port: ka.Addr.Port, Name: ka.Addr.Name}
+	a.Logger.Info("Rotate address ID in book", "addr", ka.Addr, "id", newID)
+	for _, bucketIdx := range ka.Buckets {
+		bucket := a.getBucket(ka.BucketType, bucketIdx)
+		delete(bucket, ka.Addr.String())
+		bucket[addr.String()] = ka
+	}
+	delete(a.addrLookup, oldID)
+	ka.Addr = addr
+	a.addrLookup[newID] = ka
+}
+
 // IsGood returns true if peer was ever marked as good and haven't
 // done anything wrong since then.
 func (a *addrBook) IsGood(addr *p2p.NetAddress) bool {
@@ -826,8 +856,8 @@ func (a *addrBook) calcOldBucket(addr *p2p.NetAddress) int {
 
 // Return a string representing the network group of this address.
 // This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the string
-// "local" for a local address and the string "unroutable" for an unroutable
-// address.
+// "local" for a local address, the string "unroutable" for an unroutable
+// address and the string "onion" for a Tor hidden service.
 func (a *addrBook) groupKey(na *p2p.NetAddress) string {
 	if a.routabilityStrict && na.Local() {
 		return "local"
@@ -835,6 +865,9 @@ func (a *addrBook) groupKey(na *p2p.NetAddress) string {
 	if a.routabilityStrict && !na.Routable() {
 		return "unroutable"
 	}
+	if na.IsOnion() {
+		return "onion"
+	}
 
 	if ipv4 := na.IP.To4(); ipv4 != nil {
 		return (&net.IPNet{IP: na.IP, Mask: net.CIDRMask(16, 32)}).String()
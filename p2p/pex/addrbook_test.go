@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/p2p"
@@ -84,6 +85,23 @@ func TestAddrBookSaveLoad(t *testing.T) {
 	assert.Equal(t, 100, book.Size())
 }
 
+func TestAddrBookInMemory(t *testing.T) {
+	book := NewAddrBook("", true)
+	book.SetLogger(log.TestingLogger())
+	require.NoError(t, book.Start())
+
+	for _, addrSrc := range randNetAddressPairs(t, 10) {
+		book.AddAddress(addrSrc.addr, addrSrc.src)
+	}
+	assert.Equal(t, 10, book.Size())
+	assert.Equal(t, "", book.FilePath())
+
+	// nothing to save nor load
+	book.Stop()
+	book.Wait()
+	assert.False(t, book.loadFromFile(""))
+}
+
 func TestAddrBookLookup(t *testing.T) {
 	fname := createTempFileName("addrbook_test")
 	defer deleteTempFile(fname)
@@ -219,6 +237,38 @@ func TestAddrBookRemoveAddress(t *testing.T) {
 	assert.Equal(t, 0, book.Size())
 }
 
+func TestAddrBookRotateID(t *testing.T) {
+	fname := createTempFileName("addrbook_test")
+	defer deleteTempFile(fname)
+
+	book := NewAddrBook(fname, true)
+	book.SetLogger(log.TestingLogger())
+
+	addr := randIPv4Address(t)
+	book.AddAddress(addr, addr)
+	book.MarkGood(addr)
+	require.True(t, book.IsGood(addr))
+
+	newID := p2p.PubKeyToID(ed25519.GenPrivKey().PubKey())
+	book.RotateID(addr.ID, newID)
+	assert.Equal(t, 1, book.Size())
+	assert.False(t, book.HasAddress(addr))
+
+	// the reputation of the old ID is the one of the new ID
+	newAddr := p2p.NewNetAddressIPPort(addr.IP, addr.Port)
+	newAddr.ID = newID
+	assert.True(t, book.HasAddress(newAddr))
+	assert.True(t, book.IsGood(newAddr))
+	kas := book.ListOfKnownAddresses()
+	require.Len(t, kas, 1)
+	assert.Equal(t, newAddr.String(), kas[0].Addr.String())
+
+	// unknown ID
+	book.RotateID(addr.ID, p2p.PubKeyToID(ed25519.GenPrivKey().PubKey()))
+	assert.Equal(t, 1, book.Size())
+	assert.True(t, book.HasAddress(newAddr))
+}
+
 func TestAddrBookGetSelectionWithOneMarkedGood(t *testing.T) {
 	// create a book with 10 addresses, 1 good/old and 9 new
 	book, fname := createAddrBookWithMOldAndNNewAddrs(t, 1, 9)
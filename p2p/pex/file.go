@@ -10,12 +10,17 @@ import (
 
 /* Loading & Saving */
 
+// The address book is kept in memory only if its file path is empty.
+
 type addrBookJSON struct {
 	Key   string          `json:"key"`
 	Addrs []*knownAddress `json:"addrs"`
 }
 
 func (a *addrBook) saveToFile(filePath string) {
+	if filePath == "" {
+		return
+	}
 	a.Logger.Info("Saving AddrBook to file", "size", a.Size())
 
 	a.mtx.Lock()
@@ -45,7 +50,10 @@ func (a *addrBook) saveToFile(filePath string) {
 // Returns false if file does not exist.
 // cmn.Panics if file is corrupt.
 func (a *addrBook) loadFromFile(filePath string) bool {
-	// If doesn't exist, do nothing.
+	// If kept in memory only, or doesn't exist, do nothing.
+	if filePath == "" {
+		return false
+	}
 	_, err := os.Stat(filePath)
 	if os.IsNotExist(err) {
 		return false
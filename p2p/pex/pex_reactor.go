@@ -158,6 +158,11 @@ func (r *PEXReactor) GetChannels() []*conn.ChannelDescriptor {
 // AddPeer implements Reactor by adding peer to the address book (if inbound)
 // or by requesting more addresses (if outbound).
 func (r *PEXReactor) AddPeer(p Peer) {
+	// move what we know about the peer to its new ID, if it rotated its key
+	if ni, ok := p.NodeInfo().(p2p.DefaultNodeInfo); ok && ni.KeyRotation != nil {
+		r.book.RotateID(ni.KeyRotation.OldID(), p.ID())
+	}
+
 	if p.IsOutbound() {
 		// For outbound peers, the address is already in the books -
 		// either via DialPeersAsync or r.Receive.
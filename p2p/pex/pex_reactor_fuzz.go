@@ -0,0 +1,41 @@
+// +build gofuzz
+
+package pex
+
+import (
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/p2p/dummy"
+)
+
+var (
+	fuzzReactor *PEXReactor
+	fuzzPeer    Peer
+)
+
+// FuzzReactorReceive passes data to the Receive method of the reactor. It
+// returns 1 for the data decoding to a message, to give it priority.
+//
+// Build it with go-fuzz-build -func FuzzReactorReceive.
+func FuzzReactorReceive(data []byte) int {
+	if fuzzReactor == nil {
+		fuzzReactor, fuzzPeer = newFuzzReactor()
+	}
+	fuzzReactor.Receive(PexChannel, fuzzPeer, data)
+	if _, err := decodeMsg(data); err != nil {
+		return 0
+	}
+	return 1
+}
+
+func newFuzzReactor() (*PEXReactor, Peer) {
+	// the address book is never saved
+	r := NewPEXReactor(NewAddrBook("", false), &PEXReactorConfig{})
+
+	p2pConfig := config.DefaultP2PConfig()
+	sw := p2p.NewSwitch(p2pConfig, p2p.NewMultiplexTransport(p2p.DefaultNodeInfo{},
+		p2p.NodeKey{PrivKey: ed25519.GenPrivKey()}, p2p.MConnConfig(p2pConfig)))
+	sw.AddReactor("PEX", r)
+	return r, dummy.NewPeer()
+}
@@ -479,8 +479,11 @@ func (mockPeer) TrySend(byte, []byte) bool     { return false }
 func (mockPeer) Set(string, interface{})       {}
 func (mockPeer) Get(string) interface{}        { return nil }
 func (mockPeer) OriginalAddr() *p2p.NetAddress { return nil }
+func (mockPeer) ClockOffset() *p2p.ClockOffset { return nil }
 func (mockPeer) RemoteAddr() net.Addr          { return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8800} }
 func (mockPeer) CloseConn() error              { return nil }
+func (mockPeer) PauseRecv(byte)                {}
+func (mockPeer) ResumeRecv(byte)               {}
 
 func assertPeersWithTimeout(
 	t *testing.T,
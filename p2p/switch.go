@@ -25,6 +25,9 @@ const (
 	// ie. 3**10 = 16hrs
 	reconnectBackOffAttempts    = 10
 	reconnectBackOffBaseSeconds = 3
+
+	// how often to check that enough peers of each peer group are connected
+	peerGroupsCheckInterval = 10 * time.Second
 )
 
 // MConnConfig returns an MConnConfig with fields updated
@@ -35,6 +38,7 @@ func MConnConfig(cfg *config.P2PConfig) conn.MConnConfig {
 	mConfig.SendRate = cfg.SendRate
 	mConfig.RecvRate = cfg.RecvRate
 	mConfig.MaxPacketMsgPayloadSize = cfg.MaxPacketMsgPayloadSize
+	mConfig.CompressionMinSize = cfg.CompressionMinSize
 	return mConfig
 }
 
@@ -80,10 +84,17 @@ type Switch struct {
 
 	filterTimeout time.Duration
 	peerFilters   []PeerFilterFunc
+	peerGroups    []PeerGroup
 
 	rng *cmn.Rand // seed for randomizing dial times and orders
 
 	metrics *Metrics
+
+	panicHandler func(source string, v interface{}, stack []byte)
+
+	// whether the peers running a newer protocol version were warned about
+	newerPeersMtx    sync.Mutex
+	newerPeersWarned bool
 }
 
 // SwitchOption sets an optional parameter on the Switch.
@@ -130,6 +141,17 @@ func SwitchPeerFilters(filters ...PeerFilterFunc) SwitchOption {
 	return func(sw *Switch) { sw.peerFilters = filters }
 }
 
+// SwitchPanicHandler sets the function called with the panics of the reactors
+// receiving from a peer, before the peer is stopped for the error.
+func SwitchPanicHandler(handler func(source string, v interface{}, stack []byte)) SwitchOption {
+	return func(sw *Switch) { sw.panicHandler = handler }
+}
+
+// SwitchPeerGroups sets the peer groups the switch keeps connections to.
+func SwitchPeerGroups(groups ...PeerGroup) SwitchOption {
+	return func(sw *Switch) { sw.peerGroups = groups }
+}
+
 // WithMetrics sets the metrics.
 func WithMetrics(metrics *Metrics) SwitchOption {
 	return func(sw *Switch) { sw.metrics = metrics }
@@ -203,6 +225,10 @@ func (sw *Switch) OnStart() error {
 	// Start accepting Peers.
 	go sw.acceptRoutine()
 
+	if len(sw.peerGroups) > 0 {
+		go sw.peerGroupsRoutine()
+	}
+
 	return nil
 }
 
@@ -276,6 +302,22 @@ func (sw *Switch) Peers() IPeerSet {
 	return sw.peers
 }
 
+// SetPeerBusy lets a reactor signal it is busy handling msgs received from the
+// peer on the given channel: the msgs of the channel are queued until
+// SetPeerReady is called, and the switch stops reading from the peer if too
+// many are, see MConnection#PauseRecv. Reactors should keep such pauses short.
+func (sw *Switch) SetPeerBusy(peer Peer, chID byte) {
+	sw.Logger.Debug("Pausing receiving from peer", "peer", peer, "channel", chID)
+	peer.PauseRecv(chID)
+}
+
+// SetPeerReady lets a reactor signal it is ready to handle msgs received
+// from the peer on the given channel again.
+func (sw *Switch) SetPeerReady(peer Peer, chID byte) {
+	sw.Logger.Debug("Resuming receiving from peer", "peer", peer, "channel", chID)
+	peer.ResumeRecv(chID)
+}
+
 // StopPeerForError disconnects from a peer due to external error.
 // If the peer is persistent, it will attempt to reconnect.
 // TODO: make record depending on reason.
@@ -310,6 +352,7 @@ func (sw *Switch) stopAndRemovePeer(peer Peer, reason interface{}) {
 	for _, reactor := range sw.reactors {
 		reactor.RemovePeer(peer, reason)
 	}
+	sw.checkPeerVersions()
 }
 
 // reconnectToPeer tries to reconnect to the addr, first repeatedly
@@ -369,6 +412,66 @@ func (sw *Switch) reconnectToPeer(addr *NetAddress) {
 	sw.Logger.Error("Failed to reconnect to peer. Giving up", "addr", addr, "elapsed", time.Since(start))
 }
 
+// peerGroupsRoutine periodically dials peers of the groups which have less
+// than MinConnected peers connected. Peers of a group are not persistent:
+// when one of them disconnects, another one of the group may replace it.
+func (sw *Switch) peerGroupsRoutine() {
+	ticker := time.NewTicker(peerGroupsCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		sw.ensurePeerGroups()
+
+		select {
+		case <-ticker.C:
+		case <-sw.Quit():
+			return
+		}
+	}
+}
+
+func (sw *Switch) ensurePeerGroups() {
+	for _, group := range sw.peerGroups {
+		missing := group.MinConnected - group.NumConnected(sw.peers)
+		if missing <= 0 {
+			continue
+		}
+
+		// dial missing peers in random order, skipping the ones connected or
+		// being dialed already.
+		perm := sw.rng.Perm(len(group.Addrs))
+		for i := 0; i < len(perm) && missing > 0; i++ {
+			addr := group.Addrs[perm[i]]
+			if sw.IsDialingOrExistingAddress(addr) {
+				continue
+			}
+			missing--
+
+			go func(addr *NetAddress) {
+				err := sw.DialPeerWithAddress(addr, false)
+				if err != nil {
+					sw.Logger.Info("Error dialing peer group member", "err", err, "addr", addr)
+				}
+			}(addr)
+		}
+	}
+}
+
+// onReceivePanic passes the panic of the reactor receiving on the channel from
+// the peer on to the panic handler, if any.
+func (sw *Switch) onReceivePanic(peer Peer, chID byte, v interface{}, stack []byte) {
+	if sw.panicHandler == nil {
+		return
+	}
+	name := "unknown"
+	for n, reactor := range sw.reactors {
+		if reactor == sw.reactorsByCh[chID] {
+			name = n
+		}
+	}
+	sw.panicHandler(fmt.Sprintf("reactor %s receiving on channel %#x from peer %v", name, chID, peer.ID()), v, stack)
+}
+
 // SetAddrBook allows to set address book on Switch.
 func (sw *Switch) SetAddrBook(addrBook AddrBook) {
 	sw.addrBook = addrBook
@@ -474,10 +577,11 @@ func (sw *Switch) IsDialingOrExistingAddress(addr *NetAddress) bool {
 func (sw *Switch) acceptRoutine() {
 	for {
 		p, err := sw.transport.Accept(peerConfig{
-			chDescs:      sw.chDescs,
-			onPeerError:  sw.StopPeerForError,
-			reactorsByCh: sw.reactorsByCh,
-			metrics:      sw.metrics,
+			chDescs:        sw.chDescs,
+			onPeerError:    sw.StopPeerForError,
+			reactorsByCh:   sw.reactorsByCh,
+			metrics:        sw.metrics,
+			onReceivePanic: sw.onReceivePanic,
 		})
 		if err != nil {
 			switch err := err.(type) {
@@ -567,11 +671,12 @@ func (sw *Switch) addOutboundPeerWithConfig(
 	}
 
 	p, err := sw.transport.Dial(*addr, peerConfig{
-		chDescs:      sw.chDescs,
-		onPeerError:  sw.StopPeerForError,
-		persistent:   persistent,
-		reactorsByCh: sw.reactorsByCh,
-		metrics:      sw.metrics,
+		chDescs:        sw.chDescs,
+		onPeerError:    sw.StopPeerForError,
+		persistent:     persistent,
+		reactorsByCh:   sw.reactorsByCh,
+		metrics:        sw.metrics,
+		onReceivePanic: sw.onReceivePanic,
 	})
 	if err != nil {
 		switch e := err.(type) {
@@ -663,6 +768,7 @@ func (sw *Switch) addPeer(p Peer) error {
 
 	sw.Logger.Info("Added peer", "peer", p)
 	sw.metrics.Peers.Add(float64(1))
+	sw.checkPeerVersions()
 
 	return nil
 }
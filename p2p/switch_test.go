@@ -463,6 +463,29 @@ func TestSwitchReconnectsToPersistentPeer(t *testing.T) {
 	assert.EqualValues(2, npeers)
 }
 
+func TestSwitchConnectsToPeerGroup(t *testing.T) {
+	// simulate remote peers
+	rp1 := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	rp1.Start()
+	defer rp1.Stop()
+	rp2 := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	rp2.Start()
+	defer rp2.Stop()
+
+	group := PeerGroup{MinConnected: 1, Addrs: []*NetAddress{rp1.Addr(), rp2.Addr()}}
+	sw := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc, SwitchPeerGroups(group))
+	err := sw.Start()
+	require.NoError(t, err)
+	defer sw.Stop()
+
+	// only one peer of the group must be dialed
+	for i := 0; i < 20 && sw.Peers().Size() == 0; i++ {
+		time.Sleep(250 * time.Millisecond)
+	}
+	assert.Equal(t, 1, sw.Peers().Size())
+	assert.Equal(t, 1, group.NumConnected(sw.Peers()))
+}
+
 func TestSwitchFullConnectivity(t *testing.T) {
 	switches := MakeConnectedSwitches(cfg, 3, initSwitchFunc, Connect2Switches)
 	defer func() {
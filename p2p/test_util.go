@@ -225,7 +225,7 @@ func testPeerConn(
 	}
 
 	// Encrypt connection
-	conn, err = upgradeSecretConn(conn, cfg.HandshakeTimeout, ourNodePrivKey)
+	conn, err = upgradeSecretConn(conn, cfg.HandshakeTimeout, ourNodePrivKey, false, nil)
 	if err != nil {
 		return pc, cmn.ErrorWrap(err, "Error creating peer")
 	}
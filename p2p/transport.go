@@ -2,10 +2,15 @@ package p2p
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
+	"sync"
 	"time"
 
+	"golang.org/x/net/proxy"
+
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/p2p/conn"
 )
@@ -21,12 +26,29 @@ type IPResolver interface {
 	LookupIPAddr(context.Context, string) ([]net.IPAddr, error)
 }
 
+// Dialer is a behaviour subset of net.Dialer and proxy.Dialer.
+type Dialer interface {
+	Dial(network, addr string) (net.Conn, error)
+}
+
+// NewProxyDialer returns a Dialer which connects through the proxy given as
+// URL (e.g. socks5://127.0.0.1:9050), connecting to the proxy itself with
+// the given timeout.
+func NewProxyDialer(proxyURL string, timeout time.Duration) (Dialer, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	return proxy.FromURL(u, &net.Dialer{Timeout: timeout})
+}
+
 // accept is the container to carry the upgraded connection and NodeInfo from an
 // asynchronously running routine to the Accept method.
 type accept struct {
-	conn     net.Conn
-	nodeInfo NodeInfo
-	err      error
+	conn        net.Conn
+	nodeInfo    NodeInfo
+	clockOffset *ClockOffset
+	err         error
 }
 
 // peerConfig is used to bundle data we need to fully setup a Peer with an
@@ -41,6 +63,7 @@ type peerConfig struct {
 	outbound, persistent bool
 	reactorsByCh         map[byte]Reactor
 	metrics              *Metrics
+	onReceivePanic       func(Peer, byte, interface{}, []byte)
 }
 
 // Transport emits and connects to Peers. The implementation of Peer is left to
@@ -106,6 +129,37 @@ func MultiplexTransportFilterTimeout(
 	return func(mt *MultiplexTransport) { mt.filterTimeout = timeout }
 }
 
+// MultiplexTransportDialer sets the Dialer used for outbound connections,
+// e.g. one created with NewProxyDialer. If set, all outbound connections go
+// through it and the connection filters are not applied to them, as the
+// remote address is that of the proxy.
+func MultiplexTransportDialer(dialer Dialer) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.dialer = dialer }
+}
+
+// MultiplexTransportListen sets the function creating the listener of the
+// transport, e.g. the Listen of a MemNetwork, defaults to net.Listen.
+func MultiplexTransportListen(
+	listen func(network, address string) (net.Listener, error),
+) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.listen = listen }
+}
+
+// MultiplexTransportNoiseHandshake sets whether the persistent peers are
+// dialed with the Noise IK handshake once their key is pinned, i.e. known from
+// a previous connection, instead of the STS handshake. Inbound connections
+// accept both handshakes.
+func MultiplexTransportNoiseHandshake(enabled bool) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.noiseHandshake = enabled }
+}
+
+// MultiplexTransportCertificateAuthorities sets the certificate authorities of
+// a permissioned network: peers must present a certificate signed by one of
+// them in their NodeInfo.
+func MultiplexTransportCertificateAuthorities(cas []crypto.PubKey) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.cas = cas }
+}
+
 // MultiplexTransportResolver sets the Resolver used for ip lokkups, defaults to
 // net.DefaultResolver.
 func MultiplexTransportResolver(resolver IPResolver) MultiplexTransportOption {
@@ -124,6 +178,8 @@ type MultiplexTransport struct {
 	conns       ConnSet
 	connFilters []ConnFilterFunc
 
+	dialer           Dialer
+	listen           func(network, address string) (net.Listener, error)
 	dialTimeout      time.Duration
 	filterTimeout    time.Duration
 	handshakeTimeout time.Duration
@@ -131,6 +187,14 @@ type MultiplexTransport struct {
 	nodeKey          NodeKey
 	resolver         IPResolver
 
+	// Certificate authorities of a permissioned network, if any.
+	cas []crypto.PubKey
+
+	// Keys of the persistent peers, to dial them with the Noise handshake.
+	noiseHandshake bool
+	pinnedKeysMtx  sync.Mutex
+	pinnedKeys     map[ID]crypto.PubKey
+
 	// TODO(xla): This config is still needed as we parameterise peerConn and
 	// peer currently. All relevant configuration should be refactored into options
 	// with sane defaults.
@@ -151,6 +215,7 @@ func NewMultiplexTransport(
 		acceptc:          make(chan accept),
 		closec:           make(chan struct{}),
 		dialTimeout:      defaultDialTimeout,
+		listen:           net.Listen,
 		filterTimeout:    defaultFilterTimeout,
 		handshakeTimeout: defaultHandshakeTimeout,
 		mConfig:          mConfig,
@@ -158,6 +223,7 @@ func NewMultiplexTransport(
 		nodeKey:          nodeKey,
 		conns:            NewConnSet(),
 		resolver:         net.DefaultResolver,
+		pinnedKeys:       make(map[ID]crypto.PubKey),
 	}
 }
 
@@ -173,7 +239,7 @@ func (mt *MultiplexTransport) Accept(cfg peerConfig) (Peer, error) {
 
 		cfg.outbound = false
 
-		return mt.wrapPeer(a.conn, a.nodeInfo, cfg, nil), nil
+		return mt.wrapPeer(a.conn, a.nodeInfo, a.clockOffset, cfg, nil), nil
 	case <-mt.closec:
 		return nil, &ErrTransportClosed{}
 	}
@@ -184,6 +250,17 @@ func (mt *MultiplexTransport) Dial(
 	addr NetAddress,
 	cfg peerConfig,
 ) (Peer, error) {
+	if mt.dialer != nil {
+		return mt.dialProxied(addr, cfg)
+	}
+
+	if addr.IsOnion() {
+		return nil, ErrNetAddressInvalid{
+			addr.String(),
+			errors.New("onion addresses require an upstream proxy"),
+		}
+	}
+
 	c, err := addr.DialTimeout(mt.dialTimeout)
 	if err != nil {
 		return nil, err
@@ -194,18 +271,74 @@ func (mt *MultiplexTransport) Dial(
 		return nil, err
 	}
 
-	secretConn, nodeInfo, err := mt.upgrade(c, &addr)
+	secretConn, nodeInfo, clockOffset, err := mt.dialUpgrade(c, addr, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.outbound = true
+
+	p := mt.wrapPeer(secretConn, nodeInfo, clockOffset, cfg, &addr)
+
+	return p, nil
+}
+
+// dialProxied connects to the Peer through the configured Dialer. The
+// connection is not filtered, nor added to the connection set, as all
+// proxied connections share the remote address of the proxy.
+func (mt *MultiplexTransport) dialProxied(
+	addr NetAddress,
+	cfg peerConfig,
+) (Peer, error) {
+	c, err := mt.dialer.Dial("tcp", addr.DialString())
+	if err != nil {
+		return nil, err
+	}
+
+	secretConn, nodeInfo, clockOffset, err := mt.dialUpgrade(c, addr, cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	cfg.outbound = true
 
-	p := mt.wrapPeer(secretConn, nodeInfo, cfg, &addr)
+	p := mt.wrapPeer(secretConn, nodeInfo, clockOffset, cfg, &addr)
 
 	return p, nil
 }
 
+// DialNodeInfo connects to the address and performs the handshakes, as Dial,
+// but only returns the NodeInfo of the peer, closing the connection. It checks
+// the address points to a compatible node without connecting a peer.
+func (mt *MultiplexTransport) DialNodeInfo(addr NetAddress) (NodeInfo, error) {
+	var (
+		c   net.Conn
+		err error
+	)
+	switch {
+	case mt.dialer != nil:
+		c, err = mt.dialer.Dial("tcp", addr.DialString())
+	case addr.IsOnion():
+		return nil, ErrNetAddressInvalid{
+			addr.String(),
+			errors.New("onion addresses require an upstream proxy"),
+		}
+	default:
+		c, err = addr.DialTimeout(mt.dialTimeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, nodeInfo, _, err := mt.upgrade(c, &addr, nil)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.Close()
+
+	return nodeInfo, nil
+}
+
 // Close implements transportLifecycle.
 func (mt *MultiplexTransport) Close() error {
 	close(mt.closec)
@@ -219,7 +352,7 @@ func (mt *MultiplexTransport) Close() error {
 
 // Listen implements transportLifecycle.
 func (mt *MultiplexTransport) Listen(addr NetAddress) error {
-	ln, err := net.Listen("tcp", addr.DialString())
+	ln, err := mt.listen("tcp", addr.DialString())
 	if err != nil {
 		return err
 	}
@@ -256,17 +389,18 @@ func (mt *MultiplexTransport) acceptPeers() {
 		// [0] https://en.wikipedia.org/wiki/Head-of-line_blocking
 		go func(c net.Conn) {
 			var (
-				nodeInfo   NodeInfo
-				secretConn *conn.SecretConnection
+				nodeInfo    NodeInfo
+				secretConn  *conn.SecretConnection
+				clockOffset *ClockOffset
 			)
 
 			err := mt.filterConn(c)
 			if err == nil {
-				secretConn, nodeInfo, err = mt.upgrade(c, nil)
+				secretConn, nodeInfo, clockOffset, err = mt.upgrade(c, nil, nil)
 			}
 
 			select {
-			case mt.acceptc <- accept{secretConn, nodeInfo, err}:
+			case mt.acceptc <- accept{secretConn, nodeInfo, clockOffset, err}:
 				// Make the upgraded peer available.
 			case <-mt.closec:
 				// Give up if the transport was closed.
@@ -333,45 +467,64 @@ func (mt *MultiplexTransport) filterConn(c net.Conn) (err error) {
 	return nil
 }
 
+// dialUpgrade upgrades the connection to the dialed peer, with the Noise
+// handshake if its key is pinned. The key of a persistent peer is pinned once
+// connected, and unpinned if the Noise handshake fails, to fall back to the
+// STS handshake at the next attempt, e.g. if it was downgraded.
+func (mt *MultiplexTransport) dialUpgrade(
+	c net.Conn,
+	addr NetAddress,
+	cfg peerConfig,
+) (*conn.SecretConnection, NodeInfo, *ClockOffset, error) {
+	var pinnedKey crypto.PubKey
+	if mt.noiseHandshake {
+		mt.pinnedKeysMtx.Lock()
+		pinnedKey = mt.pinnedKeys[addr.ID]
+		mt.pinnedKeysMtx.Unlock()
+	}
+
+	secretConn, nodeInfo, clockOffset, err := mt.upgrade(c, &addr, pinnedKey)
+
+	mt.pinnedKeysMtx.Lock()
+	defer mt.pinnedKeysMtx.Unlock()
+	switch {
+	case err == nil && mt.noiseHandshake && cfg.persistent:
+		mt.pinnedKeys[addr.ID] = secretConn.RemotePubKey()
+	case err != nil && pinnedKey != nil:
+		if e, ok := err.(ErrRejected); ok && e.IsAuthFailure() {
+			delete(mt.pinnedKeys, addr.ID)
+		}
+	}
+	return secretConn, nodeInfo, clockOffset, err
+}
+
+// upgrade performs the handshakes on the connection. Outbound connections,
+// with dialedAddr set, use the Noise handshake if the remote key is pinned.
 func (mt *MultiplexTransport) upgrade(
 	c net.Conn,
 	dialedAddr *NetAddress,
-) (secretConn *conn.SecretConnection, nodeInfo NodeInfo, err error) {
+	pinnedKey crypto.PubKey,
+) (secretConn *conn.SecretConnection, nodeInfo NodeInfo, clockOffset *ClockOffset, err error) {
 	defer func() {
 		if err != nil {
 			_ = mt.cleanup(c)
 		}
 	}()
 
-	secretConn, err = upgradeSecretConn(c, mt.handshakeTimeout, mt.nodeKey.PrivKey)
+	secretConn, err = upgradeSecretConn(c, mt.handshakeTimeout, mt.nodeKey.PrivKey, dialedAddr == nil, pinnedKey)
 	if err != nil {
-		return nil, nil, ErrRejected{
+		return nil, nil, nil, ErrRejected{
 			conn:          c,
 			err:           fmt.Errorf("secrect conn failed: %v", err),
 			isAuthFailure: true,
 		}
 	}
 
-	// For outgoing conns, ensure connection key matches dialed key.
 	connID := PubKeyToID(secretConn.RemotePubKey())
-	if dialedAddr != nil {
-		if dialedID := dialedAddr.ID; connID != dialedID {
-			return nil, nil, ErrRejected{
-				conn: c,
-				id:   connID,
-				err: fmt.Errorf(
-					"conn.ID (%v) dialed ID (%v) missmatch",
-					connID,
-					dialedID,
-				),
-				isAuthFailure: true,
-			}
-		}
-	}
 
 	nodeInfo, err = handshake(secretConn, mt.handshakeTimeout, mt.nodeInfo)
 	if err != nil {
-		return nil, nil, ErrRejected{
+		return nil, nil, nil, ErrRejected{
 			conn:          c,
 			err:           fmt.Errorf("handshake failed: %v", err),
 			isAuthFailure: true,
@@ -379,16 +532,33 @@ func (mt *MultiplexTransport) upgrade(
 	}
 
 	if err := nodeInfo.Validate(); err != nil {
-		return nil, nil, ErrRejected{
+		return nil, nil, nil, ErrRejected{
 			conn:              c,
 			err:               err,
 			isNodeInfoInvalid: true,
 		}
 	}
 
+	// For outgoing conns, ensure connection key matches dialed key, or the
+	// node rotated its key from the dialed one.
+	if dialedAddr != nil {
+		if dialedID := dialedAddr.ID; connID != dialedID && !rotatedFrom(nodeInfo, dialedID) {
+			return nil, nil, nil, ErrRejected{
+				conn: c,
+				id:   connID,
+				err: fmt.Errorf(
+					"conn.ID (%v) dialed ID (%v) missmatch",
+					connID,
+					dialedID,
+				),
+				isAuthFailure: true,
+			}
+		}
+	}
+
 	// Ensure connection key matches self reported key.
 	if connID != nodeInfo.ID() {
-		return nil, nil, ErrRejected{
+		return nil, nil, nil, ErrRejected{
 			conn: c,
 			id:   connID,
 			err: fmt.Errorf(
@@ -400,9 +570,21 @@ func (mt *MultiplexTransport) upgrade(
 		}
 	}
 
+	// Ensure the peer is certified, in a permissioned network.
+	if len(mt.cas) > 0 {
+		if err := verifyCertificate(nodeInfo, mt.cas); err != nil {
+			return nil, nil, nil, ErrRejected{
+				conn:          c,
+				id:            connID,
+				err:           err,
+				isAuthFailure: true,
+			}
+		}
+	}
+
 	// Reject self.
 	if mt.nodeInfo.ID() == nodeInfo.ID() {
-		return nil, nil, ErrRejected{
+		return nil, nil, nil, ErrRejected{
 			addr:   *NewNetAddress(nodeInfo.ID(), c.RemoteAddr()),
 			conn:   c,
 			id:     nodeInfo.ID(),
@@ -411,7 +593,7 @@ func (mt *MultiplexTransport) upgrade(
 	}
 
 	if err := mt.nodeInfo.CompatibleWith(nodeInfo); err != nil {
-		return nil, nil, ErrRejected{
+		return nil, nil, nil, ErrRejected{
 			conn:           c,
 			err:            err,
 			id:             nodeInfo.ID(),
@@ -419,12 +601,27 @@ func (mt *MultiplexTransport) upgrade(
 		}
 	}
 
-	return secretConn, nodeInfo, nil
+	// Estimate the clock offset of the peer, if both nodes support it.
+	if ourInfo, ok := mt.nodeInfo.(DefaultNodeInfo); ok && ourInfo.ClockSync {
+		if info, ok := nodeInfo.(DefaultNodeInfo); ok && info.ClockSync {
+			clockOffset, err = syncClocks(secretConn, mt.handshakeTimeout)
+			if err != nil {
+				return nil, nil, nil, ErrRejected{
+					conn: c,
+					err:  fmt.Errorf("clock sync failed: %v", err),
+					id:   nodeInfo.ID(),
+				}
+			}
+		}
+	}
+
+	return secretConn, nodeInfo, clockOffset, nil
 }
 
 func (mt *MultiplexTransport) wrapPeer(
 	c net.Conn,
 	ni NodeInfo,
+	clockOffset *ClockOffset,
 	cfg peerConfig,
 	dialedAddr *NetAddress,
 ) Peer {
@@ -435,20 +632,47 @@ func (mt *MultiplexTransport) wrapPeer(
 		c,
 		dialedAddr,
 	)
+	peerConn.clockOffset = clockOffset
+
+	// Compress the channels negotiated with the peer.
+	mConfig := mt.mConfig
+	if info, ok := mt.nodeInfo.(DefaultNodeInfo); ok {
+		if other, ok := ni.(DefaultNodeInfo); ok {
+			mConfig.CompressedChannels = info.compressedChannelsWith(other)
+		}
+	}
 
 	p := newPeer(
 		peerConn,
-		mt.mConfig,
+		mConfig,
 		ni,
 		cfg.reactorsByCh,
 		cfg.chDescs,
 		cfg.onPeerError,
 		PeerMetrics(cfg.metrics),
+		PeerOnReceivePanic(cfg.onReceivePanic),
 	)
 
 	return p
 }
 
+// rotatedFrom returns whether the node announces the rotation of its key from
+// the one of oldID. The rotation is verified by NodeInfo.Validate.
+func rotatedFrom(nodeInfo NodeInfo, oldID ID) bool {
+	info, ok := nodeInfo.(DefaultNodeInfo)
+	return ok && info.KeyRotation != nil && info.KeyRotation.OldID() == oldID
+}
+
+// verifyCertificate returns an error if the node has no valid certificate
+// signed by one of the certificate authorities.
+func verifyCertificate(nodeInfo NodeInfo, cas []crypto.PubKey) error {
+	info, ok := nodeInfo.(DefaultNodeInfo)
+	if !ok || info.Certificate == nil {
+		return errors.New("peer has no certificate")
+	}
+	return info.Certificate.Verify(info.ID(), cas, time.Now())
+}
+
 func handshake(
 	c net.Conn,
 	timeout time.Duration,
@@ -464,6 +688,7 @@ func handshake(
 		peerNodeInfo DefaultNodeInfo
 		ourNodeInfo  = nodeInfo.(DefaultNodeInfo)
 	)
+	ourNodeInfo.Time = time.Now().UTC()
 
 	go func(errc chan<- error, c net.Conn) {
 		_, err := cdc.MarshalBinaryLengthPrefixedWriter(c, ourNodeInfo)
@@ -488,16 +713,32 @@ func handshake(
 	return peerNodeInfo, c.SetDeadline(time.Time{})
 }
 
+// upgradeSecretConn performs the secret connection handshake: the one chosen
+// by the remote peer if accept is set, else the Noise one if pinnedKey is set,
+// else the STS one.
 func upgradeSecretConn(
 	c net.Conn,
 	timeout time.Duration,
 	privKey crypto.PrivKey,
+	accept bool,
+	pinnedKey crypto.PubKey,
 ) (*conn.SecretConnection, error) {
 	if err := c.SetDeadline(time.Now().Add(timeout)); err != nil {
 		return nil, err
 	}
 
-	sc, err := conn.MakeSecretConnection(c, privKey)
+	var (
+		sc  *conn.SecretConnection
+		err error
+	)
+	switch {
+	case accept:
+		sc, err = conn.AcceptSecretConnection(c, privKey)
+	case pinnedKey != nil:
+		sc, err = conn.MakeNoiseSecretConnection(c, privKey, pinnedKey)
+	default:
+		sc, err = conn.MakeSecretConnection(c, privKey)
+	}
 	if err != nil {
 		return nil, err
 	}
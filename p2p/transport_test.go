@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/p2p/conn"
 )
@@ -251,7 +252,7 @@ func TestTransportMultiplexAcceptNonBlocking(t *testing.T) {
 			errc <- fmt.Errorf("Fast peer timed out")
 		}
 
-		sc, err := upgradeSecretConn(c, 20*time.Millisecond, ed25519.GenPrivKey())
+		sc, err := upgradeSecretConn(c, 20*time.Millisecond, ed25519.GenPrivKey(), false, nil)
 		if err != nil {
 			errc <- err
 			return
@@ -430,6 +431,286 @@ func TestTransportMultiplexDialRejectWrongID(t *testing.T) {
 	}
 }
 
+func TestTransportMultiplexDialRotatedID(t *testing.T) {
+	var (
+		oldKey = ed25519.GenPrivKey()
+		pv     = ed25519.GenPrivKey()
+		id     = PubKeyToID(pv.PubKey())
+	)
+	rot, err := NewKeyRotation(oldKey, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ni := testNodeInfo(id, "transport").(DefaultNodeInfo)
+	ni.KeyRotation = rot
+	mt := newMultiplexTransport(ni, NodeKey{PrivKey: pv})
+	listenAddr, err := NewNetAddressStringWithOptionalID(IDAddressString(id, "127.0.0.1:0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mt.Listen(*listenAddr); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			_, err := mt.Accept(peerConfig{})
+			if _, ok := err.(*ErrTransportClosed); ok {
+				return
+			}
+		}
+	}()
+	defer mt.Close()
+
+	pv2 := ed25519.GenPrivKey()
+	dialer := newMultiplexTransport(
+		testNodeInfo(PubKeyToID(pv2.PubKey()), "dialer"),
+		NodeKey{
+			PrivKey: pv2,
+		},
+	)
+
+	// The old ID is accepted for the new one.
+	addr, err := NewNetAddressStringWithOptionalID(IDAddressString(rot.OldID(), mt.listener.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := dialer.Dial(*addr, peerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = p.CloseConn()
+	if p.ID() != id {
+		t.Errorf("expected the peer to have the new ID %v, got %v", id, p.ID())
+	}
+}
+
+func TestTransportMultiplexDialClockSync(t *testing.T) {
+	var (
+		pv = ed25519.GenPrivKey()
+		id = PubKeyToID(pv.PubKey())
+		ni = testNodeInfo(id, "transport").(DefaultNodeInfo)
+	)
+	ni.ClockSync = true
+	mt := newMultiplexTransport(ni, NodeKey{PrivKey: pv})
+	listenAddr, err := NewNetAddressStringWithOptionalID(IDAddressString(id, "127.0.0.1:0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mt.Listen(*listenAddr); err != nil {
+		t.Fatal(err)
+	}
+	acceptc := make(chan Peer, 2)
+	go func() {
+		for {
+			p, err := mt.Accept(peerConfig{})
+			if _, ok := err.(*ErrTransportClosed); ok {
+				return
+			}
+			if err == nil {
+				acceptc <- p
+			}
+		}
+	}()
+	defer mt.Close()
+
+	addr, err := NewNetAddressStringWithOptionalID(IDAddressString(id, mt.listener.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dial := func(clockSync bool) (Peer, Peer) {
+		pv := ed25519.GenPrivKey()
+		ni := testNodeInfo(PubKeyToID(pv.PubKey()), "dialer").(DefaultNodeInfo)
+		ni.ClockSync = clockSync
+		dialer := newMultiplexTransport(ni, NodeKey{PrivKey: pv})
+		p, err := dialer.Dial(*addr, peerConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = p.CloseConn()
+		return p, <-acceptc
+	}
+
+	// Both nodes sync the clocks.
+	dialed, accepted := dial(true)
+	for _, p := range []Peer{dialed, accepted} {
+		if offset := p.ClockOffset(); offset == nil {
+			t.Errorf("expected the clock offset of %v", p)
+		} else if offset.Offset > offset.RTT/2+time.Millisecond || offset.Offset < -offset.RTT/2-time.Millisecond {
+			t.Errorf("expected a clock offset within half of the RTT, got %+v", offset)
+		}
+	}
+
+	// The dialer does not, e.g. it is an older node.
+	dialed, accepted = dial(false)
+	for _, p := range []Peer{dialed, accepted} {
+		if offset := p.ClockOffset(); offset != nil {
+			t.Errorf("expected no clock offset of %v, got %+v", p, offset)
+		}
+	}
+}
+
+func TestTransportMultiplexDialNoiseHandshake(t *testing.T) {
+	mt := testSetupMultiplexTransport(t)
+	go func() {
+		for {
+			_, err := mt.Accept(peerConfig{})
+			if _, ok := err.(*ErrTransportClosed); ok {
+				return
+			}
+		}
+	}()
+	defer mt.Close()
+
+	var (
+		pv     = ed25519.GenPrivKey()
+		dialer = newMultiplexTransport(
+			testNodeInfo(PubKeyToID(pv.PubKey()), "dialer"),
+			NodeKey{
+				PrivKey: pv,
+			},
+		)
+	)
+	MultiplexTransportNoiseHandshake(true)(dialer)
+
+	addr, err := NewNetAddressStringWithOptionalID(IDAddressString(mt.nodeKey.ID(), mt.listener.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pinnedKey := func() crypto.PubKey {
+		dialer.pinnedKeysMtx.Lock()
+		defer dialer.pinnedKeysMtx.Unlock()
+		return dialer.pinnedKeys[addr.ID]
+	}
+
+	// The first connection pins the key, the second one uses it.
+	for i := 0; i < 2; i++ {
+		p, err := dialer.Dial(*addr, peerConfig{persistent: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = p.CloseConn()
+
+		if key := pinnedKey(); key == nil || !key.Equals(mt.nodeKey.PubKey()) {
+			t.Fatalf("expected the key of the peer to be pinned, got %v", key)
+		}
+	}
+
+	// A wrong pinned key fails the handshake, and is unpinned.
+	dialer.pinnedKeysMtx.Lock()
+	dialer.pinnedKeys[addr.ID] = ed25519.GenPrivKey().PubKey()
+	dialer.pinnedKeysMtx.Unlock()
+	if _, err := dialer.Dial(*addr, peerConfig{persistent: true}); err == nil {
+		t.Fatal("expected the Noise handshake to fail")
+	}
+	if key := pinnedKey(); key != nil {
+		t.Errorf("expected the key to be unpinned, got %v", key)
+	}
+}
+
+func TestTransportMultiplexDialNodeInfo(t *testing.T) {
+	mt := testSetupMultiplexTransport(t)
+	go func() {
+		for {
+			_, err := mt.Accept(peerConfig{})
+			if _, ok := err.(*ErrTransportClosed); ok {
+				return
+			}
+		}
+	}()
+	defer mt.Close()
+
+	var (
+		pv     = ed25519.GenPrivKey()
+		dialer = newMultiplexTransport(
+			testNodeInfo(PubKeyToID(pv.PubKey()), "dialer"),
+			NodeKey{
+				PrivKey: pv,
+			},
+		)
+	)
+
+	addr, err := NewNetAddressStringWithOptionalID(IDAddressString(mt.nodeKey.ID(), mt.listener.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ni, err := dialer.DialNodeInfo(*addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := ni.ID(), mt.nodeKey.ID(); have != want {
+		t.Errorf("have %v, want %v", have, want)
+	}
+
+	wrongID := PubKeyToID(ed25519.GenPrivKey().PubKey())
+	addr, err = NewNetAddressStringWithOptionalID(IDAddressString(wrongID, mt.listener.Addr().String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dialer.DialNodeInfo(*addr); err == nil {
+		t.Error("expected the dial of the wrong ID to fail")
+	}
+}
+
+func TestTransportMultiplexMemNetwork(t *testing.T) {
+	var (
+		network         = NewMemNetwork()
+		newMemTransport = func(name string) *MultiplexTransport {
+			pv := ed25519.GenPrivKey()
+			mt := newMultiplexTransport(
+				testNodeInfo(PubKeyToID(pv.PubKey()), name),
+				NodeKey{
+					PrivKey: pv,
+				},
+			)
+			MultiplexTransportDialer(network)(mt)
+			MultiplexTransportListen(network.Listen)(mt)
+			return mt
+		}
+		mt     = newMemTransport("transport")
+		dialer = newMemTransport("dialer")
+	)
+
+	addr, err := NewNetAddressStringWithOptionalID(IDAddressString(mt.nodeKey.ID(), "127.0.0.1:26656"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dialer.Dial(*addr, peerConfig{}); err == nil {
+		t.Fatal("expected the dial to fail before the transport listens")
+	}
+
+	if err := mt.Listen(*addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := dialer.Listen(*addr); err == nil {
+		t.Fatal("expected the address to be in use")
+	}
+
+	errc := make(chan error)
+	go func() {
+		_, err := dialer.Dial(*addr, peerConfig{})
+		errc <- err
+	}()
+
+	p, err := mt.Accept(peerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if have, want := p.NodeInfo().ID(), dialer.nodeKey.ID(); have != want {
+		t.Errorf("have %v, want %v", have, want)
+	}
+
+	// the address is released once closed
+	if err := mt.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dialer.Dial(*addr, peerConfig{}); err == nil {
+		t.Error("expected the dial to fail once the transport is closed")
+	}
+}
+
 func TestTransportMultiplexRejectIncompatible(t *testing.T) {
 	mt := testSetupMultiplexTransport(t)
 
@@ -470,6 +751,62 @@ func TestTransportMultiplexRejectIncompatible(t *testing.T) {
 	}
 }
 
+func TestTransportMultiplexRejectUncertified(t *testing.T) {
+	mt := testSetupMultiplexTransport(t)
+	caKey := ed25519.GenPrivKey()
+	MultiplexTransportCertificateAuthorities([]crypto.PubKey{caKey.PubKey()})(mt)
+
+	dial := func(cert *PeerCertificate, pv crypto.PrivKey) {
+		info := testNodeInfo(PubKeyToID(pv.PubKey()), "dialer").(DefaultNodeInfo)
+		info.Certificate = cert
+		dialer := newMultiplexTransport(info, NodeKey{PrivKey: pv})
+
+		addr, err := NewNetAddressStringWithOptionalID(IDAddressString(mt.nodeKey.ID(), mt.listener.Addr().String()))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, _ = dialer.Dial(*addr, peerConfig{})
+	}
+
+	// Without a certificate, then with one of another CA.
+	for _, otherCA := range []crypto.PrivKey{nil, ed25519.GenPrivKey()} {
+		pv := ed25519.GenPrivKey()
+		var cert *PeerCertificate
+		if otherCA != nil {
+			var err error
+			cert, err = NewPeerCertificate(PubKeyToID(pv.PubKey()), "org", time.Time{}, otherCA)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		go dial(cert, pv)
+
+		_, err := mt.Accept(peerConfig{})
+		if err, ok := err.(ErrRejected); ok {
+			if !err.IsAuthFailure() {
+				t.Errorf("expected to reject uncertified peer, got: %v", err)
+			}
+		} else {
+			t.Errorf("expected ErrRejected, got %v", err)
+		}
+	}
+
+	// With a certificate of the CA.
+	pv := ed25519.GenPrivKey()
+	cert, err := NewPeerCertificate(PubKeyToID(pv.PubKey()), "org", time.Now().Add(time.Hour), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go dial(cert, pv)
+
+	p, err := mt.Accept(peerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = p.CloseConn()
+}
+
 func TestTransportMultiplexRejectSelf(t *testing.T) {
 	mt := testSetupMultiplexTransport(t)
 
@@ -573,6 +910,9 @@ func TestTransportHandshake(t *testing.T) {
 			if err != nil {
 				t.Error(err)
 			}
+			if ni.Time.IsZero() {
+				t.Error("expected the time of the handshake")
+			}
 		}(c)
 	}()
 
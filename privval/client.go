@@ -36,6 +36,11 @@ func SocketValHeartbeat(period time.Duration) SocketValOption {
 	return func(sc *SocketVal) { sc.connHeartbeat = period }
 }
 
+// SocketValMetrics sets the metrics.
+func SocketValMetrics(metrics *Metrics) SocketValOption {
+	return func(sc *SocketVal) { sc.metrics = metrics }
+}
+
 // SocketVal implements PrivValidator.
 // It listens for an external process to dial in and uses
 // the socket to request signatures.
@@ -59,19 +64,30 @@ type SocketVal struct {
 	// are already gorountine safe.
 	mtx    sync.Mutex
 	signer *RemoteSignerClient
+
+	metrics *Metrics
 }
 
-// Check that SocketVal implements PrivValidator.
-var _ types.PrivValidator = (*SocketVal)(nil)
+// Check that SocketVal implements PrivValidator and SigningLock.
+var (
+	_ types.PrivValidator = (*SocketVal)(nil)
+	_ SigningLock         = (*SocketVal)(nil)
+	_ SignStateSyncer     = (*SocketVal)(nil)
+)
 
 // NewSocketVal returns an instance of SocketVal.
 func NewSocketVal(
 	logger log.Logger,
 	listener net.Listener,
+	options ...SocketValOption,
 ) *SocketVal {
 	sc := &SocketVal{
 		listener:      listener,
 		connHeartbeat: connHeartbeat,
+		metrics:       NopMetrics(),
+	}
+	for _, option := range options {
+		option(sc)
 	}
 
 	sc.BaseService = *cmn.NewBaseService(logger, "SocketVal", sc)
@@ -90,17 +106,55 @@ func (sc *SocketVal) GetPubKey() crypto.PubKey {
 }
 
 // SignVote implements PrivValidator.
-func (sc *SocketVal) SignVote(chainID string, vote *types.Vote) error {
+func (sc *SocketVal) SignVote(chainID string, domain types.SignDomain, vote *types.Vote) error {
 	sc.mtx.Lock()
 	defer sc.mtx.Unlock()
-	return sc.signer.SignVote(chainID, vote)
+	return sc.observeSign(msgTypeLabel(vote.Type), func() error {
+		return sc.signer.SignVote(chainID, domain, vote)
+	})
 }
 
 // SignProposal implements PrivValidator.
-func (sc *SocketVal) SignProposal(chainID string, proposal *types.Proposal) error {
+func (sc *SocketVal) SignProposal(chainID string, domain types.SignDomain, proposal *types.Proposal) error {
 	sc.mtx.Lock()
 	defer sc.mtx.Unlock()
-	return sc.signer.SignProposal(chainID, proposal)
+	return sc.observeSign(msgTypeLabel(proposal.Type), func() error {
+		return sc.signer.SignProposal(chainID, domain, proposal)
+	})
+}
+
+// observeSign records the round-trip time of the signing request, and its
+// failure if any, labeled with the type of the message signed.
+func (sc *SocketVal) observeSign(msgType string, sign func() error) error {
+	start := time.Now()
+	err := sign()
+	sc.metrics.SignLatency.With("msg_type", msgType).Observe(
+		float64(time.Since(start)) / float64(time.Millisecond))
+	if err != nil {
+		sc.metrics.SignFailures.With("msg_type", msgType).Add(1)
+	}
+	return err
+}
+
+// isConnDrop returns whether the error of a request to the remote signer
+// reports that the connection was dropped, rather than the request timing
+// out or being answered unexpectedly.
+func isConnDrop(err error) bool {
+	return err != nil && err != ErrUnexpectedResponse && !IsConnTimeout(err)
+}
+
+// msgTypeLabel returns the msg_type label of the metrics of a signed message.
+func msgTypeLabel(t types.SignedMsgType) string {
+	switch t {
+	case types.ProposalType:
+		return "proposal"
+	case types.PrevoteType:
+		return "prevote"
+	case types.PrecommitType:
+		return "precommit"
+	default:
+		return "unknown"
+	}
 }
 
 //--------------------------------------------------------
@@ -130,6 +184,37 @@ func (sc *SocketVal) Close() {
 	}
 }
 
+// Lock implements SigningLock by acquiring or renewing the lease of the
+// remote signer.
+func (sc *SocketVal) Lock(holder string, lease time.Duration) error {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	return sc.signer.Lock(holder, lease)
+}
+
+// Unlock implements SigningLock by releasing the lease of the remote signer.
+func (sc *SocketVal) Unlock(holder string) error {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	return sc.signer.Unlock(holder)
+}
+
+// ExportSignState implements SignStateSyncer by exporting the sign state of
+// the remote signer.
+func (sc *SocketVal) ExportSignState(freeze bool) (FilePVLastSignState, error) {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	return sc.signer.ExportSignState(freeze)
+}
+
+// ImportSignState implements SignStateSyncer by importing the sign state into
+// the remote signer.
+func (sc *SocketVal) ImportSignState(state FilePVLastSignState) error {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	return sc.signer.ImportSignState(state)
+}
+
 //--------------------------------------------------------
 // Service start and stop
 
@@ -152,6 +237,9 @@ func (sc *SocketVal) OnStart() error {
 				err := sc.Ping()
 				if err != nil {
 					sc.Logger.Error("Ping", "err", err)
+					if isConnDrop(err) {
+						sc.metrics.ConnectionDrops.Add(1)
+					}
 					if err == ErrUnexpectedResponse {
 						return
 					}
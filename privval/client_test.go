@@ -101,8 +101,8 @@ func TestSocketPVProposal(t *testing.T) {
 			defer sc.Stop()
 			defer rs.Stop()
 
-			require.NoError(t, rs.privVal.SignProposal(chainID, privProposal))
-			require.NoError(t, sc.SignProposal(chainID, clientProposal))
+			require.NoError(t, rs.privVal.SignProposal(chainID, types.SignDomain{}, privProposal))
+			require.NoError(t, sc.SignProposal(chainID, types.SignDomain{}, clientProposal))
 			assert.Equal(t, privProposal.Signature, clientProposal.Signature)
 		}()
 	}
@@ -123,8 +123,8 @@ func TestSocketPVVote(t *testing.T) {
 			defer sc.Stop()
 			defer rs.Stop()
 
-			require.NoError(t, rs.privVal.SignVote(chainID, want))
-			require.NoError(t, sc.SignVote(chainID, have))
+			require.NoError(t, rs.privVal.SignVote(chainID, types.SignDomain{}, want))
+			require.NoError(t, sc.SignVote(chainID, types.SignDomain{}, have))
 			assert.Equal(t, want.Signature, have.Signature)
 		}()
 	}
@@ -147,15 +147,15 @@ func TestSocketPVVoteResetDeadline(t *testing.T) {
 
 			time.Sleep(testConnDeadline2o3)
 
-			require.NoError(t, rs.privVal.SignVote(chainID, want))
-			require.NoError(t, sc.SignVote(chainID, have))
+			require.NoError(t, rs.privVal.SignVote(chainID, types.SignDomain{}, want))
+			require.NoError(t, sc.SignVote(chainID, types.SignDomain{}, have))
 			assert.Equal(t, want.Signature, have.Signature)
 
 			// This would exceed the deadline if it was not extended by the previous message
 			time.Sleep(testConnDeadline2o3)
 
-			require.NoError(t, rs.privVal.SignVote(chainID, want))
-			require.NoError(t, sc.SignVote(chainID, have))
+			require.NoError(t, rs.privVal.SignVote(chainID, types.SignDomain{}, want))
+			require.NoError(t, sc.SignVote(chainID, types.SignDomain{}, have))
 			assert.Equal(t, want.Signature, have.Signature)
 		}()
 	}
@@ -178,8 +178,8 @@ func TestSocketPVVoteKeepalive(t *testing.T) {
 
 			time.Sleep(testConnDeadline * 2)
 
-			require.NoError(t, rs.privVal.SignVote(chainID, want))
-			require.NoError(t, sc.SignVote(chainID, have))
+			require.NoError(t, rs.privVal.SignVote(chainID, types.SignDomain{}, want))
+			require.NoError(t, sc.SignVote(chainID, types.SignDomain{}, have))
 			assert.Equal(t, want.Signature, have.Signature)
 		}()
 	}
@@ -231,12 +231,12 @@ func TestRemoteSignVoteErrors(t *testing.T) {
 			defer sc.Stop()
 			defer rs.Stop()
 
-			err := sc.SignVote("", vote)
+			err := sc.SignVote("", types.SignDomain{}, vote)
 			require.Equal(t, err.(*RemoteSignerError).Description, types.ErroringMockPVErr.Error())
 
-			err = rs.privVal.SignVote(chainID, vote)
+			err = rs.privVal.SignVote(chainID, types.SignDomain{}, vote)
 			require.Error(t, err)
-			err = sc.SignVote(chainID, vote)
+			err = sc.SignVote(chainID, types.SignDomain{}, vote)
 			require.Error(t, err)
 		}()
 	}
@@ -255,13 +255,13 @@ func TestRemoteSignProposalErrors(t *testing.T) {
 			defer sc.Stop()
 			defer rs.Stop()
 
-			err := sc.SignProposal("", proposal)
+			err := sc.SignProposal("", types.SignDomain{}, proposal)
 			require.Equal(t, err.(*RemoteSignerError).Description, types.ErroringMockPVErr.Error())
 
-			err = rs.privVal.SignProposal(chainID, proposal)
+			err = rs.privVal.SignProposal(chainID, types.SignDomain{}, proposal)
 			require.Error(t, err)
 
-			err = sc.SignProposal(chainID, proposal)
+			err = sc.SignProposal(chainID, types.SignDomain{}, proposal)
 			require.Error(t, err)
 		}()
 	}
@@ -301,7 +301,7 @@ func TestErrUnexpectedResponse(t *testing.T) {
 
 			// Proposal:
 			go func(errc chan error) {
-				errc <- sc.SignProposal(chainID, &types.Proposal{})
+				errc <- sc.SignProposal(chainID, types.SignDomain{}, &types.Proposal{})
 			}(errc)
 			// read request and write wrong response:
 			go testReadWriteResponse(t, &SignedVoteResponse{}, rsConn)
@@ -311,7 +311,7 @@ func TestErrUnexpectedResponse(t *testing.T) {
 
 			// Vote:
 			go func(errc chan error) {
-				errc <- sc.SignVote(chainID, &types.Vote{})
+				errc <- sc.SignVote(chainID, types.SignDomain{}, &types.Vote{})
 			}(errc)
 			// read request and write wrong response:
 			go testReadWriteResponse(t, &SignedProposalResponse{}, rsConn)
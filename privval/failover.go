@@ -0,0 +1,255 @@
+package privval
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tendermint/tendermint/crypto"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	defaultFailoverTimeout = 10 * time.Second
+)
+
+// FailoverPVOption sets an optional parameter on the FailoverPV.
+type FailoverPVOption func(*FailoverPV)
+
+// FailoverPVTimeout sets how long the active signer must be unhealthy before
+// failing over to another one.
+func FailoverPVTimeout(timeout time.Duration) FailoverPVOption {
+	return func(pv *FailoverPV) { pv.timeout = timeout }
+}
+
+// FailoverPVHealthCheckInterval sets the period on which to check the
+// health of the signers.
+func FailoverPVHealthCheckInterval(interval time.Duration) FailoverPVOption {
+	return func(pv *FailoverPV) { pv.checkInterval = interval }
+}
+
+// FailoverPV implements PrivValidator with several remote signers (e.g. a
+// primary HSM and a backup one), in priority order.
+//
+// It signs with one active signer at a time, initially the first which
+// connects. The signers are pinged periodically and, once the active one has
+// been unhealthy for the failover timeout, the first healthy signer becomes
+// active. It doesn't fail back to a higher priority signer while the active
+// one is healthy, so that only one signer signs at a time.
+//
+// The timeout should be longer than the lease of the signing lock, if any, so
+// that the lease of the failed signer expires before another one signs.
+type FailoverPV struct {
+	cmn.BaseService
+
+	newEndpoints  []SocketValFactory
+	timeout       time.Duration
+	checkInterval time.Duration
+
+	mtx         sync.Mutex
+	endpoints   []*SocketVal // by endpoint, nil if not created yet or stopped
+	active      int
+	pubKey      crypto.PubKey
+	lastHealthy []time.Time // by endpoint, zero if never healthy
+}
+
+// SocketValFactory creates the SocketVal of a remote signer, listening for it
+// anew. A stopped SocketVal can't be started again, so a new one is created
+// each time the signer is restarted.
+type SocketValFactory func() (*SocketVal, error)
+
+// Check that FailoverPV implements PrivValidator and SigningLock.
+var (
+	_ types.PrivValidator = (*FailoverPV)(nil)
+	_ SigningLock         = (*FailoverPV)(nil)
+)
+
+// NewFailoverPV returns a FailoverPV using the SocketVals created by the given
+// factories, in priority order, which it starts and stops.
+func NewFailoverPV(newEndpoints []SocketValFactory, options ...FailoverPVOption) *FailoverPV {
+	pv := &FailoverPV{
+		newEndpoints:  newEndpoints,
+		timeout:       defaultFailoverTimeout,
+		checkInterval: connHeartbeat,
+		endpoints:     make([]*SocketVal, len(newEndpoints)),
+		lastHealthy:   make([]time.Time, len(newEndpoints)),
+	}
+	pv.BaseService = *cmn.NewBaseService(nil, "FailoverPV", pv)
+
+	for _, option := range options {
+		option(pv)
+	}
+
+	return pv
+}
+
+// OnStart implements cmn.Service by starting the signers in priority order
+// until one connects, which becomes active. The others are started by the
+// health checks.
+func (pv *FailoverPV) OnStart() error {
+	if len(pv.newEndpoints) == 0 {
+		return errors.New("no remote signer")
+	}
+
+	pv.active = -1
+	for i := range pv.newEndpoints {
+		if err := pv.startEndpoint(i); err != nil {
+			pv.Logger.Error("Failed to start remote signer", "endpoint", i, "err", err)
+			continue
+		}
+		pv.active = i
+		break
+	}
+	if pv.active < 0 {
+		return errors.New("no remote signer connected")
+	}
+
+	for i := range pv.newEndpoints {
+		go pv.checkRoutine(i)
+	}
+	return nil
+}
+
+// OnStop implements cmn.Service by stopping the signers.
+func (pv *FailoverPV) OnStop() {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	for _, ep := range pv.endpoints {
+		if ep != nil && ep.IsRunning() {
+			ep.Stop()
+		}
+	}
+}
+
+// endpoint returns the signer at index i, nil if not created yet or stopped.
+func (pv *FailoverPV) endpoint(i int) *SocketVal {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	return pv.endpoints[i]
+}
+
+// startEndpoint starts the signer at index i, creating it if it was not
+// created yet or was stopped, and checks it has the same key as the others.
+func (pv *FailoverPV) startEndpoint(i int) error {
+	ep := pv.endpoint(i)
+	if ep == nil {
+		var err error
+		if ep, err = pv.newEndpoints[i](); err != nil {
+			return err
+		}
+		pv.mtx.Lock()
+		pv.endpoints[i] = ep
+		pv.mtx.Unlock()
+	}
+	if err := ep.Start(); err != nil {
+		return err
+	}
+
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	pubKey := ep.GetPubKey()
+	if pv.pubKey == nil {
+		pv.pubKey = pubKey
+	} else if !bytes.Equal(pubKey.Bytes(), pv.pubKey.Bytes()) {
+		// the stopped signer is created again on the next health check
+		ep.Stop()
+		pv.endpoints[i] = nil
+		return fmt.Errorf("remote signer has public key %v, expected %v", pubKey, pv.pubKey)
+	}
+	pv.lastHealthy[i] = time.Now()
+	return nil
+}
+
+// checkRoutine checks the health of the signer at index i periodically,
+// failing over if it is the active one and it has been unhealthy for too
+// long.
+func (pv *FailoverPV) checkRoutine(i int) {
+	ticker := time.NewTicker(pv.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			var err error
+			if ep := pv.endpoint(i); ep == nil || !ep.IsRunning() {
+				err = pv.startEndpoint(i)
+			} else if err = ep.Ping(); err == nil {
+				pv.mtx.Lock()
+				pv.lastHealthy[i] = time.Now()
+				pv.mtx.Unlock()
+			}
+			if err != nil {
+				pv.Logger.Debug("Remote signer is unhealthy", "endpoint", i, "err", err)
+			}
+			pv.failover()
+		case <-pv.Quit():
+			return
+		}
+	}
+}
+
+// failover makes the first healthy signer active if the active one has been
+// unhealthy for the failover timeout.
+func (pv *FailoverPV) failover() {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+
+	now := time.Now()
+	healthy := func(i int) bool {
+		return now.Sub(pv.lastHealthy[i]) <= 2*pv.checkInterval
+	}
+	if now.Sub(pv.lastHealthy[pv.active]) < pv.timeout {
+		return
+	}
+	for i := range pv.newEndpoints {
+		if i != pv.active && healthy(i) {
+			pv.Logger.Error("Failing over to another remote signer", "from", pv.active, "to", i)
+			pv.active = i
+			return
+		}
+	}
+}
+
+// Active returns the index of the active signer.
+func (pv *FailoverPV) Active() int {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	return pv.active
+}
+
+func (pv *FailoverPV) activeEndpoint() *SocketVal {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	return pv.endpoints[pv.active]
+}
+
+// GetPubKey implements PrivValidator.
+func (pv *FailoverPV) GetPubKey() crypto.PubKey {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	return pv.pubKey
+}
+
+// SignVote implements PrivValidator.
+func (pv *FailoverPV) SignVote(chainID string, domain types.SignDomain, vote *types.Vote) error {
+	return pv.activeEndpoint().SignVote(chainID, domain, vote)
+}
+
+// SignProposal implements PrivValidator.
+func (pv *FailoverPV) SignProposal(chainID string, domain types.SignDomain, proposal *types.Proposal) error {
+	return pv.activeEndpoint().SignProposal(chainID, domain, proposal)
+}
+
+// Lock implements SigningLock by acquiring or renewing the lease of the
+// active signer.
+func (pv *FailoverPV) Lock(holder string, lease time.Duration) error {
+	return pv.activeEndpoint().Lock(holder, lease)
+}
+
+// Unlock implements SigningLock by releasing the lease of the active signer.
+func (pv *FailoverPV) Unlock(holder string) error {
+	return pv.activeEndpoint().Unlock(holder)
+}
@@ -0,0 +1,130 @@
+package privval
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestFailoverPV(t *testing.T) {
+	var (
+		logger  = log.TestingLogger()
+		chainID = cmn.RandStr(12)
+		privVal = types.NewMockPV()
+
+		newEndpoints []SocketValFactory
+		signers      []*RemoteSigner
+	)
+	for i := 0; i < 2; i++ {
+		unixFilePath, err := testUnixAddr()
+		require.NoError(t, err)
+		newEndpoints = append(newEndpoints, newTestSocketValFactory(logger, unixFilePath))
+
+		rs := NewRemoteSigner(logger, chainID, privVal, DialUnixFn(unixFilePath))
+		RemoteSignerConnDeadline(testConnDeadline)(rs)
+		RemoteSignerConnRetries(1e6)(rs)
+		require.NoError(t, rs.Start())
+		signers = append(signers, rs)
+	}
+	defer signers[1].Stop()
+
+	pv := NewFailoverPV(newEndpoints,
+		FailoverPVTimeout(50*time.Millisecond),
+		FailoverPVHealthCheckInterval(testHeartbeatTimeout),
+	)
+	pv.SetLogger(logger)
+	require.NoError(t, pv.Start())
+	defer pv.Stop()
+
+	assert.Equal(t, 0, pv.Active())
+	assert.Equal(t, privVal.GetPubKey(), pv.GetPubKey())
+	require.NoError(t, pv.SignVote(chainID, types.SignDomain{}, &types.Vote{Type: types.PrevoteType}))
+
+	// The backup signer is started by the health checks.
+	waitFor(t, func() bool { return pv.endpoint(1) != nil && pv.endpoint(1).IsRunning() })
+	assert.Equal(t, 0, pv.Active(), "no failover while the primary signer is healthy")
+
+	// The primary signer fails.
+	require.NoError(t, signers[0].Stop())
+	waitFor(t, func() bool { return pv.Active() == 1 })
+	assert.NoError(t, pv.SignVote(chainID, types.SignDomain{}, &types.Vote{Type: types.PrecommitType}))
+}
+
+func TestFailoverPVRestartsEndpoint(t *testing.T) {
+	var (
+		logger  = log.TestingLogger()
+		chainID = cmn.RandStr(12)
+		privVal = types.NewMockPV()
+	)
+	unixFilePaths := make([]string, 2)
+	newEndpoints := make([]SocketValFactory, 2)
+	for i := range unixFilePaths {
+		var err error
+		unixFilePaths[i], err = testUnixAddr()
+		require.NoError(t, err)
+		newEndpoints[i] = newTestSocketValFactory(logger, unixFilePaths[i])
+	}
+	var created int32
+	newEndpoint := newEndpoints[1]
+	newEndpoints[1] = func() (*SocketVal, error) {
+		atomic.AddInt32(&created, 1)
+		return newEndpoint()
+	}
+	newSigner := func(privVal types.PrivValidator, unixFilePath string) *RemoteSigner {
+		rs := NewRemoteSigner(logger, chainID, privVal, DialUnixFn(unixFilePath))
+		RemoteSignerConnDeadline(testConnDeadline)(rs)
+		RemoteSignerConnRetries(1e6)(rs)
+		require.NoError(t, rs.Start())
+		return rs
+	}
+
+	primary := newSigner(privVal, unixFilePaths[0])
+	defer primary.Stop()
+	// The backup signer has another key at first.
+	wrong := newSigner(types.NewMockPV(), unixFilePaths[1])
+
+	pv := NewFailoverPV(newEndpoints,
+		FailoverPVTimeout(50*time.Millisecond),
+		FailoverPVHealthCheckInterval(testHeartbeatTimeout),
+	)
+	pv.SetLogger(logger)
+	require.NoError(t, pv.Start())
+	defer pv.Stop()
+
+	// The backup signer is stopped on the key mismatch, and created again.
+	waitFor(t, func() bool { return atomic.LoadInt32(&created) >= 2 })
+	require.NoError(t, wrong.Stop())
+	backup := newSigner(privVal, unixFilePaths[1])
+	defer backup.Stop()
+	waitFor(t, func() bool { return pv.endpoint(1) != nil && pv.endpoint(1).IsRunning() })
+
+	require.NoError(t, primary.Stop())
+	waitFor(t, func() bool { return pv.Active() == 1 })
+	assert.NoError(t, pv.SignVote(chainID, types.SignDomain{}, &types.Vote{Type: types.PrevoteType}))
+}
+
+// newTestSocketValFactory returns a SocketValFactory listening at the unix
+// socket.
+func newTestSocketValFactory(logger log.Logger, unixFilePath string) SocketValFactory {
+	return func() (*SocketVal, error) {
+		sc := newSocketVal(logger, fmt.Sprintf("unix://%s", unixFilePath), testConnDeadline)
+		SocketValHeartbeat(testHeartbeatTimeout)(sc)
+		return sc, nil
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	for start := time.Now(); !cond(); time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > 10*time.Second {
+			t.Fatal("timed out waiting for the condition")
+		}
+	}
+}
@@ -72,6 +72,9 @@ type FilePVLastSignState struct {
 	Step      int8         `json:"step"`
 	Signature []byte       `json:"signature,omitempty"`
 	SignBytes cmn.HexBytes `json:"signbytes,omitempty"`
+	// Frozen is true once the state was exported to another signer, after
+	// which signing is refused.
+	Frozen bool `json:"frozen,omitempty"`
 
 	filePath string
 }
@@ -139,6 +142,9 @@ type FilePV struct {
 	LastSignState FilePVLastSignState
 }
 
+// Check that FilePV implements SignStateSyncer.
+var _ SignStateSyncer = (*FilePV)(nil)
+
 // GenFilePV generates a new validator with randomly generated private key
 // and sets the filePaths, but does not call Save().
 func GenFilePV(keyFilePath, stateFilePath string) *FilePV {
@@ -234,18 +240,24 @@ func (pv *FilePV) GetPubKey() crypto.PubKey {
 }
 
 // SignVote signs a canonical representation of the vote, along with the
-// chainID. Implements PrivValidator.
-func (pv *FilePV) SignVote(chainID string, vote *types.Vote) error {
-	if err := pv.signVote(chainID, vote); err != nil {
+// chainID, in the sign domain of the chain. Implements PrivValidator.
+func (pv *FilePV) SignVote(chainID string, domain types.SignDomain, vote *types.Vote) error {
+	if pv.LastSignState.Frozen {
+		return ErrSignStateExported
+	}
+	if err := pv.signVote(chainID, domain, vote); err != nil {
 		return fmt.Errorf("Error signing vote: %v", err)
 	}
 	return nil
 }
 
 // SignProposal signs a canonical representation of the proposal, along with
-// the chainID. Implements PrivValidator.
-func (pv *FilePV) SignProposal(chainID string, proposal *types.Proposal) error {
-	if err := pv.signProposal(chainID, proposal); err != nil {
+// the chainID, in the sign domain of the chain. Implements PrivValidator.
+func (pv *FilePV) SignProposal(chainID string, domain types.SignDomain, proposal *types.Proposal) error {
+	if pv.LastSignState.Frozen {
+		return ErrSignStateExported
+	}
+	if err := pv.signProposal(chainID, domain, proposal); err != nil {
 		return fmt.Errorf("Error signing proposal: %v", err)
 	}
 	return nil
@@ -266,9 +278,42 @@ func (pv *FilePV) Reset() {
 	pv.LastSignState.Step = 0
 	pv.LastSignState.Signature = sig
 	pv.LastSignState.SignBytes = nil
+	pv.LastSignState.Frozen = false
 	pv.Save()
 }
 
+// ExportSignState returns the last sign state. If freeze is true, the FilePV
+// refuses to sign afterwards, including after a restart, as the frozen flag
+// is saved with the state. Implements SignStateSyncer.
+func (pv *FilePV) ExportSignState(freeze bool) (FilePVLastSignState, error) {
+	if freeze && !pv.LastSignState.Frozen {
+		pv.LastSignState.Frozen = true
+		pv.LastSignState.Save()
+	}
+	state := pv.LastSignState
+	state.Frozen = false
+	state.filePath = ""
+	return state, nil
+}
+
+// ImportSignState sets and saves the last sign state, unless it is behind
+// the current one. It doesn't unfreeze the FilePV. Implements
+// SignStateSyncer.
+func (pv *FilePV) ImportSignState(state FilePVLastSignState) error {
+	lss := &pv.LastSignState
+	if compareHRS(state.Height, state.Round, state.Step, lss.Height, lss.Round, lss.Step) < 0 {
+		return fmt.Errorf("Sign state regression. Got %v/%v/%v, last sign state %v/%v/%v",
+			state.Height, state.Round, state.Step, lss.Height, lss.Round, lss.Step)
+	}
+	lss.Height = state.Height
+	lss.Round = state.Round
+	lss.Step = state.Step
+	lss.Signature = state.Signature
+	lss.SignBytes = state.SignBytes
+	lss.Save()
+	return nil
+}
+
 // String returns a string representation of the FilePV.
 func (pv *FilePV) String() string {
 	return fmt.Sprintf("PrivValidator{%v LH:%v, LR:%v, LS:%v}", pv.GetAddress(), pv.LastSignState.Height, pv.LastSignState.Round, pv.LastSignState.Step)
@@ -279,7 +324,7 @@ func (pv *FilePV) String() string {
 // signVote checks if the vote is good to sign and sets the vote signature.
 // It may need to set the timestamp as well if the vote is otherwise the same as
 // a previously signed vote (ie. we crashed after signing but before the vote hit the WAL).
-func (pv *FilePV) signVote(chainID string, vote *types.Vote) error {
+func (pv *FilePV) signVote(chainID string, domain types.SignDomain, vote *types.Vote) error {
 	height, round, step := vote.Height, vote.Round, voteToStep(vote)
 
 	lss := pv.LastSignState
@@ -289,7 +334,7 @@ func (pv *FilePV) signVote(chainID string, vote *types.Vote) error {
 		return err
 	}
 
-	signBytes := vote.SignBytes(chainID)
+	signBytes := vote.SignBytes(chainID, domain)
 
 	// We might crash before writing to the wal,
 	// causing us to try to re-sign for the same HRS.
@@ -321,7 +366,7 @@ func (pv *FilePV) signVote(chainID string, vote *types.Vote) error {
 // signProposal checks if the proposal is good to sign and sets the proposal signature.
 // It may need to set the timestamp as well if the proposal is otherwise the same as
 // a previously signed proposal ie. we crashed after signing but before the proposal hit the WAL).
-func (pv *FilePV) signProposal(chainID string, proposal *types.Proposal) error {
+func (pv *FilePV) signProposal(chainID string, domain types.SignDomain, proposal *types.Proposal) error {
 	height, round, step := proposal.Height, proposal.Round, stepPropose
 
 	lss := pv.LastSignState
@@ -331,7 +376,7 @@ func (pv *FilePV) signProposal(chainID string, proposal *types.Proposal) error {
 		return err
 	}
 
-	signBytes := proposal.SignBytes(chainID)
+	signBytes := proposal.SignBytes(chainID, domain)
 
 	// We might crash before writing to the wal,
 	// causing us to try to re-sign for the same HRS.
@@ -378,10 +423,10 @@ func (pv *FilePV) saveSigned(height int64, round int, step int8,
 // returns true if the only difference in the votes is their timestamp.
 func checkVotesOnlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (time.Time, bool) {
 	var lastVote, newVote types.CanonicalVote
-	if err := cdc.UnmarshalBinaryLengthPrefixed(lastSignBytes, &lastVote); err != nil {
+	if err := unmarshalSignBytes(lastSignBytes, &lastVote); err != nil {
 		panic(fmt.Sprintf("LastSignBytes cannot be unmarshalled into vote: %v", err))
 	}
-	if err := cdc.UnmarshalBinaryLengthPrefixed(newSignBytes, &newVote); err != nil {
+	if err := unmarshalSignBytes(newSignBytes, &newVote); err != nil {
 		panic(fmt.Sprintf("signBytes cannot be unmarshalled into vote: %v", err))
 	}
 
@@ -401,10 +446,10 @@ func checkVotesOnlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (time.T
 // returns true if the only difference in the proposals is their timestamp
 func checkProposalsOnlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (time.Time, bool) {
 	var lastProposal, newProposal types.CanonicalProposal
-	if err := cdc.UnmarshalBinaryLengthPrefixed(lastSignBytes, &lastProposal); err != nil {
+	if err := unmarshalSignBytes(lastSignBytes, &lastProposal); err != nil {
 		panic(fmt.Sprintf("LastSignBytes cannot be unmarshalled into proposal: %v", err))
 	}
-	if err := cdc.UnmarshalBinaryLengthPrefixed(newSignBytes, &newProposal); err != nil {
+	if err := unmarshalSignBytes(newSignBytes, &newProposal); err != nil {
 		panic(fmt.Sprintf("signBytes cannot be unmarshalled into proposal: %v", err))
 	}
 
@@ -418,3 +463,13 @@ func checkProposalsOnlyDifferByTimestamp(lastSignBytes, newSignBytes []byte) (ti
 
 	return lastTime, bytes.Equal(newProposalBytes, lastProposalBytes)
 }
+
+// unmarshalSignBytes unmarshals the canonical vote or proposal of the sign
+// bytes, with or without their sign domain.
+func unmarshalSignBytes(signBytes []byte, canonical interface{}) error {
+	bz, err := types.StripSignDomain(signBytes)
+	if err != nil {
+		return err
+	}
+	return cdc.UnmarshalBinaryLengthPrefixed(bz, canonical)
+}
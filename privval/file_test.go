@@ -52,7 +52,7 @@ func TestResetValidator(t *testing.T) {
 	voteType := byte(types.PrevoteType)
 	blockID := types.BlockID{[]byte{1, 2, 3}, types.PartSetHeader{}}
 	vote := newVote(privVal.Key.Address, 0, height, round, voteType, blockID)
-	err = privVal.SignVote("mychainid", vote)
+	err = privVal.SignVote("mychainid", types.SignDomain{}, vote)
 	assert.NoError(t, err, "expected no error signing vote")
 
 	// priv val after signing is not same as empty
@@ -169,11 +169,11 @@ func TestSignVote(t *testing.T) {
 
 	// sign a vote for first time
 	vote := newVote(privVal.Key.Address, 0, height, round, voteType, block1)
-	err = privVal.SignVote("mychainid", vote)
+	err = privVal.SignVote("mychainid", types.SignDomain{}, vote)
 	assert.NoError(err, "expected no error signing vote")
 
 	// try to sign the same vote again; should be fine
-	err = privVal.SignVote("mychainid", vote)
+	err = privVal.SignVote("mychainid", types.SignDomain{}, vote)
 	assert.NoError(err, "expected no error on signing same vote")
 
 	// now try some bad votes
@@ -185,14 +185,14 @@ func TestSignVote(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		err = privVal.SignVote("mychainid", c)
+		err = privVal.SignVote("mychainid", types.SignDomain{}, c)
 		assert.Error(err, "expected error on signing conflicting vote")
 	}
 
 	// try signing a vote with a different time stamp
 	sig := vote.Signature
 	vote.Timestamp = vote.Timestamp.Add(time.Duration(1000))
-	err = privVal.SignVote("mychainid", vote)
+	err = privVal.SignVote("mychainid", types.SignDomain{}, vote)
 	assert.NoError(err)
 	assert.Equal(sig, vote.Signature)
 }
@@ -213,11 +213,11 @@ func TestSignProposal(t *testing.T) {
 
 	// sign a proposal for first time
 	proposal := newProposal(height, round, block1)
-	err = privVal.SignProposal("mychainid", proposal)
+	err = privVal.SignProposal("mychainid", types.SignDomain{}, proposal)
 	assert.NoError(err, "expected no error signing proposal")
 
 	// try to sign the same proposal again; should be fine
-	err = privVal.SignProposal("mychainid", proposal)
+	err = privVal.SignProposal("mychainid", types.SignDomain{}, proposal)
 	assert.NoError(err, "expected no error on signing same proposal")
 
 	// now try some bad Proposals
@@ -229,14 +229,14 @@ func TestSignProposal(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		err = privVal.SignProposal("mychainid", c)
+		err = privVal.SignProposal("mychainid", types.SignDomain{}, c)
 		assert.Error(err, "expected error on signing conflicting proposal")
 	}
 
 	// try signing a proposal with a different time stamp
 	sig := proposal.Signature
 	proposal.Timestamp = proposal.Timestamp.Add(time.Duration(1000))
-	err = privVal.SignProposal("mychainid", proposal)
+	err = privVal.SignProposal("mychainid", types.SignDomain{}, proposal)
 	assert.NoError(err)
 	assert.Equal(sig, proposal.Signature)
 }
@@ -256,9 +256,9 @@ func TestDifferByTimestamp(t *testing.T) {
 	// test proposal
 	{
 		proposal := newProposal(height, round, block1)
-		err := privVal.SignProposal(chainID, proposal)
+		err := privVal.SignProposal(chainID, types.SignDomain{}, proposal)
 		assert.NoError(t, err, "expected no error signing proposal")
-		signBytes := proposal.SignBytes(chainID)
+		signBytes := proposal.SignBytes(chainID, types.SignDomain{})
 		sig := proposal.Signature
 		timeStamp := proposal.Timestamp
 
@@ -266,11 +266,11 @@ func TestDifferByTimestamp(t *testing.T) {
 		proposal.Timestamp = proposal.Timestamp.Add(time.Millisecond)
 		var emptySig []byte
 		proposal.Signature = emptySig
-		err = privVal.SignProposal("mychainid", proposal)
+		err = privVal.SignProposal("mychainid", types.SignDomain{}, proposal)
 		assert.NoError(t, err, "expected no error on signing same proposal")
 
 		assert.Equal(t, timeStamp, proposal.Timestamp)
-		assert.Equal(t, signBytes, proposal.SignBytes(chainID))
+		assert.Equal(t, signBytes, proposal.SignBytes(chainID, types.SignDomain{}))
 		assert.Equal(t, sig, proposal.Signature)
 	}
 
@@ -279,10 +279,10 @@ func TestDifferByTimestamp(t *testing.T) {
 		voteType := byte(types.PrevoteType)
 		blockID := types.BlockID{[]byte{1, 2, 3}, types.PartSetHeader{}}
 		vote := newVote(privVal.Key.Address, 0, height, round, voteType, blockID)
-		err := privVal.SignVote("mychainid", vote)
+		err := privVal.SignVote("mychainid", types.SignDomain{}, vote)
 		assert.NoError(t, err, "expected no error signing vote")
 
-		signBytes := vote.SignBytes(chainID)
+		signBytes := vote.SignBytes(chainID, types.SignDomain{})
 		sig := vote.Signature
 		timeStamp := vote.Timestamp
 
@@ -290,15 +290,43 @@ func TestDifferByTimestamp(t *testing.T) {
 		vote.Timestamp = vote.Timestamp.Add(time.Millisecond)
 		var emptySig []byte
 		vote.Signature = emptySig
-		err = privVal.SignVote("mychainid", vote)
+		err = privVal.SignVote("mychainid", types.SignDomain{}, vote)
 		assert.NoError(t, err, "expected no error on signing same vote")
 
 		assert.Equal(t, timeStamp, vote.Timestamp)
-		assert.Equal(t, signBytes, vote.SignBytes(chainID))
+		assert.Equal(t, signBytes, vote.SignBytes(chainID, types.SignDomain{}))
 		assert.Equal(t, sig, vote.Signature)
 	}
 }
 
+func TestDifferByTimestampSignDomain(t *testing.T) {
+	tempKeyFile, err := ioutil.TempFile("", "priv_validator_key_")
+	require.Nil(t, err)
+	tempStateFile, err := ioutil.TempFile("", "priv_validator_state_")
+	require.Nil(t, err)
+
+	privVal := GenFilePV(tempKeyFile.Name(), tempStateFile.Name())
+
+	chainID := "mydomainchainid"
+	domain := types.SignDomain{Version: types.SignBytesVersionDomain, Tag: "mainnet"}
+
+	blockID := types.BlockID{[]byte{1, 2, 3}, types.PartSetHeader{}}
+	vote := newVote(privVal.Key.Address, 0, 10, 1, byte(types.PrevoteType), blockID)
+	err = privVal.SignVote(chainID, domain, vote)
+	require.NoError(t, err, "expected no error signing vote")
+	sig := vote.Signature
+	timeStamp := vote.Timestamp
+
+	// the timestamp is changed back, the sign bytes having a domain
+	vote.Timestamp = vote.Timestamp.Add(time.Millisecond)
+	vote.Signature = nil
+	err = privVal.SignVote(chainID, domain, vote)
+	assert.NoError(t, err, "expected no error on signing same vote")
+	assert.Equal(t, timeStamp, vote.Timestamp)
+	assert.Equal(t, sig, vote.Signature)
+	assert.NoError(t, vote.Verify(chainID, domain, privVal.Key.PubKey))
+}
+
 func newVote(addr types.Address, idx int, height int64, round int, typ byte, blockID types.BlockID) *types.Vote {
 	return &types.Vote{
 		ValidatorAddress: addr,
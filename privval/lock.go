@@ -0,0 +1,289 @@
+package privval
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tendermint/tendermint/crypto"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Signing lock errors.
+var (
+	ErrSigningLockExpired = errors.New("signing lock lease expired")
+)
+
+// ErrSigningLockHeld is returned when the signing lock is held by another
+// holder.
+type ErrSigningLockHeld struct {
+	Holder  string
+	Expires time.Time
+}
+
+func (e ErrSigningLockHeld) Error() string {
+	return fmt.Sprintf("signing lock is held by %s until %v", e.Holder, e.Expires)
+}
+
+// SigningLock prevents two nodes from signing with the same validator key at
+// the same time. It is held for a lease, which the holder must renew before
+// it expires.
+type SigningLock interface {
+	// Lock acquires the lock for holder for the lease duration, or renews
+	// it if holder already holds it. It returns ErrSigningLockHeld if
+	// another holder holds an unexpired lease.
+	Lock(holder string, lease time.Duration) error
+
+	// Unlock releases the lock if holder holds it.
+	Unlock(holder string) error
+}
+
+//-------------------------------------------------------------------------------
+
+// signingLease is the holder of a lease and its expiry.
+type signingLease struct {
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+}
+
+// check returns ErrSigningLockHeld if another holder than holder holds an
+// unexpired lease.
+func (l signingLease) check(holder string, now time.Time) error {
+	if l.Holder != "" && l.Holder != holder && now.Before(l.Expires) {
+		return ErrSigningLockHeld{l.Holder, l.Expires}
+	}
+	return nil
+}
+
+// FileSigningLock is a SigningLock storing the lease in a file, which must be
+// shared by the nodes, e.g. on a network file system.
+//
+// The lease is read then written, so two nodes locking at the same instant
+// could both succeed: it guards against operational mistakes, like starting
+// a backup validator while the primary one is still running, not against
+// concurrent starts.
+type FileSigningLock struct {
+	filePath string
+}
+
+var _ SigningLock = (*FileSigningLock)(nil)
+
+// NewFileSigningLock returns a SigningLock storing the lease at filePath.
+func NewFileSigningLock(filePath string) *FileSigningLock {
+	return &FileSigningLock{filePath: filePath}
+}
+
+// Lock implements SigningLock.
+func (fl *FileSigningLock) Lock(holder string, lease time.Duration) error {
+	current, err := fl.load()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if err := current.check(holder, now); err != nil {
+		return err
+	}
+	return fl.save(signingLease{holder, now.Add(lease)})
+}
+
+// Unlock implements SigningLock.
+func (fl *FileSigningLock) Unlock(holder string) error {
+	current, err := fl.load()
+	if err != nil {
+		return err
+	}
+	if current.Holder != holder {
+		return nil
+	}
+	return os.Remove(fl.filePath)
+}
+
+func (fl *FileSigningLock) load() (signingLease, error) {
+	var lease signingLease
+	bz, err := ioutil.ReadFile(fl.filePath)
+	if os.IsNotExist(err) {
+		return lease, nil
+	} else if err != nil {
+		return lease, err
+	}
+	if err := cdc.UnmarshalJSON(bz, &lease); err != nil {
+		return lease, errors.Wrapf(err, "failed to read the signing lock %s", fl.filePath)
+	}
+	return lease, nil
+}
+
+func (fl *FileSigningLock) save(lease signingLease) error {
+	bz, err := cdc.MarshalJSON(lease)
+	if err != nil {
+		return err
+	}
+	return cmn.WriteFileAtomic(fl.filePath, bz, 0600)
+}
+
+//-------------------------------------------------------------------------------
+
+// SignerLease is the SigningLock of remote signers, in memory. It can be
+// shared by the RemoteSigners of several nodes, see RemoteSignerLease, to
+// only sign for one of them at a time.
+type SignerLease struct {
+	mtx   sync.Mutex
+	lease signingLease
+}
+
+var _ SigningLock = (*SignerLease)(nil)
+
+// NewSignerLease returns a free SignerLease.
+func NewSignerLease() *SignerLease {
+	return &SignerLease{}
+}
+
+// Lock implements SigningLock.
+func (sl *SignerLease) Lock(holder string, lease time.Duration) error {
+	sl.mtx.Lock()
+	defer sl.mtx.Unlock()
+	now := time.Now()
+	if err := sl.lease.check(holder, now); err != nil {
+		return err
+	}
+	sl.lease = signingLease{holder, now.Add(lease)}
+	return nil
+}
+
+// Unlock implements SigningLock.
+func (sl *SignerLease) Unlock(holder string) error {
+	sl.mtx.Lock()
+	defer sl.mtx.Unlock()
+	if sl.lease.Holder == holder {
+		sl.lease = signingLease{}
+	}
+	return nil
+}
+
+// CanSign returns an error if another holder than holder holds an unexpired
+// lease.
+func (sl *SignerLease) CanSign(holder string) error {
+	sl.mtx.Lock()
+	defer sl.mtx.Unlock()
+	return sl.lease.check(holder, time.Now())
+}
+
+//-------------------------------------------------------------------------------
+
+// LockedPV is a PrivValidator which only signs while it holds a signing lock.
+// It acquires the lock at start, failing if another node holds it, and
+// renews the lease in the background. If the lease can't be renewed before
+// it expires, signing fails with ErrSigningLockExpired until it is.
+type LockedPV struct {
+	cmn.BaseService
+
+	privVal types.PrivValidator
+	lock    SigningLock
+	holder  string
+	lease   time.Duration
+
+	mtx     sync.Mutex
+	expires time.Time
+}
+
+var _ types.PrivValidator = (*LockedPV)(nil)
+
+// NewLockedPV returns a LockedPV signing with privVal while holder holds
+// lock. If privVal is a cmn.Service, it must be started, and is stopped with
+// the LockedPV.
+func NewLockedPV(privVal types.PrivValidator, lock SigningLock, holder string, lease time.Duration) *LockedPV {
+	pv := &LockedPV{
+		privVal: privVal,
+		lock:    lock,
+		holder:  holder,
+		lease:   lease,
+	}
+	pv.BaseService = *cmn.NewBaseService(nil, "LockedPV", pv)
+	return pv
+}
+
+// OnStart implements cmn.Service by acquiring the lock.
+func (pv *LockedPV) OnStart() error {
+	if err := pv.renew(); err != nil {
+		return errors.Wrap(err, "failed to acquire the signing lock, another node may be signing with the validator key")
+	}
+	go pv.renewRoutine()
+	return nil
+}
+
+// OnStop implements cmn.Service by releasing the lock.
+func (pv *LockedPV) OnStop() {
+	if err := pv.lock.Unlock(pv.holder); err != nil {
+		pv.Logger.Error("Failed to release the signing lock", "err", err)
+	}
+	if svc, ok := pv.privVal.(cmn.Service); ok {
+		svc.Stop()
+	}
+}
+
+func (pv *LockedPV) renewRoutine() {
+	ticker := time.NewTicker(pv.lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := pv.renew(); err != nil {
+				pv.Logger.Error("Failed to renew the signing lock", "err", err)
+			}
+		case <-pv.Quit():
+			return
+		}
+	}
+}
+
+func (pv *LockedPV) renew() error {
+	// The lease is measured from before the request, so it expires here no
+	// later than in the lock.
+	expires := time.Now().Add(pv.lease)
+	if err := pv.lock.Lock(pv.holder, pv.lease); err != nil {
+		return err
+	}
+	pv.mtx.Lock()
+	pv.expires = expires
+	pv.mtx.Unlock()
+	return nil
+}
+
+func (pv *LockedPV) checkLease() error {
+	pv.mtx.Lock()
+	defer pv.mtx.Unlock()
+	if !time.Now().Before(pv.expires) {
+		return ErrSigningLockExpired
+	}
+	return nil
+}
+
+// GetPubKey implements PrivValidator.
+func (pv *LockedPV) GetPubKey() crypto.PubKey {
+	return pv.privVal.GetPubKey()
+}
+
+// SignVote implements PrivValidator.
+func (pv *LockedPV) SignVote(chainID string, domain types.SignDomain, vote *types.Vote) error {
+	if err := pv.checkLease(); err != nil {
+		return err
+	}
+	return pv.privVal.SignVote(chainID, domain, vote)
+}
+
+// SignProposal implements PrivValidator.
+func (pv *LockedPV) SignProposal(chainID string, domain types.SignDomain, proposal *types.Proposal) error {
+	if err := pv.checkLease(); err != nil {
+		return err
+	}
+	return pv.privVal.SignProposal(chainID, domain, proposal)
+}
+
+// String returns a string representation of the LockedPV.
+func (pv *LockedPV) String() string {
+	return fmt.Sprintf("LockedPV{%v %s}", pv.privVal, pv.holder)
+}
@@ -0,0 +1,127 @@
+package privval
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestFileSigningLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signing_lock_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	lock := NewFileSigningLock(filepath.Join(dir, "lock.json"))
+	require.NoError(t, lock.Lock("a", time.Minute))
+	// renewing
+	require.NoError(t, lock.Lock("a", time.Minute))
+
+	err = lock.Lock("b", time.Minute)
+	require.IsType(t, ErrSigningLockHeld{}, err)
+	assert.Equal(t, "a", err.(ErrSigningLockHeld).Holder)
+
+	// b doesn't hold the lock
+	require.NoError(t, lock.Unlock("b"))
+	assert.Error(t, lock.Lock("b", time.Minute))
+
+	require.NoError(t, lock.Unlock("a"))
+	require.NoError(t, lock.Lock("b", 10*time.Millisecond))
+
+	// the lease of b expires
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, lock.Lock("a", time.Minute))
+}
+
+// testLock is a SigningLock failing with err.
+type testLock struct {
+	mtx sync.Mutex
+	err error
+}
+
+func (tl *testLock) Lock(holder string, lease time.Duration) error {
+	tl.mtx.Lock()
+	defer tl.mtx.Unlock()
+	return tl.err
+}
+
+func (tl *testLock) Unlock(holder string) error {
+	return nil
+}
+
+func (tl *testLock) setErr(err error) {
+	tl.mtx.Lock()
+	tl.err = err
+	tl.mtx.Unlock()
+}
+
+func TestLockedPV(t *testing.T) {
+	var (
+		chainID = cmn.RandStr(12)
+		lease   = NewSignerLease()
+		privVal = types.NewMockPV()
+		pvA     = NewLockedPV(privVal, lease, "a", time.Minute)
+		pvB     = NewLockedPV(privVal, lease, "b", time.Minute)
+	)
+
+	require.NoError(t, pvA.Start())
+	assert.Error(t, pvB.Start(), "a holds the lock")
+	assert.NoError(t, pvA.SignVote(chainID, types.SignDomain{}, &types.Vote{Type: types.PrevoteType}))
+
+	require.NoError(t, pvA.Stop())
+	require.NoError(t, pvB.Start())
+	defer pvB.Stop()
+	assert.NoError(t, pvB.SignVote(chainID, types.SignDomain{}, &types.Vote{Type: types.PrevoteType}))
+}
+
+func TestLockedPVLeaseExpiry(t *testing.T) {
+	var (
+		chainID = cmn.RandStr(12)
+		lock    = &testLock{}
+		pv      = NewLockedPV(types.NewMockPV(), lock, "a", 30*time.Millisecond)
+	)
+	require.NoError(t, pv.Start())
+	defer pv.Stop()
+
+	// the lease can't be renewed anymore
+	lock.setErr(ErrSigningLockHeld{Holder: "b"})
+	time.Sleep(40 * time.Millisecond)
+	assert.Equal(t, ErrSigningLockExpired, pv.SignVote(chainID, types.SignDomain{}, &types.Vote{Type: types.PrevoteType}))
+	assert.Equal(t, ErrSigningLockExpired, pv.SignProposal(chainID, types.SignDomain{}, &types.Proposal{}))
+
+	// signing resumes once it is renewed
+	lock.setErr(nil)
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, pv.SignVote(chainID, types.SignDomain{}, &types.Vote{Type: types.PrevoteType}))
+}
+
+func TestRemoteSignerSigningLock(t *testing.T) {
+	for _, tc := range socketTestCases(t) {
+		func() {
+			var (
+				chainID = cmn.RandStr(12)
+				sc, rs  = testSetupSocketPair(t, chainID, types.NewMockPV(), tc.addr, tc.dialer)
+			)
+			defer sc.Stop()
+			defer rs.Stop()
+
+			require.NoError(t, sc.Lock("a", time.Minute))
+			assert.Error(t, sc.Lock("b", time.Minute), "a holds the lock")
+			assert.NoError(t, sc.SignVote(chainID, types.SignDomain{}, &types.Vote{Type: types.PrevoteType}))
+
+			// another node takes the lease of the signer
+			require.NoError(t, sc.Unlock("a"))
+			require.NoError(t, rs.lease.Lock("b", time.Minute))
+			assert.Error(t, sc.SignVote(chainID, types.SignDomain{}, &types.Vote{Type: types.PrevoteType}))
+			assert.Error(t, sc.SignProposal(chainID, types.SignDomain{}, &types.Proposal{}))
+		}()
+	}
+}
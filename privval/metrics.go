@@ -0,0 +1,66 @@
+package privval
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "privval"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Round-trip time of the signing requests to the remote signer, by
+	// msg_type.
+	SignLatency metrics.Histogram
+	// Number of the signing requests to the remote signer which failed, by
+	// msg_type.
+	SignFailures metrics.Counter
+	// Number of connections to the remote signer dropped.
+	ConnectionDrops metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		SignLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sign_latency",
+			Help:      "Round-trip time of the signing requests to the remote signer in ms.",
+			Buckets:   stdprometheus.ExponentialBuckets(1, 2, 12),
+		}, append(labels, "msg_type")).With(labelsAndValues...),
+		SignFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sign_failures",
+			Help:      "Number of the signing requests to the remote signer which failed.",
+		}, append(labels, "msg_type")).With(labelsAndValues...),
+		ConnectionDrops: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "connection_drops",
+			Help:      "Number of connections to the remote signer dropped.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		SignLatency:     discard.NewHistogram(),
+		SignFailures:    discard.NewCounter(),
+		ConnectionDrops: discard.NewCounter(),
+	}
+}
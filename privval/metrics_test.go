@@ -0,0 +1,130 @@
+package privval
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types"
+)
+
+// testMetric records the values added to a metric, or the number of values
+// observed, by label values.
+type testMetric struct {
+	mtx    *sync.Mutex
+	values map[string]float64
+	lvs    string
+}
+
+func newTestMetric() *testMetric {
+	return &testMetric{mtx: &sync.Mutex{}, values: make(map[string]float64)}
+}
+
+func (m *testMetric) with(labelValues []string) *testMetric {
+	return &testMetric{mtx: m.mtx, values: m.values, lvs: strings.Join(labelValues, ",")}
+}
+
+func (m *testMetric) add(delta float64) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.values[m.lvs] += delta
+}
+
+func (m *testMetric) value(lvs string) float64 {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.values[lvs]
+}
+
+type testCounter struct{ *testMetric }
+
+func (c testCounter) With(labelValues ...string) metrics.Counter {
+	return testCounter{c.with(labelValues)}
+}
+
+func (c testCounter) Add(delta float64) { c.add(delta) }
+
+type testHistogram struct{ *testMetric }
+
+func (h testHistogram) With(labelValues ...string) metrics.Histogram {
+	return testHistogram{h.with(labelValues)}
+}
+
+func (h testHistogram) Observe(value float64) { h.add(1) }
+
+func TestSocketValSignMetrics(t *testing.T) {
+	for _, tc := range socketTestCases(t) {
+		func() {
+			var (
+				chainID = cmn.RandStr(12)
+				sc, rs  = testSetupSocketPair(t, chainID, types.NewErroringMockPV(), tc.addr, tc.dialer)
+
+				latency  = newTestMetric()
+				failures = newTestMetric()
+			)
+			defer sc.Stop()
+			defer rs.Stop()
+			sc.metrics = &Metrics{
+				SignLatency:     testHistogram{latency},
+				SignFailures:    testCounter{failures},
+				ConnectionDrops: testCounter{newTestMetric()},
+			}
+
+			vote := &types.Vote{Timestamp: time.Now(), Type: types.PrevoteType}
+			require.Error(t, sc.SignVote(chainID, types.SignDomain{}, vote))
+			proposal := &types.Proposal{Timestamp: time.Now(), Type: types.ProposalType}
+			require.Error(t, sc.SignProposal(chainID, types.SignDomain{}, proposal))
+
+			assert.EqualValues(t, 1, latency.value("msg_type,prevote"))
+			assert.EqualValues(t, 1, latency.value("msg_type,proposal"))
+			assert.EqualValues(t, 1, failures.value("msg_type,prevote"))
+			assert.EqualValues(t, 1, failures.value("msg_type,proposal"))
+			assert.EqualValues(t, 0, failures.value("msg_type,precommit"))
+		}()
+	}
+}
+
+func TestSocketValConnectionDrops(t *testing.T) {
+	for _, tc := range socketTestCases(t) {
+		func() {
+			var (
+				chainID = cmn.RandStr(12)
+				sc, rs  = testSetupSocketPair(t, chainID, types.NewMockPV(), tc.addr, tc.dialer)
+
+				drops = newTestMetric()
+			)
+			defer sc.Stop()
+			sc.metrics = &Metrics{
+				SignLatency:     testHistogram{newTestMetric()},
+				SignFailures:    testCounter{newTestMetric()},
+				ConnectionDrops: testCounter{drops},
+			}
+
+			// No drop while the remote signer answers the pings.
+			time.Sleep(testHeartbeatTimeout * 5)
+			assert.EqualValues(t, 0, drops.value(""))
+
+			// The remote signer goes away.
+			require.NoError(t, rs.Stop())
+			for start := time.Now(); drops.value("") == 0; time.Sleep(testHeartbeatTimeout) {
+				if time.Since(start) > 10*time.Second {
+					t.Fatal("timed out waiting for the connection drop")
+				}
+			}
+		}()
+	}
+}
+
+func TestIsConnDrop(t *testing.T) {
+	assert.True(t, isConnDrop(io.EOF))
+	assert.False(t, isConnDrop(nil))
+	assert.False(t, isConnDrop(ErrUnexpectedResponse))
+	assert.False(t, isConnDrop(cmn.ErrorWrap(ErrConnTimeout, "read timeout")))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -78,8 +79,9 @@ func getPubKey(conn net.Conn) (crypto.PubKey, error) {
 	return pubKeyResp.PubKey, nil
 }
 
-// SignVote implements PrivValidator.
-func (sc *RemoteSignerClient) SignVote(chainID string, vote *types.Vote) error {
+// SignVote implements PrivValidator. The remote signer signs in the chain ID
+// and sign domain it is configured with.
+func (sc *RemoteSignerClient) SignVote(chainID string, domain types.SignDomain, vote *types.Vote) error {
 	err := writeMsg(sc.conn, &SignVoteRequest{Vote: vote})
 	if err != nil {
 		return err
@@ -102,9 +104,11 @@ func (sc *RemoteSignerClient) SignVote(chainID string, vote *types.Vote) error {
 	return nil
 }
 
-// SignProposal implements PrivValidator.
+// SignProposal implements PrivValidator. The remote signer signs in the chain
+// ID and sign domain it is configured with.
 func (sc *RemoteSignerClient) SignProposal(
 	chainID string,
+	domain types.SignDomain,
 	proposal *types.Proposal,
 ) error {
 	err := writeMsg(sc.conn, &SignProposalRequest{Proposal: proposal})
@@ -147,6 +151,84 @@ func (sc *RemoteSignerClient) Ping() error {
 	return nil
 }
 
+// Lock implements SigningLock by acquiring or renewing the lease of the
+// remote signer.
+func (sc *RemoteSignerClient) Lock(holder string, lease time.Duration) error {
+	return sc.signingLock(&SigningLockRequest{Holder: holder, Lease: lease})
+}
+
+// Unlock implements SigningLock by releasing the lease of the remote signer.
+func (sc *RemoteSignerClient) Unlock(holder string) error {
+	return sc.signingLock(&SigningLockRequest{Holder: holder, Unlock: true})
+}
+
+func (sc *RemoteSignerClient) signingLock(req *SigningLockRequest) error {
+	err := writeMsg(sc.conn, req)
+	if err != nil {
+		return err
+	}
+
+	res, err := readMsg(sc.conn)
+	if err != nil {
+		return err
+	}
+	resp, ok := res.(*SigningLockResponse)
+	if !ok {
+		return ErrUnexpectedResponse
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	return nil
+}
+
+// ExportSignState implements SignStateSyncer by exporting the sign state of
+// the remote signer.
+func (sc *RemoteSignerClient) ExportSignState(freeze bool) (FilePVLastSignState, error) {
+	err := writeMsg(sc.conn, &ExportSignStateRequest{Freeze: freeze})
+	if err != nil {
+		return FilePVLastSignState{}, err
+	}
+
+	res, err := readMsg(sc.conn)
+	if err != nil {
+		return FilePVLastSignState{}, err
+	}
+	resp, ok := res.(*ExportSignStateResponse)
+	if !ok {
+		return FilePVLastSignState{}, ErrUnexpectedResponse
+	}
+	if resp.Error != nil {
+		return FilePVLastSignState{}, resp.Error
+	}
+
+	return *resp.State, nil
+}
+
+// ImportSignState implements SignStateSyncer by importing the sign state into
+// the remote signer.
+func (sc *RemoteSignerClient) ImportSignState(state FilePVLastSignState) error {
+	err := writeMsg(sc.conn, &ImportSignStateRequest{State: &state})
+	if err != nil {
+		return err
+	}
+
+	res, err := readMsg(sc.conn)
+	if err != nil {
+		return err
+	}
+	resp, ok := res.(*ImportSignStateResponse)
+	if !ok {
+		return ErrUnexpectedResponse
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	return nil
+}
+
 // RemoteSignerMsg is sent between RemoteSigner and the RemoteSigner client.
 type RemoteSignerMsg interface{}
 
@@ -160,6 +242,12 @@ func RegisterRemoteSignerMsg(cdc *amino.Codec) {
 	cdc.RegisterConcrete(&SignedProposalResponse{}, "tendermint/remotesigner/SignedProposalResponse", nil)
 	cdc.RegisterConcrete(&PingRequest{}, "tendermint/remotesigner/PingRequest", nil)
 	cdc.RegisterConcrete(&PingResponse{}, "tendermint/remotesigner/PingResponse", nil)
+	cdc.RegisterConcrete(&SigningLockRequest{}, "tendermint/remotesigner/SigningLockRequest", nil)
+	cdc.RegisterConcrete(&SigningLockResponse{}, "tendermint/remotesigner/SigningLockResponse", nil)
+	cdc.RegisterConcrete(&ExportSignStateRequest{}, "tendermint/remotesigner/ExportSignStateRequest", nil)
+	cdc.RegisterConcrete(&ExportSignStateResponse{}, "tendermint/remotesigner/ExportSignStateResponse", nil)
+	cdc.RegisterConcrete(&ImportSignStateRequest{}, "tendermint/remotesigner/ImportSignStateRequest", nil)
+	cdc.RegisterConcrete(&ImportSignStateResponse{}, "tendermint/remotesigner/ImportSignStateResponse", nil)
 }
 
 // PubKeyRequest requests the consensus public key from the remote signer.
@@ -199,6 +287,40 @@ type PingRequest struct {
 type PingResponse struct {
 }
 
+// SigningLockRequest is a PrivValidatorSocket message to acquire or renew the
+// lease of the remote signer for Holder, or to release it (see SigningLock).
+type SigningLockRequest struct {
+	Holder string
+	Lease  time.Duration
+	Unlock bool
+}
+
+type SigningLockResponse struct {
+	Error *RemoteSignerError
+}
+
+// ExportSignStateRequest is a PrivValidatorSocket message requesting the last
+// sign state of the remote signer, freezing it if Freeze is true (see
+// SignStateSyncer).
+type ExportSignStateRequest struct {
+	Freeze bool
+}
+
+type ExportSignStateResponse struct {
+	State *FilePVLastSignState
+	Error *RemoteSignerError
+}
+
+// ImportSignStateRequest is a PrivValidatorSocket message containing the last
+// sign state to import into the remote signer.
+type ImportSignStateRequest struct {
+	State *FilePVLastSignState
+}
+
+type ImportSignStateResponse struct {
+	Error *RemoteSignerError
+}
+
 // RemoteSignerError allows (remote) validators to include meaningful error descriptions in their reply.
 type RemoteSignerError struct {
 	// TODO(ismail): create an enum of known errors
@@ -227,7 +349,7 @@ func writeMsg(w io.Writer, msg interface{}) (err error) {
 	return
 }
 
-func handleRequest(req RemoteSignerMsg, chainID string, privVal types.PrivValidator) (RemoteSignerMsg, error) {
+func handleRequest(req RemoteSignerMsg, chainID string, domain types.SignDomain, privVal types.PrivValidator) (RemoteSignerMsg, error) {
 	var res RemoteSignerMsg
 	var err error
 
@@ -237,14 +359,14 @@ func handleRequest(req RemoteSignerMsg, chainID string, privVal types.PrivValida
 		p = privVal.GetPubKey()
 		res = &PubKeyResponse{p, nil}
 	case *SignVoteRequest:
-		err = privVal.SignVote(chainID, r.Vote)
+		err = privVal.SignVote(chainID, domain, r.Vote)
 		if err != nil {
 			res = &SignedVoteResponse{nil, &RemoteSignerError{0, err.Error()}}
 		} else {
 			res = &SignedVoteResponse{r.Vote, nil}
 		}
 	case *SignProposalRequest:
-		err = privVal.SignProposal(chainID, r.Proposal)
+		err = privVal.SignProposal(chainID, domain, r.Proposal)
 		if err != nil {
 			res = &SignedProposalResponse{nil, &RemoteSignerError{0, err.Error()}}
 		} else {
@@ -252,6 +374,34 @@ func handleRequest(req RemoteSignerMsg, chainID string, privVal types.PrivValida
 		}
 	case *PingRequest:
 		res = &PingResponse{}
+	case *ExportSignStateRequest:
+		syncer, ok := privVal.(SignStateSyncer)
+		if !ok {
+			err = ErrSignStateUnsupported
+			res = &ExportSignStateResponse{nil, &RemoteSignerError{0, err.Error()}}
+			break
+		}
+		var state FilePVLastSignState
+		state, err = syncer.ExportSignState(r.Freeze)
+		if err != nil {
+			res = &ExportSignStateResponse{nil, &RemoteSignerError{0, err.Error()}}
+		} else {
+			res = &ExportSignStateResponse{&state, nil}
+		}
+	case *ImportSignStateRequest:
+		syncer, ok := privVal.(SignStateSyncer)
+		if !ok {
+			err = ErrSignStateUnsupported
+		} else if r.State == nil {
+			err = errors.New("no sign state")
+		} else {
+			err = syncer.ImportSignState(*r.State)
+		}
+		if err != nil {
+			res = &ImportSignStateResponse{&RemoteSignerError{0, err.Error()}}
+		} else {
+			res = &ImportSignStateResponse{}
+		}
 	default:
 		err = fmt.Errorf("unknown msg: %v", r)
 	}
@@ -32,18 +32,38 @@ func RemoteSignerConnRetries(retries int) RemoteSignerOption {
 	return func(ss *RemoteSigner) { ss.connRetries = retries }
 }
 
+// RemoteSignerLease sets the lease of the signing lock, to share it with the
+// RemoteSigners of other nodes signing with the same key.
+func RemoteSignerLease(lease *SignerLease) RemoteSignerOption {
+	return func(ss *RemoteSigner) { ss.lease = lease }
+}
+
+// RemoteSignerSignDomain sets the SignDomain of the sign bytes of the votes
+// and proposals of the chain, the legacy one by default.
+func RemoteSignerSignDomain(domain types.SignDomain) RemoteSignerOption {
+	return func(ss *RemoteSigner) { ss.domain = domain }
+}
+
 // RemoteSigner dials using its dialer and responds to any
 // signature requests using its privVal.
+//
+// It only signs if its signing lock lease is free or held by the node it is
+// connected to, see SigningLockRequest, and until its sign state is exported
+// with Freeze, see ExportSignStateRequest.
 type RemoteSigner struct {
 	cmn.BaseService
 
 	chainID      string
+	domain       types.SignDomain
 	connDeadline time.Duration
 	connRetries  int
 	privVal      types.PrivValidator
+	lease        *SignerLease
 
 	dialer Dialer
 	conn   net.Conn
+	holder string // of the lease, for the connected node
+	frozen bool   // since the sign state was exported with Freeze
 }
 
 // Dialer dials a remote address and returns a net.Conn or an error.
@@ -86,6 +106,7 @@ func NewRemoteSigner(
 		connDeadline: time.Second * defaultConnDeadlineSeconds,
 		connRetries:  defaultDialRetries,
 		privVal:      privVal,
+		lease:        NewSignerLease(),
 		dialer:       dialer,
 	}
 
@@ -152,7 +173,7 @@ func (rs *RemoteSigner) handleConnection(conn net.Conn) {
 			return
 		}
 
-		res, err := handleRequest(req, rs.chainID, rs.privVal)
+		res, err := rs.handleRequest(req)
 
 		if err != nil {
 			// only log the error; we'll reply with an error in res
@@ -166,3 +187,46 @@ func (rs *RemoteSigner) handleConnection(conn net.Conn) {
 		}
 	}
 }
+
+// handleRequest handles the signing lock requests, and the other requests if
+// the signing lock lets the connected node sign.
+func (rs *RemoteSigner) handleRequest(req RemoteSignerMsg) (RemoteSignerMsg, error) {
+	switch r := req.(type) {
+	case *SigningLockRequest:
+		var err error
+		if r.Unlock {
+			err = rs.lease.Unlock(r.Holder)
+		} else if err = rs.lease.Lock(r.Holder, r.Lease); err == nil {
+			rs.holder = r.Holder
+		}
+		if err != nil {
+			return &SigningLockResponse{&RemoteSignerError{0, err.Error()}}, err
+		}
+		return &SigningLockResponse{}, nil
+	case *SignVoteRequest:
+		if err := rs.canSign(); err != nil {
+			return &SignedVoteResponse{nil, &RemoteSignerError{0, err.Error()}}, err
+		}
+	case *SignProposalRequest:
+		if err := rs.canSign(); err != nil {
+			return &SignedProposalResponse{nil, &RemoteSignerError{0, err.Error()}}, err
+		}
+	case *ExportSignStateRequest:
+		res, err := handleRequest(req, rs.chainID, rs.domain, rs.privVal)
+		if err == nil && r.Freeze {
+			// whether the privVal persists it or not, don't sign anymore
+			rs.frozen = true
+		}
+		return res, err
+	}
+	return handleRequest(req, rs.chainID, rs.domain, rs.privVal)
+}
+
+// canSign returns an error if the RemoteSigner doesn't sign anymore, or if its
+// signing lock lease is held by another node.
+func (rs *RemoteSigner) canSign() error {
+	if rs.frozen {
+		return ErrSignStateExported
+	}
+	return rs.lease.CanSign(rs.holder)
+}
@@ -0,0 +1,62 @@
+package privval
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Sign state errors.
+var (
+	ErrSignStateExported    = errors.New("signing is frozen since the sign state was exported")
+	ErrSignStateUnsupported = errors.New("private validator doesn't support exporting and importing its sign state")
+)
+
+// SignStateSyncer is a PrivValidator whose last sign state (height, round,
+// step and signature) can be exported and imported, to migrate its key to
+// another signer without double signing.
+type SignStateSyncer interface {
+	// ExportSignState returns the last sign state. If freeze is true, the
+	// signer refuses to sign afterwards, so that the exported state stays
+	// the last one.
+	ExportSignState(freeze bool) (FilePVLastSignState, error)
+
+	// ImportSignState sets the last sign state, unless it is behind the
+	// current one.
+	ImportSignState(state FilePVLastSignState) error
+}
+
+// MigrateSignState moves the last sign state from one signer to another:
+// from is frozen as its state is exported, so it can't sign after the state
+// is imported into to.
+func MigrateSignState(from, to SignStateSyncer) error {
+	state, err := from.ExportSignState(true)
+	if err != nil {
+		return errors.Wrap(err, "failed to export the sign state")
+	}
+	if err := to.ImportSignState(state); err != nil {
+		return errors.Wrap(err, "failed to import the sign state")
+	}
+	return nil
+}
+
+// compareHRS returns -1, 0 or 1 if the height, round and step (HRS) h1/r1/s1
+// is before, equal to or after h2/r2/s2.
+func compareHRS(h1 int64, r1 int, s1 int8, h2 int64, r2 int, s2 int8) int {
+	switch {
+	case h1 != h2:
+		if h1 < h2 {
+			return -1
+		}
+		return 1
+	case r1 != r2:
+		if r1 < r2 {
+			return -1
+		}
+		return 1
+	case s1 != s2:
+		if s1 < s2 {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package privval
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/types"
+)
+
+func newTestFilePV(t *testing.T) *FilePV {
+	tempKeyFile, err := ioutil.TempFile("", "priv_validator_key_")
+	require.Nil(t, err)
+	tempStateFile, err := ioutil.TempFile("", "priv_validator_state_")
+	require.Nil(t, err)
+	return GenFilePV(tempKeyFile.Name(), tempStateFile.Name())
+}
+
+func TestMigrateSignState(t *testing.T) {
+	var (
+		chainID = "mychainid"
+		blockID = types.BlockID{[]byte{1, 2, 3}, types.PartSetHeader{}}
+		from    = newTestFilePV(t)
+		to      = newTestFilePV(t)
+	)
+	to.Key = from.Key
+
+	vote := newVote(from.Key.Address, 0, 10, 1, byte(types.PrevoteType), blockID)
+	require.NoError(t, from.SignVote(chainID, types.SignDomain{}, vote))
+
+	require.NoError(t, MigrateSignState(from, to))
+	assert.EqualValues(t, 10, to.LastSignState.Height)
+	assert.Equal(t, vote.Signature, to.LastSignState.Signature)
+
+	// the old signer is frozen
+	vote = newVote(from.Key.Address, 0, 11, 0, byte(types.PrevoteType), blockID)
+	assert.Error(t, from.SignVote(chainID, types.SignDomain{}, vote))
+	assert.Error(t, from.SignProposal(chainID, types.SignDomain{}, newProposal(11, 0, blockID)))
+
+	// including after a restart
+	from.Key.Save()
+	reloaded := LoadFilePV(from.Key.filePath, from.LastSignState.filePath)
+	assert.True(t, reloaded.LastSignState.Frozen)
+	assert.Equal(t, ErrSignStateExported, reloaded.SignVote(chainID, types.SignDomain{}, vote))
+
+	// the new signer can't sign again for the height
+	vote = newVote(from.Key.Address, 0, 10, 0, byte(types.PrevoteType), blockID)
+	assert.Error(t, to.SignVote(chainID, types.SignDomain{}, vote))
+	vote = newVote(from.Key.Address, 0, 11, 0, byte(types.PrevoteType), blockID)
+	assert.NoError(t, to.SignVote(chainID, types.SignDomain{}, vote))
+
+	// the state can't go backwards
+	state, err := from.ExportSignState(false)
+	require.NoError(t, err)
+	assert.Error(t, to.ImportSignState(state))
+}
+
+func TestRemoteSignerSignState(t *testing.T) {
+	for _, tc := range socketTestCases(t) {
+		func() {
+			var (
+				chainID = cmn.RandStr(12)
+				pv      = newTestFilePV(t)
+				sc, rs  = testSetupSocketPair(t, chainID, pv, tc.addr, tc.dialer)
+				blockID = types.BlockID{[]byte{1, 2, 3}, types.PartSetHeader{}}
+			)
+			defer sc.Stop()
+			defer rs.Stop()
+
+			require.NoError(t, sc.ImportSignState(FilePVLastSignState{Height: 5, Round: 1, Step: stepPrevote}))
+			assert.EqualValues(t, 5, pv.LastSignState.Height)
+			assert.Error(t, sc.ImportSignState(FilePVLastSignState{Height: 4}))
+
+			state, err := sc.ExportSignState(true)
+			require.NoError(t, err)
+			assert.EqualValues(t, 5, state.Height)
+			assert.Equal(t, 1, state.Round)
+			assert.Equal(t, stepPrevote, state.Step)
+
+			vote := newVote(pv.Key.Address, 0, 6, 0, byte(types.PrevoteType), blockID)
+			assert.Error(t, sc.SignVote(chainID, types.SignDomain{}, vote), "the remote signer is frozen")
+			assert.True(t, rs.frozen)
+			assert.True(t, pv.LastSignState.Frozen)
+		}()
+	}
+}
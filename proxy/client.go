@@ -35,6 +35,27 @@ func (l *localClientCreator) NewABCIClient() (abcicli.Client, error) {
 	return abcicli.NewLocalClient(l.mtx, l.app), nil
 }
 
+//----------------------------------------------------
+// concurrent local proxy uses a mutex per connection on an in-proc app
+
+type concurrentLocalClientCreator struct {
+	app types.Application
+}
+
+// NewConcurrentLocalClientCreator returns a ClientCreator for an in-proc app
+// with a mutex per connection instead of one for all of them, so that e.g.
+// queries don't wait for the blocks being executed. The app must synchronize
+// the calls on different connections itself.
+func NewConcurrentLocalClientCreator(app types.Application) ClientCreator {
+	return &concurrentLocalClientCreator{
+		app: app,
+	}
+}
+
+func (l *concurrentLocalClientCreator) NewABCIClient() (abcicli.Client, error) {
+	return abcicli.NewLocalClient(new(sync.Mutex), l.app), nil
+}
+
 //---------------------------------------------------------------
 // remote proxy opens new connections to an external app process
 
@@ -42,18 +63,20 @@ type remoteClientCreator struct {
 	addr        string
 	transport   string
 	mustConnect bool
+	options     []abcicli.SocketClientOption
 }
 
-func NewRemoteClientCreator(addr, transport string, mustConnect bool) ClientCreator {
+func NewRemoteClientCreator(addr, transport string, mustConnect bool, options ...abcicli.SocketClientOption) ClientCreator {
 	return &remoteClientCreator{
 		addr:        addr,
 		transport:   transport,
 		mustConnect: mustConnect,
+		options:     options,
 	}
 }
 
 func (r *remoteClientCreator) NewABCIClient() (abcicli.Client, error) {
-	remoteApp, err := abcicli.NewClient(r.addr, r.transport, r.mustConnect)
+	remoteApp, err := abcicli.NewClient(r.addr, r.transport, r.mustConnect, r.options...)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to connect to proxy")
 	}
@@ -63,7 +86,10 @@ func (r *remoteClientCreator) NewABCIClient() (abcicli.Client, error) {
 //-----------------------------------------------------------------
 // default
 
-func DefaultClientCreator(addr, transport, dbDir string) ClientCreator {
+// DefaultClientCreator returns a ClientCreator for the application compiled
+// in with the given name, or connecting to the application at addr. The
+// options only apply to applications connected over a socket.
+func DefaultClientCreator(addr, transport, dbDir string, options ...abcicli.SocketClientOption) ClientCreator {
 	switch addr {
 	case "counter":
 		return NewLocalClientCreator(counter.NewCounterApplication(false))
@@ -77,6 +103,6 @@ func DefaultClientCreator(addr, transport, dbDir string) ClientCreator {
 		return NewLocalClientCreator(types.NewBaseApplication())
 	default:
 		mustConnect := false // loop retrying
-		return NewRemoteClientCreator(addr, transport, mustConnect)
+		return NewRemoteClientCreator(addr, transport, mustConnect, options...)
 	}
 }
@@ -0,0 +1,49 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/abci/types"
+)
+
+// blockingApp blocks in DeliverTx until unblock is closed.
+type blockingApp struct {
+	types.BaseApplication
+	delivering chan struct{}
+	unblock    chan struct{}
+}
+
+func (app *blockingApp) DeliverTx(tx []byte) types.ResponseDeliverTx {
+	close(app.delivering)
+	<-app.unblock
+	return types.ResponseDeliverTx{}
+}
+
+func TestConcurrentLocalClientCreator(t *testing.T) {
+	app := &blockingApp{delivering: make(chan struct{}), unblock: make(chan struct{})}
+	defer close(app.unblock)
+
+	proxyApp := NewAppConns(NewConcurrentLocalClientCreator(app))
+	require.NoError(t, proxyApp.Start())
+	defer proxyApp.Stop()
+
+	go proxyApp.Consensus().DeliverTxAsync([]byte("tx"))
+	<-app.delivering
+
+	// The query connection isn't blocked by the consensus one.
+	done := make(chan error, 1)
+	go func() {
+		_, err := proxyApp.Query().QuerySync(types.RequestQuery{})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Query is blocked by DeliverTx")
+	}
+}
@@ -0,0 +1,277 @@
+package proxy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/types"
+)
+
+// Names of the connections to the application, passed to the interceptors.
+const (
+	ConnConsensus = "consensus"
+	ConnMempool   = "mempool"
+	ConnQuery     = "query"
+)
+
+// SyncInvoker sends a request to the application and waits for its response.
+type SyncInvoker func(req *types.Request) (*types.Response, error)
+
+// SyncInterceptor intercepts the synchronous requests (e.g. Query, Commit)
+// sent to the application on the conn connection. It may send the request,
+// possibly modified, with invoke and handle the response (e.g. logging,
+// metrics), or answer it without invoking the application (e.g. caching,
+// fault injection). The response must be of the type of the request.
+type SyncInterceptor func(conn string, req *types.Request, invoke SyncInvoker) (*types.Response, error)
+
+// AsyncInvoker sends a request to the application without waiting for its
+// response.
+type AsyncInvoker func(req *types.Request) *abcicli.ReqRes
+
+// AsyncInterceptor intercepts the asynchronous requests (CheckTx, DeliverTx
+// and Flush) sent to the application on the conn connection. It may send the
+// request, possibly modified, with invoke. The response is not received yet
+// when invoke returns: use ReqRes.Wait in another goroutine to observe it.
+type AsyncInterceptor func(conn string, req *types.Request, invoke AsyncInvoker) *abcicli.ReqRes
+
+//----------------------------------------------------------------------------------------
+
+// interceptedClient is an abcicli.Client passing the requests through
+// interceptors, the first one being the outermost, like gRPC interceptors.
+type interceptedClient struct {
+	abcicli.Client
+
+	conn        string
+	invokeSync  SyncInvoker
+	invokeAsync AsyncInvoker
+}
+
+// intercept returns client with the interceptors, or client itself if there
+// are none.
+func intercept(conn string, client abcicli.Client,
+	syncInterceptors []SyncInterceptor, asyncInterceptors []AsyncInterceptor) abcicli.Client {
+
+	if len(syncInterceptors) == 0 && len(asyncInterceptors) == 0 {
+		return client
+	}
+	ic := &interceptedClient{Client: client, conn: conn}
+
+	ic.invokeSync = ic.sendSync
+	for i := len(syncInterceptors) - 1; i >= 0; i-- {
+		interceptor, next := syncInterceptors[i], ic.invokeSync
+		ic.invokeSync = func(req *types.Request) (*types.Response, error) {
+			return interceptor(conn, req, next)
+		}
+	}
+
+	ic.invokeAsync = ic.sendAsync
+	for i := len(asyncInterceptors) - 1; i >= 0; i-- {
+		interceptor, next := asyncInterceptors[i], ic.invokeAsync
+		ic.invokeAsync = func(req *types.Request) *abcicli.ReqRes {
+			return interceptor(conn, req, next)
+		}
+	}
+
+	return ic
+}
+
+// sendSync sends req to the application with the underlying client.
+func (ic *interceptedClient) sendSync(req *types.Request) (*types.Response, error) {
+	switch r := req.Value.(type) {
+	case *types.Request_Echo:
+		res, err := ic.Client.EchoSync(r.Echo.Message)
+		return &types.Response{Value: &types.Response_Echo{Echo: res}}, err
+	case *types.Request_Flush:
+		err := ic.Client.FlushSync()
+		return types.ToResponseFlush(), err
+	case *types.Request_Info:
+		res, err := ic.Client.InfoSync(*r.Info)
+		return &types.Response{Value: &types.Response_Info{Info: res}}, err
+	case *types.Request_SetOption:
+		res, err := ic.Client.SetOptionSync(*r.SetOption)
+		return &types.Response{Value: &types.Response_SetOption{SetOption: res}}, err
+	case *types.Request_DeliverTx:
+		res, err := ic.Client.DeliverTxSync(r.DeliverTx.Tx)
+		return &types.Response{Value: &types.Response_DeliverTx{DeliverTx: res}}, err
+	case *types.Request_CheckTx:
+		res, err := ic.Client.CheckTxSync(r.CheckTx.Tx)
+		return &types.Response{Value: &types.Response_CheckTx{CheckTx: res}}, err
+	case *types.Request_Query:
+		res, err := ic.Client.QuerySync(*r.Query)
+		return &types.Response{Value: &types.Response_Query{Query: res}}, err
+	case *types.Request_Commit:
+		res, err := ic.Client.CommitSync()
+		return &types.Response{Value: &types.Response_Commit{Commit: res}}, err
+	case *types.Request_InitChain:
+		res, err := ic.Client.InitChainSync(*r.InitChain)
+		return &types.Response{Value: &types.Response_InitChain{InitChain: res}}, err
+	case *types.Request_BeginBlock:
+		res, err := ic.Client.BeginBlockSync(*r.BeginBlock)
+		return &types.Response{Value: &types.Response_BeginBlock{BeginBlock: res}}, err
+	case *types.Request_EndBlock:
+		res, err := ic.Client.EndBlockSync(*r.EndBlock)
+		return &types.Response{Value: &types.Response_EndBlock{EndBlock: res}}, err
+	default:
+		return nil, fmt.Errorf("Unknown request %v", reflect.TypeOf(req.Value))
+	}
+}
+
+// sendAsync sends req to the application with the underlying client.
+func (ic *interceptedClient) sendAsync(req *types.Request) *abcicli.ReqRes {
+	switch r := req.Value.(type) {
+	case *types.Request_Echo:
+		return ic.Client.EchoAsync(r.Echo.Message)
+	case *types.Request_Flush:
+		return ic.Client.FlushAsync()
+	case *types.Request_Info:
+		return ic.Client.InfoAsync(*r.Info)
+	case *types.Request_SetOption:
+		return ic.Client.SetOptionAsync(*r.SetOption)
+	case *types.Request_DeliverTx:
+		return ic.Client.DeliverTxAsync(r.DeliverTx.Tx)
+	case *types.Request_CheckTx:
+		return ic.Client.CheckTxAsync(r.CheckTx.Tx)
+	case *types.Request_Query:
+		return ic.Client.QueryAsync(*r.Query)
+	case *types.Request_Commit:
+		return ic.Client.CommitAsync()
+	case *types.Request_InitChain:
+		return ic.Client.InitChainAsync(*r.InitChain)
+	case *types.Request_BeginBlock:
+		return ic.Client.BeginBlockAsync(*r.BeginBlock)
+	case *types.Request_EndBlock:
+		return ic.Client.EndBlockAsync(*r.EndBlock)
+	default:
+		panic(fmt.Sprintf("Unknown request %v", reflect.TypeOf(req.Value)))
+	}
+}
+
+// sync sends req through the interceptors, checking the response is of the
+// type of the request.
+func (ic *interceptedClient) sync(req *types.Request) (*types.Response, error) {
+	res, err := ic.invokeSync(req)
+	if err != nil {
+		return nil, err
+	}
+	if !responseMatches(req, res) {
+		return nil, fmt.Errorf("Unexpected response %v to %v on the %s connection",
+			reflect.TypeOf(res.GetValue()), reflect.TypeOf(req.Value), ic.conn)
+	}
+	return res, nil
+}
+
+// responseMatches returns whether res is of the type of req: the types of
+// the values are named Request_Xxx and Response_Xxx.
+func responseMatches(req *types.Request, res *types.Response) bool {
+	if req.GetValue() == nil || res.GetValue() == nil {
+		return false
+	}
+	reqType := reflect.TypeOf(req.Value).Elem().Name()
+	resType := reflect.TypeOf(res.Value).Elem().Name()
+	return strings.TrimPrefix(reqType, "Request_") == strings.TrimPrefix(resType, "Response_")
+}
+
+//----------------------------------------
+
+func (ic *interceptedClient) FlushAsync() *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestFlush())
+}
+
+func (ic *interceptedClient) EchoAsync(msg string) *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestEcho(msg))
+}
+
+func (ic *interceptedClient) InfoAsync(req types.RequestInfo) *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestInfo(req))
+}
+
+func (ic *interceptedClient) SetOptionAsync(req types.RequestSetOption) *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestSetOption(req))
+}
+
+func (ic *interceptedClient) DeliverTxAsync(tx []byte) *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestDeliverTx(tx))
+}
+
+func (ic *interceptedClient) CheckTxAsync(tx []byte) *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestCheckTx(tx))
+}
+
+func (ic *interceptedClient) QueryAsync(req types.RequestQuery) *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestQuery(req))
+}
+
+func (ic *interceptedClient) CommitAsync() *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestCommit())
+}
+
+func (ic *interceptedClient) InitChainAsync(req types.RequestInitChain) *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestInitChain(req))
+}
+
+func (ic *interceptedClient) BeginBlockAsync(req types.RequestBeginBlock) *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestBeginBlock(req))
+}
+
+func (ic *interceptedClient) EndBlockAsync(req types.RequestEndBlock) *abcicli.ReqRes {
+	return ic.invokeAsync(types.ToRequestEndBlock(req))
+}
+
+//----------------------------------------
+
+func (ic *interceptedClient) FlushSync() error {
+	_, err := ic.sync(types.ToRequestFlush())
+	return err
+}
+
+func (ic *interceptedClient) EchoSync(msg string) (*types.ResponseEcho, error) {
+	res, err := ic.sync(types.ToRequestEcho(msg))
+	return res.GetEcho(), err
+}
+
+func (ic *interceptedClient) InfoSync(req types.RequestInfo) (*types.ResponseInfo, error) {
+	res, err := ic.sync(types.ToRequestInfo(req))
+	return res.GetInfo(), err
+}
+
+func (ic *interceptedClient) SetOptionSync(req types.RequestSetOption) (*types.ResponseSetOption, error) {
+	res, err := ic.sync(types.ToRequestSetOption(req))
+	return res.GetSetOption(), err
+}
+
+func (ic *interceptedClient) DeliverTxSync(tx []byte) (*types.ResponseDeliverTx, error) {
+	res, err := ic.sync(types.ToRequestDeliverTx(tx))
+	return res.GetDeliverTx(), err
+}
+
+func (ic *interceptedClient) CheckTxSync(tx []byte) (*types.ResponseCheckTx, error) {
+	res, err := ic.sync(types.ToRequestCheckTx(tx))
+	return res.GetCheckTx(), err
+}
+
+func (ic *interceptedClient) QuerySync(req types.RequestQuery) (*types.ResponseQuery, error) {
+	res, err := ic.sync(types.ToRequestQuery(req))
+	return res.GetQuery(), err
+}
+
+func (ic *interceptedClient) CommitSync() (*types.ResponseCommit, error) {
+	res, err := ic.sync(types.ToRequestCommit())
+	return res.GetCommit(), err
+}
+
+func (ic *interceptedClient) InitChainSync(req types.RequestInitChain) (*types.ResponseInitChain, error) {
+	res, err := ic.sync(types.ToRequestInitChain(req))
+	return res.GetInitChain(), err
+}
+
+func (ic *interceptedClient) BeginBlockSync(req types.RequestBeginBlock) (*types.ResponseBeginBlock, error) {
+	res, err := ic.sync(types.ToRequestBeginBlock(req))
+	return res.GetBeginBlock(), err
+}
+
+func (ic *interceptedClient) EndBlockSync(req types.RequestEndBlock) (*types.ResponseEndBlock, error) {
+	res, err := ic.sync(types.ToRequestEndBlock(req))
+	return res.GetEndBlock(), err
+}
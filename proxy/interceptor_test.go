@@ -0,0 +1,80 @@
+package proxy
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	"github.com/tendermint/tendermint/abci/types"
+)
+
+func TestInterceptors(t *testing.T) {
+	var (
+		mtx   sync.Mutex
+		calls []string
+	)
+	record := func(name string) {
+		mtx.Lock()
+		calls = append(calls, name)
+		mtx.Unlock()
+	}
+
+	logging := func(conn string, req *types.Request, invoke SyncInvoker) (*types.Response, error) {
+		record("logging " + conn)
+		return invoke(req)
+	}
+	// caching answers the queries of "cached" without the application.
+	caching := func(conn string, req *types.Request, invoke SyncInvoker) (*types.Response, error) {
+		record("caching " + conn)
+		if q := req.GetQuery(); q != nil && string(q.Data) == "cached" {
+			return types.ToResponseQuery(types.ResponseQuery{Value: []byte("from cache")}), nil
+		}
+		return invoke(req)
+	}
+	checkTxs := func(conn string, req *types.Request, invoke AsyncInvoker) *abcicli.ReqRes {
+		if req.GetCheckTx() != nil {
+			record("checkTx " + conn)
+		}
+		return invoke(req)
+	}
+
+	proxyApp := NewAppConns(NewLocalClientCreator(kvstore.NewKVStoreApplication()),
+		AppConnsSyncInterceptors(logging, caching),
+		AppConnsAsyncInterceptors(checkTxs),
+	)
+	require.NoError(t, proxyApp.Start())
+	defer proxyApp.Stop()
+
+	res, err := proxyApp.Query().QuerySync(types.RequestQuery{Data: []byte("cached")})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("from cache"), res.Value)
+	assert.Equal(t, []string{"logging query", "caching query"}, calls)
+
+	res, err = proxyApp.Query().QuerySync(types.RequestQuery{Data: []byte("key")})
+	require.NoError(t, err)
+	assert.NotEqual(t, []byte("from cache"), res.Value)
+
+	calls = nil
+	proxyApp.Mempool().CheckTxAsync([]byte("key=value"))
+	require.NoError(t, proxyApp.Mempool().FlushSync())
+	assert.Equal(t, []string{"checkTx mempool", "logging mempool", "caching mempool"}, calls)
+}
+
+func TestInterceptorUnexpectedResponse(t *testing.T) {
+	faulty := func(conn string, req *types.Request, invoke SyncInvoker) (*types.Response, error) {
+		return types.ToResponseFlush(), nil
+	}
+
+	proxyApp := NewAppConns(NewLocalClientCreator(kvstore.NewKVStoreApplication()),
+		AppConnsSyncInterceptors(faulty))
+	require.NoError(t, proxyApp.Start())
+	defer proxyApp.Stop()
+
+	res, err := proxyApp.Query().InfoSync(types.RequestInfo{})
+	assert.Error(t, err)
+	assert.Nil(t, res)
+}
@@ -17,8 +17,27 @@ type AppConns interface {
 	Query() AppConnQuery
 }
 
-func NewAppConns(clientCreator ClientCreator) AppConns {
-	return NewMultiAppConn(clientCreator)
+func NewAppConns(clientCreator ClientCreator, options ...AppConnsOption) AppConns {
+	return NewMultiAppConn(clientCreator, options...)
+}
+
+// AppConnsOption sets an optional parameter on the AppConns.
+type AppConnsOption func(*multiAppConn)
+
+// AppConnsSyncInterceptors appends interceptors for the synchronous requests
+// sent to the application. The first one is the outermost.
+func AppConnsSyncInterceptors(interceptors ...SyncInterceptor) AppConnsOption {
+	return func(app *multiAppConn) {
+		app.syncInterceptors = append(app.syncInterceptors, interceptors...)
+	}
+}
+
+// AppConnsAsyncInterceptors appends interceptors for the asynchronous
+// requests sent to the application. The first one is the outermost.
+func AppConnsAsyncInterceptors(interceptors ...AsyncInterceptor) AppConnsOption {
+	return func(app *multiAppConn) {
+		app.asyncInterceptors = append(app.asyncInterceptors, interceptors...)
+	}
 }
 
 //-----------------------------
@@ -34,15 +53,20 @@ type multiAppConn struct {
 	consensusConn *appConnConsensus
 	queryConn     *appConnQuery
 
-	clientCreator ClientCreator
+	clientCreator     ClientCreator
+	syncInterceptors  []SyncInterceptor
+	asyncInterceptors []AsyncInterceptor
 }
 
 // Make all necessary abci connections to the application
-func NewMultiAppConn(clientCreator ClientCreator) *multiAppConn {
+func NewMultiAppConn(clientCreator ClientCreator, options ...AppConnsOption) *multiAppConn {
 	multiAppConn := &multiAppConn{
 		clientCreator: clientCreator,
 	}
 	multiAppConn.BaseService = *cmn.NewBaseService(nil, "multiAppConn", multiAppConn)
+	for _, option := range options {
+		option(multiAppConn)
+	}
 	return multiAppConn
 }
 
@@ -71,7 +95,7 @@ func (app *multiAppConn) OnStart() error {
 	if err := querycli.Start(); err != nil {
 		return errors.Wrap(err, "Error starting ABCI client (query connection)")
 	}
-	app.queryConn = NewAppConnQuery(querycli)
+	app.queryConn = NewAppConnQuery(intercept(ConnQuery, querycli, app.syncInterceptors, app.asyncInterceptors))
 
 	// mempool connection
 	memcli, err := app.clientCreator.NewABCIClient()
@@ -82,7 +106,7 @@ func (app *multiAppConn) OnStart() error {
 	if err := memcli.Start(); err != nil {
 		return errors.Wrap(err, "Error starting ABCI client (mempool connection)")
 	}
-	app.mempoolConn = NewAppConnMempool(memcli)
+	app.mempoolConn = NewAppConnMempool(intercept(ConnMempool, memcli, app.syncInterceptors, app.asyncInterceptors))
 
 	// consensus connection
 	concli, err := app.clientCreator.NewABCIClient()
@@ -93,7 +117,7 @@ func (app *multiAppConn) OnStart() error {
 	if err := concli.Start(); err != nil {
 		return errors.Wrap(err, "Error starting ABCI client (consensus connection)")
 	}
-	app.consensusConn = NewAppConnConsensus(concli)
+	app.consensusConn = NewAppConnConsensus(intercept(ConnConsensus, concli, app.syncInterceptors, app.asyncInterceptors))
 
 	return nil
 }
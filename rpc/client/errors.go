@@ -0,0 +1,104 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// ABCIError is the error of a CheckTx or DeliverTx result with a non-OK code,
+// which isn't registered in the ABCIErrors.
+type ABCIError struct {
+	Codespace string
+	Code      uint32
+	Log       string
+}
+
+func (e ABCIError) Error() string {
+	if e.Codespace == "" {
+		return fmt.Sprintf("ABCI error code %d: %s", e.Code, e.Log)
+	}
+	return fmt.Sprintf("ABCI error %s/%d: %s", e.Codespace, e.Code, e.Log)
+}
+
+type abciErrorKey struct {
+	codespace string
+	code      uint32
+}
+
+// ABCIErrors maps the codespaces and codes of the CheckTx and DeliverTx
+// results to the Go errors registered by the app, so that the results of the
+// BroadcastTx calls and of Tx can be checked against them:
+//
+//	errs := client.NewABCIErrors()
+//	errs.Register("bank", 1, ErrInsufficientFunds)
+//
+//	res, err := c.BroadcastTxSync(tx)
+//	...
+//	if errs.BroadcastTxError(res) == ErrInsufficientFunds {
+//		...
+//	}
+type ABCIErrors struct {
+	mtx  sync.RWMutex
+	errs map[abciErrorKey]error
+}
+
+// NewABCIErrors returns an empty ABCIErrors.
+func NewABCIErrors() *ABCIErrors {
+	return &ABCIErrors{errs: make(map[abciErrorKey]error)}
+}
+
+// Register registers err as the error of the code in the codespace. It panics
+// if the code is OK, or if an error is already registered for it.
+func (e *ABCIErrors) Register(codespace string, code uint32, err error) {
+	if code == abci.CodeTypeOK {
+		panic("cannot register an error for the OK code")
+	}
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	key := abciErrorKey{codespace, code}
+	if _, ok := e.errs[key]; ok {
+		panic(fmt.Sprintf("error already registered for code %d in codespace %q", code, codespace))
+	}
+	e.errs[key] = err
+}
+
+// Error returns the error registered for the code in the codespace, an
+// ABCIError if there is none, or nil if the code is OK.
+func (e *ABCIErrors) Error(codespace string, code uint32, log string) error {
+	if code == abci.CodeTypeOK {
+		return nil
+	}
+	e.mtx.RLock()
+	err, ok := e.errs[abciErrorKey{codespace, code}]
+	e.mtx.RUnlock()
+	if ok {
+		return err
+	}
+	return ABCIError{Codespace: codespace, Code: code, Log: log}
+}
+
+// BroadcastTxError returns the error of the CheckTx result of a
+// BroadcastTxSync, or nil if it's OK. The BroadcastTxAsync results are always
+// OK, as they don't wait for CheckTx.
+func (e *ABCIErrors) BroadcastTxError(res *ctypes.ResultBroadcastTx) error {
+	return e.Error(res.Codespace, res.Code, res.Log)
+}
+
+// BroadcastTxCommitError returns the error of the CheckTx result of a
+// BroadcastTxCommit if it's not OK, or else the error of its DeliverTx result,
+// or nil if both are OK.
+func (e *ABCIErrors) BroadcastTxCommitError(res *ctypes.ResultBroadcastTxCommit) error {
+	if err := e.Error(res.CheckTx.Codespace, res.CheckTx.Code, res.CheckTx.Log); err != nil {
+		return err
+	}
+	return e.Error(res.DeliverTx.Codespace, res.DeliverTx.Code, res.DeliverTx.Log)
+}
+
+// TxError returns the error of the DeliverTx result of a Tx, or nil if it's
+// OK.
+func (e *ABCIErrors) TxError(res *ctypes.ResultTx) error {
+	return e.Error(res.TxResult.Codespace, res.TxResult.Code, res.TxResult.Log)
+}
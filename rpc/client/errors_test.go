@@ -0,0 +1,42 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+func TestABCIErrors(t *testing.T) {
+	errInsufficientFunds := errors.New("insufficient funds")
+	errs := client.NewABCIErrors()
+	errs.Register("bank", 1, errInsufficientFunds)
+
+	assert.Panics(t, func() { errs.Register("bank", 1, errors.New("other")) })
+	assert.Panics(t, func() { errs.Register("bank", abci.CodeTypeOK, errors.New("ok")) })
+
+	// the codes are scoped by codespace
+	assert.NoError(t, errs.BroadcastTxError(&ctypes.ResultBroadcastTx{}))
+	assert.Equal(t, errInsufficientFunds,
+		errs.BroadcastTxError(&ctypes.ResultBroadcastTx{Codespace: "bank", Code: 1}))
+	assert.Equal(t, client.ABCIError{Codespace: "staking", Code: 1, Log: "unbonding"},
+		errs.BroadcastTxError(&ctypes.ResultBroadcastTx{Codespace: "staking", Code: 1, Log: "unbonding"}))
+
+	// CheckTx errors come first
+	res := &ctypes.ResultBroadcastTxCommit{
+		CheckTx:   abci.ResponseCheckTx{Codespace: "bank", Code: 1},
+		DeliverTx: abci.ResponseDeliverTx{Codespace: "bank", Code: 2},
+	}
+	assert.Equal(t, errInsufficientFunds, errs.BroadcastTxCommitError(res))
+	res.CheckTx = abci.ResponseCheckTx{}
+	assert.Equal(t, client.ABCIError{Codespace: "bank", Code: 2}, errs.BroadcastTxCommitError(res))
+	res.DeliverTx = abci.ResponseDeliverTx{}
+	assert.NoError(t, errs.BroadcastTxCommitError(res))
+
+	assert.Equal(t, errInsufficientFunds,
+		errs.TxError(&ctypes.ResultTx{TxResult: abci.ResponseDeliverTx{Codespace: "bank", Code: 1}}))
+}
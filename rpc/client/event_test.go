@@ -1,6 +1,7 @@
 package client_test
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
@@ -9,6 +10,7 @@ import (
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	cmn "github.com/tendermint/tendermint/libs/common"
+	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
 	"github.com/tendermint/tendermint/rpc/client"
 	"github.com/tendermint/tendermint/types"
 )
@@ -96,7 +98,7 @@ func TestTxEventsSentWithBroadcastTxAsync(t *testing.T) {
 			evtTyp := types.EventTx
 
 			// send async
-			txres, err := c.BroadcastTxAsync(tx)
+			txres, err := c.BroadcastTxAsync(context.Background(), tx)
 			require.Nil(t, err, "%+v", err)
 			require.Equal(t, txres.Code, abci.CodeTypeOK) // FIXME
 
@@ -131,7 +133,7 @@ func TestTxEventsSentWithBroadcastTxSync(t *testing.T) {
 			evtTyp := types.EventTx
 
 			// send sync
-			txres, err := c.BroadcastTxSync(tx)
+			txres, err := c.BroadcastTxSync(context.Background(), tx)
 			require.Nil(t, err, "%+v", err)
 			require.Equal(t, txres.Code, abci.CodeTypeOK) // FIXME
 
@@ -147,3 +149,180 @@ func TestTxEventsSentWithBroadcastTxSync(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscribeEvents(t *testing.T) {
+	for i, c := range GetClients() {
+		i, c := i, c // capture params
+		t.Run(reflect.TypeOf(c).String(), func(t *testing.T) {
+
+			// start for this test it if it wasn't already running
+			if !c.IsRunning() {
+				// if so, then we start it, listen, and stop it.
+				err := c.Start()
+				require.Nil(t, err, "%d: %+v", i, err)
+				defer c.Stop()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), waitForEventTimeout)
+			defer cancel()
+			evts := make(chan client.Event, 1)
+			query := types.QueryForEvent(types.EventNewBlockHeader)
+			err := client.SubscribeEvents(ctx, c, "TestSubscribeEvents", query, evts)
+			require.Nil(t, err, "%d: %+v", i, err)
+
+			select {
+			case evt := <-evts:
+				require.NotNil(t, evt.NewBlockHeader, "%d: %#v", i, evt)
+				require.Nil(t, evt.NewBlock)
+				require.Equal(t, *evt.NewBlockHeader, evt.Data)
+			case <-ctx.Done():
+				t.Fatalf("%d: timed out waiting for event", i)
+			}
+
+			// the events channel is closed once unsubscribed
+			err = c.UnsubscribeAll(ctx, "TestSubscribeEvents")
+			require.Nil(t, err, "%d: %+v", i, err)
+			for range evts {
+			}
+		})
+	}
+}
+
+func TestSubscribeEventsCanceled(t *testing.T) {
+	for i, c := range GetClients() {
+		i, c := i, c // capture params
+		t.Run(reflect.TypeOf(c).String(), func(t *testing.T) {
+
+			// start for this test it if it wasn't already running
+			if !c.IsRunning() {
+				// if so, then we start it, listen, and stop it.
+				err := c.Start()
+				require.Nil(t, err, "%d: %+v", i, err)
+				defer c.Stop()
+			}
+
+			// the events are not read, so the sends to the channel block
+			ctx, cancel := context.WithCancel(context.Background())
+			evts := make(chan client.Event)
+			query := types.QueryForEvent(types.EventNewBlockHeader)
+			err := client.SubscribeEvents(ctx, c, "TestSubscribeEventsCanceled", query, evts)
+			require.Nil(t, err, "%d: %+v", i, err)
+			time.Sleep(100 * time.Millisecond)
+
+			// the events channel is closed once the context is canceled
+			cancel()
+			timeout := time.After(waitForEventTimeout)
+			for done := false; !done; {
+				select {
+				case _, ok := <-evts:
+					done = !ok
+				case <-timeout:
+					t.Fatalf("%d: the events channel was not closed", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSubscribeEventsOrQuery(t *testing.T) {
+	for i, c := range GetClients() {
+		i, c := i, c // capture params
+		t.Run(reflect.TypeOf(c).String(), func(t *testing.T) {
+
+			// start for this test it if it wasn't already running
+			if !c.IsRunning() {
+				// if so, then we start it, listen, and stop it.
+				err := c.Start()
+				require.Nil(t, err, "%d: %+v", i, err)
+				defer c.Stop()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), waitForEventTimeout)
+			defer cancel()
+			evts := make(chan client.Event, 1)
+			query, err := tmquery.NewOr("tm.event='NewBlock' OR tm.event='NewBlockHeader'")
+			require.Nil(t, err)
+			err = client.SubscribeEvents(ctx, c, "TestSubscribeEventsOrQuery", query, evts)
+			require.Nil(t, err, "%d: %+v", i, err)
+
+			// both types of events are received
+			received := make(map[string]bool)
+			for len(received) < 2 {
+				select {
+				case evt := <-evts:
+					require.Contains(t, []string{types.EventNewBlock, types.EventNewBlockHeader}, evt.Type)
+					received[evt.Type] = true
+				case <-ctx.Done():
+					t.Fatalf("%d: timed out waiting for events, got %v", i, received)
+				}
+			}
+
+			err = c.UnsubscribeAll(ctx, "TestSubscribeEventsOrQuery")
+			require.Nil(t, err, "%d: %+v", i, err)
+			for range evts {
+			}
+		})
+	}
+}
+
+func TestSubscribeAllEvents(t *testing.T) {
+	for i, c := range GetClients() {
+		i, c := i, c // capture params
+		t.Run(reflect.TypeOf(c).String(), func(t *testing.T) {
+
+			// start for this test it if it wasn't already running
+			if !c.IsRunning() {
+				// if so, then we start it, listen, and stop it.
+				err := c.Start()
+				require.Nil(t, err, "%d: %+v", i, err)
+				defer c.Stop()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), waitForEventTimeout)
+			defer cancel()
+			evts := make(chan client.Event, 1)
+			err := client.SubscribeAllEvents(ctx, c, "TestSubscribeAllEvents", evts, types.EventNewBlock)
+			require.Nil(t, err, "%d: %+v", i, err)
+
+			// only the events of the given type are received
+			for j := 0; j < 2; j++ {
+				select {
+				case evt := <-evts:
+					require.Equal(t, types.EventNewBlock, evt.Type)
+					require.NotNil(t, evt.NewBlock)
+				case <-ctx.Done():
+					t.Fatalf("%d: timed out waiting for event", i)
+				}
+			}
+
+			err = c.UnsubscribeAll(ctx, "TestSubscribeAllEvents")
+			require.Nil(t, err, "%d: %+v", i, err)
+			for range evts {
+			}
+		})
+	}
+}
+
+func TestSubscribeHeaderOnly(t *testing.T) {
+	c := getHTTPClient()
+	err := c.Start()
+	require.Nil(t, err, "%+v", err)
+	defer c.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitForEventTimeout)
+	defer cancel()
+	query := types.QueryForEvent(types.EventNewBlock)
+	evts := make(chan interface{}, 1)
+	err = c.SubscribeHeaderOnly(ctx, "test-client", query, evts)
+	require.Nil(t, err, "%+v", err)
+	defer c.UnsubscribeAll(ctx, "test-client")
+
+	select {
+	case evt := <-evts:
+		// the NewBlock event is sent without the body of the block
+		_, ok := evt.(types.EventDataNewBlockHeader)
+		require.True(t, ok, "%#v", evt)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the event")
+	}
+}
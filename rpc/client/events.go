@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
+	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Event is an event received by a subscription, with its data set in the
+// field matching its type, so that it can be used without type assertions.
+// Data always holds the data of the event, and is the only field set for the
+// events which don't have a field of their own.
+//
+// Type is the type of the event (e.g. types.EventNewBlock), if it can be told
+// from its data. It is empty for the events with types.EventDataRoundState
+// data, which is shared by several types of events.
+type Event struct {
+	Type string
+
+	NewBlock            *types.EventDataNewBlock
+	NewBlockHeader      *types.EventDataNewBlockHeader
+	Tx                  *types.EventDataTx
+	ValidatorSetUpdates *types.EventDataValidatorSetUpdates
+	NewRound            *types.EventDataNewRound
+	CompleteProposal    *types.EventDataCompleteProposal
+	RoundState          *types.EventDataRoundState
+	Vote                *types.EventDataVote
+
+	Data types.TMEventData
+}
+
+// NewEvent returns the Event for the data received by a subscription.
+func NewEvent(data interface{}) (Event, error) {
+	tmData, ok := data.(types.TMEventData)
+	if !ok {
+		return Event{}, errors.Errorf("unexpected event data %T", data)
+	}
+
+	evt := Event{Data: tmData}
+	switch d := tmData.(type) {
+	case types.EventDataNewBlock:
+		evt.Type, evt.NewBlock = types.EventNewBlock, &d
+	case types.EventDataNewBlockHeader:
+		evt.Type, evt.NewBlockHeader = types.EventNewBlockHeader, &d
+	case types.EventDataTx:
+		evt.Type, evt.Tx = types.EventTx, &d
+	case types.EventDataValidatorSetUpdates:
+		evt.Type, evt.ValidatorSetUpdates = types.EventValidatorSetUpdates, &d
+	case types.EventDataNewRound:
+		evt.Type, evt.NewRound = types.EventNewRound, &d
+	case types.EventDataCompleteProposal:
+		evt.Type, evt.CompleteProposal = types.EventCompleteProposal, &d
+	case types.EventDataRoundState:
+		evt.RoundState = &d
+	case types.EventDataVote:
+		evt.Type, evt.Vote = types.EventVote, &d
+	}
+	return evt, nil
+}
+
+// SubscribeEvents subscribes to the events matching the query, like
+// Subscribe, but sends them to out as Events. Events with unexpected data
+// are dropped. out is closed once the subscription is cancelled, or once ctx
+// is done, which cancels it.
+func SubscribeEvents(ctx context.Context, c EventsClient, subscriber string, query tmpubsub.Query, out chan<- Event) error {
+	return subscribeEvents(ctx, c, subscriber, query, out, func(Event) bool { return true })
+}
+
+// SubscribeAllEvents subscribes to all the events, using a wildcard query,
+// and sends to out those of the given types, or all of them if no type is
+// given. The events with no Type (see Event) are only sent if no type is
+// given. out is closed once the subscription is cancelled, or once ctx is
+// done, which cancels it.
+func SubscribeAllEvents(ctx context.Context, c EventsClient, subscriber string, out chan<- Event, eventTypes ...string) error {
+	wanted := make(map[string]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		wanted[eventType] = true
+	}
+	return subscribeEvents(ctx, c, subscriber, tmquery.Empty{}, out, func(evt Event) bool {
+		return len(wanted) == 0 || wanted[evt.Type]
+	})
+}
+
+func subscribeEvents(
+	ctx context.Context,
+	c EventsClient,
+	subscriber string,
+	query tmpubsub.Query,
+	out chan<- Event,
+	filter func(Event) bool,
+) error {
+	in := make(chan interface{}, cap(out))
+	if err := c.Subscribe(ctx, subscriber, query, in); err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case data, ok := <-in:
+				if !ok {
+					return
+				}
+				evt, err := NewEvent(data)
+				if err != nil || !filter(evt) {
+					continue
+				}
+				select {
+				case out <- evt:
+				case <-ctx.Done():
+					unsubscribeEvents(c, subscriber, query, in)
+					return
+				}
+			case <-ctx.Done():
+				unsubscribeEvents(c, subscriber, query, in)
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// unsubscribeEvents cancels the subscription once its context is done. The
+// events are drained until it is, so that their publisher doesn't block on
+// them.
+func unsubscribeEvents(c EventsClient, subscriber string, query tmpubsub.Query, in <-chan interface{}) {
+	go func() {
+		for range in {
+		}
+	}()
+	go c.Unsubscribe(context.Background(), subscriber, query) // nolint: errcheck
+}
@@ -3,12 +3,14 @@ package client
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
 	amino "github.com/tendermint/go-amino"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
+	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpcclient "github.com/tendermint/tendermint/rpc/lib/client"
 	"github.com/tendermint/tendermint/types"
@@ -45,36 +47,37 @@ func NewHTTP(remote, wsEndpoint string) *HTTP {
 }
 
 var (
-	_ Client        = (*HTTP)(nil)
-	_ NetworkClient = (*HTTP)(nil)
-	_ EventsClient  = (*HTTP)(nil)
+	_ Client         = (*HTTP)(nil)
+	_ NetworkClient  = (*HTTP)(nil)
+	_ EventsClient   = (*HTTP)(nil)
+	_ EvidenceClient = (*HTTP)(nil)
 )
 
-func (c *HTTP) Status() (*ctypes.ResultStatus, error) {
+func (c *HTTP) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
 	result := new(ctypes.ResultStatus)
-	_, err := c.rpc.Call("status", map[string]interface{}{}, result)
+	_, err := c.rpc.CallWithContext(ctx, "status", map[string]interface{}{}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "Status")
 	}
 	return result, nil
 }
 
-func (c *HTTP) ABCIInfo() (*ctypes.ResultABCIInfo, error) {
+func (c *HTTP) ABCIInfo(ctx context.Context) (*ctypes.ResultABCIInfo, error) {
 	result := new(ctypes.ResultABCIInfo)
-	_, err := c.rpc.Call("abci_info", map[string]interface{}{}, result)
+	_, err := c.rpc.CallWithContext(ctx, "abci_info", map[string]interface{}{}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "ABCIInfo")
 	}
 	return result, nil
 }
 
-func (c *HTTP) ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
-	return c.ABCIQueryWithOptions(path, data, DefaultABCIQueryOptions)
+func (c *HTTP) ABCIQuery(ctx context.Context, path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
+	return c.ABCIQueryWithOptions(ctx, path, data, DefaultABCIQueryOptions)
 }
 
-func (c *HTTP) ABCIQueryWithOptions(path string, data cmn.HexBytes, opts ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+func (c *HTTP) ABCIQueryWithOptions(ctx context.Context, path string, data cmn.HexBytes, opts ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
 	result := new(ctypes.ResultABCIQuery)
-	_, err := c.rpc.Call("abci_query",
+	_, err := c.rpc.CallWithContext(ctx, "abci_query",
 		map[string]interface{}{"path": path, "data": data, "height": opts.Height, "prove": opts.Prove},
 		result)
 	if err != nil {
@@ -83,89 +86,135 @@ func (c *HTTP) ABCIQueryWithOptions(path string, data cmn.HexBytes, opts ABCIQue
 	return result, nil
 }
 
-func (c *HTTP) BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+func (c *HTTP) BroadcastTxCommit(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+	return c.BroadcastTxCommitWithMode(ctx, tx, BroadcastTxModeDefault)
+}
+
+// BroadcastTxCommitWithMode is like BroadcastTxCommit, the tx being used by
+// the node with the given BroadcastTxMode*.
+func (c *HTTP) BroadcastTxCommitWithMode(ctx context.Context, tx types.Tx, mode string) (*ctypes.ResultBroadcastTxCommit, error) {
 	result := new(ctypes.ResultBroadcastTxCommit)
-	_, err := c.rpc.Call("broadcast_tx_commit", map[string]interface{}{"tx": tx}, result)
+	_, err := c.rpc.CallWithContext(ctx, "broadcast_tx_commit", broadcastTxParams(tx, mode), result)
 	if err != nil {
 		return nil, errors.Wrap(err, "broadcast_tx_commit")
 	}
 	return result, nil
 }
 
-func (c *HTTP) BroadcastTxAsync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
-	return c.broadcastTX("broadcast_tx_async", tx)
+func (c *HTTP) BroadcastTxAsync(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	return c.broadcastTX(ctx, "broadcast_tx_async", tx, BroadcastTxModeDefault)
+}
+
+// BroadcastTxAsyncWithMode is like BroadcastTxAsync, the tx being used by
+// the node with the given BroadcastTxMode*.
+func (c *HTTP) BroadcastTxAsyncWithMode(ctx context.Context, tx types.Tx, mode string) (*ctypes.ResultBroadcastTx, error) {
+	return c.broadcastTX(ctx, "broadcast_tx_async", tx, mode)
+}
+
+func (c *HTTP) BroadcastTxSync(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	return c.broadcastTX(ctx, "broadcast_tx_sync", tx, BroadcastTxModeDefault)
 }
 
-func (c *HTTP) BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
-	return c.broadcastTX("broadcast_tx_sync", tx)
+// BroadcastTxSyncWithMode is like BroadcastTxSync, the tx being used by the
+// node with the given BroadcastTxMode*.
+func (c *HTTP) BroadcastTxSyncWithMode(ctx context.Context, tx types.Tx, mode string) (*ctypes.ResultBroadcastTx, error) {
+	return c.broadcastTX(ctx, "broadcast_tx_sync", tx, mode)
 }
 
-func (c *HTTP) broadcastTX(route string, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+func (c *HTTP) broadcastTX(ctx context.Context, route string, tx types.Tx, mode string) (*ctypes.ResultBroadcastTx, error) {
 	result := new(ctypes.ResultBroadcastTx)
-	_, err := c.rpc.Call(route, map[string]interface{}{"tx": tx}, result)
+	_, err := c.rpc.CallWithContext(ctx, route, broadcastTxParams(tx, mode), result)
 	if err != nil {
 		return nil, errors.Wrap(err, route)
 	}
 	return result, nil
 }
 
-func (c *HTTP) UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
+// broadcastTxParams returns the params of a /broadcast_tx_* call, the mode
+// being omitted for the nodes which don't know it.
+func broadcastTxParams(tx types.Tx, mode string) map[string]interface{} {
+	params := map[string]interface{}{"tx": tx}
+	if mode != BroadcastTxModeDefault {
+		params["mode"] = mode
+	}
+	return params
+}
+
+func (c *HTTP) UnconfirmedTxs(ctx context.Context, limit int) (*ctypes.ResultUnconfirmedTxs, error) {
 	result := new(ctypes.ResultUnconfirmedTxs)
-	_, err := c.rpc.Call("unconfirmed_txs", map[string]interface{}{"limit": limit}, result)
+	_, err := c.rpc.CallWithContext(ctx, "unconfirmed_txs", map[string]interface{}{"limit": limit}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "unconfirmed_txs")
 	}
 	return result, nil
 }
 
-func (c *HTTP) NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error) {
+func (c *HTTP) NumUnconfirmedTxs(ctx context.Context) (*ctypes.ResultUnconfirmedTxs, error) {
 	result := new(ctypes.ResultUnconfirmedTxs)
-	_, err := c.rpc.Call("num_unconfirmed_txs", map[string]interface{}{}, result)
+	_, err := c.rpc.CallWithContext(ctx, "num_unconfirmed_txs", map[string]interface{}{}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "num_unconfirmed_txs")
 	}
 	return result, nil
 }
 
-func (c *HTTP) NetInfo() (*ctypes.ResultNetInfo, error) {
+func (c *HTTP) NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, error) {
 	result := new(ctypes.ResultNetInfo)
-	_, err := c.rpc.Call("net_info", map[string]interface{}{}, result)
+	_, err := c.rpc.CallWithContext(ctx, "net_info", map[string]interface{}{}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "NetInfo")
 	}
 	return result, nil
 }
 
-func (c *HTTP) DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
+func (c *HTTP) DumpConsensusState(ctx context.Context) (*ctypes.ResultDumpConsensusState, error) {
 	result := new(ctypes.ResultDumpConsensusState)
-	_, err := c.rpc.Call("dump_consensus_state", map[string]interface{}{}, result)
+	_, err := c.rpc.CallWithContext(ctx, "dump_consensus_state", map[string]interface{}{}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "DumpConsensusState")
 	}
 	return result, nil
 }
 
-func (c *HTTP) ConsensusState() (*ctypes.ResultConsensusState, error) {
+func (c *HTTP) ConsensusState(ctx context.Context) (*ctypes.ResultConsensusState, error) {
 	result := new(ctypes.ResultConsensusState)
-	_, err := c.rpc.Call("consensus_state", map[string]interface{}{}, result)
+	_, err := c.rpc.CallWithContext(ctx, "consensus_state", map[string]interface{}{}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "ConsensusState")
 	}
 	return result, nil
 }
 
-func (c *HTTP) Health() (*ctypes.ResultHealth, error) {
+func (c *HTTP) ValidatorUptime(ctx context.Context) (*ctypes.ResultValidatorUptime, error) {
+	result := new(ctypes.ResultValidatorUptime)
+	_, err := c.rpc.CallWithContext(ctx, "validator_uptime", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "ValidatorUptime")
+	}
+	return result, nil
+}
+
+func (c *HTTP) ChainHealth(ctx context.Context, blocks int) (*ctypes.ResultChainHealth, error) {
+	result := new(ctypes.ResultChainHealth)
+	_, err := c.rpc.CallWithContext(ctx, "chain_health", map[string]interface{}{"blocks": blocks}, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "ChainHealth")
+	}
+	return result, nil
+}
+
+func (c *HTTP) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
 	result := new(ctypes.ResultHealth)
-	_, err := c.rpc.Call("health", map[string]interface{}{}, result)
+	_, err := c.rpc.CallWithContext(ctx, "health", map[string]interface{}{}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "Health")
 	}
 	return result, nil
 }
 
-func (c *HTTP) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
+func (c *HTTP) BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
 	result := new(ctypes.ResultBlockchainInfo)
-	_, err := c.rpc.Call("blockchain",
+	_, err := c.rpc.CallWithContext(ctx, "blockchain",
 		map[string]interface{}{"minHeight": minHeight, "maxHeight": maxHeight},
 		result)
 	if err != nil {
@@ -174,56 +223,86 @@ func (c *HTTP) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockch
 	return result, nil
 }
 
-func (c *HTTP) Genesis() (*ctypes.ResultGenesis, error) {
+func (c *HTTP) BlockchainInfoByTime(ctx context.Context, from, to time.Time) (*ctypes.ResultBlockchainInfo, error) {
+	result := new(ctypes.ResultBlockchainInfo)
+	_, err := c.rpc.CallWithContext(ctx, "blockchain_by_time",
+		map[string]interface{}{"from": from, "to": to},
+		result)
+	if err != nil {
+		return nil, errors.Wrap(err, "BlockchainInfoByTime")
+	}
+	return result, nil
+}
+
+func (c *HTTP) HeightAtTime(ctx context.Context, t time.Time) (*ctypes.ResultHeightAtTime, error) {
+	result := new(ctypes.ResultHeightAtTime)
+	_, err := c.rpc.CallWithContext(ctx, "height_at_time", map[string]interface{}{"time": t}, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "HeightAtTime")
+	}
+	return result, nil
+}
+
+func (c *HTTP) Genesis(ctx context.Context) (*ctypes.ResultGenesis, error) {
 	result := new(ctypes.ResultGenesis)
-	_, err := c.rpc.Call("genesis", map[string]interface{}{}, result)
+	_, err := c.rpc.CallWithContext(ctx, "genesis", map[string]interface{}{}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "Genesis")
 	}
 	return result, nil
 }
 
-func (c *HTTP) Block(height *int64) (*ctypes.ResultBlock, error) {
+func (c *HTTP) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
 	result := new(ctypes.ResultBlock)
-	_, err := c.rpc.Call("block", map[string]interface{}{"height": height}, result)
+	_, err := c.rpc.CallWithContext(ctx, "block", map[string]interface{}{"height": height}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "Block")
 	}
 	return result, nil
 }
 
-func (c *HTTP) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
+func (c *HTTP) BlockRaw(ctx context.Context, height *int64) (*ctypes.ResultBlockRaw, error) {
+	result := new(ctypes.ResultBlockRaw)
+	_, err := c.rpc.CallWithContext(ctx, "block_raw", map[string]interface{}{"height": height}, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "BlockRaw")
+	}
+	return result, nil
+}
+
+func (c *HTTP) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
 	result := new(ctypes.ResultBlockResults)
-	_, err := c.rpc.Call("block_results", map[string]interface{}{"height": height}, result)
+	_, err := c.rpc.CallWithContext(ctx, "block_results", map[string]interface{}{"height": height}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "Block Result")
 	}
 	return result, nil
 }
 
-func (c *HTTP) Commit(height *int64) (*ctypes.ResultCommit, error) {
+func (c *HTTP) Commit(ctx context.Context, height *int64) (*ctypes.ResultCommit, error) {
 	result := new(ctypes.ResultCommit)
-	_, err := c.rpc.Call("commit", map[string]interface{}{"height": height}, result)
+	_, err := c.rpc.CallWithContext(ctx, "commit", map[string]interface{}{"height": height}, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "Commit")
 	}
 	return result, nil
 }
 
-func (c *HTTP) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
+func (c *HTTP) Tx(ctx context.Context, hash []byte, prove, proveResult bool) (*ctypes.ResultTx, error) {
 	result := new(ctypes.ResultTx)
 	params := map[string]interface{}{
-		"hash":  hash,
-		"prove": prove,
+		"hash":         hash,
+		"prove":        prove,
+		"prove_result": proveResult,
 	}
-	_, err := c.rpc.Call("tx", params, result)
+	_, err := c.rpc.CallWithContext(ctx, "tx", params, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "Tx")
 	}
 	return result, nil
 }
 
-func (c *HTTP) TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
+func (c *HTTP) TxSearch(ctx context.Context, query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
 	result := new(ctypes.ResultTxSearch)
 	params := map[string]interface{}{
 		"query":    query,
@@ -231,22 +310,67 @@ func (c *HTTP) TxSearch(query string, prove bool, page, perPage int) (*ctypes.Re
 		"page":     page,
 		"per_page": perPage,
 	}
-	_, err := c.rpc.Call("tx_search", params, result)
+	_, err := c.rpc.CallWithContext(ctx, "tx_search", params, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "TxSearch")
 	}
 	return result, nil
 }
 
-func (c *HTTP) Validators(height *int64) (*ctypes.ResultValidators, error) {
+func (c *HTTP) Validators(ctx context.Context, height *int64, page, perPage int) (*ctypes.ResultValidators, error) {
 	result := new(ctypes.ResultValidators)
-	_, err := c.rpc.Call("validators", map[string]interface{}{"height": height}, result)
+	params := map[string]interface{}{
+		"height":   height,
+		"page":     page,
+		"per_page": perPage,
+	}
+	_, err := c.rpc.CallWithContext(ctx, "validators", params, result)
 	if err != nil {
 		return nil, errors.Wrap(err, "Validators")
 	}
 	return result, nil
 }
 
+func (c *HTTP) Evidence(ctx context.Context, height *int64) (*ctypes.ResultEvidence, error) {
+	result := new(ctypes.ResultEvidence)
+	_, err := c.rpc.CallWithContext(ctx, "evidence", map[string]interface{}{"height": height}, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "Evidence")
+	}
+	return result, nil
+}
+
+func (c *HTTP) EvidenceSearch(ctx context.Context, address []byte, page, perPage int) (*ctypes.ResultEvidenceSearch, error) {
+	result := new(ctypes.ResultEvidenceSearch)
+	params := map[string]interface{}{
+		"address":  address,
+		"page":     page,
+		"per_page": perPage,
+	}
+	_, err := c.rpc.CallWithContext(ctx, "evidence_search", params, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "EvidenceSearch")
+	}
+	return result, nil
+}
+
+// PollEvents gets the events of the query after the cursor with /events, by
+// long polling, for the clients which can't use the WebSocket. The cursor of
+// the result is the one of the next call.
+func (c *HTTP) PollEvents(ctx context.Context, query, cursor string, schema int) (*ctypes.ResultEvents, error) {
+	result := new(ctypes.ResultEvents)
+	params := map[string]interface{}{
+		"query":  query,
+		"after":  cursor,
+		"schema": schema,
+	}
+	_, err := c.rpc.CallWithContext(ctx, "events", params, result)
+	if err != nil {
+		return nil, errors.Wrap(err, "PollEvents")
+	}
+	return result, nil
+}
+
 /** websocket event stuff here... **/
 
 type WSEvents struct {
@@ -257,15 +381,27 @@ type WSEvents struct {
 	ws       *rpcclient.WSClient
 
 	mtx           sync.RWMutex
-	subscriptions map[string]chan<- interface{}
+	subscriptions map[string]wsSubscription
 }
 
+// wsSubscription is a subscription of WSEvents, with the params of the
+// subscribe call, to subscribe again with the same options after a
+// reconnection.
+type wsSubscription struct {
+	out    chan<- interface{}
+	params map[string]interface{}
+}
+
+// wsEventsSchema is the version of the schema of the events WSEvents
+// receives: the first one, with tags.
+const wsEventsSchema = 1
+
 func newWSEvents(cdc *amino.Codec, remote, endpoint string) *WSEvents {
 	wsEvents := &WSEvents{
 		cdc:           cdc,
 		endpoint:      endpoint,
 		remote:        remote,
-		subscriptions: make(map[string]chan<- interface{}),
+		subscriptions: make(map[string]wsSubscription),
 	}
 
 	wsEvents.BaseService = *cmn.NewBaseService(nil, "WSEvents", wsEvents)
@@ -296,9 +432,24 @@ func (w *WSEvents) OnStop() {
 }
 
 func (w *WSEvents) Subscribe(ctx context.Context, subscriber string, query tmpubsub.Query, out chan<- interface{}) error {
-	q := query.String()
+	return w.subscribe(ctx, query, out, false)
+}
 
-	err := w.ws.Subscribe(ctx, q)
+// SubscribeHeaderOnly subscribes to the query like Subscribe, but the
+// NewBlock events are sent as types.EventDataNewBlockHeader, without the body
+// of the block, to save bandwidth.
+func (w *WSEvents) SubscribeHeaderOnly(ctx context.Context, subscriber string, query tmpubsub.Query, out chan<- interface{}) error {
+	return w.subscribe(ctx, query, out, true)
+}
+
+func (w *WSEvents) subscribe(ctx context.Context, query tmpubsub.Query, out chan<- interface{}, headerOnly bool) error {
+	q := queryString(query)
+
+	params := map[string]interface{}{"query": q, "schema": wsEventsSchema}
+	if headerOnly {
+		params["header_only"] = true
+	}
+	err := w.ws.Call(ctx, "subscribe", params)
 	if err != nil {
 		return err
 	}
@@ -306,14 +457,14 @@ func (w *WSEvents) Subscribe(ctx context.Context, subscriber string, query tmpub
 	w.mtx.Lock()
 	// subscriber param is ignored because Tendermint will override it with
 	// remote IP anyway.
-	w.subscriptions[q] = out
+	w.subscriptions[q] = wsSubscription{out: out, params: params}
 	w.mtx.Unlock()
 
 	return nil
 }
 
 func (w *WSEvents) Unsubscribe(ctx context.Context, subscriber string, query tmpubsub.Query) error {
-	q := query.String()
+	q := queryString(query)
 
 	err := w.ws.Unsubscribe(ctx, q)
 	if err != nil {
@@ -321,9 +472,9 @@ func (w *WSEvents) Unsubscribe(ctx context.Context, subscriber string, query tmp
 	}
 
 	w.mtx.Lock()
-	ch, ok := w.subscriptions[q]
+	sub, ok := w.subscriptions[q]
 	if ok {
-		close(ch)
+		close(sub.out)
 		delete(w.subscriptions, q)
 	}
 	w.mtx.Unlock()
@@ -331,6 +482,15 @@ func (w *WSEvents) Unsubscribe(ctx context.Context, subscriber string, query tmp
 	return nil
 }
 
+// queryString returns the string of the query sent to the server, which
+// parses it back.
+func queryString(query tmpubsub.Query) string {
+	if q, ok := query.(tmquery.Empty); ok {
+		return q.Wildcard()
+	}
+	return query.String()
+}
+
 func (w *WSEvents) UnsubscribeAll(ctx context.Context, subscriber string) error {
 	err := w.ws.UnsubscribeAll(ctx)
 	if err != nil {
@@ -338,22 +498,30 @@ func (w *WSEvents) UnsubscribeAll(ctx context.Context, subscriber string) error
 	}
 
 	w.mtx.Lock()
-	for _, ch := range w.subscriptions {
-		close(ch)
+	for _, sub := range w.subscriptions {
+		close(sub.out)
 	}
-	w.subscriptions = make(map[string]chan<- interface{})
+	w.subscriptions = make(map[string]wsSubscription)
 	w.mtx.Unlock()
 
 	return nil
 }
 
 // After being reconnected, it is necessary to redo subscription to server
-// otherwise no data will be automatically received.
+// otherwise no data will be automatically received. The subscriptions are
+// made again with their options.
 func (w *WSEvents) redoSubscriptions() {
-	for q := range w.subscriptions {
+	w.mtx.RLock()
+	params := make([]map[string]interface{}, 0, len(w.subscriptions))
+	for _, sub := range w.subscriptions {
+		params = append(params, sub.params)
+	}
+	w.mtx.RUnlock()
+
+	for _, p := range params {
 		// NOTE: no timeout for resubscribing
 		// FIXME: better logging/handling of errors??
-		w.ws.Subscribe(context.Background(), q)
+		w.ws.Call(context.Background(), "subscribe", p)
 	}
 }
 
@@ -381,8 +549,10 @@ func (w *WSEvents) eventListener() {
 			// NOTE: writing also happens inside mutex so we can't close a channel in
 			// Unsubscribe/UnsubscribeAll.
 			w.mtx.RLock()
-			if ch, ok := w.subscriptions[result.Query]; ok {
-				ch <- result.Data
+			if sub, ok := w.subscriptions[result.Query]; ok {
+				// the events are received in the first version of the schema,
+				// with tags
+				sub.out <- ctypes.EventDataWithEvents(result.Data)
 			}
 			w.mtx.RUnlock()
 		case <-w.Quit():
@@ -0,0 +1,81 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
+	"github.com/tendermint/tendermint/rpc/client"
+)
+
+// subscribeRecorder is a websocket server recording the params of the
+// subscribe calls, which drops the connection after the first one.
+type subscribeRecorder struct {
+	params chan map[string]interface{}
+
+	mtx     sync.Mutex
+	dropped bool
+}
+
+func (s *subscribeRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close() // nolint: errcheck
+	for {
+		var req struct {
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		if req.Method != "subscribe" {
+			continue
+		}
+		s.params <- req.Params
+		s.mtx.Lock()
+		drop := !s.dropped
+		s.dropped = true
+		s.mtx.Unlock()
+		if drop {
+			return
+		}
+	}
+}
+
+func TestWSEventsResubscribeWithOptions(t *testing.T) {
+	recorder := &subscribeRecorder{params: make(chan map[string]interface{}, 2)}
+	s := httptest.NewServer(recorder)
+	defer s.Close()
+
+	c := client.NewHTTP("tcp://"+s.Listener.Addr().String(), "/websocket")
+	require.NoError(t, c.Start())
+	defer c.Stop()
+
+	query := tmquery.MustParse("tm.event = 'NewBlock'")
+	out := make(chan interface{})
+	require.NoError(t, c.SubscribeHeaderOnly(context.Background(), "test", query, out))
+
+	var params []map[string]interface{}
+	for len(params) < 2 {
+		select {
+		case p := <-recorder.params:
+			params = append(params, p)
+		case <-time.After(10 * time.Second):
+			t.Fatal("Timed out waiting for the subscriptions")
+		}
+	}
+	assert.Equal(t, true, params[0]["header_only"])
+	assert.Equal(t, params[0], params[1], "the subscription is made again with its options")
+}
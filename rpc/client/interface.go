@@ -18,9 +18,14 @@ In addition to the Client interface, which should be used externally
 for maximum flexibility and testability, and two implementations,
 this package also provides helper functions that work on any Client
 implementation.
+
+The methods take a context.Context, which cancels the requests of client.HTTP
+and the ones waiting on the node, e.g. BroadcastTxCommit.
 */
 
 import (
+	"context"
+
 	cmn "github.com/tendermint/tendermint/libs/common"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	"github.com/tendermint/tendermint/types"
@@ -31,37 +36,37 @@ import (
 // so we can accept an interface which is easier to mock
 type ABCIClient interface {
 	// Reading from abci app
-	ABCIInfo() (*ctypes.ResultABCIInfo, error)
-	ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error)
-	ABCIQueryWithOptions(path string, data cmn.HexBytes,
+	ABCIInfo(ctx context.Context) (*ctypes.ResultABCIInfo, error)
+	ABCIQuery(ctx context.Context, path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error)
+	ABCIQueryWithOptions(ctx context.Context, path string, data cmn.HexBytes,
 		opts ABCIQueryOptions) (*ctypes.ResultABCIQuery, error)
 
 	// Writing to abci app
-	BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error)
-	BroadcastTxAsync(tx types.Tx) (*ctypes.ResultBroadcastTx, error)
-	BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error)
+	BroadcastTxCommit(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error)
+	BroadcastTxAsync(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error)
+	BroadcastTxSync(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error)
 }
 
 // SignClient groups together the interfaces need to get valid
 // signatures and prove anything about the chain
 type SignClient interface {
-	Block(height *int64) (*ctypes.ResultBlock, error)
-	BlockResults(height *int64) (*ctypes.ResultBlockResults, error)
-	Commit(height *int64) (*ctypes.ResultCommit, error)
-	Validators(height *int64) (*ctypes.ResultValidators, error)
-	Tx(hash []byte, prove bool) (*ctypes.ResultTx, error)
-	TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error)
+	Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error)
+	BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error)
+	Commit(ctx context.Context, height *int64) (*ctypes.ResultCommit, error)
+	Validators(ctx context.Context, height *int64, page, perPage int) (*ctypes.ResultValidators, error)
+	Tx(ctx context.Context, hash []byte, prove, proveResult bool) (*ctypes.ResultTx, error)
+	TxSearch(ctx context.Context, query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error)
 }
 
 // HistoryClient shows us data from genesis to now in large chunks.
 type HistoryClient interface {
-	Genesis() (*ctypes.ResultGenesis, error)
-	BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error)
+	Genesis(ctx context.Context) (*ctypes.ResultGenesis, error)
+	BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error)
 }
 
 type StatusClient interface {
 	// General chain info
-	Status() (*ctypes.ResultStatus, error)
+	Status(ctx context.Context) (*ctypes.ResultStatus, error)
 }
 
 // Client wraps most important rpc calls a client would make
@@ -82,10 +87,12 @@ type Client interface {
 // Not included in the Client interface, but generally implemented
 // by concrete implementations.
 type NetworkClient interface {
-	NetInfo() (*ctypes.ResultNetInfo, error)
-	DumpConsensusState() (*ctypes.ResultDumpConsensusState, error)
-	ConsensusState() (*ctypes.ResultConsensusState, error)
-	Health() (*ctypes.ResultHealth, error)
+	NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, error)
+	DumpConsensusState(ctx context.Context) (*ctypes.ResultDumpConsensusState, error)
+	ConsensusState(ctx context.Context) (*ctypes.ResultConsensusState, error)
+	ValidatorUptime(ctx context.Context) (*ctypes.ResultValidatorUptime, error)
+	ChainHealth(ctx context.Context, blocks int) (*ctypes.ResultChainHealth, error)
+	Health(ctx context.Context) (*ctypes.ResultHealth, error)
 }
 
 // EventsClient is reactive, you can subscribe to any message, given the proper
@@ -94,8 +101,17 @@ type EventsClient interface {
 	types.EventBusSubscriber
 }
 
+// EvidenceClient shows us the evidence committed in the blocks.
+//
+// Not included in the Client interface, but generally implemented
+// by concrete implementations.
+type EvidenceClient interface {
+	Evidence(ctx context.Context, height *int64) (*ctypes.ResultEvidence, error)
+	EvidenceSearch(ctx context.Context, address []byte, page, perPage int) (*ctypes.ResultEvidenceSearch, error)
+}
+
 // MempoolClient shows us data about current mempool state.
 type MempoolClient interface {
-	UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error)
-	NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error)
+	UnconfirmedTxs(ctx context.Context, limit int) (*ctypes.ResultUnconfirmedTxs, error)
+	NumUnconfirmedTxs(ctx context.Context) (*ctypes.ResultUnconfirmedTxs, error)
 }
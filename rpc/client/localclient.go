@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"time"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
@@ -24,120 +25,240 @@ are compiled in process.
 
 For real clients, you probably want to use client.HTTP.  For more
 powerful control during testing, you probably want the "client/mock" package.
+
+A call isn't made if its context is already canceled. The long-running calls
+(BroadcastTxCommit, TxSearch, ChainHealth...) also stop once it's canceled
+meanwhile.
 */
 type Local struct {
 	*types.EventBus
+	env *core.Environment
 }
 
-// NewLocal configures a client that calls the Node directly.
-//
-// Note that given how rpc/core works with package singletons, that
-// you can only have one node per process.  So make sure test cases
-// don't run in parallel, or try to simulate an entire network in
-// one process...
+// NewLocal configures a client that calls the Node directly, through the RPC
+// environment of the node.
 func NewLocal(node *nm.Node) *Local {
-	node.ConfigureRPC()
 	return &Local{
 		EventBus: node.EventBus(),
+		env:      node.ConfigureRPC(),
 	}
 }
 
 var (
-	_ Client        = (*Local)(nil)
-	_ NetworkClient = Local{}
-	_ EventsClient  = (*Local)(nil)
+	_ Client         = (*Local)(nil)
+	_ NetworkClient  = Local{}
+	_ EventsClient   = (*Local)(nil)
+	_ EvidenceClient = Local{}
 )
 
-func (Local) Status() (*ctypes.ResultStatus, error) {
-	return core.Status()
+func (c Local) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.Status()
 }
 
-func (Local) ABCIInfo() (*ctypes.ResultABCIInfo, error) {
-	return core.ABCIInfo()
+func (c Local) ABCIInfo(ctx context.Context) (*ctypes.ResultABCIInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.ABCIInfo()
 }
 
-func (c *Local) ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
-	return c.ABCIQueryWithOptions(path, data, DefaultABCIQueryOptions)
+func (c *Local) ABCIQuery(ctx context.Context, path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
+	return c.ABCIQueryWithOptions(ctx, path, data, DefaultABCIQueryOptions)
 }
 
-func (Local) ABCIQueryWithOptions(path string, data cmn.HexBytes, opts ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
-	return core.ABCIQuery(path, data, opts.Height, opts.Prove)
+func (c Local) ABCIQueryWithOptions(ctx context.Context, path string, data cmn.HexBytes, opts ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	return c.env.ABCIQuery(ctx, path, data, opts.Height, opts.Prove)
 }
 
-func (Local) BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
-	return core.BroadcastTxCommit(tx)
+func (c Local) BroadcastTxCommit(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+	return c.BroadcastTxCommitWithMode(ctx, tx, BroadcastTxModeDefault)
 }
 
-func (Local) BroadcastTxAsync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
-	return core.BroadcastTxAsync(tx)
+// BroadcastTxCommitWithMode is like BroadcastTxCommit, the tx being used by
+// the node with the given BroadcastTxMode*.
+func (c Local) BroadcastTxCommitWithMode(ctx context.Context, tx types.Tx, mode string) (*ctypes.ResultBroadcastTxCommit, error) {
+	return c.env.BroadcastTxCommit(ctx, tx, mode)
 }
 
-func (Local) BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
-	return core.BroadcastTxSync(tx)
+func (c Local) BroadcastTxAsync(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	return c.BroadcastTxAsyncWithMode(ctx, tx, BroadcastTxModeDefault)
 }
 
-func (Local) UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
-	return core.UnconfirmedTxs(limit)
+// BroadcastTxAsyncWithMode is like BroadcastTxAsync, the tx being used by
+// the node with the given BroadcastTxMode*.
+func (c Local) BroadcastTxAsyncWithMode(ctx context.Context, tx types.Tx, mode string) (*ctypes.ResultBroadcastTx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.BroadcastTxAsync(tx, mode)
 }
 
-func (Local) NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error) {
-	return core.NumUnconfirmedTxs()
+func (c Local) BroadcastTxSync(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	return c.BroadcastTxSyncWithMode(ctx, tx, BroadcastTxModeDefault)
 }
 
-func (Local) NetInfo() (*ctypes.ResultNetInfo, error) {
-	return core.NetInfo()
+// BroadcastTxSyncWithMode is like BroadcastTxSync, the tx being used by the
+// node with the given BroadcastTxMode*.
+func (c Local) BroadcastTxSyncWithMode(ctx context.Context, tx types.Tx, mode string) (*ctypes.ResultBroadcastTx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.BroadcastTxSync(tx, mode)
 }
 
-func (Local) DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
-	return core.DumpConsensusState()
+func (c Local) UnconfirmedTxs(ctx context.Context, limit int) (*ctypes.ResultUnconfirmedTxs, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.UnconfirmedTxs(limit, "", 0, 0, "")
 }
 
-func (Local) ConsensusState() (*ctypes.ResultConsensusState, error) {
-	return core.ConsensusState()
+func (c Local) NumUnconfirmedTxs(ctx context.Context) (*ctypes.ResultUnconfirmedTxs, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.NumUnconfirmedTxs()
 }
 
-func (Local) Health() (*ctypes.ResultHealth, error) {
-	return core.Health()
+func (c Local) NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.NetInfo()
 }
 
-func (Local) DialSeeds(seeds []string) (*ctypes.ResultDialSeeds, error) {
-	return core.UnsafeDialSeeds(seeds)
+func (c Local) DumpConsensusState(ctx context.Context) (*ctypes.ResultDumpConsensusState, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.DumpConsensusState()
 }
 
-func (Local) DialPeers(peers []string, persistent bool) (*ctypes.ResultDialPeers, error) {
-	return core.UnsafeDialPeers(peers, persistent)
+func (c Local) ConsensusState(ctx context.Context) (*ctypes.ResultConsensusState, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.ConsensusState()
 }
 
-func (Local) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
-	return core.BlockchainInfo(minHeight, maxHeight)
+func (c Local) ValidatorUptime(ctx context.Context) (*ctypes.ResultValidatorUptime, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.ValidatorUptime()
 }
 
-func (Local) Genesis() (*ctypes.ResultGenesis, error) {
-	return core.Genesis()
+func (c Local) ChainHealth(ctx context.Context, blocks int) (*ctypes.ResultChainHealth, error) {
+	return c.env.ChainHealth(ctx, blocks)
 }
 
-func (Local) Block(height *int64) (*ctypes.ResultBlock, error) {
-	return core.Block(height)
+func (c Local) Health(ctx context.Context) (*ctypes.ResultHealth, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.Health()
 }
 
-func (Local) BlockResults(height *int64) (*ctypes.ResultBlockResults, error) {
-	return core.BlockResults(height)
+func (c Local) DialSeeds(ctx context.Context, seeds []string) (*ctypes.ResultDialSeeds, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.UnsafeDialSeeds(seeds)
 }
 
-func (Local) Commit(height *int64) (*ctypes.ResultCommit, error) {
-	return core.Commit(height)
+func (c Local) DialPeers(ctx context.Context, peers []string, persistent bool) (*ctypes.ResultDialPeers, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.UnsafeDialPeers(peers, persistent)
 }
 
-func (Local) Validators(height *int64) (*ctypes.ResultValidators, error) {
-	return core.Validators(height)
+func (c Local) BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.BlockchainInfo(minHeight, maxHeight)
 }
 
-func (Local) Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
-	return core.Tx(hash, prove)
+func (c Local) BlockchainInfoByTime(ctx context.Context, from, to time.Time) (*ctypes.ResultBlockchainInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.BlockchainInfoByTime(from, to)
 }
 
-func (Local) TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
-	return core.TxSearch(query, prove, page, perPage)
+func (c Local) HeightAtTime(ctx context.Context, t time.Time) (*ctypes.ResultHeightAtTime, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.HeightAtTime(t)
+}
+
+func (c Local) Genesis(ctx context.Context) (*ctypes.ResultGenesis, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.Genesis()
+}
+
+func (c Local) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.Block(height)
+}
+
+func (c Local) BlockRaw(ctx context.Context, height *int64) (*ctypes.ResultBlockRaw, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.BlockRaw(height)
+}
+
+func (c Local) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	return c.env.BlockResults(ctx, height)
+}
+
+func (c Local) Commit(ctx context.Context, height *int64) (*ctypes.ResultCommit, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.Commit(height)
+}
+
+func (c Local) Validators(ctx context.Context, height *int64, page, perPage int) (*ctypes.ResultValidators, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.Validators(height, page, perPage)
+}
+
+func (c Local) Tx(ctx context.Context, hash []byte, prove, proveResult bool) (*ctypes.ResultTx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.Tx(hash, prove, proveResult)
+}
+
+func (c Local) TxSearch(ctx context.Context, query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
+	return c.env.TxSearch(ctx, query, prove, page, perPage)
+}
+
+func (c Local) Evidence(ctx context.Context, height *int64) (*ctypes.ResultEvidence, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.Evidence(height)
+}
+
+func (c Local) EvidenceSearch(ctx context.Context, address []byte, page, perPage int) (*ctypes.ResultEvidenceSearch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.env.EvidenceSearch(address, page, perPage)
 }
 
 func (c *Local) Subscribe(ctx context.Context, subscriber string, query tmpubsub.Query, out chan<- interface{}) error {
@@ -15,6 +15,8 @@ want to directly call a tendermint node in process, you can use the
 */
 
 import (
+	"context"
+
 	"reflect"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -28,7 +30,8 @@ import (
 //
 // We provide a few choices to mock out each one in this package.
 // Nothing hidden here, so no New function, just construct it from
-// some parts, and swap them out them during the tests.
+// some parts, and swap them out them during the tests. The calls which
+// can't be mocked are made to the RPC environment of a node, Env.
 type Client struct {
 	client.ABCIClient
 	client.SignClient
@@ -36,6 +39,8 @@ type Client struct {
 	client.StatusClient
 	client.EventsClient
 	cmn.Service
+
+	Env *core.Environment
 }
 
 var _ client.Client = Client{}
@@ -75,62 +80,62 @@ func (c Call) GetResponse(args interface{}) (interface{}, error) {
 	return nil, c.Error
 }
 
-func (c Client) Status() (*ctypes.ResultStatus, error) {
-	return core.Status()
+func (c Client) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	return c.Env.Status()
 }
 
-func (c Client) ABCIInfo() (*ctypes.ResultABCIInfo, error) {
-	return core.ABCIInfo()
+func (c Client) ABCIInfo(ctx context.Context) (*ctypes.ResultABCIInfo, error) {
+	return c.Env.ABCIInfo()
 }
 
-func (c Client) ABCIQuery(path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
-	return c.ABCIQueryWithOptions(path, data, client.DefaultABCIQueryOptions)
+func (c Client) ABCIQuery(ctx context.Context, path string, data cmn.HexBytes) (*ctypes.ResultABCIQuery, error) {
+	return c.ABCIQueryWithOptions(ctx, path, data, client.DefaultABCIQueryOptions)
 }
 
-func (c Client) ABCIQueryWithOptions(path string, data cmn.HexBytes, opts client.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
-	return core.ABCIQuery(path, data, opts.Height, opts.Prove)
+func (c Client) ABCIQueryWithOptions(ctx context.Context, path string, data cmn.HexBytes, opts client.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	return c.Env.ABCIQuery(ctx, path, data, opts.Height, opts.Prove)
 }
 
-func (c Client) BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
-	return core.BroadcastTxCommit(tx)
+func (c Client) BroadcastTxCommit(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+	return c.Env.BroadcastTxCommit(ctx, tx, "")
 }
 
-func (c Client) BroadcastTxAsync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
-	return core.BroadcastTxAsync(tx)
+func (c Client) BroadcastTxAsync(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	return c.Env.BroadcastTxAsync(tx, "")
 }
 
-func (c Client) BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
-	return core.BroadcastTxSync(tx)
+func (c Client) BroadcastTxSync(ctx context.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+	return c.Env.BroadcastTxSync(tx, "")
 }
 
-func (c Client) NetInfo() (*ctypes.ResultNetInfo, error) {
-	return core.NetInfo()
+func (c Client) NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, error) {
+	return c.Env.NetInfo()
 }
 
-func (c Client) DialSeeds(seeds []string) (*ctypes.ResultDialSeeds, error) {
-	return core.UnsafeDialSeeds(seeds)
+func (c Client) DialSeeds(ctx context.Context, seeds []string) (*ctypes.ResultDialSeeds, error) {
+	return c.Env.UnsafeDialSeeds(seeds)
 }
 
-func (c Client) DialPeers(peers []string, persistent bool) (*ctypes.ResultDialPeers, error) {
-	return core.UnsafeDialPeers(peers, persistent)
+func (c Client) DialPeers(ctx context.Context, peers []string, persistent bool) (*ctypes.ResultDialPeers, error) {
+	return c.Env.UnsafeDialPeers(peers, persistent)
 }
 
-func (c Client) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
-	return core.BlockchainInfo(minHeight, maxHeight)
+func (c Client) BlockchainInfo(ctx context.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
+	return c.Env.BlockchainInfo(minHeight, maxHeight)
 }
 
-func (c Client) Genesis() (*ctypes.ResultGenesis, error) {
-	return core.Genesis()
+func (c Client) Genesis(ctx context.Context) (*ctypes.ResultGenesis, error) {
+	return c.Env.Genesis()
 }
 
-func (c Client) Block(height *int64) (*ctypes.ResultBlock, error) {
-	return core.Block(height)
+func (c Client) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	return c.Env.Block(height)
 }
 
-func (c Client) Commit(height *int64) (*ctypes.ResultCommit, error) {
-	return core.Commit(height)
+func (c Client) Commit(ctx context.Context, height *int64) (*ctypes.ResultCommit, error) {
+	return c.Env.Commit(height)
 }
 
-func (c Client) Validators(height *int64) (*ctypes.ResultValidators, error) {
-	return core.Validators(height)
+func (c Client) Validators(ctx context.Context, height *int64, page, perPage int) (*ctypes.ResultValidators, error) {
+	return c.Env.Validators(height, page, perPage)
 }
@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"context"
+
 	"github.com/tendermint/tendermint/rpc/client"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 )
@@ -15,7 +17,7 @@ var (
 	_ client.StatusClient = (*StatusRecorder)(nil)
 )
 
-func (m *StatusMock) Status() (*ctypes.ResultStatus, error) {
+func (m *StatusMock) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
 	res, err := m.GetResponse(nil)
 	if err != nil {
 		return nil, err
@@ -41,8 +43,8 @@ func (r *StatusRecorder) addCall(call Call) {
 	r.Calls = append(r.Calls, call)
 }
 
-func (r *StatusRecorder) Status() (*ctypes.ResultStatus, error) {
-	res, err := r.Client.Status()
+func (r *StatusRecorder) Status(ctx context.Context) (*ctypes.ResultStatus, error) {
+	res, err := r.Client.Status(ctx)
 	r.addCall(Call{
 		Name:     "status",
 		Response: res,
@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// PageOptions configures the walk of the pages of a paginated query.
+type PageOptions struct {
+	// Number of results per page, 0 for the default of the node.
+	PerPage int
+	// Minimum time between the requests of two pages, to rate limit them.
+	Interval time.Duration
+}
+
+// pager walks the pages of a query, from the first one, until it fetched the
+// total count of results, or an empty page.
+type pager struct {
+	ctx  context.Context
+	opts PageOptions
+
+	page      int // last fetched, 0 before the first one
+	fetched   int // results fetched so far
+	total     int // results of the query, as of the last page
+	lastFetch time.Time
+	err       error
+}
+
+// next fetches the next page with fetch, which returns the number of results
+// of the page and the total count of them, and returns true if it has any.
+func (p *pager) next(fetch func(page int) (count, total int, err error)) bool {
+	if p.err != nil || p.page > 0 && p.fetched >= p.total {
+		return false
+	}
+	if p.err = p.ctx.Err(); p.err != nil {
+		return false
+	}
+	if wait := p.opts.Interval - time.Since(p.lastFetch); p.page > 0 && wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-p.ctx.Done():
+			timer.Stop()
+			p.err = p.ctx.Err()
+			return false
+		}
+	}
+
+	p.lastFetch = time.Now()
+	count, total, err := fetch(p.page + 1)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	// The node returns the last page again for the pages after it, hence the
+	// check of the total above, which may shrink between two pages.
+	if count == 0 {
+		return false
+	}
+	p.page++
+	p.fetched += count
+	p.total = total
+	return true
+}
+
+// TxSearchPager iterates over the pages of a /tx_search:
+//
+//	pages := client.TxSearchPages(ctx, c, query, false, client.PageOptions{})
+//	for pages.Next() {
+//		for _, tx := range pages.Page().Txs {
+//			...
+//		}
+//	}
+//	if err := pages.Err(); err != nil {
+//		...
+//	}
+type TxSearchPager struct {
+	pager
+	c     SignClient
+	query string
+	prove bool
+	res   *ctypes.ResultTxSearch
+}
+
+// TxSearchPages returns a TxSearchPager over the results of the query.
+func TxSearchPages(ctx context.Context, c SignClient, query string, prove bool, opts PageOptions) *TxSearchPager {
+	return &TxSearchPager{
+		pager: pager{ctx: ctx, opts: opts},
+		c:     c,
+		query: query,
+		prove: prove,
+	}
+}
+
+// Next fetches the next page, and returns false once there is none, or on an
+// error, returned by Err.
+func (p *TxSearchPager) Next() bool {
+	return p.next(func(page int) (int, int, error) {
+		res, err := p.c.TxSearch(p.ctx, p.query, p.prove, page, p.opts.PerPage)
+		if err != nil {
+			return 0, 0, err
+		}
+		p.res = res
+		return len(res.Txs), res.TotalCount, nil
+	})
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *TxSearchPager) Page() *ctypes.ResultTxSearch {
+	return p.res
+}
+
+// Err returns the error which stopped the iteration, if any, e.g. the error
+// of the context.
+func (p *TxSearchPager) Err() error {
+	return p.err
+}
+
+// ValidatorsPager iterates over the pages of the /validators of a height, as
+// the TxSearchPager does over the ones of a /tx_search.
+type ValidatorsPager struct {
+	pager
+	c      SignClient
+	height *int64
+	res    *ctypes.ResultValidators
+}
+
+// ValidatorsPages returns a ValidatorsPager over the validators of the height,
+// nil for the latest one.
+func ValidatorsPages(ctx context.Context, c SignClient, height *int64, opts PageOptions) *ValidatorsPager {
+	return &ValidatorsPager{
+		pager:  pager{ctx: ctx, opts: opts},
+		c:      c,
+		height: height,
+	}
+}
+
+// Next fetches the next page, and returns false once there is none, or on an
+// error, returned by Err. All the pages are of the height of the first one.
+func (p *ValidatorsPager) Next() bool {
+	return p.next(func(page int) (int, int, error) {
+		res, err := p.c.Validators(p.ctx, p.height, page, p.opts.PerPage)
+		if err != nil {
+			return 0, 0, err
+		}
+		p.res = res
+		height := res.BlockHeight
+		p.height = &height
+		return len(res.Validators), res.Total, nil
+	})
+}
+
+// Page returns the page fetched by the last call to Next.
+func (p *ValidatorsPager) Page() *ctypes.ResultValidators {
+	return p.res
+}
+
+// Err returns the error which stopped the iteration, if any, e.g. the error
+// of the context.
+func (p *ValidatorsPager) Err() error {
+	return p.err
+}
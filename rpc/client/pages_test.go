@@ -0,0 +1,115 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// pagedClient pages txs and validators as the node does, returning the last
+// page for the pages after it.
+type pagedClient struct {
+	client.SignClient
+	txs      int
+	vals     int
+	requests []int
+}
+
+func (c *pagedClient) paginate(total, page, perPage int) (from, to int) {
+	c.requests = append(c.requests, page)
+	if perPage < 1 {
+		perPage = 30
+	}
+	if pages := (total-1)/perPage + 1; page > pages {
+		page = pages
+	}
+	from = (page - 1) * perPage
+	to = from + perPage
+	if to > total {
+		to = total
+	}
+	return from, to
+}
+
+func (c *pagedClient) TxSearch(ctx context.Context, query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
+	from, to := c.paginate(c.txs, page, perPage)
+	res := &ctypes.ResultTxSearch{TotalCount: c.txs}
+	for i := from; i < to; i++ {
+		res.Txs = append(res.Txs, &ctypes.ResultTx{Index: uint32(i)})
+	}
+	return res, nil
+}
+
+func (c *pagedClient) Validators(ctx context.Context, height *int64, page, perPage int) (*ctypes.ResultValidators, error) {
+	from, to := c.paginate(c.vals, page, perPage)
+	res := &ctypes.ResultValidators{BlockHeight: 10, Total: c.vals}
+	for i := from; i < to; i++ {
+		res.Validators = append(res.Validators, &types.Validator{VotingPower: int64(i)})
+	}
+	res.Count = len(res.Validators)
+	return res, nil
+}
+
+func TestTxSearchPages(t *testing.T) {
+	c := &pagedClient{txs: 25}
+	pages := client.TxSearchPages(context.Background(), c, "tm.height > 0", false, client.PageOptions{PerPage: 10})
+	var indexes []uint32
+	for pages.Next() {
+		for _, tx := range pages.Page().Txs {
+			indexes = append(indexes, tx.Index)
+		}
+	}
+	require.NoError(t, pages.Err())
+	require.Len(t, indexes, 25)
+	for i, index := range indexes {
+		assert.EqualValues(t, i, index)
+	}
+	// the last page is not fetched again
+	assert.Equal(t, []int{1, 2, 3}, c.requests)
+	assert.False(t, pages.Next())
+
+	// no results
+	c = &pagedClient{}
+	pages = client.TxSearchPages(context.Background(), c, "tm.height > 0", false, client.PageOptions{})
+	assert.False(t, pages.Next())
+	assert.NoError(t, pages.Err())
+}
+
+func TestValidatorsPages(t *testing.T) {
+	c := &pagedClient{vals: 5}
+	pages := client.ValidatorsPages(context.Background(), c, nil, client.PageOptions{PerPage: 2})
+	count := 0
+	for pages.Next() {
+		assert.EqualValues(t, 10, pages.Page().BlockHeight)
+		count += len(pages.Page().Validators)
+	}
+	require.NoError(t, pages.Err())
+	assert.Equal(t, 5, count)
+	assert.Equal(t, []int{1, 2, 3}, c.requests)
+}
+
+func TestPagesRateLimitAndContext(t *testing.T) {
+	c := &pagedClient{txs: 3}
+	start := time.Now()
+	pages := client.TxSearchPages(context.Background(), c, "tm.height > 0", false,
+		client.PageOptions{PerPage: 1, Interval: 20 * time.Millisecond})
+	for pages.Next() {
+	}
+	require.NoError(t, pages.Err())
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pages = client.TxSearchPages(ctx, c, "tm.height > 0", false,
+		client.PageOptions{PerPage: 1, Interval: time.Minute})
+	require.True(t, pages.Next())
+	cancel()
+	assert.False(t, pages.Next())
+	assert.Equal(t, context.Canceled, pages.Err())
+}
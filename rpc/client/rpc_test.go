@@ -1,10 +1,13 @@
 package client_test
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,6 +15,7 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 
 	"github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctest "github.com/tendermint/tendermint/rpc/test"
 	"github.com/tendermint/tendermint/types"
 )
@@ -25,6 +29,21 @@ func getLocalClient() *client.Local {
 	return client.NewLocal(node)
 }
 
+// waitForTxIndexed waits for the tx to be indexed, as the txs of the committed
+// blocks are indexed asynchronously.
+func waitForTxIndexed(t *testing.T, c client.SignClient, hash []byte) {
+	for i := 0; ; i++ {
+		_, err := c.Tx(context.Background(), hash, false, false)
+		if err == nil {
+			return
+		}
+		if i == 100 {
+			t.Fatalf("Tx %X not indexed: %v", hash, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
 // GetClients returns a slice of clients for table-driven tests
 func GetClients() []client.Client {
 	return []client.Client{
@@ -52,7 +71,7 @@ func TestCorsEnabled(t *testing.T) {
 func TestStatus(t *testing.T) {
 	for i, c := range GetClients() {
 		moniker := rpctest.GetConfig().Moniker
-		status, err := c.Status()
+		status, err := c.Status(context.Background())
 		require.Nil(t, err, "%d: %+v", i, err)
 		assert.Equal(t, moniker, status.NodeInfo.Moniker)
 	}
@@ -61,9 +80,9 @@ func TestStatus(t *testing.T) {
 // Make sure info is correct (we connect properly)
 func TestInfo(t *testing.T) {
 	for i, c := range GetClients() {
-		// status, err := c.Status()
+		// status, err := c.Status(context.Background())
 		// require.Nil(t, err, "%+v", err)
-		info, err := c.ABCIInfo()
+		info, err := c.ABCIInfo(context.Background())
 		require.Nil(t, err, "%d: %+v", i, err)
 		// TODO: this is not correct - fix merkleeyes!
 		// assert.EqualValues(t, status.SyncInfo.LatestBlockHeight, info.Response.LastBlockHeight)
@@ -75,7 +94,7 @@ func TestNetInfo(t *testing.T) {
 	for i, c := range GetClients() {
 		nc, ok := c.(client.NetworkClient)
 		require.True(t, ok, "%d", i)
-		netinfo, err := nc.NetInfo()
+		netinfo, err := nc.NetInfo(context.Background())
 		require.Nil(t, err, "%d: %+v", i, err)
 		assert.True(t, netinfo.Listening)
 		assert.Equal(t, 0, len(netinfo.Peers))
@@ -87,7 +106,7 @@ func TestDumpConsensusState(t *testing.T) {
 		// FIXME: fix server so it doesn't panic on invalid input
 		nc, ok := c.(client.NetworkClient)
 		require.True(t, ok, "%d", i)
-		cons, err := nc.DumpConsensusState()
+		cons, err := nc.DumpConsensusState(context.Background())
 		require.Nil(t, err, "%d: %+v", i, err)
 		assert.NotEmpty(t, cons.RoundState)
 		assert.Empty(t, cons.Peers)
@@ -99,17 +118,63 @@ func TestConsensusState(t *testing.T) {
 		// FIXME: fix server so it doesn't panic on invalid input
 		nc, ok := c.(client.NetworkClient)
 		require.True(t, ok, "%d", i)
-		cons, err := nc.ConsensusState()
+		cons, err := nc.ConsensusState(context.Background())
 		require.Nil(t, err, "%d: %+v", i, err)
 		assert.NotEmpty(t, cons.RoundState)
 	}
 }
 
+func TestValidatorUptime(t *testing.T) {
+	for i, c := range GetClients() {
+		nc, ok := c.(client.NetworkClient)
+		require.True(t, ok, "%d", i)
+		require.NoError(t, client.WaitForHeight(context.Background(), c, 3, nil))
+
+		uptime, err := nc.ValidatorUptime(context.Background())
+		require.Nil(t, err, "%d: %+v", i, err)
+		require.True(t, uptime.ToHeight > 0, "%d", i)
+		require.Equal(t, 1, len(uptime.Validators), "%d", i)
+		// the only validator signs all blocks
+		val := uptime.Validators[0]
+		assert.Equal(t, int(uptime.ToHeight-uptime.FromHeight+1), val.SignedBlocks, "%d", i)
+		assert.Zero(t, val.MissedBlocks, "%d", i)
+		assert.Equal(t, uptime.ToHeight, val.LastSignedHeight, "%d", i)
+	}
+}
+
+func TestChainHealth(t *testing.T) {
+	for i, c := range GetClients() {
+		nc, ok := c.(client.NetworkClient)
+		require.True(t, ok, "%d", i)
+		require.NoError(t, client.WaitForHeight(context.Background(), c, 3, nil))
+
+		health, err := nc.ChainHealth(context.Background(), 2)
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.Equal(t, health.ToHeight-1, health.FromHeight, "%d", i)
+		assert.True(t, health.BlockInterval.Max >= health.BlockInterval.P50, "%d", i)
+		// the only validator signs all blocks
+		assert.Zero(t, health.MissingPowerRatio, "%d", i)
+	}
+}
+
+func TestLocalCanceled(t *testing.T) {
+	c := getLocalClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.Status(ctx)
+	assert.Equal(t, context.Canceled, err)
+	_, err = c.ABCIQuery(ctx, "/key", []byte("name"))
+	assert.Equal(t, context.Canceled, err)
+	_, err = c.TxSearch(ctx, "tx.height >= 1", false, 1, 30)
+	assert.Equal(t, context.Canceled, err)
+}
+
 func TestHealth(t *testing.T) {
 	for i, c := range GetClients() {
 		nc, ok := c.(client.NetworkClient)
 		require.True(t, ok, "%d", i)
-		_, err := nc.Health()
+		_, err := nc.Health(context.Background())
 		require.Nil(t, err, "%d: %+v", i, err)
 	}
 }
@@ -118,14 +183,14 @@ func TestGenesisAndValidators(t *testing.T) {
 	for i, c := range GetClients() {
 
 		// make sure this is the right genesis file
-		gen, err := c.Genesis()
+		gen, err := c.Genesis(context.Background())
 		require.Nil(t, err, "%d: %+v", i, err)
 		// get the genesis validator
 		require.Equal(t, 1, len(gen.Genesis.Validators))
 		gval := gen.Genesis.Validators[0]
 
 		// get the current validators
-		vals, err := c.Validators(nil)
+		vals, err := c.Validators(context.Background(), nil, 0, 0)
 		require.Nil(t, err, "%d: %+v", i, err)
 		require.Equal(t, 1, len(vals.Validators))
 		val := vals.Validators[0]
@@ -140,13 +205,13 @@ func TestABCIQuery(t *testing.T) {
 	for i, c := range GetClients() {
 		// write something
 		k, v, tx := MakeTxKV()
-		bres, err := c.BroadcastTxCommit(tx)
+		bres, err := c.BroadcastTxCommit(context.Background(), tx)
 		require.Nil(t, err, "%d: %+v", i, err)
 		apph := bres.Height + 1 // this is where the tx will be applied to the state
 
 		// wait before querying
-		client.WaitForHeight(c, apph, nil)
-		res, err := c.ABCIQuery("/key", k)
+		client.WaitForHeight(context.Background(), c, apph, nil)
+		res, err := c.ABCIQuery(context.Background(), "/key", k)
 		qres := res.Response
 		if assert.Nil(t, err) && assert.True(t, qres.IsOK()) {
 			assert.EqualValues(t, v, qres.Value)
@@ -160,29 +225,29 @@ func TestAppCalls(t *testing.T) {
 	for i, c := range GetClients() {
 
 		// get an offset of height to avoid racing and guessing
-		s, err := c.Status()
+		s, err := c.Status(context.Background())
 		require.Nil(err, "%d: %+v", i, err)
 		// sh is start height or status height
 		sh := s.SyncInfo.LatestBlockHeight
 
 		// look for the future
 		h := sh + 2
-		_, err = c.Block(&h)
+		_, err = c.Block(context.Background(), &h)
 		assert.NotNil(err) // no block yet
 
 		// write something
 		k, v, tx := MakeTxKV()
-		bres, err := c.BroadcastTxCommit(tx)
+		bres, err := c.BroadcastTxCommit(context.Background(), tx)
 		require.Nil(err, "%d: %+v", i, err)
 		require.True(bres.DeliverTx.IsOK())
 		txh := bres.Height
 		apph := txh + 1 // this is where the tx will be applied to the state
 
 		// wait before querying
-		if err := client.WaitForHeight(c, apph, nil); err != nil {
+		if err := client.WaitForHeight(context.Background(), c, apph, nil); err != nil {
 			t.Error(err)
 		}
-		_qres, err := c.ABCIQueryWithOptions("/key", k, client.ABCIQueryOptions{Prove: false})
+		_qres, err := c.ABCIQueryWithOptions(context.Background(), "/key", k, client.ABCIQueryOptions{Prove: false})
 		qres := _qres.Response
 		if assert.Nil(err) && assert.True(qres.IsOK()) {
 			assert.Equal(k, qres.Key)
@@ -190,29 +255,33 @@ func TestAppCalls(t *testing.T) {
 		}
 
 		// make sure we can lookup the tx with proof
-		ptx, err := c.Tx(bres.Hash, true)
+		waitForTxIndexed(t, c, bres.Hash)
+		ptx, err := c.Tx(context.Background(), bres.Hash, true, false)
 		require.Nil(err, "%d: %+v", i, err)
 		assert.EqualValues(txh, ptx.Height)
 		assert.EqualValues(tx, ptx.Tx)
 
 		// and we can even check the block is added
-		block, err := c.Block(&apph)
+		block, err := c.Block(context.Background(), &apph)
 		require.Nil(err, "%d: %+v", i, err)
 		appHash := block.BlockMeta.Header.AppHash
 		assert.True(len(appHash) > 0)
 		assert.EqualValues(apph, block.BlockMeta.Header.Height)
 
 		// now check the results
-		blockResults, err := c.BlockResults(&txh)
+		blockResults, err := c.BlockResults(context.Background(), &txh)
 		require.Nil(err, "%d: %+v", i, err)
 		assert.Equal(txh, blockResults.Height)
 		if assert.Equal(1, len(blockResults.Results.DeliverTx)) {
 			// check success code
 			assert.EqualValues(0, blockResults.Results.DeliverTx[0].Code)
 		}
+		assert.NoError(client.VerifyBlockResults(blockResults, &block.BlockMeta.Header))
+		_, err = client.FetchAndVerifyBlockResults(context.Background(), c, txh)
+		assert.NoError(err)
 
 		// check blockchain info, now that we know there is info
-		info, err := c.BlockchainInfo(apph, apph)
+		info, err := c.BlockchainInfo(context.Background(), apph, apph)
 		require.Nil(err, "%d: %+v", i, err)
 		assert.True(info.LastHeight >= apph)
 		if assert.Equal(1, len(info.BlockMetas)) {
@@ -224,7 +293,7 @@ func TestAppCalls(t *testing.T) {
 		}
 
 		// and get the corresponding commit with the same apphash
-		commit, err := c.Commit(&apph)
+		commit, err := c.Commit(context.Background(), &apph)
 		require.Nil(err, "%d: %+v", i, err)
 		cappHash := commit.Header.AppHash
 		assert.Equal(appHash, cappHash)
@@ -232,12 +301,12 @@ func TestAppCalls(t *testing.T) {
 
 		// compare the commits (note Commit(2) has commit from Block(3))
 		h = apph - 1
-		commit2, err := c.Commit(&h)
+		commit2, err := c.Commit(context.Background(), &h)
 		require.Nil(err, "%d: %+v", i, err)
 		assert.Equal(block.Block.LastCommit, commit2.Commit)
 
 		// and we got a proof that works!
-		_pres, err := c.ABCIQueryWithOptions("/key", k, client.ABCIQueryOptions{Prove: true})
+		_pres, err := c.ABCIQueryWithOptions(context.Background(), "/key", k, client.ABCIQueryOptions{Prove: true})
 		pres := _pres.Response
 		assert.Nil(err)
 		assert.True(pres.IsOK())
@@ -246,6 +315,96 @@ func TestAppCalls(t *testing.T) {
 	}
 }
 
+type blockTimeClient interface {
+	client.Client
+	BlockchainInfoByTime(ctx context.Context, from, to time.Time) (*ctypes.ResultBlockchainInfo, error)
+	HeightAtTime(ctx context.Context, t time.Time) (*ctypes.ResultHeightAtTime, error)
+}
+
+func TestBlockchainInfoByTime(t *testing.T) {
+	clients := []blockTimeClient{getHTTPClient(), getLocalClient()}
+
+	for i, c := range clients {
+		height := int64(1)
+		block, err := c.Block(context.Background(), &height)
+		require.Nil(t, err, "%d: %+v", i, err)
+		blockTime := block.Block.Time
+
+		info, err := c.BlockchainInfoByTime(context.Background(), blockTime, blockTime)
+		require.Nil(t, err, "%d: %+v", i, err)
+		if assert.Equal(t, 1, len(info.BlockMetas), "%d", i) {
+			assert.Equal(t, block.BlockMeta.BlockID, info.BlockMetas[0].BlockID, "%d", i)
+		}
+
+		info, err = c.BlockchainInfoByTime(context.Background(), blockTime.Add(-time.Hour), blockTime.Add(-time.Nanosecond))
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.Empty(t, info.BlockMetas, "%d", i)
+
+		// the latest blocks
+		info, err = c.BlockchainInfoByTime(context.Background(), time.Time{}, time.Time{})
+		require.Nil(t, err, "%d: %+v", i, err)
+		if assert.NotEmpty(t, info.BlockMetas, "%d", i) {
+			assert.True(t, info.BlockMetas[0].Header.Height >= info.LastHeight, "%d", i)
+		}
+	}
+}
+
+func TestHeightAtTime(t *testing.T) {
+	clients := []blockTimeClient{getHTTPClient(), getLocalClient()}
+
+	for i, c := range clients {
+		height := int64(2)
+		require.NoError(t, client.WaitForHeight(context.Background(), c, height, nil))
+		block, err := c.Block(context.Background(), &height)
+		require.Nil(t, err, "%d: %+v", i, err)
+		blockTime := block.Block.Time
+
+		res, err := c.HeightAtTime(context.Background(), blockTime)
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.EqualValues(t, 2, res.Height, "%d", i)
+		assert.Equal(t, block.BlockMeta.BlockID, res.BlockMeta.BlockID, "%d", i)
+		require.NotNil(t, res.PreviousTime, "%d", i)
+		assert.True(t, res.PreviousTime.Before(blockTime), "%d", i)
+
+		res, err = c.HeightAtTime(context.Background(), res.PreviousTime.Add(time.Nanosecond))
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.EqualValues(t, 2, res.Height, "%d", i)
+
+		// no block yet
+		_, err = c.HeightAtTime(context.Background(), time.Now().Add(time.Hour))
+		assert.Error(t, err, "%d", i)
+	}
+}
+
+func TestBlockRaw(t *testing.T) {
+	type blockRawClient interface {
+		client.Client
+		BlockRaw(ctx context.Context, height *int64) (*ctypes.ResultBlockRaw, error)
+	}
+	clients := []blockRawClient{getHTTPClient(), getLocalClient()}
+
+	for i, c := range clients {
+		height := int64(2)
+		require.NoError(t, client.WaitForHeight(context.Background(), c, height, nil))
+		block, err := c.Block(context.Background(), &height)
+		require.Nil(t, err, "%d: %+v", i, err)
+
+		res, err := c.BlockRaw(context.Background(), &height)
+		require.Nil(t, err, "%d: %+v", i, err)
+		assert.EqualValues(t, height, res.Height, "%d", i)
+		assert.Equal(t, block.BlockMeta.BlockID, res.BlockID, "%d", i)
+
+		var decoded types.Block
+		require.NoError(t, types.GetCodec().UnmarshalBinaryBare(res.Block, &decoded), "%d", i)
+		assert.Equal(t, res.BlockID.Hash, decoded.Hash(), "%d", i)
+		assert.Equal(t, res.BlockID.PartsHeader, decoded.MakePartSet(types.BlockPartSizeBytes).Header(), "%d", i)
+
+		height = 1 << 30
+		_, err = c.BlockRaw(context.Background(), &height)
+		assert.Error(t, err, "%d", i)
+	}
+}
+
 func TestBroadcastTxSync(t *testing.T) {
 	require := require.New(t)
 
@@ -254,7 +413,7 @@ func TestBroadcastTxSync(t *testing.T) {
 
 	for i, c := range GetClients() {
 		_, _, tx := MakeTxKV()
-		bres, err := c.BroadcastTxSync(tx)
+		bres, err := c.BroadcastTxSync(context.Background(), tx)
 		require.Nil(err, "%d: %+v", i, err)
 		require.Equal(bres.Code, abci.CodeTypeOK) // FIXME
 
@@ -272,7 +431,7 @@ func TestBroadcastTxCommit(t *testing.T) {
 	mempool := node.MempoolReactor().Mempool
 	for i, c := range GetClients() {
 		_, _, tx := MakeTxKV()
-		bres, err := c.BroadcastTxCommit(tx)
+		bres, err := c.BroadcastTxCommit(context.Background(), tx)
 		require.Nil(err, "%d: %+v", i, err)
 		require.True(bres.CheckTx.IsOK())
 		require.True(bres.DeliverTx.IsOK())
@@ -281,6 +440,79 @@ func TestBroadcastTxCommit(t *testing.T) {
 	}
 }
 
+func TestBroadcastTxCommitConcurrent(t *testing.T) {
+	_, _, tx := MakeTxKV()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// The consensus is paused, so that the tx is not committed before all
+	// the calls are in flight.
+	cs := node.ConsensusState()
+	_, err := cs.PauseAtHeight(0)
+	require.NoError(t, err)
+	defer cs.Resume()
+	for {
+		if _, paused := cs.PausedAt(); paused {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("Timed out waiting for the consensus to pause")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	const subscriber = "TestBroadcastTxCommitConcurrent"
+	committed := make(chan interface{}, 1)
+	require.NoError(t, node.EventBus().Subscribe(ctx, subscriber, types.EventQueryTxFor(tx), committed))
+	defer node.EventBus().UnsubscribeAll(context.Background(), subscriber)
+
+	// the identical calls are coalesced onto a single check and wait
+	clients := append(GetClients(), GetClients()...)
+	results := make([]*ctypes.ResultBroadcastTxCommit, len(clients))
+	errs := make([]error, len(clients))
+	start := make(chan struct{})
+	var ready, wg sync.WaitGroup
+	for i, c := range clients {
+		ready.Add(1)
+		wg.Add(1)
+		go func(i int, c client.Client) {
+			defer wg.Done()
+			ready.Done()
+			<-start
+			results[i], errs[i] = c.BroadcastTxCommit(ctx, tx)
+		}(i, c)
+	}
+	ready.Wait()
+	close(start)
+
+	// Once the tx is in the mempool, leave the other calls time to join it,
+	// well within the timeout of the commit wait, before committing it.
+	mempool := node.MempoolReactor().Mempool
+	for mempool.TxsByHashes([][]byte{types.Tx(tx).Hash()})[0] == nil {
+		select {
+		case <-ctx.Done():
+			t.Fatal("Timed out waiting for the tx to be checked")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	time.Sleep(time.Second)
+	cs.Resume()
+	select {
+	case <-committed:
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for the tx to be committed")
+	}
+	wg.Wait()
+
+	for i := range clients {
+		require.NoError(t, errs[i], "%d", i)
+		require.True(t, results[i].CheckTx.IsOK(), "%d: %v", i, results[i].CheckTx)
+		require.True(t, results[i].DeliverTx.IsOK(), "%d: %v", i, results[i].DeliverTx)
+		assert.Equal(t, results[0].Height, results[i].Height, "%d", i)
+	}
+}
+
 func TestUnconfirmedTxs(t *testing.T) {
 	_, _, tx := MakeTxKV()
 
@@ -290,7 +522,7 @@ func TestUnconfirmedTxs(t *testing.T) {
 	for i, c := range GetClients() {
 		mc, ok := c.(client.MempoolClient)
 		require.True(t, ok, "%d", i)
-		txs, err := mc.UnconfirmedTxs(1)
+		txs, err := mc.UnconfirmedTxs(context.Background(), 1)
 		require.Nil(t, err, "%d: %+v", i, err)
 		assert.Exactly(t, types.Txs{tx}, types.Txs(txs.Txs))
 	}
@@ -308,7 +540,7 @@ func TestNumUnconfirmedTxs(t *testing.T) {
 	for i, c := range GetClients() {
 		mc, ok := c.(client.MempoolClient)
 		require.True(t, ok, "%d", i)
-		res, err := mc.NumUnconfirmedTxs()
+		res, err := mc.NumUnconfirmedTxs(context.Background())
 		require.Nil(t, err, "%d: %+v", i, err)
 
 		assert.Equal(t, mempoolSize, res.N)
@@ -321,26 +553,29 @@ func TestTx(t *testing.T) {
 	// first we broadcast a tx
 	c := getHTTPClient()
 	_, _, tx := MakeTxKV()
-	bres, err := c.BroadcastTxCommit(tx)
+	bres, err := c.BroadcastTxCommit(context.Background(), tx)
 	require.Nil(t, err, "%+v", err)
 
 	txHeight := bres.Height
 	txHash := bres.Hash
+	waitForTxIndexed(t, c, txHash)
 
 	anotherTxHash := types.Tx("a different tx").Hash()
 
 	cases := []struct {
-		valid bool
-		hash  []byte
-		prove bool
+		valid       bool
+		hash        []byte
+		prove       bool
+		proveResult bool
 	}{
 		// only valid if correct hash provided
-		{true, txHash, false},
-		{true, txHash, true},
-		{false, anotherTxHash, false},
-		{false, anotherTxHash, true},
-		{false, nil, false},
-		{false, nil, true},
+		{true, txHash, false, false},
+		{true, txHash, true, false},
+		{true, txHash, false, true},
+		{false, anotherTxHash, false, false},
+		{false, anotherTxHash, true, true},
+		{false, nil, false, false},
+		{false, nil, true, true},
 	}
 
 	for i, c := range GetClients() {
@@ -349,7 +584,7 @@ func TestTx(t *testing.T) {
 
 			// now we query for the tx.
 			// since there's only one tx, we know index=0.
-			ptx, err := c.Tx(tc.hash, tc.prove)
+			ptx, err := c.Tx(context.Background(), tc.hash, tc.prove, tc.proveResult)
 
 			if !tc.valid {
 				require.NotNil(t, err)
@@ -366,6 +601,18 @@ func TestTx(t *testing.T) {
 				if tc.prove && assert.EqualValues(t, tx, proof.Data) {
 					assert.NoError(t, proof.Proof.Verify(proof.RootHash, txHash))
 				}
+
+				// the result proof is verified against the next header
+				if tc.proveResult {
+					resultProof := ptx.ResultProof
+					assert.EqualValues(t, ptx.TxResult.Code, resultProof.Data.Code)
+					assert.EqualValues(t, ptx.TxResult.Data, resultProof.Data.Data)
+					nextHeight := txHeight + 1
+					require.NoError(t, client.WaitForHeight(context.Background(), c, nextHeight+1, nil))
+					commit, err := c.Commit(context.Background(), &nextHeight)
+					require.Nil(t, err, "%+v", err)
+					assert.NoError(t, resultProof.Validate(commit.Header.LastResultsHash))
+				}
 			}
 		}
 	}
@@ -375,11 +622,12 @@ func TestTxSearch(t *testing.T) {
 	// first we broadcast a tx
 	c := getHTTPClient()
 	_, _, tx := MakeTxKV()
-	bres, err := c.BroadcastTxCommit(tx)
+	bres, err := c.BroadcastTxCommit(context.Background(), tx)
 	require.Nil(t, err, "%+v", err)
 
 	txHeight := bres.Height
 	txHash := bres.Hash
+	waitForTxIndexed(t, c, txHash)
 
 	anotherTxHash := types.Tx("a different tx").Hash()
 
@@ -388,7 +636,7 @@ func TestTxSearch(t *testing.T) {
 
 		// now we query for the tx.
 		// since there's only one tx, we know index=0.
-		result, err := c.TxSearch(fmt.Sprintf("tx.hash='%v'", txHash), true, 1, 30)
+		result, err := c.TxSearch(context.Background(), fmt.Sprintf("tx.hash='%v'", txHash), true, 1, 30)
 		require.Nil(t, err, "%+v", err)
 		require.Len(t, result.Txs, 1)
 
@@ -406,31 +654,31 @@ func TestTxSearch(t *testing.T) {
 		}
 
 		// query by height
-		result, err = c.TxSearch(fmt.Sprintf("tx.height=%d", txHeight), true, 1, 30)
+		result, err = c.TxSearch(context.Background(), fmt.Sprintf("tx.height=%d", txHeight), true, 1, 30)
 		require.Nil(t, err, "%+v", err)
 		require.Len(t, result.Txs, 1)
 
 		// query for non existing tx
-		result, err = c.TxSearch(fmt.Sprintf("tx.hash='%X'", anotherTxHash), false, 1, 30)
+		result, err = c.TxSearch(context.Background(), fmt.Sprintf("tx.hash='%X'", anotherTxHash), false, 1, 30)
 		require.Nil(t, err, "%+v", err)
 		require.Len(t, result.Txs, 0)
 
 		// query using a tag (see kvstore application)
-		result, err = c.TxSearch("app.creator='Cosmoshi Netowoko'", false, 1, 30)
+		result, err = c.TxSearch(context.Background(), "app.creator='Cosmoshi Netowoko'", false, 1, 30)
 		require.Nil(t, err, "%+v", err)
 		if len(result.Txs) == 0 {
 			t.Fatal("expected a lot of transactions")
 		}
 
 		// query using a tag (see kvstore application) and height
-		result, err = c.TxSearch("app.creator='Cosmoshi Netowoko' AND tx.height<10000", true, 1, 30)
+		result, err = c.TxSearch(context.Background(), "app.creator='Cosmoshi Netowoko' AND tx.height<10000", true, 1, 30)
 		require.Nil(t, err, "%+v", err)
 		if len(result.Txs) == 0 {
 			t.Fatal("expected a lot of transactions")
 		}
 
 		// query a non existing tx with page 1 and txsPerPage 1
-		result, err = c.TxSearch("app.creator='Cosmoshi Neetowoko'", true, 1, 1)
+		result, err = c.TxSearch(context.Background(), "app.creator='Cosmoshi Neetowoko'", true, 1, 1)
 		require.Nil(t, err, "%+v", err)
 		require.Len(t, result.Txs, 0)
 	}
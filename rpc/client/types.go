@@ -9,3 +9,14 @@ type ABCIQueryOptions struct {
 
 // DefaultABCIQueryOptions are latest height (0) and prove false.
 var DefaultABCIQueryOptions = ABCIQueryOptions{Height: 0, Prove: false}
+
+// Modes of the BroadcastTx*WithMode calls, restricting the use of the tx by
+// the node.
+const (
+	// BroadcastTxModeDefault txs are gossiped to the peers and proposed.
+	BroadcastTxModeDefault = ""
+	// BroadcastTxModeLocal txs are proposed, but not gossiped to the peers.
+	BroadcastTxModeLocal = "local"
+	// BroadcastTxModeGossip txs are gossiped to the peers, but not proposed.
+	BroadcastTxModeGossip = "gossip"
+)
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// ResultsHeightMismatchError is the error of results checked against a header
+// which isn't the one of the next block.
+type ResultsHeightMismatchError struct {
+	ResultsHeight int64
+	HeaderHeight  int64
+}
+
+func (e ResultsHeightMismatchError) Error() string {
+	return fmt.Sprintf("results of height %d checked against the header of height %d, instead of %d",
+		e.ResultsHeight, e.HeaderHeight, e.ResultsHeight+1)
+}
+
+// ResultsHashMismatchError is the error of results whose hash isn't the
+// LastResultsHash of the header of the next block.
+type ResultsHashMismatchError struct {
+	Height   int64        // of the results
+	Computed cmn.HexBytes // from the results
+	Expected cmn.HexBytes // LastResultsHash of the header
+}
+
+func (e ResultsHashMismatchError) Error() string {
+	return fmt.Sprintf("hash %X of the results of height %d doesn't match the LastResultsHash %X of the next header",
+		e.Computed, e.Height, e.Expected)
+}
+
+// VerifyBlockResults checks the results of a block against the header of the
+// next block: their hash must be its LastResultsHash. It returns a
+// ResultsHeightMismatchError or a ResultsHashMismatchError if they don't
+// match.
+//
+// The header is trusted: it must have been verified beforehand, e.g. with the
+// lite client.
+func VerifyBlockResults(results *ctypes.ResultBlockResults, nextHeader *types.Header) error {
+	if nextHeader.Height != results.Height+1 {
+		return ResultsHeightMismatchError{ResultsHeight: results.Height, HeaderHeight: nextHeader.Height}
+	}
+	var computed []byte
+	if results.Results != nil {
+		computed = results.Results.ResultsHash()
+	} else {
+		computed = types.NewResults(nil).Hash()
+	}
+	if !bytes.Equal(computed, nextHeader.LastResultsHash) {
+		return ResultsHashMismatchError{Height: results.Height, Computed: computed, Expected: nextHeader.LastResultsHash}
+	}
+	return nil
+}
+
+// FetchAndVerifyBlockResults fetches the results of the block at height and
+// the header of the next block, and checks them with VerifyBlockResults.
+//
+// As the header is fetched from the same node, this only checks that the node
+// is consistent with itself: to audit a node, check the results against a
+// verified header with VerifyBlockResults instead.
+func FetchAndVerifyBlockResults(ctx context.Context, c SignClient, height int64) (*ctypes.ResultBlockResults, error) {
+	results, err := c.BlockResults(ctx, &height)
+	if err != nil {
+		return nil, err
+	}
+	nextHeight := height + 1
+	commit, err := c.Commit(ctx, &nextHeight)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyBlockResults(results, commit.Header); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
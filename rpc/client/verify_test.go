@@ -0,0 +1,50 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestVerifyBlockResults(t *testing.T) {
+	deliverTxs := []*abci.ResponseDeliverTx{
+		{Code: abci.CodeTypeOK, Data: []byte("foo")},
+		{Code: 1, Data: []byte("bar")},
+	}
+	results := &ctypes.ResultBlockResults{
+		Height:  5,
+		Results: &sm.ABCIResponses{DeliverTx: deliverTxs},
+	}
+	resultsHash := types.NewResults(deliverTxs).Hash()
+
+	header := &types.Header{Height: 6, LastResultsHash: resultsHash}
+	assert.NoError(t, client.VerifyBlockResults(results, header))
+
+	// the header must be the one of the next block
+	header.Height = 5
+	assert.Equal(t, client.ResultsHeightMismatchError{ResultsHeight: 5, HeaderHeight: 5},
+		client.VerifyBlockResults(results, header))
+
+	// a result was tampered with
+	header.Height = 6
+	deliverTxs[1].Code = abci.CodeTypeOK
+	err := client.VerifyBlockResults(results, header)
+	if assert.IsType(t, client.ResultsHashMismatchError{}, err) {
+		mismatch := err.(client.ResultsHashMismatchError)
+		assert.EqualValues(t, 5, mismatch.Height)
+		assert.EqualValues(t, resultsHash, mismatch.Expected)
+		assert.EqualValues(t, types.NewResults(deliverTxs).Hash(), mismatch.Computed)
+	}
+
+	// no results
+	results = &ctypes.ResultBlockResults{Height: 5}
+	assert.IsType(t, client.ResultsHashMismatchError{}, client.VerifyBlockResults(results, header))
+	header.LastResultsHash = types.NewResults(nil).Hash()
+	assert.NoError(t, client.VerifyBlockResults(results, header))
+}
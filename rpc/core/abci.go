@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+
 	abci "github.com/tendermint/tendermint/abci/types"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/proxy"
@@ -20,7 +22,7 @@ import (
 //   // handle error
 // }
 // defer client.Stop()
-// result, err := client.ABCIQuery("", "abcd", true)
+// result, err := client.ABCIQuery(context.Background(), "", "abcd", true)
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -52,8 +54,12 @@ import (
 // | data      | []byte | false   | true     | Data                                           |
 // | height    | int64  | 0       | false    | Height (0 means latest)                        |
 // | prove     | bool   | false   | false    | Includes proof if true                         |
-func ABCIQuery(path string, data cmn.HexBytes, height int64, prove bool) (*ctypes.ResultABCIQuery, error) {
-	resQuery, err := proxyAppQuery.QuerySync(abci.RequestQuery{
+func (env *Environment) ABCIQuery(ctx context.Context, path string, data cmn.HexBytes, height int64, prove bool) (*ctypes.ResultABCIQuery, error) {
+	// the query can't be canceled once sent to the app
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	resQuery, err := env.ProxyAppQuery.QuerySync(abci.RequestQuery{
 		Path:   path,
 		Data:   data,
 		Height: height,
@@ -62,7 +68,7 @@ func ABCIQuery(path string, data cmn.HexBytes, height int64, prove bool) (*ctype
 	if err != nil {
 		return nil, err
 	}
-	logger.Info("ABCIQuery", "path", path, "data", data, "result", resQuery)
+	env.Logger.Info("ABCIQuery", "path", path, "data", data, "result", resQuery)
 	return &ctypes.ResultABCIQuery{Response: *resQuery}, nil
 }
 
@@ -79,7 +85,7 @@ func ABCIQuery(path string, data cmn.HexBytes, height int64, prove bool) (*ctype
 //   // handle error
 // }
 // defer client.Stop()
-// info, err := client.ABCIInfo()
+// info, err := client.ABCIInfo(context.Background())
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -96,8 +102,8 @@ func ABCIQuery(path string, data cmn.HexBytes, height int64, prove bool) (*ctype
 // 	"jsonrpc": "2.0"
 // }
 // ```
-func ABCIInfo() (*ctypes.ResultABCIInfo, error) {
-	resInfo, err := proxyAppQuery.InfoSync(proxy.RequestInfo)
+func (env *Environment) ABCIInfo() (*ctypes.ResultABCIInfo, error) {
+	resInfo, err := env.ProxyAppQuery.InfoSync(proxy.RequestInfo)
 	if err != nil {
 		return nil, err
 	}
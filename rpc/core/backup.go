@@ -0,0 +1,116 @@
+package core
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// backupOrder is the order in which the snapshots of the DBs are taken. The
+// state is taken before the blockstore, so that the backup of the blockstore
+// is never behind the one of the state, as blocks are saved before the state
+// is updated.
+var backupOrder = []string{"state", "blockstore", "tx_index", "evidence"}
+
+// UnsafeBackup writes a copy of the databases of the node (blockstore, state,
+// tx index and evidence) to the directory at path, without stopping the node.
+// The directory must not exist, or be empty.
+//
+// A snapshot of each database is taken first, then copied. The backup can be
+// restored by replacing the data directory of a stopped node with it (keeping
+// the priv_validator_state.json). The application state is not part of the
+// backup: if the application is behind the restored blockstore, the blocks
+// are replayed to it on startup.
+//
+// Only the goleveldb and cleveldb backends support backups. An error is
+// returned for the other ones, including memdb, which has nothing to write
+// the backup to.
+//
+// ```shell
+// curl 'localhost:26657/unsafe_backup?path="/tmp/tendermint-backup"'
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "path": "/tmp/tendermint-backup",
+//     "dbs": [
+//       "state",
+//       "blockstore",
+//       "tx_index",
+//       "evidence"
+//     ]
+//   }
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                       |
+// |-----------+--------+---------+----------+-----------------------------------|
+// | path      | string | ""      | true     | Directory to write the backup to  |
+func (env *Environment) UnsafeBackup(path string) (*ctypes.ResultUnsafeBackup, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+
+	if env.BackupDBBackend == dbm.MemDBBackend {
+		return nil, fmt.Errorf("backups are not supported by the %s backend", env.BackupDBBackend)
+	}
+
+	env.backupMtx.Lock()
+	defer env.backupMtx.Unlock()
+
+	if files, err := ioutil.ReadDir(path); err == nil && len(files) > 0 {
+		return nil, fmt.Errorf("%s is not empty", path)
+	} else if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// Take all the snapshots before copying any of them, so that they are as
+	// close in time as possible.
+	names := make([]string, 0, len(env.BackupDBs))
+	snaps := make([]dbm.Snapshot, 0, len(env.BackupDBs))
+	defer func() {
+		for _, snap := range snaps {
+			snap.Release()
+		}
+	}()
+	for _, name := range backupOrder {
+		db, ok := env.BackupDBs[name]
+		if !ok {
+			continue
+		}
+		snap, err := dbm.TakeSnapshot(db)
+		if err != nil {
+			return nil, fmt.Errorf("error taking a snapshot of %s: %v", name, err)
+		}
+		names = append(names, name)
+		snaps = append(snaps, snap)
+	}
+
+	if err := cmn.EnsureDir(path, 0700); err != nil {
+		return nil, err
+	}
+	for i, name := range names {
+		db := dbm.NewDB(name, env.BackupDBBackend, path)
+		err := dbm.CopySnapshot(snaps[i], db)
+		if closeErr := dbm.CloseDB(db); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error backing up %s: %v", name, err)
+		}
+		env.Logger.Info("Backed up DB", "name", name, "path", path)
+	}
+
+	return &ctypes.ResultUnsafeBackup{Path: path, DBs: names}, nil
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestUnsafeBackup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unsafe_backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	blockStoreDB, stateDB := dbm.NewMemDB(), dbm.NewMemDB()
+	blockStoreDB.Set([]byte("block"), []byte("1"))
+	stateDB.Set([]byte("state"), []byte("2"))
+	env := &Environment{
+		BackupDBs:       map[string]dbm.DB{"blockstore": blockStoreDB, "state": stateDB},
+		BackupDBBackend: dbm.GoLevelDBBackend,
+		Logger:          log.TestingLogger(),
+	}
+
+	path := filepath.Join(dir, "backup")
+	res, err := env.UnsafeBackup(path)
+	require.NoError(t, err)
+	assert.Equal(t, path, res.Path)
+	assert.Equal(t, []string{"state", "blockstore"}, res.DBs)
+
+	db := dbm.NewDB("blockstore", dbm.GoLevelDBBackend, path)
+	assert.Equal(t, []byte("1"), db.Get([]byte("block")))
+	db.Close()
+	db = dbm.NewDB("state", dbm.GoLevelDBBackend, path)
+	assert.Equal(t, []byte("2"), db.Get([]byte("state")))
+	db.Close()
+
+	// The backup directory must be empty.
+	_, err = env.UnsafeBackup(path)
+	assert.Error(t, err)
+
+	// A memdb backup would not be written anywhere.
+	env.BackupDBBackend = dbm.MemDBBackend
+	_, err = env.UnsafeBackup(filepath.Join(dir, "memdb"))
+	assert.Error(t, err)
+}
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"context"
+	"encoding/binary"
 	"fmt"
+	"time"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
@@ -23,7 +26,7 @@ import (
 //   // handle error
 // }
 // defer client.Stop()
-// info, err := client.BlockchainInfo(10, 10)
+// info, err := client.BlockchainInfo(context.Background(), 10, 10)
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -68,28 +71,145 @@ import (
 // ```
 //
 // <aside class="notice">Returns at most 20 items.</aside>
-func BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
+func (env *Environment) BlockchainInfo(minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
 
 	// maximum 20 block metas
 	const limit int64 = 20
 	var err error
-	minHeight, maxHeight, err = filterMinMax(blockStore.Height(), minHeight, maxHeight, limit)
+	minHeight, maxHeight, err = filterMinMax(env.BlockStore.Height(), minHeight, maxHeight, limit)
 	if err != nil {
 		return nil, err
 	}
-	logger.Debug("BlockchainInfoHandler", "maxHeight", maxHeight, "minHeight", minHeight)
+	env.Logger.Debug("BlockchainInfoHandler", "maxHeight", maxHeight, "minHeight", minHeight)
 
 	blockMetas := []*types.BlockMeta{}
 	for height := maxHeight; height >= minHeight; height-- {
-		blockMeta := blockStore.LoadBlockMeta(height)
+		blockMeta := env.BlockStore.LoadBlockMeta(height)
 		blockMetas = append(blockMetas, blockMeta)
 	}
 
 	return &ctypes.ResultBlockchainInfo{
-		LastHeight: blockStore.Height(),
+		LastHeight: env.BlockStore.Height(),
 		BlockMetas: blockMetas}, nil
 }
 
+// Get block headers for the blocks with a time in [from, to], from the time
+// index of the block store. Block headers are returned in descending order
+// (highest first).
+//
+// ```shell
+// curl 'localhost:26657/blockchain_by_time?from="2019-03-04T10:00:00Z"&to="2019-03-04T11:00:00Z"'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// err := client.Start()
+// if err != nil {
+//   // handle error
+// }
+// defer client.Stop()
+// info, err := client.BlockchainInfoByTime(context.Background(), from, to)
+// ```
+//
+// > The above command returns JSON structured like the one of /blockchain.
+//
+// ### Query Parameters
+//
+// | Parameter | Type      | Default | Required | Description                                        |
+// |-----------+-----------+---------+----------+----------------------------------------------------|
+// | from      | time.Time | zero    | false    | Minimum block time (zero means the first block)    |
+// | to        | time.Time | zero    | false    | Maximum block time (zero means the latest block)   |
+//
+// <aside class="notice">Returns at most 20 items, the latest ones: to get the
+// earlier ones, call it again with to set before the time of the earliest
+// one.</aside>
+func (env *Environment) BlockchainInfoByTime(from, to time.Time) (*ctypes.ResultBlockchainInfo, error) {
+	if !to.IsZero() && to.Before(from) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	if to.IsZero() {
+		to = time.Unix(0, 1<<63-1)
+	}
+
+	// maximum 20 block metas
+	const limit int64 = 20
+	height := env.BlockStore.Height()
+	minHeight, maxHeight := env.BlockTimeIndex.HeightsByTime(from, to)
+	blockMetas := []*types.BlockMeta{}
+	for h := maxHeight; h >= minHeight && h > maxHeight-limit; h-- {
+		blockMetas = append(blockMetas, env.BlockStore.LoadBlockMeta(h))
+	}
+
+	return &ctypes.ResultBlockchainInfo{
+		LastHeight: height,
+		BlockMetas: blockMetas}, nil
+}
+
+// Get the height of the first block with a time after or at the given time,
+// with its header, and the time of the block before it, if any, so that the
+// result can be checked against the headers.
+//
+// ```shell
+// curl 'localhost:26657/height_at_time?time="2019-03-04T10:00:00Z"'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// err := client.Start()
+// if err != nil {
+//   // handle error
+// }
+// defer client.Stop()
+// result, err := client.HeightAtTime(context.Background(), t)
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+// 	"error": "",
+// 	"result": {
+// 		"height": "26682",
+// 		"block_meta": {
+// 			"header": {...},
+// 			"block_id": {...}
+// 		},
+// 		"previous_time": "2019-03-04T09:59:59.412Z"
+// 	},
+// 	"id": "",
+// 	"jsonrpc": "2.0"
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type      | Default | Required | Description |
+// |-----------+-----------+---------+----------+-------------|
+// | time      | time.Time | zero    | true     | Block time  |
+func (env *Environment) HeightAtTime(t time.Time) (*ctypes.ResultHeightAtTime, error) {
+	height := env.BlockTimeIndex.HeightAtTime(t)
+	if height == 0 {
+		return nil, fmt.Errorf("no block at or after %v, the latest one is at height %v",
+			t, env.BlockStore.Height())
+	}
+
+	// Check the lookup against the headers.
+	blockMeta := env.BlockStore.LoadBlockMeta(height)
+	if blockMeta == nil || blockMeta.Header.Time.Before(t) {
+		return nil, fmt.Errorf("the block time index is inconsistent with the block at height %v", height)
+	}
+	result := &ctypes.ResultHeightAtTime{Height: height, BlockMeta: blockMeta}
+	// At the base, the previous block is pruned or there is none.
+	if height > env.BlockStore.Base() {
+		previousMeta := env.BlockStore.LoadBlockMeta(height - 1)
+		if previousMeta == nil || !previousMeta.Header.Time.Before(t) {
+			return nil, fmt.Errorf("the block time index is inconsistent with the block at height %v", height-1)
+		}
+		result.PreviousTime = &previousMeta.Header.Time
+	}
+	return result, nil
+}
+
 // error if either min or max are negative or min < max
 // if 0, use 1 for min, latest block height for max
 // enforce limit.
@@ -135,7 +255,7 @@ func filterMinMax(height, min, max, limit int64) (int64, int64, error) {
 //   // handle error
 // }
 // defer client.Stop()
-// info, err := client.Block(10)
+// info, err := client.Block(context.Background(), 10)
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -226,18 +346,91 @@ func filterMinMax(height, min, max, limit int64) (int64, int64, error) {
 //   "jsonrpc": "2.0"
 // }
 // ```
-func Block(heightPtr *int64) (*ctypes.ResultBlock, error) {
-	storeHeight := blockStore.Height()
-	height, err := getHeight(storeHeight, heightPtr)
+func (env *Environment) Block(heightPtr *int64) (*ctypes.ResultBlock, error) {
+	storeHeight := env.BlockStore.Height()
+	height, err := env.getStoredHeight(storeHeight, heightPtr)
 	if err != nil {
 		return nil, err
 	}
 
-	blockMeta := blockStore.LoadBlockMeta(height)
-	block := blockStore.LoadBlock(height)
+	blockMeta := env.BlockStore.LoadBlockMeta(height)
+	block := env.BlockStore.LoadBlock(height)
 	return &ctypes.ResultBlock{BlockMeta: blockMeta, Block: block}, nil
 }
 
+// Get the amino encoding of the block at a given height, as stored and gossiped
+// in its parts, so it can be checked against the parts hash of its block ID
+// and decoded by other implementations.
+// If no height is provided, it will fetch the latest block.
+//
+// The block is encoded with `MarshalBinaryBare`, without the length prefix of
+// its parts.
+//
+// ```shell
+// curl 'localhost:26657/block_raw?height=10'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// err := client.Start()
+// if err != nil {
+//   // handle error
+// }
+// defer client.Stop()
+// info, err := client.BlockRaw(context.Background(), 10)
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "height": "10",
+//     "block_id": {
+//       "hash": "96B1D2F2D201BA4BC383EB8224139DB1294944E5",
+//       "parts": {
+//         "total": "1",
+//         "hash": "277A4DBEF91483A18B85F2F5677ABF9694DFA40F"
+//       }
+//     },
+//     "block": "CtsBCgJ0ZXN0LWNoYWluLTZVVE5JThIKCAsQ..."
+//   }
+// }
+// ```
+func (env *Environment) BlockRaw(heightPtr *int64) (*ctypes.ResultBlockRaw, error) {
+	storeHeight := env.BlockStore.Height()
+	height, err := env.getStoredHeight(storeHeight, heightPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	blockMeta := env.BlockStore.LoadBlockMeta(height)
+	if blockMeta == nil {
+		return nil, fmt.Errorf("No block at height %d", height)
+	}
+	var bz []byte
+	for i := 0; i < blockMeta.BlockID.PartsHeader.Total; i++ {
+		part := env.BlockStore.LoadBlockPart(height, i)
+		if part == nil {
+			return nil, fmt.Errorf("Missing part %d of the block at height %d", i, height)
+		}
+		bz = append(bz, part.Bytes...)
+	}
+
+	// the parts are of the length prefixed block
+	size, n := binary.Uvarint(bz)
+	if n <= 0 || uint64(len(bz)-n) != size {
+		return nil, fmt.Errorf("Invalid length prefix of the block at height %d", height)
+	}
+	return &ctypes.ResultBlockRaw{
+		Height:  height,
+		BlockID: blockMeta.BlockID,
+		Block:   bz[n:],
+	}, nil
+}
+
 // Get block commit at a given height.
 // If no height is provided, it will fetch the commit for the latest block.
 //
@@ -252,7 +445,7 @@ func Block(heightPtr *int64) (*ctypes.ResultBlock, error) {
 //   // handle error
 // }
 // defer client.Stop()
-// info, err := client.Commit(11)
+// info, err := client.Commit(context.Background(), 11)
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -313,24 +506,24 @@ func Block(heightPtr *int64) (*ctypes.ResultBlock, error) {
 //   "jsonrpc": "2.0"
 // }
 // ```
-func Commit(heightPtr *int64) (*ctypes.ResultCommit, error) {
-	storeHeight := blockStore.Height()
-	height, err := getHeight(storeHeight, heightPtr)
+func (env *Environment) Commit(heightPtr *int64) (*ctypes.ResultCommit, error) {
+	storeHeight := env.BlockStore.Height()
+	height, err := env.getStoredHeight(storeHeight, heightPtr)
 	if err != nil {
 		return nil, err
 	}
 
-	header := blockStore.LoadBlockMeta(height).Header
+	header := env.BlockStore.LoadBlockMeta(height).Header
 
 	// If the next block has not been committed yet,
 	// use a non-canonical commit
 	if height == storeHeight {
-		commit := blockStore.LoadSeenCommit(height)
+		commit := env.BlockStore.LoadSeenCommit(height)
 		return ctypes.NewResultCommit(&header, commit, false), nil
 	}
 
 	// Return the canonical commit (comes from the block at height+1)
-	commit := blockStore.LoadBlockCommit(height)
+	commit := env.BlockStore.LoadBlockCommit(height)
 	return ctypes.NewResultCommit(&header, commit, true), nil
 }
 
@@ -351,7 +544,7 @@ func Commit(heightPtr *int64) (*ctypes.ResultCommit, error) {
 //   // handle error
 // }
 // defer client.Stop()
-// info, err := client.BlockResults(10)
+// info, err := client.BlockResults(context.Background(), 10)
 // ```
 //
 //
@@ -372,15 +565,18 @@ func Commit(heightPtr *int64) (*ctypes.ResultCommit, error) {
 //  ]
 // }
 // ```
-func BlockResults(heightPtr *int64) (*ctypes.ResultBlockResults, error) {
-	storeHeight := blockStore.Height()
-	height, err := getHeight(storeHeight, heightPtr)
+func (env *Environment) BlockResults(ctx context.Context, heightPtr *int64) (*ctypes.ResultBlockResults, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	storeHeight := env.BlockStore.Height()
+	height, err := env.getStoredHeight(storeHeight, heightPtr)
 	if err != nil {
 		return nil, err
 	}
 
 	// load the results
-	results, err := sm.LoadABCIResponses(stateDB, height)
+	results, err := sm.LoadABCIResponses(env.StateDB, height)
 	if err != nil {
 		return nil, err
 	}
@@ -392,6 +588,19 @@ func BlockResults(heightPtr *int64) (*ctypes.ResultBlockResults, error) {
 	return res, nil
 }
 
+// getStoredHeight is getHeight for the heights of the block store, and of the
+// states pruned along with it, which are not available below its base.
+func (env *Environment) getStoredHeight(currentHeight int64, heightPtr *int64) (int64, error) {
+	height, err := getHeight(currentHeight, heightPtr)
+	if err != nil {
+		return 0, err
+	}
+	if base := env.BlockStore.Base(); height < base {
+		return 0, fmt.Errorf("height %d is pruned, the lowest height available is %d", height, base)
+	}
+	return height, nil
+}
+
 func getHeight(currentHeight int64, heightPtr *int64) (int64, error) {
 	if heightPtr != nil {
 		height := *heightPtr
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"github.com/pkg/errors"
+
 	cm "github.com/tendermint/tendermint/consensus"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
@@ -10,6 +13,13 @@ import (
 // Get the validator set at the given block height.
 // If no height is provided, it will fetch the current validator set.
 //
+// The monikers of the validators, from the names of the genesis validators and
+// the `validator_monikers_file`, are in the same order, empty if unknown, and
+// omitted if none is known.
+//
+// Without a page, all the validators are returned. With a page, only the
+// validators of the page are, `total` being the size of the set.
+//
 // ```shell
 // curl 'localhost:26657/validators'
 // ```
@@ -21,7 +31,7 @@ import (
 //   // handle error
 // }
 // defer client.Stop()
-// state, err := client.Validators()
+// state, err := client.Validators(context.Background(), nil, 0, 0)
 // ```
 //
 // The above command returns JSON structured like this:
@@ -41,28 +51,126 @@ import (
 // 				"address": "E89A51D60F68385E09E716D353373B11F8FACD62"
 // 			}
 // 		],
-// 		"block_height": "5241"
+// 		"monikers": [
+// 			"alice"
+// 		],
+// 		"block_height": "5241",
+// 		"count": "1",
+// 		"total": "1"
 // 	},
 // 	"id": "",
 // 	"jsonrpc": "2.0"
 // }
 // ```
-func Validators(heightPtr *int64) (*ctypes.ResultValidators, error) {
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                                      |
+// |-----------+--------+---------+----------+--------------------------------------------------|
+// | height    | int64  | 0       | false    | Height (0 means latest)                          |
+// | page      | int    | 0       | false    | Page number (1-based), 0 for all the validators  |
+// | per_page  | int    | 30      | false    | Number of entries per page (max: 100)            |
+func (env *Environment) Validators(heightPtr *int64, page, perPage int) (*ctypes.ResultValidators, error) {
 	// The latest validator that we know is the
 	// NextValidator of the last block.
-	height := consensusState.GetState().LastBlockHeight + 1
-	height, err := getHeight(height, heightPtr)
+	height := env.Consensus.GetState().LastBlockHeight + 1
+	height, err := env.getStoredHeight(height, heightPtr)
 	if err != nil {
 		return nil, err
 	}
 
-	validators, err := sm.LoadValidators(stateDB, height)
+	validators, err := sm.LoadValidators(env.StateDB, height)
 	if err != nil {
 		return nil, err
 	}
+	vals := validators.Validators
+	total := len(vals)
+	if page != 0 {
+		perPage = validatePerPage(perPage)
+		page = validatePage(page, perPage, total)
+		skipCount := validateSkipCount(page, perPage)
+		vals = vals[cmn.MinInt(skipCount, total):cmn.MinInt(skipCount+perPage, total)]
+	}
 	return &ctypes.ResultValidators{
 		BlockHeight: height,
-		Validators:  validators.Validators}, nil
+		Validators:  vals,
+		Monikers:    env.validatorMonikers(vals),
+		Count:       len(vals),
+		Total:       total}, nil
+}
+
+// validatorMonikers returns the monikers of the validators, nil if none is
+// known.
+func (env *Environment) validatorMonikers(vals []*types.Validator) []string {
+	if env.Monikers == nil {
+		return nil
+	}
+	names := make([]string, len(vals))
+	known := false
+	for i, val := range vals {
+		names[i] = env.Monikers.Moniker(val.Address)
+		known = known || names[i] != ""
+	}
+	if !known {
+		return nil
+	}
+	return names
+}
+
+// ValidatorUptime returns how many blocks each validator signed and missed
+// over the last blocks (`instrumentation.uptime_window` in the config), among
+// the blocks it was a validator at. A block is signed if the validator's
+// precommit is in its commit.
+//
+// The window is filled from the block store at start, so it can span blocks
+// committed before the node started.
+//
+// ```shell
+// curl 'localhost:26657/validator_uptime'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// err := client.Start()
+// if err != nil {
+//   // handle error
+// }
+// defer client.Stop()
+// uptime, err := client.ValidatorUptime(context.Background())
+// ```
+//
+// The above command returns JSON structured like this:
+//
+// ```json
+// {
+// 	"error": "",
+// 	"result": {
+// 		"window": "1000",
+// 		"from_height": "4242",
+// 		"to_height": "5241",
+// 		"validators": [
+// 			{
+// 				"address": "E89A51D60F68385E09E716D353373B11F8FACD62",
+// 				"signed_blocks": "993",
+// 				"missed_blocks": "7",
+// 				"last_signed_height": "5241"
+// 			}
+// 		]
+// 	},
+// 	"id": "",
+// 	"jsonrpc": "2.0"
+// }
+// ```
+func (env *Environment) ValidatorUptime() (*ctypes.ResultValidatorUptime, error) {
+	if env.UptimeTracker == nil {
+		return nil, errors.New("Validator uptime tracking is disabled (instrumentation.uptime_window = 0)")
+	}
+	fromHeight, toHeight, validators := env.UptimeTracker.Uptime()
+	return &ctypes.ResultValidatorUptime{
+		Window:     env.UptimeTracker.Window(),
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+		Validators: validators}, nil
 }
 
 // DumpConsensusState dumps consensus state.
@@ -79,7 +187,7 @@ func Validators(heightPtr *int64) (*ctypes.ResultValidators, error) {
 //   // handle error
 // }
 // defer client.Stop()
-// state, err := client.DumpConsensusState()
+// state, err := client.DumpConsensusState(context.Background())
 // ```
 //
 // The above command returns JSON structured like this:
@@ -200,9 +308,9 @@ func Validators(heightPtr *int64) (*ctypes.ResultValidators, error) {
 //   }
 // }
 // ```
-func DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
+func (env *Environment) DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
 	// Get Peer consensus states.
-	peers := p2pPeers.Peers().List()
+	peers := env.P2PPeers.Peers().List()
 	peerStates := make([]ctypes.PeerStateInfo, len(peers))
 	for i, peer := range peers {
 		peerState, ok := peer.Get(types.PeerStateKey).(*cm.PeerState)
@@ -221,7 +329,7 @@ func DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
 		}
 	}
 	// Get self round state.
-	roundState, err := consensusState.GetRoundStateJSON()
+	roundState, err := env.Consensus.GetRoundStateJSON()
 	if err != nil {
 		return nil, err
 	}
@@ -233,6 +341,14 @@ func DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
 // ConsensusState returns a concise summary of the consensus state.
 // UNSTABLE
 //
+// `missing_votes` lists the validators whose prevotes and precommits are
+// missing in the current round, and, for each validator missing votes, how
+// many of its votes are missing over the last `rounds` rounds of the height
+// (at most 10). It helps to find which validators are stalling the network
+// when it can't commit a block: note votes are naturally missing early in a
+// round. The proposer of the round and the validators missing votes have
+// their monikers, if known.
+//
 // ```shell
 // curl 'localhost:26657/consensus_state'
 // ```
@@ -244,7 +360,7 @@ func DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
 //   // handle error
 // }
 // defer client.Stop()
-// state, err := client.ConsensusState()
+// state, err := client.ConsensusState(context.Background())
 // ```
 //
 // The above command returns JSON structured like this:
@@ -272,14 +388,37 @@ func DumpConsensusState() (*ctypes.ResultDumpConsensusState, error) {
 //          ],
 //          "precommits_bit_array": "BA{1:_} 0/10 = 0.00"
 //        }
-//      ]
+//      ],
+//      "missing_votes": {
+//        "rounds": "1",
+//        "prevotes": [
+//          "5D6A51A8E9899C44079C6AF90618BA0369070E6E"
+//        ],
+//        "precommits": [
+//          "5D6A51A8E9899C44079C6AF90618BA0369070E6E"
+//        ],
+//        "validators": [
+//          {
+//            "address": "5D6A51A8E9899C44079C6AF90618BA0369070E6E",
+//            "index": "0",
+//            "moniker": "alice",
+//            "missing_prevotes": "1",
+//            "missing_precommits": "1"
+//          }
+//        ]
+//      },
+//      "proposer": {
+//        "address": "5D6A51A8E9899C44079C6AF90618BA0369070E6E",
+//        "index": "0",
+//        "moniker": "alice"
+//      }
 //    }
 //  }
 //}
 //```
-func ConsensusState() (*ctypes.ResultConsensusState, error) {
+func (env *Environment) ConsensusState() (*ctypes.ResultConsensusState, error) {
 	// Get self round state.
-	bz, err := consensusState.GetRoundStateSimpleJSON()
+	bz, err := env.Consensus.GetRoundStateSimpleJSON()
 	return &ctypes.ResultConsensusState{RoundState: bz}, err
 }
 
@@ -297,7 +436,7 @@ func ConsensusState() (*ctypes.ResultConsensusState, error) {
 //   // handle error
 // }
 // defer client.Stop()
-// state, err := client.ConsensusParams()
+// state, err := client.ConsensusParams(context.Background())
 // ```
 //
 // The above command returns JSON structured like this:
@@ -320,14 +459,14 @@ func ConsensusState() (*ctypes.ResultConsensusState, error) {
 //   }
 // }
 // ```
-func ConsensusParams(heightPtr *int64) (*ctypes.ResultConsensusParams, error) {
-	height := consensusState.GetState().LastBlockHeight + 1
+func (env *Environment) ConsensusParams(heightPtr *int64) (*ctypes.ResultConsensusParams, error) {
+	height := env.Consensus.GetState().LastBlockHeight + 1
 	height, err := getHeight(height, heightPtr)
 	if err != nil {
 		return nil, err
 	}
 
-	consensusparams, err := sm.LoadConsensusParams(stateDB, height)
+	consensusparams, err := sm.LoadConsensusParams(env.StateDB, height)
 	if err != nil {
 		return nil, err
 	}
@@ -335,3 +474,128 @@ func ConsensusParams(heightPtr *int64) (*ctypes.ResultConsensusParams, error) {
 		BlockHeight:     height,
 		ConsensusParams: consensusparams}, nil
 }
+
+// Get the changes of the consensus parameters up to the given block height,
+// each with the height from which it is in effect, starting with the genesis
+// consensus params. If no height is provided, it will fetch the changes up to
+// the current consensus params.
+//
+// ```shell
+// curl 'localhost:26657/consensus_params_history'
+// ```
+//
+// The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "block_height": "12",
+//     "changes": [
+//       {
+//         "height": "1",
+//         "consensus_params": {
+//           "block_size_params": {
+//             "max_txs_bytes": "22020096",
+//             "max_gas": "-1"
+//           },
+//           "evidence_params": {
+//             "max_age": "100000"
+//           }
+//         }
+//       },
+//       {
+//         "height": "8",
+//         "consensus_params": {
+//           "block_size_params": {
+//             "max_txs_bytes": "1048576",
+//             "max_gas": "-1"
+//           },
+//           "evidence_params": {
+//             "max_age": "100000"
+//           }
+//         }
+//       }
+//     ]
+//   }
+// }
+// ```
+func (env *Environment) ConsensusParamsHistory(heightPtr *int64) (*ctypes.ResultConsensusParamsHistory, error) {
+	height := env.Consensus.GetState().LastBlockHeight + 1
+	height, err := getHeight(height, heightPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := sm.LoadConsensusParamsChanges(env.StateDB, height)
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultConsensusParamsHistory{
+		BlockHeight: height,
+		Changes:     changes}, nil
+}
+
+// Get the changes of the validator set after the given from_height, up to the
+// given block height, each with the height from which it is in effect. The
+// removed validators have no voting power, and the genesis validators are a
+// change at height 1. If no height is provided, it will fetch the changes up
+// to the current validator set.
+//
+// ```shell
+// curl 'localhost:26657/validator_set_changes?from_height=1'
+// ```
+//
+// The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "block_height": "12",
+//     "changes": [
+//       {
+//         "height": "8",
+//         "changes": [
+//           {
+//             "address": "B5B3D40BE53982AD294EF99FF5A34C0C3E5A3244",
+//             "pub_key": {
+//               "type": "tendermint/PubKeyEd25519",
+//               "value": "SBctdhRBcXtBgdI/8a/alTsUhGXqGs9k5ylV1u5iKHg="
+//             },
+//             "voting_power": "11",
+//             "proposer_priority": "0"
+//           }
+//         ]
+//       }
+//     ]
+//   }
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter   | Type  | Default | Required | Description                                     |
+// |-------------+-------+---------+----------+-------------------------------------------------|
+// | from_height | int64 | 0       | false    | Height after which the changes are returned     |
+// | height      | int64 | 0       | false    | Height up to which the changes are returned     |
+func (env *Environment) ValidatorSetChanges(fromHeight int64, heightPtr *int64) (*ctypes.ResultValidatorSetChanges, error) {
+	height := env.Consensus.GetState().LastBlockHeight + 1
+	height, err := getHeight(height, heightPtr)
+	if err != nil {
+		return nil, err
+	}
+	if fromHeight < 0 {
+		return nil, errors.New("from_height can't be negative")
+	}
+
+	changes, err := sm.LoadValidatorSetChanges(env.StateDB, fromHeight, height)
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultValidatorSetChanges{
+		BlockHeight: height,
+		Changes:     changes}, nil
+}
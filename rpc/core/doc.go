@@ -60,6 +60,39 @@ JSONRPC requests can be POST'd to the root RPC endpoint via HTTP (e.g. `http://l
 
 JSONRPC requests can be made via websocket. The websocket endpoint is at `/websocket`, e.g. `localhost:26657/websocket`.  Asynchronous RPC functions like event `subscribe` and `unsubscribe` are only available via websockets.
 
+## Canonical JSON
+
+Responses can be requested in canonical JSON (RFC 8785: sorted keys, no
+whitespace, fixed number and string encodings), so that the same response
+always has the same bytes and can be hashed or compared across clients. Set
+the `X-Canonical-JSON: true` header, or the `canonical_json=true` URL
+parameter, on the request (or on the websocket handshake, for all the
+responses of the connection).
+
+```bash
+curl -H 'X-Canonical-JSON: true' 'localhost:26657/status'
+curl 'localhost:26657/status?canonical_json=true'
+```
+
+## CBOR and amino
+
+Over HTTP, responses can also be requested in CBOR or amino with the
+`Accept` header of the request, which is useful for heavy endpoints like
+`/block` and `/block_results`:
+
+- `application/cbor`: the whole JSONRPC response, in canonical CBOR. Amino
+  encodes 64-bit integers and bytes as strings, which are kept as is.
+- `application/x-amino`: only the result, in amino binary (decode it with
+  `cdc.UnmarshalBinaryBare` in Go). The result isn't encoded in JSON at all.
+  Errors are returned in JSON.
+
+The first of these types found in the header is used, regardless of quality
+values, and JSON otherwise.
+
+```bash
+curl -H 'Accept: application/x-amino' 'localhost:26657/block?height=1'
+```
+
 
 ## More Examples
 
@@ -86,24 +119,37 @@ Available endpoints:
 /health
 /unconfirmed_txs
 /unsafe_flush_mempool
+/unsafe_resume_consensus
+/unsafe_step_consensus
 /unsafe_stop_cpu_profiler
+/validator_uptime
 /validators
 
 Endpoints that require arguments:
 /abci_query?path=_&data=_&prove=_
+/chain_health?blocks=_
 /block?height=_
+/block_raw?height=_
 /blockchain?minHeight=_&maxHeight=_
+/blockchain_by_time?from=_&to=_
 /broadcast_tx_async?tx=_
 /broadcast_tx_commit?tx=_
 /broadcast_tx_sync?tx=_
 /commit?height=_
 /dial_seeds?seeds=_
 /dial_persistent_peers?persistent_peers=_
+/evidence?height=_
+/evidence_search?address=_&page=_&per_page=_
+/height_at_time?time=_
 /subscribe?event=_
 /tx?hash=_&prove=_
+/unsafe_backup?path=_
+/unsafe_logs?module=_&lines=_
+/unsafe_pause_consensus?height=_
 /unsafe_start_cpu_profiler?filename=_
 /unsafe_write_heap_profile?filename=_
 /unsubscribe?event=_
+/validator_set_changes?from_height=_&height=_
 ```
 
 # Endpoints
@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/lib/types"
@@ -15,11 +16,12 @@ import (
 // Subscribe for events via WebSocket.
 //
 // To tell which events you want, you need to provide a query. query is a
-// string, which has a form: "condition AND condition ..." (no OR at the
-// moment). condition has a form: "key operation operand". key is a string with
-// a restricted set of possible symbols ( \t\n\r\\()"'=>< are not allowed).
-// operation can be "=", "<", "<=", ">", ">=", "CONTAINS". operand can be a
-// string (escaped with single quotes), number, date or time.
+// string, which has a form: "condition AND condition ... OR condition AND
+// condition ...", AND taking precedence over OR. condition has a form: "key
+// operation operand". key is a string with a restricted set of possible
+// symbols ( \t\n\r\\()"'=>< are not allowed). operation can be "=", "<",
+// "<=", ">", ">=", "CONTAINS". operand can be a string (escaped with single
+// quotes), number, date or time. The query "*" matches all the events.
 //
 // Examples:
 //		tm.event = 'NewBlock'								# new blocks
@@ -27,14 +29,17 @@ import (
 //		tm.event = 'Tx' AND tx.hash = 'XYZ' # single transaction
 //		tm.event = 'Tx' AND tx.height = 5		# all txs of the fifth block
 //		tx.height = 5												# all txs of the fifth block
+//		tm.event = 'NewBlock' OR tm.event = 'Tx'	# new blocks and txs
+//		*																		# all events
 //
 // Tendermint provides a few predefined keys: tm.event, tx.hash and tx.height.
-// Note for transactions, you can define additional keys by providing tags with
-// DeliverTx response.
+// Note for transactions, you can define additional keys by providing events
+// with DeliverTx response, their attributes being matched by the composite
+// keys type.key.
 //
 //		DeliverTx{
-//			Tags: []*KVPair{
-//				"agent.name": "K",
+//			Events: []Event{
+//				{Type: "agent", Attributes: []KVPair{{"name", "K"}}},
 //			}
 //	  }
 //
@@ -85,36 +90,145 @@ import (
 //
 // ### Query Parameters
 //
-// | Parameter | Type   | Default | Required | Description |
-// |-----------+--------+---------+----------+-------------|
-// | query     | string | ""      | true     | Query       |
+// | Parameter   | Type   | Default | Required | Description                                  |
+// |-------------+--------+---------+----------+----------------------------------------------|
+// | query       | string | ""      | true     | Query                                        |
+// | header_only | bool   | false   | false    | Send NewBlock events without the block body  |
+// | ack_id      | string | ""      | false    | Subscribe in the ack mode, with this ID      |
+// | schema      | int    | 1       | false    | Version of the schema of the events          |
+//
+// With header_only, NewBlock events are sent like NewBlockHeader events: with
+// the header of the block (which has the number of txs), but without its txs,
+// evidence and last commit, to save bandwidth.
+//
+// With ack_id, the events are delivered at least once: they are numbered (the
+// seq of the event), and kept until the client acks them with /ack, up to
+// rpc.max_unacked_events, after which the subscription is dropped, with an
+// error response. The subscription outlives the connection: subscribing again
+// with the same ack ID and query, e.g. after a reconnection, resends the events
+// not acked yet, and the next ones, to the new connection. It ends with
+// /unsubscribe with the ack ID. Any client knowing the ack ID can resume the
+// subscription, so it should be random.
+//
+// The schema is the version of the format of the events, the first one by
+// default, so that existing clients keep receiving the events they expect:
+//
+// 1. the event data, with the events of the ABCI responses flattened to
+// `tags`, base64-encoded key-value pairs keyed by the composite keys of the
+// attributes (`type.key`), as before the events.
+// 2. the event data, with the events of the txs in the `events` field instead
+// of the data, their attributes as strings, the values in the tag encoding of
+// the node.
+// 3. the event data, with the `events` of the ABCI responses as is, their
+// attributes being base64-encoded key-value pairs.
+//
+// The response has the version of the schema of the events.
 //
 // <aside class="notice">WebSocket only</aside>
-func Subscribe(wsCtx rpctypes.WSRPCContext, query string) (*ctypes.ResultSubscribe, error) {
+func (env *Environment) Subscribe(wsCtx rpctypes.WSRPCContext, query string, headerOnly bool, ackID string, schema int) (*ctypes.ResultSubscribe, error) {
 	addr := wsCtx.GetRemoteAddr()
-	logger.Info("Subscribe to query", "remote", addr, "query", query)
+	env.Logger.Info("Subscribe to query", "remote", addr, "query", query, "schema", schema)
 
-	q, err := tmquery.New(query)
+	schema, err := eventSchema(schema)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse query")
+		return nil, err
+	}
+	q, err := env.parseEventQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if ackID != "" {
+		if err := env.subscribeAck(wsCtx, q, query, headerOnly, ackID, schema); err != nil {
+			return nil, err
+		}
+		return &ctypes.ResultSubscribe{Schema: schema}, nil
+	}
+
+	eventBus := env.eventBusFor(wsCtx)
+	if counter, ok := eventBus.(subscriptionCounter); ok {
+		max := env.Config.MaxSubscriptionsPerClient
+		if max > 0 && counter.NumClientSubscriptions(addr) >= max {
+			return nil, &rpctypes.RPCError{
+				Code:    rpctypes.CodeTooManySubscriptions,
+				Message: "Too many subscriptions",
+				Data:    fmt.Sprintf("max %d subscriptions per client", max),
+			}
+		}
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), subscribeTimeout)
 	defer cancel()
 	ch := make(chan interface{})
-	err = eventBusFor(wsCtx).Subscribe(ctx, addr, q, ch)
+	err = eventBus.Subscribe(ctx, addr, q, ch)
 	if err != nil {
 		return nil, err
 	}
 
 	go func() {
 		for event := range ch {
-			tmResult := &ctypes.ResultEvent{Query: query, Data: event.(tmtypes.TMEventData)}
+			data := event.(tmtypes.TMEventData)
+			if newBlock, ok := data.(tmtypes.EventDataNewBlock); ok && headerOnly {
+				data = newBlock.HeaderOnly()
+			}
+			tmResult := env.newResultEvent(query, data, schema)
 			wsCtx.TryWriteRPCResponse(rpctypes.NewRPCSuccessResponse(wsCtx.Codec(), rpctypes.JSONRPCStringID(fmt.Sprintf("%v#event", wsCtx.Request.ID)), tmResult))
 		}
 	}()
 
-	return &ctypes.ResultSubscribe{}, nil
+	return &ctypes.ResultSubscribe{Schema: schema}, nil
+}
+
+// eventSchema returns the version of the event schema, the first one if 0, or
+// an error if it isn't supported.
+func eventSchema(schema int) (int, error) {
+	if schema == 0 {
+		return ctypes.EventSchemaV1, nil
+	}
+	if schema < ctypes.EventSchemaV1 || schema > ctypes.EventSchemaLatest {
+		return 0, fmt.Errorf("unsupported event schema %d, the supported ones are %d to %d",
+			schema, ctypes.EventSchemaV1, ctypes.EventSchemaLatest)
+	}
+	return schema, nil
+}
+
+// parseEventQuery parses the query of a subscription, limited to
+// rpc.max_query_conditions.
+func (env *Environment) parseEventQuery(query string) (tmpubsub.Query, error) {
+	q, err := tmquery.Parse(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse query")
+	}
+	if max := env.Config.MaxQueryConditions; max > 0 && numQueryConditions(q) > max {
+		return nil, &rpctypes.RPCError{
+			Code:    rpctypes.CodeQueryTooComplex,
+			Message: "Query too complex",
+			Data:    fmt.Sprintf("max %d conditions per query", max),
+		}
+	}
+	return q, nil
+}
+
+// newResultEvent returns the event in the version of the event schema.
+func (env *Environment) newResultEvent(query string, data tmtypes.TMEventData, schema int) *ctypes.ResultEvent {
+	if schema < ctypes.EventSchemaV2 {
+		return &ctypes.ResultEvent{Query: query, Data: ctypes.EventDataWithTags(data)}
+	}
+	res := &ctypes.ResultEvent{Query: query, Data: data, Schema: schema}
+	if schema != ctypes.EventSchemaV2 {
+		return res
+	}
+	encoding := env.TagEncoding
+	if encoding == "" {
+		encoding = tmtypes.TagEncodingString
+	}
+	if tx, ok := data.(tmtypes.EventDataTx); ok {
+		res.Events = ctypes.NewEvents(tx.Result.Events, encoding)
+		res.Encoding = encoding
+		tx.Result.Events = nil
+		res.Data = tx
+	}
+	return res
 }
 
 // Unsubscribe from events via WebSocket.
@@ -142,25 +256,66 @@ func Subscribe(wsCtx rpctypes.WSRPCContext, query string) (*ctypes.ResultSubscri
 //
 // ### Query Parameters
 //
-// | Parameter | Type   | Default | Required | Description |
-// |-----------+--------+---------+----------+-------------|
-// | query     | string | ""      | true     | Query       |
+// | Parameter | Type   | Default | Required | Description                          |
+// |-----------+--------+---------+----------+--------------------------------------|
+// | query     | string | ""      | true     | Query                                |
+// | ack_id    | string | ""      | false    | Ack ID of a subscription in ack mode |
 //
 // <aside class="notice">WebSocket only</aside>
-func Unsubscribe(wsCtx rpctypes.WSRPCContext, query string) (*ctypes.ResultUnsubscribe, error) {
+func (env *Environment) Unsubscribe(wsCtx rpctypes.WSRPCContext, query string, ackID string) (*ctypes.ResultUnsubscribe, error) {
 	addr := wsCtx.GetRemoteAddr()
-	logger.Info("Unsubscribe from query", "remote", addr, "query", query)
-	q, err := tmquery.New(query)
+	env.Logger.Info("Unsubscribe from query", "remote", addr, "query", query, "ackID", ackID)
+	if ackID != "" {
+		if err := env.unsubscribeAck(ackID); err != nil {
+			return nil, err
+		}
+		return &ctypes.ResultUnsubscribe{}, nil
+	}
+	q, err := tmquery.Parse(query)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse query")
 	}
-	err = eventBusFor(wsCtx).Unsubscribe(context.Background(), addr, q)
+	err = env.eventBusFor(wsCtx).Unsubscribe(context.Background(), addr, q)
 	if err != nil {
 		return nil, err
 	}
 	return &ctypes.ResultUnsubscribe{}, nil
 }
 
+// Ack the events of a subscription in the ack mode, up to a seq, included.
+//
+// The events not acked yet which failed to be sent, because the write buffer of
+// the connection was full, are sent again.
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+// 	"error": "",
+// 	"result": {
+// 		"unacked": "2"
+// 	},
+// 	"id": "",
+// 	"jsonrpc": "2.0"
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                    |
+// |-----------+--------+---------+----------+--------------------------------|
+// | ack_id    | string | ""      | true     | Ack ID of the subscription     |
+// | seq       | int64  | 0       | true     | Seq of the last event to ack   |
+//
+// <aside class="notice">WebSocket only</aside>
+func (env *Environment) Ack(wsCtx rpctypes.WSRPCContext, ackID string, seq int64) (*ctypes.ResultAck, error) {
+	unacked, err := env.ackEvents(ackID, seq)
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultAck{Unacked: unacked}, nil
+}
+
 // Unsubscribe from all events via WebSocket.
 //
 // ```go
@@ -185,20 +340,43 @@ func Unsubscribe(wsCtx rpctypes.WSRPCContext, query string) (*ctypes.ResultUnsub
 // ```
 //
 // <aside class="notice">WebSocket only</aside>
-func UnsubscribeAll(wsCtx rpctypes.WSRPCContext) (*ctypes.ResultUnsubscribe, error) {
+func (env *Environment) UnsubscribeAll(wsCtx rpctypes.WSRPCContext) (*ctypes.ResultUnsubscribe, error) {
 	addr := wsCtx.GetRemoteAddr()
-	logger.Info("Unsubscribe from all", "remote", addr)
-	err := eventBusFor(wsCtx).UnsubscribeAll(context.Background(), addr)
+	env.Logger.Info("Unsubscribe from all", "remote", addr)
+	err := env.eventBusFor(wsCtx).UnsubscribeAll(context.Background(), addr)
 	if err != nil {
 		return nil, err
 	}
 	return &ctypes.ResultUnsubscribe{}, nil
 }
 
-func eventBusFor(wsCtx rpctypes.WSRPCContext) tmtypes.EventBusSubscriber {
+// subscriptionCounter is implemented by the event buses which can tell how
+// many subscriptions a client has, like types.EventBus.
+type subscriptionCounter interface {
+	NumClientSubscriptions(subscriber string) int
+}
+
+// numQueryConditions returns the number of conditions of the query, summed
+// over the queries OR'd together.
+func numQueryConditions(q tmpubsub.Query) int {
+	switch q := q.(type) {
+	case *tmquery.Query:
+		return len(q.Conditions())
+	case *tmquery.Or:
+		n := 0
+		for _, query := range q.Queries() {
+			n += len(query.Conditions())
+		}
+		return n
+	default:
+		return 0
+	}
+}
+
+func (env *Environment) eventBusFor(wsCtx rpctypes.WSRPCContext) tmtypes.EventBusSubscriber {
 	es := wsCtx.GetEventSubscriber()
 	if es == nil {
-		es = eventBus
+		es = env.EventBus
 	}
 	return es
 }
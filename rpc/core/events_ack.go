@@ -0,0 +1,229 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
+	rpctypes "github.com/tendermint/tendermint/rpc/lib/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// ackSubscription is a subscription in the ack mode of /subscribe. Its events
+// are numbered, and kept until the client acks them, across the connections
+// of the client, which resumes the subscription by subscribing again with the
+// same ack ID.
+type ackSubscription struct {
+	env        *Environment
+	id         string
+	query      string
+	q          tmpubsub.Query
+	headerOnly bool
+	eventBus   tmtypes.EventBusSubscriber
+
+	mtx     sync.Mutex
+	wsCtx   rpctypes.WSRPCContext // of the last /subscribe
+	schema  int                   // of the last /subscribe
+	events  []*ackEvent           // not acked yet, by seq
+	nextSeq int64
+	closed  bool
+}
+
+// ackEvent is an event of an ackSubscription.
+type ackEvent struct {
+	seq  int64
+	data tmtypes.TMEventData
+	sent bool // to the current connection
+}
+
+// ackSubscriber is the subscriber of the event bus of the ack ID, independent
+// of the connections of the client.
+func ackSubscriber(ackID string) string {
+	return "ack#" + ackID
+}
+
+// subscribeAck creates the ack subscription of the ack ID, or resumes it on
+// the connection of wsCtx, resending the events not acked yet.
+func (env *Environment) subscribeAck(wsCtx rpctypes.WSRPCContext, q tmpubsub.Query, query string, headerOnly bool, ackID string, schema int) error {
+	if env.Config.MaxUnackedEvents <= 0 {
+		return fmt.Errorf("the ack mode is disabled")
+	}
+
+	env.ackSubscriptionsMtx.Lock()
+	defer env.ackSubscriptionsMtx.Unlock()
+	if sub, ok := env.ackSubscriptions[ackID]; ok {
+		if sub.query != query {
+			return fmt.Errorf("ack ID %q is subscribed to another query", ackID)
+		}
+		if !sub.resume(wsCtx, schema) {
+			return fmt.Errorf("the subscription with ack ID %q was dropped, subscribe again", ackID)
+		}
+		return nil
+	}
+	if max := env.Config.MaxAckSubscriptions; max > 0 && len(env.ackSubscriptions) >= max {
+		return &rpctypes.RPCError{
+			Code:    rpctypes.CodeTooManySubscriptions,
+			Message: "Too many subscriptions",
+			Data:    fmt.Sprintf("max %d subscriptions in the ack mode", max),
+		}
+	}
+
+	sub := &ackSubscription{
+		env:        env,
+		id:         ackID,
+		query:      query,
+		q:          q,
+		headerOnly: headerOnly,
+		eventBus:   env.eventBusFor(wsCtx),
+		wsCtx:      wsCtx,
+		schema:     schema,
+		nextSeq:    1,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), subscribeTimeout)
+	defer cancel()
+	ch := make(chan interface{})
+	if err := sub.eventBus.Subscribe(ctx, ackSubscriber(ackID), q, ch); err != nil {
+		return err
+	}
+	if env.ackSubscriptions == nil {
+		env.ackSubscriptions = make(map[string]*ackSubscription)
+	}
+	env.ackSubscriptions[ackID] = sub
+	go sub.receive(ch)
+	return nil
+}
+
+// unsubscribeAck ends the ack subscription of the ack ID.
+func (env *Environment) unsubscribeAck(ackID string) error {
+	env.ackSubscriptionsMtx.Lock()
+	sub, ok := env.ackSubscriptions[ackID]
+	delete(env.ackSubscriptions, ackID)
+	env.ackSubscriptionsMtx.Unlock()
+	if !ok {
+		return fmt.Errorf("no subscription with ack ID %q", ackID)
+	}
+
+	sub.mtx.Lock()
+	sub.closed = true
+	sub.mtx.Unlock()
+	return sub.eventBus.Unsubscribe(context.Background(), ackSubscriber(ackID), sub.q)
+}
+
+// drop removes the subscription after it was closed, and unsubscribes it.
+func (sub *ackSubscription) drop() {
+	sub.env.ackSubscriptionsMtx.Lock()
+	if sub.env.ackSubscriptions[sub.id] == sub {
+		delete(sub.env.ackSubscriptions, sub.id)
+	}
+	sub.env.ackSubscriptionsMtx.Unlock()
+	if err := sub.eventBus.Unsubscribe(context.Background(), ackSubscriber(sub.id), sub.q); err != nil {
+		sub.env.Logger.Error("Failed to unsubscribe", "ackID", sub.id, "err", err)
+	}
+}
+
+// ackEvents acks the events of the ack subscription up to seq, and returns the
+// number of events not acked yet.
+func (env *Environment) ackEvents(ackID string, seq int64) (int, error) {
+	env.ackSubscriptionsMtx.Lock()
+	sub, ok := env.ackSubscriptions[ackID]
+	env.ackSubscriptionsMtx.Unlock()
+	if !ok {
+		return 0, fmt.Errorf("no subscription with ack ID %q", ackID)
+	}
+	return sub.ack(seq), nil
+}
+
+// receive adds the events of the event bus until unsubscribed.
+func (sub *ackSubscription) receive(ch <-chan interface{}) {
+	for event := range ch {
+		sub.add(event.(tmtypes.TMEventData))
+	}
+	sub.env.ackSubscriptionsMtx.Lock()
+	if sub.env.ackSubscriptions[sub.id] == sub {
+		delete(sub.env.ackSubscriptions, sub.id)
+	}
+	sub.env.ackSubscriptionsMtx.Unlock()
+}
+
+// add numbers and sends the event, and keeps it until acked. The subscription
+// is dropped if it has too many events not acked yet.
+func (sub *ackSubscription) add(data tmtypes.TMEventData) {
+	sub.mtx.Lock()
+	defer sub.mtx.Unlock()
+	if sub.closed {
+		return
+	}
+
+	if len(sub.events) >= sub.env.Config.MaxUnackedEvents {
+		sub.closed = true
+		sub.env.Logger.Error("Dropping the subscription with too many unacked events", "ackID", sub.id,
+			"query", sub.query, "unacked", len(sub.events))
+		sub.wsCtx.TryWriteRPCResponse(rpctypes.NewRPCErrorResponse(sub.eventRPCID(),
+			rpctypes.CodeTooManyUnackedEvents, "Too many unacked events",
+			fmt.Sprintf("subscription %q dropped after %d unacked events", sub.id, len(sub.events))))
+		// the events are still received, and dropped, until unsubscribed
+		go sub.drop()
+		return
+	}
+
+	if newBlock, ok := data.(tmtypes.EventDataNewBlock); ok && sub.headerOnly {
+		data = newBlock.HeaderOnly()
+	}
+	sub.events = append(sub.events, &ackEvent{seq: sub.nextSeq, data: data})
+	sub.nextSeq++
+	sub.sendPending()
+}
+
+// ack drops the events up to seq, resends the ones which failed to be sent,
+// and returns the number of events not acked yet.
+func (sub *ackSubscription) ack(seq int64) int {
+	sub.mtx.Lock()
+	defer sub.mtx.Unlock()
+	i := 0
+	for i < len(sub.events) && sub.events[i].seq <= seq {
+		i++
+	}
+	sub.events = sub.events[i:]
+	sub.sendPending()
+	return len(sub.events)
+}
+
+// resume sends the events not acked yet, and the next ones, to the connection
+// of wsCtx, in the version of the event schema. It returns false if the
+// subscription was dropped.
+func (sub *ackSubscription) resume(wsCtx rpctypes.WSRPCContext, schema int) bool {
+	sub.mtx.Lock()
+	defer sub.mtx.Unlock()
+	if sub.closed {
+		return false
+	}
+	sub.wsCtx = wsCtx
+	sub.schema = schema
+	for _, e := range sub.events {
+		e.sent = false
+	}
+	sub.sendPending()
+	return true
+}
+
+// sendPending sends the events not sent yet to the current connection, in
+// order, until its write buffer is full. Must be called with sub.mtx held.
+func (sub *ackSubscription) sendPending() {
+	for _, e := range sub.events {
+		if e.sent {
+			continue
+		}
+		tmResult := sub.env.newResultEvent(sub.query, e.data, sub.schema)
+		tmResult.Seq = e.seq
+		resp := rpctypes.NewRPCSuccessResponse(sub.wsCtx.Codec(), sub.eventRPCID(), tmResult)
+		if !sub.wsCtx.TryWriteRPCResponse(resp) {
+			return
+		}
+		e.sent = true
+	}
+}
+
+func (sub *ackSubscription) eventRPCID() rpctypes.JSONRPCStringID {
+	return rpctypes.JSONRPCStringID(fmt.Sprintf("%v#event", sub.wsCtx.Request.ID))
+}
@@ -0,0 +1,172 @@
+package core
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	amino "github.com/tendermint/go-amino"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/lib/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// mockWSConn records the responses written to it, and fails to write them
+// while full.
+type mockWSConn struct {
+	eventBus *types.EventBus
+	cdc      *amino.Codec
+
+	mtx       sync.Mutex
+	full      bool
+	responses []rpctypes.RPCResponse
+}
+
+func newMockWSConn(eventBus *types.EventBus) *mockWSConn {
+	cdc := amino.NewCodec()
+	ctypes.RegisterAmino(cdc)
+	return &mockWSConn{eventBus: eventBus, cdc: cdc}
+}
+
+func (c *mockWSConn) GetRemoteAddr() string                        { return "127.0.0.1:1234" }
+func (c *mockWSConn) GetEventSubscriber() rpctypes.EventSubscriber { return c.eventBus }
+func (c *mockWSConn) Codec() *amino.Codec                          { return c.cdc }
+func (c *mockWSConn) WriteRPCResponse(resp rpctypes.RPCResponse)   { c.TryWriteRPCResponse(resp) }
+
+func (c *mockWSConn) TryWriteRPCResponse(resp rpctypes.RPCResponse) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.full {
+		return false
+	}
+	c.responses = append(c.responses, resp)
+	return true
+}
+
+func (c *mockWSConn) setFull(full bool) {
+	c.mtx.Lock()
+	c.full = full
+	c.mtx.Unlock()
+}
+
+// seqs returns the seqs of the events received, or -1 for an error.
+func (c *mockWSConn) seqs(t *testing.T) []int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	seqs := make([]int64, len(c.responses))
+	for i, resp := range c.responses {
+		if resp.Error != nil {
+			seqs[i] = -1
+			continue
+		}
+		var result ctypes.ResultEvent
+		require.NoError(t, c.cdc.UnmarshalJSON(resp.Result, &result))
+		seqs[i] = result.Seq
+	}
+	return seqs
+}
+
+func TestSubscribeAck(t *testing.T) {
+	env := &Environment{Logger: log.TestingLogger(), Config: *cfg.DefaultRPCConfig()}
+	env.Config.MaxUnackedEvents = 3
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	defer eventBus.Stop()
+
+	query := "tm.event = 'NewRoundStep'"
+	publish := func(n int) {
+		for i := 0; i < n; i++ {
+			require.NoError(t, eventBus.PublishEventNewRoundStep(types.EventDataRoundState{Height: 1}))
+		}
+	}
+	waitSeqs := func(conn *mockWSConn, expected ...int64) {
+		for start := time.Now(); time.Since(start) < time.Second; time.Sleep(10 * time.Millisecond) {
+			if assert.ObjectsAreEqual(expected, conn.seqs(t)) {
+				return
+			}
+		}
+		t.Fatalf("expected the events %v, got %v", expected, conn.seqs(t))
+	}
+
+	conn := newMockWSConn(eventBus)
+	wsCtx := rpctypes.WSRPCContext{WSRPCConnection: conn}
+	_, err := env.Subscribe(wsCtx, query, false, "id", 0)
+	require.NoError(t, err)
+	_, err = env.Subscribe(wsCtx, "tm.event = 'Tx'", false, "id", 0)
+	assert.Error(t, err, "another query")
+
+	publish(2)
+	waitSeqs(conn, 1, 2)
+	res, err := env.Ack(wsCtx, "id", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Unacked)
+
+	// the events not acked are resent to the new connection
+	conn.setFull(true)
+	publish(1)
+	conn2 := newMockWSConn(eventBus)
+	_, err = env.Subscribe(rpctypes.WSRPCContext{WSRPCConnection: conn2}, query, false, "id", 0)
+	require.NoError(t, err)
+	waitSeqs(conn2, 2, 3)
+	_, err = env.Ack(wsCtx, "id", 3)
+	require.NoError(t, err)
+
+	// the subscription is dropped after too many unacked events
+	publish(4)
+	waitSeqs(conn2, 2, 3, 4, 5, 6, -1)
+	time.Sleep(100 * time.Millisecond)
+	_, err = env.Ack(wsCtx, "id", 6)
+	assert.Error(t, err)
+
+	_, err = env.Subscribe(wsCtx, query, false, "id2", 0)
+	require.NoError(t, err)
+	_, err = env.Unsubscribe(wsCtx, query, "id2")
+	require.NoError(t, err)
+	_, err = env.Ack(wsCtx, "id2", 1)
+	assert.Error(t, err)
+}
+
+func TestNewResultEvent(t *testing.T) {
+	env := &Environment{Logger: log.TestingLogger()}
+	tx := types.EventDataTx{TxResult: types.TxResult{Height: 1, Tx: types.Tx("tx")}}
+	tx.Result.Events = []abci.Event{
+		{Type: "transfer", Attributes: []cmn.KVPair{{Key: []byte("sender"), Value: []byte("alice")}}},
+	}
+	query := "tm.event = 'Tx'"
+
+	res := env.newResultEvent(query, tx, ctypes.EventSchemaV1)
+	txV1 := tx
+	txV1.Result.Tags = []cmn.KVPair{{Key: []byte("transfer.sender"), Value: []byte("alice")}}
+	txV1.Result.Events = nil
+	assert.Equal(t, &ctypes.ResultEvent{Query: query, Data: txV1}, res)
+
+	res = env.newResultEvent(query, tx, ctypes.EventSchemaV3)
+	assert.Equal(t, &ctypes.ResultEvent{Query: query, Data: tx, Schema: ctypes.EventSchemaV3}, res)
+
+	res = env.newResultEvent(query, tx, ctypes.EventSchemaV2)
+	assert.Equal(t, ctypes.EventSchemaV2, res.Schema)
+	assert.Equal(t, types.TagEncodingString, res.Encoding)
+	assert.Equal(t, []ctypes.Event{
+		{Type: "transfer", Attributes: []ctypes.EventAttribute{{Key: "sender", Value: "alice"}}},
+	}, res.Events)
+	assert.Empty(t, res.Data.(types.EventDataTx).Result.Events)
+	// the data of the other subscribers is unchanged
+	assert.Len(t, tx.Result.Events, 1)
+
+	env.TagEncoding = types.TagEncodingHex
+	res = env.newResultEvent(query, tx, ctypes.EventSchemaV2)
+	assert.Equal(t, types.TagEncodingHex, res.Encoding)
+	assert.Equal(t, "616C696365", res.Events[0].Attributes[0].Value)
+
+	wsCtx := rpctypes.WSRPCContext{WSRPCConnection: newMockWSConn(types.NewEventBus())}
+	_, err := env.Subscribe(wsCtx, query, false, "", ctypes.EventSchemaLatest+1)
+	assert.Error(t, err)
+}
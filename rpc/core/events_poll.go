@@ -0,0 +1,322 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/lib/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// Get the events of a query after a cursor, by long polling: an alternative to
+// the /subscribe of the WebSocket, for the clients which can't use it, e.g.
+// behind proxies blocking the WebSocket upgrades.
+//
+// The events of a query are buffered from its first /events, up to
+// rpc.events_buffer_size events, until it isn't polled for
+// rpc.events_buffer_timeout. /events returns the buffered events after the
+// cursor, waiting for the next one if there is none yet, up to 10 seconds,
+// and the cursor to poll the next ones with. Without a cursor, it returns the
+// events still buffered.
+//
+// If events after the cursor were dropped before being polled, because they
+// didn't fit in the buffer or it expired, the result is flagged as dropped,
+// with the events still buffered.
+//
+// ```shell
+// curl "localhost:26657/events?query=\"tm.event='NewBlock'\""
+// curl "localhost:26657/events?query=\"tm.event='NewBlock'\"&after=\"1551958478212785000-12\""
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+// 	"error": "",
+// 	"result": {
+// 		"events": [
+// 			{
+// 				"query": "tm.event='NewBlock'",
+// 				"data": {
+// 					"type": "tendermint/event/NewBlock",
+// 					"value": {...}
+// 				},
+// 				"seq": "13"
+// 			}
+// 		],
+// 		"cursor": "1551958478212785000-13",
+// 		"dropped": false
+// 	},
+// 	"id": "",
+// 	"jsonrpc": "2.0"
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                             |
+// |-----------+--------+---------+----------+-----------------------------------------|
+// | query     | string | ""      | true     | Query, as for /subscribe                |
+// | after     | string | ""      | false    | Cursor of the last event polled         |
+// | schema    | int    | 1       | false    | Version of the schema of the events     |
+//
+// The call returns once ctx is done, e.g. the client disconnected, with the
+// error of ctx.
+func (env *Environment) Events(ctx context.Context, query string, after string, schema int) (*ctypes.ResultEvents, error) {
+	if env.Config.EventsBufferSize <= 0 {
+		return nil, errors.New("/events is disabled")
+	}
+	schema, err := eventSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	q, err := env.parseEventQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := parsePollCursor(after)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := env.pollBufferFor(q, query)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.done()
+
+	timeout := time.NewTimer(subscribeTimeout)
+	defer timeout.Stop()
+	for {
+		events, next, dropped, newEventCh := buf.poll(cursor, maxPerPage)
+		if len(events) > 0 || dropped {
+			result := &ctypes.ResultEvents{
+				Events:  make([]*ctypes.ResultEvent, len(events)),
+				Cursor:  next.String(),
+				Dropped: dropped,
+			}
+			for i, e := range events {
+				result.Events[i] = env.newResultEvent(query, e.data, schema)
+				result.Events[i].Seq = e.seq
+			}
+			return result, nil
+		}
+		select {
+		case <-newEventCh:
+		case <-timeout.C:
+			return &ctypes.ResultEvents{Events: []*ctypes.ResultEvent{}, Cursor: next.String()}, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// pollCursor is the position of an event in the pollBuffer of an epoch.
+type pollCursor struct {
+	epoch int64 // of the buffer
+	seq   int64
+}
+
+func (c pollCursor) String() string {
+	return fmt.Sprintf("%d-%d", c.epoch, c.seq)
+}
+
+// parsePollCursor parses a cursor returned by /events, empty for none.
+func parsePollCursor(s string) (pollCursor, error) {
+	if s == "" {
+		return pollCursor{}, nil
+	}
+	parts := strings.Split(s, "-")
+	if len(parts) == 2 {
+		epoch, err1 := strconv.ParseInt(parts[0], 10, 64)
+		seq, err2 := strconv.ParseInt(parts[1], 10, 64)
+		if err1 == nil && err2 == nil && epoch > 0 && seq >= 0 {
+			return pollCursor{epoch: epoch, seq: seq}, nil
+		}
+	}
+	return pollCursor{}, fmt.Errorf("invalid cursor %q", s)
+}
+
+// pollBuffer buffers the last events of a query for /events, until it isn't
+// polled for rpc.events_buffer_timeout.
+type pollBuffer struct {
+	env   *Environment
+	query string
+	q     tmpubsub.Query
+	epoch int64 // unique to the buffer, to tell its cursors from the others
+
+	mtx        sync.Mutex
+	events     []*pollEvent  // by seq, the last ones
+	nextSeq    int64         // of the next event
+	newEventCh chan struct{} // closed on the next event
+	polling    int           // number of /events in progress
+	lastPoll   time.Time
+	closed     bool
+}
+
+// pollEvent is an event of a pollBuffer.
+type pollEvent struct {
+	seq  int64
+	data tmtypes.TMEventData
+}
+
+// subscriber is the subscriber of the event bus of the buffer.
+func (buf *pollBuffer) subscriber() string {
+	return fmt.Sprintf("events#%d", buf.epoch)
+}
+
+// pollBufferFor returns the buffer of the query, created if there is none, for
+// a /events, which must call done when it's over.
+func (env *Environment) pollBufferFor(q tmpubsub.Query, query string) (*pollBuffer, error) {
+	env.pollBuffersMtx.Lock()
+	defer env.pollBuffersMtx.Unlock()
+	if buf, ok := env.pollBuffers[query]; ok && buf.start() {
+		return buf, nil
+	}
+	if max := env.Config.MaxEventsBuffers; max > 0 && len(env.pollBuffers) >= max {
+		return nil, &rpctypes.RPCError{
+			Code:    rpctypes.CodeTooManySubscriptions,
+			Message: "Too many subscriptions",
+			Data:    fmt.Sprintf("max %d queries buffered for /events", max),
+		}
+	}
+
+	buf := &pollBuffer{
+		env:        env,
+		query:      query,
+		q:          q,
+		epoch:      time.Now().UnixNano(),
+		nextSeq:    1,
+		newEventCh: make(chan struct{}),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), subscribeTimeout)
+	defer cancel()
+	ch := make(chan interface{})
+	if err := env.EventBus.Subscribe(ctx, buf.subscriber(), q, ch); err != nil {
+		return nil, err
+	}
+	buf.start()
+	if env.pollBuffers == nil {
+		env.pollBuffers = make(map[string]*pollBuffer)
+	}
+	env.pollBuffers[query] = buf
+	go buf.receive(ch)
+	go buf.expireRoutine()
+	return buf, nil
+}
+
+// start starts a /events of the buffer, unless it expired.
+func (buf *pollBuffer) start() bool {
+	buf.mtx.Lock()
+	defer buf.mtx.Unlock()
+	if buf.closed {
+		return false
+	}
+	buf.polling++
+	buf.lastPoll = time.Now()
+	return true
+}
+
+// done ends a /events of the buffer.
+func (buf *pollBuffer) done() {
+	buf.mtx.Lock()
+	defer buf.mtx.Unlock()
+	buf.polling--
+	buf.lastPoll = time.Now()
+}
+
+// poll returns the events after the cursor, up to max, the cursor of the last
+// one, whether events after the cursor were dropped, and a channel closed on
+// the next event.
+func (buf *pollBuffer) poll(after pollCursor, max int) (events []*pollEvent, next pollCursor, dropped bool, newEventCh <-chan struct{}) {
+	buf.mtx.Lock()
+	defer buf.mtx.Unlock()
+
+	seq := after.seq
+	firstSeq := buf.nextSeq
+	if len(buf.events) > 0 {
+		firstSeq = buf.events[0].seq
+	}
+	if after.epoch != buf.epoch {
+		// a cursor of an expired buffer, or none
+		dropped = after != pollCursor{}
+		seq = 0
+	} else if seq < firstSeq-1 {
+		dropped = true
+	}
+
+	for _, e := range buf.events {
+		if e.seq <= seq {
+			continue
+		}
+		if len(events) == max {
+			break
+		}
+		events = append(events, e)
+		seq = e.seq
+	}
+	return events, pollCursor{epoch: buf.epoch, seq: seq}, dropped, buf.newEventCh
+}
+
+// receive adds the events of the event bus until unsubscribed.
+func (buf *pollBuffer) receive(ch <-chan interface{}) {
+	for event := range ch {
+		buf.add(event.(tmtypes.TMEventData))
+	}
+}
+
+// add numbers and buffers the event, dropping the oldest one if the buffer is
+// full, and wakes up the /events waiting for it.
+func (buf *pollBuffer) add(data tmtypes.TMEventData) {
+	buf.mtx.Lock()
+	defer buf.mtx.Unlock()
+	if buf.closed {
+		return
+	}
+	if len(buf.events) >= buf.env.Config.EventsBufferSize {
+		buf.events = buf.events[1:]
+	}
+	buf.events = append(buf.events, &pollEvent{seq: buf.nextSeq, data: data})
+	buf.nextSeq++
+	close(buf.newEventCh)
+	buf.newEventCh = make(chan struct{})
+}
+
+// expireRoutine closes and unsubscribes the buffer once it isn't polled for
+// rpc.events_buffer_timeout.
+func (buf *pollBuffer) expireRoutine() {
+	for {
+		buf.mtx.Lock()
+		wait := buf.lastPoll.Add(buf.env.Config.EventsBufferTimeout).Sub(time.Now())
+		expired := buf.polling == 0 && wait <= 0
+		if expired {
+			buf.closed = true
+		}
+		buf.mtx.Unlock()
+
+		if expired {
+			buf.env.pollBuffersMtx.Lock()
+			if buf.env.pollBuffers[buf.query] == buf {
+				delete(buf.env.pollBuffers, buf.query)
+			}
+			buf.env.pollBuffersMtx.Unlock()
+			if err := buf.env.EventBus.Unsubscribe(context.Background(), buf.subscriber(), buf.q); err != nil {
+				buf.env.Logger.Error("Failed to unsubscribe", "query", buf.query, "err", err)
+			}
+			return
+		}
+		if wait <= 0 {
+			// being polled
+			wait = subscribeTimeout
+		}
+		time.Sleep(wait)
+	}
+}
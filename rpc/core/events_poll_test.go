@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestEvents(t *testing.T) {
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	defer eventBus.Stop()
+	env := &Environment{Logger: log.TestingLogger(), Config: *cfg.DefaultRPCConfig(), EventBus: eventBus}
+	env.Config.EventsBufferSize = 3
+	env.Config.EventsBufferTimeout = 100 * time.Millisecond
+
+	query := "tm.event = 'NewRoundStep'"
+	publish := func(n int) {
+		for i := 0; i < n; i++ {
+			require.NoError(t, eventBus.PublishEventNewRoundStep(types.EventDataRoundState{Height: 1}))
+		}
+	}
+	seqs := func(res *ctypes.ResultEvents) []int64 {
+		seqs := make([]int64, len(res.Events))
+		for i, e := range res.Events {
+			seqs[i] = e.Seq
+		}
+		return seqs
+	}
+	buffered := func() bool {
+		env.pollBuffersMtx.Lock()
+		defer env.pollBuffersMtx.Unlock()
+		_, ok := env.pollBuffers[query]
+		return ok
+	}
+
+	// the first /events waits for the next event
+	resCh := make(chan *ctypes.ResultEvents)
+	go func() {
+		res, err := env.Events(context.Background(), query, "", 0)
+		require.NoError(t, err)
+		resCh <- res
+	}()
+	for !buffered() {
+		time.Sleep(10 * time.Millisecond)
+	}
+	publish(1)
+	res := <-resCh
+	assert.Equal(t, []int64{1}, seqs(res))
+	assert.False(t, res.Dropped)
+
+	// the events which don't fit in the buffer are dropped
+	publish(4)
+	time.Sleep(10 * time.Millisecond)
+	res, err := env.Events(context.Background(), query, res.Cursor, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4, 5}, seqs(res))
+	assert.True(t, res.Dropped)
+
+	publish(1)
+	time.Sleep(10 * time.Millisecond)
+	res, err = env.Events(context.Background(), query, res.Cursor, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{6}, seqs(res))
+	assert.False(t, res.Dropped)
+
+	// the buffer expires when not polled
+	time.Sleep(300 * time.Millisecond)
+	assert.False(t, buffered())
+	res, err = env.Events(context.Background(), query, res.Cursor, 0)
+	require.NoError(t, err)
+	assert.Empty(t, res.Events)
+	assert.True(t, res.Dropped)
+
+	_, err = env.Events(context.Background(), query, "invalid", 0)
+	assert.Error(t, err)
+	_, err = env.Events(context.Background(), query, "", ctypes.EventSchemaLatest+1)
+	assert.Error(t, err)
+
+	env.Config.EventsBufferSize = 0
+	_, err = env.Events(context.Background(), query, "", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	cmn "github.com/tendermint/tendermint/libs/common"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// Get the evidence committed in the block at the given height.
+// If no height is provided, it will fetch the evidence of the latest block.
+//
+// ```shell
+// curl 'localhost:26657/evidence?height=10'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// err := client.Start()
+// if err != nil {
+//   // handle error
+// }
+// defer client.Stop()
+// height := int64(10)
+// result, err := client.Evidence(context.Background(), &height)
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "height": "10",
+//     "evidence": [
+//       {
+//         "type": "tendermint/DuplicateVoteEvidence",
+//         "value": {
+//           "PubKey": {
+//             "type": "tendermint/PubKeyEd25519",
+//             "value": "9tK9IT+FPdf2qm+5c2qaxi10sWP+3erWTKgftn2PaQM="
+//           },
+//           "VoteA": {...},
+//           "VoteB": {...}
+//         }
+//       }
+//     ]
+//   }
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type  | Default | Required | Description                                 |
+// |-----------+-------+---------+----------+---------------------------------------------|
+// | height    | int64 | 0       | false    | Height of the block (0 means latest height) |
+func (env *Environment) Evidence(heightPtr *int64) (*ctypes.ResultEvidence, error) {
+	height, err := env.getStoredHeight(env.BlockStore.Height(), heightPtr)
+	if err != nil {
+		return nil, err
+	}
+
+	eis := env.EvidenceIndex.CommittedEvidence(height)
+	evidence := make([]types.Evidence, len(eis))
+	for i, ei := range eis {
+		evidence[i] = ei.Evidence
+	}
+	return &ctypes.ResultEvidence{Height: height, Evidence: evidence}, nil
+}
+
+// EvidenceSearch allows you to search for the evidence committed against a
+// validator. It returns a list of evidence (maximum ?per_page entries), by
+// increasing height of the blocks it was committed in, and the total count.
+//
+// ```shell
+// curl 'localhost:26657/evidence_search?address=0x9A0F8AB3BA47C1F1B4DD7A4A36BF0ADA7D32C2AB'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// err := client.Start()
+// if err != nil {
+//   // handle error
+// }
+// defer client.Stop()
+// result, err := client.EvidenceSearch(context.Background(), address, 1, 30)
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "evidence": [
+//       {
+//         "height": "10",
+//         "evidence": {
+//           "type": "tendermint/DuplicateVoteEvidence",
+//           "value": {...}
+//         }
+//       }
+//     ],
+//     "total_count": "1"
+//   }
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                           |
+// |-----------+--------+---------+----------+---------------------------------------|
+// | address   | []byte | nil     | true     | Address of the validator              |
+// | page      | int    | 1       | false    | Page number (1-based)                 |
+// | per_page  | int    | 30      | false    | Number of entries per page (max: 100) |
+//
+// ### Returns
+//
+// - `height`: `int` - height of the block where this evidence was committed in
+// - `evidence`: the `types.Evidence` object
+func (env *Environment) EvidenceSearch(address []byte, page, perPage int) (*ctypes.ResultEvidenceSearch, error) {
+	eis := env.EvidenceIndex.SearchCommittedEvidence(address)
+
+	totalCount := len(eis)
+	perPage = validatePerPage(perPage)
+	page = validatePage(page, perPage, totalCount)
+	skipCount := validateSkipCount(page, perPage)
+
+	apiResults := make([]*ctypes.ResultCommittedEvidence, cmn.MinInt(perPage, totalCount-skipCount))
+	for i := 0; i < len(apiResults); i++ {
+		ei := eis[skipCount+i]
+		apiResults[i] = &ctypes.ResultCommittedEvidence{
+			Height:   ei.CommittedHeight,
+			Evidence: ei.Evidence,
+		}
+	}
+
+	return &ctypes.ResultEvidenceSearch{Evidence: apiResults, TotalCount: totalCount}, nil
+}
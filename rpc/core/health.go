@@ -1,7 +1,17 @@
 package core
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
 )
 
 // Get node health. Returns empty result (200 OK) on success, no response - in
@@ -18,7 +28,7 @@ import (
 //   // handle error
 // }
 // defer client.Stop()
-// result, err := client.Health()
+// result, err := client.Health(context.Background())
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -31,6 +41,180 @@ import (
 // 	"jsonrpc": "2.0"
 // }
 // ```
-func Health() (*ctypes.ResultHealth, error) {
+func (env *Environment) Health() (*ctypes.ResultHealth, error) {
 	return &ctypes.ResultHealth{}, nil
 }
+
+const (
+	defaultChainHealthBlocks = 100
+	maxChainHealthBlocks     = 1000
+)
+
+// Get the health of the chain over the last blocks: the intervals between
+// the blocks, the rounds they were committed at, the voting power missing
+// from their commits, and the depth of the mempool, for dashboards and SLA
+// checks.
+//
+// The blocks pruned from the block store are not included, and the missing
+// power of the blocks whose validator set was pruned is not counted.
+//
+// ```shell
+// curl 'localhost:26657/chain_health?blocks=100'
+// ```
+//
+// ```go
+// client := client.NewHTTP("tcp://0.0.0.0:26657", "/websocket")
+// err := client.Start()
+// if err != nil {
+//   // handle error
+// }
+// defer client.Stop()
+// result, err := client.ChainHealth(context.Background(), 100)
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+// 	"error": "",
+// 	"result": {
+// 		"from_height": "5142",
+// 		"to_height": "5241",
+// 		"block_interval": {
+// 			"avg": "1012000000",
+// 			"p50": "1004000000",
+// 			"p90": "1025000000",
+// 			"p99": "2101000000",
+// 			"max": "3052000000"
+// 		},
+// 		"avg_round": 0.02,
+// 		"max_round": "1",
+// 		"missing_power_ratio": 0.005,
+// 		"avg_txs": 12.5,
+// 		"mempool_size": "42"
+// 	},
+// 	"id": "",
+// 	"jsonrpc": "2.0"
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type | Default | Required | Description                           |
+// |-----------+------+---------+----------+---------------------------------------|
+// | blocks    | int  | 100     | false    | Number of the last blocks (max: 1000) |
+func (env *Environment) ChainHealth(ctx context.Context, blocks int) (*ctypes.ResultChainHealth, error) {
+	if blocks <= 0 {
+		blocks = defaultChainHealthBlocks
+	} else if blocks > maxChainHealthBlocks {
+		blocks = maxChainHealthBlocks
+	}
+	toHeight := env.BlockStore.Height()
+	if toHeight == 0 {
+		return nil, errors.New("no block committed yet")
+	}
+	// The blocks below the base are pruned.
+	fromHeight := cmn.MaxInt64(toHeight-int64(blocks)+1, env.BlockStore.Base())
+
+	res := &ctypes.ResultChainHealth{
+		FromHeight:  fromHeight,
+		ToHeight:    toHeight,
+		MempoolSize: env.Mempool.Size(),
+	}
+	var (
+		intervals     []time.Duration
+		lastTime      time.Time
+		rounds, txs   int64
+		missingRatios float64
+		commits       int
+	)
+	for height := fromHeight; height <= toHeight; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		meta := env.BlockStore.LoadBlockMeta(height)
+		if meta == nil {
+			return nil, fmt.Errorf("no block meta at height %d", height)
+		}
+		if height > fromHeight {
+			intervals = append(intervals, meta.Header.Time.Sub(lastTime))
+		}
+		lastTime = meta.Header.Time
+		txs += meta.Header.NumTxs
+
+		commit := env.BlockStore.LoadBlockCommit(height)
+		if commit == nil {
+			commit = env.BlockStore.LoadSeenCommit(height)
+		}
+		if commit == nil {
+			continue
+		}
+		round := commit.Round()
+		rounds += int64(round)
+		if round > res.MaxRound {
+			res.MaxRound = round
+		}
+		if ratio, ok := env.missingPowerRatio(height, commit); ok {
+			missingRatios += ratio
+			commits++
+		}
+	}
+
+	n := toHeight - fromHeight + 1
+	res.BlockInterval = blockIntervalStats(intervals)
+	res.AvgRound = float64(rounds) / float64(n)
+	res.AvgTxs = float64(txs) / float64(n)
+	if commits > 0 {
+		res.MissingPowerRatio = missingRatios / float64(commits)
+	}
+	return res, nil
+}
+
+// missingPowerRatio returns the ratio of the voting power of the validators
+// of the height missing from its commit, false if its validator set was
+// pruned.
+func (env *Environment) missingPowerRatio(height int64, commit *types.Commit) (float64, bool) {
+	vals, err := sm.LoadValidators(env.StateDB, height)
+	if err != nil || vals.Size() != commit.Size() || vals.TotalVotingPower() == 0 {
+		return 0, false
+	}
+	var missing int64
+	for i, precommit := range commit.Precommits {
+		if precommit == nil {
+			_, val := vals.GetByIndex(i)
+			missing += val.VotingPower
+		}
+	}
+	return float64(missing) / float64(vals.TotalVotingPower()), true
+}
+
+// blockIntervalStats returns the average, the percentiles and the max of the
+// intervals.
+func blockIntervalStats(intervals []time.Duration) ctypes.BlockIntervalStats {
+	if len(intervals) == 0 {
+		return ctypes.BlockIntervalStats{}
+	}
+	sorted := make([]time.Duration, len(intervals))
+	copy(sorted, intervals)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, interval := range sorted {
+		sum += interval
+	}
+	// nearest rank
+	percentile := func(p int) time.Duration {
+		rank := (p*len(sorted) + 99) / 100
+		if rank < 1 {
+			rank = 1
+		}
+		return sorted[rank-1]
+	}
+	return ctypes.BlockIntervalStats{
+		Avg: sum / time.Duration(len(sorted)),
+		P50: percentile(50),
+		P90: percentile(90),
+		P99: percentile(99),
+		Max: sorted[len(sorted)-1],
+	}
+}
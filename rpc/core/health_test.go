@@ -0,0 +1,32 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+func TestBlockIntervalStats(t *testing.T) {
+	assert.Equal(t, ctypes.BlockIntervalStats{}, blockIntervalStats(nil))
+
+	intervals := make([]time.Duration, 100)
+	for i := range intervals {
+		intervals[i] = time.Duration(100-i) * time.Second
+	}
+	assert.Equal(t, ctypes.BlockIntervalStats{
+		Avg: 50500 * time.Millisecond,
+		P50: 50 * time.Second,
+		P90: 90 * time.Second,
+		P99: 99 * time.Second,
+		Max: 100 * time.Second,
+	}, blockIntervalStats(intervals))
+	// the intervals are not sorted in place
+	assert.Equal(t, 100*time.Second, intervals[0])
+
+	assert.Equal(t, ctypes.BlockIntervalStats{
+		Avg: time.Second, P50: time.Second, P90: time.Second, P99: time.Second, Max: time.Second,
+	}, blockIntervalStats([]time.Duration{time.Second}))
+}
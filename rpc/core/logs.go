@@ -0,0 +1,61 @@
+package core
+
+import (
+	"errors"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// UnsafeLogs returns the last info and error lines logged by the module, or
+// by every module if none is given, the oldest first. At most
+// log_buffer_lines lines are kept for each module.
+//
+// ```shell
+// curl 'localhost:26657/unsafe_logs?module="consensus"&lines=2'
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "modules": {
+//       "consensus": [
+//         "I[2019-03-04T10:21:09.371418Z] Finalizing commit of block with 0 txs module=consensus height=41 hash=5E1A3A7D9F60D8B1C2E3F4A5B6C7D8E9F0A1B2C3 root=0100000000000000",
+//         "I[2019-03-04T10:21:10.384512Z] enterNewRound(42/0). Current: 42/0/RoundStepNewHeight module=consensus height=42 round=0"
+//       ]
+//     }
+//   }
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                                 |
+// |-----------+--------+---------+----------+---------------------------------------------|
+// | module    | string | ""      | false    | Module of the lines ("" means every module) |
+// | lines     | int    | 0       | false    | Number of lines per module (0 means all)    |
+func (env *Environment) UnsafeLogs(module string, lines int) (*ctypes.ResultUnsafeLogs, error) {
+	if env.RecentLogs == nil {
+		return nil, errors.New("the recent logs are disabled (log_buffer_lines is 0)")
+	}
+	if lines < 0 {
+		return nil, errors.New("lines can't be negative")
+	}
+
+	modules := []string{module}
+	if module == "" {
+		modules = env.RecentLogs.Modules()
+	}
+	result := &ctypes.ResultUnsafeLogs{Modules: make(map[string][]string, len(modules))}
+	for _, module := range modules {
+		moduleLines := env.RecentLogs.Lines(module)
+		if lines > 0 && len(moduleLines) > lines {
+			moduleLines = moduleLines[len(moduleLines)-lines:]
+		}
+		result.Modules[module] = moduleLines
+	}
+	return result, nil
+}
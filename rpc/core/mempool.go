@@ -3,11 +3,14 @@ package core
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/pkg/errors"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+	mempl "github.com/tendermint/tendermint/mempool"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpcserver "github.com/tendermint/tendermint/rpc/lib/server"
 	"github.com/tendermint/tendermint/types"
@@ -29,7 +32,7 @@ import (
 //   // handle error
 // }
 // defer client.Stop()
-// result, err := client.BroadcastTxAsync("123")
+// result, err := client.BroadcastTxAsync(context.Background(), "123")
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -41,7 +44,9 @@ import (
 // 		"hash": "E39AAB7A537ABAA237831742DCE1117F187C3C52",
 // 		"log": "",
 // 		"data": "",
-// 		"code": "0"
+// 		"code": "0",
+// 		"info": "",
+// 		"codespace": ""
 // 	},
 // 	"id": "",
 // 	"jsonrpc": "2.0"
@@ -50,11 +55,16 @@ import (
 //
 // ### Query Parameters
 //
-// | Parameter | Type | Default | Required | Description     |
-// |-----------+------+---------+----------+-----------------|
-// | tx        | Tx   | nil     | true     | The transaction |
-func BroadcastTxAsync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
-	err := mempool.CheckTx(tx, nil)
+// | Parameter | Type   | Default | Required | Description                                       |
+// |-----------+--------+---------+----------+---------------------------------------------------|
+// | tx        | Tx     | nil     | true     | The transaction                                   |
+// | mode      | string | ""      | false    | "local" (not gossiped) or "gossip" (not proposed) |
+func (env *Environment) BroadcastTxAsync(tx types.Tx, mode string) (*ctypes.ResultBroadcastTx, error) {
+	txMode, err := parseTxMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	err = env.Mempool.CheckTxWithMode(tx, txMode, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +84,7 @@ func BroadcastTxAsync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 //   // handle error
 // }
 // defer client.Stop()
-// result, err := client.BroadcastTxSync("456")
+// result, err := client.BroadcastTxSync(context.Background(), "456")
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -87,6 +97,8 @@ func BroadcastTxAsync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 // 		"code": "0",
 // 		"data": "",
 // 		"log": "",
+// 		"info": "",
+// 		"codespace": "",
 // 		"hash": "0D33F2F03A5234F38706E43004489E061AC40A2E"
 // 	},
 // 	"error": ""
@@ -95,12 +107,17 @@ func BroadcastTxAsync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 //
 // ### Query Parameters
 //
-// | Parameter | Type | Default | Required | Description     |
-// |-----------+------+---------+----------+-----------------|
-// | tx        | Tx   | nil     | true     | The transaction |
-func BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
+// | Parameter | Type   | Default | Required | Description                                       |
+// |-----------+--------+---------+----------+---------------------------------------------------|
+// | tx        | Tx     | nil     | true     | The transaction                                   |
+// | mode      | string | ""      | false    | "local" (not gossiped) or "gossip" (not proposed) |
+func (env *Environment) BroadcastTxSync(tx types.Tx, mode string) (*ctypes.ResultBroadcastTx, error) {
+	txMode, err := parseTxMode(mode)
+	if err != nil {
+		return nil, err
+	}
 	resCh := make(chan *abci.Response, 1)
-	err := mempool.CheckTx(tx, func(res *abci.Response) {
+	err = env.Mempool.CheckTxWithMode(tx, txMode, func(res *abci.Response) {
 		resCh <- res
 	})
 	if err != nil {
@@ -109,10 +126,12 @@ func BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 	res := <-resCh
 	r := res.GetCheckTx()
 	return &ctypes.ResultBroadcastTx{
-		Code: r.Code,
-		Data: r.Data,
-		Log:  r.Log,
-		Hash: tx.Hash(),
+		Code:      r.Code,
+		Data:      r.Data,
+		Log:       r.Log,
+		Info:      r.Info,
+		Codespace: r.Codespace,
+		Hash:      tx.Hash(),
 	}, nil
 }
 
@@ -122,6 +141,9 @@ func BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 // If CheckTx or DeliverTx fail, no error will be returned, but the returned result
 // will contain a non-OK ABCI code.
 //
+// The identical calls (same tx and mode) made while one is in flight are
+// coalesced onto it: the tx is checked once, and they all return its result.
+//
 // ```shell
 // curl 'localhost:26657/broadcast_tx_commit?tx="789"'
 // ```
@@ -133,7 +155,7 @@ func BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 //   // handle error
 // }
 // defer client.Stop()
-// result, err := client.BroadcastTxCommit("789")
+// result, err := client.BroadcastTxCommit(context.Background(), "789")
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -162,19 +184,69 @@ func BroadcastTxSync(tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 //
 // ### Query Parameters
 //
-// | Parameter | Type | Default | Required | Description     |
-// |-----------+------+---------+----------+-----------------|
-// | tx        | Tx   | nil     | true     | The transaction |
-func BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
+// | Parameter | Type   | Default | Required | Description                                       |
+// |-----------+--------+---------+----------+---------------------------------------------------|
+// | tx        | Tx     | nil     | true     | The transaction                                   |
+// | mode      | string | ""      | false    | "local" (not gossiped) or "gossip" (not proposed) |
+//
+// The call returns once ctx is done, e.g. the client disconnected, with the
+// error of ctx, while the tx is still broadcast.
+func (env *Environment) BroadcastTxCommit(ctx context.Context, tx types.Tx, mode string) (*ctypes.ResultBroadcastTxCommit, error) {
+	txMode, err := parseTxMode(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	key := broadcastTxCommitKey{hash: string(tx.Hash()), mode: txMode}
+	env.broadcastTxCommitMtx.Lock()
+	call, ok := env.broadcastTxCommitCalls[key]
+	if !ok {
+		call = &broadcastTxCommitCall{done: make(chan struct{})}
+		if env.broadcastTxCommitCalls == nil {
+			env.broadcastTxCommitCalls = make(map[broadcastTxCommitKey]*broadcastTxCommitCall)
+		}
+		env.broadcastTxCommitCalls[key] = call
+		go func() {
+			call.res, call.err = env.broadcastTxCommit(tx, txMode)
+			env.broadcastTxCommitMtx.Lock()
+			delete(env.broadcastTxCommitCalls, key)
+			env.broadcastTxCommitMtx.Unlock()
+			close(call.done)
+		}()
+	}
+	env.broadcastTxCommitMtx.Unlock()
+
+	select {
+	case <-call.done:
+		return call.res, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type broadcastTxCommitKey struct {
+	hash string
+	mode mempl.TxMode
+}
+
+// broadcastTxCommitCall is a BroadcastTxCommit in flight, whose result is
+// returned to the identical calls made meanwhile.
+type broadcastTxCommitCall struct {
+	done chan struct{} // closed once res and err are set
+	res  *ctypes.ResultBroadcastTxCommit
+	err  error
+}
+
+func (env *Environment) broadcastTxCommit(tx types.Tx, txMode mempl.TxMode) (*ctypes.ResultBroadcastTxCommit, error) {
 	// Subscribe to tx being committed in block.
 	ctx, cancel := context.WithTimeout(context.Background(), subscribeTimeout)
 	defer cancel()
 	deliverTxResCh := make(chan interface{}, 1)
 	q := types.EventQueryTxFor(tx)
-	err := eventBus.Subscribe(ctx, "mempool", q, deliverTxResCh)
+	err := env.EventBus.Subscribe(ctx, "mempool", q, deliverTxResCh)
 	if err != nil {
 		err = errors.Wrap(err, "failed to subscribe to tx")
-		logger.Error("Error on broadcast_tx_commit", "err", err)
+		env.Logger.Error("Error on broadcast_tx_commit", "err", err)
 		return nil, err
 	}
 	defer func() {
@@ -187,16 +259,16 @@ func BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 				break LOOP
 			}
 		}
-		eventBus.Unsubscribe(context.Background(), "mempool", q)
+		env.EventBus.Unsubscribe(context.Background(), "mempool", q)
 	}()
 
 	// Broadcast tx and wait for CheckTx result
 	checkTxResCh := make(chan *abci.Response, 1)
-	err = mempool.CheckTx(tx, func(res *abci.Response) {
+	err = env.Mempool.CheckTxWithMode(tx, txMode, func(res *abci.Response) {
 		checkTxResCh <- res
 	})
 	if err != nil {
-		logger.Error("Error on broadcastTxCommit", "err", err)
+		env.Logger.Error("Error on broadcastTxCommit", "err", err)
 		return nil, fmt.Errorf("Error on broadcastTxCommit: %v", err)
 	}
 	checkTxResMsg := <-checkTxResCh
@@ -226,7 +298,7 @@ func BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 		}, nil
 	case <-time.After(deliverTxTimeout):
 		err = errors.New("Timed out waiting for tx to be included in a block")
-		logger.Error("Error on broadcastTxCommit", "err", err)
+		env.Logger.Error("Error on broadcastTxCommit", "err", err)
 		return &ctypes.ResultBroadcastTxCommit{
 			CheckTx:   *checkTxRes,
 			DeliverTx: abci.ResponseDeliverTx{},
@@ -237,8 +309,15 @@ func BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 
 // Get unconfirmed transactions (maximum ?limit entries) including their number.
 //
+// The transactions can be filtered by sender, one of the values of the
+// `sender` attributes of the events of their CheckTx, by the gas they want,
+// and by how long they've been in the mempool, in seconds. With
+// `fields=hashes`, only their hashes are returned, instead of the
+// transactions.
+//
 // ```shell
 // curl 'localhost:26657/unconfirmed_txs'
+// curl 'localhost:26657/unconfirmed_txs?sender="alice"&min_gas=100&min_age=60&fields="hashes"'
 // ```
 //
 // ```go
@@ -248,7 +327,7 @@ func BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 //   // handle error
 // }
 // defer client.Stop()
-// result, err := client.UnconfirmedTxs()
+// result, err := client.UnconfirmedTxs(context.Background())
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -266,15 +345,34 @@ func BroadcastTxCommit(tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 //
 // ### Query Parameters
 //
-// | Parameter | Type | Default | Required | Description                          |
-// |-----------+------+---------+----------+--------------------------------------|
-// | limit     | int  | 30      | false    | Maximum number of entries (max: 100) |
+// | Parameter | Type   | Default | Required | Description                             |
+// |-----------+--------+---------+----------+-----------------------------------------|
+// | limit     | int    | 30      | false    | Maximum number of entries (max: 100)    |
+// | sender    | string | ""      | false    | Sender of the transactions              |
+// | min_gas   | int64  | 0       | false    | Minimum gas wanted by the transactions  |
+// | min_age   | int    | 0       | false    | Minimum time in the mempool, in seconds |
+// | fields    | string | "txs"   | false    | "txs", or "hashes" for the hashes only  |
 // ```
-func UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
+func (env *Environment) UnconfirmedTxs(limit int, sender string, minGas int64, minAge int, fields string) (*ctypes.ResultUnconfirmedTxs, error) {
 	// reuse per_page validator
 	limit = validatePerPage(limit)
+	if fields != "" && fields != "txs" && fields != "hashes" {
+		return nil, fmt.Errorf("unknown fields %q, expected \"txs\" or \"hashes\"", fields)
+	}
 
-	txs := mempool.ReapMaxTxs(limit)
+	filter := mempl.TxFilter{
+		Sender: sender,
+		MinGas: minGas,
+		MinAge: time.Duration(minAge) * time.Second,
+	}
+	txs := env.Mempool.ReapFilteredTxs(filter, limit)
+	if fields == "hashes" {
+		hashes := make([]cmn.HexBytes, len(txs))
+		for i, tx := range txs {
+			hashes[i] = tx.Hash()
+		}
+		return &ctypes.ResultUnconfirmedTxs{N: len(txs), Hashes: hashes}, nil
+	}
 	return &ctypes.ResultUnconfirmedTxs{N: len(txs), Txs: txs}, nil
 }
 
@@ -291,7 +389,7 @@ func UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
 //   // handle error
 // }
 // defer client.Stop()
-// result, err := client.UnconfirmedTxs()
+// result, err := client.UnconfirmedTxs(context.Background())
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -307,6 +405,105 @@ func UnconfirmedTxs(limit int) (*ctypes.ResultUnconfirmedTxs, error) {
 //   "jsonrpc": "2.0"
 // }
 // ```
-func NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error) {
-	return &ctypes.ResultUnconfirmedTxs{N: mempool.Size()}, nil
+func (env *Environment) NumUnconfirmedTxs() (*ctypes.ResultUnconfirmedTxs, error) {
+	return &ctypes.ResultUnconfirmedTxs{N: env.Mempool.Size()}, nil
+}
+
+// parseTxMode returns the mempool mode of a broadcast tx.
+func parseTxMode(mode string) (mempl.TxMode, error) {
+	switch mode {
+	case "":
+		return mempl.TxModeDefault, nil
+	case "local":
+		return mempl.TxModeLocal, nil
+	case "gossip":
+		return mempl.TxModeGossip, nil
+	default:
+		return 0, fmt.Errorf("unknown mode %q (must be \"\", \"local\" or \"gossip\")", mode)
+	}
+}
+
+// UnsafeDumpMempool writes the unconfirmed transactions to a new file at
+// path, to load them with /unsafe_load_mempool after a restart of the node.
+//
+// ```shell
+// curl 'localhost:26657/unsafe_dump_mempool?path="/tmp/mempool"'
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "path": "/tmp/mempool",
+//     "n_txs": "120"
+//   }
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                    |
+// |-----------+--------+---------+----------+--------------------------------|
+// | path      | string | ""      | true     | File to write the txs to       |
+func (env *Environment) UnsafeDumpMempool(path string) (*ctypes.ResultUnsafeMempoolTxs, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	n, err := env.Mempool.DumpTxs(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultUnsafeMempoolTxs{Path: path, N: n}, nil
+}
+
+// UnsafeLoadMempool checks the transactions of the file at path, written by
+// /unsafe_dump_mempool, adding the valid ones to the mempool. The
+// transactions already in the mempool, or recently committed, are skipped.
+//
+// ```shell
+// curl 'localhost:26657/unsafe_load_mempool?path="/tmp/mempool"'
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "path": "/tmp/mempool",
+//     "n_txs": "118"
+//   }
+// }
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                    |
+// |-----------+--------+---------+----------+--------------------------------|
+// | path      | string | ""      | true     | File to read the txs from      |
+func (env *Environment) UnsafeLoadMempool(path string) (*ctypes.ResultUnsafeMempoolTxs, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	n, err := env.Mempool.LoadTxs(f)
+	if err != nil {
+		return nil, fmt.Errorf("error loading the txs (%d checked): %v", n, err)
+	}
+	return &ctypes.ResultUnsafeMempoolTxs{Path: path, N: n}, nil
 }
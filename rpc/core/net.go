@@ -11,6 +11,9 @@ import (
 
 // Get network info.
 //
+// The versions run by the peers are counted in `peer_versions`, the most
+// common first, to notice when the node is behind an upgrade.
+//
 // ```shell
 // curl 'localhost:26657/net_info'
 // ```
@@ -22,7 +25,7 @@ import (
 //   // handle error
 // }
 // defer client.Stop()
-// info, err := client.NetInfo()
+// info, err := client.NetInfo(context.Background())
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -151,12 +154,24 @@ import (
 //   			"remote_ip": "192.167.10.3"
 //   		},
 //      ...
+//   	],
+//   	"peer_versions": [
+//   		{
+//   			"version": "0.30.0",
+//   			"protocol_version": {
+//   				"p2p": "7",
+//   				"block": "8",
+//   				"app": "1"
+//   			},
+//   			"n_peers": "3"
+//   		}
+//   	]
 //   }
 // ```
-func NetInfo() (*ctypes.ResultNetInfo, error) {
-	out, in, _ := p2pPeers.NumPeers()
+func (env *Environment) NetInfo() (*ctypes.ResultNetInfo, error) {
+	out, in, _ := env.P2PPeers.NumPeers()
 	peers := make([]ctypes.Peer, 0, out+in)
-	for _, peer := range p2pPeers.Peers().List() {
+	for _, peer := range env.P2PPeers.Peers().List() {
 		nodeInfo, ok := peer.NodeInfo().(p2p.DefaultNodeInfo)
 		if !ok {
 			return nil, fmt.Errorf("peer.NodeInfo() is not DefaultNodeInfo")
@@ -166,46 +181,48 @@ func NetInfo() (*ctypes.ResultNetInfo, error) {
 			IsOutbound:       peer.IsOutbound(),
 			ConnectionStatus: peer.Status(),
 			RemoteIP:         peer.RemoteIP().String(),
+			ClockOffset:      peer.ClockOffset(),
 		})
 	}
 	// TODO: Should we include PersistentPeers and Seeds in here?
 	// PRO: useful info
 	// CON: privacy
 	return &ctypes.ResultNetInfo{
-		Listening: p2pTransport.IsListening(),
-		Listeners: p2pTransport.Listeners(),
-		NPeers:    len(peers),
-		Peers:     peers,
+		Listening:    env.P2PTransport.IsListening(),
+		Listeners:    env.P2PTransport.Listeners(),
+		NPeers:       len(peers),
+		Peers:        peers,
+		PeerVersions: p2p.PeerVersions(env.P2PPeers.Peers()),
 	}, nil
 }
 
-func UnsafeDialSeeds(seeds []string) (*ctypes.ResultDialSeeds, error) {
+func (env *Environment) UnsafeDialSeeds(seeds []string) (*ctypes.ResultDialSeeds, error) {
 	if len(seeds) == 0 {
 		return &ctypes.ResultDialSeeds{}, errors.New("No seeds provided")
 	}
 	// starts go routines to dial each peer after random delays
-	logger.Info("DialSeeds", "addrBook", addrBook, "seeds", seeds)
-	err := p2pPeers.DialPeersAsync(addrBook, seeds, false)
+	env.Logger.Info("DialSeeds", "addrBook", env.AddrBook, "seeds", seeds)
+	err := env.P2PPeers.DialPeersAsync(env.AddrBook, seeds, false)
 	if err != nil {
 		return &ctypes.ResultDialSeeds{}, err
 	}
 	return &ctypes.ResultDialSeeds{Log: "Dialing seeds in progress. See /net_info for details"}, nil
 }
 
-func UnsafeDialPeers(peers []string, persistent bool) (*ctypes.ResultDialPeers, error) {
+func (env *Environment) UnsafeDialPeers(peers []string, persistent bool) (*ctypes.ResultDialPeers, error) {
 	if len(peers) == 0 {
 		return &ctypes.ResultDialPeers{}, errors.New("No peers provided")
 	}
 	// starts go routines to dial each peer after random delays
-	logger.Info("DialPeers", "addrBook", addrBook, "peers", peers, "persistent", persistent)
-	err := p2pPeers.DialPeersAsync(addrBook, peers, persistent)
+	env.Logger.Info("DialPeers", "addrBook", env.AddrBook, "peers", peers, "persistent", persistent)
+	err := env.P2PPeers.DialPeersAsync(env.AddrBook, peers, persistent)
 	if err != nil {
 		return &ctypes.ResultDialPeers{}, err
 	}
 	return &ctypes.ResultDialPeers{Log: "Dialing peers in progress. See /net_info for details"}, nil
 }
 
-// Get genesis file.
+// Get genesis file, and the SHA-256 hash of the file.
 //
 // ```shell
 // curl 'localhost:26657/genesis'
@@ -218,7 +235,7 @@ func UnsafeDialPeers(peers []string, persistent bool) (*ctypes.ResultDialPeers,
 //   // handle error
 // }
 // defer client.Stop()
-// genesis, err := client.Genesis()
+// genesis, err := client.Genesis(context.Background())
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -241,12 +258,13 @@ func UnsafeDialPeers(peers []string, persistent bool) (*ctypes.ResultDialPeers,
 // 			],
 // 			"chain_id": "test-chain-6UTNIN",
 // 			"genesis_time": "2017-05-29T15:05:41.671Z"
-// 		}
+// 		},
+// 		"hash": "0A3F3C2DB9B6D2A0E5B4D4C19C7B1AA1D0A7A0A52C95E8B0B2F2B7C3FBD9D7E1"
 // 	},
 // 	"id": "",
 // 	"jsonrpc": "2.0"
 // }
 // ```
-func Genesis() (*ctypes.ResultGenesis, error) {
-	return &ctypes.ResultGenesis{Genesis: genDoc}, nil
+func (env *Environment) Genesis() (*ctypes.ResultGenesis, error) {
+	return &ctypes.ResultGenesis{Genesis: env.GenDoc, Hash: env.GenesisHash}, nil
 }
@@ -0,0 +1,107 @@
+package core
+
+import (
+	"fmt"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// UnsafePauseConsensus makes the node stop participating in consensus before
+// entering the height, until it's stepped or resumed. Once paused, the node
+// neither proposes nor votes, and keeps the messages for the height (up to a
+// bound) to handle them once resumed. It's
+// meant to coordinate a recovery, with all the validators stopping at the
+// same height. The pause doesn't survive a restart.
+//
+// ```shell
+// curl 'localhost:26657/unsafe_pause_consensus?height=100'
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "pause_height": "100",
+//     "paused": false
+//   }
+// }
+// ```
+//
+// `paused` becomes true once the node has committed the previous height.
+//
+// ### Query Parameters
+//
+// | Parameter | Type  | Default | Required | Description                                      |
+// |-----------+-------+---------+----------+--------------------------------------------------|
+// | height    | int64 | 0       | false    | Height to pause at, the next one to enter if 0   |
+func (env *Environment) UnsafePauseConsensus(heightPtr *int64) (*ctypes.ResultUnsafeConsensusPause, error) {
+	var height int64
+	if heightPtr != nil {
+		height = *heightPtr
+	}
+	if height < 0 {
+		return nil, fmt.Errorf("height must be greater than or equal to 0")
+	}
+	if _, err := env.Consensus.PauseAtHeight(height); err != nil {
+		return nil, err
+	}
+	return env.consensusPause(), nil
+}
+
+// UnsafeStepConsensus lets the paused node commit the height it's paused at,
+// then pauses it again at the next height.
+//
+// ```shell
+// curl 'localhost:26657/unsafe_step_consensus'
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "pause_height": "101",
+//     "paused": false
+//   }
+// }
+// ```
+func (env *Environment) UnsafeStepConsensus() (*ctypes.ResultUnsafeConsensusPause, error) {
+	if _, err := env.Consensus.StepHeight(); err != nil {
+		return nil, err
+	}
+	return env.consensusPause(), nil
+}
+
+// UnsafeResumeConsensus makes the node participate in consensus again,
+// cancelling any pause.
+//
+// ```shell
+// curl 'localhost:26657/unsafe_resume_consensus'
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+// {
+//   "jsonrpc": "2.0",
+//   "id": "",
+//   "result": {
+//     "pause_height": "0",
+//     "paused": false
+//   }
+// }
+// ```
+func (env *Environment) UnsafeResumeConsensus() (*ctypes.ResultUnsafeConsensusPause, error) {
+	env.Consensus.Resume()
+	return env.consensusPause(), nil
+}
+
+func (env *Environment) consensusPause() *ctypes.ResultUnsafeConsensusPause {
+	height, paused := env.Consensus.PausedAt()
+	return &ctypes.ResultUnsafeConsensusPause{PauseHeight: height, Paused: paused}
+}
@@ -1,8 +1,14 @@
 package core
 
 import (
+	"sync"
+	"time"
+
+	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/consensus"
 	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/evidence"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	dbm "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
 	mempl "github.com/tendermint/tendermint/mempool"
@@ -11,6 +17,7 @@ import (
 	rpcserver "github.com/tendermint/tendermint/rpc/lib/server"
 	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/state/txindex"
+	"github.com/tendermint/tendermint/state/uptime"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -31,6 +38,10 @@ type Consensus interface {
 	GetLastHeight() int64
 	GetRoundStateJSON() ([]byte, error)
 	GetRoundStateSimpleJSON() ([]byte, error)
+	PauseAtHeight(height int64) (int64, error)
+	StepHeight() (int64, error)
+	Resume()
+	PausedAt() (height int64, paused bool)
 }
 
 type transport interface {
@@ -39,6 +50,16 @@ type transport interface {
 	NodeInfo() p2p.NodeInfo
 }
 
+type blockTimeIndexer interface {
+	HeightsByTime(from, to time.Time) (minHeight, maxHeight int64)
+	HeightAtTime(t time.Time) int64
+}
+
+type evidenceIndexer interface {
+	CommittedEvidence(height int64) []evidence.EvidenceInfo
+	SearchCommittedEvidence(address []byte) []evidence.EvidenceInfo
+}
+
 type peers interface {
 	DialPeersAsync(p2p.AddrBook, []string, bool) error
 	NumPeers() (outbound, inbound, dialig int)
@@ -46,91 +67,57 @@ type peers interface {
 }
 
 //----------------------------------------------
-// These package level globals come with setters
-// that are expected to be called only once, on startup
 
-var (
+// Environment is the environment of the RPC of a node: the components of the
+// node which the routes serve. The nodes of a process each have their own.
+// Its fields are expected to be set only once, on startup, before the routes
+// are served.
+type Environment struct {
 	// external, thread safe interfaces
-	proxyAppQuery proxy.AppConnQuery
+	ProxyAppQuery proxy.AppConnQuery
 
 	// interfaces defined in types and above
-	stateDB        dbm.DB
-	blockStore     sm.BlockStore
-	evidencePool   sm.EvidencePool
-	consensusState Consensus
-	p2pPeers       peers
-	p2pTransport   transport
+	StateDB        dbm.DB
+	BlockStore     sm.BlockStore
+	EvidencePool   sm.EvidencePool
+	EvidenceIndex  evidenceIndexer
+	BlockTimeIndex blockTimeIndexer
+	Consensus      Consensus
+	P2PPeers       peers
+	P2PTransport   transport
 
 	// objects
-	pubKey           crypto.PubKey
-	genDoc           *types.GenesisDoc // cache the genesis structure
-	addrBook         p2p.AddrBook
-	txIndexer        txindex.TxIndexer
-	consensusReactor *consensus.ConsensusReactor
-	eventBus         *types.EventBus // thread safe
-	mempool          *mempl.Mempool
-
-	logger log.Logger
-)
-
-func SetStateDB(db dbm.DB) {
-	stateDB = db
-}
-
-func SetBlockStore(bs sm.BlockStore) {
-	blockStore = bs
-}
-
-func SetMempool(mem *mempl.Mempool) {
-	mempool = mem
-}
-
-func SetEvidencePool(evpool sm.EvidencePool) {
-	evidencePool = evpool
-}
+	PubKey           crypto.PubKey
+	GenDoc           *types.GenesisDoc // cache the genesis structure
+	GenesisHash      cmn.HexBytes      // hash of the genesis file, if any
+	AddrBook         p2p.AddrBook
+	TxIndexer        txindex.TxIndexer
+	UptimeTracker    *uptime.Tracker // nil if disabled
+	ConsensusReactor *consensus.ConsensusReactor
+	EventBus         *types.EventBus // thread safe
+	TagEncoding      types.TagEncoding
+	Monikers         *types.ValidatorMonikers // nil if unknown
+	Mempool          *mempl.Mempool
 
-func SetConsensusState(cs Consensus) {
-	consensusState = cs
-}
-
-func SetP2PPeers(p peers) {
-	p2pPeers = p
-}
-
-func SetP2PTransport(t transport) {
-	p2pTransport = t
-}
-
-func SetPubKey(pk crypto.PubKey) {
-	pubKey = pk
-}
+	Config cfg.RPCConfig
 
-func SetGenesisDoc(doc *types.GenesisDoc) {
-	genDoc = doc
-}
+	// DBs copied by UnsafeBackup, by name
+	BackupDBs       map[string]dbm.DB
+	BackupDBBackend dbm.DBBackendType
 
-func SetAddrBook(book p2p.AddrBook) {
-	addrBook = book
-}
+	Logger     log.Logger
+	RecentLogs *log.RecentModuleLogs // nil if disabled
 
-func SetProxyAppQuery(appConn proxy.AppConnQuery) {
-	proxyAppQuery = appConn
-}
+	backupMtx sync.Mutex
 
-func SetTxIndexer(indexer txindex.TxIndexer) {
-	txIndexer = indexer
-}
+	broadcastTxCommitMtx   sync.Mutex
+	broadcastTxCommitCalls map[broadcastTxCommitKey]*broadcastTxCommitCall
 
-func SetConsensusReactor(conR *consensus.ConsensusReactor) {
-	consensusReactor = conR
-}
-
-func SetLogger(l log.Logger) {
-	logger = l
-}
+	ackSubscriptionsMtx sync.Mutex
+	ackSubscriptions    map[string]*ackSubscription // by ack ID
 
-func SetEventBus(b *types.EventBus) {
-	eventBus = b
+	pollBuffersMtx sync.Mutex
+	pollBuffers    map[string]*pollBuffer // by query
 }
 
 func validatePage(page, perPage, totalCount int) int {
@@ -5,49 +5,53 @@ import (
 )
 
 // TODO: better system than "unsafe" prefix
-// NOTE: Amino is registered in rpc/core/types/wire.go.
-var Routes = map[string]*rpc.RPCFunc{
-	// subscribe/unsubscribe are reserved for websocket events.
-	"subscribe":       rpc.NewWSRPCFunc(Subscribe, "query"),
-	"unsubscribe":     rpc.NewWSRPCFunc(Unsubscribe, "query"),
-	"unsubscribe_all": rpc.NewWSRPCFunc(UnsubscribeAll, ""),
-
-	// info API
-	"health":               rpc.NewRPCFunc(Health, ""),
-	"status":               rpc.NewRPCFunc(Status, ""),
-	"net_info":             rpc.NewRPCFunc(NetInfo, ""),
-	"blockchain":           rpc.NewRPCFunc(BlockchainInfo, "minHeight,maxHeight"),
-	"genesis":              rpc.NewRPCFunc(Genesis, ""),
-	"block":                rpc.NewRPCFunc(Block, "height"),
-	"block_results":        rpc.NewRPCFunc(BlockResults, "height"),
-	"commit":               rpc.NewRPCFunc(Commit, "height"),
-	"tx":                   rpc.NewRPCFunc(Tx, "hash,prove"),
-	"tx_search":            rpc.NewRPCFunc(TxSearch, "query,prove,page,per_page"),
-	"validators":           rpc.NewRPCFunc(Validators, "height"),
-	"dump_consensus_state": rpc.NewRPCFunc(DumpConsensusState, ""),
-	"consensus_state":      rpc.NewRPCFunc(ConsensusState, ""),
-	"consensus_params":     rpc.NewRPCFunc(ConsensusParams, "height"),
-	"unconfirmed_txs":      rpc.NewRPCFunc(UnconfirmedTxs, "limit"),
-	"num_unconfirmed_txs":  rpc.NewRPCFunc(NumUnconfirmedTxs, ""),
 
-	// broadcast API
-	"broadcast_tx_commit": rpc.NewRPCFunc(BroadcastTxCommit, "tx"),
-	"broadcast_tx_sync":   rpc.NewRPCFunc(BroadcastTxSync, "tx"),
-	"broadcast_tx_async":  rpc.NewRPCFunc(BroadcastTxAsync, "tx"),
+// Routes returns the routes of the RPC of the environment, without the unsafe
+// ones (see AddUnsafeRoutes).
+// NOTE: Amino is registered in rpc/core/types/wire.go.
+func (env *Environment) Routes() map[string]*rpc.RPCFunc {
+	return map[string]*rpc.RPCFunc{
+		// subscribe/unsubscribe are reserved for websocket events.
+		"subscribe":       rpc.NewWSRPCFunc(env.Subscribe, "query,header_only,ack_id,schema"),
+		"unsubscribe":     rpc.NewWSRPCFunc(env.Unsubscribe, "query,ack_id"),
+		"ack":             rpc.NewWSRPCFunc(env.Ack, "ack_id,seq"),
+		"unsubscribe_all": rpc.NewWSRPCFunc(env.UnsubscribeAll, ""),
+		"events":          rpc.NewRPCFunc(env.Events, "query,after,schema"),
 
-	// abci API
-	"abci_query": rpc.NewRPCFunc(ABCIQuery, "path,data,height,prove"),
-	"abci_info":  rpc.NewRPCFunc(ABCIInfo, ""),
-}
+		// info API
+		"health":                   rpc.NewRPCFunc(env.Health, ""),
+		"status":                   rpc.NewRPCFunc(env.Status, ""),
+		"net_info":                 rpc.NewRPCFunc(env.NetInfo, ""),
+		"blockchain":               rpc.NewRPCFunc(env.BlockchainInfo, "minHeight,maxHeight"),
+		"blockchain_by_time":       rpc.NewRPCFunc(env.BlockchainInfoByTime, "from,to"),
+		"height_at_time":           rpc.NewRPCFunc(env.HeightAtTime, "time"),
+		"genesis":                  rpc.NewRPCFunc(env.Genesis, ""),
+		"block":                    rpc.NewRPCFunc(env.Block, "height"),
+		"block_raw":                rpc.NewRPCFunc(env.BlockRaw, "height"),
+		"block_results":            rpc.NewRPCFunc(env.BlockResults, "height"),
+		"commit":                   rpc.NewRPCFunc(env.Commit, "height"),
+		"tx":                       rpc.NewRPCFunc(env.Tx, "hash,prove,prove_result"),
+		"tx_search":                rpc.NewRPCFunc(env.TxSearch, "query,prove,page,per_page"),
+		"validators":               rpc.NewRPCFunc(env.Validators, "height,page,per_page"),
+		"evidence":                 rpc.NewRPCFunc(env.Evidence, "height"),
+		"evidence_search":          rpc.NewRPCFunc(env.EvidenceSearch, "address,page,per_page"),
+		"dump_consensus_state":     rpc.NewRPCFunc(env.DumpConsensusState, ""),
+		"consensus_state":          rpc.NewRPCFunc(env.ConsensusState, ""),
+		"validator_uptime":         rpc.NewRPCFunc(env.ValidatorUptime, ""),
+		"chain_health":             rpc.NewRPCFunc(env.ChainHealth, "blocks"),
+		"consensus_params":         rpc.NewRPCFunc(env.ConsensusParams, "height"),
+		"consensus_params_history": rpc.NewRPCFunc(env.ConsensusParamsHistory, "height"),
+		"validator_set_changes":    rpc.NewRPCFunc(env.ValidatorSetChanges, "from_height,height"),
+		"unconfirmed_txs":          rpc.NewRPCFunc(env.UnconfirmedTxs, "limit,sender,min_gas,min_age,fields"),
+		"num_unconfirmed_txs":      rpc.NewRPCFunc(env.NumUnconfirmedTxs, ""),
 
-func AddUnsafeRoutes() {
-	// control API
-	Routes["dial_seeds"] = rpc.NewRPCFunc(UnsafeDialSeeds, "seeds")
-	Routes["dial_peers"] = rpc.NewRPCFunc(UnsafeDialPeers, "peers,persistent")
-	Routes["unsafe_flush_mempool"] = rpc.NewRPCFunc(UnsafeFlushMempool, "")
+		// broadcast API
+		"broadcast_tx_commit": rpc.NewRPCFunc(env.BroadcastTxCommit, "tx,mode"),
+		"broadcast_tx_sync":   rpc.NewRPCFunc(env.BroadcastTxSync, "tx,mode"),
+		"broadcast_tx_async":  rpc.NewRPCFunc(env.BroadcastTxAsync, "tx,mode"),
 
-	// profiler API
-	Routes["unsafe_start_cpu_profiler"] = rpc.NewRPCFunc(UnsafeStartCPUProfiler, "filename")
-	Routes["unsafe_stop_cpu_profiler"] = rpc.NewRPCFunc(UnsafeStopCPUProfiler, "")
-	Routes["unsafe_write_heap_profile"] = rpc.NewRPCFunc(UnsafeWriteHeapProfile, "filename")
+		// abci API
+		"abci_query": rpc.NewRPCFunc(env.ABCIQuery, "path,data,height,prove"),
+		"abci_info":  rpc.NewRPCFunc(env.ABCIInfo, ""),
+	}
 }
@@ -0,0 +1,15 @@
+// +build nounsafe
+
+package core
+
+import (
+	rpc "github.com/tendermint/tendermint/rpc/lib/server"
+)
+
+// UnsafeRoutesAvailable is false in the binaries built with the nounsafe tag,
+// which don't have the unsafe routes.
+const UnsafeRoutesAvailable = false
+
+// AddUnsafeRoutes does nothing, as the binary is built with the nounsafe tag,
+// so that the unsafe routes can't be served whatever the config.
+func (env *Environment) AddUnsafeRoutes(routes map[string]*rpc.RPCFunc) {}
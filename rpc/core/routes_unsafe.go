@@ -0,0 +1,32 @@
+// +build !nounsafe
+
+package core
+
+import (
+	rpc "github.com/tendermint/tendermint/rpc/lib/server"
+)
+
+// UnsafeRoutesAvailable is false in the binaries built with the nounsafe tag,
+// which don't have the unsafe routes.
+const UnsafeRoutesAvailable = true
+
+// AddUnsafeRoutes adds the unsafe routes of the environment (/dial_seeds,
+// /dial_peers and the /unsafe_* ones) to the routes.
+func (env *Environment) AddUnsafeRoutes(routes map[string]*rpc.RPCFunc) {
+	// control API
+	routes["dial_seeds"] = rpc.NewRPCFunc(env.UnsafeDialSeeds, "seeds")
+	routes["dial_peers"] = rpc.NewRPCFunc(env.UnsafeDialPeers, "peers,persistent")
+	routes["unsafe_flush_mempool"] = rpc.NewRPCFunc(env.UnsafeFlushMempool, "")
+	routes["unsafe_dump_mempool"] = rpc.NewRPCFunc(env.UnsafeDumpMempool, "path")
+	routes["unsafe_load_mempool"] = rpc.NewRPCFunc(env.UnsafeLoadMempool, "path")
+	routes["unsafe_backup"] = rpc.NewRPCFunc(env.UnsafeBackup, "path")
+	routes["unsafe_pause_consensus"] = rpc.NewRPCFunc(env.UnsafePauseConsensus, "height")
+	routes["unsafe_step_consensus"] = rpc.NewRPCFunc(env.UnsafeStepConsensus, "")
+	routes["unsafe_resume_consensus"] = rpc.NewRPCFunc(env.UnsafeResumeConsensus, "")
+	routes["unsafe_logs"] = rpc.NewRPCFunc(env.UnsafeLogs, "module,lines")
+
+	// profiler API
+	routes["unsafe_start_cpu_profiler"] = rpc.NewRPCFunc(env.UnsafeStartCPUProfiler, "filename")
+	routes["unsafe_stop_cpu_profiler"] = rpc.NewRPCFunc(env.UnsafeStopCPUProfiler, "")
+	routes["unsafe_write_heap_profile"] = rpc.NewRPCFunc(env.UnsafeWriteHeapProfile, "filename")
+}
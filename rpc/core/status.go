@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"math"
 	"time"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -9,10 +10,13 @@ import (
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
 )
 
 // Get Tendermint status including node info, pubkey, latest block
-// hash, app hash, block height and time.
+// hash, app hash, block height and time, the seconds until the genesis
+// time before the first block, and the current p2p, block and app protocol
+// versions.
 //
 // ```shell
 // curl 'localhost:26657/status'
@@ -25,7 +29,7 @@ import (
 //   // handle error
 // }
 // defer client.Stop()
-// result, err := client.Status()
+// result, err := client.Status(context.Background())
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -57,7 +61,10 @@ import (
 //   		"latest_app_hash": "0000000000000000",
 //   		"latest_block_height": "18",
 //   		"latest_block_time": "2018-09-17T11:42:19.149920551Z",
-//   		"catching_up": false
+//   		"catching_up": false,
+//   		"earliest_block_height": "1",
+//   		"genesis_time": "2018-09-17T11:40:02.354221253Z",
+//   		"seconds_until_genesis": "0"
 //   	},
 //   	"validator_info": {
 //   		"address": "D9F56456D7C5793815D0E9AF07C3A355D0FC64FD",
@@ -66,16 +73,21 @@ import (
 //   			"value": "wVxKNtEsJmR4vvh651LrVoRguPs+6yJJ9Bz174gw9DM="
 //   		},
 //   		"voting_power": "10"
+//   	},
+//   	"protocol_version": {
+//   		"p2p": "4",
+//   		"block": "7",
+//   		"app": "1"
 //   	}
 //   }
 // }
 // ```
-func Status() (*ctypes.ResultStatus, error) {
+func (env *Environment) Status() (*ctypes.ResultStatus, error) {
 	var latestHeight int64 = -1
-	if consensusReactor.FastSync() {
-		latestHeight = blockStore.Height()
+	if env.ConsensusReactor.FastSync() {
+		latestHeight = env.BlockStore.Height()
 	} else {
-		latestHeight = consensusState.GetLastHeight()
+		latestHeight = env.Consensus.GetLastHeight()
 	}
 	var (
 		latestBlockMeta     *types.BlockMeta
@@ -84,7 +96,7 @@ func Status() (*ctypes.ResultStatus, error) {
 		latestBlockTimeNano int64
 	)
 	if latestHeight != 0 {
-		latestBlockMeta = blockStore.LoadBlockMeta(latestHeight)
+		latestBlockMeta = env.BlockStore.LoadBlockMeta(latestHeight)
 		latestBlockHash = latestBlockMeta.BlockID.Hash
 		latestAppHash = latestBlockMeta.Header.AppHash
 		latestBlockTimeNano = latestBlockMeta.Header.Time.UnixNano()
@@ -92,35 +104,58 @@ func Status() (*ctypes.ResultStatus, error) {
 
 	latestBlockTime := time.Unix(0, latestBlockTimeNano)
 
+	var (
+		genesisTime         time.Time
+		secondsUntilGenesis int64
+	)
+	if env.GenDoc != nil {
+		genesisTime = env.GenDoc.GenesisTime
+		if untilGenesis := time.Until(genesisTime); latestHeight == 0 && untilGenesis > 0 {
+			secondsUntilGenesis = int64(math.Ceil(untilGenesis.Seconds()))
+		}
+	}
+
 	var votingPower int64
-	if val := validatorAtHeight(latestHeight); val != nil {
+	if val := env.validatorAtHeight(latestHeight); val != nil {
 		votingPower = val.VotingPower
 	}
 
+	stateVersion := env.Consensus.GetState().Version.Consensus
+
 	result := &ctypes.ResultStatus{
-		NodeInfo: p2pTransport.NodeInfo().(p2p.DefaultNodeInfo),
+		NodeInfo: env.P2PTransport.NodeInfo().(p2p.DefaultNodeInfo),
 		SyncInfo: ctypes.SyncInfo{
 			LatestBlockHash:   latestBlockHash,
 			LatestAppHash:     latestAppHash,
 			LatestBlockHeight: latestHeight,
 			LatestBlockTime:   latestBlockTime,
-			CatchingUp:        consensusReactor.FastSync(),
+			CatchingUp:        env.ConsensusReactor.FastSync(),
+
+			EarliestBlockHeight: env.BlockStore.Base(),
+
+			GenesisTime:         genesisTime,
+			SecondsUntilGenesis: secondsUntilGenesis,
 		},
 		ValidatorInfo: ctypes.ValidatorInfo{
-			Address:     pubKey.Address(),
-			PubKey:      pubKey,
+			Address:     env.PubKey.Address(),
+			PubKey:      env.PubKey,
 			VotingPower: votingPower,
 		},
+		ProtocolVersion: p2p.NewProtocolVersion(
+			version.P2PProtocol,
+			stateVersion.Block,
+			stateVersion.App,
+		),
 	}
 
 	return result, nil
 }
 
-func validatorAtHeight(h int64) *types.Validator {
-	privValAddress := pubKey.Address()
+func (env *Environment) validatorAtHeight(h int64) *types.Validator {
+	privValAddress := env.PubKey.Address()
 
 	// If we're still at height h, search in the current validator set.
-	lastBlockHeight, vals := consensusState.GetValidators()
+	lastBlockHeight, vals := env.Consensus.GetValidators()
 	if lastBlockHeight == h {
 		for _, val := range vals {
 			if bytes.Equal(val.Address, privValAddress) {
@@ -131,7 +166,7 @@ func validatorAtHeight(h int64) *types.Validator {
 
 	// If we've moved to the next height, retrieve the validator set from DB.
 	if lastBlockHeight > h {
-		vals, err := sm.LoadValidators(stateDB, h)
+		vals, err := sm.LoadValidators(env.StateDB, h)
 		if err != nil {
 			return nil // should not happen
 		}
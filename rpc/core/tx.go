@@ -1,12 +1,14 @@
 package core
 
 import (
+	"context"
 	"fmt"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
 
 	tmquery "github.com/tendermint/tendermint/libs/pubsub/query"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/state/txindex/null"
 	"github.com/tendermint/tendermint/types"
 )
@@ -26,7 +28,7 @@ import (
 //   // handle error
 // }
 // defer client.Stop()
-// tx, err := client.Tx([]byte("2B8EC32BA2579B3B8606E42C06DE2F7AFA2556EF"), true)
+// tx, err := client.Tx(context.Background(), []byte("2B8EC32BA2579B3B8606E42C06DE2F7AFA2556EF"), true, false)
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -63,27 +65,33 @@ import (
 //
 // ### Query Parameters
 //
-// | Parameter | Type   | Default | Required | Description                                               |
-// |-----------+--------+---------+----------+-----------------------------------------------------------|
-// | hash      | []byte | nil     | true     | The transaction hash                                      |
-// | prove     | bool   | false   | false    | Include a proof of the transaction inclusion in the block |
+// | Parameter    | Type   | Default | Required | Description                                               |
+// |--------------+--------+---------+----------+-----------------------------------------------------------|
+// | hash         | []byte | nil     | true     | The transaction hash                                      |
+// | prove        | bool   | false   | false    | Include a proof of the transaction inclusion in the block |
+// | prove_result | bool   | false   | false    | Include a proof of the transaction result                 |
+//
+// The result proof covers the code and data of `tx_result`, and its root hash
+// is the `last_results_hash` of the header at `height+1`, so it can only be
+// verified once the next block is committed.
 //
 // ### Returns
 //
 // - `proof`: the `types.TxProof` object
+// - `result_proof`: the `types.ResultProof` object
 // - `tx`: `[]byte` - the transaction
 // - `tx_result`: the `abci.Result` object
 // - `index`: `int` - index of the transaction
 // - `height`: `int` - height of the block where this transaction was in
 // - `hash`: `[]byte` - hash of the transaction
-func Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
+func (env *Environment) Tx(hash []byte, prove, proveResult bool) (*ctypes.ResultTx, error) {
 
 	// if index is disabled, return error
-	if _, ok := txIndexer.(*null.TxIndex); ok {
+	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
 		return nil, fmt.Errorf("Transaction indexing is disabled")
 	}
 
-	r, err := txIndexer.Get(hash)
+	r, err := env.TxIndexer.Get(hash)
 	if err != nil {
 		return nil, err
 	}
@@ -97,17 +105,27 @@ func Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 
 	var proof types.TxProof
 	if prove {
-		block := blockStore.LoadBlock(height)
+		block := env.BlockStore.LoadBlock(height)
 		proof = block.Data.Txs.Proof(int(index)) // XXX: overflow on 32-bit machines
 	}
 
+	var resultProof types.ResultProof
+	if proveResult {
+		abciResponses, err := sm.LoadABCIResponses(env.StateDB, height)
+		if err != nil {
+			return nil, err
+		}
+		resultProof = types.NewResults(abciResponses.DeliverTx).Proof(int(index)) // XXX: overflow on 32-bit machines
+	}
+
 	return &ctypes.ResultTx{
-		Hash:     hash,
-		Height:   height,
-		Index:    uint32(index),
-		TxResult: r.Result,
-		Tx:       r.Tx,
-		Proof:    proof,
+		Hash:        hash,
+		Height:      height,
+		Index:       uint32(index),
+		TxResult:    r.Result,
+		Tx:          r.Tx,
+		Proof:       proof,
+		ResultProof: resultProof,
 	}, nil
 }
 
@@ -126,7 +144,7 @@ func Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 // }
 // defer client.Stop()
 // q, err := tmquery.New("account.owner='Ivan'")
-// tx, err := client.TxSearch(q, true)
+// tx, err := client.TxSearch(context.Background(), q, true)
 // ```
 //
 // > The above command returns JSON structured like this:
@@ -182,9 +200,9 @@ func Tx(hash []byte, prove bool) (*ctypes.ResultTx, error) {
 // - `index`: `int` - index of the transaction
 // - `height`: `int` - height of the block where this transaction was in
 // - `hash`: `[]byte` - hash of the transaction
-func TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
+func (env *Environment) TxSearch(ctx context.Context, query string, prove bool, page, perPage int) (*ctypes.ResultTxSearch, error) {
 	// if index is disabled, return error
-	if _, ok := txIndexer.(*null.TxIndex); ok {
+	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
 		return nil, fmt.Errorf("Transaction indexing is disabled")
 	}
 
@@ -193,7 +211,7 @@ func TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSear
 		return nil, err
 	}
 
-	results, err := txIndexer.Search(q)
+	results, err := env.TxIndexer.Search(ctx, q)
 	if err != nil {
 		return nil, err
 	}
@@ -212,7 +230,10 @@ func TxSearch(query string, prove bool, page, perPage int) (*ctypes.ResultTxSear
 		index := r.Index
 
 		if prove {
-			block := blockStore.LoadBlock(height)
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			block := env.BlockStore.LoadBlock(height)
 			proof = block.Data.Txs.Proof(int(index)) // XXX: overflow on 32-bit machines
 		}
 
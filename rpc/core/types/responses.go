@@ -10,6 +10,7 @@ import (
 
 	"github.com/tendermint/tendermint/p2p"
 	"github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/state/uptime"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -19,9 +20,18 @@ type ResultBlockchainInfo struct {
 	BlockMetas []*types.BlockMeta `json:"block_metas"`
 }
 
+// First block at or after a time, and the time of the block before it, if
+// any, to check it
+type ResultHeightAtTime struct {
+	Height       int64            `json:"height"`
+	BlockMeta    *types.BlockMeta `json:"block_meta"`
+	PreviousTime *time.Time       `json:"previous_time"`
+}
+
 // Genesis file
 type ResultGenesis struct {
 	Genesis *types.GenesisDoc `json:"genesis"`
+	Hash    cmn.HexBytes      `json:"hash"` // of the genesis file
 }
 
 // Single block (with meta)
@@ -30,6 +40,13 @@ type ResultBlock struct {
 	Block     *types.Block     `json:"block"`
 }
 
+// Single block, as encoded in its parts
+type ResultBlockRaw struct {
+	Height  int64         `json:"height"`
+	BlockID types.BlockID `json:"block_id"`
+	Block   []byte        `json:"block"`
+}
+
 // Commit and Header
 type ResultCommit struct {
 	types.SignedHeader `json:"signed_header"`
@@ -63,6 +80,15 @@ type SyncInfo struct {
 	LatestBlockHeight int64        `json:"latest_block_height"`
 	LatestBlockTime   time.Time    `json:"latest_block_time"`
 	CatchingUp        bool         `json:"catching_up"`
+
+	// The lowest height of the blocks the node has, the ones below it being
+	// pruned.
+	EarliestBlockHeight int64 `json:"earliest_block_height"`
+
+	// Before the first block, the seconds until the genesis time, at which
+	// it starts (0 once started).
+	GenesisTime         time.Time `json:"genesis_time"`
+	SecondsUntilGenesis int64     `json:"seconds_until_genesis"`
 }
 
 // Info about the node's validator
@@ -77,6 +103,10 @@ type ResultStatus struct {
 	NodeInfo      p2p.DefaultNodeInfo `json:"node_info"`
 	SyncInfo      SyncInfo            `json:"sync_info"`
 	ValidatorInfo ValidatorInfo       `json:"validator_info"`
+
+	// The current protocol versions, the app version being the one of the
+	// next block, as set by the app.
+	ProtocolVersion p2p.ProtocolVersion `json:"protocol_version"`
 }
 
 // Is TxIndexing enabled
@@ -93,6 +123,9 @@ type ResultNetInfo struct {
 	Listeners []string `json:"listeners"`
 	NPeers    int      `json:"n_peers"`
 	Peers     []Peer   `json:"peers"`
+
+	// versions run by the peers, the most common first
+	PeerVersions []p2p.PeerVersion `json:"peer_versions"`
 }
 
 // Log from dialing seeds
@@ -111,12 +144,18 @@ type Peer struct {
 	IsOutbound       bool                 `json:"is_outbound"`
 	ConnectionStatus p2p.ConnectionStatus `json:"connection_status"`
 	RemoteIP         string               `json:"remote_ip"`
+	ClockOffset      *p2p.ClockOffset     `json:"clock_offset"`
 }
 
 // Validators for a height
 type ResultValidators struct {
 	BlockHeight int64              `json:"block_height"`
 	Validators  []*types.Validator `json:"validators"`
+	// Monikers of the Validators, empty if unknown, nil if none is known.
+	Monikers []string `json:"monikers,omitempty"`
+	// Number of the Validators, of the page if any, and of the whole set.
+	Count int `json:"count"`
+	Total int `json:"total"`
 }
 
 // ConsensusParams for given height
@@ -125,6 +164,18 @@ type ResultConsensusParams struct {
 	ConsensusParams types.ConsensusParams `json:"consensus_params"`
 }
 
+// Changes of the ConsensusParams up to a given height
+type ResultConsensusParamsHistory struct {
+	BlockHeight int64                         `json:"block_height"`
+	Changes     []state.ConsensusParamsChange `json:"changes"`
+}
+
+// Changes of the validator set in a range of heights
+type ResultValidatorSetChanges struct {
+	BlockHeight int64                      `json:"block_height"`
+	Changes     []state.ValidatorSetChange `json:"changes"`
+}
+
 // Info about the consensus state.
 // UNSTABLE
 type ResultDumpConsensusState struct {
@@ -143,11 +194,47 @@ type ResultConsensusState struct {
 	RoundState json.RawMessage `json:"round_state"`
 }
 
+// Signed and missed blocks of the validators over the last blocks
+type ResultValidatorUptime struct {
+	Window     int                      `json:"window"`
+	FromHeight int64                    `json:"from_height"`
+	ToHeight   int64                    `json:"to_height"`
+	Validators []uptime.ValidatorUptime `json:"validators"`
+}
+
+// Health of the chain over the last blocks
+type ResultChainHealth struct {
+	FromHeight int64 `json:"from_height"`
+	ToHeight   int64 `json:"to_height"`
+
+	// Time between the consecutive blocks.
+	BlockInterval BlockIntervalStats `json:"block_interval"`
+	// Rounds the blocks were committed at, 0 for the first one.
+	AvgRound float64 `json:"avg_round" amino:"unsafe"`
+	MaxRound int     `json:"max_round"`
+	// Average ratio of the voting power missing from the commits.
+	MissingPowerRatio float64 `json:"missing_power_ratio" amino:"unsafe"`
+	// Average number of txs of the blocks, and txs in the mempool now.
+	AvgTxs      float64 `json:"avg_txs" amino:"unsafe"`
+	MempoolSize int     `json:"mempool_size"`
+}
+
+// Average, percentiles and max of the intervals between the blocks
+type BlockIntervalStats struct {
+	Avg time.Duration `json:"avg"`
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
 // CheckTx result
 type ResultBroadcastTx struct {
-	Code uint32       `json:"code"`
-	Data cmn.HexBytes `json:"data"`
-	Log  string       `json:"log"`
+	Code      uint32       `json:"code"`
+	Data      cmn.HexBytes `json:"data"`
+	Log       string       `json:"log"`
+	Info      string       `json:"info"`
+	Codespace string       `json:"codespace"`
 
 	Hash cmn.HexBytes `json:"hash"`
 }
@@ -168,6 +255,9 @@ type ResultTx struct {
 	TxResult abci.ResponseDeliverTx `json:"tx_result"`
 	Tx       types.Tx               `json:"tx"`
 	Proof    types.TxProof          `json:"proof,omitempty"`
+	// proves TxResult's code and data against the LastResultsHash of the
+	// header at Height+1
+	ResultProof types.ResultProof `json:"result_proof,omitempty"`
 }
 
 // Result of searching for txs
@@ -176,10 +266,30 @@ type ResultTxSearch struct {
 	TotalCount int         `json:"total_count"`
 }
 
+// Evidence committed in a block
+type ResultEvidence struct {
+	Height   int64            `json:"height"`
+	Evidence []types.Evidence `json:"evidence"`
+}
+
+// Evidence committed against a validator, with the height of the block it was
+// committed in
+type ResultCommittedEvidence struct {
+	Height   int64          `json:"height"`
+	Evidence types.Evidence `json:"evidence"`
+}
+
+// Result of searching for evidence
+type ResultEvidenceSearch struct {
+	Evidence   []*ResultCommittedEvidence `json:"evidence"`
+	TotalCount int                        `json:"total_count"`
+}
+
 // List of mempool txs
 type ResultUnconfirmedTxs struct {
-	N   int        `json:"n_txs"`
-	Txs []types.Tx `json:"txs"`
+	N      int            `json:"n_txs"`
+	Txs    []types.Tx     `json:"txs"`
+	Hashes []cmn.HexBytes `json:"hashes,omitempty"` // of the txs, instead of them, with fields=hashes
 }
 
 // Info abci msg
@@ -192,17 +302,164 @@ type ResultABCIQuery struct {
 	Response abci.ResponseQuery `json:"response"`
 }
 
+// Result of a backup
+type ResultUnsafeBackup struct {
+	Path string   `json:"path"`
+	DBs  []string `json:"dbs"`
+}
+
+// Result of a mempool dump or load
+type ResultUnsafeMempoolTxs struct {
+	Path string `json:"path"`
+	N    int    `json:"n_txs"`
+}
+
+// Recent log lines, by module
+type ResultUnsafeLogs struct {
+	Modules map[string][]string `json:"modules"`
+}
+
+// State of a consensus pause
+type ResultUnsafeConsensusPause struct {
+	PauseHeight int64 `json:"pause_height"`
+	Paused      bool  `json:"paused"`
+}
+
 // empty results
 type (
 	ResultUnsafeFlushMempool struct{}
 	ResultUnsafeProfile      struct{}
-	ResultSubscribe          struct{}
 	ResultUnsubscribe        struct{}
 	ResultHealth             struct{}
 )
 
+// Version of the event schema of a subscription
+type ResultSubscribe struct {
+	Schema int `json:"schema"`
+}
+
+// Versions of the schema of the events sent to the subscribers. A subscriber
+// chooses one, the first one by default, so that the format of the events can
+// evolve without breaking the existing subscribers.
+const (
+	// The event data, with the events of the ABCI responses flattened to
+	// tags, base64-encoded key-value pairs (see EventDataWithTags).
+	EventSchemaV1 = 1
+	// The event data, with the events of the txs in the events field instead
+	// of the data, their attributes as strings, the values in the tag encoding
+	// of the node (see NewEvents).
+	EventSchemaV2 = 2
+	// The event data, with the events of the ABCI responses as is, their
+	// attributes being base64-encoded key-value pairs.
+	EventSchemaV3 = 3
+
+	EventSchemaLatest = EventSchemaV3
+)
+
 // Event data from a subscription
 type ResultEvent struct {
-	Query string            `json:"query"`
-	Data  types.TMEventData `json:"data"`
+	Query  string            `json:"query"`
+	Data   types.TMEventData `json:"data"`
+	Seq    int64             `json:"seq,omitempty"`    // in the ack mode and /events
+	Schema int               `json:"schema,omitempty"` // from EventSchemaV2
+	Events []Event           `json:"events,omitempty"` // in EventSchemaV2
+	// Encoding of the values of the attributes of the events, in EventSchemaV2
+	Encoding types.TagEncoding `json:"encoding,omitempty"`
+}
+
+// List of the events of a query polled with /events
+type ResultEvents struct {
+	Events  []*ResultEvent `json:"events"`
+	Cursor  string         `json:"cursor"`  // of the last event, to poll the next ones
+	Dropped bool           `json:"dropped"` // events after the cursor were dropped before being polled
+}
+
+// Event of a tx, with string attributes
+type Event struct {
+	Type       string           `json:"type"`
+	Attributes []EventAttribute `json:"attributes"`
+}
+
+// Attribute of an Event, its value in the tag encoding of the node
+type EventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Number of events of a subscription in the ack mode not acked yet
+type ResultAck struct {
+	Unacked int `json:"unacked"`
+}
+
+// NewEvents returns the events with string attributes, the values in the
+// encoding.
+func NewEvents(events []abci.Event, encoding types.TagEncoding) []Event {
+	if events == nil {
+		return nil
+	}
+	res := make([]Event, len(events))
+	for i, event := range events {
+		res[i].Type = event.Type
+		res[i].Attributes = make([]EventAttribute, len(event.Attributes))
+		for j, attr := range event.Attributes {
+			res[i].Attributes[j] = EventAttribute{Key: string(attr.Key), Value: encoding.EncodeToString(attr.Value)}
+		}
+	}
+	return res
+}
+
+// EventDataWithTags returns the event data with the events of its ABCI
+// responses flattened to tags (see abci.TagsFromEvents), as in the first
+// version of the event schema. The data is left unchanged.
+func EventDataWithTags(data types.TMEventData) types.TMEventData {
+	switch data := data.(type) {
+	case types.EventDataTx:
+		data.Result.Tags, data.Result.Events = abci.TagsFromEvents(data.Result.Events), nil
+		return data
+	case types.EventDataNewBlock:
+		data.ResultBeginBlock, data.ResultEndBlock = beginBlockWithTags(data.ResultBeginBlock), endBlockWithTags(data.ResultEndBlock)
+		return data
+	case types.EventDataNewBlockHeader:
+		data.ResultBeginBlock, data.ResultEndBlock = beginBlockWithTags(data.ResultBeginBlock), endBlockWithTags(data.ResultEndBlock)
+		return data
+	}
+	return data
+}
+
+// EventDataWithEvents returns the event data with the tags of its ABCI
+// responses converted back to events (see abci.EventsFromTags), the inverse
+// of EventDataWithTags. The data is left unchanged.
+func EventDataWithEvents(data types.TMEventData) types.TMEventData {
+	switch data := data.(type) {
+	case types.EventDataTx:
+		data.Result.Events, data.Result.Tags = abci.EventsFromTags(data.Result.Tags), nil
+		return data
+	case types.EventDataNewBlock:
+		data.ResultBeginBlock, data.ResultEndBlock = beginBlockWithEvents(data.ResultBeginBlock), endBlockWithEvents(data.ResultEndBlock)
+		return data
+	case types.EventDataNewBlockHeader:
+		data.ResultBeginBlock, data.ResultEndBlock = beginBlockWithEvents(data.ResultBeginBlock), endBlockWithEvents(data.ResultEndBlock)
+		return data
+	}
+	return data
+}
+
+func beginBlockWithTags(res abci.ResponseBeginBlock) abci.ResponseBeginBlock {
+	res.Tags, res.Events = abci.TagsFromEvents(res.Events), nil
+	return res
+}
+
+func endBlockWithTags(res abci.ResponseEndBlock) abci.ResponseEndBlock {
+	res.Tags, res.Events = abci.TagsFromEvents(res.Events), nil
+	return res
+}
+
+func beginBlockWithEvents(res abci.ResponseBeginBlock) abci.ResponseBeginBlock {
+	res.Events, res.Tags = abci.EventsFromTags(res.Tags), nil
+	return res
+}
+
+func endBlockWithEvents(res abci.ResponseEndBlock) abci.ResponseEndBlock {
+	res.Events, res.Tags = abci.EventsFromTags(res.Tags), nil
+	return res
 }
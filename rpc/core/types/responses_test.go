@@ -5,7 +5,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
 )
 
 func TestStatusIndexer(t *testing.T) {
@@ -33,3 +36,50 @@ func TestStatusIndexer(t *testing.T) {
 		assert.Equal(t, tc.expected, status.TxIndexEnabled())
 	}
 }
+
+func TestNewEvents(t *testing.T) {
+	assert.Nil(t, NewEvents(nil, types.TagEncodingString))
+
+	events := []abci.Event{
+		{Type: "transfer", Attributes: []cmn.KVPair{
+			{Key: []byte("sender"), Value: []byte("alice")},
+			{Key: []byte("recipient"), Value: []byte("bob")},
+		}},
+		{Type: "", Attributes: []cmn.KVPair{{Key: []byte("fee"), Value: []byte("1")}}},
+	}
+	assert.Equal(t, []Event{
+		{Type: "transfer", Attributes: []EventAttribute{{"sender", "alice"}, {"recipient", "bob"}}},
+		{Type: "", Attributes: []EventAttribute{{"fee", "1"}}},
+	}, NewEvents(events, types.TagEncodingString))
+
+	assert.Equal(t, []Event{
+		{Type: "", Attributes: []EventAttribute{{"fee", "MQ=="}}},
+	}, NewEvents(events[1:], types.TagEncodingBase64))
+}
+
+func TestEventDataWithTags(t *testing.T) {
+	events := []abci.Event{
+		{Type: "transfer", Attributes: []cmn.KVPair{{Key: []byte("sender"), Value: []byte("alice")}}},
+	}
+	tags := []cmn.KVPair{{Key: []byte("transfer.sender"), Value: []byte("alice")}}
+
+	tx := types.EventDataTx{TxResult: types.TxResult{Height: 1, Tx: types.Tx("tx")}}
+	tx.Result.Events = events
+	txV1 := EventDataWithTags(tx).(types.EventDataTx)
+	assert.Equal(t, tags, txV1.Result.Tags)
+	assert.Empty(t, txV1.Result.Events)
+	assert.Equal(t, tx, EventDataWithEvents(txV1))
+	// the data is unchanged
+	assert.Equal(t, events, tx.Result.Events)
+
+	block := types.EventDataNewBlock{}
+	block.ResultBeginBlock.Events = events
+	block.ResultEndBlock.Events = events
+	blockV1 := EventDataWithTags(block).(types.EventDataNewBlock)
+	assert.Equal(t, tags, blockV1.ResultBeginBlock.Tags)
+	assert.Equal(t, tags, blockV1.ResultEndBlock.Tags)
+	assert.Equal(t, block, EventDataWithEvents(blockV1))
+
+	vote := types.EventDataVote{}
+	assert.Equal(t, vote, EventDataWithTags(vote))
+}
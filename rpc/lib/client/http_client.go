@@ -2,6 +2,7 @@ package rpcclient
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -98,7 +99,13 @@ func NewJSONRPCClient(remote string) *JSONRPCClient {
 	}
 }
 
+// Call calls the method with the params, unmarshalling its result into result.
 func (c *JSONRPCClient) Call(method string, params map[string]interface{}, result interface{}) (interface{}, error) {
+	return c.CallWithContext(context.Background(), method, params, result)
+}
+
+// CallWithContext is Call, canceled with the context, e.g. on its deadline.
+func (c *JSONRPCClient) CallWithContext(ctx context.Context, method string, params map[string]interface{}, result interface{}) (interface{}, error) {
 	request, err := types.MapToRequest(c.cdc, types.JSONRPCStringID("jsonrpc-client"), method, params)
 	if err != nil {
 		return nil, err
@@ -110,7 +117,12 @@ func (c *JSONRPCClient) Call(method string, params map[string]interface{}, resul
 	// log.Info(string(requestBytes))
 	requestBuf := bytes.NewBuffer(requestBytes)
 	// log.Info(Fmt("RPC request to %v (%v): %v", c.remote, method, string(requestBytes)))
-	httpResponse, err := c.client.Post(c.address, "text/json", requestBuf)
+	httpRequest, err := http.NewRequest(http.MethodPost, c.address, requestBuf)
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "text/json")
+	httpResponse, err := c.client.Do(httpRequest.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -150,13 +162,24 @@ func NewURIClient(remote string) *URIClient {
 	}
 }
 
+// Call calls the method with the params, unmarshalling its result into result.
 func (c *URIClient) Call(method string, params map[string]interface{}, result interface{}) (interface{}, error) {
+	return c.CallWithContext(context.Background(), method, params, result)
+}
+
+// CallWithContext is Call, canceled with the context, e.g. on its deadline.
+func (c *URIClient) CallWithContext(ctx context.Context, method string, params map[string]interface{}, result interface{}) (interface{}, error) {
 	values, err := argsToURLValues(c.cdc, params)
 	if err != nil {
 		return nil, err
 	}
 	// log.Info(Fmt("URI request to %v (%v): %v", c.address, method, values))
-	resp, err := c.client.PostForm(c.address+"/"+method, values)
+	req, err := http.NewRequest(http.MethodPost, c.address+"/"+method, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.client.Do(req.WithContext(ctx))
 	if err != nil {
 		return nil, err
 	}
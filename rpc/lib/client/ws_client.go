@@ -485,6 +485,15 @@ func (c *WSClient) Subscribe(ctx context.Context, query string) error {
 	return c.Call(ctx, "subscribe", params)
 }
 
+// SubscribeHeaderOnly subscribes to a query like Subscribe, but NewBlock
+// events are received without the body of the block, like NewBlockHeader
+// events. Note the server must have a "subscribe" route with a "header_only"
+// param defined.
+func (c *WSClient) SubscribeHeaderOnly(ctx context.Context, query string) error {
+	params := map[string]interface{}{"query": query, "header_only": true}
+	return c.Call(ctx, "subscribe", params)
+}
+
 // Unsubscribe from a query. Note the server must have a "unsubscribe" route
 // defined.
 func (c *WSClient) Unsubscribe(ctx context.Context, query string) error {
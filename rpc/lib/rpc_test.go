@@ -58,6 +58,8 @@ var Routes = map[string]*server.RPCFunc{
 	"echo_bytes":      server.NewRPCFunc(EchoBytesResult, "arg"),
 	"echo_data_bytes": server.NewRPCFunc(EchoDataBytesResult, "arg"),
 	"echo_int":        server.NewRPCFunc(EchoIntResult, "arg"),
+	"echo_context":    server.NewRPCFunc(EchoContextResult, "arg"),
+	"wait_context":    server.NewRPCFunc(WaitContextResult, ""),
 }
 
 // Amino codec required to encode/decode everything above.
@@ -83,6 +85,20 @@ func EchoDataBytesResult(v cmn.HexBytes) (*ResultEchoDataBytes, error) {
 	return &ResultEchoDataBytes{v}, nil
 }
 
+func EchoContextResult(ctx context.Context, v string) (*ResultEcho, error) {
+	return &ResultEcho{v}, ctx.Err()
+}
+
+// contextCanceled receives the errors of the contexts WaitContextResult waited
+// for.
+var contextCanceled = make(chan error, 1)
+
+func WaitContextResult(ctx context.Context) (*ResultEcho, error) {
+	<-ctx.Done()
+	contextCanceled <- ctx.Err()
+	return nil, ctx.Err()
+}
+
 func TestMain(m *testing.M) {
 	setup()
 	code := m.Run()
@@ -288,6 +304,32 @@ func TestServersAndClientsBasic(t *testing.T) {
 	}
 }
 
+func TestRPCFuncWithContext(t *testing.T) {
+	for _, cl := range []interface {
+		CallWithContext(ctx context.Context, method string, params map[string]interface{}, result interface{}) (interface{}, error)
+	}{
+		client.NewURIClient(tcpAddr),
+		client.NewJSONRPCClient(tcpAddr),
+	} {
+		result := new(ResultEcho)
+		_, err := cl.CallWithContext(context.Background(), "echo_context", map[string]interface{}{"arg": "acbd"}, result)
+		require.NoError(t, err)
+		assert.Equal(t, "acbd", result.Value)
+
+		// The context of the request is canceled once the client gives up.
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		_, err = cl.CallWithContext(ctx, "wait_context", map[string]interface{}{}, new(ResultEcho))
+		cancel()
+		assert.Error(t, err)
+		select {
+		case err := <-contextCanceled:
+			assert.Equal(t, context.Canceled, err)
+		case <-time.After(time.Second):
+			t.Fatal("the context of the request was not canceled")
+		}
+	}
+}
+
 func TestHexStringArg(t *testing.T) {
 	cl := client.NewURIClient(tcpAddr)
 	// should NOT be handled as hex
@@ -0,0 +1,112 @@
+package rpcserver
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// AccessLogConfig configures the AccessLogHandler.
+type AccessLogConfig struct {
+	// Fraction of the requests logged, from 0 (none) to 1 (all).
+	SampleRate float64
+
+	// Requests taking longer are logged as slow, sampled or not. 0 to not log
+	// the slow requests.
+	SlowThreshold time.Duration
+
+	// SlowThreshold of some endpoints, e.g. "broadcast_tx_commit".
+	SlowThresholds map[string]time.Duration
+}
+
+func (c AccessLogConfig) slowThreshold(endpoint string) time.Duration {
+	if threshold, ok := c.SlowThresholds[endpoint]; ok {
+		return threshold
+	}
+	return c.SlowThreshold
+}
+
+// AccessLogHandler wraps an HTTP handler, logging a sample of the requests,
+// with their endpoint, status and duration, and the requests slower than the
+// threshold of their endpoint, as errors.
+//
+// The endpoint of a request is the RPC method, of the URI or of the JSON-RPC
+// request, e.g. "status", without the prefix of its tenant. The WebSocket connections are logged once they are
+// closed, never as slow, and not the requests made through them.
+func AccessLogHandler(handler http.Handler, logger log.Logger, config AccessLogConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if config.SampleRate <= 0 && config.SlowThreshold <= 0 && len(config.SlowThresholds) == 0 {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		entry := &accessLogEntry{endpoint: strings.TrimPrefix(r.URL.Path, "/")}
+		alw := &accessLogResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		begin := time.Now()
+		handler.ServeHTTP(alw, r.WithContext(context.WithValue(r.Context(), accessLogEntryKey{}, entry)))
+		duration := time.Since(begin)
+
+		keyvals := []interface{}{
+			"endpoint", entry.endpoint,
+			"method", r.Method,
+			"status", alw.status,
+			"bytes", alw.bytes,
+			"duration", duration.Nanoseconds() / 1000000,
+			"remoteAddr", r.RemoteAddr,
+		}
+		if threshold := config.slowThreshold(entry.endpoint); threshold > 0 && duration > threshold && !alw.hijacked {
+			logger.Error("Slow RPC request", append(keyvals, "threshold", threshold)...)
+		} else if config.SampleRate >= 1 || config.SampleRate > 0 && cmn.RandFloat64() < config.SampleRate {
+			logger.Info("RPC request", keyvals...)
+		}
+	})
+}
+
+// accessLogEntry is the entry of a request in the access log, in its context,
+// for the handlers to set the endpoint of.
+type accessLogEntry struct {
+	endpoint string
+}
+
+type accessLogEntryKey struct{}
+
+// setRequestEndpoint sets the endpoint of the request logged by the
+// AccessLogHandler, if any, e.g. the method of a JSON-RPC request.
+func setRequestEndpoint(r *http.Request, endpoint string) {
+	if entry, ok := r.Context().Value(accessLogEntryKey{}).(*accessLogEntry); ok {
+		entry.endpoint = endpoint
+	}
+}
+
+// accessLogResponseWriter remembers the status and size of the response, and
+// whether the connection was hijacked, i.e. upgraded to a WebSocket.
+type accessLogResponseWriter struct {
+	http.ResponseWriter
+	status   int
+	bytes    int
+	hijacked bool
+}
+
+func (w *accessLogResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessLogResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// implements http.Hijacker
+func (w *accessLogResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	w.status = http.StatusSwitchingProtocols
+	return w.ResponseWriter.(http.Hijacker).Hijack()
+}
@@ -0,0 +1,142 @@
+package rpcserver
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	amino "github.com/tendermint/go-amino"
+
+	types "github.com/tendermint/tendermint/rpc/lib/types"
+)
+
+// Content types of the responses. A client can ask for CBOR or amino
+// responses with the Accept header of the request.
+const (
+	ContentTypeJSON = "application/json"
+
+	// The whole response (see types.RPCResponse) in CBOR, see
+	// types.JSONToCBOR.
+	ContentTypeCBOR = "application/cbor"
+
+	// Only the result, in amino binary. Errors are returned in JSON, with
+	// ContentTypeJSON.
+	ContentTypeAmino = "application/x-amino"
+)
+
+const (
+	// CanonicalJSONHeader is the HTTP header requesting canonical JSON
+	// responses (see types.CanonicalJSON), when set to "true". It is also set
+	// on the canonical responses.
+	CanonicalJSONHeader = "X-Canonical-JSON"
+
+	// CanonicalJSONParam is the URL query parameter requesting canonical JSON
+	// responses, like the header (e.g. /status?canonical_json=true).
+	CanonicalJSONParam = "canonical_json"
+)
+
+type responseEncoding int
+
+const (
+	encodingJSON responseEncoding = iota
+	encodingCanonicalJSON
+	encodingCBOR
+	encodingAmino
+)
+
+// encodingResponseWriter is a ResponseWriter for which the responses are
+// written with the given encoding, see withResponseEncoding.
+type encodingResponseWriter struct {
+	http.ResponseWriter
+	encoding responseEncoding
+}
+
+// withResponseEncoding returns a ResponseWriter for which the responses are
+// written with the encoding r asks for, or w if r asks for plain JSON.
+func withResponseEncoding(w http.ResponseWriter, r *http.Request) http.ResponseWriter {
+	if encoding := requestedEncoding(r); encoding != encodingJSON {
+		return encodingResponseWriter{w, encoding}
+	}
+	return w
+}
+
+func responseEncodingOf(w http.ResponseWriter) responseEncoding {
+	if ew, ok := w.(encodingResponseWriter); ok {
+		return ew.encoding
+	}
+	return encodingJSON
+}
+
+// requestedEncoding returns the encoding of the first supported content type
+// of the Accept header of r, ignoring their quality values. Without any, it
+// is canonical JSON if canonicalJSONRequested, and JSON otherwise.
+func requestedEncoding(r *http.Request) responseEncoding {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(accepted)
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case ContentTypeCBOR:
+			return encodingCBOR
+		case ContentTypeAmino:
+			return encodingAmino
+		}
+	}
+	if canonicalJSONRequested(r) {
+		return encodingCanonicalJSON
+	}
+	return encodingJSON
+}
+
+// canonicalJSONRequested returns true if r asks for canonical JSON responses,
+// with either CanonicalJSONHeader or CanonicalJSONParam.
+func canonicalJSONRequested(r *http.Request) bool {
+	if v := r.Header.Get(CanonicalJSONHeader); v != "" {
+		canonical, _ := strconv.ParseBool(v)
+		return canonical
+	}
+	canonical, _ := strconv.ParseBool(r.URL.Query().Get(CanonicalJSONParam))
+	return canonical
+}
+
+// marshalRPCResponse encodes the response with the encoding of w, and returns
+// it with its content type. Responses are in JSON for amino, as only the
+// results are encoded in amino (see writeAminoResult).
+func marshalRPCResponse(w http.ResponseWriter, res types.RPCResponse) ([]byte, string, error) {
+	switch responseEncodingOf(w) {
+	case encodingCanonicalJSON:
+		jsonBytes, err := json.Marshal(res)
+		if err != nil {
+			return nil, "", err
+		}
+		w.Header().Set(CanonicalJSONHeader, "true")
+		bz, err := types.CanonicalJSON(jsonBytes)
+		return bz, ContentTypeJSON, err
+	case encodingCBOR:
+		jsonBytes, err := json.Marshal(res)
+		if err != nil {
+			return nil, "", err
+		}
+		bz, err := types.JSONToCBOR(jsonBytes)
+		return bz, ContentTypeCBOR, err
+	default:
+		bz, err := json.MarshalIndent(res, "", "  ")
+		return bz, ContentTypeJSON, err
+	}
+}
+
+// writeAminoResult writes a successful response with only the given result,
+// encoded in amino binary. Nothing is written if it returns an error.
+func writeAminoResult(w http.ResponseWriter, cdc *amino.Codec, result interface{}) error {
+	bz, err := cdc.MarshalBinaryBare(result)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", ContentTypeAmino)
+	w.WriteHeader(200)
+	w.Write(bz) // nolint: errcheck, gas
+	return nil
+}
@@ -7,11 +7,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"reflect"
 	"runtime/debug"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -45,10 +48,13 @@ type RPCFunc struct {
 	returns  []reflect.Type // type of each return arg
 	argNames []string       // name of each argument
 	ws       bool           // websocket only
+	ctx      bool           // takes the context of the request first
 }
 
 // NewRPCFunc wraps a function for introspection.
-// f is the function, args are comma separated argument names
+// f is the function, args are comma separated argument names. If its first
+// argument is a context.Context, not named in args, it is called with the
+// context of the request, canceled once the client disconnects.
 func NewRPCFunc(f interface{}, args string) *RPCFunc {
 	return newRPCFunc(f, args, false)
 }
@@ -63,15 +69,37 @@ func newRPCFunc(f interface{}, args string, ws bool) *RPCFunc {
 	if args != "" {
 		argNames = strings.Split(args, ",")
 	}
+	argTypes := funcArgTypes(f)
 	return &RPCFunc{
 		f:        reflect.ValueOf(f),
-		args:     funcArgTypes(f),
+		args:     argTypes,
 		returns:  funcReturnTypes(f),
 		argNames: argNames,
 		ws:       ws,
+		ctx:      !ws && len(argTypes) > 0 && argTypes[0] == contextType,
 	}
 }
 
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// argsOffset returns the number of arguments of the function before the ones
+// of the request: the websocket connection, or the context.
+func (f *RPCFunc) argsOffset() int {
+	if f.ws || f.ctx {
+		return 1
+	}
+	return 0
+}
+
+// withContext prepends the context to the arguments of the function, if it
+// takes it.
+func (f *RPCFunc) withContext(ctx context.Context, args []reflect.Value) []reflect.Value {
+	if !f.ctx {
+		return args
+	}
+	return append([]reflect.Value{reflect.ValueOf(ctx)}, args...)
+}
+
 // return a function's argument types
 func funcArgTypes(f interface{}) []reflect.Type {
 	t := reflect.TypeOf(f)
@@ -101,6 +129,7 @@ func funcReturnTypes(f interface{}) []reflect.Type {
 // jsonrpc calls grab the given method's function info and runs reflect.Call
 func makeJSONRPCHandler(funcMap map[string]*RPCFunc, cdc *amino.Codec, logger log.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		w = withResponseEncoding(w, r)
 		b, err := ioutil.ReadAll(r.Body)
 		if err != nil {
 			WriteRPCResponseHTTP(w, types.RPCInvalidRequestError(types.JSONRPCStringID(""), errors.Wrap(err, "Error reading request body")))
@@ -119,6 +148,7 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, cdc *amino.Codec, logger lo
 			WriteRPCResponseHTTP(w, types.RPCParseError(types.JSONRPCStringID(""), errors.Wrap(err, "Error unmarshalling request")))
 			return
 		}
+		setRequestEndpoint(r, request.Method)
 		// A Notification is a Request object without an "id" member.
 		// The Server MUST NOT reply to a Notification, including those that are within a batch request.
 		if request.ID == types.JSONRPCStringID("") {
@@ -142,11 +172,17 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, cdc *amino.Codec, logger lo
 				return
 			}
 		}
-		returns := rpcFunc.f.Call(args)
+		returns := rpcFunc.f.Call(rpcFunc.withContext(r.Context(), args))
 		logger.Info("HTTPJSONRPC", "method", request.Method, "args", args, "returns", returns)
 		result, err := unreflectResult(returns)
 		if err != nil {
-			WriteRPCResponseHTTP(w, types.RPCInternalError(request.ID, err))
+			WriteRPCResponseHTTP(w, types.RPCFuncError(request.ID, err))
+			return
+		}
+		if responseEncodingOf(w) == encodingAmino {
+			if err := writeAminoResult(w, cdc, result); err != nil {
+				WriteRPCResponseHTTP(w, types.RPCInternalError(request.ID, err))
+			}
 			return
 		}
 		WriteRPCResponseHTTP(w, types.NewRPCSuccessResponse(cdc, request.ID, result))
@@ -206,7 +242,8 @@ func arrayParamsToArgs(rpcFunc *RPCFunc, cdc *amino.Codec, params []json.RawMess
 }
 
 // `raw` is unparsed json (from json.RawMessage) encoding either a map or an array.
-// `argsOffset` should be 0 for RPC calls, and 1 for WS requests, where len(rpcFunc.args) != len(rpcFunc.argNames).
+// `argsOffset` should be 0 for RPC calls, and 1 for WS requests and the functions
+// taking a context, where len(rpcFunc.args) != len(rpcFunc.argNames).
 //
 // Example:
 //   rpcFunc.args = [rpctypes.WSRPCContext string]
@@ -234,7 +271,7 @@ func jsonParamsToArgs(rpcFunc *RPCFunc, cdc *amino.Codec, raw []byte, argsOffset
 
 // Convert a []interface{} OR a map[string]interface{} to properly typed values
 func jsonParamsToArgsRPC(rpcFunc *RPCFunc, cdc *amino.Codec, params json.RawMessage) ([]reflect.Value, error) {
-	return jsonParamsToArgs(rpcFunc, cdc, params, 0)
+	return jsonParamsToArgs(rpcFunc, cdc, params, rpcFunc.argsOffset())
 }
 
 // Same as above, but with the first param the websocket connection
@@ -260,17 +297,24 @@ func makeHTTPHandler(rpcFunc *RPCFunc, cdc *amino.Codec, logger log.Logger) func
 	}
 	// All other endpoints
 	return func(w http.ResponseWriter, r *http.Request) {
+		w = withResponseEncoding(w, r)
 		logger.Debug("HTTP HANDLER", "req", r)
 		args, err := httpParamsToArgs(rpcFunc, cdc, r)
 		if err != nil {
 			WriteRPCResponseHTTP(w, types.RPCInvalidParamsError(types.JSONRPCStringID(""), errors.Wrap(err, "Error converting http params to arguments")))
 			return
 		}
-		returns := rpcFunc.f.Call(args)
+		returns := rpcFunc.f.Call(rpcFunc.withContext(r.Context(), args))
 		logger.Info("HTTPRestRPC", "method", r.URL.Path, "args", args, "returns", returns)
 		result, err := unreflectResult(returns)
 		if err != nil {
-			WriteRPCResponseHTTP(w, types.RPCInternalError(types.JSONRPCStringID(""), err))
+			WriteRPCResponseHTTP(w, types.RPCFuncError(types.JSONRPCStringID(""), err))
+			return
+		}
+		if responseEncodingOf(w) == encodingAmino {
+			if err := writeAminoResult(w, cdc, result); err != nil {
+				WriteRPCResponseHTTP(w, types.RPCInternalError(types.JSONRPCStringID(""), err))
+			}
 			return
 		}
 		WriteRPCResponseHTTP(w, types.NewRPCSuccessResponse(cdc, types.JSONRPCStringID(""), result))
@@ -280,10 +324,10 @@ func makeHTTPHandler(rpcFunc *RPCFunc, cdc *amino.Codec, logger log.Logger) func
 // Covert an http query to a list of properly typed values.
 // To be properly decoded the arg must be a concrete type from tendermint (if its an interface).
 func httpParamsToArgs(rpcFunc *RPCFunc, cdc *amino.Codec, r *http.Request) ([]reflect.Value, error) {
-	values := make([]reflect.Value, len(rpcFunc.args))
+	values := make([]reflect.Value, len(rpcFunc.argNames))
 
 	for i, name := range rpcFunc.argNames {
-		argType := rpcFunc.args[i]
+		argType := rpcFunc.args[i+rpcFunc.argsOffset()]
 
 		values[i] = reflect.Zero(argType) // set default for that type
 
@@ -408,6 +452,8 @@ const (
 	defaultWSPingPeriod        = (defaultWSReadWait * 9) / 10
 )
 
+var errEvicted = errors.New("websocket connection evicted")
+
 // A single websocket connection contains listener id, underlying ws
 // connection, and the event switch for subscribing to events.
 //
@@ -436,6 +482,20 @@ type wsConnection struct {
 
 	// object that is used to subscribe / unsubscribe from events
 	eventSub types.EventSubscriber
+
+	// write responses in canonical JSON
+	canonicalJSON bool
+
+	// called with the panics of the handlers
+	onPanic func(source string, v interface{}, stack []byte)
+
+	connectedAt time.Time
+	lastRequest int64  // unix nano time, atomic
+	evicted     uint32 // atomic, 1 once evicted, even if not started yet
+
+	// context of the requests, canceled once the connection stops
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 // NewWSConnection wraps websocket.Conn.
@@ -460,7 +520,12 @@ func NewWSConnection(
 		writeChanCapacity: defaultWSWriteChanCapacity,
 		readWait:          defaultWSReadWait,
 		pingPeriod:        defaultWSPingPeriod,
+		connectedAt:       time.Now(),
 	}
+	wsc.lastRequest = wsc.connectedAt.UnixNano()
+	// created here, as it's canceled by OnStop, which an eviction may run
+	// before OnStart
+	wsc.ctx, wsc.cancel = context.WithCancel(context.Background())
 	for _, option := range options {
 		option(wsc)
 	}
@@ -501,6 +566,25 @@ func ReadWait(readWait time.Duration) func(*wsConnection) {
 	}
 }
 
+// CanonicalJSON makes the connection write responses in canonical JSON (see
+// types.CanonicalJSON). It should only be used with NewWSConnection.
+// WebsocketManager sets it for the connections which ask for it, like HTTP
+// requests do.
+func CanonicalJSON() func(*wsConnection) {
+	return func(wsc *wsConnection) {
+		wsc.canonicalJSON = true
+	}
+}
+
+// OnPanic sets the function called with the panics of the handlers, before the
+// error is written to the client.
+// It should only be used in the constructor - not Goroutine-safe.
+func OnPanic(onPanic func(source string, v interface{}, stack []byte)) func(*wsConnection) {
+	return func(wsc *wsConnection) {
+		wsc.onPanic = onPanic
+	}
+}
+
 // PingPeriod sets the duration for sending websocket pings.
 // It should only be used in the constructor - not Goroutine-safe.
 func PingPeriod(pingPeriod time.Duration) func(*wsConnection) {
@@ -512,6 +596,9 @@ func PingPeriod(pingPeriod time.Duration) func(*wsConnection) {
 // OnStart implements cmn.Service by starting the read and write routines. It
 // blocks until the connection closes.
 func (wsc *wsConnection) OnStart() error {
+	if atomic.LoadUint32(&wsc.evicted) == 1 {
+		return errEvicted
+	}
 	wsc.writeChan = make(chan types.RPCResponse, wsc.writeChanCapacity)
 
 	// Read subscriptions/unsubscriptions to events
@@ -526,11 +613,19 @@ func (wsc *wsConnection) OnStart() error {
 func (wsc *wsConnection) OnStop() {
 	// Both read and write loops close the websocket connection when they exit their loops.
 	// The writeChan is never closed, to allow WriteRPCResponse() to fail.
+	wsc.cancel()
 	if wsc.eventSub != nil {
 		wsc.eventSub.UnsubscribeAll(context.TODO(), wsc.remoteAddr)
 	}
 }
 
+// evict stops the connection. If it's not started yet, which Stop ignores,
+// it won't start.
+func (wsc *wsConnection) evict() {
+	atomic.StoreUint32(&wsc.evicted, 1)
+	wsc.Stop()
+}
+
 // GetRemoteAddr returns the remote address of the underlying connection.
 // It implements WSRPCConnection
 func (wsc *wsConnection) GetRemoteAddr() string {
@@ -575,11 +670,15 @@ func (wsc *wsConnection) Codec() *amino.Codec {
 func (wsc *wsConnection) readRoutine() {
 	defer func() {
 		if r := recover(); r != nil {
+			stack := debug.Stack()
+			if wsc.onPanic != nil {
+				wsc.onPanic(fmt.Sprintf("RPC websocket handler for %s", wsc.remoteAddr), r, stack)
+			}
 			err, ok := r.(error)
 			if !ok {
 				err = fmt.Errorf("WSJSONRPC: %v", r)
 			}
-			wsc.Logger.Error("Panic in WSJSONRPC handler", "err", err, "stack", string(debug.Stack()))
+			wsc.Logger.Error("Panic in WSJSONRPC handler", "err", err, "stack", string(stack))
 			wsc.WriteRPCResponse(types.RPCInternalError(types.JSONRPCStringID("unknown"), err))
 			go wsc.readRoutine()
 		} else {
@@ -612,6 +711,8 @@ func (wsc *wsConnection) readRoutine() {
 				return
 			}
 
+			atomic.StoreInt64(&wsc.lastRequest, time.Now().UnixNano())
+
 			var request types.RPCRequest
 			err = json.Unmarshal(in, &request)
 			if err != nil {
@@ -648,14 +749,14 @@ func (wsc *wsConnection) readRoutine() {
 				wsc.WriteRPCResponse(types.RPCInternalError(request.ID, errors.Wrap(err, "Error converting json params to arguments")))
 				continue
 			}
-			returns := rpcFunc.f.Call(args)
+			returns := rpcFunc.f.Call(rpcFunc.withContext(wsc.ctx, args))
 
 			// TODO: Need to encode args/returns to string if we want to log them
 			wsc.Logger.Info("WSJSONRPC", "method", request.Method)
 
 			result, err := unreflectResult(returns)
 			if err != nil {
-				wsc.WriteRPCResponse(types.RPCInternalError(request.ID, err))
+				wsc.WriteRPCResponse(types.RPCFuncError(request.ID, err))
 				continue
 			}
 
@@ -699,7 +800,7 @@ func (wsc *wsConnection) writeRoutine() {
 				return
 			}
 		case msg := <-wsc.writeChan:
-			jsonBytes, err := json.MarshalIndent(msg, "", "  ")
+			jsonBytes, err := wsc.marshalRPCResponse(msg)
 			if err != nil {
 				wsc.Logger.Error("Failed to marshal RPCResponse to JSON", "err", err)
 			} else {
@@ -715,6 +816,17 @@ func (wsc *wsConnection) writeRoutine() {
 	}
 }
 
+func (wsc *wsConnection) marshalRPCResponse(res types.RPCResponse) ([]byte, error) {
+	if !wsc.canonicalJSON {
+		return json.MarshalIndent(res, "", "  ")
+	}
+	jsonBytes, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	return types.CanonicalJSON(jsonBytes)
+}
+
 // All writes to the websocket must (re)set the write deadline.
 // If some writes don't set it while others do, they may timeout incorrectly (https://github.com/tendermint/tendermint/issues/553)
 func (wsc *wsConnection) writeMessageWithDeadline(msgType int, msg []byte) error {
@@ -726,6 +838,20 @@ func (wsc *wsConnection) writeMessageWithDeadline(msgType int, msg []byte) error
 
 //----------------------------------------
 
+// EvictionPolicy tells which websocket connection a WebsocketManager evicts
+// for a new one when it has the max number of connections.
+type EvictionPolicy string
+
+const (
+	// EvictNone rejects the new connection.
+	EvictNone EvictionPolicy = "reject"
+	// EvictOldest evicts the oldest connection.
+	EvictOldest EvictionPolicy = "oldest"
+	// EvictLRU evicts the connection which sent a request the longest time
+	// ago.
+	EvictLRU EvictionPolicy = "lru"
+)
+
 // WebsocketManager provides a WS handler for incoming connections and passes a
 // map of functions along with any additional params to new connections.
 // NOTE: The websocket path is defined externally, e.g. in node/node.go
@@ -736,6 +862,15 @@ type WebsocketManager struct {
 	cdc           *amino.Codec
 	logger        log.Logger
 	wsConnOptions []func(*wsConnection)
+
+	mtx           sync.Mutex
+	maxConnsPerIP int            // 0 - unlimited
+	connsPerIP    map[string]int // number of open connections by IP
+	maxConns      int            // 0 - unlimited
+	eviction      EvictionPolicy
+	numConns      int                        // open or being opened
+	conns         map[*wsConnection]struct{} // open, not evicted
+	metrics       *Metrics
 }
 
 // NewWebsocketManager returns a new WebsocketManager that passes a map of
@@ -752,9 +887,42 @@ func NewWebsocketManager(funcMap map[string]*RPCFunc, cdc *amino.Codec, wsConnOp
 		},
 		logger:        log.NewNopLogger(),
 		wsConnOptions: wsConnOptions,
+		connsPerIP:    make(map[string]int),
+		eviction:      EvictNone,
+		conns:         make(map[*wsConnection]struct{}),
+		metrics:       NopMetrics(),
 	}
 }
 
+// SetMaxConnections sets the maximum number of simultaneous connections, over
+// all the IPs, and which connection is evicted for a new one when it's
+// reached. 0 means unlimited.
+func (wm *WebsocketManager) SetMaxConnections(max int, eviction EvictionPolicy) {
+	wm.mtx.Lock()
+	wm.maxConns = max
+	wm.eviction = eviction
+	wm.mtx.Unlock()
+}
+
+// SetMetrics sets the metrics.
+func (wm *WebsocketManager) SetMetrics(metrics *Metrics) {
+	wm.metrics = metrics
+}
+
+// SetMaxConnectionsPerIP sets the maximum number of simultaneous connections
+// from a single IP. 0 means unlimited.
+func (wm *WebsocketManager) SetMaxConnectionsPerIP(max int) {
+	wm.mtx.Lock()
+	wm.maxConnsPerIP = max
+	wm.mtx.Unlock()
+}
+
+// SetPanicHandler sets the function called with the panics of the handlers of
+// the connections. It must be called before serving any connection.
+func (wm *WebsocketManager) SetPanicHandler(handler func(source string, v interface{}, stack []byte)) {
+	wm.wsConnOptions = append(wm.wsConnOptions, OnPanic(handler))
+}
+
 // SetLogger sets the logger.
 func (wm *WebsocketManager) SetLogger(l log.Logger) {
 	wm.logger = l
@@ -763,6 +931,44 @@ func (wm *WebsocketManager) SetLogger(l log.Logger) {
 // WebsocketHandler upgrades the request/response (via http.Hijack) and starts
 // the wsConnection.
 func (wm *WebsocketManager) WebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	if max, ok := wm.addConn(ip); !ok {
+		wm.logger.Info("Rejected websocket connection", "remote", r.RemoteAddr, "reason", "too many connections from the IP")
+		wm.metrics.RejectedWebsocketConnections.Add(1)
+		WriteRPCResponseHTTPError(w, http.StatusTooManyRequests, types.NewRPCErrorResponse(
+			types.JSONRPCStringID(""),
+			types.CodeTooManyConnections,
+			"Too many connections",
+			fmt.Sprintf("max %d websocket connections per IP", max),
+		))
+		return
+	}
+	defer wm.removeConn(ip)
+
+	evicted, max, ok := wm.admitConn()
+	if !ok {
+		wm.logger.Info("Rejected websocket connection", "remote", r.RemoteAddr, "reason", "too many connections")
+		wm.metrics.RejectedWebsocketConnections.Add(1)
+		WriteRPCResponseHTTPError(w, http.StatusTooManyRequests, types.NewRPCErrorResponse(
+			types.JSONRPCStringID(""),
+			types.CodeTooManyConnections,
+			"Too many connections",
+			fmt.Sprintf("max %d websocket connections", max),
+		))
+		return
+	}
+	if evicted != nil {
+		wm.logger.Info("Evicted websocket connection", "remote", evicted.remoteAddr, "for", r.RemoteAddr)
+		wm.metrics.EvictedWebsocketConnections.Add(1)
+		evicted.evict()
+	}
+
+	var con *wsConnection
+	defer func() { wm.releaseConn(con) }()
+
 	wsConn, err := wm.Upgrade(w, r, nil)
 	if err != nil {
 		// TODO - return http error
@@ -771,15 +977,107 @@ func (wm *WebsocketManager) WebsocketHandler(w http.ResponseWriter, r *http.Requ
 	}
 
 	// register connection
-	con := NewWSConnection(wsConn, wm.funcMap, wm.cdc, wm.wsConnOptions...)
+	options := wm.wsConnOptions
+	if canonicalJSONRequested(r) {
+		options = append(options[:len(options):len(options)], CanonicalJSON())
+	}
+	con = NewWSConnection(wsConn, wm.funcMap, wm.cdc, options...)
 	con.SetLogger(wm.logger.With("remote", wsConn.RemoteAddr()))
+	wm.registerConn(con)
 	wm.logger.Info("New websocket connection", "remote", con.remoteAddr)
 	err = con.Start() // Blocking
 	if err != nil {
+		// e.g. evicted already
 		wm.logger.Error("Error starting connection", "err", err)
+		wsConn.Close() // nolint: errcheck
 	}
 }
 
+// addConn registers a connection from the given IP, unless the IP already
+// has the maximum number of connections, which is returned.
+func (wm *WebsocketManager) addConn(ip string) (max int, ok bool) {
+	wm.mtx.Lock()
+	defer wm.mtx.Unlock()
+	if wm.maxConnsPerIP > 0 && wm.connsPerIP[ip] >= wm.maxConnsPerIP {
+		return wm.maxConnsPerIP, false
+	}
+	wm.connsPerIP[ip]++
+	return wm.maxConnsPerIP, true
+}
+
+func (wm *WebsocketManager) removeConn(ip string) {
+	wm.mtx.Lock()
+	defer wm.mtx.Unlock()
+	wm.connsPerIP[ip]--
+	if wm.connsPerIP[ip] == 0 {
+		delete(wm.connsPerIP, ip)
+	}
+}
+
+// admitConn reserves a connection, unless there are already the maximum
+// number of them, which is returned, and no connection can be evicted for it.
+// The connection to evict, if any, is returned, and must be stopped.
+func (wm *WebsocketManager) admitConn() (evicted *wsConnection, max int, ok bool) {
+	wm.mtx.Lock()
+	defer wm.mtx.Unlock()
+	if wm.maxConns > 0 && wm.numConns >= wm.maxConns {
+		evicted = wm.connToEvict()
+		if evicted == nil {
+			return nil, wm.maxConns, false
+		}
+		delete(wm.conns, evicted)
+		wm.numConns--
+		wm.metrics.WebsocketConnections.Set(float64(len(wm.conns)))
+	}
+	wm.numConns++
+	return evicted, wm.maxConns, true
+}
+
+// connToEvict returns the connection to evict under the eviction policy, or
+// nil for none. Must be called with wm.mtx held.
+func (wm *WebsocketManager) connToEvict() *wsConnection {
+	var evicted *wsConnection
+	for con := range wm.conns {
+		switch wm.eviction {
+		case EvictOldest:
+			if evicted == nil || con.connectedAt.Before(evicted.connectedAt) {
+				evicted = con
+			}
+		case EvictLRU:
+			if evicted == nil || atomic.LoadInt64(&con.lastRequest) < atomic.LoadInt64(&evicted.lastRequest) {
+				evicted = con
+			}
+		default:
+			return nil
+		}
+	}
+	return evicted
+}
+
+// registerConn registers a connection admitted by admitConn, once open.
+func (wm *WebsocketManager) registerConn(con *wsConnection) {
+	wm.mtx.Lock()
+	defer wm.mtx.Unlock()
+	wm.conns[con] = struct{}{}
+	wm.metrics.WebsocketConnections.Set(float64(len(wm.conns)))
+}
+
+// releaseConn releases a connection admitted by admitConn, nil if it failed to
+// open, unless it was evicted.
+func (wm *WebsocketManager) releaseConn(con *wsConnection) {
+	wm.mtx.Lock()
+	defer wm.mtx.Unlock()
+	if con != nil {
+		if _, ok := wm.conns[con]; !ok {
+			// evicted
+			return
+		}
+		delete(wm.conns, con)
+	}
+	wm.numConns--
+	wm.metrics.WebsocketConnections.Set(float64(len(wm.conns)))
+}
+
 // rpc.websocket
 //-----------------------------------------------------------------------------
 
@@ -787,6 +1085,9 @@ func (wm *WebsocketManager) WebsocketHandler(w http.ResponseWriter, r *http.Requ
 func unreflectResult(returns []reflect.Value) (interface{}, error) {
 	errV := returns[1]
 	if errV.Interface() != nil {
+		if rpcErr, ok := errV.Interface().(*types.RPCError); ok {
+			return nil, rpcErr
+		}
 		return nil, errors.Errorf("%v", errV.Interface())
 	}
 	rv := returns[0]
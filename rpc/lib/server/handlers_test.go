@@ -7,8 +7,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/go-kit/kit/metrics"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,6 +32,9 @@ import (
 func testMux() *http.ServeMux {
 	funcMap := map[string]*rs.RPCFunc{
 		"c": rs.NewRPCFunc(func(s string, i int) (string, error) { return "foo", nil }, "s,i"),
+		"e": rs.NewRPCFunc(func() (string, error) {
+			return "", &types.RPCError{Code: types.CodeTooManySubscriptions, Message: "Too many subscriptions", Data: "max 1"}
+		}, ""),
 	}
 	cdc := amino.NewCodec()
 	mux := http.NewServeMux()
@@ -154,6 +160,91 @@ func TestRPCNotification(t *testing.T) {
 	require.Equal(t, len(blob), 0, "a notification SHOULD NOT be responded to by the server")
 }
 
+func TestRPCFuncError(t *testing.T) {
+	mux := testMux()
+	req, _ := http.NewRequest("POST", "http://localhost/", strings.NewReader(`{"jsonrpc": "2.0", "method": "e", "id": "0"}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	res := rec.Result()
+	blob, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	recv := new(types.RPCResponse)
+	require.NoError(t, json.Unmarshal(blob, recv))
+	require.NotNil(t, recv.Error)
+	// errors of type *RPCError are returned as is
+	assert.Equal(t, types.RPCError{Code: types.CodeTooManySubscriptions, Message: "Too many subscriptions", Data: "max 1"}, *recv.Error)
+}
+
+func TestCanonicalJSONResponses(t *testing.T) {
+	mux := testMux()
+	payload := `{"jsonrpc": "2.0", "method": "c", "id": "0", "params": ["a", "10"]}`
+	want := `{"id":"0","jsonrpc":"2.0","result":"foo"}`
+
+	// requested with the header
+	req, _ := http.NewRequest("POST", "http://localhost/", strings.NewReader(payload))
+	req.Header.Set(rs.CanonicalJSONHeader, "true")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.Equal(t, want, rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(rs.CanonicalJSONHeader))
+
+	// requested with the URL param
+	req, _ = http.NewRequest("GET", "http://localhost/c?s=\"a\"&i=10&canonical_json=true", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.Equal(t, `{"id":"","jsonrpc":"2.0","result":"foo"}`, rec.Body.String())
+
+	// not requested
+	req, _ = http.NewRequest("POST", "http://localhost/", strings.NewReader(payload))
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.NotEqual(t, want, rec.Body.String())
+	assert.Empty(t, rec.Header().Get(rs.CanonicalJSONHeader))
+}
+
+func TestResponseEncodings(t *testing.T) {
+	mux := testMux()
+	payload := `{"jsonrpc": "2.0", "method": "c", "id": "0", "params": ["a", "10"]}`
+
+	// CBOR
+	req, _ := http.NewRequest("POST", "http://localhost/", strings.NewReader(payload))
+	req.Header.Set("Accept", "application/cbor, application/json;q=0.9")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	want, err := types.JSONToCBOR([]byte(`{"id":"0","jsonrpc":"2.0","result":"foo"}`))
+	require.NoError(t, err)
+	assert.Equal(t, want, rec.Body.Bytes())
+	assert.Equal(t, rs.ContentTypeCBOR, rec.Header().Get("Content-Type"))
+
+	// amino, only the result
+	req, _ = http.NewRequest("GET", "http://localhost/c?s=\"a\"&i=10", nil)
+	req.Header.Set("Accept", rs.ContentTypeAmino)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.Equal(t, rs.ContentTypeAmino, rec.Header().Get("Content-Type"))
+	var result string
+	require.NoError(t, amino.NewCodec().UnmarshalBinaryBare(rec.Body.Bytes(), &result))
+	assert.Equal(t, "foo", result)
+
+	// protobuf isn't supported
+	req, _ = http.NewRequest("GET", "http://localhost/c?s=\"a\"&i=10", nil)
+	req.Header.Set("Accept", "application/x-protobuf")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.Equal(t, rs.ContentTypeJSON, rec.Header().Get("Content-Type"))
+
+	// amino, errors are in JSON
+	req, _ = http.NewRequest("POST", "http://localhost/", strings.NewReader(`{"jsonrpc": "2.0", "method": "e", "id": "0"}`))
+	req.Header.Set("Accept", rs.ContentTypeAmino)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.Equal(t, rs.ContentTypeJSON, rec.Header().Get("Content-Type"))
+	recv := new(types.RPCResponse)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), recv))
+	assert.NotNil(t, recv.Error)
+}
+
 func TestUnknownRPCPath(t *testing.T) {
 	mux := testMux()
 	req, _ := http.NewRequest("GET", "http://localhost/unknownrpcpath", nil)
@@ -193,12 +284,144 @@ func TestWebsocketManagerHandler(t *testing.T) {
 	require.Nil(t, resp.Error)
 }
 
+// counter is a metrics.Counter recording its total.
+type counter struct {
+	total int64
+}
+
+func (c *counter) With(labelValues ...string) metrics.Counter { return c }
+func (c *counter) Add(delta float64)                          { atomic.AddInt64(&c.total, int64(delta)) }
+func (c *counter) Total() int64                               { return atomic.LoadInt64(&c.total) }
+
+func rejectionsCounter(wm *rs.WebsocketManager) *counter {
+	rejected := &counter{}
+	wsMetrics := rs.NopMetrics()
+	wsMetrics.RejectedWebsocketConnections = rejected
+	wm.SetMetrics(wsMetrics)
+	return rejected
+}
+
+func TestWebsocketManagerMaxConnectionsPerIP(t *testing.T) {
+	var rejected *counter
+	s := newWSServerWith(func(wm *rs.WebsocketManager) {
+		wm.SetMaxConnectionsPerIP(1)
+		rejected = rejectionsCounter(wm)
+	})
+	defer s.Close()
+
+	d := websocket.Dialer{}
+	c, _, err := d.Dial("ws://"+s.Listener.Addr().String()+"/websocket", nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	// a second connection from the same IP is rejected
+	_, dialResp, err := d.Dial("ws://"+s.Listener.Addr().String()+"/websocket", nil)
+	require.Error(t, err)
+	require.NotNil(t, dialResp)
+	assert.Equal(t, http.StatusTooManyRequests, dialResp.StatusCode)
+
+	var resp types.RPCResponse
+	require.NoError(t, json.NewDecoder(dialResp.Body).Decode(&resp))
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, types.CodeTooManyConnections, resp.Error.Code)
+	assert.EqualValues(t, 1, rejected.Total())
+}
+
+func TestWebsocketManagerMaxConnections(t *testing.T) {
+	var rejected *counter
+	s := newWSServerWith(func(wm *rs.WebsocketManager) {
+		wm.SetMaxConnections(1, rs.EvictNone)
+		rejected = rejectionsCounter(wm)
+	})
+	defer s.Close()
+
+	d := websocket.Dialer{}
+	c, _, err := d.Dial("ws://"+s.Listener.Addr().String()+"/websocket", nil)
+	require.NoError(t, err)
+
+	// a second connection is rejected
+	_, dialResp, err := d.Dial("ws://"+s.Listener.Addr().String()+"/websocket", nil)
+	require.Error(t, err)
+	require.NotNil(t, dialResp)
+	assert.Equal(t, http.StatusTooManyRequests, dialResp.StatusCode)
+
+	var resp types.RPCResponse
+	require.NoError(t, json.NewDecoder(dialResp.Body).Decode(&resp))
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, types.CodeTooManyConnections, resp.Error.Code)
+	assert.EqualValues(t, 1, rejected.Total())
+
+	// until the first one is closed
+	c.Close()
+	for i := 0; ; i++ {
+		c, _, err = d.Dial("ws://"+s.Listener.Addr().String()+"/websocket", nil)
+		if err == nil {
+			break
+		}
+		require.True(t, i < 50, "connection still rejected")
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Close()
+}
+
+func TestWebsocketManagerEviction(t *testing.T) {
+	for _, eviction := range []rs.EvictionPolicy{rs.EvictOldest, rs.EvictLRU} {
+		t.Run(string(eviction), func(t *testing.T) {
+			s := newWSServerWith(func(wm *rs.WebsocketManager) { wm.SetMaxConnections(2, eviction) })
+			defer s.Close()
+
+			d := websocket.Dialer{}
+			dial := func() *websocket.Conn {
+				c, _, err := d.Dial("ws://"+s.Listener.Addr().String()+"/websocket", nil)
+				require.NoError(t, err)
+				time.Sleep(10 * time.Millisecond)
+				return c
+			}
+			request := func(c *websocket.Conn) {
+				req, err := types.MapToRequest(amino.NewCodec(), types.JSONRPCStringID("TestWebsocketManager"), "c", map[string]interface{}{"s": "a", "i": 10})
+				require.NoError(t, err)
+				require.NoError(t, c.WriteJSON(req))
+				var resp types.RPCResponse
+				require.NoError(t, c.ReadJSON(&resp))
+				require.Nil(t, resp.Error)
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			c1 := dial()
+			defer c1.Close()
+			c2 := dial()
+			defer c2.Close()
+			// the first connection is the oldest one, but the second one is
+			// the least recently used
+			request(c1)
+
+			c3 := dial()
+			defer c3.Close()
+			request(c3)
+
+			evicted, kept := c1, c2
+			if eviction == rs.EvictLRU {
+				evicted, kept = c2, c1
+			}
+			evicted.SetReadDeadline(time.Now().Add(time.Second))
+			_, _, err := evicted.ReadMessage()
+			assert.Error(t, err)
+			request(kept)
+		})
+	}
+}
+
 func newWSServer() *httptest.Server {
+	return newWSServerWith(func(*rs.WebsocketManager) {})
+}
+
+func newWSServerWith(configure func(*rs.WebsocketManager)) *httptest.Server {
 	funcMap := map[string]*rs.RPCFunc{
 		"c": rs.NewWSRPCFunc(func(wsCtx types.WSRPCContext, s string, i int) (string, error) { return "foo", nil }, "s,i"),
 	}
 	wm := rs.NewWebsocketManager(funcMap, amino.NewCodec())
 	wm.SetLogger(log.TestingLogger())
+	configure(wm)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/websocket", wm.WebsocketHandler)
@@ -3,10 +3,10 @@ package rpcserver
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
+	"path"
 	"runtime/debug"
 	"strings"
 	"time"
@@ -84,24 +84,24 @@ func WriteRPCResponseHTTPError(
 	httpCode int,
 	res types.RPCResponse,
 ) {
-	jsonBytes, err := json.MarshalIndent(res, "", "  ")
+	bz, contentType, err := marshalRPCResponse(w, res)
 	if err != nil {
 		panic(err)
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(httpCode)
-	w.Write(jsonBytes) // nolint: errcheck, gas
+	w.Write(bz) // nolint: errcheck, gas
 }
 
 func WriteRPCResponseHTTP(w http.ResponseWriter, res types.RPCResponse) {
-	jsonBytes, err := json.MarshalIndent(res, "", "  ")
+	bz, contentType, err := marshalRPCResponse(w, res)
 	if err != nil {
 		panic(err)
 	}
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(200)
-	w.Write(jsonBytes) // nolint: errcheck, gas
+	w.Write(bz) // nolint: errcheck, gas
 }
 
 //-----------------------------------------------------------------------------
@@ -132,7 +132,11 @@ func RecoverAndLogHandler(handler http.Handler, logger log.Logger) http.Handler
 						"Panic in RPC HTTP handler", "err", e, "stack",
 						string(debug.Stack()),
 					)
-					WriteRPCResponseHTTPError(rww, http.StatusInternalServerError, types.RPCInternalError(types.JSONRPCStringID(""), e.(error)))
+					err, ok := e.(error)
+					if !ok {
+						err = fmt.Errorf("%v", e)
+					}
+					WriteRPCResponseHTTPError(rww, http.StatusInternalServerError, types.RPCInternalError(types.JSONRPCStringID(""), err))
 				}
 			}
 
@@ -152,6 +156,39 @@ func RecoverAndLogHandler(handler http.Handler, logger log.Logger) http.Handler
 	})
 }
 
+// ShedLoadHandler wraps an HTTP handler, rejecting the requests with an HTTP
+// 503 error while overloaded returns true. The /health requests, under any
+// tenant prefix, are always served, so that the node isn't reported down
+// while shedding load.
+func ShedLoadHandler(handler http.Handler, overloaded func() bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) != "health" && overloaded() {
+			WriteRPCResponseHTTPError(w, http.StatusServiceUnavailable,
+				types.RPCInternalError(types.JSONRPCStringID(""), errors.New("server overloaded, retry later")))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// PanicHandler wraps an HTTP handler, calling onPanic with the panics of the
+// handler, before passing them on to the RecoverAndLogHandler, which sends an
+// HTTP 500 error response. The RPC responses and http.ErrAbortHandler are not
+// handled as panics.
+func PanicHandler(handler http.Handler, onPanic func(source string, v interface{}, stack []byte)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if e := recover(); e != nil {
+				if _, ok := e.(types.RPCResponse); !ok && e != http.ErrAbortHandler {
+					onPanic(fmt.Sprintf("RPC HTTP handler for %s %s", r.Method, r.URL.Path), e, debug.Stack())
+				}
+				panic(e)
+			}
+		}()
+		handler.ServeHTTP(w, r)
+	})
+}
+
 // Remember the status for logging
 type ResponseWriterWrapper struct {
 	Status int
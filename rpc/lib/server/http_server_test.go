@@ -1,11 +1,15 @@
 package rpcserver
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -14,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/tendermint/tendermint/libs/log"
+	types "github.com/tendermint/tendermint/rpc/lib/types"
 )
 
 func TestMaxOpenConnections(t *testing.T) {
@@ -77,3 +82,122 @@ func TestStartHTTPAndTLSServer(t *testing.T) {
 
 	// TODO: test that starting the server can actually work
 }
+
+func TestShedLoadHandler(t *testing.T) {
+	var overloaded int32
+	handler := ShedLoadHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), func() bool { return atomic.LoadInt32(&overloaded) == 1 })
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/status", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	atomic.StoreInt32(&overloaded, 1)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/status", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Contains(t, rec.Body.String(), "server overloaded")
+
+	// /health is still served
+	for _, path := range []string{"/health", "/node1/health"} {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		require.Equal(t, http.StatusOK, rec.Code, path)
+	}
+}
+
+func TestPanicHandler(t *testing.T) {
+	var sources []string
+	handler := RecoverAndLogHandler(PanicHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/panic":
+			panic("boom")
+		case "/rpc_error":
+			panic(types.RPCInvalidParamsError(types.JSONRPCStringID(""), errors.New("bad param")))
+		}
+		w.WriteHeader(http.StatusOK)
+	}), func(source string, v interface{}, stack []byte) {
+		require.Equal(t, "boom", v)
+		require.NotEmpty(t, stack)
+		sources = append(sources, source)
+	}), log.TestingLogger())
+
+	for _, path := range []string{"/status", "/rpc_error", "/panic"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	require.Equal(t, []string{"RPC HTTP handler for GET /panic"}, sources)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/panic", nil))
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestAccessLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	handler := AccessLogHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			setRequestEndpoint(r, "tx_search")
+			time.Sleep(20 * time.Millisecond)
+		case "/broadcast_tx_commit":
+			time.Sleep(20 * time.Millisecond)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprint(w, "body")
+	}), log.NewTMLogger(&buf), AccessLogConfig{
+		SampleRate:     1,
+		SlowThreshold:  10 * time.Millisecond,
+		SlowThresholds: map[string]time.Duration{"broadcast_tx_commit": time.Minute},
+	})
+	serve := func(method, path string) string {
+		buf.Reset()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+		return buf.String()
+	}
+
+	line := serve("GET", "/status")
+	require.True(t, strings.HasPrefix(line, "I"), line)
+	require.Contains(t, line, "RPC request")
+	require.Contains(t, line, "endpoint=status")
+	require.Contains(t, line, "status=200")
+	require.Contains(t, line, "bytes=4")
+
+	require.Contains(t, serve("GET", "/missing"), "status=404")
+
+	// the endpoint of a JSON-RPC request is its method
+	line = serve("POST", "/")
+	require.True(t, strings.HasPrefix(line, "E"), line)
+	require.Contains(t, line, "Slow RPC request")
+	require.Contains(t, line, "endpoint=tx_search")
+
+	// the threshold of the endpoint
+	line = serve("GET", "/broadcast_tx_commit")
+	require.Contains(t, line, "RPC request")
+	require.NotContains(t, line, "Slow")
+
+	// the endpoint of a tenant is without its prefix
+	tenants, err := NewTenantsHandler([]Tenant{{
+		Name:   "chain-a",
+		Prefix: "/chain-a",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(20 * time.Millisecond)
+		}),
+	}})
+	require.NoError(t, err)
+	handler = AccessLogHandler(tenants, log.NewTMLogger(&buf), AccessLogConfig{
+		SampleRate:     1,
+		SlowThreshold:  10 * time.Millisecond,
+		SlowThresholds: map[string]time.Duration{"broadcast_tx_commit": time.Minute},
+	})
+	line = serve("GET", "/chain-a/broadcast_tx_commit")
+	require.Contains(t, line, "RPC request")
+	require.Contains(t, line, "endpoint=broadcast_tx_commit")
+	require.NotContains(t, line, "Slow")
+
+	// not sampled
+	handler = AccessLogHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		log.NewTMLogger(&buf), AccessLogConfig{SlowThreshold: time.Minute})
+	require.Empty(t, serve("GET", "/status"))
+}
@@ -0,0 +1,65 @@
+package rpcserver
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "rpc"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of open websocket connections.
+	WebsocketConnections metrics.Gauge
+	// Number of websocket connections rejected over the max number of them,
+	// in total or per IP.
+	RejectedWebsocketConnections metrics.Counter
+	// Number of websocket connections evicted for new ones over the max
+	// number of them.
+	EvictedWebsocketConnections metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		WebsocketConnections: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "websocket_connections",
+			Help:      "Number of open websocket connections.",
+		}, labels).With(labelsAndValues...),
+		RejectedWebsocketConnections: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rejected_websocket_connections",
+			Help:      "Number of websocket connections rejected over the max number of them, in total or per IP.",
+		}, labels).With(labelsAndValues...),
+		EvictedWebsocketConnections: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "evicted_websocket_connections",
+			Help:      "Number of websocket connections evicted for new ones over the max number of them.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		WebsocketConnections:         discard.NewGauge(),
+		RejectedWebsocketConnections: discard.NewCounter(),
+		EvictedWebsocketConnections:  discard.NewCounter(),
+	}
+}
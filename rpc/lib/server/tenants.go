@@ -0,0 +1,188 @@
+package rpcserver
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	types "github.com/tendermint/tendermint/rpc/lib/types"
+)
+
+// Tenant is a namespace of a TenantsHandler, e.g. the RPC of one of the nodes
+// fronted by a provider. Its requests are selected by their host and their URL
+// path prefix, authenticated, rate limited, and passed on to its handler.
+type Tenant struct {
+	Name string
+
+	// Host matched against the Host header, without the port. Any if empty.
+	Host string
+	// URL path prefix, e.g. "/chain-a", stripped from the paths passed on to
+	// the handler. None if empty.
+	Prefix string
+
+	Handler http.Handler
+
+	// Tokens accepted in an "Authorization: Bearer <token>" header. The
+	// requests aren't authenticated if empty.
+	AuthTokens []string
+
+	// Max number of requests per second, with bursts of up to RateBurst
+	// requests. Not limited if zero.
+	RateLimit float64
+	RateBurst int
+}
+
+// ValidateBasic performs basic validation of the tenant.
+func (t Tenant) ValidateBasic() error {
+	if t.Handler == nil {
+		return errors.New("no handler")
+	}
+	if t.Prefix != "" && (!strings.HasPrefix(t.Prefix, "/") || strings.HasSuffix(t.Prefix, "/")) {
+		return fmt.Errorf("prefix %q must start with a slash and not end with one", t.Prefix)
+	}
+	if t.RateLimit < 0 {
+		return errors.New("negative rate limit")
+	}
+	if t.RateLimit > 0 && t.RateBurst < 1 {
+		return errors.New("rate burst must be positive with a rate limit")
+	}
+	return nil
+}
+
+// matches returns the path of the request for the tenant, if it's one of its
+// requests.
+func (t Tenant) matches(r *http.Request) (path string, ok bool) {
+	if t.Host != "" {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !strings.EqualFold(host, t.Host) {
+			return "", false
+		}
+	}
+	if t.Prefix == "" {
+		return r.URL.Path, true
+	}
+	if r.URL.Path == t.Prefix {
+		return "/", true
+	}
+	if strings.HasPrefix(r.URL.Path, t.Prefix+"/") {
+		return strings.TrimPrefix(r.URL.Path, t.Prefix), true
+	}
+	return "", false
+}
+
+func (t Tenant) authorized(r *http.Request) bool {
+	if len(t.AuthTokens) == 0 {
+		return true
+	}
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	token := []byte(strings.TrimPrefix(auth, "Bearer "))
+	for _, authToken := range t.AuthTokens {
+		if subtle.ConstantTimeCompare(token, []byte(authToken)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+type tenantRoute struct {
+	Tenant
+	limiter *rateLimiter // nil if not limited
+}
+
+// NewTenantsHandler returns a handler routing the requests to the first of the
+// tenants matching their host and path prefix, so that one RPC server can
+// front several nodes. The requests matching none, missing a valid token or
+// over the rate limit of their tenant are rejected with an HTTP 404, 401 or 429
+// error.
+func NewTenantsHandler(tenants []Tenant) (http.Handler, error) {
+	routes := make([]tenantRoute, len(tenants))
+	for i, t := range tenants {
+		if err := t.ValidateBasic(); err != nil {
+			return nil, fmt.Errorf("invalid tenant %q: %v", t.Name, err)
+		}
+		routes[i] = tenantRoute{Tenant: t}
+		if t.RateLimit > 0 {
+			routes[i].limiter = newRateLimiter(t.RateLimit, t.RateBurst)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, route := range routes {
+			path, ok := route.matches(r)
+			if !ok {
+				continue
+			}
+			if !route.authorized(r) {
+				WriteRPCResponseHTTPError(w, http.StatusUnauthorized,
+					types.RPCInvalidRequestError(types.JSONRPCStringID(""), errors.New("missing or invalid auth token")))
+				return
+			}
+			if route.limiter != nil && !route.limiter.allow(time.Now()) {
+				WriteRPCResponseHTTPError(w, http.StatusTooManyRequests,
+					types.RPCInternalError(types.JSONRPCStringID(""), errors.New("rate limit exceeded, retry later")))
+				return
+			}
+			if path != r.URL.Path {
+				// the endpoint logged, and its slow threshold, are the ones
+				// without the prefix
+				setRequestEndpoint(r, strings.TrimPrefix(path, "/"))
+				r2 := new(http.Request)
+				*r2 = *r
+				r2.URL = new(url.URL)
+				*r2.URL = *r.URL
+				r2.URL.Path = path
+				r = r2
+			}
+			route.Handler.ServeHTTP(w, r)
+			return
+		}
+		WriteRPCResponseHTTPError(w, http.StatusNotFound,
+			types.RPCInvalidRequestError(types.JSONRPCStringID(""), errors.New("unknown tenant")))
+	}), nil
+}
+
+// rateLimiter is a token bucket.
+type rateLimiter struct {
+	mtx    sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// allow takes a token, if there is one at now.
+func (rl *rateLimiter) allow(now time.Time) bool {
+	rl.mtx.Lock()
+	defer rl.mtx.Unlock()
+	if !rl.last.IsZero() && now.After(rl.last) {
+		rl.tokens += now.Sub(rl.last).Seconds() * rl.rate
+		if rl.tokens > rl.burst {
+			rl.tokens = rl.burst
+		}
+	}
+	if now.After(rl.last) {
+		rl.last = now
+	}
+	if rl.tokens < 1 {
+		return false
+	}
+	rl.tokens--
+	return true
+}
@@ -0,0 +1,79 @@
+package rpcserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantsHandler(t *testing.T) {
+	nodeHandler := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(name + " " + r.URL.Path))
+		})
+	}
+	handler, err := NewTenantsHandler([]Tenant{
+		{Name: "a", Prefix: "/chain-a", Handler: nodeHandler("a"), AuthTokens: []string{"secret"}},
+		{Name: "b", Host: "b.example.com", Handler: nodeHandler("b"), RateLimit: 1, RateBurst: 2},
+	})
+	require.NoError(t, err)
+
+	serve := func(host, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = host
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// routed by prefix, which is stripped
+	rec := serve("example.com", "/chain-a/status", "secret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a /status", rec.Body.String())
+	rec = serve("example.com", "/chain-a", "secret")
+	assert.Equal(t, "a /", rec.Body.String())
+	assert.Equal(t, http.StatusUnauthorized, serve("example.com", "/chain-a/status", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("example.com", "/chain-a/status", "wrong").Code)
+	assert.Equal(t, http.StatusNotFound, serve("example.com", "/chain-ab/status", "secret").Code)
+
+	// routed by host, and rate limited
+	rec = serve("b.example.com:26657", "/status", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "b /status", rec.Body.String())
+	assert.Equal(t, http.StatusOK, serve("b.example.com", "/status", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve("b.example.com", "/status", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve("c.example.com", "/status", "").Code)
+
+	// invalid tenants
+	_, err = NewTenantsHandler([]Tenant{{Name: "a", Prefix: "chain-a", Handler: nodeHandler("a")}})
+	assert.Error(t, err)
+	_, err = NewTenantsHandler([]Tenant{{Name: "a", Prefix: "/chain-a/", Handler: nodeHandler("a")}})
+	assert.Error(t, err)
+	_, err = NewTenantsHandler([]Tenant{{Name: "a"}})
+	assert.Error(t, err)
+	_, err = NewTenantsHandler([]Tenant{{Name: "a", Handler: nodeHandler("a"), RateLimit: 1}})
+	assert.Error(t, err)
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Now()
+	rl := newRateLimiter(2, 2)
+	assert.True(t, rl.allow(now))
+	assert.True(t, rl.allow(now))
+	assert.False(t, rl.allow(now))
+
+	// a token every half second, up to the burst
+	assert.False(t, rl.allow(now.Add(400*time.Millisecond)))
+	assert.True(t, rl.allow(now.Add(500*time.Millisecond)))
+	assert.False(t, rl.allow(now.Add(500*time.Millisecond)))
+	assert.True(t, rl.allow(now.Add(10*time.Second)))
+	assert.True(t, rl.allow(now.Add(10*time.Second)))
+	assert.False(t, rl.allow(now.Add(10*time.Second)))
+}
@@ -0,0 +1,36 @@
+package rpctypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{`{"b": 1, "a": [true, false, null]}`, `{"a":[true,false,null],"b":1}`},
+		{`{"z": {"y": "1", "x": "2"}}`, `{"z":{"x":"2","y":"1"}}`},
+		// numbers
+		{`[1.0, -0, 1e2, 0.000001, 1e-7, 1e21, 123456789012345680000, 4.50]`,
+			`[1,0,100,0.000001,1e-7,1e+21,123456789012345680000,4.5]`},
+		// strings only have the mandatory escapes
+		{`"<a & b>é \n\u001f\/"`, "\"<a & b>é \\n\\u001f/\""},
+		// members are sorted by UTF-16 code units
+		{`{"\ufb33": 1, "\ud83d\ude00": 2}`, "{\"\U0001F600\":2,\"\uFB33\":1}"},
+	}
+
+	for _, tc := range testCases {
+		bz, err := CanonicalJSON([]byte(tc.in))
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, string(bz), tc.in)
+	}
+
+	for _, in := range []string{``, `{"a": }`, `{} {}`} {
+		_, err := CanonicalJSON([]byte(in))
+		assert.Error(t, err, in)
+	}
+}
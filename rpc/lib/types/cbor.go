@@ -0,0 +1,145 @@
+package rpctypes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// CBOR major types (RFC 7049).
+const (
+	cborUint   = 0 << 5
+	cborNegInt = 1 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+	cborSimple = 7 << 5
+
+	cborFalse   = cborSimple | 20
+	cborTrue    = cborSimple | 21
+	cborNull    = cborSimple | 22
+	cborFloat64 = cborSimple | 27
+)
+
+// JSONToCBOR encodes the given JSON value in CBOR (RFC 7049), following the
+// canonical CBOR rules (shortest integer encodings, map keys sorted by length
+// then bytewise), so that the same value always has the same bytes.
+//
+// Integers are encoded as CBOR integers and other numbers as 64-bit floats.
+// Since amino encodes 64-bit integers and byte slices as strings in JSON,
+// they are encoded as text strings.
+func JSONToCBOR(bz []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+
+	var buf bytes.Buffer
+	if err := writeCBOR(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeCBOR(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteByte(cborNull)
+	case bool:
+		if v {
+			buf.WriteByte(cborTrue)
+		} else {
+			buf.WriteByte(cborFalse)
+		}
+	case json.Number:
+		return writeCBORNumber(buf, v)
+	case string:
+		writeCBORHead(buf, cborText, uint64(len(v)))
+		buf.WriteString(v)
+	case []interface{}:
+		writeCBORHead(buf, cborArray, uint64(len(v)))
+		for _, elem := range v {
+			if err := writeCBOR(buf, elem); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		// The encoded keys all have the same head for a given length, so
+		// sorting the keys by length then bytewise sorts the encoded keys.
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+
+		writeCBORHead(buf, cborMap, uint64(len(v)))
+		for _, k := range keys {
+			writeCBORHead(buf, cborText, uint64(len(k)))
+			buf.WriteString(k)
+			if err := writeCBOR(buf, v[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unexpected JSON value %T", v)
+	}
+	return nil
+}
+
+func writeCBORNumber(buf *bytes.Buffer, n json.Number) error {
+	if i, err := n.Int64(); err == nil {
+		if i >= 0 {
+			writeCBORHead(buf, cborUint, uint64(i))
+		} else {
+			writeCBORHead(buf, cborNegInt, uint64(-(i + 1)))
+		}
+		return nil
+	}
+
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+	var b [9]byte
+	b[0] = cborFloat64
+	binary.BigEndian.PutUint64(b[1:], math.Float64bits(f))
+	buf.Write(b[:])
+	return nil
+}
+
+// writeCBORHead writes the initial byte(s) of an item of the given major type,
+// with n in its shortest form.
+func writeCBORHead(buf *bytes.Buffer, majorType byte, n uint64) {
+	var b [9]byte
+	switch {
+	case n < 24:
+		buf.WriteByte(majorType | byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{majorType | 24, byte(n)})
+	case n <= math.MaxUint16:
+		b[0] = majorType | 25
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		buf.Write(b[:3])
+	case n <= math.MaxUint32:
+		b[0] = majorType | 26
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		buf.Write(b[:5])
+	default:
+		b[0] = majorType | 27
+		binary.BigEndian.PutUint64(b[1:], n)
+		buf.Write(b[:9])
+	}
+}
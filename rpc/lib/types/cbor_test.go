@@ -0,0 +1,49 @@
+package rpctypes
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONToCBOR(t *testing.T) {
+	// Examples from RFC 7049, Appendix A.
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{`0`, "00"},
+		{`23`, "17"},
+		{`24`, "1818"},
+		{`1000`, "1903e8"},
+		{`1000000`, "1a000f4240"},
+		{`1000000000000`, "1b000000e8d4a51000"},
+		{`-1`, "20"},
+		{`-1000`, "3903e7"},
+		{`1.5`, "fb3ff8000000000000"},
+		{`false`, "f4"},
+		{`true`, "f5"},
+		{`null`, "f6"},
+		{`""`, "60"},
+		{`"IETF"`, "6449455446"},
+		{`[]`, "80"},
+		{`[1, [2, 3], [4, 5]]`, "8301820203820405"},
+		{`{}`, "a0"},
+		{`{"a": 1, "b": [2, 3]}`, "a26161016162820203"},
+		// keys are sorted by length, then bytewise
+		{`{"bb": 1, "c": 2, "a": 3}`, "a361610361630262626201"},
+	}
+
+	for _, tc := range testCases {
+		bz, err := JSONToCBOR([]byte(tc.in))
+		require.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, hex.EncodeToString(bz), tc.in)
+	}
+
+	for _, in := range []string{``, `{"a": }`, `{} {}`} {
+		_, err := JSONToCBOR([]byte(in))
+		assert.Error(t, err, in)
+	}
+}
@@ -209,6 +209,15 @@ func (resp RPCResponse) String() string {
 	return fmt.Sprintf("[%s %s]", resp.ID, resp.Error)
 }
 
+// Codes of the errors returned when a client exceeds the limits of the
+// server, in the range reserved for implementation-defined server errors.
+const (
+	CodeTooManyConnections   = -32001
+	CodeTooManySubscriptions = -32002
+	CodeQueryTooComplex      = -32003
+	CodeTooManyUnackedEvents = -32004
+)
+
 func RPCParseError(id jsonrpcid, err error) RPCResponse {
 	return NewRPCErrorResponse(id, -32700, "Parse error. Invalid JSON", err.Error())
 }
@@ -233,6 +242,15 @@ func RPCServerError(id jsonrpcid, err error) RPCResponse {
 	return NewRPCErrorResponse(id, -32000, "Server error", err.Error())
 }
 
+// RPCFuncError returns the response for an error returned by an RPC function:
+// the error itself if it is an *RPCError, or an internal error otherwise.
+func RPCFuncError(id jsonrpcid, err error) RPCResponse {
+	if rpcErr, ok := err.(*RPCError); ok {
+		return NewRPCErrorResponse(id, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+	}
+	return RPCInternalError(id, err)
+}
+
 //----------------------------------------
 
 // *wsConnection implements this interface.
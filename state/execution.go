@@ -5,11 +5,13 @@ import (
 	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
 	dbm "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/fail"
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
 )
 
 //-----------------------------------------------------------------------------
@@ -36,6 +38,9 @@ type BlockExecutor struct {
 	logger log.Logger
 
 	metrics *Metrics
+
+	// panic at the first inconsistent state, see CheckApplyInvariants
+	checkInvariants bool
 }
 
 type BlockExecutorOption func(executor *BlockExecutor)
@@ -46,6 +51,15 @@ func BlockExecutorWithMetrics(metrics *Metrics) BlockExecutorOption {
 	}
 }
 
+// BlockExecutorWithInvariantChecks makes ApplyBlock panic with an
+// ErrInvariantViolation at the first inconsistent state, see
+// CheckApplyInvariants.
+func BlockExecutorWithInvariantChecks() BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.checkInvariants = true
+	}
+}
+
 // NewBlockExecutor returns a new BlockExecutor with a NopEventBus.
 // Call SetEventBus to provide one.
 func NewBlockExecutor(db dbm.DB, logger log.Logger, proxyApp proxy.AppConnConsensus, mempool Mempool, evpool EvidencePool, options ...BlockExecutorOption) *BlockExecutor {
@@ -114,6 +128,7 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 	if err := blockExec.ValidateBlock(state, block); err != nil {
 		return state, ErrInvalidBlock(err)
 	}
+	prevState := state
 
 	startTime := time.Now().UnixNano()
 	abciResponses, err := execBlockOnProxyApp(blockExec.logger, blockExec.proxyApp, block, state.LastValidators, blockExec.db)
@@ -126,7 +141,7 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 	fail.Fail() // XXX
 
 	// Save the results before we commit.
-	saveABCIResponses(blockExec.db, block.Height, abciResponses)
+	SaveABCIResponses(blockExec.db, block.Height, abciResponses)
 
 	fail.Fail() // XXX
 
@@ -163,7 +178,16 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 
 	// Update the app hash and save the state.
 	state.AppHash = appHash
+	startTime = time.Now().UnixNano()
 	SaveState(blockExec.db, state)
+	endTime = time.Now().UnixNano()
+	blockExec.metrics.StateCommitTime.Observe(float64(endTime-startTime) / 1000000)
+
+	if blockExec.checkInvariants {
+		if err := CheckApplyInvariants(blockExec.db, prevState, blockID, block, state); err != nil {
+			panic(err)
+		}
+	}
 
 	fail.Fail() // XXX
 
@@ -255,6 +279,7 @@ func execBlockOnProxyApp(
 				logger.Debug("Invalid tx", "code", txRes.Code, "log", txRes.Log)
 				invalidTxs++
 			}
+			txRes.Events, txRes.Tags = eventsWithTags(txRes.Events, txRes.Tags), nil
 			abciResponses.DeliverTx[txIndex] = txRes
 			txIndex++
 		}
@@ -275,6 +300,8 @@ func execBlockOnProxyApp(
 		logger.Error("Error in proxyAppConn.BeginBlock", "err", err)
 		return nil, err
 	}
+	beginBlock := abciResponses.BeginBlock
+	beginBlock.Events, beginBlock.Tags = eventsWithTags(beginBlock.Events, beginBlock.Tags), nil
 
 	// Run txs of block.
 	for _, tx := range block.Txs {
@@ -290,12 +317,24 @@ func execBlockOnProxyApp(
 		logger.Error("Error in proxyAppConn.EndBlock", "err", err)
 		return nil, err
 	}
+	endBlock := abciResponses.EndBlock
+	endBlock.Events, endBlock.Tags = eventsWithTags(endBlock.Events, endBlock.Tags), nil
 
 	logger.Info("Executed block", "height", block.Height, "validTxs", validTxs, "invalidTxs", invalidTxs)
 
 	return abciResponses, nil
 }
 
+// eventsWithTags returns the events of a response, with the tags of an
+// application of a previous version of ABCI converted to events (see
+// abci.EventsFromTags), so that they are indexed and published as before.
+func eventsWithTags(events []abci.Event, tags []cmn.KVPair) []abci.Event {
+	if len(tags) == 0 {
+		return events
+	}
+	return append(events, abci.EventsFromTags(tags)...)
+}
+
 func getBeginBlockValidatorInfo(block *types.Block, lastValSet *types.ValidatorSet, stateDB dbm.DB) (abci.LastCommitInfo, []abci.Evidence) {
 
 	// Sanity check that commit length matches validator set size -
@@ -356,6 +395,17 @@ func validateValidatorUpdates(abciUpdates []abci.ValidatorUpdate,
 			continue
 		}
 
+		// Check the voting power is within the limits, so that the total voting
+		// power can't overflow. The total itself is checked when updating the set.
+		if params.MaxPower > 0 && valUpdate.GetPower() > params.MaxPower {
+			return fmt.Errorf("Validator %v has voting power %d, greater than the max %d of the consensus params",
+				valUpdate, valUpdate.GetPower(), params.MaxPower)
+		}
+		if valUpdate.GetPower() > types.MaxTotalVotingPower {
+			return fmt.Errorf("Validator %v has voting power %d, greater than the max total voting power %d",
+				valUpdate, valUpdate.GetPower(), types.MaxTotalVotingPower)
+		}
+
 		// Check if validator's pubkey matches an ABCI type in the consensus params
 		thisKeyType := valUpdate.PubKey.Type
 		if !params.IsValidPubkeyType(thisKeyType) {
@@ -382,6 +432,11 @@ func updateState(
 	// Update the validator set with the latest abciResponses.
 	lastHeightValsChanged := state.LastHeightValidatorsChanged
 	if len(validatorUpdates) > 0 {
+		// The validators joining the set are skipped as proposer for a while;
+		// the others keep their grace.
+		for _, valUpdate := range validatorUpdates {
+			valUpdate.ProposerGrace = state.ConsensusParams.Validator.ProposerGraceRounds
+		}
 		err := nValSet.UpdateWithChangeSet(validatorUpdates)
 		if err != nil {
 			return state, fmt.Errorf("Error changing validator set: %v", err)
@@ -407,8 +462,12 @@ func updateState(
 		lastHeightParamsChanged = header.Height + 1
 	}
 
-	// TODO: allow app to upgrade version
+	// Update the app version with the latest abciResponses.
+	// Change results from this height but only applies to the next height.
 	nextVersion := state.Version
+	if abciResponses.EndBlock.AppVersion != 0 {
+		nextVersion.Consensus.App = version.Protocol(abciResponses.EndBlock.AppVersion)
+	}
 
 	// NOTE: the AppHash has not been populated.
 	// It will be filled on state.Save.
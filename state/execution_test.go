@@ -3,6 +3,7 @@ package state
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -159,7 +160,7 @@ func TestValidateValidatorUpdates(t *testing.T) {
 
 	secpKey := secp256k1.GenPrivKey().PubKey()
 
-	defaultValidatorParams := types.ValidatorParams{[]string{types.ABCIPubKeyTypeEd25519}}
+	defaultValidatorParams := types.ValidatorParams{PubKeyTypes: []string{types.ABCIPubKeyTypeEd25519}}
 
 	testCases := []struct {
 		name string
@@ -207,6 +208,30 @@ func TestValidateValidatorUpdates(t *testing.T) {
 			[]abci.ValidatorUpdate{{PubKey: types.TM2PB.PubKey(secpKey), Power: -100}},
 			defaultValidatorParams,
 
+			true,
+		},
+		{
+			"adding a validator with more than the max power results in error",
+
+			[]abci.ValidatorUpdate{{PubKey: types.TM2PB.PubKey(pubkey2), Power: 101}},
+			types.ValidatorParams{PubKeyTypes: []string{types.ABCIPubKeyTypeEd25519}, MaxPower: 100},
+
+			true,
+		},
+		{
+			"adding a validator with the max power is OK",
+
+			[]abci.ValidatorUpdate{{PubKey: types.TM2PB.PubKey(pubkey2), Power: 100}},
+			types.ValidatorParams{PubKeyTypes: []string{types.ABCIPubKeyTypeEd25519}, MaxPower: 100},
+
+			false,
+		},
+		{
+			"adding a validator with more than the max total power results in error",
+
+			[]abci.ValidatorUpdate{{PubKey: types.TM2PB.PubKey(pubkey2), Power: math.MaxInt64}},
+			defaultValidatorParams,
+
 			true,
 		},
 	}
@@ -381,6 +406,58 @@ func TestEndBlockValidatorUpdatesResultingInEmptySet(t *testing.T) {
 
 }
 
+// TestEndBlockAppVersion ensures the app version set at EndBlock is the one of
+// the next block.
+func TestEndBlockAppVersion(t *testing.T) {
+	app := &testApp{}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop()
+
+	state, stateDB := state(1, 1)
+	blockExec := NewBlockExecutor(stateDB, log.TestingLogger(), proxyApp.Consensus(), MockMempool{}, MockEvidencePool{})
+
+	block := makeBlock(state, 1)
+	blockID := types.BlockID{block.Hash(), block.MakePartSet(testPartSize).Header()}
+
+	app.AppVersion = 2
+	state, err = blockExec.ApplyBlock(state, blockID, block)
+	require.Nil(t, err)
+	assert.EqualValues(t, 0, block.Version.App)
+	assert.EqualValues(t, 2, state.Version.Consensus.App)
+
+	block = makeBlock(state, 2)
+	assert.EqualValues(t, 2, block.Version.App)
+}
+
+// TestExecBlockTags ensures the tags of an application of a previous version of
+// ABCI are converted to events.
+func TestExecBlockTags(t *testing.T) {
+	app := &testApp{Tags: []cmn.KVPair{{Key: []byte("transfer.sender"), Value: []byte("alice")}}}
+	cc := proxy.NewLocalClientCreator(app)
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop()
+
+	state, stateDB := state(1, 1)
+	block := makeBlock(state, 1)
+
+	abciResponses, err := execBlockOnProxyApp(log.TestingLogger(), proxyApp.Consensus(), block, state.Validators, stateDB)
+	require.Nil(t, err)
+	events := []abci.Event{
+		{Type: "transfer", Attributes: []cmn.KVPair{{Key: []byte("sender"), Value: []byte("alice")}}},
+	}
+	for _, txRes := range abciResponses.DeliverTx {
+		assert.Equal(t, events, txRes.Events)
+		assert.Empty(t, txRes.Tags)
+	}
+	assert.Equal(t, events, abciResponses.EndBlock.Events)
+	assert.Empty(t, abciResponses.EndBlock.Tags)
+}
+
 //----------------------------------------------------------------------------
 
 // make some bogus txs
@@ -434,6 +511,8 @@ type testApp struct {
 	CommitVotes         []abci.VoteInfo
 	ByzantineValidators []abci.Evidence
 	ValidatorUpdates    []abci.ValidatorUpdate
+	AppVersion          uint64
+	Tags                []cmn.KVPair // of DeliverTx and EndBlock
 }
 
 var _ abci.Application = (*testApp)(nil)
@@ -449,11 +528,11 @@ func (app *testApp) BeginBlock(req abci.RequestBeginBlock) abci.ResponseBeginBlo
 }
 
 func (app *testApp) EndBlock(req abci.RequestEndBlock) abci.ResponseEndBlock {
-	return abci.ResponseEndBlock{ValidatorUpdates: app.ValidatorUpdates}
+	return abci.ResponseEndBlock{ValidatorUpdates: app.ValidatorUpdates, AppVersion: app.AppVersion, Tags: app.Tags}
 }
 
 func (app *testApp) DeliverTx(tx []byte) abci.ResponseDeliverTx {
-	return abci.ResponseDeliverTx{Tags: []cmn.KVPair{}}
+	return abci.ResponseDeliverTx{Events: []abci.Event{}, Tags: app.Tags}
 }
 
 func (app *testApp) CheckTx(tx []byte) abci.ResponseCheckTx {
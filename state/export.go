@@ -0,0 +1,90 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+)
+
+// Export is the state of tendermint after the block of a height, as written
+// by tendermint export-state. Along with an export of the state of the app at
+// the same height, it is enough to start a new chain from it.
+type Export struct {
+	ChainID         string              `json:"chain_id"`
+	Height          int64               `json:"height"`
+	BlockID         types.BlockID       `json:"block_id"`
+	BlockTime       time.Time           `json:"block_time"`
+	AppVersion      version.Protocol    `json:"app_version"`
+	AppHash         cmn.HexBytes        `json:"app_hash"`
+	LastResultsHash cmn.HexBytes        `json:"last_results_hash"`
+	Validators      *types.ValidatorSet `json:"validators"`      // of the next block
+	NextValidators  *types.ValidatorSet `json:"next_validators"` // of the block after it
+	// of the next block
+	ConsensusParams types.ConsensusParams `json:"consensus_params"`
+}
+
+// ExportState exports the state after the block of the height, 0 for the last
+// block. The block after it, if any, must be in the block store, as its header
+// holds the app hash and the results hash of the height.
+func ExportState(db dbm.DB, blockStore BlockStoreRPC, height int64) (*Export, error) {
+	state := LoadState(db)
+	if height == 0 {
+		height = state.LastBlockHeight
+	}
+	if height < 1 || height > state.LastBlockHeight {
+		return nil, fmt.Errorf("height %d is not between 1 and the last height %d", height, state.LastBlockHeight)
+	}
+
+	meta := blockStore.LoadBlockMeta(height)
+	if meta == nil {
+		return nil, ErrUnknownBlock{height}
+	}
+	e := &Export{
+		ChainID:         state.ChainID,
+		Height:          height,
+		BlockID:         meta.BlockID,
+		BlockTime:       meta.Header.Time,
+		AppVersion:      meta.Header.Version.App,
+		AppHash:         state.AppHash,
+		LastResultsHash: state.LastResultsHash,
+	}
+	if height < state.LastBlockHeight {
+		next := blockStore.LoadBlockMeta(height + 1)
+		if next == nil {
+			return nil, ErrUnknownBlock{height + 1}
+		}
+		e.AppHash = next.Header.AppHash
+		e.LastResultsHash = next.Header.LastResultsHash
+	}
+
+	var err error
+	if e.Validators, err = LoadValidators(db, height+1); err != nil {
+		return nil, err
+	}
+	if e.NextValidators, err = LoadValidators(db, height+2); err != nil {
+		return nil, err
+	}
+	if e.ConsensusParams, err = LoadConsensusParams(db, height+1); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ValidateBasic performs basic validation of an imported export.
+func (e *Export) ValidateBasic() error {
+	if e.ChainID == "" {
+		return errors.New("empty chain ID")
+	}
+	if e.Height < 1 {
+		return fmt.Errorf("invalid height %d", e.Height)
+	}
+	if e.Validators.IsNilOrEmpty() {
+		return errors.New("no validators")
+	}
+	return e.ConsensusParams.Validate()
+}
@@ -0,0 +1,58 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// exportBlockStore is a block store of the block metas of the heights.
+type exportBlockStore struct {
+	BlockStoreRPC
+	metas map[int64]*types.BlockMeta
+}
+
+func (bs exportBlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
+	return bs.metas[height]
+}
+
+func TestExportState(t *testing.T) {
+	state, stateDB := state(2, 3)
+	state.AppHash = []byte("last app hash")
+	state.LastResultsHash = []byte("last results hash")
+	SaveState(stateDB, state)
+
+	blockStore := exportBlockStore{metas: make(map[int64]*types.BlockMeta)}
+	for h := int64(1); h <= state.LastBlockHeight; h++ {
+		blockStore.metas[h] = &types.BlockMeta{Header: types.Header{
+			Height:          h,
+			AppHash:         []byte{byte(h)},
+			LastResultsHash: []byte{byte(h), byte(h)},
+		}}
+	}
+
+	// the hashes of the last height are the ones of the state
+	e, err := ExportState(stateDB, blockStore, 0)
+	require.NoError(t, err)
+	assert.Equal(t, state.LastBlockHeight, e.Height)
+	assert.EqualValues(t, state.AppHash, e.AppHash)
+	assert.EqualValues(t, state.LastResultsHash, e.LastResultsHash)
+	assert.Equal(t, state.NextValidators.Hash(), e.Validators.Hash())
+	assert.Equal(t, state.ConsensusParams, e.ConsensusParams)
+	require.NoError(t, e.ValidateBasic())
+
+	// and the ones of the other heights are in the header of the next block
+	e, err = ExportState(stateDB, blockStore, 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, []byte{2}, e.AppHash)
+	assert.EqualValues(t, []byte{2, 2}, e.LastResultsHash)
+
+	delete(blockStore.metas, 2)
+	_, err = ExportState(stateDB, blockStore, 1)
+	assert.Error(t, err)
+	_, err = ExportState(stateDB, blockStore, state.LastBlockHeight+1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/types"
+)
+
+// ErrInvariantViolation is the panic value of a failed invariant check, with
+// the diagnostics of the inconsistency, e.g. "block.AppHash: 0A1B...".
+type ErrInvariantViolation struct {
+	Invariant   string
+	Height      int64
+	Diagnostics []string
+}
+
+func (e ErrInvariantViolation) Error() string {
+	return fmt.Sprintf("Invariant %q violated at height %d:\n  %s",
+		e.Invariant, e.Height, strings.Join(e.Diagnostics, "\n  "))
+}
+
+// CheckApplyInvariants returns an ErrInvariantViolation if next, the state
+// after applying the block of blockID to prev, is inconsistent with prev, the
+// block, or the state saved in db.
+//
+// These checks are redundant with the validation of the block: they catch the
+// bugs corrupting the state at the first block, instead of the blocks failing
+// to validate later on.
+func CheckApplyInvariants(db dbm.DB, prev State, blockID types.BlockID, block *types.Block, next State) error {
+	height := block.Height
+	violation := func(invariant string, diagnostics ...string) error {
+		return ErrInvariantViolation{Invariant: invariant, Height: height, Diagnostics: diagnostics}
+	}
+
+	// height continuity
+	if prev.LastBlockHeight != 0 && prev.LastBlockHeight+1 != height ||
+		next.LastBlockHeight != height || !next.LastBlockID.Equals(blockID) {
+		return violation("height continuity",
+			fmt.Sprintf("prev.LastBlockHeight: %d", prev.LastBlockHeight),
+			fmt.Sprintf("block.Height: %d", height),
+			fmt.Sprintf("next.LastBlockHeight: %d", next.LastBlockHeight),
+			fmt.Sprintf("blockID: %v", blockID),
+			fmt.Sprintf("next.LastBlockID: %v", next.LastBlockID))
+	}
+
+	// validator set hash vs header
+	if !bytes.Equal(block.ValidatorsHash, prev.Validators.Hash()) ||
+		!bytes.Equal(block.ValidatorsHash, next.LastValidators.Hash()) ||
+		!bytes.Equal(block.NextValidatorsHash, prev.NextValidators.Hash()) ||
+		!bytes.Equal(block.NextValidatorsHash, next.Validators.Hash()) {
+		return violation("validator set hash",
+			fmt.Sprintf("block.ValidatorsHash: %X", block.ValidatorsHash),
+			fmt.Sprintf("prev.Validators.Hash(): %X", prev.Validators.Hash()),
+			fmt.Sprintf("next.LastValidators.Hash(): %X", next.LastValidators.Hash()),
+			fmt.Sprintf("block.NextValidatorsHash: %X", block.NextValidatorsHash),
+			fmt.Sprintf("prev.NextValidators.Hash(): %X", prev.NextValidators.Hash()),
+			fmt.Sprintf("next.Validators.Hash(): %X", next.Validators.Hash()))
+	}
+	vals, err := LoadValidators(db, height+1)
+	if err != nil || !bytes.Equal(vals.Hash(), next.Validators.Hash()) {
+		diagnostics := []string{fmt.Sprintf("next.Validators.Hash(): %X", next.Validators.Hash())}
+		if err != nil {
+			diagnostics = append(diagnostics, fmt.Sprintf("saved validators of height %d: %v", height+1, err))
+		} else {
+			diagnostics = append(diagnostics, fmt.Sprintf("saved validators of height %d hash: %X", height+1, vals.Hash()))
+		}
+		return violation("saved validator set", diagnostics...)
+	}
+
+	// app hash continuity
+	if !bytes.Equal(block.AppHash, prev.AppHash) {
+		return violation("app hash continuity",
+			fmt.Sprintf("block.AppHash: %X", block.AppHash),
+			fmt.Sprintf("prev.AppHash: %X", prev.AppHash))
+	}
+	if !bytes.Equal(block.LastResultsHash, prev.LastResultsHash) {
+		return violation("results hash continuity",
+			fmt.Sprintf("block.LastResultsHash: %X", block.LastResultsHash),
+			fmt.Sprintf("prev.LastResultsHash: %X", prev.LastResultsHash))
+	}
+	if !bytes.Equal(block.ConsensusHash, prev.ConsensusParams.Hash()) {
+		return violation("consensus params hash",
+			fmt.Sprintf("block.ConsensusHash: %X", block.ConsensusHash),
+			fmt.Sprintf("prev.ConsensusParams.Hash(): %X", prev.ConsensusParams.Hash()))
+	}
+
+	// saved state
+	if saved := LoadState(db); !saved.Equals(next) {
+		return violation("saved state",
+			fmt.Sprintf("next.LastBlockHeight: %d", next.LastBlockHeight),
+			fmt.Sprintf("saved.LastBlockHeight: %d", saved.LastBlockHeight),
+			fmt.Sprintf("next.AppHash: %X", next.AppHash),
+			fmt.Sprintf("saved.AppHash: %X", saved.AppHash),
+			fmt.Sprintf("next.LastResultsHash: %X", next.LastResultsHash),
+			fmt.Sprintf("saved.LastResultsHash: %X", saved.LastResultsHash))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/proxy"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestCheckApplyInvariants(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(kvstore.NewKVStoreApplication())
+	proxyApp := proxy.NewAppConns(cc)
+	err := proxyApp.Start()
+	require.Nil(t, err)
+	defer proxyApp.Stop()
+
+	prev, stateDB := state(1, 1)
+	blockExec := NewBlockExecutor(stateDB, log.TestingLogger(), proxyApp.Consensus(),
+		MockMempool{}, MockEvidencePool{}, BlockExecutorWithInvariantChecks())
+
+	block := makeBlock(prev, 1)
+	blockID := types.BlockID{block.Hash(), block.MakePartSet(testPartSize).Header()}
+	next, err := blockExec.ApplyBlock(prev, blockID, block)
+	require.Nil(t, err)
+	require.NoError(t, CheckApplyInvariants(stateDB, prev, blockID, block, next))
+
+	invariant := func(err error) string {
+		require.Error(t, err)
+		violation, ok := err.(ErrInvariantViolation)
+		require.True(t, ok, "expected an ErrInvariantViolation, got %v", err)
+		assert.Equal(t, int64(1), violation.Height)
+		assert.NotEmpty(t, violation.Diagnostics)
+		return violation.Invariant
+	}
+
+	badNext := next.Copy()
+	badNext.LastBlockHeight = 2
+	assert.Equal(t, "height continuity", invariant(CheckApplyInvariants(stateDB, prev, blockID, block, badNext)))
+
+	badPrev := prev.Copy()
+	badPrev.AppHash = []byte("other app hash")
+	assert.Equal(t, "app hash continuity", invariant(CheckApplyInvariants(stateDB, badPrev, blockID, block, next)))
+
+	badNext = next.Copy()
+	badNext.AppHash = []byte("unsaved app hash")
+	assert.Equal(t, "saved state", invariant(CheckApplyInvariants(stateDB, prev, blockID, block, badNext)))
+
+	badNext = next.Copy()
+	badNext.Validators = types.NewValidatorSet([]*types.Validator{types.NewValidator(ed25519.GenPrivKey().PubKey(), 10)})
+	assert.Equal(t, "validator set hash", invariant(CheckApplyInvariants(stateDB, prev, blockID, block, badNext)))
+}
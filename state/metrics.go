@@ -17,6 +17,9 @@ const (
 type Metrics struct {
 	// Time between BeginBlock and EndBlock.
 	BlockProcessingTime metrics.Histogram
+
+	// Time to write the state of a block to the state store, in one batch.
+	StateCommitTime metrics.Histogram
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -35,6 +38,13 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Help:      "Time between BeginBlock and EndBlock in ms.",
 			Buckets:   stdprometheus.LinearBuckets(1, 10, 10),
 		}, labels).With(labelsAndValues...),
+		StateCommitTime: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "state_commit_time",
+			Help:      "Time to write the state of a block to the state store in ms.",
+			Buckets:   stdprometheus.ExponentialBuckets(1, 2, 12),
+		}, labels).With(labelsAndValues...),
 	}
 }
 
@@ -42,5 +52,6 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 func NopMetrics() *Metrics {
 	return &Metrics{
 		BlockProcessingTime: discard.NewHistogram(),
+		StateCommitTime:     discard.NewHistogram(),
 	}
 }
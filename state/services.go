@@ -60,6 +60,7 @@ func (MockMempool) EnableTxsAvailable()           {}
 
 // BlockStoreRPC is the block store interface used by the RPC.
 type BlockStoreRPC interface {
+	Base() int64
 	Height() int64
 
 	LoadBlockMeta(height int64) *types.BlockMeta
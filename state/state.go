@@ -81,6 +81,10 @@ type State struct {
 
 	// the latest AppHash we've received from calling abci.Commit()
 	AppHash []byte
+
+	// immutable, the format of the sign bytes of the votes and proposals.
+	// Last, so that the states saved without it still decode.
+	SignDomain types.SignDomain
 }
 
 // Copy makes a copy of the State for mutating.
@@ -105,6 +109,8 @@ func (state State) Copy() State {
 		AppHash: state.AppHash,
 
 		LastResultsHash: state.LastResultsHash,
+
+		SignDomain: state.SignDomain,
 	}
 }
 
@@ -246,5 +252,7 @@ func MakeGenesisState(genDoc *types.GenesisDoc) (State, error) {
 		LastHeightConsensusParamsChanged: 1,
 
 		AppHash: genDoc.AppHash,
+
+		SignDomain: genDoc.GetSignDomain(),
 	}, nil
 }
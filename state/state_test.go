@@ -93,13 +93,13 @@ func TestABCIResponsesSaveLoad1(t *testing.T) {
 	// Build mock responses.
 	block := makeBlock(state, 2)
 	abciResponses := NewABCIResponses(block)
-	abciResponses.DeliverTx[0] = &abci.ResponseDeliverTx{Data: []byte("foo"), Tags: nil}
-	abciResponses.DeliverTx[1] = &abci.ResponseDeliverTx{Data: []byte("bar"), Log: "ok", Tags: nil}
+	abciResponses.DeliverTx[0] = &abci.ResponseDeliverTx{Data: []byte("foo"), Events: nil}
+	abciResponses.DeliverTx[1] = &abci.ResponseDeliverTx{Data: []byte("bar"), Log: "ok", Events: nil}
 	abciResponses.EndBlock = &abci.ResponseEndBlock{ValidatorUpdates: []abci.ValidatorUpdate{
 		types.TM2PB.NewValidatorUpdate(ed25519.GenPrivKey().PubKey(), 10),
 	}}
 
-	saveABCIResponses(stateDB, block.Height, abciResponses)
+	SaveABCIResponses(stateDB, block.Height, abciResponses)
 	loadedABCIResponses, err := LoadABCIResponses(stateDB, block.Height)
 	assert.Nil(err)
 	assert.Equal(abciResponses, loadedABCIResponses,
@@ -135,9 +135,11 @@ func TestABCIResponsesSaveLoad2(t *testing.T) {
 			[]*abci.ResponseDeliverTx{
 				{Code: 383},
 				{Data: []byte("Gotcha!"),
-					Tags: []cmn.KVPair{
-						{Key: []byte("a"), Value: []byte("1")},
-						{Key: []byte("build"), Value: []byte("stuff")},
+					Events: []abci.Event{
+						{Type: "test", Attributes: []cmn.KVPair{
+							{Key: []byte("a"), Value: []byte("1")},
+							{Key: []byte("build"), Value: []byte("stuff")},
+						}},
 					}},
 			},
 			types.ABCIResults{
@@ -164,7 +166,7 @@ func TestABCIResponsesSaveLoad2(t *testing.T) {
 			DeliverTx: tc.added,
 			EndBlock:  &abci.ResponseEndBlock{},
 		}
-		saveABCIResponses(stateDB, h, responses)
+		SaveABCIResponses(stateDB, h, responses)
 	}
 
 	// Query all before, should return expected value.
@@ -200,7 +202,7 @@ func TestValidatorSimpleSaveLoad(t *testing.T) {
 	// Increment height, save; should be able to load for next & next next height.
 	state.LastBlockHeight++
 	nextHeight := state.LastBlockHeight + 1
-	saveValidatorsInfo(stateDB, nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators)
+	saveValidatorsInfo(stateDB, stateDB, nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators)
 	vp0, err := LoadValidators(stateDB, nextHeight+0)
 	assert.Nil(err, "expected no err")
 	vp1, err := LoadValidators(stateDB, nextHeight+1)
@@ -238,7 +240,7 @@ func TestOneValidatorChangesSaveLoad(t *testing.T) {
 		state, err = updateState(state, blockID, &header, responses, validatorUpdates)
 		require.NoError(t, err)
 		nextHeight := state.LastBlockHeight + 1
-		saveValidatorsInfo(stateDB, nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators)
+		saveValidatorsInfo(stateDB, stateDB, nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators)
 	}
 
 	// On each height change, increment the power by one.
@@ -494,6 +496,50 @@ func TestProposerPriorityDoesNotGetResetToZero(t *testing.T) {
 	assert.Equal(t, wantVal1Prio, updatedVal1.ProposerPriority)
 }
 
+func TestProposerGraceRounds(t *testing.T) {
+	tearDown, _, state := setupTestCase(t)
+	defer tearDown(t)
+	state.ConsensusParams.Validator.ProposerGraceRounds = 2
+	val1PubKey := ed25519.GenPrivKey().PubKey()
+	val1 := &types.Validator{Address: val1PubKey.Address(), PubKey: val1PubKey, VotingPower: 1}
+	state.Validators = types.NewValidatorSet([]*types.Validator{val1})
+	state.NextValidators = state.Validators
+
+	block := makeBlock(state, state.LastBlockHeight+1)
+	blockID := types.BlockID{block.Hash(), block.MakePartSet(testPartSize).Header()}
+	abciResponses := &ABCIResponses{
+		EndBlock: &abci.ResponseEndBlock{ValidatorUpdates: nil},
+	}
+
+	// A validator with most of the power joins the set.
+	val2PubKey := ed25519.GenPrivKey().PubKey()
+	validatorUpdates, err := types.PB2TM.ValidatorUpdates([]abci.ValidatorUpdate{
+		{PubKey: types.TM2PB.PubKey(val2PubKey), Power: 1000},
+	})
+	require.NoError(t, err)
+	state, err = updateState(state, blockID, &block.Header, abciResponses, validatorUpdates)
+	require.NoError(t, err)
+
+	// It is skipped as proposer for its first 2 heights, then selected.
+	var proposers []crypto.Address
+	for i := 0; i < 3; i++ {
+		proposers = append(proposers, state.NextValidators.GetProposer().Address)
+		state, err = updateState(state, blockID, &block.Header, abciResponses, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []crypto.Address{val1.Address, val1.Address, val2PubKey.Address()}, proposers)
+
+	// Updating its power doesn't restore the grace.
+	validatorUpdates, err = types.PB2TM.ValidatorUpdates([]abci.ValidatorUpdate{
+		{PubKey: types.TM2PB.PubKey(val2PubKey), Power: 999},
+	})
+	require.NoError(t, err)
+	state, err = updateState(state, blockID, &block.Header, abciResponses, validatorUpdates)
+	require.NoError(t, err)
+	_, val2 := state.NextValidators.GetByAddress(val2PubKey.Address())
+	assert.EqualValues(t, 0, val2.ProposerGrace)
+}
+
 func TestProposerPriorityProposerAlternates(t *testing.T) {
 	// Regression test that would fail if the inner workings of
 	// IncrementProposerPriority change.
@@ -848,7 +894,7 @@ func TestManyValidatorChangesSaveLoad(t *testing.T) {
 	state, err = updateState(state, blockID, &header, responses, validatorUpdates)
 	require.Nil(t, err)
 	nextHeight := state.LastBlockHeight + 1
-	saveValidatorsInfo(stateDB, nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators)
+	saveValidatorsInfo(stateDB, stateDB, nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators)
 
 	// Load nextheight, it should be the oldpubkey.
 	v0, err := LoadValidators(stateDB, nextHeight)
@@ -871,6 +917,137 @@ func TestManyValidatorChangesSaveLoad(t *testing.T) {
 	}
 }
 
+func TestValidatorSetDiffsSaveLoad(t *testing.T) {
+	tearDown, stateDB, state := setupTestCase(t)
+	defer tearDown(t)
+	state.Validators = genValSet(4)
+	state.NextValidators = state.Validators.CopyIncrementProposerPriority(1)
+	SaveState(stateDB, state)
+
+	// Change the power of a validator at these heights, effective 2 blocks
+	// later.
+	changeHeights := map[int64]bool{2: true, 3: true, 7: true, 12: true}
+	expected := map[int64]*types.ValidatorSet{
+		1: state.Validators.Copy(),
+		2: state.NextValidators.Copy(),
+	}
+	_, val := state.Validators.GetByIndex(0)
+	power := val.VotingPower
+	for height := int64(1); height <= 15; height++ {
+		if changeHeights[height] {
+			power++
+		}
+		header, blockID, responses := makeHeaderPartsResponsesValPowerChange(state, height, power)
+		validatorUpdates, err := types.PB2TM.ValidatorUpdates(responses.EndBlock.ValidatorUpdates)
+		require.NoError(t, err)
+		state, err = updateState(state, blockID, &header, responses, validatorUpdates)
+		require.NoError(t, err)
+		nextHeight := state.LastBlockHeight + 1
+		saveValidatorsInfo(stateDB, stateDB, nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators)
+		expected[nextHeight+1] = state.NextValidators.Copy()
+	}
+
+	// The sets, with their proposer priorities, are rebuilt from the diffs.
+	for height, valSet := range expected {
+		loaded, err := LoadValidators(stateDB, height)
+		require.NoError(t, err, "height %d", height)
+		assert.Equal(t, cdc.MustMarshalBinaryBare(valSet), cdc.MustMarshalBinaryBare(loaded), "height %d", height)
+	}
+	for height := range changeHeights {
+		valInfo := loadValidatorsInfo(stateDB, height+2)
+		assert.Nil(t, valInfo.ValidatorSet, "height %d", height+2)
+		if assert.NotNil(t, valInfo.ValidatorSetDiff, "height %d", height+2) {
+			assert.Len(t, valInfo.ValidatorSetDiff.Changes, 1, "height %d", height+2)
+		}
+	}
+
+	changes, err := LoadValidatorSetChanges(stateDB, 0, 17)
+	require.NoError(t, err)
+	if assert.Len(t, changes, 5) {
+		assert.EqualValues(t, 1, changes[0].Height)
+		assert.Len(t, changes[0].Changes, 4)
+		for i, height := range []int64{4, 5, 9, 14} {
+			assert.Equal(t, height, changes[i+1].Height)
+			if assert.Len(t, changes[i+1].Changes, 1) {
+				assert.Equal(t, val.Address, changes[i+1].Changes[0].Address)
+			}
+		}
+	}
+	changes, err = LoadValidatorSetChanges(stateDB, 5, 12)
+	require.NoError(t, err)
+	if assert.Len(t, changes, 1) {
+		assert.EqualValues(t, 9, changes[0].Height)
+	}
+
+	// The rebuilt sets are checked against the hashes of the diffs.
+	valInfo := loadValidatorsInfo(stateDB, 9)
+	valInfo.ValidatorSetDiff.Hash = []byte("wrong")
+	stateDB.Set(calcValidatorsKey(9), valInfo.Bytes())
+	_, err = LoadValidators(stateDB, 10)
+	assert.Error(t, err)
+}
+
+func TestPruneStates(t *testing.T) {
+	tearDown, stateDB, state := setupTestCase(t)
+	defer tearDown(t)
+	state.Validators = genValSet(4)
+	state.NextValidators = state.Validators.CopyIncrementProposerPriority(1)
+	SaveState(stateDB, state)
+
+	// Change the power of a validator at these heights, effective 2 blocks
+	// later.
+	changeHeights := map[int64]bool{2: true, 3: true, 12: true}
+	expected := make(map[int64]*types.ValidatorSet)
+	_, val := state.Validators.GetByIndex(0)
+	power := val.VotingPower
+	for height := int64(1); height <= 20; height++ {
+		if changeHeights[height] {
+			power++
+		}
+		header, blockID, responses := makeHeaderPartsResponsesValPowerChange(state, height, power)
+		SaveABCIResponses(stateDB, height, responses)
+		validatorUpdates, err := types.PB2TM.ValidatorUpdates(responses.EndBlock.ValidatorUpdates)
+		require.NoError(t, err)
+		state, err = updateState(state, blockID, &header, responses, validatorUpdates)
+		require.NoError(t, err)
+		SaveState(stateDB, state)
+		expected[state.LastBlockHeight+2] = state.NextValidators.Copy()
+	}
+
+	// The validators changed at height 5, as a diff.
+	assert.Error(t, PruneStates(stateDB, 21), "beyond the last height")
+	require.NoError(t, PruneStates(stateDB, 10))
+	for height := int64(1); height < 10; height++ {
+		_, err := LoadValidators(stateDB, height)
+		if height == 5 {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err, "height %d", height)
+		}
+		_, err = LoadABCIResponses(stateDB, height)
+		assert.Error(t, err, "height %d", height)
+	}
+	for height := int64(10); height <= 22; height++ {
+		valSet, err := LoadValidators(stateDB, height)
+		require.NoError(t, err, "height %d", height)
+		assert.Equal(t, cdc.MustMarshalBinaryBare(expected[height]), cdc.MustMarshalBinaryBare(valSet), "height %d", height)
+		_, err = LoadConsensusParams(stateDB, height)
+		assert.NoError(t, err, "height %d", height)
+	}
+
+	// The validators kept by the previous pruning are not in effect anymore.
+	require.NoError(t, PruneStates(stateDB, 16))
+	_, err := LoadValidators(stateDB, 5)
+	assert.Error(t, err)
+	for height := int64(16); height <= 22; height++ {
+		valSet, err := LoadValidators(stateDB, height)
+		require.NoError(t, err, "height %d", height)
+		assert.Equal(t, cdc.MustMarshalBinaryBare(expected[height]), cdc.MustMarshalBinaryBare(valSet), "height %d", height)
+	}
+	_, err = LoadConsensusParams(stateDB, 16)
+	assert.NoError(t, err)
+}
+
 func genValSet(size int) *types.ValidatorSet {
 	vals := make([]*types.Validator, size)
 	for i := 0; i < size; i++ {
@@ -954,6 +1131,23 @@ func TestConsensusParamsChangesSaveLoad(t *testing.T) {
 		assert.Equal(t, testCase.params, p, fmt.Sprintf(`unexpected consensus params at
                 height %d`, testCase.height))
 	}
+
+	// The history has the genesis params then each change, from the height
+	// after the change height.
+	changes, err := LoadConsensusParamsChanges(stateDB, highestHeight)
+	require.NoError(t, err)
+	require.Len(t, changes, N+1)
+	assert.Equal(t, ConsensusParamsChange{1, params[0]}, changes[0])
+	for i, height := range changeHeights {
+		assert.Equal(t, ConsensusParamsChange{height + 1, params[i+1]}, changes[i+1])
+	}
+
+	changes, err = LoadConsensusParamsChanges(stateDB, changeHeights[2])
+	require.NoError(t, err)
+	assert.Len(t, changes, 3)
+
+	_, err = LoadConsensusParamsChanges(stateDB, highestHeight+1)
+	assert.Error(t, err)
 }
 
 func makeParams(blockBytes, blockGas, evidenceAge int64) types.ConsensusParams {
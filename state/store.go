@@ -1,6 +1,7 @@
 package state
 
 import (
+	"bytes"
 	"fmt"
 
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -80,25 +81,27 @@ func loadState(db dbm.DB, key []byte) (state State) {
 }
 
 // SaveState persists the State, the ValidatorsInfo, and the ConsensusParamsInfo to the database.
-// This flushes the writes (e.g. calls SetSync).
+// This flushes the writes (e.g. calls SetSync), in one atomic batch.
 func SaveState(db dbm.DB, state State) {
 	saveState(db, state, stateKey)
 }
 
 func saveState(db dbm.DB, state State, key []byte) {
+	batch := db.NewBatch()
 	nextHeight := state.LastBlockHeight + 1
 	// If first block, save validators for block 1.
 	if nextHeight == 1 {
 		// This extra logic due to Tendermint validator set changes being delayed 1 block.
 		// It may get overwritten due to InitChain validator updates.
 		lastHeightVoteChanged := int64(1)
-		saveValidatorsInfo(db, nextHeight, lastHeightVoteChanged, state.Validators)
+		saveValidatorsInfo(db, batch, nextHeight, lastHeightVoteChanged, state.Validators)
 	}
 	// Save next validators.
-	saveValidatorsInfo(db, nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators)
+	saveValidatorsInfo(db, batch, nextHeight+1, state.LastHeightValidatorsChanged, state.NextValidators)
 	// Save next consensus params.
-	saveConsensusParamsInfo(db, nextHeight, state.LastHeightConsensusParamsChanged, state.ConsensusParams)
-	db.SetSync(key, state.Bytes())
+	saveConsensusParamsInfo(batch, nextHeight, state.LastHeightConsensusParamsChanged, state.ConsensusParams)
+	batch.Set(key, state.Bytes())
+	batch.WriteSync()
 }
 
 //------------------------------------------------------------------------
@@ -158,16 +161,24 @@ func LoadABCIResponses(db dbm.DB, height int64) (*ABCIResponses, error) {
 // SaveABCIResponses persists the ABCIResponses to the database.
 // This is useful in case we crash after app.Commit and before s.Save().
 // Responses are indexed by height so they can also be loaded later to produce Merkle proofs.
-func saveABCIResponses(db dbm.DB, height int64, abciResponses *ABCIResponses) {
+func SaveABCIResponses(db dbm.DB, height int64, abciResponses *ABCIResponses) {
 	db.SetSync(calcABCIResponsesKey(height), abciResponses.Bytes())
 }
 
 //-----------------------------------------------------------------------------
 
+// Every valSetCheckpointInterval changes of the validator set, the set is saved
+// in full instead of its diff, to bound the diffs loaded to rebuild a set.
+const valSetCheckpointInterval = 100
+
 // ValidatorsInfo represents the latest validator set, or the last height it changed
 type ValidatorsInfo struct {
 	ValidatorSet      *types.ValidatorSet
 	LastHeightChanged int64
+
+	// The diff of the validator set from the previous height, saved instead of
+	// the set at the height it changed, if it can be rebuilt from it.
+	ValidatorSetDiff *ValidatorSetDiff
 }
 
 // Bytes serializes the ValidatorsInfo using go-amino.
@@ -175,6 +186,57 @@ func (valInfo *ValidatorsInfo) Bytes() []byte {
 	return cdc.MustMarshalBinaryBare(valInfo)
 }
 
+// ValidatorSetDiff is the change of the validator set at a height, from the set
+// at the previous height. The hashes of both sets chain the diffs, and check
+// the sets rebuilt from them.
+type ValidatorSetDiff struct {
+	PrevHash []byte
+	// the updated validators, the removed ones with no voting power
+	Changes []*types.Validator
+	Hash    []byte
+	// number of diffs since the last set saved in full
+	Depth int64
+}
+
+// apply returns the validator set rebuilt from the previous one, as updated by
+// updateState.
+func (diff *ValidatorSetDiff) apply(prev *types.ValidatorSet) (*types.ValidatorSet, error) {
+	if !bytes.Equal(prev.Hash(), diff.PrevHash) {
+		return nil, fmt.Errorf("previous validator set hash %X doesn't match the diff's %X", prev.Hash(), diff.PrevHash)
+	}
+	changes := make([]*types.Validator, len(diff.Changes))
+	for i, val := range diff.Changes {
+		changes[i] = val.Copy()
+	}
+	valSet := prev.Copy()
+	if err := valSet.UpdateWithChangeSet(changes); err != nil {
+		return nil, err
+	}
+	valSet.IncrementProposerPriority(1)
+	if !bytes.Equal(valSet.Hash(), diff.Hash) {
+		return nil, fmt.Errorf("rebuilt validator set hash %X doesn't match the diff's %X", valSet.Hash(), diff.Hash)
+	}
+	return valSet, nil
+}
+
+// validatorChanges returns the validators updated from prev to next, the
+// removed ones with no voting power.
+func validatorChanges(prev, next *types.ValidatorSet) []*types.Validator {
+	changes := make([]*types.Validator, 0)
+	for _, val := range next.Validators {
+		_, prevVal := prev.GetByAddress(val.Address)
+		if prevVal == nil || prevVal.VotingPower != val.VotingPower {
+			changes = append(changes, types.NewValidator(val.PubKey, val.VotingPower))
+		}
+	}
+	for _, val := range prev.Validators {
+		if !next.HasAddress(val.Address) {
+			changes = append(changes, types.NewValidator(val.PubKey, 0))
+		}
+	}
+	return changes
+}
+
 // LoadValidators loads the ValidatorSet for a given height.
 // Returns ErrNoValSetForHeight if the validator set can't be found for this height.
 func LoadValidators(db dbm.DB, height int64) (*types.ValidatorSet, error) {
@@ -182,23 +244,96 @@ func LoadValidators(db dbm.DB, height int64) (*types.ValidatorSet, error) {
 	if valInfo == nil {
 		return nil, ErrNoValSetForHeight{height}
 	}
+	if valInfo.ValidatorSet != nil || valInfo.ValidatorSetDiff != nil {
+		return loadChangedValidators(db, height, valInfo)
+	}
 
-	if valInfo.ValidatorSet == nil {
-		valInfo2 := loadValidatorsInfo(db, valInfo.LastHeightChanged)
-		if valInfo2 == nil {
-			panic(
-				fmt.Sprintf(
-					"Couldn't find validators at height %d as last changed from height %d",
-					valInfo.LastHeightChanged,
-					height,
-				),
-			)
+	valInfo2 := loadValidatorsInfo(db, valInfo.LastHeightChanged)
+	if valInfo2 == nil {
+		panic(
+			fmt.Sprintf(
+				"Couldn't find validators at height %d as last changed from height %d",
+				valInfo.LastHeightChanged,
+				height,
+			),
+		)
+	}
+	valSet, err := loadChangedValidators(db, valInfo.LastHeightChanged, valInfo2)
+	if err != nil {
+		return nil, err
+	}
+	valSet.IncrementProposerPriority(height - valInfo.LastHeightChanged) // mutate
+	return valSet, nil
+}
+
+// loadChangedValidators returns the validator set of the valInfo saved at the
+// height it changed, rebuilding it from the previous sets if it was saved as a
+// diff.
+func loadChangedValidators(db dbm.DB, height int64, valInfo *ValidatorsInfo) (*types.ValidatorSet, error) {
+	if valInfo.ValidatorSet != nil {
+		return valInfo.ValidatorSet, nil
+	}
+	prev, err := LoadValidators(db, height-1)
+	if err != nil {
+		return nil, err
+	}
+	valSet, err := valInfo.ValidatorSetDiff.apply(prev)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't rebuild validators at height %d: %v", height, err)
+	}
+	return valSet, nil
+}
+
+// ValidatorSetChange is the change of the validator set at a height.
+type ValidatorSetChange struct {
+	Height int64 `json:"height"`
+	// the updated validators, the removed ones with no voting power
+	Changes []*types.Validator `json:"changes"`
+}
+
+// LoadValidatorSetChanges loads the changes of the validator set at the
+// heights in (fromHeight, toHeight], in ascending order of height. The first
+// validator set is a change of all its validators.
+func LoadValidatorSetChanges(db dbm.DB, fromHeight, toHeight int64) ([]ValidatorSetChange, error) {
+	valInfo := loadValidatorsInfo(db, toHeight)
+	if valInfo == nil {
+		return nil, ErrNoValSetForHeight{toHeight}
+	}
+
+	changes := []ValidatorSetChange{}
+	for changeHeight := valInfo.LastHeightChanged; changeHeight > fromHeight; {
+		changeInfo := loadValidatorsInfo(db, changeHeight)
+		if changeInfo == nil {
+			return nil, ErrNoValSetForHeight{changeHeight}
+		}
+		prevInfo := loadValidatorsInfo(db, changeHeight-1)
+
+		change := ValidatorSetChange{Height: changeHeight}
+		switch {
+		case changeInfo.ValidatorSetDiff != nil:
+			change.Changes = changeInfo.ValidatorSetDiff.Changes
+		case prevInfo == nil:
+			change.Changes = validatorChanges(types.NewValidatorSet(nil), changeInfo.ValidatorSet)
+		default:
+			prev, err := LoadValidators(db, changeHeight-1)
+			if err != nil {
+				return nil, err
+			}
+			change.Changes = validatorChanges(prev, changeInfo.ValidatorSet)
 		}
-		valInfo2.ValidatorSet.IncrementProposerPriority(int(height - valInfo.LastHeightChanged)) // mutate
-		valInfo = valInfo2
+		changes = append(changes, change)
+
+		// The set before the change was in effect since the previous change.
+		if prevInfo == nil || prevInfo.LastHeightChanged >= changeHeight {
+			break
+		}
+		changeHeight = prevInfo.LastHeightChanged
 	}
 
-	return valInfo.ValidatorSet, nil
+	for i, j := 0, len(changes)-1; i < j; i, j = i+1, j-1 {
+		changes[i], changes[j] = changes[j], changes[i]
+	}
+	return changes, nil
 }
 
 // CONTRACT: Returned ValidatorsInfo can be mutated.
@@ -225,7 +360,10 @@ func loadValidatorsInfo(db dbm.DB, height int64) *ValidatorsInfo {
 // It should be called from s.Save(), right before the state itself is persisted.
 // If the validator set did not change after processing the latest block,
 // only the last height for which the validators changed is persisted.
-func saveValidatorsInfo(db dbm.DB, height, lastHeightChanged int64, valSet *types.ValidatorSet) {
+// If it did, only its diff from the previous height is persisted, if the set
+// can be rebuilt from it. The previous sets are read from db, and the set is
+// written to batch.
+func saveValidatorsInfo(db dbm.DB, batch dbm.SetDeleter, height, lastHeightChanged int64, valSet *types.ValidatorSet) {
 	if lastHeightChanged > height {
 		panic("LastHeightChanged cannot be greater than ValidatorsInfo height")
 	}
@@ -233,9 +371,50 @@ func saveValidatorsInfo(db dbm.DB, height, lastHeightChanged int64, valSet *type
 		LastHeightChanged: lastHeightChanged,
 	}
 	if lastHeightChanged == height {
-		valInfo.ValidatorSet = valSet
+		if diff := makeValidatorSetDiff(db, height, valSet); diff != nil {
+			valInfo.ValidatorSetDiff = diff
+		} else {
+			valInfo.ValidatorSet = valSet
+		}
 	}
-	db.Set(calcValidatorsKey(height), valInfo.Bytes())
+	batch.Set(calcValidatorsKey(height), valInfo.Bytes())
+}
+
+// makeValidatorSetDiff returns the diff of the validator set at the height from
+// the previous one, or nil if the set must be saved in full: there is no
+// previous one, it's a checkpoint, or the set can't be rebuilt from the diff.
+func makeValidatorSetDiff(db dbm.DB, height int64, valSet *types.ValidatorSet) *ValidatorSetDiff {
+	prevInfo := loadValidatorsInfo(db, height-1)
+	if prevInfo == nil {
+		return nil
+	}
+	changeInfo := prevInfo
+	if prevInfo.ValidatorSet == nil && prevInfo.ValidatorSetDiff == nil {
+		changeInfo = loadValidatorsInfo(db, prevInfo.LastHeightChanged)
+	}
+	depth := int64(1)
+	if changeInfo != nil && changeInfo.ValidatorSetDiff != nil {
+		depth = changeInfo.ValidatorSetDiff.Depth + 1
+	}
+	if depth >= valSetCheckpointInterval {
+		return nil
+	}
+
+	prev, err := LoadValidators(db, height-1)
+	if err != nil {
+		return nil
+	}
+	diff := &ValidatorSetDiff{
+		PrevHash: prev.Hash(),
+		Changes:  validatorChanges(prev, valSet),
+		Hash:     valSet.Hash(),
+		Depth:    depth,
+	}
+	rebuilt, err := diff.apply(prev)
+	if err != nil || !bytes.Equal(cdc.MustMarshalBinaryBare(rebuilt), cdc.MustMarshalBinaryBare(valSet)) {
+		return nil
+	}
+	return diff
 }
 
 //-----------------------------------------------------------------------------
@@ -277,6 +456,42 @@ func LoadConsensusParams(db dbm.DB, height int64) (types.ConsensusParams, error)
 	return paramsInfo.ConsensusParams, nil
 }
 
+// ConsensusParamsChange is the consensus params in effect from a height.
+type ConsensusParamsChange struct {
+	Height          int64                 `json:"height"`
+	ConsensusParams types.ConsensusParams `json:"consensus_params"`
+}
+
+// LoadConsensusParamsChanges loads the changes of the ConsensusParams up to a
+// given height, in ascending order of height, starting with the genesis params.
+func LoadConsensusParamsChanges(db dbm.DB, height int64) ([]ConsensusParamsChange, error) {
+	paramsInfo := loadConsensusParamsInfo(db, height)
+	if paramsInfo == nil {
+		return nil, ErrNoConsensusParamsForHeight{height}
+	}
+
+	var changes []ConsensusParamsChange
+	for changeHeight := paramsInfo.LastHeightChanged; ; {
+		params, err := LoadConsensusParams(db, changeHeight)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, ConsensusParamsChange{changeHeight, params})
+
+		// The params before the change were in effect since the previous change.
+		prevInfo := loadConsensusParamsInfo(db, changeHeight-1)
+		if prevInfo == nil || prevInfo.LastHeightChanged >= changeHeight {
+			break
+		}
+		changeHeight = prevInfo.LastHeightChanged
+	}
+
+	for i, j := 0, len(changes)-1; i < j; i, j = i+1, j-1 {
+		changes[i], changes[j] = changes[j], changes[i]
+	}
+	return changes, nil
+}
+
 func loadConsensusParamsInfo(db dbm.DB, height int64) *ConsensusParamsInfo {
 	buf := db.Get(calcConsensusParamsKey(height))
 	if len(buf) == 0 {
@@ -299,12 +514,108 @@ func loadConsensusParamsInfo(db dbm.DB, height int64) *ConsensusParamsInfo {
 // It should be called from s.Save(), right before the state itself is persisted.
 // If the consensus params did not change after processing the latest block,
 // only the last height for which they changed is persisted.
-func saveConsensusParamsInfo(db dbm.DB, nextHeight, changeHeight int64, params types.ConsensusParams) {
+func saveConsensusParamsInfo(batch dbm.SetDeleter, nextHeight, changeHeight int64, params types.ConsensusParams) {
 	paramsInfo := &ConsensusParamsInfo{
 		LastHeightChanged: changeHeight,
 	}
 	if changeHeight == nextHeight {
 		paramsInfo.ConsensusParams = params
 	}
-	db.Set(calcConsensusParamsKey(nextHeight), paramsInfo.Bytes())
+	batch.Set(calcConsensusParamsKey(nextHeight), paramsInfo.Bytes())
+}
+
+//-----------------------------------------------------------------------------
+
+var prunedStatesKey = []byte("prunedStatesKey")
+
+// prunedStates is the last height pruned by PruneStates, and the heights below
+// it it kept the validator set and consensus params at.
+type prunedStates struct {
+	Height                int64
+	ValidatorsHeight      int64
+	ConsensusParamsHeight int64
+}
+
+// PruneStates deletes the validator sets, consensus params and ABCI responses
+// saved for the heights below retainHeight, in a single batch, to be called
+// once the blocks below it are pruned (see BlockStore.PruneBlocks). The ones
+// in effect at retainHeight are kept, in full, so that they can still be
+// loaded for the heights from it. retainHeight can't be beyond the last block
+// of the saved state.
+func PruneStates(db dbm.DB, retainHeight int64) error {
+	if retainHeight <= 1 {
+		return nil
+	}
+	if lastHeight := LoadState(db).LastBlockHeight; retainHeight > lastHeight {
+		return fmt.Errorf("cannot prune the states beyond the last height %v", lastHeight)
+	}
+	var pruned prunedStates
+	if bz := db.Get(prunedStatesKey); len(bz) > 0 {
+		cdc.MustUnmarshalBinaryBare(bz, &pruned)
+	}
+	if retainHeight <= pruned.Height+1 {
+		return nil
+	}
+
+	batch := db.NewBatch()
+
+	// Keep the validator set in effect at retainHeight, in full, at the height
+	// it changed, as the later heights point to it, and the diffs after it
+	// chain to it.
+	valInfo := loadValidatorsInfo(db, retainHeight)
+	if valInfo == nil {
+		return ErrNoValSetForHeight{retainHeight}
+	}
+	keepValsHeight := valInfo.LastHeightChanged
+	if keepValsHeight > retainHeight {
+		keepValsHeight = retainHeight
+	}
+	keepValsInfo := loadValidatorsInfo(db, keepValsHeight)
+	if keepValsInfo == nil {
+		return ErrNoValSetForHeight{keepValsHeight}
+	}
+	if keepValsInfo.ValidatorSet == nil {
+		valSet, err := LoadValidators(db, keepValsHeight)
+		if err != nil {
+			return err
+		}
+		keepValsInfo = &ValidatorsInfo{
+			ValidatorSet:      valSet,
+			LastHeightChanged: keepValsHeight,
+		}
+		batch.Set(calcValidatorsKey(keepValsHeight), keepValsInfo.Bytes())
+	}
+
+	// Keep the consensus params in effect at retainHeight, at the height they
+	// changed, as the later heights point to them.
+	paramsInfo := loadConsensusParamsInfo(db, retainHeight)
+	if paramsInfo == nil {
+		return ErrNoConsensusParamsForHeight{retainHeight}
+	}
+	keepParamsHeight := paramsInfo.LastHeightChanged
+
+	// The ones kept by the previous pruning may not be in effect anymore.
+	if pruned.ValidatorsHeight != 0 && pruned.ValidatorsHeight != keepValsHeight {
+		batch.Delete(calcValidatorsKey(pruned.ValidatorsHeight))
+	}
+	if pruned.ConsensusParamsHeight != 0 && pruned.ConsensusParamsHeight != keepParamsHeight {
+		batch.Delete(calcConsensusParamsKey(pruned.ConsensusParamsHeight))
+	}
+	for height := pruned.Height + 1; height < retainHeight; height++ {
+		if height != keepValsHeight {
+			batch.Delete(calcValidatorsKey(height))
+		}
+		if height != keepParamsHeight {
+			batch.Delete(calcConsensusParamsKey(height))
+		}
+		batch.Delete(calcABCIResponsesKey(height))
+	}
+	pruned = prunedStates{
+		Height:                retainHeight - 1,
+		ValidatorsHeight:      keepValsHeight,
+		ConsensusParamsHeight: keepParamsHeight,
+	}
+	batch.Set(prunedStatesKey, cdc.MustMarshalBinaryBare(pruned))
+	batch.WriteSync()
+	return nil
 }
@@ -1,6 +1,7 @@
 package txindex
 
 import (
+	"context"
 	"errors"
 
 	"github.com/tendermint/tendermint/libs/pubsub/query"
@@ -20,8 +21,9 @@ type TxIndexer interface {
 	// or stored.
 	Get(hash []byte) (*types.TxResult, error)
 
-	// Search allows you to query for transactions.
-	Search(q *query.Query) ([]*types.TxResult, error)
+	// Search allows you to query for transactions, until the context is
+	// canceled.
+	Search(ctx context.Context, q *query.Query) ([]*types.TxResult, error)
 }
 
 //----------------------------------------------------
@@ -2,72 +2,226 @@ package txindex
 
 import (
 	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
 
 	cmn "github.com/tendermint/tendermint/libs/common"
-
+	dbm "github.com/tendermint/tendermint/libs/db"
+	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
 )
 
 const (
 	subscriber = "IndexerService"
+
+	// Delay before indexing a block again after failing to.
+	indexRetryInterval = time.Second
 )
 
-// IndexerService connects event bus and transaction indexer together in order
-// to index transactions coming from event bus.
+// Key of the last indexed height in the db of the IndexerService.
+var indexedHeightKey = []byte("IndexerService:indexedHeight")
+
+// IndexerService indexes the transactions of the committed blocks, off the
+// commit path: the NewBlockHeader events only tell it the last committed
+// height, and a worker indexes the blocks up to it, from the block store and
+// the ABCI responses of the state, so a slow indexer can't delay the blocks.
+//
+// The store and the state are its durable queue: the last indexed height is
+// persisted, and the blocks committed since are indexed at start.
 type IndexerService struct {
 	cmn.BaseService
 
-	idr      TxIndexer
-	eventBus *types.EventBus
+	idr        TxIndexer
+	eventBus   *types.EventBus
+	db         dbm.DB // of the last indexed height, nil to not persist it
+	stateDB    dbm.DB
+	blockStore sm.BlockStoreRPC
+
+	mtx           sync.Mutex
+	indexedHeight int64
+	targetHeight  int64         // last committed height
+	wakeCh        chan struct{} // the target height increased
 }
 
-// NewIndexerService returns a new service instance.
-func NewIndexerService(idr TxIndexer, eventBus *types.EventBus) *IndexerService {
-	is := &IndexerService{idr: idr, eventBus: eventBus}
+// NewIndexerService returns a new service instance, indexing the blocks of
+// blockStore with the ABCI responses of stateDB. The last indexed height is
+// persisted in db, if not nil.
+func NewIndexerService(idr TxIndexer, eventBus *types.EventBus, db, stateDB dbm.DB, blockStore sm.BlockStoreRPC) *IndexerService {
+	is := &IndexerService{
+		idr:        idr,
+		eventBus:   eventBus,
+		db:         db,
+		stateDB:    stateDB,
+		blockStore: blockStore,
+		wakeCh:     make(chan struct{}, 1),
+	}
 	is.BaseService = *cmn.NewBaseService(nil, "IndexerService", is)
 	return is
 }
 
-// OnStart implements cmn.Service by subscribing for all transactions
-// and indexing them by tags.
+// OnStart implements cmn.Service by subscribing for the new blocks and
+// catching up with the blocks committed since the last indexed one.
 func (is *IndexerService) OnStart() error {
-	blockHeadersCh := make(chan interface{})
+	blockHeadersCh := make(chan interface{}, 1)
 	if err := is.eventBus.Subscribe(context.Background(), subscriber, types.EventQueryNewBlockHeader, blockHeadersCh); err != nil {
 		return err
 	}
 
-	txsCh := make(chan interface{})
-	if err := is.eventBus.Subscribe(context.Background(), subscriber, types.EventQueryTx, txsCh); err != nil {
-		return err
+	lastHeight := sm.LoadState(is.stateDB).LastBlockHeight
+	indexedHeight, ok := is.loadIndexedHeight()
+	if !ok {
+		// The blocks were indexed on commit before the height was persisted,
+		// but the last one, if the node stopped between its commit and its
+		// indexing: it is indexed again.
+		indexedHeight = cmn.MaxInt64(lastHeight-1, 0)
+		is.saveIndexedHeight(indexedHeight)
 	}
+	is.indexedHeight = indexedHeight
+	is.setTargetHeight(lastHeight)
 
-	go func() {
-		for {
-			e, ok := <-blockHeadersCh
+	go is.followRoutine(blockHeadersCh)
+	go is.indexRoutine()
+	return nil
+}
+
+// OnStop implements cmn.Service by unsubscribing from the new blocks.
+func (is *IndexerService) OnStop() {
+	if is.eventBus.IsRunning() {
+		_ = is.eventBus.UnsubscribeAll(context.Background(), subscriber)
+	}
+}
+
+// IndexedHeight returns the last indexed height.
+func (is *IndexerService) IndexedHeight() int64 {
+	is.mtx.Lock()
+	defer is.mtx.Unlock()
+	return is.indexedHeight
+}
+
+// followRoutine only records the committed heights, so it never blocks the
+// event bus.
+func (is *IndexerService) followRoutine(blockHeadersCh <-chan interface{}) {
+	for {
+		select {
+		case e, ok := <-blockHeadersCh:
 			if !ok {
 				return
 			}
-			header := e.(types.EventDataNewBlockHeader).Header
-			batch := NewBatch(header.NumTxs)
-			for i := int64(0); i < header.NumTxs; i++ {
-				e, ok := <-txsCh
-				if !ok {
-					is.Logger.Error("Failed to index all transactions due to closed transactions channel", "height", header.Height, "numTxs", header.NumTxs, "numProcessed", i)
-					return
-				}
-				txResult := e.(types.EventDataTx).TxResult
-				batch.Add(&txResult)
+			is.setTargetHeight(e.(types.EventDataNewBlockHeader).Header.Height)
+		case <-is.Quit():
+			return
+		}
+	}
+}
+
+func (is *IndexerService) setTargetHeight(height int64) {
+	is.mtx.Lock()
+	if height > is.targetHeight {
+		is.targetHeight = height
+	}
+	is.mtx.Unlock()
+
+	select {
+	case is.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+// indexRoutine indexes the blocks up to the target height, in order.
+func (is *IndexerService) indexRoutine() {
+	for {
+		is.mtx.Lock()
+		height, target := is.indexedHeight+1, is.targetHeight
+		is.mtx.Unlock()
+
+		var retryCh <-chan time.Time
+		if height <= target {
+			err := is.indexBlock(height)
+			if _, ok := err.(errNotIndexable); ok {
+				// Retrying can't index it: skip it.
+				is.Logger.Error("Skipping block, it can't be indexed", "height", height, "err", err)
+				err = nil
+			}
+			if err != nil {
+				is.Logger.Error("Failed to index block", "height", height, "err", err)
+				retryCh = time.After(indexRetryInterval)
+			} else {
+				is.saveIndexedHeight(height)
+				is.mtx.Lock()
+				is.indexedHeight = height
+				is.mtx.Unlock()
+				is.Logger.Info("Indexed block", "height", height, "behind", target-height)
+				continue
 			}
-			is.idr.AddBatch(batch)
-			is.Logger.Info("Indexed block", "height", header.Height)
 		}
-	}()
-	return nil
+
+		select {
+		case <-is.wakeCh:
+		case <-retryCh:
+		case <-is.Quit():
+			return
+		}
+	}
 }
 
-// OnStop implements cmn.Service by unsubscribing from all transactions.
-func (is *IndexerService) OnStop() {
-	if is.eventBus.IsRunning() {
-		_ = is.eventBus.UnsubscribeAll(context.Background(), subscriber)
+// errNotIndexable is returned by indexBlock for the blocks it can never
+// index, e.g. as they or their ABCI responses were pruned.
+type errNotIndexable struct {
+	error
+}
+
+// indexBlock indexes the txs of the block at height with their results.
+func (is *IndexerService) indexBlock(height int64) error {
+	block := is.blockStore.LoadBlock(height)
+	if block == nil {
+		err := fmt.Errorf("no block at height %d", height)
+		if height <= is.blockStore.Height() {
+			return errNotIndexable{err}
+		}
+		return err
+	}
+	batch := NewBatch(int64(len(block.Data.Txs)))
+	if len(block.Data.Txs) > 0 {
+		abciResponses, err := sm.LoadABCIResponses(is.stateDB, height)
+		if _, ok := err.(sm.ErrNoABCIResponsesForHeight); ok {
+			return errNotIndexable{err}
+		} else if err != nil {
+			return err
+		}
+		if len(abciResponses.DeliverTx) != len(block.Data.Txs) {
+			return errNotIndexable{fmt.Errorf("%d tx results for %d txs",
+				len(abciResponses.DeliverTx), len(block.Data.Txs))}
+		}
+		for i, tx := range block.Data.Txs {
+			batch.Add(&types.TxResult{
+				Height: height,
+				Index:  uint32(i),
+				Tx:     tx,
+				Result: *(abciResponses.DeliverTx[i]),
+			})
+		}
+	}
+	return is.idr.AddBatch(batch)
+}
+
+func (is *IndexerService) loadIndexedHeight() (int64, bool) {
+	if is.db == nil {
+		return 0, false
+	}
+	bz := is.db.Get(indexedHeightKey)
+	if len(bz) != 8 {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(bz)), true
+}
+
+func (is *IndexerService) saveIndexedHeight(height int64) {
+	if is.db == nil {
+		return
 	}
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(height))
+	is.db.Set(indexedHeightKey, bz)
 }
@@ -0,0 +1,122 @@
+package txindex_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	db "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/state/txindex"
+	"github.com/tendermint/tendermint/state/txindex/kv"
+	"github.com/tendermint/tendermint/types"
+)
+
+// testBlockStore is a block store of the blocks of the heights.
+type testBlockStore struct {
+	sm.BlockStoreRPC
+	blocks map[int64]*types.Block
+}
+
+func (bs testBlockStore) LoadBlock(height int64) *types.Block {
+	return bs.blocks[height]
+}
+
+func (bs testBlockStore) Height() int64 {
+	var height int64
+	for h := range bs.blocks {
+		if h > height {
+			height = h
+		}
+	}
+	return height
+}
+
+func TestIndexerServiceAsyncAndCatchUp(t *testing.T) {
+	stateDB, indexDB := db.NewMemDB(), db.NewMemDB()
+	blockStore := testBlockStore{blocks: make(map[int64]*types.Block)}
+	commit := func(height int64) *types.Block {
+		block := types.MakeBlock(height, []types.Tx{{byte(height)}}, nil, nil)
+		blockStore.blocks[height] = block
+		abciResponses := sm.NewABCIResponses(block)
+		abciResponses.DeliverTx[0] = &abci.ResponseDeliverTx{Code: abci.CodeTypeOK, Data: []byte{byte(height)}}
+		sm.SaveABCIResponses(stateDB, height, abciResponses)
+		return block
+	}
+
+	genDoc := &types.GenesisDoc{ChainID: "indexer"}
+	require.NoError(t, genDoc.ValidateAndComplete())
+	state, err := sm.MakeGenesisState(genDoc)
+	require.NoError(t, err)
+	commit(1)
+	commit(2)
+	state.LastBlockHeight = 2
+	sm.SaveState(stateDB, state)
+
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	defer eventBus.Stop()
+
+	indexer := kv.NewTxIndex(indexDB)
+	start := func() *txindex.IndexerService {
+		service := txindex.NewIndexerService(indexer, eventBus, indexDB, stateDB, blockStore)
+		service.SetLogger(log.TestingLogger())
+		require.NoError(t, service.Start())
+		return service
+	}
+	indexed := func(block *types.Block) bool {
+		txResult, err := indexer.Get(block.Txs[0].Hash())
+		require.NoError(t, err)
+		return txResult != nil
+	}
+
+	// The blocks committed before the first start were indexed on commit,
+	// but the last one, which may not be.
+	service := start()
+	waitIndexedHeight(t, service, 2)
+	assert.True(t, indexed(blockStore.blocks[2]))
+	assert.False(t, indexed(blockStore.blocks[1]))
+
+	block := commit(3)
+	require.NoError(t, eventBus.PublishEventNewBlockHeader(types.EventDataNewBlockHeader{Header: block.Header}))
+	waitIndexedHeight(t, service, 3)
+	assert.True(t, indexed(block))
+	service.Stop()
+
+	// The blocks committed while it was stopped are indexed at restart.
+	commit(4)
+	commit(5)
+	state.LastBlockHeight = 5
+	sm.SaveState(stateDB, state)
+
+	service = start()
+	defer service.Stop()
+	waitIndexedHeight(t, service, 5)
+	assert.True(t, indexed(blockStore.blocks[4]))
+	assert.True(t, indexed(blockStore.blocks[5]))
+
+	txResult, err := indexer.Get(blockStore.blocks[5].Txs[0].Hash())
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, txResult.Height)
+	assert.Equal(t, []byte{5}, txResult.Result.Data)
+
+	// A pruned block is skipped.
+	commit(6)
+	delete(blockStore.blocks, 6)
+	block = commit(7)
+	require.NoError(t, eventBus.PublishEventNewBlockHeader(types.EventDataNewBlockHeader{Header: block.Header}))
+	waitIndexedHeight(t, service, 7)
+	assert.True(t, indexed(block))
+}
+
+func waitIndexedHeight(t *testing.T, service *txindex.IndexerService, height int64) {
+	for start := time.Now(); service.IndexedHeight() < height; time.Sleep(10 * time.Millisecond) {
+		if time.Since(start) > time.Second {
+			t.Fatalf("Timed out waiting for height %d to be indexed, at %d", height, service.IndexedHeight())
+		}
+	}
+}
@@ -2,6 +2,7 @@ package kv
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"fmt"
 	"sort"
@@ -10,6 +11,8 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+
+	abci "github.com/tendermint/tendermint/abci/types"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	dbm "github.com/tendermint/tendermint/libs/db"
 
@@ -29,6 +32,7 @@ type TxIndex struct {
 	store        dbm.DB
 	tagsToIndex  []string
 	indexAllTags bool
+	tagEncoding  types.TagEncoding
 }
 
 // NewTxIndex creates new KV indexer.
@@ -54,6 +58,14 @@ func IndexAllTags() func(*TxIndex) {
 	}
 }
 
+// TagEncoding is an option for setting the encoding of the values of the tags
+// in the queries. The index has the values as is, whatever the encoding.
+func TagEncoding(e types.TagEncoding) func(*TxIndex) {
+	return func(txi *TxIndex) {
+		txi.tagEncoding = e
+	}
+}
+
 // Get gets transaction from the TxIndex storage and returns it or nil if the
 // transaction is not found.
 func (txi *TxIndex) Get(hash []byte) (*types.TxResult, error) {
@@ -82,12 +94,8 @@ func (txi *TxIndex) AddBatch(b *txindex.Batch) error {
 	for _, result := range b.Ops {
 		hash := result.Tx.Hash()
 
-		// index tx by tags
-		for _, tag := range result.Result.Tags {
-			if txi.indexAllTags || cmn.StringInSlice(string(tag.Key), txi.tagsToIndex) {
-				storeBatch.Set(keyForTag(tag, result), hash)
-			}
-		}
+		// index tx by events
+		txi.indexEvents(result, hash, storeBatch)
 
 		// index tx by height
 		if txi.indexAllTags || cmn.StringInSlice(types.TxHeightKey, txi.tagsToIndex) {
@@ -102,7 +110,8 @@ func (txi *TxIndex) AddBatch(b *txindex.Batch) error {
 		storeBatch.Set(hash, rawBytes)
 	}
 
-	storeBatch.Write()
+	// One atomic write, and one fsync, for the whole block.
+	storeBatch.WriteSync()
 	return nil
 }
 
@@ -112,12 +121,8 @@ func (txi *TxIndex) Index(result *types.TxResult) error {
 
 	hash := result.Tx.Hash()
 
-	// index tx by tags
-	for _, tag := range result.Result.Tags {
-		if txi.indexAllTags || cmn.StringInSlice(string(tag.Key), txi.tagsToIndex) {
-			b.Set(keyForTag(tag, result), hash)
-		}
-	}
+	// index tx by events
+	txi.indexEvents(result, hash, b)
 
 	// index tx by height
 	if txi.indexAllTags || cmn.StringInSlice(types.TxHeightKey, txi.tagsToIndex) {
@@ -135,18 +140,38 @@ func (txi *TxIndex) Index(result *types.TxResult) error {
 	return nil
 }
 
+// indexEvents indexes the tx by the composite keys of the attributes of its
+// events (see abci.EventKey) to index.
+func (txi *TxIndex) indexEvents(result *types.TxResult, hash []byte, store dbm.SetDeleter) {
+	for _, event := range result.Result.Events {
+		for _, attr := range event.Attributes {
+			if len(attr.Key) == 0 {
+				continue
+			}
+			compositeKey := abci.EventKey(event.Type, attr.Key)
+			if txi.indexAllTags || cmn.StringInSlice(compositeKey, txi.tagsToIndex) {
+				store.Set(keyForEvent(compositeKey, attr.Value, result), hash)
+			}
+		}
+	}
+}
+
 // Search performs a search using the given query. It breaks the query into
 // conditions (like "tx.height > 5"). For each condition, it queries the DB
 // index. One special use cases here: (1) if "tx.hash" is found, it returns tx
 // result for it (2) for range queries it is better for the client to provide
 // both lower and upper bounds, so we are not performing a full scan. Results
-// from querying indexes are then intersected and returned to the caller.
-func (txi *TxIndex) Search(q *query.Query) ([]*types.TxResult, error) {
+// from querying indexes are then intersected and returned to the caller. It
+// returns the error of the context if canceled meanwhile.
+func (txi *TxIndex) Search(ctx context.Context, q *query.Query) ([]*types.TxResult, error) {
 	var hashes [][]byte
 	var hashesInitialized bool
 
 	// get a list of conditions (like "tx.height > 5")
-	conditions := q.Conditions()
+	conditions, err := txi.decodeOperands(q.Conditions())
+	if err != nil {
+		return nil, errors.Wrap(err, "error during decoding the tag values of the query")
+	}
 
 	// if there is a hash condition, return the result immediately
 	hash, err, ok := lookForHash(conditions)
@@ -172,10 +197,13 @@ func (txi *TxIndex) Search(q *query.Query) ([]*types.TxResult, error) {
 
 		for _, r := range ranges {
 			if !hashesInitialized {
-				hashes = txi.matchRange(r, startKey(r.key))
+				hashes = txi.matchRange(ctx, r, startKey(r.key))
 				hashesInitialized = true
 			} else {
-				hashes = intersect(hashes, txi.matchRange(r, startKey(r.key)))
+				hashes = intersect(hashes, txi.matchRange(ctx, r, startKey(r.key)))
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
 			}
 		}
 	}
@@ -190,16 +218,22 @@ func (txi *TxIndex) Search(q *query.Query) ([]*types.TxResult, error) {
 		}
 
 		if !hashesInitialized {
-			hashes = txi.match(c, startKeyForCondition(c, height))
+			hashes = txi.match(ctx, c, startKeyForCondition(c, height))
 			hashesInitialized = true
 		} else {
-			hashes = intersect(hashes, txi.match(c, startKeyForCondition(c, height)))
+			hashes = intersect(hashes, txi.match(ctx, c, startKeyForCondition(c, height)))
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 	}
 
 	results := make([]*types.TxResult, len(hashes))
 	i := 0
 	for _, h := range hashes {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		results[i], err = txi.Get(h)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to get Tx{%X}", h)
@@ -218,6 +252,24 @@ func (txi *TxIndex) Search(q *query.Query) ([]*types.TxResult, error) {
 	return results, nil
 }
 
+// decodeOperands decodes the string operands of the = conditions on the tags
+// of the txs, in the tag encoding, to the values of the index. The CONTAINS
+// conditions match the encoded values, see match.
+func (txi *TxIndex) decodeOperands(conditions []query.Condition) ([]query.Condition, error) {
+	for i, c := range conditions {
+		operand, ok := c.Operand.(string)
+		if !ok || c.Op != query.OpEqual || c.Tag == types.TxHashKey || c.Tag == types.TxHeightKey {
+			continue
+		}
+		value, err := txi.tagEncoding.DecodeString(operand)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value of tag %s: %v", txi.tagEncoding, c.Tag, err)
+		}
+		conditions[i].Operand = string(value)
+	}
+	return conditions, nil
+}
+
 func lookForHash(conditions []query.Condition) (hash []byte, err error, ok bool) {
 	for _, c := range conditions {
 		if c.Tag == types.TxHashKey {
@@ -332,11 +384,13 @@ func isRangeOperation(op query.Operator) bool {
 	}
 }
 
-func (txi *TxIndex) match(c query.Condition, startKeyBz []byte) (hashes [][]byte) {
+// match returns the hashes of the txs matching the condition, or some of them
+// if the context is canceled meanwhile.
+func (txi *TxIndex) match(ctx context.Context, c query.Condition, startKeyBz []byte) (hashes [][]byte) {
 	if c.Op == query.OpEqual {
 		it := dbm.IteratePrefix(txi.store, startKeyBz)
 		defer it.Close()
-		for ; it.Valid(); it.Next() {
+		for ; it.Valid() && ctx.Err() == nil; it.Next() {
 			hashes = append(hashes, it.Value())
 		}
 	} else if c.Op == query.OpContains {
@@ -345,11 +399,12 @@ func (txi *TxIndex) match(c query.Condition, startKeyBz []byte) (hashes [][]byte
 		// we can't iterate with prefix "account.owner/an/" because we might miss keys like "account.owner/Ulan/"
 		it := dbm.IteratePrefix(txi.store, startKey(c.Tag))
 		defer it.Close()
-		for ; it.Valid(); it.Next() {
+		for ; it.Valid() && ctx.Err() == nil; it.Next() {
 			if !isTagKey(it.Key()) {
 				continue
 			}
-			if strings.Contains(extractValueFromKey(it.Key()), c.Operand.(string)) {
+			value := txi.tagEncoding.EncodeToString([]byte(extractValueFromKey(it.Key())))
+			if strings.Contains(value, c.Operand.(string)) {
 				hashes = append(hashes, it.Value())
 			}
 		}
@@ -359,7 +414,9 @@ func (txi *TxIndex) match(c query.Condition, startKeyBz []byte) (hashes [][]byte
 	return
 }
 
-func (txi *TxIndex) matchRange(r queryRange, startKey []byte) (hashes [][]byte) {
+// matchRange returns the hashes of the txs in the range, or some of them if the
+// context is canceled meanwhile.
+func (txi *TxIndex) matchRange(ctx context.Context, r queryRange, startKey []byte) (hashes [][]byte) {
 	// create a map to prevent duplicates
 	hashesMap := make(map[string][]byte)
 
@@ -369,7 +426,7 @@ func (txi *TxIndex) matchRange(r queryRange, startKey []byte) (hashes [][]byte)
 	it := dbm.IteratePrefix(txi.store, startKey)
 	defer it.Close()
 LOOP:
-	for ; it.Valid(); it.Next() {
+	for ; it.Valid() && ctx.Err() == nil; it.Next() {
 		if !isTagKey(it.Key()) {
 			continue
 		}
@@ -418,10 +475,10 @@ func extractValueFromKey(key []byte) string {
 	return parts[1]
 }
 
-func keyForTag(tag cmn.KVPair, result *types.TxResult) []byte {
+func keyForEvent(key string, value []byte, result *types.TxResult) []byte {
 	return []byte(fmt.Sprintf("%s/%s/%d/%d",
-		tag.Key,
-		tag.Value,
+		key,
+		value,
 		result.Height,
 		result.Index,
 	))
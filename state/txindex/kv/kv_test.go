@@ -1,6 +1,7 @@
 package kv
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -21,7 +22,7 @@ func TestTxIndex(t *testing.T) {
 	indexer := NewTxIndex(db.NewMemDB())
 
 	tx := types.Tx("HELLO WORLD")
-	txResult := &types.TxResult{1, 0, tx, abci.ResponseDeliverTx{Data: []byte{0}, Code: abci.CodeTypeOK, Log: "", Tags: nil}}
+	txResult := &types.TxResult{1, 0, tx, abci.ResponseDeliverTx{Data: []byte{0}, Code: abci.CodeTypeOK, Log: "", Events: nil}}
 	hash := tx.Hash()
 
 	batch := txindex.NewBatch(1)
@@ -36,7 +37,7 @@ func TestTxIndex(t *testing.T) {
 	assert.Equal(t, txResult, loadedTxResult)
 
 	tx2 := types.Tx("BYE BYE WORLD")
-	txResult2 := &types.TxResult{1, 0, tx2, abci.ResponseDeliverTx{Data: []byte{0}, Code: abci.CodeTypeOK, Log: "", Tags: nil}}
+	txResult2 := &types.TxResult{1, 0, tx2, abci.ResponseDeliverTx{Data: []byte{0}, Code: abci.CodeTypeOK, Log: "", Events: nil}}
 	hash2 := tx2.Hash()
 
 	err = indexer.Index(txResult2)
@@ -51,10 +52,12 @@ func TestTxSearch(t *testing.T) {
 	allowedTags := []string{"account.number", "account.owner", "account.date"}
 	indexer := NewTxIndex(db.NewMemDB(), IndexTags(allowedTags))
 
-	txResult := txResultWithTags([]cmn.KVPair{
-		{Key: []byte("account.number"), Value: []byte("1")},
-		{Key: []byte("account.owner"), Value: []byte("Ivan")},
-		{Key: []byte("not_allowed"), Value: []byte("Vlad")},
+	txResult := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []cmn.KVPair{
+			{Key: []byte("number"), Value: []byte("1")},
+			{Key: []byte("owner"), Value: []byte("Ivan")},
+		}},
+		{Type: "", Attributes: []cmn.KVPair{{Key: []byte("not_allowed"), Value: []byte("Vlad")}}},
 	})
 	hash := txResult.Tx.Hash()
 
@@ -97,7 +100,7 @@ func TestTxSearch(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.q, func(t *testing.T) {
-			results, err := indexer.Search(query.MustParse(tc.q))
+			results, err := indexer.Search(context.Background(), query.MustParse(tc.q))
 			assert.NoError(t, err)
 
 			assert.Len(t, results, tc.resultsLength)
@@ -112,15 +115,15 @@ func TestTxSearchOneTxWithMultipleSameTagsButDifferentValues(t *testing.T) {
 	allowedTags := []string{"account.number"}
 	indexer := NewTxIndex(db.NewMemDB(), IndexTags(allowedTags))
 
-	txResult := txResultWithTags([]cmn.KVPair{
-		{Key: []byte("account.number"), Value: []byte("1")},
-		{Key: []byte("account.number"), Value: []byte("2")},
+	txResult := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []cmn.KVPair{{Key: []byte("number"), Value: []byte("1")}}},
+		{Type: "account", Attributes: []cmn.KVPair{{Key: []byte("number"), Value: []byte("2")}}},
 	})
 
 	err := indexer.Index(txResult)
 	require.NoError(t, err)
 
-	results, err := indexer.Search(query.MustParse("account.number >= 1"))
+	results, err := indexer.Search(context.Background(), query.MustParse("account.number >= 1"))
 	assert.NoError(t, err)
 
 	assert.Len(t, results, 1)
@@ -132,8 +135,8 @@ func TestTxSearchMultipleTxs(t *testing.T) {
 	indexer := NewTxIndex(db.NewMemDB(), IndexTags(allowedTags))
 
 	// indexed first, but bigger height (to test the order of transactions)
-	txResult := txResultWithTags([]cmn.KVPair{
-		{Key: []byte("account.number"), Value: []byte("1")},
+	txResult := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []cmn.KVPair{{Key: []byte("number"), Value: []byte("1")}}},
 	})
 	txResult.Tx = types.Tx("Bob's account")
 	txResult.Height = 2
@@ -142,8 +145,8 @@ func TestTxSearchMultipleTxs(t *testing.T) {
 	require.NoError(t, err)
 
 	// indexed second, but smaller height (to test the order of transactions)
-	txResult2 := txResultWithTags([]cmn.KVPair{
-		{Key: []byte("account.number"), Value: []byte("2")},
+	txResult2 := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []cmn.KVPair{{Key: []byte("number"), Value: []byte("2")}}},
 	})
 	txResult2.Tx = types.Tx("Alice's account")
 	txResult2.Height = 1
@@ -153,8 +156,8 @@ func TestTxSearchMultipleTxs(t *testing.T) {
 	require.NoError(t, err)
 
 	// indexed third (to test the order of transactions)
-	txResult3 := txResultWithTags([]cmn.KVPair{
-		{Key: []byte("account.number"), Value: []byte("3")},
+	txResult3 := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []cmn.KVPair{{Key: []byte("number"), Value: []byte("3")}}},
 	})
 	txResult3.Tx = types.Tx("Jack's account")
 	txResult3.Height = 1
@@ -164,8 +167,8 @@ func TestTxSearchMultipleTxs(t *testing.T) {
 
 	// indexed fourth (to test we don't include txs with similar tags)
 	// https://github.com/tendermint/tendermint/issues/2908
-	txResult4 := txResultWithTags([]cmn.KVPair{
-		{Key: []byte("account.number.id"), Value: []byte("1")},
+	txResult4 := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []cmn.KVPair{{Key: []byte("number.id"), Value: []byte("1")}}},
 	})
 	txResult4.Tx = types.Tx("Mike's account")
 	txResult4.Height = 2
@@ -173,7 +176,7 @@ func TestTxSearchMultipleTxs(t *testing.T) {
 	err = indexer.Index(txResult4)
 	require.NoError(t, err)
 
-	results, err := indexer.Search(query.MustParse("account.number >= 1"))
+	results, err := indexer.Search(context.Background(), query.MustParse("account.number >= 1"))
 	assert.NoError(t, err)
 
 	require.Len(t, results, 3)
@@ -183,36 +186,100 @@ func TestTxSearchMultipleTxs(t *testing.T) {
 func TestIndexAllTags(t *testing.T) {
 	indexer := NewTxIndex(db.NewMemDB(), IndexAllTags())
 
-	txResult := txResultWithTags([]cmn.KVPair{
-		{Key: []byte("account.owner"), Value: []byte("Ivan")},
-		{Key: []byte("account.number"), Value: []byte("1")},
+	txResult := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []cmn.KVPair{
+			{Key: []byte("owner"), Value: []byte("Ivan")},
+			{Key: []byte("number"), Value: []byte("1")},
+		}},
 	})
 
 	err := indexer.Index(txResult)
 	require.NoError(t, err)
 
-	results, err := indexer.Search(query.MustParse("account.number >= 1"))
+	results, err := indexer.Search(context.Background(), query.MustParse("account.number >= 1"))
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
 	assert.Equal(t, []*types.TxResult{txResult}, results)
 
-	results, err = indexer.Search(query.MustParse("account.owner = 'Ivan'"))
+	results, err = indexer.Search(context.Background(), query.MustParse("account.owner = 'Ivan'"))
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
 	assert.Equal(t, []*types.TxResult{txResult}, results)
 }
 
-func txResultWithTags(tags []cmn.KVPair) *types.TxResult {
+func TestTxSearchTagEncoding(t *testing.T) {
+	indexer := NewTxIndex(db.NewMemDB(), IndexAllTags(), TagEncoding(types.TagEncodingHex))
+
+	txResult := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []cmn.KVPair{{Key: []byte("owner"), Value: []byte{0xca, 0xfe, 0xba, 0xbe}}}},
+	})
+	err := indexer.Index(txResult)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		q             string
+		resultsLength int
+	}{
+		{"account.owner = 'CAFEBABE'", 1},
+		{"account.owner = 'cafebabe'", 1},
+		{"account.owner = 'CAFE'", 0},
+		{"account.owner CONTAINS 'FEBA'", 1},
+		{"account.owner CONTAINS 'feba'", 0},
+		{"tx.height = 1 AND account.owner = 'CAFEBABE'", 1},
+	}
+	for _, tc := range testCases {
+		results, err := indexer.Search(context.Background(), query.MustParse(tc.q))
+		assert.NoError(t, err, tc.q)
+		assert.Len(t, results, tc.resultsLength, tc.q)
+	}
+
+	_, err = indexer.Search(context.Background(), query.MustParse("account.owner = 'xyz'"))
+	assert.Error(t, err)
+}
+
+// the events of the tags of the previous versions of ABCI are indexed by the
+// keys of the tags
+func TestIndexEventsFromTags(t *testing.T) {
+	indexer := NewTxIndex(db.NewMemDB(), IndexTags([]string{"account.owner", "fee"}))
+
+	txResult := txResultWithEvents(abci.EventsFromTags([]cmn.KVPair{
+		{Key: []byte("account.owner"), Value: []byte("Ivan")},
+		{Key: []byte("fee"), Value: []byte("1")},
+	}))
+	err := indexer.Index(txResult)
+	require.NoError(t, err)
+
+	results, err := indexer.Search(context.Background(), query.MustParse("account.owner = 'Ivan' AND fee = 1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []*types.TxResult{txResult}, results)
+}
+
+func TestTxSearchCanceled(t *testing.T) {
+	indexer := NewTxIndex(db.NewMemDB(), IndexAllTags())
+	txResult := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []cmn.KVPair{{Key: []byte("number"), Value: []byte("1")}}},
+	})
+	require.NoError(t, indexer.Index(txResult))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := indexer.Search(ctx, query.MustParse("account.number >= 1"))
+	assert.Equal(t, context.Canceled, err)
+	_, err = indexer.Search(ctx, query.MustParse("account.number = 1"))
+	assert.Equal(t, context.Canceled, err)
+}
+
+func txResultWithEvents(events []abci.Event) *types.TxResult {
 	tx := types.Tx("HELLO WORLD")
 	return &types.TxResult{
 		Height: 1,
 		Index:  0,
 		Tx:     tx,
 		Result: abci.ResponseDeliverTx{
-			Data: []byte{0},
-			Code: abci.CodeTypeOK,
-			Log:  "",
-			Tags: tags,
+			Data:   []byte{0},
+			Code:   abci.CodeTypeOK,
+			Log:    "",
+			Events: events,
 		},
 	}
 }
@@ -236,10 +303,10 @@ func benchmarkTxIndex(txsCount int64, b *testing.B) {
 			Index:  txIndex,
 			Tx:     tx,
 			Result: abci.ResponseDeliverTx{
-				Data: []byte{0},
-				Code: abci.CodeTypeOK,
-				Log:  "",
-				Tags: []cmn.KVPair{},
+				Data:   []byte{0},
+				Code:   abci.CodeTypeOK,
+				Log:    "",
+				Events: []abci.Event{},
 			},
 		}
 		if err := batch.Add(txResult); err != nil {
@@ -0,0 +1,227 @@
+package uptime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	cmn "github.com/tendermint/tendermint/libs/common"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	subscriber = "UptimeTracker"
+)
+
+// ValidatorUptime is the number of blocks a validator signed and missed in
+// the window of the Tracker, among the blocks of the window it was a
+// validator at.
+type ValidatorUptime struct {
+	Address          types.Address `json:"address"`
+	SignedBlocks     int           `json:"signed_blocks"`
+	MissedBlocks     int           `json:"missed_blocks"`
+	LastSignedHeight int64         `json:"last_signed_height"`
+}
+
+// heightSigners are the validators which signed and missed the commit of a
+// height.
+type heightSigners struct {
+	height int64
+	signed []types.Address
+	missed []types.Address
+}
+
+// Tracker keeps a rolling window of the blocks each validator signed and
+// missed over the last committed heights, using the commits of the blocks.
+//
+// At start, the window is filled with the commits of the block store. Then,
+// the tracker follows the NewBlock events: a block carries the commit of the
+// previous height.
+type Tracker struct {
+	cmn.BaseService
+
+	window     int
+	stateDB    dbm.DB
+	blockStore sm.BlockStoreRPC
+	eventBus   *types.EventBus
+
+	mtx        sync.Mutex
+	heights    []heightSigners // ring buffer of the last window heights
+	next       int             // index in heights of the next height
+	validators map[string]*ValidatorUptime
+	lastHeight int64
+}
+
+// NewTracker returns a tracker over the last window heights. window must be
+// positive.
+func NewTracker(window int, stateDB dbm.DB, blockStore sm.BlockStoreRPC, eventBus *types.EventBus) *Tracker {
+	t := &Tracker{
+		window:     window,
+		stateDB:    stateDB,
+		blockStore: blockStore,
+		eventBus:   eventBus,
+		heights:    make([]heightSigners, 0, window),
+		validators: make(map[string]*ValidatorUptime),
+	}
+	t.BaseService = *cmn.NewBaseService(nil, "UptimeTracker", t)
+	return t
+}
+
+// OnStart implements cmn.Service by filling the window from the block store
+// and following the new blocks.
+func (t *Tracker) OnStart() error {
+	blocksCh := make(chan interface{}, 1)
+	if err := t.eventBus.Subscribe(context.Background(), subscriber, types.EventQueryNewBlock, blocksCh); err != nil {
+		return err
+	}
+
+	// The commit of the last height is only known as the seen commit, which
+	// may differ from the canonical one: it comes with the next block.
+	fromHeight := t.blockStore.Height() - int64(t.window)
+	if fromHeight < 1 {
+		fromHeight = 1
+	}
+	for height := fromHeight; height < t.blockStore.Height(); height++ {
+		if err := t.trackCommit(height, t.blockStore.LoadBlockCommit(height)); err != nil {
+			t.Logger.Error("Failed to track the commit of a stored block", "height", height, "err", err)
+		}
+	}
+
+	go t.trackRoutine(blocksCh)
+	return nil
+}
+
+// OnStop implements cmn.Service by unsubscribing from the new blocks.
+func (t *Tracker) OnStop() {
+	if t.eventBus.IsRunning() {
+		_ = t.eventBus.UnsubscribeAll(context.Background(), subscriber)
+	}
+}
+
+func (t *Tracker) trackRoutine(blocksCh <-chan interface{}) {
+	for {
+		select {
+		case e, ok := <-blocksCh:
+			if !ok {
+				return
+			}
+			block := e.(types.EventDataNewBlock).Block
+			if block.Height <= 1 {
+				continue // no last commit
+			}
+			if err := t.trackCommit(block.Height-1, block.LastCommit); err != nil {
+				t.Logger.Error("Failed to track the last commit of a block", "height", block.Height, "err", err)
+			}
+		case <-t.Quit():
+			return
+		}
+	}
+}
+
+func (t *Tracker) trackCommit(height int64, commit *types.Commit) error {
+	if commit == nil {
+		return fmt.Errorf("no commit for height %d", height)
+	}
+	vals, err := sm.LoadValidators(t.stateDB, height)
+	if err != nil {
+		return err
+	}
+	return t.track(height, vals, commit)
+}
+
+// track adds the commit of height, signed by vals, to the window, evicting
+// the oldest height if the window is full.
+func (t *Tracker) track(height int64, vals *types.ValidatorSet, commit *types.Commit) error {
+	if vals.Size() != commit.Size() {
+		return fmt.Errorf("commit has %d precommits, but there are %d validators", commit.Size(), vals.Size())
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if height <= t.lastHeight {
+		return nil // already tracked
+	}
+
+	hs := heightSigners{height: height}
+	for idx, val := range vals.Validators {
+		vu, ok := t.validators[string(val.Address)]
+		if !ok {
+			vu = &ValidatorUptime{Address: val.Address}
+			t.validators[string(val.Address)] = vu
+		}
+		if commit.Precommits[idx] != nil {
+			vu.SignedBlocks++
+			vu.LastSignedHeight = height
+			hs.signed = append(hs.signed, val.Address)
+		} else {
+			vu.MissedBlocks++
+			hs.missed = append(hs.missed, val.Address)
+		}
+	}
+
+	if len(t.heights) < t.window {
+		t.heights = append(t.heights, hs)
+	} else {
+		t.evict(t.heights[t.next])
+		t.heights[t.next] = hs
+	}
+	t.next = (t.next + 1) % t.window
+	t.lastHeight = height
+	return nil
+}
+
+// evict removes the signers of an old height from the counts.
+func (t *Tracker) evict(hs heightSigners) {
+	for _, addr := range hs.signed {
+		t.validators[string(addr)].SignedBlocks--
+		t.deleteIfUntracked(addr)
+	}
+	for _, addr := range hs.missed {
+		t.validators[string(addr)].MissedBlocks--
+		t.deleteIfUntracked(addr)
+	}
+}
+
+// deleteIfUntracked deletes the validator once none of its blocks is in the
+// window.
+func (t *Tracker) deleteIfUntracked(addr types.Address) {
+	vu := t.validators[string(addr)]
+	if vu.SignedBlocks == 0 && vu.MissedBlocks == 0 {
+		delete(t.validators, string(addr))
+	}
+}
+
+// Window returns the maximum number of heights of the window.
+func (t *Tracker) Window() int {
+	return t.window
+}
+
+// Uptime returns the first and last heights of the window, and the uptime of
+// the validators over it, sorted by address.
+func (t *Tracker) Uptime() (fromHeight, toHeight int64, validators []ValidatorUptime) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	validators = make([]ValidatorUptime, 0, len(t.validators))
+	for _, vu := range t.validators {
+		validators = append(validators, *vu)
+	}
+	sort.Slice(validators, func(i, j int) bool {
+		return bytes.Compare(validators[i].Address, validators[j].Address) < 0
+	})
+
+	if len(t.heights) == 0 {
+		return 0, 0, validators
+	}
+	// The oldest height is the next to be overwritten.
+	oldest := 0
+	if len(t.heights) == t.window {
+		oldest = t.next
+	}
+	return t.heights[oldest].height, t.lastHeight, validators
+}
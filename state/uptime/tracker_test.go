@@ -0,0 +1,72 @@
+package uptime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// makeCommit returns a commit signed by the validators for which signed is
+// true.
+func makeCommit(signed ...bool) *types.Commit {
+	commit := &types.Commit{Precommits: make([]*types.CommitSig, len(signed))}
+	for i, s := range signed {
+		if s {
+			commit.Precommits[i] = &types.CommitSig{}
+		}
+	}
+	return commit
+}
+
+func uptimeByAddress(t *Tracker) map[string]ValidatorUptime {
+	_, _, validators := t.Uptime()
+	m := make(map[string]ValidatorUptime, len(validators))
+	for _, vu := range validators {
+		m[string(vu.Address)] = vu
+	}
+	return m
+}
+
+func TestTrackerWindow(t *testing.T) {
+	vals, _ := types.RandValidatorSet(3, 10)
+	val0, val1, val2 := vals.Validators[0].Address, vals.Validators[1].Address, vals.Validators[2].Address
+	lastVal := types.NewValidatorSet([]*types.Validator{vals.Validators[2].Copy()})
+
+	tracker := NewTracker(2, nil, nil, nil)
+	require.NoError(t, tracker.track(1, vals, makeCommit(true, true, true)))
+	require.NoError(t, tracker.track(2, vals, makeCommit(false, true, true)))
+	require.NoError(t, tracker.track(3, vals, makeCommit(true, false, true)))
+
+	// Height 1 is out of the window.
+	fromHeight, toHeight, _ := tracker.Uptime()
+	assert.EqualValues(t, 2, fromHeight)
+	assert.EqualValues(t, 3, toHeight)
+	uptime := uptimeByAddress(tracker)
+	assert.Equal(t, ValidatorUptime{val0, 1, 1, 3}, uptime[string(val0)])
+	assert.Equal(t, ValidatorUptime{val1, 1, 1, 2}, uptime[string(val1)])
+	assert.Equal(t, ValidatorUptime{val2, 2, 0, 3}, uptime[string(val2)])
+
+	// Already tracked heights are ignored.
+	require.NoError(t, tracker.track(3, vals, makeCommit(false, false, false)))
+	assert.Equal(t, ValidatorUptime{val2, 2, 0, 3}, uptimeByAddress(tracker)[string(val2)])
+
+	// The commit must match the validators.
+	assert.Error(t, tracker.track(4, vals, makeCommit(true)))
+
+	// Validators which left are counted until their last height leaves the
+	// window.
+	require.NoError(t, tracker.track(4, lastVal, makeCommit(true)))
+	uptime = uptimeByAddress(tracker)
+	assert.Len(t, uptime, 3)
+	assert.Equal(t, ValidatorUptime{val0, 1, 0, 3}, uptime[string(val0)])
+	assert.Equal(t, ValidatorUptime{val1, 0, 1, 2}, uptime[string(val1)])
+
+	require.NoError(t, tracker.track(5, lastVal, makeCommit(false)))
+	fromHeight, toHeight, validators := tracker.Uptime()
+	assert.EqualValues(t, 4, fromHeight)
+	assert.EqualValues(t, 5, toHeight)
+	assert.Equal(t, []ValidatorUptime{{val2, 1, 1, 4}}, validators)
+}
@@ -100,7 +100,7 @@ func validateBlock(evidencePool EvidencePool, stateDB dbm.DB, state State, block
 			)
 		}
 		err := state.LastValidators.VerifyCommit(
-			state.ChainID, state.LastBlockID, block.Height-1, block.LastCommit)
+			state.ChainID, state.SignDomain, state.LastBlockID, block.Height-1, block.LastCommit)
 		if err != nil {
 			return err
 		}
@@ -197,7 +197,7 @@ func VerifyEvidence(stateDB dbm.DB, state State, evidence types.Evidence) error
 		return fmt.Errorf("Address %X was not a validator at height %d", addr, height)
 	}
 
-	if err := evidence.Verify(state.ChainID, val.PubKey); err != nil {
+	if err := evidence.Verify(state.ChainID, state.SignDomain, val.PubKey); err != nil {
 		return err
 	}
 
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	tmrpc "github.com/tendermint/tendermint/rpc/client"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	latencySubscriber = "tm-bench"
+
+	// once the txs are sent, we wait for the remaining ones to be committed
+	// until no tx was committed for latencyIdleTimeout, at most
+	// latencyDrainTimeout.
+	latencyIdleTimeout  = 3 * time.Second
+	latencyDrainTimeout = 30 * time.Second
+)
+
+// latencyRecorder records the commit latencies of the txs we send, from the
+// send time embedded in the txs to the time we receive their Tx events.
+type latencyRecorder struct {
+	client       *tmrpc.HTTP
+	hostnameHash [16]byte
+	latencies    metrics.Histogram
+
+	txsCh  chan interface{}
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+func newLatencyRecorder(client *tmrpc.HTTP) *latencyRecorder {
+	r := &latencyRecorder{
+		client:    client,
+		latencies: metrics.NewHistogram(metrics.NewUniformSample(10000)),
+		txsCh:     make(chan interface{}, 1000),
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}
+	hash := hostnameHash()
+	copy(r.hostnameHash[:], hash[:16])
+	return r
+}
+
+// Start subscribes to the txs committed on the endpoint of the client.
+func (r *latencyRecorder) Start() error {
+	if err := r.client.Start(); err != nil {
+		return err
+	}
+	if err := r.client.Subscribe(context.Background(), latencySubscriber, types.EventQueryTx, r.txsCh); err != nil {
+		return err
+	}
+	go r.recordLoop()
+	return nil
+}
+
+// Stop waits for the txs sent to be committed, and unsubscribes. The txs
+// which aren't committed by then are ignored.
+func (r *latencyRecorder) Stop() {
+	close(r.stopCh)
+	<-r.doneCh
+	if err := r.client.UnsubscribeAll(context.Background(), latencySubscriber); err != nil {
+		logger.Error("Failed to unsubscribe", "err", err)
+	}
+	r.client.Stop()
+}
+
+func (r *latencyRecorder) recordLoop() {
+	defer close(r.doneCh)
+
+	var (
+		stopCh = r.stopCh
+		idle   <-chan time.Time
+		drain  <-chan time.Time
+	)
+	for {
+		select {
+		case data := <-r.txsCh:
+			if event, ok := data.(types.EventDataTx); ok {
+				r.record(event.Tx, time.Now())
+			}
+			if idle != nil {
+				idle = time.After(latencyIdleTimeout)
+			}
+		case <-stopCh:
+			stopCh = nil
+			idle = time.After(latencyIdleTimeout)
+			drain = time.After(latencyDrainTimeout)
+		case <-idle:
+			return
+		case <-drain:
+			return
+		}
+	}
+}
+
+// record records the latency of the tx if we sent it.
+func (r *latencyRecorder) record(tx types.Tx, committed time.Time) {
+	if len(tx) < 40 || !bytes.Equal(tx[16:32], r.hostnameHash[:]) {
+		return
+	}
+	sent := time.Unix(0, int64(binary.BigEndian.Uint64(tx[32:40])))
+	r.latencies.Update(int64(committed.Sub(sent) / time.Millisecond))
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tmrpc "github.com/tendermint/tendermint/rpc/client"
+)
+
+func TestLatencyRecorderRecord(t *testing.T) {
+	r := newLatencyRecorder(tmrpc.NewHTTP("localhost:26657", "/websocket"))
+
+	tx := generateTx(0, 0, 100, hostnameHash())
+	txHex := make([]byte, len(tx)*2)
+	hex.Encode(txHex, tx)
+
+	sent := time.Now()
+	updateTxTime(tx, txHex, sent)
+	require.Equal(t, hex.EncodeToString(tx), string(txHex))
+
+	r.record(tx, sent.Add(1500*time.Millisecond))
+	// txs of other hosts or tools are ignored
+	r.record(make([]byte, 100), sent.Add(time.Second))
+	r.record([]byte("key=value"), sent.Add(time.Second))
+
+	assert.EqualValues(t, 1, r.latencies.Count())
+	assert.EqualValues(t, 1500, r.latencies.Max())
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -20,7 +21,7 @@ var logger = log.NewNopLogger()
 
 func main() {
 	var durationInt, txsRate, connections, txSize int
-	var verbose bool
+	var verbose, latency bool
 	var outputFormat, broadcastTxMethod string
 
 	flagSet := flag.NewFlagSet("tm-bench", flag.ExitOnError)
@@ -30,13 +31,14 @@ func main() {
 	flagSet.IntVar(&txSize, "s", 250, "The size of a transaction in bytes, must be greater than or equal to 40.")
 	flagSet.StringVar(&outputFormat, "output-format", "plain", "Output format: plain or json")
 	flagSet.StringVar(&broadcastTxMethod, "broadcast-tx-method", "async", "Broadcast method: async (no guarantees; fastest), sync (ensures tx is checked) or commit (ensures tx is checked and committed; slowest)")
+	flagSet.BoolVar(&latency, "latency", false, "Measure the commit latency of the txs, subscribing to them on the first endpoint")
 	flagSet.BoolVar(&verbose, "v", false, "Verbose output")
 
 	flagSet.Usage = func() {
 		fmt.Println(`Tendermint blockchain benchmarking tool.
 
 Usage:
-	tm-bench [-c 1] [-T 10] [-r 1000] [-s 250] [endpoints] [-output-format <plain|json> [-broadcast-tx-method <async|sync|commit>]]
+	tm-bench [-c 1] [-T 10] [-r 1000] [-s 250] [-latency] [endpoints] [-output-format <plain|json> [-broadcast-tx-method <async|sync|commit>]]
 
 Examples:
 	tm-bench localhost:26657`)
@@ -86,6 +88,14 @@ Examples:
 	)
 	logger.Info("Latest block height", "h", initialHeight)
 
+	var recorder *latencyRecorder
+	if latency {
+		recorder = newLatencyRecorder(tmrpc.NewHTTP(endpoints[0], "/websocket"))
+		if err := recorder.Start(); err != nil {
+			printErrorAndExit(err.Error())
+		}
+	}
+
 	transacters := startTransacters(
 		endpoints,
 		connections,
@@ -127,6 +137,10 @@ Examples:
 
 	logger.Debug("Time all transacters stopped", "t", time.Now())
 
+	if recorder != nil {
+		recorder.Stop()
+	}
+
 	stats, err := calculateStatistics(
 		client,
 		initialHeight,
@@ -136,12 +150,15 @@ Examples:
 	if err != nil {
 		printErrorAndExit(err.Error())
 	}
+	if recorder != nil {
+		stats.Latency = recorder.latencies
+	}
 
 	printStatistics(stats, outputFormat)
 }
 
 func latestBlockHeight(client tmrpc.Client) int64 {
-	status, err := client.Status()
+	status, err := client.Status(context.Background())
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
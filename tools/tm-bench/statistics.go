@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -16,6 +17,7 @@ import (
 type statistics struct {
 	TxsThroughput    metrics.Histogram `json:"txs_per_sec"`
 	BlocksThroughput metrics.Histogram `json:"blocks_per_sec"`
+	Latency          metrics.Histogram `json:"latency_ms"` // nil if not measured
 }
 
 // calculateStatistics calculates the tx / second, and blocks / second based
@@ -82,7 +84,7 @@ func calculateStatistics(
 func getBlockMetas(client tmrpc.Client, minHeight int64, timeStart, timeEnd time.Time) ([]*types.BlockMeta, error) {
 	// get blocks between minHeight and last height
 	// This returns max(minHeight,(last_height - 20)) to last_height
-	info, err := client.BlockchainInfo(minHeight, 0)
+	info, err := client.BlockchainInfo(context.Background(), minHeight, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +98,7 @@ func getBlockMetas(client tmrpc.Client, minHeight int64, timeStart, timeEnd time
 
 	for offset < int(diff) {
 		// get blocks between minHeight and last height
-		info, err := client.BlockchainInfo(minHeight, lastHeight-int64(offset))
+		info, err := client.BlockchainInfo(context.Background(), minHeight, lastHeight-int64(offset))
 		if err != nil {
 			return nil, err
 		}
@@ -113,16 +115,24 @@ func secondsSinceTimeStart(timeStart, timePassed time.Time) int64 {
 
 func printStatistics(stats *statistics, outputFormat string) {
 	if outputFormat == "json" {
-		result, err := json.Marshal(struct {
-			TxsThroughput    float64 `json:"txs_per_sec_avg"`
-			BlocksThroughput float64 `json:"blocks_per_sec_avg"`
-		}{stats.TxsThroughput.Mean(), stats.BlocksThroughput.Mean()})
+		result := struct {
+			TxsThroughput    float64         `json:"txs_per_sec_avg"`
+			BlocksThroughput float64         `json:"blocks_per_sec_avg"`
+			Latency          *latencySummary `json:"latency_ms,omitempty"`
+		}{
+			TxsThroughput:    stats.TxsThroughput.Mean(),
+			BlocksThroughput: stats.BlocksThroughput.Mean(),
+		}
+		if stats.Latency != nil {
+			result.Latency = summarizeLatency(stats.Latency)
+		}
+		resultJSON, err := json.Marshal(result)
 
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
-		fmt.Println(string(result))
+		fmt.Println(string(resultJSON))
 	} else {
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 5, ' ', 0)
 		fmt.Fprintln(w, "Stats\tAvg\tStdDev\tMax\tTotal\t")
@@ -145,6 +155,46 @@ func printStatistics(stats *statistics, outputFormat string) {
 				stats.BlocksThroughput.Sum(),
 			),
 		)
+		var l *latencySummary
+		if stats.Latency != nil {
+			l = summarizeLatency(stats.Latency)
+			fmt.Fprintln(
+				w,
+				fmt.Sprintf("Latency (ms)\t%.0f\t%.0f\t%d\t%d\t",
+					l.Avg,
+					l.StdDev,
+					l.Max,
+					l.Count,
+				),
+			)
+		}
 		w.Flush()
+		if l != nil {
+			fmt.Printf("Latency percentiles (ms): p50 %.0f, p90 %.0f, p99 %.0f\n", l.P50, l.P90, l.P99)
+		}
+	}
+}
+
+// latencySummary summarizes the commit latencies of the txs, in milliseconds.
+type latencySummary struct {
+	Count  int64   `json:"count"`
+	Avg    float64 `json:"avg"`
+	StdDev float64 `json:"stddev"`
+	Max    int64   `json:"max"`
+	P50    float64 `json:"p50"`
+	P90    float64 `json:"p90"`
+	P99    float64 `json:"p99"`
+}
+
+func summarizeLatency(latency metrics.Histogram) *latencySummary {
+	ps := latency.Percentiles([]float64{0.5, 0.9, 0.99})
+	return &latencySummary{
+		Count:  latency.Count(),
+		Avg:    latency.Mean(),
+		StdDev: latency.StdDev(),
+		Max:    latency.Max(),
+		P50:    ps[0],
+		P90:    ps[1],
+		P99:    ps[2],
 	}
 }
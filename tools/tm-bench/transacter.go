@@ -156,16 +156,9 @@ func (t *transacter) sendLoop(connIndex int) {
 		t.endingWg.Done()
 	}()
 
-	// hash of the host name is a part of each tx
-	var hostnameHash [sha256.Size]byte
-	hostname, err := os.Hostname()
-	if err != nil {
-		hostname = "127.0.0.1"
-	}
-	hostnameHash = sha256.Sum256([]byte(hostname))
-	// each transaction embeds connection index, tx number and hash of the hostname
-	// we update the tx number between successive txs
-	tx := generateTx(connIndex, txNumber, t.Size, hostnameHash)
+	// each transaction embeds connection index, tx number, hash of the hostname
+	// and send time. we update the tx number and send time between successive txs
+	tx := generateTx(connIndex, txNumber, t.Size, hostnameHash())
 	txHex := make([]byte, len(tx)*2)
 	hex.Encode(txHex, tx)
 
@@ -180,10 +173,12 @@ func (t *transacter) sendLoop(connIndex int) {
 				started = true
 			}
 
-			now := time.Now()
 			for i := 0; i < t.Rate; i++ {
 				// update tx number of the tx, and the corresponding hex
 				updateTx(tx, txHex, txNumber)
+				// the send time of each tx, read right before writing it
+				now := time.Now()
+				updateTxTime(tx, txHex, now)
 				paramsJSON, err := json.Marshal(map[string]interface{}{"tx": txHex})
 				if err != nil {
 					fmt.Printf("failed to encode params: %v\n", err)
@@ -206,14 +201,10 @@ func (t *transacter) sendLoop(connIndex int) {
 					return
 				}
 
-				// cache the time.Now() reads to save time.
-				if i%5 == 0 {
-					now = time.Now()
-					if now.After(endTime) {
-						// Plus one accounts for sending this tx
-						numTxSent = i + 1
-						break
-					}
+				if now.After(endTime) {
+					// Plus one accounts for sending this tx
+					numTxSent = i + 1
+					break
 				}
 
 				txNumber++
@@ -260,13 +251,22 @@ func connect(host string) (*websocket.Conn, *http.Response, error) {
 	return websocket.DefaultDialer.Dial(u.String(), nil)
 }
 
+// hostnameHash returns the hash of the host name, which is a part of each tx.
+func hostnameHash() [sha256.Size]byte {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "127.0.0.1"
+	}
+	return sha256.Sum256([]byte(hostname))
+}
+
 func generateTx(connIndex int, txNumber int, txSize int, hostnameHash [sha256.Size]byte) []byte {
 	tx := make([]byte, txSize)
 
 	binary.PutUvarint(tx[:8], uint64(connIndex))
 	binary.PutUvarint(tx[8:16], uint64(txNumber))
 	copy(tx[16:32], hostnameHash[:16])
-	binary.PutUvarint(tx[32:40], uint64(time.Now().Unix()))
+	binary.BigEndian.PutUint64(tx[32:40], uint64(time.Now().UnixNano()))
 
 	// 40-* random data
 	if _, err := rand.Read(tx[40:]); err != nil { //nolint: gosec
@@ -285,3 +285,10 @@ func updateTx(tx []byte, txHex []byte, txNumber int) {
 		txHex[i] = hexUpdate[i-16]
 	}
 }
+
+// updateTxTime sets the send time of the tx, used to measure its commit
+// latency. warning, mutates input byte slice
+func updateTxTime(tx []byte, txHex []byte, sendTime time.Time) {
+	binary.BigEndian.PutUint64(tx[32:40], uint64(sendTime.UnixNano()))
+	hex.Encode(txHex[64:80], tx[32:40])
+}
@@ -52,6 +52,7 @@ type TestHarness struct {
 	spv              *privval.SocketVal
 	fpv              *privval.FilePV
 	chainID          string
+	domain           types.SignDomain
 	acceptRetries    int
 	logger           log.Logger
 	exitWhenComplete bool
@@ -111,6 +112,7 @@ func NewTestHarness(logger log.Logger, cfg TestHarnessConfig) (*TestHarness, err
 		spv:              spv,
 		fpv:              fpv,
 		chainID:          st.ChainID,
+		domain:           st.GetSignDomain(),
 		acceptRetries:    cfg.AcceptRetries,
 		logger:           logger,
 		exitWhenComplete: cfg.ExitWhenComplete,
@@ -210,8 +212,8 @@ func (th *TestHarness) TestSignProposal() error {
 		},
 		Timestamp: time.Now(),
 	}
-	propBytes := prop.SignBytes(th.chainID)
-	if err := th.spv.SignProposal(th.chainID, prop); err != nil {
+	propBytes := prop.SignBytes(th.chainID, th.domain)
+	if err := th.spv.SignProposal(th.chainID, th.domain, prop); err != nil {
 		th.logger.Error("FAILED: Signing of proposal", "err", err)
 		return newTestHarnessError(ErrTestSignProposalFailed, err, "")
 	}
@@ -253,9 +255,9 @@ func (th *TestHarness) TestSignVote() error {
 			ValidatorAddress: tmhash.SumTruncated([]byte("addr")),
 			Timestamp:        time.Now(),
 		}
-		voteBytes := vote.SignBytes(th.chainID)
+		voteBytes := vote.SignBytes(th.chainID, th.domain)
 		// sign the vote
-		if err := th.spv.SignVote(th.chainID, vote); err != nil {
+		if err := th.spv.SignVote(th.chainID, th.domain, vote); err != nil {
 			th.logger.Error("FAILED: Signing of vote", "err", err)
 			return newTestHarnessError(ErrTestSignVoteFailed, err, fmt.Sprintf("voteType=%d", voteType))
 		}
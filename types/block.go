@@ -531,8 +531,8 @@ func NewCommit(blockID BlockID, precommits []*CommitSig) *Commit {
 // VoteSignBytes constructs the SignBytes for the given CommitSig.
 // The only unique part of the SignBytes is the Timestamp - all other fields
 // signed over are otherwise the same for all validators.
-func (commit *Commit) VoteSignBytes(chainID string, cs *CommitSig) []byte {
-	return commit.ToVote(cs).SignBytes(chainID)
+func (commit *Commit) VoteSignBytes(chainID string, domain SignDomain, cs *CommitSig) []byte {
+	return commit.ToVote(cs).SignBytes(chainID, domain)
 }
 
 // memoizeHeightRound memoizes the height and round of the commit using
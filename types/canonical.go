@@ -23,13 +23,14 @@ type CanonicalPartSetHeader struct {
 }
 
 type CanonicalProposal struct {
-	Type      SignedMsgType // type alias for byte
-	Height    int64         `binary:"fixed64"`
-	Round     int64         `binary:"fixed64"`
-	POLRound  int64         `binary:"fixed64"`
-	BlockID   CanonicalBlockID
-	Timestamp time.Time
-	ChainID   string
+	Type              SignedMsgType // type alias for byte
+	Height            int64         `binary:"fixed64"`
+	Round             int64         `binary:"fixed64"`
+	POLRound          int64         `binary:"fixed64"`
+	BlockID           CanonicalBlockID
+	Timestamp         time.Time
+	ChainID           string
+	ParityPartsHeader *CanonicalPartSetHeader // nil if not erasure coded
 }
 
 type CanonicalVote struct {
@@ -59,7 +60,7 @@ func CanonicalizePartSetHeader(psh PartSetHeader) CanonicalPartSetHeader {
 }
 
 func CanonicalizeProposal(chainID string, proposal *Proposal) CanonicalProposal {
-	return CanonicalProposal{
+	cp := CanonicalProposal{
 		Type:      ProposalType,
 		Height:    proposal.Height,
 		Round:     int64(proposal.Round), // cast int->int64 to make amino encode it fixed64 (does not work for int)
@@ -68,6 +69,11 @@ func CanonicalizeProposal(chainID string, proposal *Proposal) CanonicalProposal
 		Timestamp: proposal.Timestamp,
 		ChainID:   chainID,
 	}
+	if proposal.ParityPartsHeader != nil {
+		psh := CanonicalizePartSetHeader(*proposal.ParityPartsHeader)
+		cp.ParityPartsHeader = &psh
+	}
+	return cp
 }
 
 func CanonicalizeVote(chainID string, vote *Vote) CanonicalVote {
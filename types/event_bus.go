@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	abci "github.com/tendermint/tendermint/abci/types"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	"github.com/tendermint/tendermint/libs/log"
 	tmpubsub "github.com/tendermint/tendermint/libs/pubsub"
@@ -22,7 +23,8 @@ type EventBusSubscriber interface {
 // EventBus to ensure correct data types.
 type EventBus struct {
 	cmn.BaseService
-	pubsub *tmpubsub.Server
+	pubsub      *tmpubsub.Server
+	tagEncoding TagEncoding
 }
 
 // NewEventBus returns a new event bus.
@@ -44,6 +46,17 @@ func (b *EventBus) SetLogger(l log.Logger) {
 	b.pubsub.SetLogger(l.With("module", "pubsub"))
 }
 
+// SetTagEncoding sets the encoding of the values of the tags matched by the
+// queries of the subscribers. Must be called before the bus is started.
+func (b *EventBus) SetTagEncoding(e TagEncoding) {
+	b.tagEncoding = e
+}
+
+// TagEncoding returns the encoding of the values of the tags.
+func (b *EventBus) TagEncoding() TagEncoding {
+	return b.tagEncoding
+}
+
 func (b *EventBus) OnStart() error {
 	return b.pubsub.Start()
 }
@@ -64,6 +77,12 @@ func (b *EventBus) UnsubscribeAll(ctx context.Context, subscriber string) error
 	return b.pubsub.UnsubscribeAll(ctx, subscriber)
 }
 
+// NumClientSubscriptions returns the number of subscriptions the subscriber
+// has.
+func (b *EventBus) NumClientSubscriptions(subscriber string) int {
+	return b.pubsub.NumClientSubscriptions(subscriber)
+}
+
 func (b *EventBus) Publish(eventType string, eventData TMEventData) error {
 	// no explicit deadline for publishing events
 	ctx := context.Background()
@@ -71,15 +90,19 @@ func (b *EventBus) Publish(eventType string, eventData TMEventData) error {
 	return nil
 }
 
-func (b *EventBus) validateAndStringifyTags(tags []cmn.KVPair, logger log.Logger) map[string]string {
+// validateAndStringifyEvents returns the tags of the attributes of the
+// events, by their composite keys (see abci.EventKey).
+func (b *EventBus) validateAndStringifyEvents(events []abci.Event, logger log.Logger) map[string]string {
 	result := make(map[string]string)
-	for _, tag := range tags {
-		// basic validation
-		if len(tag.Key) == 0 {
-			logger.Debug("Got tag with an empty key (skipping)", "tag", tag)
-			continue
+	for _, event := range events {
+		for _, attr := range event.Attributes {
+			// basic validation
+			if len(attr.Key) == 0 {
+				logger.Debug("Got event attribute with an empty key (skipping)", "event", event)
+				continue
+			}
+			result[abci.EventKey(event.Type, attr.Key)] = b.tagEncoding.EncodeToString(attr.Value)
 		}
-		result[string(tag.Key)] = string(tag.Value)
 	}
 	return result
 }
@@ -88,8 +111,8 @@ func (b *EventBus) PublishEventNewBlock(data EventDataNewBlock) error {
 	// no explicit deadline for publishing events
 	ctx := context.Background()
 
-	resultTags := append(data.ResultBeginBlock.Tags, data.ResultEndBlock.Tags...)
-	tags := b.validateAndStringifyTags(resultTags, b.Logger.With("block", data.Block.StringShort()))
+	resultEvents := append(data.ResultBeginBlock.Events, data.ResultEndBlock.Events...)
+	tags := b.validateAndStringifyEvents(resultEvents, b.Logger.With("block", data.Block.StringShort()))
 
 	// add predefined tags
 	logIfTagExists(EventTypeKey, tags, b.Logger)
@@ -103,9 +126,9 @@ func (b *EventBus) PublishEventNewBlockHeader(data EventDataNewBlockHeader) erro
 	// no explicit deadline for publishing events
 	ctx := context.Background()
 
-	resultTags := append(data.ResultBeginBlock.Tags, data.ResultEndBlock.Tags...)
+	resultEvents := append(data.ResultBeginBlock.Events, data.ResultEndBlock.Events...)
 	// TODO: Create StringShort method for Header and use it in logger.
-	tags := b.validateAndStringifyTags(resultTags, b.Logger.With("header", data.Header))
+	tags := b.validateAndStringifyEvents(resultEvents, b.Logger.With("header", data.Header))
 
 	// add predefined tags
 	logIfTagExists(EventTypeKey, tags, b.Logger)
@@ -123,14 +146,14 @@ func (b *EventBus) PublishEventValidBlock(data EventDataRoundState) error {
 	return b.Publish(EventValidBlock, data)
 }
 
-// PublishEventTx publishes tx event with tags from Result. Note it will add
+// PublishEventTx publishes tx event with the events from Result as tags. Note it will add
 // predefined tags (EventTypeKey, TxHashKey). Existing tags with the same names
 // will be overwritten.
 func (b *EventBus) PublishEventTx(data EventDataTx) error {
 	// no explicit deadline for publishing events
 	ctx := context.Background()
 
-	tags := b.validateAndStringifyTags(data.Result.Tags, b.Logger.With("tx", data.Tx))
+	tags := b.validateAndStringifyEvents(data.Result.Events, b.Logger.With("tx", data.Tx))
 
 	// add predefined tags
 	logIfTagExists(EventTypeKey, tags, b.Logger)
@@ -69,6 +69,17 @@ type EventDataNewBlock struct {
 	ResultEndBlock   abci.ResponseEndBlock   `json:"result_end_block"`
 }
 
+// HeaderOnly returns the data of the EventNewBlockHeader event for the block,
+// without its txs, evidence and last commit. The header still has the number
+// of txs of the block.
+func (d EventDataNewBlock) HeaderOnly() EventDataNewBlockHeader {
+	return EventDataNewBlockHeader{
+		Header:           d.Block.Header,
+		ResultBeginBlock: d.ResultBeginBlock,
+		ResultEndBlock:   d.ResultEndBlock,
+	}
+}
+
 // light weight event for benchmarking
 type EventDataNewBlockHeader struct {
 	Header Header `json:"header"`
@@ -95,6 +106,7 @@ type EventDataRoundState struct {
 type ValidatorInfo struct {
 	Address Address `json:"address"`
 	Index   int     `json:"index"`
+	Moniker string  `json:"moniker,omitempty"`
 }
 
 type EventDataNewRound struct {
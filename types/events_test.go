@@ -21,3 +21,12 @@ func TestQueryForEvent(t *testing.T) {
 		QueryForEvent(EventNewBlock).String(),
 	)
 }
+
+func TestEventDataNewBlockHeaderOnly(t *testing.T) {
+	block := MakeBlock(3, []Tx{Tx("foo"), Tx("bar")}, nil, nil)
+	data := EventDataNewBlock{Block: block}
+
+	header := data.HeaderOnly()
+	assert.Equal(t, block.Header, header.Header)
+	assert.EqualValues(t, 2, header.Header.NumTxs)
+}
@@ -54,12 +54,12 @@ func (err *ErrEvidenceOverflow) Error() string {
 
 // Evidence represents any provable malicious activity by a validator
 type Evidence interface {
-	Height() int64                                     // height of the equivocation
-	Address() []byte                                   // address of the equivocating validator
-	Bytes() []byte                                     // bytes which compromise the evidence
-	Hash() []byte                                      // hash of the evidence
-	Verify(chainID string, pubKey crypto.PubKey) error // verify the evidence
-	Equal(Evidence) bool                               // check equality of evidence
+	Height() int64                                                        // height of the equivocation
+	Address() []byte                                                      // address of the equivocating validator
+	Bytes() []byte                                                        // bytes which compromise the evidence
+	Hash() []byte                                                         // hash of the evidence
+	Verify(chainID string, domain SignDomain, pubKey crypto.PubKey) error // verify the evidence
+	Equal(Evidence) bool                                                  // check equality of evidence
 
 	ValidateBasic() error
 	String() string
@@ -131,7 +131,7 @@ func (dve *DuplicateVoteEvidence) Hash() []byte {
 
 // Verify returns an error if the two votes aren't conflicting.
 // To be conflicting, they must be from the same validator, for the same H/R/S, but for different blocks.
-func (dve *DuplicateVoteEvidence) Verify(chainID string, pubKey crypto.PubKey) error {
+func (dve *DuplicateVoteEvidence) Verify(chainID string, domain SignDomain, pubKey crypto.PubKey) error {
 	// H/R/S must be the same
 	if dve.VoteA.Height != dve.VoteB.Height ||
 		dve.VoteA.Round != dve.VoteB.Round ||
@@ -162,10 +162,10 @@ func (dve *DuplicateVoteEvidence) Verify(chainID string, pubKey crypto.PubKey) e
 	}
 
 	// Signatures must be valid
-	if !pubKey.VerifyBytes(dve.VoteA.SignBytes(chainID), dve.VoteA.Signature) {
+	if !pubKey.VerifyBytes(dve.VoteA.SignBytes(chainID, domain), dve.VoteA.Signature) {
 		return fmt.Errorf("DuplicateVoteEvidence Error verifying VoteA: %v", ErrVoteInvalidSignature)
 	}
-	if !pubKey.VerifyBytes(dve.VoteB.SignBytes(chainID), dve.VoteB.Signature) {
+	if !pubKey.VerifyBytes(dve.VoteB.SignBytes(chainID, domain), dve.VoteB.Signature) {
 		return fmt.Errorf("DuplicateVoteEvidence Error verifying VoteB: %v", ErrVoteInvalidSignature)
 	}
 
@@ -243,7 +243,9 @@ func (e MockGoodEvidence) Hash() []byte {
 func (e MockGoodEvidence) Bytes() []byte {
 	return []byte(fmt.Sprintf("%d-%x", e.Height_, e.Address_))
 }
-func (e MockGoodEvidence) Verify(chainID string, pubKey crypto.PubKey) error { return nil }
+func (e MockGoodEvidence) Verify(chainID string, domain SignDomain, pubKey crypto.PubKey) error {
+	return nil
+}
 func (e MockGoodEvidence) Equal(ev Evidence) bool {
 	e2 := ev.(MockGoodEvidence)
 	return e.Height_ == e2.Height_ &&
@@ -259,7 +261,7 @@ type MockBadEvidence struct {
 	MockGoodEvidence
 }
 
-func (e MockBadEvidence) Verify(chainID string, pubKey crypto.PubKey) error {
+func (e MockBadEvidence) Verify(chainID string, domain SignDomain, pubKey crypto.PubKey) error {
 	return fmt.Errorf("MockBadEvidence")
 }
 func (e MockBadEvidence) Equal(ev Evidence) bool {
@@ -26,7 +26,7 @@ func makeVote(val PrivValidator, chainID string, valIndex int, height int64, rou
 		Type:             SignedMsgType(step),
 		BlockID:          blockID,
 	}
-	err := val.SignVote(chainID, v)
+	err := val.SignVote(chainID, SignDomain{}, v)
 	if err != nil {
 		panic(err)
 	}
@@ -46,7 +46,7 @@ func TestEvidence(t *testing.T) {
 
 	vote1 := makeVote(val, chainID, 0, 10, 2, 1, blockID)
 	badVote := makeVote(val, chainID, 0, 10, 2, 1, blockID)
-	err := val2.SignVote(chainID, badVote)
+	err := val2.SignVote(chainID, SignDomain{}, badVote)
 	if err != nil {
 		panic(err)
 	}
@@ -72,9 +72,9 @@ func TestEvidence(t *testing.T) {
 			VoteB: c.vote2,
 		}
 		if c.valid {
-			assert.Nil(t, ev.Verify(chainID, pubKey), "evidence should be valid")
+			assert.Nil(t, ev.Verify(chainID, SignDomain{}, pubKey), "evidence should be valid")
 		} else {
-			assert.NotNil(t, ev.Verify(chainID, pubKey), "evidence should be invalid")
+			assert.NotNil(t, ev.Verify(chainID, SignDomain{}, pubKey), "evidence should be invalid")
 		}
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/merkle"
 	cmn "github.com/tendermint/tendermint/libs/common"
 	tmtime "github.com/tendermint/tendermint/types/time"
 )
@@ -39,6 +40,12 @@ type GenesisDoc struct {
 	Validators      []GenesisValidator `json:"validators,omitempty"`
 	AppHash         cmn.HexBytes       `json:"app_hash"`
 	AppState        json.RawMessage    `json:"app_state,omitempty"`
+	// Format of the sign bytes of the votes and proposals, the legacy one if
+	// nil.
+	SignDomain *SignDomain `json:"sign_domain,omitempty"`
+	// Name of the hash function of the Merkle trees and the headers, SHA256 if
+	// empty.
+	MerkleHasher string `json:"merkle_hasher,omitempty"`
 }
 
 // SaveAs is a utility method for saving GenensisDoc as a JSON file.
@@ -60,6 +67,17 @@ func (genDoc *GenesisDoc) ValidatorHash() []byte {
 	return vset.Hash()
 }
 
+// UseMerkleHasher sets the Hasher of the Merkle trees and the headers to the
+// one of the genesis file. It must be called before the genesis state hashes
+// its validators, see merkle.UseHasher.
+func (genDoc *GenesisDoc) UseMerkleHasher() error {
+	hasher, err := merkle.HasherByName(genDoc.MerkleHasher)
+	if err != nil {
+		return err
+	}
+	return merkle.UseHasher(hasher)
+}
+
 // ValidateAndComplete checks that all necessary fields are present
 // and fills in defaults for optional fields left empty
 func (genDoc *GenesisDoc) ValidateAndComplete() error {
@@ -70,6 +88,16 @@ func (genDoc *GenesisDoc) ValidateAndComplete() error {
 		return cmn.NewError("chain_id in genesis doc is too long (max: %d)", MaxChainIDLen)
 	}
 
+	if genDoc.SignDomain != nil {
+		if err := genDoc.SignDomain.ValidateBasic(); err != nil {
+			return cmn.NewError("Invalid sign_domain in genesis doc: %v", err)
+		}
+	}
+
+	if _, err := merkle.HasherByName(genDoc.MerkleHasher); err != nil {
+		return cmn.NewError("Invalid merkle_hasher in genesis doc: %v", err)
+	}
+
 	if genDoc.ConsensusParams == nil {
 		genDoc.ConsensusParams = DefaultConsensusParams()
 	} else {
@@ -90,6 +118,14 @@ func (genDoc *GenesisDoc) ValidateAndComplete() error {
 		}
 	}
 
+	vals := make([]*Validator, len(genDoc.Validators))
+	for i, v := range genDoc.Validators {
+		vals[i] = NewValidator(v.PubKey, v.Power)
+	}
+	if err := genDoc.ConsensusParams.Validator.ValidateVotingPowers(vals); err != nil {
+		return cmn.NewError("Invalid validators in the genesis file: %v", err)
+	}
+
 	if genDoc.GenesisTime.IsZero() {
 		genDoc.GenesisTime = tmtime.Now()
 	}
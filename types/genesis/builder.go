@@ -0,0 +1,120 @@
+// Package genesis builds the genesis doc of a chain programmatically, for the
+// tools creating networks, instead of templating its JSON.
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/types"
+	tmtime "github.com/tendermint/tendermint/types/time"
+)
+
+// Builder builds a genesis doc. Each method validates its arguments, and
+// Build validates the whole doc as a node loading it would.
+type Builder struct {
+	doc types.GenesisDoc
+}
+
+// NewBuilder returns a Builder of the genesis doc of the chain, starting now,
+// with the default consensus params and no validators.
+func NewBuilder(chainID string) *Builder {
+	return &Builder{
+		doc: types.GenesisDoc{
+			ChainID:         chainID,
+			GenesisTime:     tmtime.Now(),
+			ConsensusParams: types.DefaultConsensusParams(),
+		},
+	}
+}
+
+// SetGenesisTime sets the time of the start of the chain.
+func (b *Builder) SetGenesisTime(t time.Time) {
+	b.doc.GenesisTime = tmtime.Canonical(t)
+}
+
+// AddValidator adds a validator with the voting power. The name is optional.
+func (b *Builder) AddValidator(pubKey crypto.PubKey, power int64, name string) error {
+	if pubKey == nil {
+		return errors.New("nil public key")
+	}
+	if power <= 0 {
+		return fmt.Errorf("the voting power must be positive, got %d", power)
+	}
+	address := pubKey.Address()
+	for _, v := range b.doc.Validators {
+		if bytes.Equal(v.Address, address) {
+			return fmt.Errorf("duplicate validator %v", address)
+		}
+	}
+	b.doc.Validators = append(b.doc.Validators, types.GenesisValidator{
+		Address: address,
+		PubKey:  pubKey,
+		Power:   power,
+		Name:    name,
+	})
+	return nil
+}
+
+// SetConsensusParams sets the consensus params, replacing the default ones.
+func (b *Builder) SetConsensusParams(params types.ConsensusParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	b.doc.ConsensusParams = &params
+	return nil
+}
+
+// SetAppHash sets the expected app hash of the application before the first
+// block.
+func (b *Builder) SetAppHash(appHash []byte) {
+	b.doc.AppHash = appHash
+}
+
+// SetAppState sets the state of the application, passed to InitChain: a
+// json.RawMessage or []byte of JSON, or a value marshalled to JSON.
+func (b *Builder) SetAppState(appState interface{}) error {
+	var bz []byte
+	switch s := appState.(type) {
+	case json.RawMessage:
+		bz = s
+	case []byte:
+		bz = s
+	default:
+		var err error
+		if bz, err = json.Marshal(appState); err != nil {
+			return err
+		}
+	}
+	if !json.Valid(bz) {
+		return errors.New("the app state is not valid JSON")
+	}
+	b.doc.AppState = json.RawMessage(bz)
+	return nil
+}
+
+// Build returns the genesis doc, validated and completed as a node loading it
+// would.
+func (b *Builder) Build() (*types.GenesisDoc, error) {
+	doc := b.doc
+	params := *b.doc.ConsensusParams
+	doc.ConsensusParams = &params
+	doc.Validators = append([]types.GenesisValidator(nil), b.doc.Validators...)
+	if err := doc.ValidateAndComplete(); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Write builds the genesis doc, and writes it to the file.
+func (b *Builder) Write(file string) error {
+	doc, err := b.Build()
+	if err != nil {
+		return err
+	}
+	return doc.SaveAs(file)
+}
@@ -0,0 +1,73 @@
+package genesis
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder("test-chain")
+	genesisTime := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	b.SetGenesisTime(genesisTime)
+
+	pubKey := ed25519.GenPrivKey().PubKey()
+	require.NoError(t, b.AddValidator(pubKey, 10, "val0"))
+	require.NoError(t, b.AddValidator(ed25519.GenPrivKey().PubKey(), 5, ""))
+	assert.Error(t, b.AddValidator(pubKey, 10, "val0 again"))
+	assert.Error(t, b.AddValidator(ed25519.GenPrivKey().PubKey(), 0, "no power"))
+	assert.Error(t, b.AddValidator(nil, 10, "no key"))
+
+	params := *types.DefaultConsensusParams()
+	params.BlockSize.MaxGas = 1000
+	require.NoError(t, b.SetConsensusParams(params))
+	params.BlockSize.MaxBytes = 0
+	assert.Error(t, b.SetConsensusParams(params))
+
+	require.NoError(t, b.SetAppState(map[string]string{"key": "value"}))
+	assert.Error(t, b.SetAppState([]byte("{")))
+	b.SetAppHash([]byte{1, 2, 3})
+
+	doc, err := b.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "test-chain", doc.ChainID)
+	assert.Equal(t, genesisTime, doc.GenesisTime)
+	require.Len(t, doc.Validators, 2)
+	assert.Equal(t, pubKey.Address(), doc.Validators[0].Address)
+	assert.EqualValues(t, 1000, doc.ConsensusParams.BlockSize.MaxGas)
+	assert.Equal(t, json.RawMessage(`{"key":"value"}`), doc.AppState)
+
+	// the doc written is the one loaded by a node
+	dir, err := ioutil.TempDir("", "genesis_builder_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "genesis.json")
+	require.NoError(t, b.Write(file))
+	loaded, err := types.GenesisDocFromFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, doc.ValidatorHash(), loaded.ValidatorHash())
+	assert.Equal(t, doc.ConsensusParams, loaded.ConsensusParams)
+	assert.Equal(t, doc.AppHash, loaded.AppHash)
+}
+
+func TestBuilderInvalid(t *testing.T) {
+	_, err := NewBuilder("").Build()
+	assert.Error(t, err, "empty chain ID")
+
+	b := NewBuilder("test-chain")
+	params := *types.DefaultConsensusParams()
+	params.Validator.MaxPower = 5
+	require.NoError(t, b.SetConsensusParams(params))
+	require.NoError(t, b.AddValidator(ed25519.GenPrivKey().PubKey(), 10, ""))
+	_, err = b.Build()
+	assert.Error(t, err, "power greater than the max power")
+}
@@ -27,6 +27,12 @@ func TestGenesisBad(t *testing.T) {
 		[]byte(`{"chain_id": "Lorem ipsum dolor sit amet, consectetuer adipiscing", "validators": [{"pub_key":{"type":"tendermint/PubKeyEd25519","value":"AT/+aaL1eB0477Mud9JMm8Sh8BIvOYlPGC9KkIUmFaE="},"power":"10","name":""}]}`),
 		// wrong address
 		[]byte(`{"chain_id":"mychain", "validators":[{"address": "A", "pub_key":{"type":"tendermint/PubKeyEd25519","value":"AT/+aaL1eB0477Mud9JMm8Sh8BIvOYlPGC9KkIUmFaE="},"power":"10","name":""}]}`),
+		// power greater than the max power
+		[]byte(`{"chain_id":"mychain","consensus_params":{"block_size":{"max_bytes":"22020096","max_gas":"-1"},"evidence":{"max_age":"100000"},"validator":{"pub_key_types":["ed25519"],"max_power":"5"}},"validators":[{"pub_key":{"type":"tendermint/PubKeyEd25519","value":"AT/+aaL1eB0477Mud9JMm8Sh8BIvOYlPGC9KkIUmFaE="},"power":"10","name":""}]}`),
+		// unknown merkle hasher
+		[]byte(`{"chain_id":"mychain","merkle_hasher":"md5","validators":[{"pub_key":{"type":"tendermint/PubKeyEd25519","value":"AT/+aaL1eB0477Mud9JMm8Sh8BIvOYlPGC9KkIUmFaE="},"power":"10","name":""}]}`),
+		// power greater than the max total voting power
+		[]byte(`{"chain_id":"mychain","validators":[{"pub_key":{"type":"tendermint/PubKeyEd25519","value":"AT/+aaL1eB0477Mud9JMm8Sh8BIvOYlPGC9KkIUmFaE="},"power":"9223372036854775807","name":""}]}`),
 	}
 
 	for _, testCase := range testCases {
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"fmt"
+	"time"
+
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -12,6 +15,10 @@ const (
 
 	// BlockPartSizeBytes is the size of one block part.
 	BlockPartSizeBytes = 65536 // 64kB
+
+	// MaxEmptyIntervalMs is the maximum permitted interval between
+	// consecutive empty blocks.
+	MaxEmptyIntervalMs = 86400000 // 1 day
 )
 
 // ConsensusParams contains consensus critical parameters that determine the
@@ -34,6 +41,12 @@ type HashedParams struct {
 type BlockSizeParams struct {
 	MaxBytes int64 `json:"max_bytes"`
 	MaxGas   int64 `json:"max_gas"`
+
+	// Maximum interval between consecutive empty blocks, in milliseconds, up
+	// to which the nodes double their create_empty_blocks_interval after each
+	// consecutive empty block, 0 for a constant interval. Only set in the
+	// genesis file, as it can't be updated by the application.
+	MaxEmptyIntervalMs int64 `json:"max_empty_interval_ms"`
 }
 
 // EvidenceParams determine how we handle evidence of malfeasance
@@ -41,10 +54,22 @@ type EvidenceParams struct {
 	MaxAge int64 `json:"max_age"` // only accept new evidence more recent than this
 }
 
-// ValidatorParams restrict the public key types validators can use.
+// ValidatorParams restrict the public key types validators can use, and their
+// voting power.
 // NOTE: uses ABCI pubkey naming, not Amino names.
 type ValidatorParams struct {
 	PubKeyTypes []string `json:"pub_key_types"`
+
+	// Maximum voting power of a single validator, 0 for no limit other than
+	// MaxTotalVotingPower. Only set in the genesis file, as it can't be
+	// updated by the application.
+	MaxPower int64 `json:"max_power"`
+
+	// Number of proposer selections (one per height, plus one per extra round)
+	// a validator joining the set is skipped for, while it still votes, so
+	// that its node can finish catching up. 0 for none. Only set in the
+	// genesis file, as it can't be updated by the application.
+	ProposerGraceRounds int64 `json:"proposer_grace_rounds"`
 }
 
 // DefaultConsensusParams returns a default ConsensusParams.
@@ -59,8 +84,9 @@ func DefaultConsensusParams() *ConsensusParams {
 // DefaultBlockSizeParams returns a default BlockSizeParams.
 func DefaultBlockSizeParams() BlockSizeParams {
 	return BlockSizeParams{
-		MaxBytes: 22020096, // 21MB
-		MaxGas:   -1,
+		MaxBytes:           22020096, // 21MB
+		MaxGas:             -1,
+		MaxEmptyIntervalMs: 0,
 	}
 }
 
@@ -74,7 +100,17 @@ func DefaultEvidenceParams() EvidenceParams {
 // DefaultValidatorParams returns a default ValidatorParams, which allows
 // only ed25519 pubkeys.
 func DefaultValidatorParams() ValidatorParams {
-	return ValidatorParams{[]string{ABCIPubKeyTypeEd25519}}
+	return ValidatorParams{
+		PubKeyTypes:         []string{ABCIPubKeyTypeEd25519},
+		MaxPower:            0,
+		ProposerGraceRounds: 0,
+	}
+}
+
+// MaxEmptyInterval returns the maximum interval between consecutive empty
+// blocks, 0 for a constant interval.
+func (params BlockSizeParams) MaxEmptyInterval() time.Duration {
+	return time.Duration(params.MaxEmptyIntervalMs) * time.Millisecond
 }
 
 func (params *ValidatorParams) IsValidPubkeyType(pubkeyType string) bool {
@@ -86,6 +122,27 @@ func (params *ValidatorParams) IsValidPubkeyType(pubkeyType string) bool {
 	return false
 }
 
+// ValidateVotingPowers returns an error if a validator has a negative voting
+// power or one greater than MaxPower, or if their total voting power exceeds
+// MaxTotalVotingPower.
+func (params *ValidatorParams) ValidateVotingPowers(vals []*Validator) error {
+	var total int64
+	for _, val := range vals {
+		if val.VotingPower < 0 {
+			return fmt.Errorf("voting power can't be negative %v", val)
+		}
+		if params.MaxPower > 0 && val.VotingPower > params.MaxPower {
+			return fmt.Errorf("voting power %d of %v exceeds the max %d of the consensus params",
+				val.VotingPower, val, params.MaxPower)
+		}
+		total = safeAddClip(total, val.VotingPower)
+		if total > MaxTotalVotingPower {
+			return fmt.Errorf("total voting power exceeds the max allowed %d", MaxTotalVotingPower)
+		}
+	}
+	return nil
+}
+
 // Validate validates the ConsensusParams to ensure all values are within their
 // allowed limits, and returns an error if they are not.
 func (params *ConsensusParams) Validate() error {
@@ -103,6 +160,11 @@ func (params *ConsensusParams) Validate() error {
 			params.BlockSize.MaxGas)
 	}
 
+	if params.BlockSize.MaxEmptyIntervalMs < 0 || params.BlockSize.MaxEmptyIntervalMs > MaxEmptyIntervalMs {
+		return cmn.NewError("BlockSize.MaxEmptyIntervalMs must be between 0 and %d. Got %d",
+			MaxEmptyIntervalMs, params.BlockSize.MaxEmptyIntervalMs)
+	}
+
 	if params.Evidence.MaxAge <= 0 {
 		return cmn.NewError("EvidenceParams.MaxAge must be greater than 0. Got %d",
 			params.Evidence.MaxAge)
@@ -112,6 +174,16 @@ func (params *ConsensusParams) Validate() error {
 		return cmn.NewError("len(Validator.PubKeyTypes) must be greater than 0")
 	}
 
+	if params.Validator.MaxPower < 0 || params.Validator.MaxPower > MaxTotalVotingPower {
+		return cmn.NewError("Validator.MaxPower must be between 0 and %d. Got %d",
+			MaxTotalVotingPower, params.Validator.MaxPower)
+	}
+
+	if params.Validator.ProposerGraceRounds < 0 {
+		return cmn.NewError("Validator.ProposerGraceRounds can't be negative. Got %d",
+			params.Validator.ProposerGraceRounds)
+	}
+
 	// Check if keyType is a known ABCIPubKeyType
 	for i := 0; i < len(params.Validator.PubKeyTypes); i++ {
 		keyType := params.Validator.PubKeyTypes[i]
@@ -144,7 +216,9 @@ func (params *ConsensusParams) Hash() []byte {
 func (params *ConsensusParams) Equals(params2 *ConsensusParams) bool {
 	return params.BlockSize == params2.BlockSize &&
 		params.Evidence == params2.Evidence &&
-		cmn.StringSliceEqual(params.Validator.PubKeyTypes, params2.Validator.PubKeyTypes)
+		cmn.StringSliceEqual(params.Validator.PubKeyTypes, params2.Validator.PubKeyTypes) &&
+		params.Validator.MaxPower == params2.Validator.MaxPower &&
+		params.Validator.ProposerGraceRounds == params2.Validator.ProposerGraceRounds
 }
 
 // Update returns a copy of the params with updates from the non-zero fields of p2.
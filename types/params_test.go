@@ -2,11 +2,14 @@ package types
 
 import (
 	"bytes"
+	"math"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 )
 
 var (
@@ -45,6 +48,46 @@ func TestConsensusParamsValidation(t *testing.T) {
 	}
 }
 
+func TestValidatorParamsMaxPower(t *testing.T) {
+	params := makeParams(1, 0, 1, valEd25519)
+	for _, maxPower := range []int64{-1, MaxTotalVotingPower + 1} {
+		params.Validator.MaxPower = maxPower
+		assert.Error(t, params.Validate(), "expected error for max power %d", maxPower)
+	}
+	params.Validator.MaxPower = 100
+	assert.NoError(t, params.Validate())
+
+	vals := []*Validator{
+		NewValidator(ed25519.GenPrivKey().PubKey(), 100),
+		NewValidator(ed25519.GenPrivKey().PubKey(), 50),
+	}
+	assert.NoError(t, params.Validator.ValidateVotingPowers(vals))
+	vals[1].VotingPower = 101
+	assert.Error(t, params.Validator.ValidateVotingPowers(vals))
+
+	// The total voting power is capped even without a max power.
+	params.Validator.MaxPower = 0
+	vals[1].VotingPower = MaxTotalVotingPower
+	assert.Error(t, params.Validator.ValidateVotingPowers(vals))
+	vals[1].VotingPower = math.MaxInt64
+	assert.Error(t, params.Validator.ValidateVotingPowers(vals))
+}
+
+func TestBlockSizeParamsMaxEmptyInterval(t *testing.T) {
+	params := makeParams(1, 0, 1, valEd25519)
+	for _, maxInterval := range []int64{-1, MaxEmptyIntervalMs + 1} {
+		params.BlockSize.MaxEmptyIntervalMs = maxInterval
+		assert.Error(t, params.Validate(), "expected error for max empty interval %d", maxInterval)
+	}
+	params.BlockSize.MaxEmptyIntervalMs = 60000
+	assert.NoError(t, params.Validate())
+	assert.Equal(t, time.Minute, params.BlockSize.MaxEmptyInterval())
+
+	// Not an ABCI param, it is kept by the updates.
+	updated := params.Update(&abci.ConsensusParams{BlockSize: &abci.BlockSizeParams{MaxBytes: 10, MaxGas: -1}})
+	assert.EqualValues(t, 60000, updated.BlockSize.MaxEmptyIntervalMs)
+}
+
 func makeParams(blockBytes, blockGas, evidenceAge int64, pubkeyTypes []string) ConsensusParams {
 	return ConsensusParams{
 		BlockSize: BlockSizeParams{
@@ -2,19 +2,23 @@ package types
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"sync"
 
 	"github.com/pkg/errors"
 
 	"github.com/tendermint/tendermint/crypto/merkle"
 	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/erasure"
 )
 
 var (
 	ErrPartSetUnexpectedIndex = errors.New("Error part set unexpected index")
 	ErrPartSetInvalidProof    = errors.New("Error part set invalid proof")
+	ErrPartSetInvalidParity   = errors.New("Error part set invalid parity")
 )
 
 type Part struct {
@@ -51,6 +55,63 @@ func (part *Part) StringIndented(indent string) string {
 
 //-------------------------------------
 
+// ParityPart is a part of the Reed-Solomon parity of the parts of a PartSet,
+// so that the parts can be reconstructed from any Total() of the parts and
+// parity parts. The proof is against the hash of the parity header, which
+// the proposal of the block carries along with its PartSetHeader.
+type ParityPart struct {
+	Index        int                `json:"index"`
+	Total        int                `json:"total"`          // number of parity parts
+	LastPartSize int                `json:"last_part_size"` // size of the last part of the PartSet
+	Bytes        cmn.HexBytes       `json:"bytes"`
+	Proof        merkle.SimpleProof `json:"proof"`
+}
+
+// leaf returns the bytes proven by the proof of the parity part: its bytes,
+// prefixed with the size of the last part.
+func (part *ParityPart) leaf() []byte {
+	bz := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(part.Bytes))
+	n := binary.PutUvarint(bz, uint64(part.LastPartSize))
+	return append(bz[:n], part.Bytes...)
+}
+
+// ValidateBasic performs basic validation.
+func (part *ParityPart) ValidateBasic() error {
+	if part.Index < 0 {
+		return errors.New("Negative Index")
+	}
+	if part.Total <= 0 || part.Total >= erasure.MaxShards {
+		return fmt.Errorf("Total must be between 1 and %d", erasure.MaxShards-1)
+	}
+	if part.Index >= part.Total {
+		return errors.New("Index must be less than Total")
+	}
+	if len(part.Bytes) == 0 || len(part.Bytes) > BlockPartSizeBytes {
+		return fmt.Errorf("Bytes must be between 1 and %d bytes", BlockPartSizeBytes)
+	}
+	if part.LastPartSize <= 0 || part.LastPartSize > len(part.Bytes) {
+		return errors.New("LastPartSize must be between 1 and the size of Bytes")
+	}
+	return nil
+}
+
+func (part *ParityPart) String() string {
+	return fmt.Sprintf("ParityPart{#%v/%v %X...}", part.Index, part.Total, cmn.Fingerprint(part.Bytes))
+}
+
+// ParityPartsTotal returns the number of parity parts of a PartSet of total
+// parts, for the given ratio of parity parts to parts. It is 0 if the PartSet
+// has too many parts to be erasure coded.
+func ParityPartsTotal(total int, ratio float64) int {
+	if ratio <= 0 || total <= 0 || total >= erasure.MaxShards {
+		return 0
+	}
+	parityTotal := int(math.Ceil(float64(total) * ratio))
+	return cmn.MinInt(parityTotal, erasure.MaxShards-total)
+}
+
+//-------------------------------------
+
 type PartSetHeader struct {
 	Total int          `json:"total"`
 	Hash  cmn.HexBytes `json:"hash"`
@@ -90,6 +151,12 @@ type PartSet struct {
 	parts         []*Part
 	partsBitArray *cmn.BitArray
 	count         int
+
+	// Parity parts, if the PartSet is erasure coded.
+	parityHeader   PartSetHeader
+	parityParts    []*ParityPart
+	parityBitArray *cmn.BitArray
+	parityCount    int
 }
 
 // Returns an immutable, full PartSet from the data bytes.
@@ -211,6 +278,10 @@ func (ps *PartSet) AddPart(part *Part) (bool, error) {
 	ps.parts[part.Index] = part
 	ps.partsBitArray.SetIndex(part.Index, true)
 	ps.count++
+
+	// The part may complete the parity parts, or tell the invalid one. The
+	// errors are the ones of the parity parts, not of this part.
+	_ = ps.tryReconstruct()
 	return true, nil
 }
 
@@ -224,6 +295,249 @@ func (ps *PartSet) IsComplete() bool {
 	return ps.count == ps.total
 }
 
+// ParityTotal returns the number of parity parts, 0 if the PartSet is not
+// erasure coded.
+func (ps *PartSet) ParityTotal() int {
+	if ps == nil {
+		return 0
+	}
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+	return len(ps.parityParts)
+}
+
+// ParityBitArray returns the parity parts of the PartSet, nil if it is not
+// erasure coded.
+func (ps *PartSet) ParityBitArray() *cmn.BitArray {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+	return ps.parityBitArray.Copy()
+}
+
+// ParityHeader returns the header of the parity parts of the PartSet, zero
+// if it is not erasure coded.
+func (ps *PartSet) ParityHeader() PartSetHeader {
+	if ps == nil {
+		return PartSetHeader{}
+	}
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+	return ps.parityHeader
+}
+
+// SetParityHeader sets the header of the parity parts of the PartSet, from
+// the proposal of its block, against which the parity parts are verified. It
+// is ignored if the PartSet already has one.
+func (ps *PartSet) SetParityHeader(header PartSetHeader) {
+	if ps == nil {
+		return
+	}
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+	if ps.parityHeader.IsZero() {
+		ps.parityHeader = header
+	}
+}
+
+// GetParityPart returns the parity part of the given index, nil if the
+// PartSet doesn't have it.
+func (ps *PartSet) GetParityPart(index int) *ParityPart {
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+	if index >= len(ps.parityParts) {
+		return nil
+	}
+	return ps.parityParts[index]
+}
+
+// MakeParityParts computes the total parity parts of the complete PartSet,
+// and their header. If the PartSet has the parity header of a proposal, the
+// parity parts must match it, or ErrPartSetInvalidParity is returned.
+func (ps *PartSet) MakeParityParts(total int) error {
+	if !ps.IsComplete() {
+		return errors.New("Cannot make the parity parts of an incomplete PartSet")
+	}
+	ps.mtx.Lock()
+	made := ps.parityHeader.Total == total && ps.parityCount == total
+	ps.mtx.Unlock()
+	if made {
+		return nil
+	}
+	code, err := erasure.NewCode(ps.total, total)
+	if err != nil {
+		return err
+	}
+
+	// The parts are immutable once complete.
+	partSize := len(ps.parts[0].Bytes)
+	lastPartSize := len(ps.parts[ps.total-1].Bytes)
+	data := make([][]byte, ps.total)
+	for i, part := range ps.parts {
+		data[i] = padPart(part.Bytes, partSize)
+	}
+	parity, err := code.Encode(data)
+	if err != nil {
+		return err
+	}
+
+	parityParts := make([]*ParityPart, total)
+	parityBitArray := cmn.NewBitArray(total)
+	leaves := make([][]byte, total)
+	for i, bz := range parity {
+		parityParts[i] = &ParityPart{
+			Index:        i,
+			Total:        total,
+			LastPartSize: lastPartSize,
+			Bytes:        bz,
+		}
+		parityBitArray.SetIndex(i, true)
+		leaves[i] = parityParts[i].leaf()
+	}
+	root, proofs := merkle.SimpleProofsFromByteSlices(leaves)
+	for i, part := range parityParts {
+		part.Proof = *proofs[i]
+	}
+	header := PartSetHeader{Total: total, Hash: root}
+
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+	if !ps.parityHeader.IsZero() && !ps.parityHeader.Equals(header) {
+		return ErrPartSetInvalidParity
+	}
+	ps.parityHeader = header
+	ps.parityParts = parityParts
+	ps.parityBitArray = parityBitArray
+	ps.parityCount = total
+	return nil
+}
+
+// AddParityPart adds a parity part to the PartSet, verified against its
+// parity header, and reconstructs the missing parts once it has Total() parts
+// and parity parts. Parity parts are only added once the PartSet has the
+// parity header. If the reconstructed parts don't match the hash of the
+// PartSet, the proposer signed invalid parity parts: ErrPartSetInvalidParity
+// is returned, and the parity parts are dropped.
+func (ps *PartSet) AddParityPart(part *ParityPart) (bool, error) {
+	if ps == nil {
+		return false, nil
+	}
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	// No need for parity, or no parity expected.
+	if ps.count == ps.total || ps.parityHeader.IsZero() {
+		return false, nil
+	}
+
+	// Invalid part index
+	if part.Total != ps.parityHeader.Total || part.Index >= part.Total {
+		return false, ErrPartSetUnexpectedIndex
+	}
+	if ps.parityParts == nil {
+		ps.parityParts = make([]*ParityPart, part.Total)
+		ps.parityBitArray = cmn.NewBitArray(part.Total)
+	}
+
+	// If part already exists, return false.
+	if ps.parityParts[part.Index] != nil {
+		return false, nil
+	}
+
+	// Check hash proof
+	if part.Proof.Index != part.Index || part.Proof.Total != part.Total ||
+		part.Proof.Verify(ps.parityHeader.Hash, part.leaf()) != nil {
+		return false, ErrPartSetInvalidProof
+	}
+
+	ps.parityParts[part.Index] = part
+	ps.parityBitArray.SetIndex(part.Index, true)
+	ps.parityCount++
+
+	return true, ps.tryReconstruct()
+}
+
+// tryReconstruct reconstructs the missing parts once there are enough parts
+// and parity parts. The parity parts match the parity header, so if the
+// reconstructed parts don't match the hash, the proposer signed invalid ones:
+// they are all dropped, for the parts alone. ps.mtx must be held.
+func (ps *PartSet) tryReconstruct() error {
+	if ps.parityCount == 0 || ps.count == ps.total || ps.count+ps.parityCount < ps.total {
+		return nil
+	}
+	err := ps.reconstruct()
+	if err != nil {
+		ps.parityHeader = PartSetHeader{}
+		ps.parityParts = nil
+		ps.parityBitArray = nil
+		ps.parityCount = 0
+	}
+	return err
+}
+
+// reconstruct reconstructs the missing parts from the parts and parity parts,
+// and verifies them against the hash. ps.mtx must be held.
+func (ps *PartSet) reconstruct() error {
+	var partSize, lastPartSize int
+	shards := make([][]byte, ps.total+len(ps.parityParts))
+	for i, part := range ps.parityParts {
+		if part != nil {
+			partSize, lastPartSize = len(part.Bytes), part.LastPartSize
+			shards[ps.total+i] = part.Bytes
+		}
+	}
+	for i, part := range ps.parts {
+		if part == nil {
+			continue
+		}
+		size := partSize
+		if i == ps.total-1 {
+			size = lastPartSize
+		}
+		if len(part.Bytes) != size {
+			return ErrPartSetInvalidParity
+		}
+		shards[i] = padPart(part.Bytes, partSize)
+	}
+
+	code, err := erasure.NewCode(ps.total, len(ps.parityParts))
+	if err != nil {
+		return err
+	}
+	if err := code.ReconstructData(shards); err != nil {
+		return ErrPartSetInvalidParity
+	}
+	partsBytes := shards[:ps.total]
+	partsBytes[ps.total-1] = partsBytes[ps.total-1][:lastPartSize]
+
+	root, proofs := merkle.SimpleProofsFromByteSlices(partsBytes)
+	if !bytes.Equal(root, ps.hash) {
+		return ErrPartSetInvalidParity
+	}
+	for i, part := range ps.parts {
+		if part != nil {
+			continue
+		}
+		ps.parts[i] = &Part{
+			Index: i,
+			Bytes: partsBytes[i],
+			Proof: *proofs[i],
+		}
+		ps.partsBitArray.SetIndex(i, true)
+	}
+	ps.count = ps.total
+	return nil
+}
+
+// padPart returns the bytes of a part padded with zeros to size.
+func padPart(bz []byte, size int) []byte {
+	if len(bz) == size {
+		return bz
+	}
+	padded := make([]byte, size)
+	copy(padded, bz)
+	return padded
+}
+
 func (ps *PartSet) GetReader() io.Reader {
 	if !ps.IsComplete() {
 		cmn.PanicSanity("Cannot GetReader() on incomplete PartSet")
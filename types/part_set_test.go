@@ -126,3 +126,161 @@ func TestPartValidateBasic(t *testing.T) {
 		})
 	}
 }
+
+func TestPartSetParity(t *testing.T) {
+	// 21 parts, the last one of 123 bytes.
+	data := cmn.RandBytes(testPartSize*20 + 123)
+	partSet := NewPartSetFromData(data, testPartSize)
+	require.NoError(t, partSet.MakeParityParts(10))
+	assert.Equal(t, 10, partSet.ParityTotal())
+	assert.True(t, partSet.ParityBitArray().IsFull())
+
+	// No parity parts without the parity header.
+	partSet2 := NewPartSetFromHeader(partSet.Header())
+	added, err := partSet2.AddParityPart(partSet.GetParityPart(0))
+	assert.False(t, added)
+	assert.NoError(t, err)
+
+	// Any 21 of the parts and parity parts reconstruct the parts.
+	partSet2.SetParityHeader(partSet.ParityHeader())
+	for i := 10; i < partSet.Total(); i++ {
+		added, err := partSet2.AddPart(partSet.GetPart(i))
+		require.True(t, added)
+		require.NoError(t, err)
+	}
+	for i := 0; i < 10; i++ {
+		assert.False(t, partSet2.IsComplete())
+		added, err := partSet2.AddParityPart(partSet.GetParityPart(i))
+		require.True(t, added)
+		require.NoError(t, err)
+	}
+	assert.True(t, partSet2.IsComplete())
+	assert.True(t, partSet2.BitArray().IsFull())
+	data2, err := ioutil.ReadAll(partSet2.GetReader())
+	require.NoError(t, err)
+	assert.Equal(t, data, data2)
+
+	// The reconstructed parts have proofs.
+	partSet3 := NewPartSetFromHeader(partSet.Header())
+	for i := 0; i < partSet.Total(); i++ {
+		added, err := partSet3.AddPart(partSet2.GetPart(i))
+		require.True(t, added)
+		require.NoError(t, err)
+	}
+
+	// No need for parity once complete.
+	added, err = partSet2.AddParityPart(partSet.GetParityPart(0))
+	assert.False(t, added)
+	assert.NoError(t, err)
+
+	// The parity parts made from the complete parts match the header.
+	partSet3.SetParityHeader(partSet.ParityHeader())
+	assert.NoError(t, partSet3.MakeParityParts(10))
+	assert.Equal(t, partSet.GetParityPart(3), partSet3.GetParityPart(3))
+	partSet3 = NewPartSetFromData(cmn.RandBytes(len(data)), testPartSize)
+	partSet3.SetParityHeader(partSet.ParityHeader())
+	assert.Equal(t, ErrPartSetInvalidParity, partSet3.MakeParityParts(10))
+}
+
+func TestPartSetInvalidParity(t *testing.T) {
+	data := cmn.RandBytes(testPartSize*4 + 1)
+	partSet := NewPartSetFromData(data, testPartSize)
+	require.NoError(t, partSet.MakeParityParts(2))
+
+	partSet2 := NewPartSetFromHeader(partSet.Header())
+	partSet2.SetParityHeader(partSet.ParityHeader())
+	for i := 0; i < 3; i++ {
+		_, err := partSet2.AddPart(partSet.GetPart(i))
+		require.NoError(t, err)
+	}
+
+	// The parity parts not matching the parity header are rejected.
+	wrongSize := *partSet.GetParityPart(0)
+	wrongSize.LastPartSize++
+	wrongBytes := *partSet.GetParityPart(0)
+	wrongBytes.Bytes = append([]byte{wrongBytes.Bytes[0] + 1}, wrongBytes.Bytes[1:]...)
+	wrongIndex := *partSet.GetParityPart(0)
+	wrongIndex.Index = 1
+	for _, parityPart := range []*ParityPart{&wrongSize, &wrongBytes, &wrongIndex} {
+		added, err := partSet2.AddParityPart(parityPart)
+		assert.False(t, added)
+		assert.Equal(t, ErrPartSetInvalidProof, err)
+	}
+	wrongTotal := *partSet.GetParityPart(0)
+	wrongTotal.Total = 3
+	_, err := partSet2.AddParityPart(&wrongTotal)
+	assert.Equal(t, ErrPartSetUnexpectedIndex, err)
+
+	added, err := partSet2.AddParityPart(partSet.GetParityPart(0))
+	assert.True(t, added)
+	assert.NoError(t, err)
+	assert.True(t, partSet2.IsComplete())
+	data2, err := ioutil.ReadAll(partSet2.GetReader())
+	require.NoError(t, err)
+	assert.Equal(t, data, data2)
+}
+
+func TestPartSetInvalidParityHeader(t *testing.T) {
+	data := cmn.RandBytes(testPartSize*4 + 1)
+	partSet := NewPartSetFromData(data, testPartSize)
+
+	// The parity parts of other parts, under a header signed by the proposer.
+	otherPartSet := NewPartSetFromData(cmn.RandBytes(len(data)), testPartSize)
+	require.NoError(t, otherPartSet.MakeParityParts(2))
+
+	partSet2 := NewPartSetFromHeader(partSet.Header())
+	partSet2.SetParityHeader(otherPartSet.ParityHeader())
+	for i := 0; i < 3; i++ {
+		_, err := partSet2.AddPart(partSet.GetPart(i))
+		require.NoError(t, err)
+	}
+	added, err := partSet2.AddParityPart(otherPartSet.GetParityPart(0))
+	assert.True(t, added)
+	assert.Equal(t, ErrPartSetInvalidParity, err)
+	assert.False(t, partSet2.IsComplete())
+
+	// The parity parts are dropped, for the parts alone.
+	assert.True(t, partSet2.ParityHeader().IsZero())
+	assert.Nil(t, partSet2.ParityBitArray())
+	added, err = partSet2.AddParityPart(otherPartSet.GetParityPart(1))
+	assert.False(t, added)
+	assert.NoError(t, err)
+	_, err = partSet2.AddPart(partSet.GetPart(3))
+	require.NoError(t, err)
+	assert.True(t, partSet2.IsComplete())
+}
+
+func TestParityPartsTotal(t *testing.T) {
+	assert.Equal(t, 0, ParityPartsTotal(10, 0))
+	assert.Equal(t, 5, ParityPartsTotal(10, 0.5))
+	assert.Equal(t, 1, ParityPartsTotal(1, 0.1))
+	assert.Equal(t, 56, ParityPartsTotal(200, 0.5))
+	assert.Equal(t, 0, ParityPartsTotal(256, 0.5))
+}
+
+func TestParityPartValidateBasic(t *testing.T) {
+	testCases := []struct {
+		testName           string
+		malleateParityPart func(*ParityPart)
+		expectErr          bool
+	}{
+		{"Good ParityPart", func(pt *ParityPart) {}, false},
+		{"Negative index", func(pt *ParityPart) { pt.Index = -1 }, true},
+		{"Index too big", func(pt *ParityPart) { pt.Index = pt.Total }, true},
+		{"Total too big", func(pt *ParityPart) { pt.Total = 256 }, true},
+		{"Empty part", func(pt *ParityPart) { pt.Bytes = nil }, true},
+		{"Too big part", func(pt *ParityPart) { pt.Bytes = make([]byte, BlockPartSizeBytes+1) }, true},
+		{"Last part too big", func(pt *ParityPart) { pt.LastPartSize = len(pt.Bytes) + 1 }, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			data := cmn.RandBytes(testPartSize * 2)
+			ps := NewPartSetFromData(data, testPartSize)
+			require.NoError(t, ps.MakeParityParts(2))
+			part := ps.GetParityPart(0)
+			tc.malleateParityPart(part)
+			assert.Equal(t, tc.expectErr, part.ValidateBasic() != nil, "Validate Basic had an unexpected result")
+		})
+	}
+}
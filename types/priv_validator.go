@@ -14,8 +14,8 @@ import (
 type PrivValidator interface {
 	GetPubKey() crypto.PubKey
 
-	SignVote(chainID string, vote *Vote) error
-	SignProposal(chainID string, proposal *Proposal) error
+	SignVote(chainID string, domain SignDomain, vote *Vote) error
+	SignProposal(chainID string, domain SignDomain, proposal *Proposal) error
 }
 
 //----------------------------------------
@@ -65,12 +65,12 @@ func (pv *MockPV) GetPubKey() crypto.PubKey {
 }
 
 // Implements PrivValidator.
-func (pv *MockPV) SignVote(chainID string, vote *Vote) error {
+func (pv *MockPV) SignVote(chainID string, domain SignDomain, vote *Vote) error {
 	useChainID := chainID
 	if pv.breakVoteSigning {
 		useChainID = "incorrect-chain-id"
 	}
-	signBytes := vote.SignBytes(useChainID)
+	signBytes := vote.SignBytes(useChainID, domain)
 	sig, err := pv.privKey.Sign(signBytes)
 	if err != nil {
 		return err
@@ -80,12 +80,12 @@ func (pv *MockPV) SignVote(chainID string, vote *Vote) error {
 }
 
 // Implements PrivValidator.
-func (pv *MockPV) SignProposal(chainID string, proposal *Proposal) error {
+func (pv *MockPV) SignProposal(chainID string, domain SignDomain, proposal *Proposal) error {
 	useChainID := chainID
 	if pv.breakProposalSigning {
 		useChainID = "incorrect-chain-id"
 	}
-	signBytes := proposal.SignBytes(useChainID)
+	signBytes := proposal.SignBytes(useChainID, domain)
 	sig, err := pv.privKey.Sign(signBytes)
 	if err != nil {
 		return err
@@ -113,12 +113,12 @@ type erroringMockPV struct {
 var ErroringMockPVErr = errors.New("erroringMockPV always returns an error")
 
 // Implements PrivValidator.
-func (pv *erroringMockPV) SignVote(chainID string, vote *Vote) error {
+func (pv *erroringMockPV) SignVote(chainID string, domain SignDomain, vote *Vote) error {
 	return ErroringMockPVErr
 }
 
 // Implements PrivValidator.
-func (pv *erroringMockPV) SignProposal(chainID string, proposal *Proposal) error {
+func (pv *erroringMockPV) SignProposal(chainID string, domain SignDomain, proposal *Proposal) error {
 	return ErroringMockPVErr
 }
 
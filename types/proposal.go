@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmn "github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/erasure"
 	tmtime "github.com/tendermint/tendermint/types/time"
 )
 
@@ -20,14 +22,17 @@ var (
 // to be considered valid. It may depend on votes from a previous round,
 // a so-called Proof-of-Lock (POL) round, as noted in the POLRound.
 // If POLRound >= 0, then BlockID corresponds to the block that is locked in POLRound.
+// If the block is erasure coded, ParityPartsHeader is the header of its
+// parity parts, which are verified against it.
 type Proposal struct {
-	Type      SignedMsgType
-	Height    int64     `json:"height"`
-	Round     int       `json:"round"`
-	POLRound  int       `json:"pol_round"` // -1 if null.
-	BlockID   BlockID   `json:"block_id"`
-	Timestamp time.Time `json:"timestamp"`
-	Signature []byte    `json:"signature"`
+	Type              SignedMsgType
+	Height            int64          `json:"height"`
+	Round             int            `json:"round"`
+	POLRound          int            `json:"pol_round"` // -1 if null.
+	BlockID           BlockID        `json:"block_id"`
+	ParityPartsHeader *PartSetHeader `json:"parity_parts_header,omitempty"` // nil if not erasure coded.
+	Timestamp         time.Time      `json:"timestamp"`
+	Signature         []byte         `json:"signature"`
 }
 
 // NewProposal returns a new Proposal.
@@ -64,6 +69,16 @@ func (p *Proposal) ValidateBasic() error {
 	if !p.BlockID.IsComplete() {
 		return fmt.Errorf("Expected a complete, non-empty BlockID, got: %v", p.BlockID)
 	}
+	if h := p.ParityPartsHeader; h != nil {
+		if h.Total <= 0 || p.BlockID.PartsHeader.Total+h.Total > erasure.MaxShards {
+			return fmt.Errorf("ParityPartsHeader.Total must be between 1 and %d",
+				erasure.MaxShards-p.BlockID.PartsHeader.Total)
+		}
+		if len(h.Hash) != tmhash.Size {
+			return fmt.Errorf("Expected ParityPartsHeader.Hash size to be %d bytes, got %d bytes",
+				tmhash.Size, len(h.Hash))
+		}
+	}
 
 	// NOTE: Timestamp validation is subtle and handled elsewhere.
 
@@ -87,11 +102,8 @@ func (p *Proposal) String() string {
 		CanonicalTime(p.Timestamp))
 }
 
-// SignBytes returns the Proposal bytes for signing
-func (p *Proposal) SignBytes(chainID string) []byte {
-	bz, err := cdc.MarshalBinaryLengthPrefixed(CanonicalizeProposal(chainID, p))
-	if err != nil {
-		panic(err)
-	}
-	return bz
+// SignBytes returns the Proposal bytes for signing, in the format of the
+// SignDomain of the chain.
+func (p *Proposal) SignBytes(chainID string, domain SignDomain) []byte {
+	return signBytes(chainID, domain, CanonicalizeProposal(chainID, p))
 }
@@ -28,7 +28,7 @@ func init() {
 
 func TestProposalSignable(t *testing.T) {
 	chainID := "test_chain_id"
-	signBytes := testProposal.SignBytes(chainID)
+	signBytes := testProposal.SignBytes(chainID, SignDomain{})
 
 	expected, err := cdc.MarshalBinaryLengthPrefixed(CanonicalizeProposal(chainID, testProposal))
 	require.NoError(t, err)
@@ -50,10 +50,10 @@ func TestProposalVerifySignature(t *testing.T) {
 	prop := NewProposal(
 		4, 2, 2,
 		BlockID{[]byte{1, 2, 3}, PartSetHeader{777, []byte("proper")}})
-	signBytes := prop.SignBytes("test_chain_id")
+	signBytes := prop.SignBytes("test_chain_id", SignDomain{})
 
 	// sign it
-	err := privVal.SignProposal("test_chain_id", prop)
+	err := privVal.SignProposal("test_chain_id", SignDomain{}, prop)
 	require.NoError(t, err)
 
 	// verify the same proposal
@@ -68,7 +68,7 @@ func TestProposalVerifySignature(t *testing.T) {
 	require.NoError(t, err)
 
 	// verify the transmitted proposal
-	newSignBytes := newProp.SignBytes("test_chain_id")
+	newSignBytes := newProp.SignBytes("test_chain_id", SignDomain{})
 	require.Equal(t, string(signBytes), string(newSignBytes))
 	valid = pubKey.VerifyBytes(newSignBytes, newProp.Signature)
 	require.True(t, valid)
@@ -76,14 +76,14 @@ func TestProposalVerifySignature(t *testing.T) {
 
 func BenchmarkProposalWriteSignBytes(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		testProposal.SignBytes("test_chain_id")
+		testProposal.SignBytes("test_chain_id", SignDomain{})
 	}
 }
 
 func BenchmarkProposalSign(b *testing.B) {
 	privVal := NewMockPV()
 	for i := 0; i < b.N; i++ {
-		err := privVal.SignProposal("test_chain_id", testProposal)
+		err := privVal.SignProposal("test_chain_id", SignDomain{}, testProposal)
 		if err != nil {
 			b.Error(err)
 		}
@@ -92,12 +92,12 @@ func BenchmarkProposalSign(b *testing.B) {
 
 func BenchmarkProposalVerifySignature(b *testing.B) {
 	privVal := NewMockPV()
-	err := privVal.SignProposal("test_chain_id", testProposal)
+	err := privVal.SignProposal("test_chain_id", SignDomain{}, testProposal)
 	require.Nil(b, err)
 	pubKey := privVal.GetPubKey()
 
 	for i := 0; i < b.N; i++ {
-		pubKey.VerifyBytes(testProposal.SignBytes("test_chain_id"), testProposal.Signature)
+		pubKey.VerifyBytes(testProposal.SignBytes("test_chain_id", SignDomain{}), testProposal.Signature)
 	}
 }
 
@@ -131,7 +131,7 @@ func TestProposalValidateBasic(t *testing.T) {
 			prop := NewProposal(
 				4, 2, 2,
 				blockID)
-			err := privVal.SignProposal("test_chain_id", prop)
+			err := privVal.SignProposal("test_chain_id", SignDomain{}, prop)
 			require.NoError(t, err)
 			tc.malleateProposal(prop)
 			assert.Equal(t, tc.expectErr, prop.ValidateBasic() != nil, "Validate Basic had an unexpected result")
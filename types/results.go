@@ -1,6 +1,9 @@
 package types
 
 import (
+	"bytes"
+	"errors"
+
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto/merkle"
 	cmn "github.com/tendermint/tendermint/libs/common"
@@ -63,6 +66,19 @@ func (a ABCIResults) ProveResult(i int) merkle.SimpleProof {
 	return *proofs[i]
 }
 
+// Proof returns a merkle proof of the result at index i, along with the
+// result itself and the root hash, which is the LastResultsHash of the header
+// following the block of the results.
+// Panics if i < 0 or i >= len(a)
+func (a ABCIResults) Proof(i int) ResultProof {
+	root, proofs := merkle.SimpleProofsFromByteSlices(a.toByteSlices())
+	return ResultProof{
+		RootHash: root,
+		Data:     a[i],
+		Proof:    *proofs[i],
+	}
+}
+
 func (a ABCIResults) toByteSlices() [][]byte {
 	l := len(a)
 	bzs := make([][]byte, l)
@@ -71,3 +87,36 @@ func (a ABCIResults) toByteSlices() [][]byte {
 	}
 	return bzs
 }
+
+// ResultProof represents a Merkle proof of the presence of a DeliverTx result
+// in the Merkle tree of the results of a block.
+type ResultProof struct {
+	RootHash cmn.HexBytes
+	Data     ABCIResult
+	Proof    merkle.SimpleProof
+}
+
+// Leaf returns the amino encoded result, which is the leaf in the merkle tree
+// which this proof refers to.
+func (rp ResultProof) Leaf() []byte {
+	return rp.Data.Bytes()
+}
+
+// Validate verifies the proof. It returns nil if the RootHash matches the
+// resultsHash argument, and if the proof is internally consistent. Otherwise,
+// it returns a sensible error.
+func (rp ResultProof) Validate(resultsHash []byte) error {
+	if !bytes.Equal(resultsHash, rp.RootHash) {
+		return errors.New("Proof matches different results hash")
+	}
+	if rp.Proof.Index < 0 {
+		return errors.New("Proof index cannot be negative")
+	}
+	if rp.Proof.Total <= 0 {
+		return errors.New("Proof total must be positive")
+	}
+	if err := rp.Proof.Verify(rp.RootHash, rp.Leaf()); err != nil {
+		return errors.New("Proof is not internally consistent")
+	}
+	return nil
+}
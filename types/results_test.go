@@ -54,3 +54,27 @@ func TestABCIResultsBytes(t *testing.T) {
 	})
 	assert.NotNil(t, results.Bytes())
 }
+
+func TestABCIResultsProof(t *testing.T) {
+	results := NewResults([]*abci.ResponseDeliverTx{
+		{Code: 0, Data: []byte("one")},
+		{Code: 14, Data: nil},
+		{Code: 14, Data: []byte("foo")},
+	})
+	root := results.Hash()
+
+	for i, res := range results {
+		proof := results.Proof(i)
+		assert.EqualValues(t, root, proof.RootHash, "%d", i)
+		assert.Equal(t, res, proof.Data, "%d", i)
+		assert.NoError(t, proof.Validate(root), "%d", i)
+	}
+
+	// A proof doesn't validate against another root.
+	proof := results.Proof(1)
+	assert.Error(t, proof.Validate([]byte("foo")))
+
+	// Nor for another result.
+	proof.Data = ABCIResult{Code: 0, Data: []byte("one")}
+	assert.Error(t, proof.Validate(root))
+}
@@ -0,0 +1,68 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+func TestSignDomainValidateBasic(t *testing.T) {
+	testCases := []struct {
+		domain SignDomain
+		valid  bool
+	}{
+		{SignDomain{}, true},
+		{SignDomain{Version: SignBytesVersionLegacy, Tag: "mainnet"}, false},
+		{SignDomain{Version: SignBytesVersionDomain}, true},
+		{SignDomain{Version: SignBytesVersionDomain, Tag: "mainnet"}, true},
+		{SignDomain{Version: SignBytesVersionDomain, Tag: string(make([]byte, MaxSignDomainTagLen+1))}, false},
+		{SignDomain{Version: 2}, false},
+	}
+	for _, tc := range testCases {
+		err := tc.domain.ValidateBasic()
+		assert.Equal(t, tc.valid, err == nil, "%+v: %v", tc.domain, err)
+	}
+}
+
+func TestSignBytesDomain(t *testing.T) {
+	const chainID = "test_sign_domain_chain"
+	mainnet := SignDomain{Version: SignBytesVersionDomain, Tag: "mainnet"}
+	testnet := SignDomain{Version: SignBytesVersionDomain, Tag: "testnet"}
+
+	vote := examplePrecommit()
+	legacyVote := vote.SignBytes(chainID, SignDomain{})
+	legacyProposal := testProposal.SignBytes(chainID, SignDomain{})
+
+	mainnetVote := vote.SignBytes(chainID, mainnet)
+	mainnetProposal := testProposal.SignBytes(chainID, mainnet)
+	assert.NotEqual(t, legacyVote, mainnetVote)
+	assert.NotEqual(t, legacyProposal, mainnetProposal)
+
+	testnetVote := vote.SignBytes(chainID, testnet)
+	assert.NotEqual(t, mainnetVote, testnetVote)
+
+	// The canonical forms are the legacy sign bytes.
+	for _, bz := range [][]byte{legacyVote, mainnetVote, testnetVote} {
+		canonical, err := StripSignDomain(bz)
+		require.NoError(t, err)
+		assert.Equal(t, legacyVote, canonical)
+	}
+	canonical, err := StripSignDomain(mainnetProposal)
+	require.NoError(t, err)
+	assert.Equal(t, legacyProposal, canonical)
+
+	_, err = StripSignDomain(mainnetVote[:3])
+	assert.Error(t, err)
+
+	// A vote signed for a network is invalid on the other one.
+	privKey := ed25519.GenPrivKey()
+	vote.ValidatorAddress = privKey.PubKey().Address()
+	vote.Signature, err = privKey.Sign(testnetVote)
+	require.NoError(t, err)
+	assert.NoError(t, vote.Verify(chainID, testnet, privKey.PubKey()))
+	assert.Equal(t, ErrVoteInvalidSignature, vote.Verify(chainID, mainnet, privKey.PubKey()))
+	assert.Equal(t, ErrVoteInvalidSignature, vote.Verify(chainID, SignDomain{}, privKey.PubKey()))
+}
@@ -19,5 +19,5 @@ var (
 // necessarily the object themselves.
 // NOTE: Expected to panic if there is an error marshalling.
 type Signable interface {
-	SignBytes(chainID string) []byte
+	SignBytes(chainID string, domain SignDomain) []byte
 }
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// TagEncoding is the encoding of the values of the tags of the events as
+// strings: the values are bytes, while the queries of the events and txs, and
+// the events of the RPC, have strings. The keys of the tags are strings, as
+// are the values of the predefined tags (EventTypeKey, TxHashKey, TxHeightKey).
+type TagEncoding string
+
+const (
+	// TagEncodingString is the values as is, for text values. The default.
+	TagEncodingString TagEncoding = "string"
+	// TagEncodingHex is the values in uppercase hex, for binary values.
+	TagEncodingHex TagEncoding = "hex"
+	// TagEncodingBase64 is the values in standard base64, for binary values.
+	TagEncodingBase64 TagEncoding = "base64"
+)
+
+// ValidateBasic returns an error if the encoding is unknown. The empty
+// encoding is TagEncodingString.
+func (e TagEncoding) ValidateBasic() error {
+	switch e {
+	case "", TagEncodingString, TagEncodingHex, TagEncodingBase64:
+		return nil
+	default:
+		return fmt.Errorf("unknown tag encoding %q, expected %q, %q or %q",
+			e, TagEncodingString, TagEncodingHex, TagEncodingBase64)
+	}
+}
+
+// EncodeToString returns the tag value in the encoding.
+func (e TagEncoding) EncodeToString(value []byte) string {
+	switch e {
+	case TagEncodingHex:
+		return fmt.Sprintf("%X", value)
+	case TagEncodingBase64:
+		return base64.StdEncoding.EncodeToString(value)
+	default:
+		return string(value)
+	}
+}
+
+// DecodeString returns the tag value of the string in the encoding, e.g. of
+// the operand of a query.
+func (e TagEncoding) DecodeString(s string) ([]byte, error) {
+	switch e {
+	case TagEncodingHex:
+		return hex.DecodeString(s)
+	case TagEncodingBase64:
+		return base64.StdEncoding.DecodeString(s)
+	default:
+		return []byte(s), nil
+	}
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagEncoding(t *testing.T) {
+	value := []byte{0xde, 0xad, '/', 'b'}
+	cases := []struct {
+		encoding TagEncoding
+		encoded  string
+	}{
+		{"", "\xde\xad/b"},
+		{TagEncodingString, "\xde\xad/b"},
+		{TagEncodingHex, "DEAD2F62"},
+		{TagEncodingBase64, "3q0vYg=="},
+	}
+	for _, tc := range cases {
+		require.NoError(t, tc.encoding.ValidateBasic())
+		assert.Equal(t, tc.encoded, tc.encoding.EncodeToString(value), tc.encoding)
+		decoded, err := tc.encoding.DecodeString(tc.encoded)
+		require.NoError(t, err)
+		assert.Equal(t, value, decoded, tc.encoding)
+	}
+
+	_, err := TagEncodingHex.DecodeString("xyz")
+	assert.Error(t, err)
+	_, err = TagEncodingBase64.DecodeString("!")
+	assert.Error(t, err)
+	assert.Error(t, TagEncoding("utf16").ValidateBasic())
+}
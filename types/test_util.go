@@ -31,7 +31,7 @@ func MakeCommit(blockID BlockID, height int64, round int,
 }
 
 func signAddVote(privVal PrivValidator, vote *Vote, voteSet *VoteSet) (signed bool, err error) {
-	err = privVal.SignVote(voteSet.ChainID(), vote)
+	err = privVal.SignVote(voteSet.ChainID(), voteSet.SignDomain(), vote)
 	if err != nil {
 		return false, err
 	}
@@ -10,14 +10,18 @@ import (
 )
 
 // Volatile state for each Validator
-// NOTE: The ProposerPriority is not included in Validator.Hash();
-// make sure to update that method if changes are made here
+// NOTE: The ProposerPriority and ProposerGrace are not included in
+// Validator.Hash(); make sure to update that method if changes are made here
 type Validator struct {
 	Address     Address       `json:"address"`
 	PubKey      crypto.PubKey `json:"pub_key"`
 	VotingPower int64         `json:"voting_power"`
 
 	ProposerPriority int64 `json:"proposer_priority"`
+
+	// Number of proposer selections the validator is still skipped for, after
+	// joining the set (see ValidatorParams.ProposerGraceRounds).
+	ProposerGrace int64 `json:"proposer_grace"`
 }
 
 func NewValidator(pubKey crypto.PubKey, votingPower int64) *Validator {
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+)
+
+// ValidatorMonikers maps the addresses of the validators to their
+// human-readable monikers, for the logs and the RPC: the names of the genesis
+// validators, overridden and completed by a local file mapping the hex
+// addresses to the monikers, e.g.
+//
+//	{"E89A51D60F68385E09E716D353373B11F8FACD62": "alice"}
+//
+// It is safe for concurrent use.
+type ValidatorMonikers struct {
+	mtx      sync.RWMutex
+	genesis  map[string]string // by address
+	monikers map[string]string // by address, genesis and file
+	modTime  time.Time         // of the loaded file
+}
+
+// NewValidatorMonikers returns the monikers of the genesis validators, their
+// names.
+func NewValidatorMonikers(genVals []GenesisValidator) *ValidatorMonikers {
+	genesis := make(map[string]string, len(genVals))
+	for _, v := range genVals {
+		if v.Name == "" {
+			continue
+		}
+		address := v.Address
+		if len(address) == 0 {
+			address = v.PubKey.Address()
+		}
+		genesis[string(address)] = v.Name
+	}
+	return &ValidatorMonikers{genesis: genesis, monikers: genesis}
+}
+
+// Moniker returns the moniker of the validator, empty if it has none.
+func (vm *ValidatorMonikers) Moniker(address Address) string {
+	vm.mtx.RLock()
+	defer vm.mtx.RUnlock()
+	return vm.monikers[string(address)]
+}
+
+// LoadFile loads the monikers of the file over the genesis ones, if it
+// changed since the last load, so it can be called periodically to pick up
+// the updates of the file. A missing file is an empty one.
+func (vm *ValidatorMonikers) LoadFile(file string) error {
+	info, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		vm.setFile(nil, time.Time{})
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	vm.mtx.RLock()
+	unchanged := info.ModTime().Equal(vm.modTime)
+	vm.mtx.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var byHex map[string]string
+	if err := json.Unmarshal(bz, &byHex); err != nil {
+		return fmt.Errorf("error reading validator monikers from %v: %v", file, err)
+	}
+	monikers := make(map[string]string, len(byHex))
+	for hexAddress, moniker := range byHex {
+		address, err := hex.DecodeString(strings.TrimSpace(hexAddress))
+		if err != nil || len(address) != crypto.AddressSize {
+			return fmt.Errorf("invalid validator address %q in %v", hexAddress, file)
+		}
+		monikers[string(address)] = moniker
+	}
+	vm.setFile(monikers, info.ModTime())
+	return nil
+}
+
+func (vm *ValidatorMonikers) setFile(file map[string]string, modTime time.Time) {
+	vm.mtx.Lock()
+	defer vm.mtx.Unlock()
+	monikers := make(map[string]string, len(vm.genesis)+len(file))
+	for address, moniker := range vm.genesis {
+		monikers[address] = moniker
+	}
+	for address, moniker := range file {
+		monikers[address] = moniker
+	}
+	vm.monikers = monikers
+	vm.modTime = modTime
+}
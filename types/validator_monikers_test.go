@@ -0,0 +1,60 @@
+package types
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+func TestValidatorMonikers(t *testing.T) {
+	alice, bob, carol := ed25519.GenPrivKey().PubKey(), ed25519.GenPrivKey().PubKey(), ed25519.GenPrivKey().PubKey()
+	monikers := NewValidatorMonikers([]GenesisValidator{
+		{Address: alice.Address(), PubKey: alice, Power: 1, Name: "alice"},
+		{PubKey: bob, Power: 1, Name: "bob"},
+		{PubKey: carol, Power: 1},
+	})
+	assert.Equal(t, "alice", monikers.Moniker(alice.Address()))
+	assert.Equal(t, "bob", monikers.Moniker(bob.Address()))
+	assert.Equal(t, "", monikers.Moniker(carol.Address()))
+
+	dir, err := ioutil.TempDir("", "validator_monikers")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "validator_monikers.json")
+
+	// A missing file is an empty one.
+	require.NoError(t, monikers.LoadFile(file))
+	assert.Equal(t, "alice", monikers.Moniker(alice.Address()))
+
+	// The file overrides and completes the genesis.
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{
+		"`+bob.Address().String()+`": "bobby",
+		"`+carol.Address().String()+`": "carol"
+	}`), 0600))
+	require.NoError(t, monikers.LoadFile(file))
+	assert.Equal(t, "alice", monikers.Moniker(alice.Address()))
+	assert.Equal(t, "bobby", monikers.Moniker(bob.Address()))
+	assert.Equal(t, "carol", monikers.Moniker(carol.Address()))
+
+	// The updates of the file are picked up, back to the genesis names.
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{}`), 0600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(file, later, later))
+	require.NoError(t, monikers.LoadFile(file))
+	assert.Equal(t, "bob", monikers.Moniker(bob.Address()))
+	assert.Equal(t, "", monikers.Moniker(carol.Address()))
+
+	// An invalid file keeps the loaded monikers.
+	require.NoError(t, ioutil.WriteFile(file, []byte(`{"XYZ": "x"}`), 0600))
+	later = later.Add(time.Minute)
+	require.NoError(t, os.Chtimes(file, later, later))
+	assert.Error(t, monikers.LoadFile(file))
+	assert.Equal(t, "bob", monikers.Moniker(bob.Address()))
+}
@@ -15,7 +15,8 @@ import (
 // MaxTotalVotingPower - the maximum allowed total voting power.
 // It needs to be sufficiently small to, in all cases:
 // 1. prevent clipping in incrementProposerPriority()
-// 2. let (diff+diffMax-1) not overflow in IncrementProposerPriority()
+// 2. keep the total voting power, and the priorities of the new validators
+// (-1.125*total), in bounds of int64
 // (Proof of 1 is tricky, left to the reader).
 // It could be higher, but this is sufficiently large for our purposes,
 // and leaves room for defensive purposes.
@@ -68,7 +69,7 @@ func (vals *ValidatorSet) IsNilOrEmpty() bool {
 }
 
 // Increment ProposerPriority and update the proposer on a copy, and return it.
-func (vals *ValidatorSet) CopyIncrementProposerPriority(times int) *ValidatorSet {
+func (vals *ValidatorSet) CopyIncrementProposerPriority(times int64) *ValidatorSet {
 	copy := vals.Copy()
 	copy.IncrementProposerPriority(times)
 	return copy
@@ -76,8 +77,9 @@ func (vals *ValidatorSet) CopyIncrementProposerPriority(times int) *ValidatorSet
 
 // IncrementProposerPriority increments ProposerPriority of each validator and updates the
 // proposer. Panics if validator set is empty.
-// `times` must be positive.
-func (vals *ValidatorSet) IncrementProposerPriority(times int) {
+// `times` must be positive. It's an int64, as the number of heights since the
+// validators last changed, so that it doesn't wrap around on 32-bit platforms.
+func (vals *ValidatorSet) IncrementProposerPriority(times int64) {
 	if vals.IsNilOrEmpty() {
 		panic("empty validator set")
 	}
@@ -94,7 +96,7 @@ func (vals *ValidatorSet) IncrementProposerPriority(times int) {
 
 	var proposer *Validator
 	// call IncrementProposerPriority(1) times times:
-	for i := 0; i < times; i++ {
+	for i := int64(0); i < times; i++ {
 		proposer = vals.incrementProposerPriority()
 	}
 
@@ -115,15 +117,31 @@ func (vals *ValidatorSet) RescalePriorities(diffMax int64) {
 	// Calculating ceil(diff/diffMax):
 	// Re-normalization is performed by dividing by an integer for simplicity.
 	// NOTE: This may make debugging priority issues easier as well.
+	// NOTE: (diff + diffMax - 1) / diffMax would overflow for a diff close to
+	// math.MaxUint64.
 	diff := computeMaxMinPriorityDiff(vals)
-	ratio := (diff + diffMax - 1) / diffMax
+	ratio := diff / uint64(diffMax)
+	if diff%uint64(diffMax) != 0 {
+		ratio++
+	}
 	if ratio > 1 {
 		for _, val := range vals.Validators {
-			val.ProposerPriority /= ratio
+			val.ProposerPriority = divPriority(val.ProposerPriority, ratio)
 		}
 	}
 }
 
+// divPriority divides the priority by the ratio, rounding toward zero as /
+// does, for the ratios which don't fit in an int64.
+func divPriority(priority int64, ratio uint64) int64 {
+	if priority >= 0 {
+		return int64(uint64(priority) / ratio)
+	}
+	// -priority overflows for math.MinInt64.
+	abs := uint64(-(priority + 1)) + 1
+	return -int64(abs / ratio)
+}
+
 func (vals *ValidatorSet) incrementProposerPriority() *Validator {
 	for _, val := range vals.Validators {
 		// Check for overflow for sum.
@@ -135,6 +153,13 @@ func (vals *ValidatorSet) incrementProposerPriority() *Validator {
 	// mind underflow
 	mostest.ProposerPriority = safeSubClip(mostest.ProposerPriority, vals.TotalVotingPower())
 
+	// The validators in grace were skipped once more.
+	for _, val := range vals.Validators {
+		if val.ProposerGrace > 0 {
+			val.ProposerGrace--
+		}
+	}
+
 	return mostest
 }
 
@@ -154,8 +179,9 @@ func (vals *ValidatorSet) computeAvgProposerPriority() int64 {
 	panic(fmt.Sprintf("Cannot represent avg ProposerPriority as an int64 %v", avg))
 }
 
-// compute the difference between the max and min ProposerPriority of that set
-func computeMaxMinPriorityDiff(vals *ValidatorSet) int64 {
+// compute the difference between the max and min ProposerPriority of that set,
+// as an uint64: it overflows an int64 if they are of opposite signs and far apart
+func computeMaxMinPriorityDiff(vals *ValidatorSet) uint64 {
 	if vals.IsNilOrEmpty() {
 		panic("empty validator set")
 	}
@@ -169,18 +195,23 @@ func computeMaxMinPriorityDiff(vals *ValidatorSet) int64 {
 			max = v.ProposerPriority
 		}
 	}
-	diff := max - min
-	if diff < 0 {
-		return -1 * diff
-	} else {
-		return diff
-	}
+	// max >= min, so the difference of their two's complements is the one of
+	// the priorities.
+	return uint64(max) - uint64(min)
 }
 
+// getValWithMostPriority skips the validators in grace, unless they all are.
 func (vals *ValidatorSet) getValWithMostPriority() *Validator {
-	var res *Validator
+	var res, resInGrace *Validator
 	for _, val := range vals.Validators {
-		res = res.CompareProposerPriority(val)
+		if val.ProposerGrace > 0 {
+			resInGrace = resInGrace.CompareProposerPriority(val)
+		} else {
+			res = res.CompareProposerPriority(val)
+		}
+	}
+	if res == nil {
+		return resInGrace
 	}
 	return res
 }
@@ -259,7 +290,11 @@ func (vals *ValidatorSet) TotalVotingPower() int64 {
 		sum := int64(0)
 		for _, val := range vals.Validators {
 			// mind overflow
-			sum = safeAddClip(sum, val.VotingPower)
+			var err error
+			sum, err = checkedAdd(sum, val.VotingPower)
+			if err != nil {
+				panic(err)
+			}
 		}
 		if sum > MaxTotalVotingPower {
 			panic(fmt.Sprintf(
@@ -284,13 +319,22 @@ func (vals *ValidatorSet) GetProposer() (proposer *Validator) {
 	return vals.Proposer.Copy()
 }
 
+// findProposer skips the validators in grace, unless they all are, as the
+// selection of the proposer on increment.
 func (vals *ValidatorSet) findProposer() *Validator {
-	var proposer *Validator
+	var proposer, proposerInGrace *Validator
 	for _, val := range vals.Validators {
-		if proposer == nil || !bytes.Equal(val.Address, proposer.Address) {
+		if val.ProposerGrace > 0 {
+			if proposerInGrace == nil || !bytes.Equal(val.Address, proposerInGrace.Address) {
+				proposerInGrace = proposerInGrace.CompareProposerPriority(val)
+			}
+		} else if proposer == nil || !bytes.Equal(val.Address, proposer.Address) {
 			proposer = proposer.CompareProposerPriority(val)
 		}
 	}
+	if proposer == nil {
+		return proposerInGrace
+	}
 	return proposer
 }
 
@@ -342,7 +386,7 @@ func (vals *ValidatorSet) Iterate(fn func(index int, val *Validator) bool) {
 //
 // Returns:
 // updates, removals - the sorted lists of updates and removals
-// err - non-nil if duplicate entries or entries with negative voting power are seen
+// err - non-nil if duplicate entries or entries with negative or too large voting power are seen
 //
 // No changes are made to 'origChanges'
 func processChanges(origChanges []*Validator) (updates, removals []*Validator, err error) {
@@ -364,6 +408,11 @@ func processChanges(origChanges []*Validator) (updates, removals []*Validator, e
 			err = fmt.Errorf("voting power can't be negative %v", valUpdate)
 			return nil, nil, err
 		}
+		if valUpdate.VotingPower > MaxTotalVotingPower {
+			// also guards verifyUpdates from overflowing int64
+			err = fmt.Errorf("voting power of %v exceeds the max allowed %v", valUpdate, MaxTotalVotingPower)
+			return nil, nil, err
+		}
 		if valUpdate.VotingPower == 0 {
 			removals = append(removals, valUpdate)
 		} else {
@@ -394,12 +443,16 @@ func verifyUpdates(updates []*Validator, vals *ValidatorSet) (updatedTotalVoting
 	for _, valUpdate := range updates {
 		address := valUpdate.Address
 		_, val := vals.GetByAddress(address)
-		if val == nil {
-			// new validator, add its voting power the the total
-			updatedTotalVotingPower += valUpdate.VotingPower
-		} else {
+		// new validator, add its voting power the the total
+		delta := valUpdate.VotingPower
+		if val != nil {
 			// updated validator, add the difference in power to the total
-			updatedTotalVotingPower += valUpdate.VotingPower - val.VotingPower
+			if delta, err = checkedSub(valUpdate.VotingPower, val.VotingPower); err != nil {
+				return 0, err
+			}
+		}
+		if updatedTotalVotingPower, err = checkedAdd(updatedTotalVotingPower, delta); err != nil {
+			return 0, err
 		}
 
 		if updatedTotalVotingPower < 0 {
@@ -445,6 +498,8 @@ func computeNewPriorities(updates []*Validator, vals *ValidatorSet, updatedTotal
 			numNew++
 		} else {
 			valUpdate.ProposerPriority = val.ProposerPriority
+			// Only the new validators have the grace of the update.
+			valUpdate.ProposerGrace = val.ProposerGrace
 		}
 	}
 
@@ -586,7 +641,7 @@ func (vals *ValidatorSet) updateWithChangeSet(changes []*Validator, allowDeletes
 }
 
 // Verify that +2/3 of the set had signed the given signBytes.
-func (vals *ValidatorSet) VerifyCommit(chainID string, blockID BlockID, height int64, commit *Commit) error {
+func (vals *ValidatorSet) VerifyCommit(chainID string, domain SignDomain, blockID BlockID, height int64, commit *Commit) error {
 
 	if err := commit.ValidateBasic(); err != nil {
 		return err
@@ -610,7 +665,7 @@ func (vals *ValidatorSet) VerifyCommit(chainID string, blockID BlockID, height i
 		}
 		_, val := vals.GetByIndex(idx)
 		// Validate signature.
-		precommitSignBytes := commit.VoteSignBytes(chainID, precommit)
+		precommitSignBytes := commit.VoteSignBytes(chainID, domain, precommit)
 		if !val.PubKey.VerifyBytes(precommitSignBytes, precommit.Signature) {
 			return fmt.Errorf("Invalid commit -- invalid signature: %v", precommit)
 		}
@@ -658,12 +713,12 @@ func (vals *ValidatorSet) VerifyCommit(chainID string, blockID BlockID, height i
 // NOTE: This doesn't check whether the commit is a future commit, because the
 // current height isn't part of the ValidatorSet.  Caller must check that the
 // commit height is greater than the height for this validator set.
-func (vals *ValidatorSet) VerifyFutureCommit(newSet *ValidatorSet, chainID string,
+func (vals *ValidatorSet) VerifyFutureCommit(newSet *ValidatorSet, chainID string, domain SignDomain,
 	blockID BlockID, height int64, commit *Commit) error {
 	oldVals := vals
 
 	// Commit must be a valid commit for newSet.
-	err := newSet.VerifyCommit(chainID, blockID, height, commit)
+	err := newSet.VerifyCommit(chainID, domain, blockID, height, commit)
 	if err != nil {
 		return err
 	}
@@ -694,7 +749,7 @@ func (vals *ValidatorSet) VerifyFutureCommit(newSet *ValidatorSet, chainID strin
 		seen[idx] = true
 
 		// Validate signature.
-		precommitSignBytes := commit.VoteSignBytes(chainID, precommit)
+		precommitSignBytes := commit.VoteSignBytes(chainID, domain, precommit)
 		if !val.PubKey.VerifyBytes(precommitSignBytes, precommit.Signature) {
 			return cmn.NewError("Invalid commit -- invalid signature: %v", precommit)
 		}
@@ -807,6 +862,47 @@ func RandValidatorSet(numValidators int, votingPower int64) (*ValidatorSet, []Pr
 ///////////////////////////////////////////////////////////////////////////////
 // Safe addition/subtraction
 
+// ErrOverflowInt64 is the error of an addition or a subtraction of voting
+// powers or proposer priorities whose result can't be represented as an
+// int64.
+type ErrOverflowInt64 struct {
+	Op   string
+	A, B int64
+}
+
+func (e ErrOverflowInt64) Error() string {
+	return fmt.Sprintf("int64 overflow: %d %s %d", e.A, e.Op, e.B)
+}
+
+// IsErrOverflowInt64 returns true if err is an ErrOverflowInt64.
+func IsErrOverflowInt64(err error) bool {
+	switch err_ := err.(type) {
+	case cmn.Error:
+		_, ok := err_.Data().(ErrOverflowInt64)
+		return ok
+	case ErrOverflowInt64:
+		return true
+	default:
+		return false
+	}
+}
+
+func checkedAdd(a, b int64) (int64, error) {
+	c, overflow := safeAdd(a, b)
+	if overflow {
+		return 0, ErrOverflowInt64{"+", a, b}
+	}
+	return c, nil
+}
+
+func checkedSub(a, b int64) (int64, error) {
+	c, overflow := safeSub(a, b)
+	if overflow {
+		return 0, ErrOverflowInt64{"-", a, b}
+	}
+	return c, nil
+}
+
 func safeAdd(a, b int64) (int64, bool) {
 	if b > 0 && a > math.MaxInt64-b {
 		return -1, true
@@ -148,6 +148,40 @@ func TestProposerSelection1(t *testing.T) {
 	}
 }
 
+func TestProposerSelectionGrace(t *testing.T) {
+	foo := newValidator([]byte("foo"), 1000)
+	foo.ProposerGrace = 3
+	vset := NewValidatorSet([]*Validator{
+		foo,
+		newValidator([]byte("bar"), 1),
+	})
+	// NewValidatorSet selected the first proposer.
+	var proposers []string
+	for i := 0; i < 4; i++ {
+		proposers = append(proposers, string(vset.GetProposer().Address))
+		vset.IncrementProposerPriority(1)
+	}
+	assert.Equal(t, "bar bar bar foo", strings.Join(proposers, " "))
+	_, val := vset.GetByAddress([]byte("foo"))
+	assert.EqualValues(t, 0, val.ProposerGrace)
+
+	// The validators are selected if they all are in grace.
+	vset = NewValidatorSet([]*Validator{foo.Copy()})
+	assert.Equal(t, "foo", string(vset.GetProposer().Address))
+
+	// The proposer found again, as the one of a set loaded without it, skips
+	// the validators in grace too.
+	vset = NewValidatorSet([]*Validator{foo.Copy(), newValidator([]byte("bar"), 1)})
+	for _, val := range vset.Validators {
+		val.ProposerPriority = -1000
+		if val.ProposerGrace > 0 {
+			val.ProposerPriority = 1000
+		}
+	}
+	vset.Proposer = nil
+	assert.Equal(t, "bar", string(vset.GetProposer().Address))
+}
+
 func TestProposerSelection2(t *testing.T) {
 	addr0 := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
 	addr1 := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
@@ -259,15 +293,15 @@ func TestProposerSelection3(t *testing.T) {
 		}
 
 		// times is usually 1
-		times := 1
+		times := int64(1)
 		mod := (cmn.RandInt() % 5) + 1
 		if cmn.RandInt()%mod > 0 {
 			// sometimes its up to 5
-			times = (cmn.RandInt() % 4) + 1
+			times = int64(cmn.RandInt()%4) + 1
 		}
 		vset.IncrementProposerPriority(times)
 
-		j += times
+		j += int(times)
 	}
 }
 
@@ -355,7 +389,7 @@ func TestAveragingInIncrementProposerPriority(t *testing.T) {
 	// the expected ProposerPriority.
 	tcs := []struct {
 		vs    ValidatorSet
-		times int
+		times int64
 		avg   int64
 	}{
 		0: {ValidatorSet{
@@ -405,7 +439,7 @@ func TestAveragingInIncrementProposerPriorityWithVotingPower(t *testing.T) {
 	tcs := []struct {
 		vals                  *ValidatorSet
 		wantProposerPrioritys []int64
-		times                 int
+		times                 int64
 		wantProposer          *Validator
 	}{
 
@@ -540,6 +574,41 @@ func TestSafeSubClip(t *testing.T) {
 	assert.EqualValues(t, math.MaxInt64, safeSubClip(math.MaxInt64, -10))
 }
 
+func TestCheckedAddSub(t *testing.T) {
+	c, err := checkedAdd(math.MaxInt64-1, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, math.MaxInt64, c)
+
+	_, err = checkedAdd(math.MaxInt64, 1)
+	assert.True(t, IsErrOverflowInt64(err))
+	assert.Equal(t, ErrOverflowInt64{"+", math.MaxInt64, 1}, err)
+
+	_, err = checkedSub(math.MinInt64, 1)
+	assert.True(t, IsErrOverflowInt64(err))
+	_, err = checkedSub(0, math.MinInt64)
+	assert.True(t, IsErrOverflowInt64(err))
+}
+
+// Test that the priorities of opposite signs whose difference overflows an
+// int64 are rescaled, rather than the difference wrapping around.
+func TestRescalePrioritiesExtremeDiff(t *testing.T) {
+	vals := ValidatorSet{Validators: []*Validator{
+		{Address: []byte{0}, ProposerPriority: math.MaxInt64, VotingPower: 1},
+		{Address: []byte{1}, ProposerPriority: 0, VotingPower: 1},
+		{Address: []byte{2}, ProposerPriority: math.MinInt64, VotingPower: 1},
+	}}
+	assert.EqualValues(t, uint64(math.MaxUint64), computeMaxMinPriorityDiff(&vals))
+
+	diffMax := PriorityWindowSizeFactor * vals.TotalVotingPower()
+	vals.RescalePriorities(diffMax)
+	assert.True(t, computeMaxMinPriorityDiff(&vals) <= uint64(diffMax))
+	assert.True(t, vals.Validators[0].ProposerPriority > 0)
+	assert.True(t, vals.Validators[2].ProposerPriority < 0)
+
+	vals.IncrementProposerPriority(1)
+	assert.Equal(t, []byte{0}, vals.GetProposer().Address)
+}
+
 //-------------------------------------------------------------------
 
 func TestValidatorSetVerifyCommit(t *testing.T) {
@@ -560,7 +629,7 @@ func TestValidatorSetVerifyCommit(t *testing.T) {
 		Type:             PrecommitType,
 		BlockID:          blockID,
 	}
-	sig, err := privKey.Sign(vote.SignBytes(chainID))
+	sig, err := privKey.Sign(vote.SignBytes(chainID, SignDomain{}))
 	assert.NoError(t, err)
 	vote.Signature = sig
 	commit := NewCommit(blockID, []*CommitSig{vote.CommitSig()})
@@ -585,12 +654,12 @@ func TestValidatorSetVerifyCommit(t *testing.T) {
 	}
 
 	for i, c := range cases {
-		err := vset.VerifyCommit(c.chainID, c.blockID, c.height, c.commit)
+		err := vset.VerifyCommit(c.chainID, SignDomain{}, c.blockID, c.height, c.commit)
 		assert.NotNil(t, err, i)
 	}
 
 	// test a good one
-	err = vset.VerifyCommit(chainID, blockID, height, commit)
+	err = vset.VerifyCommit(chainID, SignDomain{}, blockID, height, commit)
 	assert.Nil(t, err)
 }
 
@@ -1018,7 +1087,7 @@ func verifyValidatorSet(t *testing.T, valSet *ValidatorSet) {
 
 	// verify that priorities are scaled
 	dist := computeMaxMinPriorityDiff(valSet)
-	assert.True(t, dist <= PriorityWindowSizeFactor*tvp,
+	assert.True(t, dist <= uint64(PriorityWindowSizeFactor*tvp),
 		"expected priority distance < %d. Got %d", PriorityWindowSizeFactor*tvp, dist)
 }
 
@@ -69,12 +69,10 @@ func (vote *Vote) CommitSig() *CommitSig {
 	return &cs
 }
 
-func (vote *Vote) SignBytes(chainID string) []byte {
-	bz, err := cdc.MarshalBinaryLengthPrefixed(CanonicalizeVote(chainID, vote))
-	if err != nil {
-		panic(err)
-	}
-	return bz
+// SignBytes returns the Vote bytes for signing, in the format of the
+// SignDomain of the chain.
+func (vote *Vote) SignBytes(chainID string, domain SignDomain) []byte {
+	return signBytes(chainID, domain, CanonicalizeVote(chainID, vote))
 }
 
 func (vote *Vote) Copy() *Vote {
@@ -109,12 +107,12 @@ func (vote *Vote) String() string {
 	)
 }
 
-func (vote *Vote) Verify(chainID string, pubKey crypto.PubKey) error {
+func (vote *Vote) Verify(chainID string, domain SignDomain, pubKey crypto.PubKey) error {
 	if !bytes.Equal(pubKey.Address(), vote.ValidatorAddress) {
 		return ErrVoteInvalidValidatorAddress
 	}
 
-	if !pubKey.VerifyBytes(vote.SignBytes(chainID), vote.Signature) {
+	if !pubKey.VerifyBytes(vote.SignBytes(chainID, domain), vote.Signature) {
 		return ErrVoteInvalidSignature
 	}
 	return nil
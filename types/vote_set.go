@@ -53,6 +53,7 @@ type P2PID string
 */
 type VoteSet struct {
 	chainID string
+	domain  SignDomain
 	height  int64
 	round   int
 	type_   SignedMsgType
@@ -68,12 +69,13 @@ type VoteSet struct {
 }
 
 // Constructs a new VoteSet struct used to accumulate votes for given height/round.
-func NewVoteSet(chainID string, height int64, round int, type_ SignedMsgType, valSet *ValidatorSet) *VoteSet {
+func NewVoteSet(chainID string, domain SignDomain, height int64, round int, type_ SignedMsgType, valSet *ValidatorSet) *VoteSet {
 	if height == 0 {
 		cmn.PanicSanity("Cannot make VoteSet for height == 0, doesn't make sense.")
 	}
 	return &VoteSet{
 		chainID:       chainID,
+		domain:        domain,
 		height:        height,
 		round:         round,
 		type_:         type_,
@@ -91,6 +93,11 @@ func (voteSet *VoteSet) ChainID() string {
 	return voteSet.chainID
 }
 
+// SignDomain returns the SignDomain of the sign bytes of the votes.
+func (voteSet *VoteSet) SignDomain() SignDomain {
+	return voteSet.domain
+}
+
 func (voteSet *VoteSet) Height() int64 {
 	if voteSet == nil {
 		return 0
@@ -186,7 +193,7 @@ func (voteSet *VoteSet) addVote(vote *Vote) (added bool, err error) {
 	}
 
 	// Check signature.
-	if err := vote.Verify(voteSet.chainID, val.PubKey); err != nil {
+	if err := vote.Verify(voteSet.chainID, voteSet.domain, val.PubKey); err != nil {
 		return false, errors.Wrapf(err, "Failed to verify vote with ChainID %s and PubKey %s", voteSet.chainID, val.PubKey)
 	}
 
@@ -13,7 +13,7 @@ import (
 // NOTE: privValidators are in order
 func randVoteSet(height int64, round int, type_ SignedMsgType, numValidators int, votingPower int64) (*VoteSet, *ValidatorSet, []PrivValidator) {
 	valSet, privValidators := RandValidatorSet(numValidators, votingPower)
-	return NewVoteSet("test_chain_id", height, round, type_, valSet), valSet, privValidators
+	return NewVoteSet("test_chain_id", SignDomain{}, height, round, type_, valSet), valSet, privValidators
 }
 
 // Convenience: Return new vote with different validator address/index
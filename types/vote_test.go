@@ -59,7 +59,7 @@ func TestVoteEncoding(t *testing.T) {
 
 func TestVoteSignable(t *testing.T) {
 	vote := examplePrecommit()
-	signBytes := vote.SignBytes("test_chain_id")
+	signBytes := vote.SignBytes("test_chain_id", SignDomain{})
 
 	expected, err := cdc.MarshalBinaryLengthPrefixed(CanonicalizeVote("test_chain_id", vote))
 	require.NoError(t, err)
@@ -138,7 +138,7 @@ func TestVoteSignBytesTestVectors(t *testing.T) {
 		},
 	}
 	for i, tc := range tests {
-		got := tc.vote.SignBytes(tc.chainID)
+		got := tc.vote.SignBytes(tc.chainID, SignDomain{})
 		require.Equal(t, tc.want, got, "test case #%v: got unexpected sign bytes for Vote.", i)
 	}
 }
@@ -158,14 +158,14 @@ func TestVoteVerifySignature(t *testing.T) {
 	pubkey := privVal.GetPubKey()
 
 	vote := examplePrecommit()
-	signBytes := vote.SignBytes("test_chain_id")
+	signBytes := vote.SignBytes("test_chain_id", SignDomain{})
 
 	// sign it
-	err := privVal.SignVote("test_chain_id", vote)
+	err := privVal.SignVote("test_chain_id", SignDomain{}, vote)
 	require.NoError(t, err)
 
 	// verify the same vote
-	valid := pubkey.VerifyBytes(vote.SignBytes("test_chain_id"), vote.Signature)
+	valid := pubkey.VerifyBytes(vote.SignBytes("test_chain_id", SignDomain{}), vote.Signature)
 	require.True(t, valid)
 
 	// serialize, deserialize and verify again....
@@ -176,7 +176,7 @@ func TestVoteVerifySignature(t *testing.T) {
 	require.NoError(t, err)
 
 	// verify the transmitted vote
-	newSignBytes := precommit.SignBytes("test_chain_id")
+	newSignBytes := precommit.SignBytes("test_chain_id", SignDomain{})
 	require.Equal(t, string(signBytes), string(newSignBytes))
 	valid = pubkey.VerifyBytes(newSignBytes, precommit.Signature)
 	require.True(t, valid)
@@ -210,12 +210,12 @@ func TestVoteVerify(t *testing.T) {
 	vote := examplePrevote()
 	vote.ValidatorAddress = pubkey.Address()
 
-	err := vote.Verify("test_chain_id", ed25519.GenPrivKey().PubKey())
+	err := vote.Verify("test_chain_id", SignDomain{}, ed25519.GenPrivKey().PubKey())
 	if assert.Error(t, err) {
 		assert.Equal(t, ErrVoteInvalidValidatorAddress, err)
 	}
 
-	err = vote.Verify("test_chain_id", pubkey)
+	err = vote.Verify("test_chain_id", SignDomain{}, pubkey)
 	if assert.Error(t, err) {
 		assert.Equal(t, ErrVoteInvalidSignature, err)
 	}
@@ -243,7 +243,7 @@ func TestMaxVoteBytes(t *testing.T) {
 	}
 
 	privVal := NewMockPV()
-	err := privVal.SignVote("test_chain_id", vote)
+	err := privVal.SignVote("test_chain_id", SignDomain{}, vote)
 	require.NoError(t, err)
 
 	bz, err := cdc.MarshalBinaryLengthPrefixed(vote)
@@ -286,7 +286,7 @@ func TestVoteValidateBasic(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.testName, func(t *testing.T) {
 			vote := examplePrecommit()
-			err := privVal.SignVote("test_chain_id", vote)
+			err := privVal.SignVote("test_chain_id", SignDomain{}, vote)
 			require.NoError(t, err)
 			tc.malleateVote(vote)
 			assert.Equal(t, tc.expectErr, vote.ValidateBasic() != nil, "Validate Basic had an unexpected result")